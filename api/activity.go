@@ -29,6 +29,12 @@ const (
 	ActivityPipelineTaskStatementUpdate ActivityType = "bb.pipeline.task.statement.update"
 	// ActivityPipelineTaskEarliestAllowedTimeUpdate is the type for updating pipeline task the earliest allowed time.
 	ActivityPipelineTaskEarliestAllowedTimeUpdate ActivityType = "bb.pipeline.task.general.earliest-allowed-time.update"
+	// ActivityPipelineTaskCheckResultSuppress is the type for suppressing pipeline task check results.
+	ActivityPipelineTaskCheckResultSuppress ActivityType = "bb.pipeline.task.check-result.suppress"
+	// ActivityPipelineStagePause is the type for pausing the pipeline before a stage.
+	ActivityPipelineStagePause ActivityType = "bb.pipeline.stage.pause"
+	// ActivityPipelineStageResume is the type for resuming the pipeline paused before a stage.
+	ActivityPipelineStageResume ActivityType = "bb.pipeline.stage.resume"
 
 	// Member related.
 
@@ -63,6 +69,20 @@ const (
 
 	// ActivityDatabaseRecoveryPITRDone is the type for performing PITR on the database successfully.
 	ActivityDatabaseRecoveryPITRDone ActivityType = "bb.database.recovery.pitr.done"
+	// ActivityDatabaseAnomalyCreate is the type for detecting a new anomaly on the database.
+	ActivityDatabaseAnomalyCreate ActivityType = "bb.database.anomaly.create"
+	// ActivityDatabaseWriteGrantUpdate is the type for requesting, approving, rejecting, revoking or expiring the elevated write access to the database.
+	ActivityDatabaseWriteGrantUpdate ActivityType = "bb.database.write-grant.update"
+
+	// Instance related.
+
+	// ActivityInstanceEnvironmentUpdate is the type for moving the instance to another environment.
+	ActivityInstanceEnvironmentUpdate ActivityType = "bb.instance.environment.update"
+
+	// Setting related.
+
+	// ActivitySettingMaintenanceUpdate is the type for enabling, disabling or updating the maintenance mode.
+	ActivitySettingMaintenanceUpdate ActivityType = "bb.setting.maintenance.update"
 )
 
 // ActivityLevel is the level of activities.
@@ -150,6 +170,26 @@ type ActivityPipelineTaskEarliestAllowedTimeUpdatePayload struct {
 	TaskName  string `json:"taskName"`
 }
 
+// ActivityPipelineStagePauseResumePayload is the API message payloads for pausing and resuming the pipeline before a stage.
+type ActivityPipelineStagePauseResumePayload struct {
+	StageID int `json:"stageId"`
+	// Used by inbox to display info without paying the join cost
+	IssueName string `json:"issueName"`
+	StageName string `json:"stageName"`
+}
+
+// ActivityPipelineTaskCheckResultSuppressPayload is the API message payloads for suppressing pipeline task check results.
+type ActivityPipelineTaskCheckResultSuppressPayload struct {
+	TaskID         int             `json:"taskId"`
+	TaskCheckRunID int             `json:"taskCheckRunId"`
+	Status         TaskCheckStatus `json:"status"`
+	Title          string          `json:"title"`
+	Content        string          `json:"content"`
+	// Used by inbox to display info without paying the join cost
+	IssueName string `json:"issueName"`
+	TaskName  string `json:"taskName"`
+}
+
 // ActivityMemberCreatePayload is the API message payloads for creating members.
 type ActivityMemberCreatePayload struct {
 	PrincipalID    int          `json:"principalId"`
@@ -192,6 +232,48 @@ type ActivityProjectDatabaseTransferPayload struct {
 	DatabaseName string `json:"databaseName,omitempty"`
 }
 
+// ActivityDatabaseAnomalyCreatePayload is the API message payloads for detecting database anomalies.
+type ActivityDatabaseAnomalyCreatePayload struct {
+	DatabaseID int `json:"databaseId,omitempty"`
+	// Used by activity table to display info without paying the join cost
+	DatabaseName string      `json:"databaseName,omitempty"`
+	AnomalyType  AnomalyType `json:"anomalyType,omitempty"`
+}
+
+// ActivityDatabaseWriteGrantUpdatePayload is the API message payloads for updating the elevated write access to the database.
+type ActivityDatabaseWriteGrantUpdatePayload struct {
+	DatabaseID  int                `json:"databaseId,omitempty"`
+	GrantID     int                `json:"grantId,omitempty"`
+	PrincipalID int                `json:"principalId,omitempty"`
+	Status      DBQueryGrantStatus `json:"status,omitempty"`
+	ExpireTs    int64              `json:"expireTs,omitempty"`
+	// Used by activity table to display info without paying the join cost
+	DatabaseName string `json:"databaseName,omitempty"`
+}
+
+// ActivityInstanceEnvironmentUpdatePayload is the API message payloads for moving the instance to another environment.
+type ActivityInstanceEnvironmentUpdatePayload struct {
+	OldEnvironmentID int `json:"oldEnvironmentId"`
+	NewEnvironmentID int `json:"newEnvironmentId"`
+	// Used by activity table to display info without paying the join cost
+	InstanceName       string `json:"instanceName"`
+	OldEnvironmentName string `json:"oldEnvironmentName"`
+	NewEnvironmentName string `json:"newEnvironmentName"`
+	// The policies of the new environment applied to the instance.
+	ApprovalPolicy     PipelineApprovalValue    `json:"approvalPolicy"`
+	BackupPlanSchedule BackupPlanPolicySchedule `json:"backupPlanSchedule"`
+	// The databases whose backup setting is updated to comply with the backup plan policy of the new environment.
+	BackupSettingUpdatedDatabaseList []string `json:"backupSettingUpdatedDatabaseList"`
+}
+
+// ActivitySettingMaintenanceUpdatePayload is the API message payloads for updating the maintenance mode.
+type ActivitySettingMaintenanceUpdatePayload struct {
+	OldEnabled bool   `json:"oldEnabled"`
+	NewEnabled bool   `json:"newEnabled"`
+	OldMessage string `json:"oldMessage"`
+	NewMessage string `json:"newMessage"`
+}
+
 // ActivitySQLEditorQueryPayload is the API message payloads for the executed query info.
 type ActivitySQLEditorQueryPayload struct {
 	// Used by activity table to display info without paying the join cost
@@ -241,6 +323,13 @@ type ActivityCreate struct {
 	Payload     string `jsonapi:"attr,payload"`
 }
 
+// ActivityResponse is the API message for a page of the activity list.
+type ActivityResponse struct {
+	// NextToken is the token to fetch the next page, empty if there are no more activities.
+	NextToken  string      `jsonapi:"attr,nextToken"`
+	Activities []*Activity `jsonapi:"relation,activities"`
+}
+
 // ActivityFind is the API message for finding activities.
 type ActivityFind struct {
 	ID *int
@@ -250,11 +339,15 @@ type ActivityFind struct {
 	TypePrefix  *string
 	Level       *ActivityLevel
 	ContainerID *int
+	// WorkspaceID finds the activities whose container belongs to the workspace, or is shared by all workspaces.
+	WorkspaceID *int
 	Limit       *int
 	// If specified, sorts the returned list by created_ts in <<ORDER>>
 	// Different use cases want different orders.
 	// e.g. Issue activity list wants ASC, while view recent activity list wants DESC.
 	Order *SortOrder
+	// If specified, only returns the activities after the cursor in <<ORDER>>, DESC if not specified.
+	After *PageCursor
 }
 
 func (find *ActivityFind) String() string {
@@ -11,6 +11,8 @@ type ServerInfo struct {
 	Host           string `json:"host"`
 	Port           string `json:"port"`
 	NeedAdminSetup bool   `json:"needAdminSetup"`
+	// Maintenance is exposed before signing in, so that the banner can be displayed on the sign in page.
+	Maintenance MaintenanceSetting `json:"maintenance"`
 	// Rand may be based on the server start time, thus exposing startedTs to the client may cause security issues (e.g. jwt key is based on Rand).
 	// StartedTs   int64  `json:"startedTs"`
 }
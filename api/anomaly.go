@@ -12,6 +12,8 @@ const (
 	AnomalyInstanceConnection AnomalyType = "bb.anomaly.instance.connection"
 	// AnomalyInstanceMigrationSchema is the anomaly type for schema migrations.
 	AnomalyInstanceMigrationSchema AnomalyType = "bb.anomaly.instance.migration-schema"
+	// AnomalyInstanceDiskFull is the anomaly type for the instance disk projected to fill up soon.
+	AnomalyInstanceDiskFull AnomalyType = "bb.anomaly.instance.disk-full"
 	// AnomalyDatabaseBackupPolicyViolation is the anomaly type for backup policy violations.
 	AnomalyDatabaseBackupPolicyViolation AnomalyType = "bb.anomaly.database.backup.policy-violation"
 	// AnomalyDatabaseBackupMissing is the anomaly type for missing backups.
@@ -20,6 +22,8 @@ const (
 	AnomalyDatabaseConnection AnomalyType = "bb.anomaly.database.connection"
 	// AnomalyDatabaseSchemaDrift is the anomaly type for database schema drifts.
 	AnomalyDatabaseSchemaDrift AnomalyType = "bb.anomaly.database.schema.drift"
+	// AnomalyDatabaseGrantDrift is the anomaly type for drifts of the managed database grants.
+	AnomalyDatabaseGrantDrift AnomalyType = "bb.anomaly.database.grant.drift"
 )
 
 // AnomalySeverity is the severity of anomaly.
@@ -39,7 +43,7 @@ func AnomalySeverityFromType(anomalyType AnomalyType) AnomalySeverity {
 	switch anomalyType {
 	case AnomalyDatabaseBackupPolicyViolation:
 		return AnomalySeverityMedium
-	case AnomalyDatabaseBackupMissing:
+	case AnomalyDatabaseBackupMissing, AnomalyDatabaseGrantDrift, AnomalyInstanceDiskFull:
 		return AnomalySeverityHigh
 	case AnomalyInstanceConnection:
 	case AnomalyInstanceMigrationSchema:
@@ -56,6 +60,15 @@ type AnomalyInstanceConnectionPayload struct {
 	Detail string `json:"detail,omitempty"`
 }
 
+// AnomalyInstanceDiskFullPayload is the API message for instance disk full payloads.
+type AnomalyInstanceDiskFullPayload struct {
+	// The latest disk usage reported by the instance agent
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+	FreeBytes  int64 `json:"freeBytes,omitempty"`
+	// The projected days until the disk is full at the growth rate of the recent samples
+	DaysToFull float64 `json:"daysToFull"`
+}
+
 // AnomalyDatabaseBackupPolicyViolationPayload is the API message for backup policy violation payloads.
 type AnomalyDatabaseBackupPolicyViolationPayload struct {
 	EnvironmentID          int                      `json:"environmentId,omitempty"`
@@ -86,6 +99,12 @@ type AnomalyDatabaseSchemaDriftPayload struct {
 	Actual string `json:"actual,omitempty"`
 }
 
+// AnomalyDatabaseGrantDriftPayload is the API message for database grant drift payloads.
+type AnomalyDatabaseGrantDriftPayload struct {
+	// The managed grants differing from the actual grants of the database
+	DriftList []*ManagedGrantDrift `json:"driftList,omitempty"`
+}
+
 // Anomaly is the API message for an anomaly.
 type Anomaly struct {
 	ID int `jsonapi:"primary,anomaly"`
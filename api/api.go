@@ -2,6 +2,8 @@
 package api
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -40,3 +42,33 @@ func StringToSortOrder(s string) (SortOrder, error) {
 	}
 	return SortOrder(""), errors.Errorf("%q cannot be converted to SortOrder", s)
 }
+
+// PageCursor is the position of the last item of a page in the keyset pagination.
+// The next page starts after the item sorted by the timestamp and then the ID,
+// so it's not affected by the items inserted before the position like the offset pagination.
+type PageCursor struct {
+	Ts int64 `json:"ts"`
+	ID int   `json:"id"`
+}
+
+// Encode encodes the cursor into an opaque token for the client.
+func (c *PageCursor) Encode() string {
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+// DecodePageCursor decodes the token returned by PageCursor.Encode.
+func DecodePageCursor(token string) (*PageCursor, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid page cursor %q", token)
+	}
+	var cursor PageCursor
+	if err := json.Unmarshal(bytes, &cursor); err != nil {
+		return nil, errors.Wrapf(err, "invalid page cursor %q", token)
+	}
+	return &cursor, nil
+}
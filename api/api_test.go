@@ -0,0 +1,18 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageCursor(t *testing.T) {
+	cursor := &PageCursor{Ts: 1662000000, ID: 101}
+	got, err := DecodePageCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, got)
+
+	_, err = DecodePageCursor("not a cursor")
+	assert.Error(t, err)
+}
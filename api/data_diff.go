@@ -0,0 +1,93 @@
+package api
+
+// DataDiffStatus is the status of a data diff.
+type DataDiffStatus string
+
+const (
+	// DataDiffRunning is the data diff status for RUNNING.
+	DataDiffRunning DataDiffStatus = "RUNNING"
+	// DataDiffDone is the data diff status for DONE.
+	DataDiffDone DataDiffStatus = "DONE"
+	// DataDiffFailed is the data diff status for FAILED.
+	DataDiffFailed DataDiffStatus = "FAILED"
+)
+
+// DataDiffTableStatus is the comparison result of a table.
+type DataDiffTableStatus string
+
+const (
+	// DataDiffTableMatch means the table data are the same in both databases.
+	DataDiffTableMatch DataDiffTableStatus = "MATCH"
+	// DataDiffTableMismatch means some chunks of the table data differ.
+	DataDiffTableMismatch DataDiffTableStatus = "MISMATCH"
+	// DataDiffTableSourceOnly means the table only exists in the source database.
+	DataDiffTableSourceOnly DataDiffTableStatus = "SOURCE_ONLY"
+	// DataDiffTableTargetOnly means the table only exists in the target database.
+	DataDiffTableTargetOnly DataDiffTableStatus = "TARGET_ONLY"
+	// DataDiffTableError means the table fails to be compared, e.g. the column lists differ.
+	DataDiffTableError DataDiffTableStatus = "ERROR"
+)
+
+// DataDiffChunk is a mismatched chunk of a table.
+// Rows are distributed into the chunks by the hash of the primary key, or of the whole row if the table has no primary key,
+// so that a missing or changed row only affects its own chunk.
+type DataDiffChunk struct {
+	Index          int    `json:"index"`
+	SourceRowCount int64  `json:"sourceRowCount"`
+	TargetRowCount int64  `json:"targetRowCount"`
+	SourceChecksum string `json:"sourceChecksum"`
+	TargetChecksum string `json:"targetChecksum"`
+}
+
+// DataDiffTableResult is the comparison result of a table.
+type DataDiffTableResult struct {
+	Table               string              `json:"table"`
+	Status              DataDiffTableStatus `json:"status"`
+	ChunkCount          int                 `json:"chunkCount"`
+	SourceRowCount      int64               `json:"sourceRowCount"`
+	TargetRowCount      int64               `json:"targetRowCount"`
+	MismatchedChunkList []*DataDiffChunk    `json:"mismatchedChunkList"`
+	// Error is set if Status is ERROR.
+	Error string `json:"error,omitempty"`
+}
+
+// DataDiff is the API message for a data diff job comparing the table data of two databases.
+// Data diff jobs are kept in memory by the server, NOT in the database.
+type DataDiff struct {
+	ID int `jsonapi:"primary,dataDiff"`
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+	UpdatedTs int64 `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	SourceDatabaseID int `jsonapi:"attr,sourceDatabaseId"`
+	TargetDatabaseID int `jsonapi:"attr,targetDatabaseId"`
+
+	// Domain specific fields
+	ChunkSize int            `jsonapi:"attr,chunkSize"`
+	Status    DataDiffStatus `jsonapi:"attr,status"`
+	// Progress counts the compared tables.
+	Progress        Progress               `jsonapi:"attr,progress"`
+	TableResultList []*DataDiffTableResult `jsonapi:"attr,tableResultList"`
+	// Error is set if Status is FAILED.
+	Error string `jsonapi:"attr,error"`
+}
+
+// DataDiffCreate is the API message for creating a data diff job.
+type DataDiffCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	SourceDatabaseID int `jsonapi:"attr,sourceDatabaseId"`
+	TargetDatabaseID int `jsonapi:"attr,targetDatabaseId"`
+
+	// Domain specific fields
+	// TableList is the tables to compare, all tables in both databases are compared if empty.
+	TableList []string `jsonapi:"attr,tableList"`
+	// ChunkSize is the expected row count of each chunk, the default is used if 0.
+	ChunkSize int `jsonapi:"attr,chunkSize"`
+}
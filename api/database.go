@@ -30,6 +30,7 @@ type Database struct {
 	ID int `jsonapi:"primary,database"`
 
 	// Standard fields
+	RowStatus RowStatus `jsonapi:"attr,rowStatus"`
 	CreatorID int
 	Creator   *Principal `jsonapi:"relation,creator"`
 	CreatedTs int64      `jsonapi:"attr,createdTs"`
@@ -47,6 +48,10 @@ type Database struct {
 	SourceBackup   *Backup `jsonapi:"relation,sourceBackup"`
 	// Anomalies are stored in a separate table, but just return here for convenience
 	AnomalyList []*Anomaly `jsonapi:"relation,anomaly"`
+	// OwnerID is the principal owning the database, UnknownID if the database has no owner.
+	// The owner is notified on failed tasks and anomalies affecting the database.
+	OwnerID int        `jsonapi:"attr,ownerId"`
+	Owner   *Principal `jsonapi:"relation,owner"`
 
 	// Domain specific fields
 	Name                 string     `jsonapi:"attr,name"`
@@ -58,6 +63,12 @@ type Database struct {
 	// Labels is a json-encoded string from a list of DatabaseLabel,
 	// e.g. "[{"key":"bb.location","value":"earth"},{"key":"bb.tenant","value":"bytebase"}]".
 	Labels string `jsonapi:"attr,labels,omitempty"`
+	// OnCall is the external handle (e.g. an email or a chat group) of the on-call rotation for the database.
+	// It's included in the webhook messages on failed tasks and anomalies affecting the database.
+	OnCall string `jsonapi:"attr,onCall"`
+	// ConnectionOption is a json-encoded string from DatabaseConnectionOption,
+	// e.g. "{"sessionVariableList":[{"name":"lock_wait_timeout","value":"10"}]}".
+	ConnectionOption string `jsonapi:"attr,connectionOption"`
 }
 
 // DatabaseCreate is the API message for creating a database.
@@ -86,6 +97,9 @@ type DatabaseCreate struct {
 type DatabaseFind struct {
 	ID *int
 
+	// Standard fields
+	RowStatus *RowStatus
+
 	// Related fields
 	ProjectID  *int
 	InstanceID *int
@@ -94,6 +108,8 @@ type DatabaseFind struct {
 	Name               *string
 	IncludeAllDatabase bool
 	SyncStatus         *SyncStatus
+	// If specified, then it will only fetch the databases of the instances in the workspace
+	WorkspaceID *int
 }
 
 func (find *DatabaseFind) String() string {
@@ -111,6 +127,7 @@ type DatabasePatch struct {
 	// Standard fields
 	// Value is assigned from the jwt subject field passed by the client.
 	UpdaterID int
+	RowStatus *string
 
 	// Related fields
 	ProjectID      *int `jsonapi:"attr,projectId"`
@@ -119,9 +136,37 @@ type DatabasePatch struct {
 	// Labels is a json-encoded string from a list of DatabaseLabel,
 	// e.g. "[{"key":"bb.location","value":"earth"},{"key":"bb.tenant","value":"bytebase"}]".
 	Labels *string `jsonapi:"attr,labels"`
+	// OwnerID unsets the owner if it's UnknownID.
+	OwnerID *int `jsonapi:"attr,ownerId"`
 
 	// Domain specific fields
 	SchemaVersion        *string
 	SyncStatus           *SyncStatus
 	LastSuccessfulSyncTs *int64
+	OnCall               *string `jsonapi:"attr,onCall"`
+	// ConnectionOption is a json-encoded string from DatabaseConnectionOption.
+	ConnectionOption *string `jsonapi:"attr,connectionOption"`
+}
+
+// DatabaseArchive is the API message for archiving the databases matching the filters in bulk.
+// At least one of StaleDays and LabelSelector must be specified.
+type DatabaseArchive struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+	// Value is assigned from the workspace of the updater.
+	WorkspaceID int
+
+	// Related fields
+	ProjectID  *int `jsonapi:"attr,projectId"`
+	InstanceID *int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	// StaleDays matches the databases without a successful sync in the last StaleDays days.
+	StaleDays int `jsonapi:"attr,staleDays"`
+	// LabelSelector is a LabelSelector in JSON matching the databases by labels,
+	// e.g. "{"matchExpressions":[{"key":"bb.tenant","operator":"In","values":["bytebase"]}]}".
+	LabelSelector string `jsonapi:"attr,labelSelector"`
+	// DryRun returns the databases to be archived without archiving them.
+	DryRun bool `jsonapi:"attr,dryRun"`
 }
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// sessionVariableNameRegexp matches the session variable names, e.g. "sql_mode" and "lock_timeout".
+var sessionVariableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// SessionVariable is a session variable set on the connections to a database.
+type SessionVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// DatabaseConnectionOption is the connection option of a database.
+// It's applied on the connections set up for the task executors and checks on the database.
+type DatabaseConnectionOption struct {
+	// SessionVariableList is set in order on the connection setup,
+	// e.g. sql_mode and lock_wait_timeout for MySQL, search_path and lock_timeout for Postgres.
+	SessionVariableList []*SessionVariable `json:"sessionVariableList,omitempty"`
+}
+
+// UnmarshalDatabaseConnectionOption unmarshals and validates the database connection option.
+// Returns the empty option for the empty string.
+func UnmarshalDatabaseConnectionOption(connectionOption string) (*DatabaseConnectionOption, error) {
+	option := &DatabaseConnectionOption{}
+	if connectionOption == "" {
+		return option, nil
+	}
+	if err := json.Unmarshal([]byte(connectionOption), option); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal database connection option %q", connectionOption)
+	}
+	visited := make(map[string]bool)
+	for _, variable := range option.SessionVariableList {
+		if !sessionVariableNameRegexp.MatchString(variable.Name) {
+			return nil, errors.Errorf("invalid session variable name %q", variable.Name)
+		}
+		if visited[variable.Name] {
+			return nil, errors.Errorf("duplicate session variable %q", variable.Name)
+		}
+		visited[variable.Name] = true
+	}
+	return option, nil
+}
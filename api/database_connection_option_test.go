@@ -0,0 +1,55 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalDatabaseConnectionOption(t *testing.T) {
+	tests := []struct {
+		connectionOption string
+		want             *DatabaseConnectionOption
+		wantErr          bool
+	}{
+		{
+			connectionOption: "",
+			want:             &DatabaseConnectionOption{},
+		},
+		{
+			connectionOption: "{}",
+			want:             &DatabaseConnectionOption{},
+		},
+		{
+			connectionOption: `{"sessionVariableList":[{"name":"sql_mode","value":"STRICT_TRANS_TABLES"},{"name":"lock_wait_timeout","value":"10"}]}`,
+			want: &DatabaseConnectionOption{
+				SessionVariableList: []*SessionVariable{
+					{Name: "sql_mode", Value: "STRICT_TRANS_TABLES"},
+					{Name: "lock_wait_timeout", Value: "10"},
+				},
+			},
+		},
+		{
+			connectionOption: `{"sessionVariableList":[{"name":"search_path; DROP TABLE t","value":"public"}]}`,
+			wantErr:          true,
+		},
+		{
+			connectionOption: `{"sessionVariableList":[{"name":"search_path","value":"a"},{"name":"search_path","value":"b"}]}`,
+			wantErr:          true,
+		},
+		{
+			connectionOption: `{"sessionVariableList":`,
+			wantErr:          true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := UnmarshalDatabaseConnectionOption(test.connectionOption)
+		if test.wantErr {
+			require.Error(t, err, test.connectionOption)
+			continue
+		}
+		require.NoError(t, err, test.connectionOption)
+		require.Equal(t, test.want, got, test.connectionOption)
+	}
+}
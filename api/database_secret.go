@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// DatabaseSecretNameRegexp is the pattern of a database secret name, which is referenced as {{secret.NAME}} in the statements.
+var DatabaseSecretNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// DatabaseSecret is the API message for a database secret.
+// The value is never returned to the client.
+type DatabaseSecret struct {
+	ID int `jsonapi:"primary,databaseSecret"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	Name        string `jsonapi:"attr,name"`
+	Value       string
+	Description string `jsonapi:"attr,description"`
+}
+
+// DatabaseSecretUpsert is the API message for creating or updating a database secret.
+type DatabaseSecretUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Name        string
+	Value       string `jsonapi:"attr,value"`
+	Description string `jsonapi:"attr,description"`
+}
+
+// DatabaseSecretFind is the API message for finding database secrets.
+type DatabaseSecretFind struct {
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *DatabaseSecretFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DatabaseSecretDelete is the API message for deleting a database secret.
+type DatabaseSecretDelete struct {
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Name string
+}
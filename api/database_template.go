@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// DatabaseTemplate is the API message for a database template.
+// The template provisions the new databases with the same character set, collation, extensions, grants and baseline schema.
+type DatabaseTemplate struct {
+	ID int `jsonapi:"primary,databaseTemplate"`
+
+	// Standard fields
+	CreatorID   int
+	Creator     *Principal `jsonapi:"relation,creator"`
+	CreatedTs   int64      `jsonapi:"attr,createdTs"`
+	UpdaterID   int
+	Updater     *Principal `jsonapi:"relation,updater"`
+	UpdatedTs   int64      `jsonapi:"attr,updatedTs"`
+	WorkspaceID int        `jsonapi:"attr,workspaceId"`
+
+	// Related fields
+	// SheetID is the ID of the sheet holding the baseline schema, nil means an empty database.
+	SheetID *int `jsonapi:"attr,sheetId"`
+
+	// Domain specific fields
+	Name         string  `jsonapi:"attr,name"`
+	Engine       db.Type `jsonapi:"attr,engine"`
+	CharacterSet string  `jsonapi:"attr,characterSet"`
+	Collation    string  `jsonapi:"attr,collation"`
+	// ExtensionList is the extensions created in the new database, only applicable to Postgres.
+	ExtensionList []string `jsonapi:"attr,extensionList"`
+	// GranteeList is the roles granted all privileges on the new database, only applicable to MySQL, TiDB and Postgres.
+	// The MySQL and TiDB grantee is in the form of "user@host", and the host defaults to "%" if omitted.
+	GranteeList []string `jsonapi:"attr,granteeList"`
+}
+
+// DatabaseTemplateCreate is the API message for creating a database template.
+type DatabaseTemplateCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+	// WorkspaceID is assigned from the workspace of the creator.
+	WorkspaceID int
+
+	// Related fields
+	SheetID *int `jsonapi:"attr,sheetId"`
+
+	// Domain specific fields
+	Name          string   `jsonapi:"attr,name"`
+	Engine        db.Type  `jsonapi:"attr,engine"`
+	CharacterSet  string   `jsonapi:"attr,characterSet"`
+	Collation     string   `jsonapi:"attr,collation"`
+	ExtensionList []string `jsonapi:"attr,extensionList"`
+	GranteeList   []string `jsonapi:"attr,granteeList"`
+}
+
+// DatabaseTemplateFind is the API message for finding database templates.
+type DatabaseTemplateFind struct {
+	ID *int
+
+	// Standard fields
+	WorkspaceID *int
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *DatabaseTemplateFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DatabaseTemplatePatch is the API message for patching a database template.
+// The engine is immutable, because the other fields are validated against it.
+type DatabaseTemplatePatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	// SheetID is the ID of the baseline schema sheet, 0 clears the baseline schema.
+	SheetID *int `jsonapi:"attr,sheetId"`
+
+	// Domain specific fields
+	Name         *string `jsonapi:"attr,name"`
+	CharacterSet *string `jsonapi:"attr,characterSet"`
+	Collation    *string `jsonapi:"attr,collation"`
+	// ExtensionList and GranteeList are comma separated.
+	ExtensionList *string `jsonapi:"attr,extensionList"`
+	GranteeList   *string `jsonapi:"attr,granteeList"`
+}
+
+// DatabaseTemplateDelete is the API message for deleting a database template.
+type DatabaseTemplateDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// DBQueryGrantStatus is the status of a database query grant.
+type DBQueryGrantStatus string
+
+const (
+	// DBQueryGrantPending is the status of the grant requested and waiting for approval.
+	DBQueryGrantPending DBQueryGrantStatus = "PENDING"
+	// DBQueryGrantApproved is the status of the grant approved, which allows querying the database until it expires.
+	DBQueryGrantApproved DBQueryGrantStatus = "APPROVED"
+	// DBQueryGrantRejected is the status of the grant request rejected.
+	DBQueryGrantRejected DBQueryGrantStatus = "REJECTED"
+	// DBQueryGrantRevoked is the status of the grant revoked after approval.
+	DBQueryGrantRevoked DBQueryGrantStatus = "REVOKED"
+	// DBQueryGrantExpired is the status of the approved grant revoked automatically after it expires.
+	DBQueryGrantExpired DBQueryGrantStatus = "EXPIRED"
+)
+
+// DBQueryGrantAccess is the access allowed by a database query grant.
+type DBQueryGrantAccess string
+
+const (
+	// DBQueryGrantAccessQuery is the access to query the database.
+	DBQueryGrantAccessQuery DBQueryGrantAccess = "QUERY"
+	// DBQueryGrantAccessWrite is the temporary elevated access to execute the write statements on the database.
+	// It's required by everyone including the owners and the DBAs, and must be approved by someone other than the grantee.
+	DBQueryGrantAccessWrite DBQueryGrantAccess = "WRITE"
+)
+
+// DBQueryGrant is the API message for a database query grant.
+// It allows the grantee to query the database in the SQL editor, the grantee is either a principal or all the members of a role.
+// The owners and the DBAs can query every database without any grant.
+type DBQueryGrant struct {
+	ID int `jsonapi:"primary,dbQueryGrant"`
+
+	// Standard fields
+	// The creator is the requester, and the updater is the approver once approved.
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	// PrincipalID is the grantee principal, 0 for the role grant.
+	PrincipalID int `jsonapi:"attr,principalId"`
+	// Role is the grantee role, empty for the principal grant.
+	Role   Role               `jsonapi:"attr,role"`
+	Status DBQueryGrantStatus `jsonapi:"attr,status"`
+	// ExpireTs is the time after which the approved grant no longer allows querying.
+	ExpireTs int64              `jsonapi:"attr,expireTs"`
+	Reason   string             `jsonapi:"attr,reason"`
+	Access   DBQueryGrantAccess `jsonapi:"attr,access"`
+}
+
+// IsActive returns true if the grant is approved and not expired at the time.
+func (g *DBQueryGrant) IsActive(ts int64) bool {
+	return g.Status == DBQueryGrantApproved && g.ExpireTs > ts
+}
+
+// DBQueryGrantCreate is the API message for requesting a database query grant.
+type DBQueryGrantCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	PrincipalID int                `jsonapi:"attr,principalId"`
+	Role        Role               `jsonapi:"attr,role"`
+	ExpireTs    int64              `jsonapi:"attr,expireTs"`
+	Reason      string             `jsonapi:"attr,reason"`
+	Access      DBQueryGrantAccess `jsonapi:"attr,access"`
+}
+
+// DBQueryGrantFind is the API message for finding database query grants.
+type DBQueryGrantFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Status *DBQueryGrantStatus
+	Access *DBQueryGrantAccess
+}
+
+func (find *DBQueryGrantFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DBQueryGrantPatch is the API message for approving, rejecting or revoking a database query grant.
+type DBQueryGrantPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Status DBQueryGrantStatus `jsonapi:"attr,status"`
+}
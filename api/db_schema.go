@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// DBSchema is the API message for a schema of a Postgres database, which is a namespace of the tables and views.
+type DBSchema struct {
+	ID int `jsonapi:"primary,dbSchema"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int
+	Database   *Database `jsonapi:"relation,database"`
+
+	// Domain specific fields
+	Name  string `jsonapi:"attr,name"`
+	Owner string `jsonapi:"attr,owner"`
+}
+
+// DBSchemaCreate is the API message for creating a database schema.
+type DBSchemaCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Name  string
+	Owner string
+}
+
+// DBSchemaFind is the API message for finding database schemas.
+type DBSchemaFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *DBSchemaFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DBSchemaDelete is the API message for deleting a database schema.
+type DBSchemaDelete struct {
+	ID int
+}
@@ -18,9 +18,10 @@ type Environment struct {
 	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
 
 	// Domain specific fields
-	Name  string               `jsonapi:"attr,name"`
-	Order int                  `jsonapi:"attr,order"`
-	Tier  EnvironmentTierValue `jsonapi:"attr,tier"`
+	WorkspaceID int                  `jsonapi:"attr,workspaceId"`
+	Name        string               `jsonapi:"attr,name"`
+	Order       int                  `jsonapi:"attr,order"`
+	Tier        EnvironmentTierValue `jsonapi:"attr,tier"`
 }
 
 // EnvironmentCreate is the API message for creating an environment.
@@ -30,7 +31,9 @@ type EnvironmentCreate struct {
 	CreatorID int
 
 	// Domain specific fields
-	Name string `jsonapi:"attr,name"`
+	// WorkspaceID is assigned from the workspace of the creator.
+	WorkspaceID int
+	Name        string `jsonapi:"attr,name"`
 }
 
 // EnvironmentFind is the API message for finding environments.
@@ -41,7 +44,8 @@ type EnvironmentFind struct {
 	RowStatus *RowStatus
 
 	// Domain specific fields
-	Name *string
+	WorkspaceID *int
+	Name        *string
 }
 
 func (find *EnvironmentFind) String() string {
@@ -0,0 +1,60 @@
+package api
+
+// IdempotencyKey is the API message for the idempotency key of a create request.
+// It keeps the response of the first successful request, which is replayed for the retried requests with the same key.
+type IdempotencyKey struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Domain specific fields
+	Key string
+	// Request is the method and the path of the request, e.g. "POST /api/pipeline/1/task/2/check".
+	Request string
+	// RequestHash is the SHA-256 hex digest of the request body.
+	RequestHash string
+	// StatusCode is 0 while the request is still in progress.
+	StatusCode  int
+	ContentType string
+	Response    string
+	ExpireTs    int64
+}
+
+// IdempotencyKeyCreate is the API message for creating an idempotency key.
+type IdempotencyKeyCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	Key         string
+	Request     string
+	RequestHash string
+	ExpireTs    int64
+}
+
+// IdempotencyKeyFind is the API message for finding idempotency keys.
+type IdempotencyKeyFind struct {
+	// Standard fields
+	CreatorID *int
+
+	// Domain specific fields
+	Key *string
+}
+
+// IdempotencyKeyPatch is the API message for saving the response of an idempotency key.
+type IdempotencyKeyPatch struct {
+	ID int
+
+	// Domain specific fields
+	StatusCode  int
+	ContentType string
+	Response    string
+}
+
+// IdempotencyKeyDelete is the API message for deleting an idempotency key.
+type IdempotencyKeyDelete struct {
+	ID int
+}
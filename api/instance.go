@@ -27,6 +27,7 @@ type Instance struct {
 	DataSourceList []*DataSource `jsonapi:"relation,dataSourceList"`
 
 	// Domain specific fields
+	WorkspaceID   int     `jsonapi:"attr,workspaceId"`
 	Name          string  `jsonapi:"attr,name"`
 	Engine        db.Type `jsonapi:"attr,engine"`
 	EngineVersion string  `jsonapi:"attr,engineVersion"`
@@ -36,6 +37,18 @@ type Instance struct {
 	Username      string  `jsonapi:"attr,username"`
 	// Password is not returned to the client
 	Password string
+	// BackupStorageBackend is where the backups of the databases in the instance are stored.
+	// Empty means the storage backend configured for the server.
+	BackupStorageBackend BackupStorageBackend `jsonapi:"attr,backupStorageBackend"`
+	// SSHHost is the SSH server through which the instance is connected, e.g. the bastion host.
+	// Empty means connecting the instance directly.
+	SSHHost string `jsonapi:"attr,sshHost"`
+	SSHPort string `jsonapi:"attr,sshPort"`
+	SSHUser string `jsonapi:"attr,sshUser"`
+	// SSHPrivateKey is not returned to the client
+	SSHPrivateKey string
+	// SSHHostKey is the public key of the SSH server in the authorized_keys format, or its SHA256 fingerprint.
+	SSHHostKey string `jsonapi:"attr,sshHostKey"`
 }
 
 // InstanceCreate is the API message for creating an instance.
@@ -48,6 +61,8 @@ type InstanceCreate struct {
 	EnvironmentID int `jsonapi:"attr,environmentId"`
 
 	// Domain specific fields
+	// WorkspaceID is assigned from the workspace of the creator.
+	WorkspaceID  int
 	Name         string  `jsonapi:"attr,name"`
 	Engine       db.Type `jsonapi:"attr,engine"`
 	ExternalLink string  `jsonapi:"attr,externalLink"`
@@ -58,12 +73,55 @@ type InstanceCreate struct {
 	SslCa        string  `jsonapi:"attr,sslCa"`
 	SslCert      string  `jsonapi:"attr,sslCert"`
 	SslKey       string  `jsonapi:"attr,sslKey"`
+	// BackupStorageBackend is empty for the storage backend configured for the server.
+	BackupStorageBackend BackupStorageBackend `jsonapi:"attr,backupStorageBackend"`
+	// SSHHost is empty for connecting the instance directly.
+	SSHHost       string `jsonapi:"attr,sshHost"`
+	SSHPort       string `jsonapi:"attr,sshPort"`
+	SSHUser       string `jsonapi:"attr,sshUser"`
+	SSHPrivateKey string `jsonapi:"attr,sshPrivateKey"`
+	SSHHostKey    string `jsonapi:"attr,sshHostKey"`
 	// If true, syncs the schema after adding the instance. The client
 	// may set to false if the target instance contains too many databases
 	// to avoid the request timeout.
 	SyncSchema bool `jsonapi:"attr,syncSchema"`
 }
 
+// InstanceImportRow is a row of the manifest for importing instances in bulk.
+// The manifest is either a JSON array of rows, or a CSV file with a header line of the json field names.
+type InstanceImportRow struct {
+	Name         string  `json:"name"`
+	Engine       db.Type `json:"engine"`
+	Host         string  `json:"host"`
+	Port         string  `json:"port"`
+	ExternalLink string  `json:"externalLink"`
+	// Environment is the name of the environment the instance belongs to.
+	Environment string `json:"environment"`
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	// CredentialInstance is the name of an existing instance whose admin username and password are reused.
+	// It's mutually exclusive to Username and Password.
+	CredentialInstance string `json:"credentialInstance"`
+}
+
+// InstanceImportResult is the import result of a manifest row.
+type InstanceImportResult struct {
+	// Row is the 1-based position of the row in the manifest.
+	Row  int    `json:"row"`
+	Name string `json:"name"`
+	// InstanceID is the ID of the created instance, 0 if the row is invalid or the instance is not created.
+	InstanceID int    `json:"instanceId,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// InstanceImportResponse is the API message for the response of importing instances.
+// Valid rows are imported even if some other rows fail.
+type InstanceImportResponse struct {
+	SuccessCount int                     `json:"successCount"`
+	FailureCount int                     `json:"failureCount"`
+	ResultList   []*InstanceImportResult `json:"resultList"`
+}
+
 // InstanceFind is the API message for finding instances.
 type InstanceFind struct {
 	ID *int
@@ -75,8 +133,9 @@ type InstanceFind struct {
 	EnvironmentID *int
 
 	// Domain specific fields
-	Host *string
-	Port *string
+	WorkspaceID *int
+	Host        *string
+	Port        *string
 }
 
 func (find *InstanceFind) String() string {
@@ -87,6 +146,26 @@ func (find *InstanceFind) String() string {
 	return string(str)
 }
 
+// InstanceArchive is the API message for archiving the stale instances in bulk.
+// An instance is stale if none of its databases has synced successfully in the last StaleDays days,
+// or it has no databases and was created before that. The instances having databases outside the default project
+// are never archived, the same as archiving a single instance.
+type InstanceArchive struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+	// Value is assigned from the workspace of the updater.
+	WorkspaceID int
+
+	// Related fields
+	EnvironmentID *int `jsonapi:"attr,environmentId"`
+
+	// Domain specific fields
+	StaleDays int `jsonapi:"attr,staleDays"`
+	// DryRun returns the instances to be archived without archiving them.
+	DryRun bool `jsonapi:"attr,dryRun"`
+}
+
 // InstancePatch is the API message for patching an instance.
 type InstancePatch struct {
 	ID int `jsonapi:"primary,instancePatch"`
@@ -96,18 +175,55 @@ type InstancePatch struct {
 	// Value is assigned from the jwt subject field passed by the client.
 	UpdaterID int
 
+	// Related fields
+	// EnvironmentID is only set when moving the instance to another environment via InstanceEnvironmentPatch,
+	// which checks the in-flight pipelines and the policies of the target environment.
+	EnvironmentID *int
+
 	// Domain specific fields
 	Name          *string `jsonapi:"attr,name"`
 	EngineVersion *string
 	ExternalLink  *string `jsonapi:"attr,externalLink"`
 	Host          *string `jsonapi:"attr,host"`
 	Port          *string `jsonapi:"attr,port"`
+	// BackupStorageBackend is set to empty to use the storage backend configured for the server.
+	BackupStorageBackend *string `jsonapi:"attr,backupStorageBackend"`
+	// SSHHost is set to empty to connect the instance directly.
+	SSHHost *string `jsonapi:"attr,sshHost"`
+	SSHPort *string `jsonapi:"attr,sshPort"`
+	SSHUser *string `jsonapi:"attr,sshUser"`
+	// SSHPrivateKey is kept if not set, because it's not returned to the client.
+	SSHPrivateKey *string `jsonapi:"attr,sshPrivateKey"`
+	SSHHostKey    *string `jsonapi:"attr,sshHostKey"`
 	// If true, syncs the schema after patching the instance. The client
 	// may set to false if the target instance contains too many databases
 	// to avoid the request timeout.
 	SyncSchema bool `jsonapi:"attr,syncSchema"`
 }
 
+// InstanceEnvironmentPatch is the API message for moving an instance to another environment.
+type InstanceEnvironmentPatch struct {
+	ID int `jsonapi:"primary,instanceEnvironmentPatch"`
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	EnvironmentID int `jsonapi:"attr,environmentId"`
+}
+
+// SSHConfigFromInstance gets the config of the SSH tunnel through which the instance is connected.
+func SSHConfigFromInstance(instance *Instance) db.SSHConfig {
+	return db.SSHConfig{
+		Host:       instance.SSHHost,
+		Port:       instance.SSHPort,
+		User:       instance.SSHUser,
+		PrivateKey: instance.SSHPrivateKey,
+		HostKey:    instance.SSHHostKey,
+	}
+}
+
 // DataSourceFromInstanceWithType gets a typed data source from a instance.
 func DataSourceFromInstanceWithType(instance *Instance, dataSourceType DataSourceType) *DataSource {
 	for _, dataSource := range instance.DataSourceList {
@@ -165,3 +281,25 @@ type MigrationHistory struct {
 	IssueID string `jsonapi:"attr,issueId"`
 	Payload string `jsonapi:"attr,payload"`
 }
+
+// MigrationHistoryImportFormat is the format of the migration history recorded by other migration tools.
+type MigrationHistoryImportFormat string
+
+const (
+	// MigrationHistoryImportFlyway is the Flyway schema history table.
+	MigrationHistoryImportFlyway MigrationHistoryImportFormat = "FLYWAY"
+	// MigrationHistoryImportLiquibase is the Liquibase DATABASECHANGELOG table.
+	MigrationHistoryImportLiquibase MigrationHistoryImportFormat = "LIQUIBASE"
+)
+
+// MigrationHistoryImport is the API message for importing the migration history recorded by other migration tools.
+type MigrationHistoryImport struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	Format MigrationHistoryImportFormat `jsonapi:"attr,format"`
+	// Table is the history table in the database, defaults to flyway_schema_history for Flyway and DATABASECHANGELOG for Liquibase.
+	Table string `jsonapi:"attr,table"`
+}
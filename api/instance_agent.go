@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// InstanceAgent is the API message for the agent of an instance in a private network.
+// The agent connects out to Bytebase, so that Bytebase never needs the inbound access to the instance.
+type InstanceAgent struct {
+	ID int `jsonapi:"primary,instanceAgent"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	InstanceID int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	TokenHash string
+	// Token is only returned on creation, since only its hash is stored.
+	Token string `jsonapi:"attr,token,omitempty"`
+	// Connected is whether the agent is connected at the moment.
+	Connected bool `jsonapi:"attr,connected"`
+}
+
+// InstanceAgentUpsert is the API message for creating the agent of an instance, or regenerating its token if it already exists.
+type InstanceAgentUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	TokenHash string
+}
+
+// InstanceAgentFind is the API message for finding instance agents.
+type InstanceAgentFind struct {
+	// Related fields
+	InstanceID *int
+
+	// Domain specific fields
+	TokenHash *string
+}
+
+func (find *InstanceAgentFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// InstanceAgentDelete is the API message for deleting the agent of an instance.
+type InstanceAgentDelete struct {
+	// Related fields
+	InstanceID int
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// InstanceDiskUsage is the API message for a disk usage sample of an instance.
+type InstanceDiskUsage struct {
+	ID int `jsonapi:"primary,instanceDiskUsage"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	InstanceID int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	// TotalBytes and FreeBytes are the disk of the database data reported by the instance agent, 0 if unknown.
+	TotalBytes int64 `jsonapi:"attr,totalBytes"`
+	FreeBytes  int64 `jsonapi:"attr,freeBytes"`
+	// DataBytes is the data and index size of the databases synced from the engine views.
+	DataBytes int64 `jsonapi:"attr,dataBytes"`
+}
+
+// InstanceDiskUsageCreate is the API message for creating a disk usage sample.
+type InstanceDiskUsageCreate struct {
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	TotalBytes int64
+	FreeBytes  int64
+	DataBytes  int64
+}
+
+// InstanceDiskUsageFind is the API message for finding disk usage samples.
+type InstanceDiskUsageFind struct {
+	// Standard fields
+	// CreatedTsAfter is inclusive.
+	CreatedTsAfter *int64
+
+	// Related fields
+	InstanceID *int
+}
+
+func (find *InstanceDiskUsageFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
@@ -58,6 +58,8 @@ const (
 	IssueFieldSubscriberList IssueFieldID = "6"
 	// IssueFieldSQL is the field ID for SQL.
 	IssueFieldSQL IssueFieldID = "7"
+	// IssueFieldLabelList is the field ID for label list.
+	IssueFieldLabelList IssueFieldID = "8"
 )
 
 // Issue is the API message for an issue.
@@ -87,6 +89,10 @@ type Issue struct {
 	Assignee       *Principal   `jsonapi:"relation,assignee"`
 	SubscriberList []*Principal `jsonapi:"relation,subscriberList"`
 	Payload        string       `jsonapi:"attr,payload"`
+	// LabelList is the free-form labels for grouping the issues, e.g. the kanban columns.
+	LabelList []string `jsonapi:"attr,labelList"`
+	// ReferenceList is the references in the description resolved to the targets, e.g. #123, db:prod/orders and sheet:123.
+	ReferenceList []*IssueReference `jsonapi:"attr,referenceList"`
 }
 
 // IssueCreate is the API message for creating an issue.
@@ -99,6 +105,9 @@ type IssueCreate struct {
 	ProjectID  int `jsonapi:"attr,projectId"`
 	PipelineID int
 	Pipeline   PipelineCreate `jsonapi:"attr,pipeline"`
+	// WorkspaceID is assigned from the workspace of the creator, the project and the pipeline must belong to it.
+	// It's left 0 for the issues created by the system, which are checked by their callers.
+	WorkspaceID int
 
 	// Domain specific fields
 	Name             string    `jsonapi:"attr,name"`
@@ -107,6 +116,7 @@ type IssueCreate struct {
 	AssigneeID       int       `jsonapi:"attr,assigneeId"`
 	SubscriberIDList []int     `jsonapi:"attr,subscriberIdList"`
 	Payload          string    `jsonapi:"attr,payload"`
+	LabelList        []string  `jsonapi:"attr,labelList"`
 	// CreateContext is used to create the issue pipeline and not persisted.
 	// The context format depends on the issue type. For example, create database issue corresponds to CreateDatabaseContext.
 	// This consolidates the pipeline generation to backend because both frontend and VCS pipeline could create issues and
@@ -117,6 +127,38 @@ type IssueCreate struct {
 	ValidateOnly bool `jsonapi:"attr,validateOnly"`
 }
 
+// IssuePayload is the payload of an issue.
+type IssuePayload struct {
+	// ClonedFromIssueID is the ID of the original issue if the issue is cloned from another issue.
+	ClonedFromIssueID int `json:"clonedFromIssueId,omitempty"`
+	// RecurringTaskID is the ID of the recurring task if the issue is created by a run of the recurring task.
+	RecurringTaskID int `json:"recurringTaskId,omitempty"`
+	// BackupFailureDatabaseID is the ID of the database if the issue is created for its consecutive failed automatic backups.
+	BackupFailureDatabaseID int `json:"backupFailureDatabaseId,omitempty"`
+}
+
+// IssueClone is the API message for cloning an issue.
+// The statements and target databases of the original issue are copied into a new issue.
+type IssueClone struct {
+	ID int `jsonapi:"primary,issueClone"`
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	// Name is the name of the new issue. The original issue name with a suffix is used if empty.
+	Name string `jsonapi:"attr,name"`
+	// AssigneeID is the assignee of the new issue. A default assignee is picked if empty.
+	AssigneeID int `jsonapi:"attr,assigneeId"`
+	// EnvironmentID retargets the new issue to the databases with the same name in this environment.
+	// The databases of the original issue are used if nil.
+	EnvironmentID *int `jsonapi:"attr,environmentId"`
+
+	// ValidateOnly validates the request and previews the review, but does not actually post it.
+	ValidateOnly bool `jsonapi:"attr,validateOnly"`
+}
+
 // CreateDatabaseContext is the issue create context for creating a database.
 type CreateDatabaseContext struct {
 	// InstanceID is the ID of an instance.
@@ -136,6 +178,9 @@ type CreateDatabaseContext struct {
 	// Labels is a json-encoded string from a list of DatabaseLabel.
 	// See definition in api.Database.
 	Labels string `jsonapi:"attr,labels,omitempty"`
+	// TemplateID is the ID of the database template provisioning the database, 0 means no template.
+	// The character set and the collation default to the template ones if not specified.
+	TemplateID int `json:"templateId"`
 }
 
 // UpdateSchemaDetail is the detail of updating database schema.
@@ -149,6 +194,11 @@ type UpdateSchemaDetail struct {
 	Statement string `json:"statement"`
 	// EarliestAllowedTs the earliest execution time of the change at system local Unix timestamp in seconds.
 	EarliestAllowedTs int64 `jsonapi:"attr,earliestAllowedTs"`
+	// TimeoutSeconds is the execution timeout of the schema update task, 0 means no timeout.
+	TimeoutSeconds int64 `json:"timeoutSeconds"`
+	// SchemaName is the schema the unqualified objects of the statement are resolved in, it's only supported for Postgres.
+	// Empty means the search_path of the connection.
+	SchemaName string `json:"schemaName"`
 }
 
 // UpdateSchemaContext is the issue create context for updating database schema.
@@ -158,8 +208,28 @@ type UpdateSchemaContext struct {
 	// DetailList is the details of schema update.
 	// When a project is in tenant mode, there should be one item in the list.
 	DetailList []*UpdateSchemaDetail `json:"updateSchemaDetailList"`
+	// DatabaseIDList and Statement are the shorthand of DetailList for applying the same statement to multiple databases,
+	// mutually exclusive to DetailList. The stages are generated by the environment order, with one task per database.
+	DatabaseIDList []int  `json:"databaseIdList"`
+	Statement      string `json:"statement"`
 	// VCSPushEvent is the event information for VCS push.
 	VCSPushEvent *vcs.PushEvent `json:"vcsPushEvent"`
+	// Canary executes the change on a subset of the tenant databases first, only for tenant mode projects.
+	Canary *CanaryConfig `json:"canary"`
+}
+
+// CanaryConfig is the canary execution config of the tenant batch change.
+// The canary databases are taken out of the deployments into the first stage, and the pipeline is held before the next stage
+// until the soak time passes after the canary finishes, or until it's resumed manually if there is no soak time.
+type CanaryConfig struct {
+	// Percentage is the percentage of the tenant databases in the canary, rounded up.
+	// The databases are picked in the deployment order. Mutually exclusive to Selector.
+	Percentage int `json:"percentage"`
+	// Selector selects the canary databases by labels. Mutually exclusive to Percentage.
+	Selector *LabelSelector `json:"selector"`
+	// SoakSeconds is the time to wait after the canary finishes before proceeding to the remaining databases automatically.
+	// 0 means the remaining databases wait for the manual confirmation.
+	SoakSeconds int64 `json:"soakSeconds"`
 }
 
 // UpdateSchemaGhostDetail is the detail of updating database schema using gh-ost.
@@ -184,6 +254,13 @@ type UpdateSchemaGhostContext struct {
 	VCSPushEvent *vcs.PushEvent
 }
 
+// DatabaseMaintenanceContext is the issue create context for a general maintenance issue of a database.
+type DatabaseMaintenanceContext struct {
+	DatabaseID int `json:"databaseId"`
+	// TaskName is the name of the maintenance task. The task is approved after the maintenance is done to resolve the issue.
+	TaskName string `json:"taskName"`
+}
+
 // PITRContext is the issue create context for performing a PITR in a database.
 type PITRContext struct {
 	DatabaseID int `json:"databaseId"`
@@ -202,6 +279,13 @@ type PITRContext struct {
 	PointInTimeTs *int64 `json:"pointInTimeTs"`
 }
 
+// IssueResponse is the API message for a page of the issue list.
+type IssueResponse struct {
+	// NextToken is the token to fetch the next page, empty if there are no more issues.
+	NextToken string   `jsonapi:"attr,nextToken"`
+	Issues    []*Issue `jsonapi:"relation,issues"`
+}
+
 // IssueFind is the API message for finding issues.
 type IssueFind struct {
 	ID *int
@@ -216,6 +300,20 @@ type IssueFind struct {
 	StatusList  []IssueStatus
 	// If specified, then it will only fetch "Limit" most recently updated issues
 	Limit *int
+	// If specified, then it will only fetch the issues updated less recently than the cursor
+	After *PageCursor
+	// If specified, then it will only fetch the issues of the active projects in the workspace
+	WorkspaceID *int
+	// If specified, then it will only fetch the issues created in [CreatedTsAfter, CreatedTsBefore)
+	CreatedTsAfter  *int64
+	CreatedTsBefore *int64
+	AssigneeID      *int
+	// If specified, then it will only fetch the issues having a stage in the environment
+	EnvironmentID *int
+	// If specified, then it will only fetch the issues having the label
+	Label *string
+	// If specified, then it will only fetch the issues referencing the target in the description
+	Reference *IssueReferenceFind
 }
 
 // IssuePatch is the API message for patching an issue.
@@ -233,6 +331,8 @@ type IssuePatch struct {
 	Description *string `jsonapi:"attr,description"`
 	AssigneeID  *int    `jsonapi:"attr,assigneeId"`
 	Payload     *string `jsonapi:"attr,payload"`
+	// LabelList is comma separated, and the empty string clears the labels.
+	LabelList *string `jsonapi:"attr,labelList"`
 }
 
 // IssueStatusPatch is the API message for patching status of an issue.
@@ -0,0 +1,31 @@
+package api
+
+// IssueReferenceType is the type of the target referenced in an issue description.
+type IssueReferenceType string
+
+const (
+	// IssueReferenceIssue is the reference to another issue, e.g. #123.
+	IssueReferenceIssue IssueReferenceType = "ISSUE"
+	// IssueReferenceDatabase is the reference to the databases by the environment and the database name, e.g. db:prod/orders.
+	IssueReferenceDatabase IssueReferenceType = "DATABASE"
+	// IssueReferenceSheet is the reference to a sheet, e.g. sheet:123.
+	IssueReferenceSheet IssueReferenceType = "SHEET"
+)
+
+// IssueReference is the API message for a reference in an issue description, resolved to the target.
+type IssueReference struct {
+	// Domain specific fields
+	IssueID int
+	Type    IssueReferenceType `json:"type"`
+	// Text is the reference as written in the description, e.g. db:prod/orders.
+	Text     string `json:"text"`
+	TargetID int    `json:"targetId"`
+	// Name is the current name of the target.
+	Name string `json:"name"`
+}
+
+// IssueReferenceFind is the API message for finding the issues referencing a target.
+type IssueReferenceFind struct {
+	Type     IssueReferenceType
+	TargetID int
+}
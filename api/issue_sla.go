@@ -0,0 +1,64 @@
+package api
+
+// IssueSLATimerType is the type of an issue SLA timer.
+type IssueSLATimerType string
+
+const (
+	// IssueSLATimerApproval is the timer for the tasks of a stage to be approved.
+	IssueSLATimerApproval IssueSLATimerType = "APPROVAL"
+	// IssueSLATimerDone is the timer for the tasks of a stage to be done.
+	IssueSLATimerDone IssueSLATimerType = "DONE"
+)
+
+// IssueSLATimer is the SLA timer of a stage of an issue.
+// The timer starts when the issue is created, and stops when the stage is approved or done, or the issue is canceled.
+type IssueSLATimer struct {
+	// Related fields
+	StageID       int `json:"stageId"`
+	EnvironmentID int `json:"environmentId"`
+
+	// Domain specific fields
+	Type          IssueSLATimerType `json:"type"`
+	TargetSeconds int64             `json:"targetSeconds"`
+	StartTs       int64             `json:"startTs"`
+	DueTs         int64             `json:"dueTs"`
+	// StopTs is 0 if the timer is still running.
+	StopTs int64 `json:"stopTs"`
+	// ElapsedSeconds is the duration from the start to the stop, or to now if the timer is still running.
+	ElapsedSeconds int64 `json:"elapsedSeconds"`
+	Breached       bool  `json:"breached"`
+}
+
+// IssueSLA is the API message for the SLA timers of an issue.
+type IssueSLA struct {
+	// Related fields
+	IssueID int `jsonapi:"attr,issueId"`
+
+	// Domain specific fields
+	// Breached is true if any timer of the issue is breached.
+	Breached  bool             `jsonapi:"attr,breached"`
+	TimerList []*IssueSLATimer `jsonapi:"attr,timerList"`
+}
+
+// IssueSLAReportItem is the SLA report of a type of timers in an environment.
+type IssueSLAReportItem struct {
+	// Related fields
+	EnvironmentID int `json:"environmentId"`
+
+	// Domain specific fields
+	Type          IssueSLATimerType `json:"type"`
+	TimerCount    int               `json:"timerCount"`
+	BreachedCount int               `json:"breachedCount"`
+	// AverageLeadSeconds is the average elapsed duration of the stopped timers, 0 if none is stopped.
+	AverageLeadSeconds float64 `json:"averageLeadSeconds"`
+}
+
+// IssueSLAReport is the API message for the SLA report of the issues.
+type IssueSLAReport struct {
+	StartTs int64 `jsonapi:"attr,startTs"`
+	EndTs   int64 `jsonapi:"attr,endTs"`
+	// IssueCount is the count of the issues with any SLA timer.
+	IssueCount          int                   `jsonapi:"attr,issueCount"`
+	BreachedIssueIDList []int                 `jsonapi:"attr,breachedIssueIdList"`
+	ItemList            []*IssueSLAReportItem `jsonapi:"attr,itemList"`
+}
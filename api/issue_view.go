@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// IssueViewFilter is the saved filter of an issue view.
+// The unset fields don't filter the issues.
+type IssueViewFilter struct {
+	StatusList    []IssueStatus `json:"statusList,omitempty"`
+	AssigneeID    *int          `json:"assigneeId,omitempty"`
+	EnvironmentID *int          `json:"environmentId,omitempty"`
+	Label         string        `json:"label,omitempty"`
+}
+
+// IssueView is the API message for an issue view.
+// The issue view is a saved filter of the issues, e.g. a column of the kanban board.
+type IssueView struct {
+	ID int `jsonapi:"primary,issueView"`
+
+	// Standard fields
+	CreatorID   int
+	Creator     *Principal `jsonapi:"relation,creator"`
+	CreatedTs   int64      `jsonapi:"attr,createdTs"`
+	UpdaterID   int
+	Updater     *Principal `jsonapi:"relation,updater"`
+	UpdatedTs   int64      `jsonapi:"attr,updatedTs"`
+	WorkspaceID int        `jsonapi:"attr,workspaceId"`
+
+	// Related fields
+	// ProjectID is the project whose issues the view filters, and the view is shared with everyone in the workspace.
+	// nil means the view filters the issues across the projects, and is private to the creator.
+	ProjectID *int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	Name string `jsonapi:"attr,name"`
+	// Filter is the json-encoded IssueViewFilter.
+	Filter string `jsonapi:"attr,filter"`
+}
+
+// IssueViewCreate is the API message for creating an issue view.
+type IssueViewCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+	// WorkspaceID is assigned from the workspace of the creator.
+	WorkspaceID int
+
+	// Related fields
+	ProjectID *int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	Name   string `jsonapi:"attr,name"`
+	Filter string `jsonapi:"attr,filter"`
+}
+
+// IssueViewFind is the API message for finding issue views.
+type IssueViewFind struct {
+	ID *int
+
+	// Standard fields
+	CreatorID   *int
+	WorkspaceID *int
+
+	// Related fields
+	ProjectID *int
+}
+
+func (find *IssueViewFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// IssueViewPatch is the API message for patching an issue view.
+// The project is immutable, because it decides who can see the view.
+type IssueViewPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Name   *string `jsonapi:"attr,name"`
+	Filter *string `jsonapi:"attr,filter"`
+}
+
+// IssueViewDelete is the API message for deleting an issue view.
+type IssueViewDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
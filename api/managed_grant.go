@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// ManagedGrant is the API message for a managed grant.
+// It declares the privileges the grantee should have on the database. The actual grants are compared with it on the database sync,
+// and the difference is reported as a grant drift anomaly.
+type ManagedGrant struct {
+	ID int `jsonapi:"primary,managedGrant"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	// Grantee is the role name for Postgres, and "user@host" for MySQL and TiDB, and the host defaults to "%" if omitted.
+	Grantee string `jsonapi:"attr,grantee"`
+	// PrivilegeList is the database level privileges in upper case, e.g. "SELECT" for MySQL and "CONNECT" for Postgres.
+	PrivilegeList []string `jsonapi:"attr,privilegeList"`
+}
+
+// ManagedGrantUpsert is the API message for creating a managed grant, or updating its privileges if the grantee is already managed.
+type ManagedGrantUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Grantee       string   `jsonapi:"attr,grantee"`
+	PrivilegeList []string `jsonapi:"attr,privilegeList"`
+}
+
+// ManagedGrantFind is the API message for finding managed grants.
+type ManagedGrantFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+}
+
+func (find *ManagedGrantFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// ManagedGrantDelete is the API message for deleting a managed grant.
+type ManagedGrantDelete struct {
+	ID int
+
+	// Related fields
+	DatabaseID int
+}
+
+// ManagedGrantDrift is the difference between the managed grant and the actual grants of the grantee.
+type ManagedGrantDrift struct {
+	Grantee string `json:"grantee"`
+	// MissingPrivilegeList is the managed privileges not granted, which are granted on remediation.
+	MissingPrivilegeList []string `json:"missingPrivilegeList"`
+	// ExtraPrivilegeList is the granted privileges not managed, which are revoked on remediation.
+	ExtraPrivilegeList []string `json:"extraPrivilegeList"`
+}
@@ -26,6 +26,8 @@ const (
 	DBA Role = "DBA"
 	// Developer is the DEVELOPER role.
 	Developer Role = "DEVELOPER"
+	// Auditor is the AUDITOR role, which can read every resource as the OWNER does but can't change anything.
+	Auditor Role = "AUDITOR"
 )
 
 // Member is the API message for a member.
@@ -42,6 +44,7 @@ type Member struct {
 	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
 
 	// Domain specific fields
+	WorkspaceID int          `jsonapi:"attr,workspaceId"`
 	Status      MemberStatus `jsonapi:"attr,status"`
 	Role        Role         `jsonapi:"attr,role"`
 	PrincipalID int
@@ -55,6 +58,8 @@ type MemberCreate struct {
 	CreatorID int
 
 	// Domain specific fields
+	// WorkspaceID is assigned from the workspace of the creator.
+	WorkspaceID int
 	Status      MemberStatus `jsonapi:"attr,status"`
 	Role        Role         `jsonapi:"attr,role"`
 	PrincipalID int          `jsonapi:"attr,principalId"`
@@ -65,6 +70,7 @@ type MemberFind struct {
 	ID *int
 
 	// Domain specific fields
+	WorkspaceID *int
 	PrincipalID *int
 	Role        *Role
 }
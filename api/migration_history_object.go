@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// MigrationHistoryObject is the API message for a table or column referenced by the statement of a migration history.
+// It's extracted by the parser when the migration is executed, so that we can tell which migrations changed a column.
+type MigrationHistoryObject struct {
+	ID int `jsonapi:"primary,migrationHistoryObject"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+	// MigrationHistoryID is the ID of the migration history in the instance.
+	MigrationHistoryID int `jsonapi:"attr,migrationHistoryId"`
+
+	// Domain specific fields
+	// SchemaName is empty if the statement doesn't qualify the table with a schema.
+	SchemaName string `jsonapi:"attr,schemaName"`
+	TableName  string `jsonapi:"attr,tableName"`
+	// ColumnName is empty if the whole table is referenced, e.g. dropping or renaming the table.
+	ColumnName string `jsonapi:"attr,columnName"`
+}
+
+// MigrationHistoryObjectCreate is the API message for creating a migration history object.
+type MigrationHistoryObjectCreate struct {
+	// Related fields
+	DatabaseID         int
+	MigrationHistoryID int
+
+	// Domain specific fields
+	SchemaName string
+	TableName  string
+	ColumnName string
+}
+
+// MigrationHistoryObjectFind is the API message for finding migration history objects.
+type MigrationHistoryObjectFind struct {
+	// Related fields
+	DatabaseID         *int
+	MigrationHistoryID *int
+
+	// Domain specific fields
+	SchemaName *string
+	TableName  *string
+	// If specified, the objects referencing the whole table are returned as well,
+	// since dropping or renaming the table changes the column too.
+	ColumnName *string
+}
+
+func (find *MigrationHistoryObjectFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
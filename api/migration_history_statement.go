@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// MigrationHistoryStatement is the API message for the execution of a statement of a migration history.
+// It's recorded when the statements of the migration are executed one by one, so that the slow migrations can be analyzed later
+// and compared across the environments.
+type MigrationHistoryStatement struct {
+	ID int `jsonapi:"primary,migrationHistoryStatement"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+	// MigrationHistoryID is the ID of the migration history in the instance.
+	MigrationHistoryID int `jsonapi:"attr,migrationHistoryId"`
+
+	// Domain specific fields
+	// StatementIndex is the 1-based position of the statement in the migration.
+	StatementIndex int    `jsonapi:"attr,statementIndex"`
+	Statement      string `jsonapi:"attr,statement"`
+	DurationNs     int64  `jsonapi:"attr,durationNs"`
+	RowsAffected   int64  `jsonapi:"attr,rowsAffected"`
+}
+
+// MigrationHistoryStatementCreate is the API message for creating a migration history statement.
+type MigrationHistoryStatementCreate struct {
+	// Related fields
+	DatabaseID         int
+	MigrationHistoryID int
+
+	// Domain specific fields
+	StatementIndex int
+	Statement      string
+	DurationNs     int64
+	RowsAffected   int64
+}
+
+// MigrationHistoryStatementFind is the API message for finding migration history statements.
+type MigrationHistoryStatementFind struct {
+	// Related fields
+	DatabaseID         *int
+	MigrationHistoryID *int
+}
+
+func (find *MigrationHistoryStatementFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// MigrationStatTaskTypeList is the list of the task types counted as migrations in the migration statistics.
+// The gh-ost migration is counted by its cutover task.
+var MigrationStatTaskTypeList = []TaskType{
+	TaskDatabaseSchemaUpdate,
+	TaskDatabaseSchemaUpdateGhostCutover,
+	TaskDatabaseDataUpdate,
+}
+
+// MigrationStatFind is the API message for finding the migration statistics.
+type MigrationStatFind struct {
+	// Related fields
+	EnvironmentID *int
+	ProjectID     *int
+	// If specified, then it will only count the migrations of the projects in the workspace.
+	WorkspaceID *int
+
+	// Domain specific fields
+	// The statistics cover the runs created in the time window [StartTs, EndTs).
+	StartTs int64
+	EndTs   int64
+}
+
+func (find *MigrationStatFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// MigrationRunCount is the count of the finished migration task runs of an environment and a project.
+type MigrationRunCount struct {
+	// Related fields
+	EnvironmentID int
+	ProjectID     int
+
+	// Domain specific fields
+	DoneCount   int
+	FailedCount int
+	// DoneDurationSeconds is the total duration of the succeeded runs.
+	DoneDurationSeconds int64
+}
+
+// MigrationCheckFailureCount is the count of the failed task checks of a type.
+// A task check fails if it reports an error that is not suppressed.
+type MigrationCheckFailureCount struct {
+	// Related fields
+	EnvironmentID int `json:"-"`
+	ProjectID     int `json:"-"`
+
+	// Domain specific fields
+	Type  TaskCheckType `json:"type"`
+	Count int           `json:"count"`
+}
+
+// MigrationStatSummary is the migration statistics of an environment, a project or the whole workspace.
+type MigrationStatSummary struct {
+	// ID is the ID of the environment or the project, 0 for the whole workspace.
+	ID             int `json:"id"`
+	MigrationCount int `json:"migrationCount"`
+	FailedCount    int `json:"failedCount"`
+	// FailureRate is the ratio of the failed migrations in [0, 1], 0 if there are no migrations.
+	FailureRate float64 `json:"failureRate"`
+	// AverageDurationSeconds is the average duration of the succeeded migrations.
+	AverageDurationSeconds float64 `json:"averageDurationSeconds"`
+	// CheckFailureList is the count of the failed task checks grouped by the check type, the most failed first.
+	CheckFailureList []*MigrationCheckFailureCount `json:"checkFailureList"`
+}
+
+// MigrationStat is the API message for the migration statistics dashboard.
+type MigrationStat struct {
+	StartTs         int64                   `jsonapi:"attr,startTs"`
+	EndTs           int64                   `jsonapi:"attr,endTs"`
+	Total           *MigrationStatSummary   `jsonapi:"attr,total"`
+	EnvironmentList []*MigrationStatSummary `jsonapi:"attr,environmentList"`
+	ProjectList     []*MigrationStatSummary `jsonapi:"attr,projectList"`
+}
@@ -107,6 +107,11 @@ const (
 	// Currently, we only support GitLab EE/CE auth.
 	Feature3rdPartyAuth FeatureType = "bb.feature.3rd-party-auth"
 
+	// FeatureMultiWorkspace allows user to host multiple isolated workspaces in a single deployment.
+	//
+	// Each workspace has its own members, environments, instances and projects.
+	FeatureMultiWorkspace FeatureType = "bb.feature.multi-workspace"
+
 	// Branding.
 
 	// FeatureBranding enables customized branding.
@@ -142,6 +147,8 @@ func (e FeatureType) Name() string {
 		return "RBAC"
 	case Feature3rdPartyAuth:
 		return "3rd party auth"
+	case FeatureMultiWorkspace:
+		return "Multi-workspace"
 	case FeatureBranding:
 		return "Branding"
 	case FeatureEnvironmentTierPolicy:
@@ -167,6 +174,35 @@ func (e FeatureType) minimumSupportedPlan() PlanType {
 	return ENTERPRISE
 }
 
+// FeatureErrorCode is the code of FeatureError, so that the client can tell it apart from the other errors.
+const FeatureErrorCode = "bb.error.feature-not-available"
+
+// FeatureError is the structured error returned when the feature isn't available in the current plan.
+// It's used as the response body as is, so the client can guide the user to upgrade to the required plan.
+type FeatureError struct {
+	// Message is the readable error message, it's named the same as the other error responses.
+	Message      string      `json:"message"`
+	Code         string      `json:"code"`
+	Feature      FeatureType `json:"feature"`
+	CurrentPlan  string      `json:"currentPlan"`
+	RequiredPlan string      `json:"requiredPlan"`
+}
+
+// NewFeatureError creates a FeatureError for the feature which isn't available in the current plan.
+func NewFeatureError(feature FeatureType, currentPlan PlanType) *FeatureError {
+	return &FeatureError{
+		Message:      feature.AccessErrorMessage(),
+		Code:         FeatureErrorCode,
+		Feature:      feature,
+		CurrentPlan:  currentPlan.String(),
+		RequiredPlan: feature.minimumSupportedPlan().String(),
+	}
+}
+
+func (e *FeatureError) Error() string {
+	return e.Message
+}
+
 // FeatureMatrix is a map from the a particular feature to the respective enablement of a particular plan.
 var FeatureMatrix = map[FeatureType][3]bool{
 	FeatureSchemaDrift:           {false, true, true},
@@ -181,6 +217,7 @@ var FeatureMatrix = map[FeatureType][3]bool{
 	FeatureSQLReviewPolicy:       {false, true, true},
 	FeatureRBAC:                  {false, true, true},
 	Feature3rdPartyAuth:          {false, true, true},
+	FeatureMultiWorkspace:        {false, false, true},
 	FeatureBranding:              {false, true, true},
 	FeatureEnvironmentTierPolicy: {false, false, true},
 }
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFeatureError(t *testing.T) {
+	err := NewFeatureError(FeatureEnvironmentTierPolicy, TEAM)
+	assert.Equal(t, &FeatureError{
+		Message:      "Environment tier is a ENTERPRISE feature, please upgrade to access it.",
+		Code:         FeatureErrorCode,
+		Feature:      FeatureEnvironmentTierPolicy,
+		CurrentPlan:  "TEAM",
+		RequiredPlan: "ENTERPRISE",
+	}, err)
+	assert.Equal(t, err.Message, err.Error())
+}
@@ -22,6 +22,9 @@ type BackupPlanPolicySchedule string
 // EnvironmentTierValue is the value for environment tier policy.
 type EnvironmentTierValue string
 
+// TaskFailureAction is the action taken on the remaining tasks when a task fails.
+type TaskFailureAction string
+
 const (
 	// DefaultPolicyID is the ID of the default policy.
 	DefaultPolicyID int = 0
@@ -34,6 +37,12 @@ const (
 	PolicyTypeSQLReview PolicyType = "bb.policy.sql-review"
 	// PolicyTypeEnvironmentTier is the tier of an environment.
 	PolicyTypeEnvironmentTier PolicyType = "bb.policy.environment-tier"
+	// PolicyTypeQueryLimit is the query limit policy type.
+	PolicyTypeQueryLimit PolicyType = "bb.policy.query-limit"
+	// PolicyTypeTaskFailure is the task failure policy type.
+	PolicyTypeTaskFailure PolicyType = "bb.policy.task-failure"
+	// PolicyTypeIssueSLA is the issue SLA policy type.
+	PolicyTypeIssueSLA PolicyType = "bb.policy.issue-sla"
 
 	// PipelineApprovalValueManualNever means the pipeline will automatically be approved without user intervention.
 	PipelineApprovalValueManualNever PipelineApprovalValue = "MANUAL_APPROVAL_NEVER"
@@ -54,6 +63,14 @@ const (
 	EnvironmentTierValueProtected EnvironmentTierValue = "PROTECTED"
 	// EnvironmentTierValueUnprotected is UNPROTECTED environment tier value.
 	EnvironmentTierValueUnprotected EnvironmentTierValue = "UNPROTECTED"
+
+	// TaskFailureActionContinue keeps scheduling the remaining tasks after a task fails.
+	TaskFailureActionContinue TaskFailureAction = "CONTINUE"
+	// TaskFailureActionPauseStage pauses the stage of the failed task, so that its remaining pending tasks are not scheduled until it's resumed.
+	TaskFailureActionPauseStage TaskFailureAction = "PAUSE_STAGE"
+
+	// DefaultQueryMaxBytes is the maximum size of the query result for the roles without a query limit.
+	DefaultQueryMaxBytes int64 = 100 * 1024 * 1024
 )
 
 var (
@@ -63,6 +80,9 @@ var (
 		PolicyTypeBackupPlan:       true,
 		PolicyTypeSQLReview:        true,
 		PolicyTypeEnvironmentTier:  true,
+		PolicyTypeQueryLimit:       true,
+		PolicyTypeTaskFailure:      true,
+		PolicyTypeIssueSLA:         true,
 	}
 )
 
@@ -94,6 +114,8 @@ type PolicyFind struct {
 
 	// Related fields
 	EnvironmentID *int
+	// WorkspaceID finds the policies of the environments in the workspace.
+	WorkspaceID *int
 
 	// Domain specific fields
 	Type *PolicyType `jsonapi:"attr,type"`
@@ -226,6 +248,99 @@ func UnmarshalEnvironmentTierPolicy(payload string) (*EnvironmentTierPolicy, err
 	return &p, nil
 }
 
+// QueryLimitPolicy is the policy configuration for the limits on the SQL editor query results in an environment.
+// The roles without a limit in the LimitList are only limited by DefaultQueryMaxBytes.
+type QueryLimitPolicy struct {
+	LimitList []QueryLimit `json:"limitList"`
+}
+
+// QueryLimit is the limit on the query results for a role.
+type QueryLimit struct {
+	Role Role `json:"role"`
+	// MaxRows is the maximum number of rows returned, 0 means unlimited.
+	MaxRows int `json:"maxRows"`
+	// MaxBytes is the maximum size of the rows returned in bytes, 0 means unlimited.
+	MaxBytes int64 `json:"maxBytes"`
+}
+
+func (p *QueryLimitPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// GetQueryLimit returns the query limit for the role.
+func (p *QueryLimitPolicy) GetQueryLimit(role Role) QueryLimit {
+	for _, limit := range p.LimitList {
+		if limit.Role == role {
+			return limit
+		}
+	}
+	return QueryLimit{
+		Role:     role,
+		MaxBytes: DefaultQueryMaxBytes,
+	}
+}
+
+// UnmarshalQueryLimitPolicy will unmarshal payload to query limit policy.
+func UnmarshalQueryLimitPolicy(payload string) (*QueryLimitPolicy, error) {
+	var p QueryLimitPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal query limit policy %q", payload)
+	}
+	return &p, nil
+}
+
+// TaskFailurePolicy is the policy configuration for the task failure of the batch pipelines of the tenant mode projects in an environment.
+type TaskFailurePolicy struct {
+	Action TaskFailureAction `json:"action"`
+}
+
+func (p *TaskFailurePolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalTaskFailurePolicy will unmarshal payload to task failure policy.
+func UnmarshalTaskFailurePolicy(payload string) (*TaskFailurePolicy, error) {
+	var p TaskFailurePolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal task failure policy %q", payload)
+	}
+	return &p, nil
+}
+
+// IssueSLAPolicy is the policy configuration for the SLA targets of the issues changing the databases in an environment.
+// The targets are the durations since the issue is created, 0 means no target.
+type IssueSLAPolicy struct {
+	// ApprovalSeconds is the target for the tasks in the environment to be approved.
+	ApprovalSeconds int64 `json:"approvalSeconds"`
+	// DoneSeconds is the target for the tasks in the environment to be done.
+	DoneSeconds int64 `json:"doneSeconds"`
+}
+
+func (p *IssueSLAPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalIssueSLAPolicy will unmarshal payload to issue SLA policy.
+func UnmarshalIssueSLAPolicy(payload string) (*IssueSLAPolicy, error) {
+	var p IssueSLAPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal issue SLA policy %q", payload)
+	}
+	return &p, nil
+}
+
 // ValidatePolicy will validate the policy type and payload values.
 func ValidatePolicy(pType PolicyType, payload string) error {
 	if !PolicyTypes[pType] {
@@ -280,6 +395,40 @@ func ValidatePolicy(pType PolicyType, payload string) error {
 		if p.EnvironmentTier != EnvironmentTierValueProtected && p.EnvironmentTier != EnvironmentTierValueUnprotected {
 			return errors.Errorf("invalid environment tier value %q", p.EnvironmentTier)
 		}
+	case PolicyTypeQueryLimit:
+		p, err := UnmarshalQueryLimitPolicy(payload)
+		if err != nil {
+			return err
+		}
+		roleSeen := make(map[Role]bool)
+		for _, limit := range p.LimitList {
+			if limit.Role != Owner && limit.Role != DBA && limit.Role != Developer {
+				return errors.Errorf("invalid query limit role %q", limit.Role)
+			}
+			if roleSeen[limit.Role] {
+				return errors.Errorf("duplicate query limit role %q", limit.Role)
+			}
+			roleSeen[limit.Role] = true
+			if limit.MaxRows < 0 || limit.MaxBytes < 0 {
+				return errors.Errorf("query limit for role %q must be non-negative", limit.Role)
+			}
+		}
+	case PolicyTypeTaskFailure:
+		p, err := UnmarshalTaskFailurePolicy(payload)
+		if err != nil {
+			return err
+		}
+		if p.Action != TaskFailureActionContinue && p.Action != TaskFailureActionPauseStage {
+			return errors.Errorf("invalid task failure action %q", p.Action)
+		}
+	case PolicyTypeIssueSLA:
+		p, err := UnmarshalIssueSLAPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if p.ApprovalSeconds < 0 || p.DoneSeconds < 0 {
+			return errors.Errorf("issue SLA targets must be non-negative")
+		}
 	}
 	return nil
 }
@@ -306,6 +455,19 @@ func GetDefaultPolicy(pType PolicyType) (string, error) {
 			EnvironmentTier: EnvironmentTierValueUnprotected,
 		}
 		return policy.String()
+	case PolicyTypeQueryLimit:
+		policy := QueryLimitPolicy{
+			LimitList: []QueryLimit{},
+		}
+		return policy.String()
+	case PolicyTypeTaskFailure:
+		policy := TaskFailurePolicy{
+			Action: TaskFailureActionContinue,
+		}
+		return policy.String()
+	case PolicyTypeIssueSLA:
+		policy := IssueSLAPolicy{}
+		return policy.String()
 	}
 	return "", nil
 }
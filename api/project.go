@@ -72,6 +72,7 @@ type Project struct {
 	ProjectMemberList []*ProjectMember `jsonapi:"relation,projectMember"`
 
 	// Domain specific fields
+	WorkspaceID  int                 `jsonapi:"attr,workspaceId"`
 	Name         string              `jsonapi:"attr,name"`
 	Key          string              `jsonapi:"attr,key"`
 	WorkflowType ProjectWorkflowType `jsonapi:"attr,workflowType"`
@@ -92,6 +93,8 @@ type ProjectCreate struct {
 	CreatorID int
 
 	// Domain specific fields
+	// WorkspaceID is assigned from the workspace of the creator.
+	WorkspaceID         int
 	Name                string                     `jsonapi:"attr,name"`
 	Key                 string                     `jsonapi:"attr,key"`
 	TenantMode          ProjectTenantMode          `jsonapi:"attr,tenantMode"`
@@ -110,6 +113,7 @@ type ProjectFind struct {
 	// Domain specific fields
 	// If present, will only find project containing PrincipalID as an active member
 	PrincipalID *int
+	WorkspaceID *int
 }
 
 func (find *ProjectFind) String() string {
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/pkg/errors"
+)
+
+// ProjectSQLReviewOverride is the API message for the project level overrides on the environment SQL review policy.
+type ProjectSQLReviewOverride struct {
+	ID int `jsonapi:"primary,projectSQLReviewOverride"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	// Just returns ProjectID since it always operates within the project context
+	ProjectID int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	// Payload is the stringify value for ProjectSQLReviewOverridePayload.
+	Payload string `jsonapi:"attr,payload"`
+}
+
+// ProjectSQLReviewOverrideUpsert is the API message for upserting the project SQL review override.
+type ProjectSQLReviewOverrideUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Payload string `jsonapi:"attr,payload"`
+}
+
+// ProjectSQLReviewOverridePayload is the payload of the project SQL review override.
+type ProjectSQLReviewOverridePayload struct {
+	RuleList []*advisor.SQLReviewRuleOverride `json:"ruleList"`
+}
+
+// UnmarshalProjectSQLReviewOverridePayload will unmarshal and validate the payload of the project SQL review override.
+func UnmarshalProjectSQLReviewOverridePayload(payload string) (*ProjectSQLReviewOverridePayload, error) {
+	var p ProjectSQLReviewOverridePayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal project SQL review override %q", payload)
+	}
+	if err := advisor.ValidateSQLReviewRuleOverrideList(p.RuleList); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ProjectSQLReviewPolicy is the API message for the effective SQL review policy of a project in an environment.
+// It's the environment SQL review policy merged with the project overrides.
+type ProjectSQLReviewPolicy struct {
+	// ID is the ID of the environment SQL review policy.
+	ID int `jsonapi:"primary,projectSQLReviewPolicy"`
+
+	// Related fields
+	ProjectID     int `jsonapi:"attr,projectId"`
+	EnvironmentID int `jsonapi:"attr,environmentId"`
+
+	// Domain specific fields
+	// Payload is the stringify value for the effective advisor.SQLReviewPolicy.
+	Payload string `jsonapi:"attr,payload"`
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// QueryAuditLogType is the type of a query audit log.
+type QueryAuditLogType string
+
+const (
+	// QueryAuditLogQuery is the query audit log type for executing the statement in the SQL editor.
+	QueryAuditLogQuery QueryAuditLogType = "QUERY"
+	// QueryAuditLogExport is the query audit log type for exporting the query result.
+	QueryAuditLogExport QueryAuditLogType = "EXPORT"
+	// QueryAuditLogExecute is the query audit log type for executing the write statement with the elevated access in the SQL editor.
+	QueryAuditLogExecute QueryAuditLogType = "EXECUTE"
+)
+
+// QueryAuditLog is the API message for a query audit log.
+// It's kept separately from the activity for compliance, and purged after the retention period.
+type QueryAuditLog struct {
+	ID int `jsonapi:"primary,queryAuditLog"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	InstanceID int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	Type         QueryAuditLogType `jsonapi:"attr,type"`
+	DatabaseName string            `jsonapi:"attr,databaseName"`
+	Statement    string            `jsonapi:"attr,statement"`
+	// Fingerprint is the statement with the literals replaced by placeholders.
+	Fingerprint string `jsonapi:"attr,fingerprint"`
+	// RowCount is the number of the rows returned or exported.
+	RowCount   int64  `jsonapi:"attr,rowCount"`
+	DurationNs int64  `jsonapi:"attr,durationNs"`
+	Error      string `jsonapi:"attr,error"`
+}
+
+// QueryAuditLogCreate is the API message for creating a query audit log.
+type QueryAuditLogCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	InstanceID int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	Type         QueryAuditLogType
+	DatabaseName string `jsonapi:"attr,databaseName"`
+	Statement    string `jsonapi:"attr,statement"`
+	Fingerprint  string
+	RowCount     int64 `jsonapi:"attr,rowCount"`
+	DurationNs   int64
+	Error        string
+}
+
+// QueryAuditLogFind is the API message for finding query audit logs.
+type QueryAuditLogFind struct {
+	// Standard fields
+	CreatorID *int
+	// CreatedTsAfter and CreatedTsBefore are inclusive.
+	CreatedTsAfter  *int64
+	CreatedTsBefore *int64
+
+	// Related fields
+	InstanceID *int
+	// WorkspaceID finds the query audit logs of the instances in the workspace.
+	WorkspaceID *int
+
+	// Domain specific fields
+	Type         *QueryAuditLogType
+	DatabaseName *string
+	Fingerprint  *string
+	// If specified, only the latest "Limit" query audit logs are returned.
+	Limit *int
+}
+
+func (find *QueryAuditLogFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// RecurringTask is the API message for a recurring task.
+// A recurring task runs the housekeeping DML statement (e.g. purging expired rows) on the database following the cron schedule.
+// Each run creates a data update issue, so the execution goes through the normal task executor and keeps the history.
+type RecurringTask struct {
+	ID int `jsonapi:"primary,recurringTask"`
+
+	// Standard fields
+	RowStatus RowStatus `jsonapi:"attr,rowStatus"`
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int       `jsonapi:"attr,databaseId"`
+	Database   *Database `jsonapi:"relation,database"`
+
+	// Domain specific fields
+	Name      string `jsonapi:"attr,name"`
+	Statement string `jsonapi:"attr,statement"`
+	// Schedule is the cron expression with five fields, e.g. "0 3 * * *" for 03:00 every day in the server time zone.
+	Schedule string `jsonapi:"attr,schedule"`
+	// MaxAffectedRows is the limit of the total affected rows of each run, 0 means unlimited.
+	// The run fails and is rolled back if the statement affects more rows than the limit.
+	MaxAffectedRows int64 `jsonapi:"attr,maxAffectedRows"`
+	// NextRunTs is the UNIX timestamp in seconds of the next run.
+	NextRunTs int64 `jsonapi:"attr,nextRunTs"`
+	// LastIssueID is the ID of the issue created by the last run, 0 if the recurring task has never run.
+	LastIssueID int `jsonapi:"attr,lastIssueId"`
+}
+
+// RecurringTaskCreate is the API message for creating a recurring task.
+type RecurringTaskCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	Name            string `jsonapi:"attr,name"`
+	Statement       string `jsonapi:"attr,statement"`
+	Schedule        string `jsonapi:"attr,schedule"`
+	MaxAffectedRows int64  `jsonapi:"attr,maxAffectedRows"`
+	// NextRunTs is calculated from the schedule by the server.
+	NextRunTs int64
+}
+
+// RecurringTaskFind is the API message for finding recurring tasks.
+type RecurringTaskFind struct {
+	ID *int
+
+	// Standard fields
+	RowStatus *RowStatus
+
+	// Related fields
+	DatabaseID *int
+	// WorkspaceID finds the recurring tasks of the databases in the workspace.
+	WorkspaceID *int
+
+	// Domain specific fields
+	// Find recurring tasks whose next run is no later than this UNIX timestamp in seconds.
+	NextRunTsBefore *int64
+}
+
+func (find *RecurringTaskFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// RecurringTaskPatch is the API message for patching a recurring task.
+type RecurringTaskPatch struct {
+	ID int `jsonapi:"primary,recurringTaskPatch"`
+
+	// Standard fields
+	RowStatus *string `jsonapi:"attr,rowStatus"`
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Name            *string `jsonapi:"attr,name"`
+	Statement       *string `jsonapi:"attr,statement"`
+	Schedule        *string `jsonapi:"attr,schedule"`
+	MaxAffectedRows *int64  `jsonapi:"attr,maxAffectedRows"`
+	NextRunTs       *int64
+	LastIssueID     *int
+}
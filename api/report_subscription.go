@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// ReportSubscription is the API message for the subscription of a principal to the weekly report of a project.
+// The weekly report summarizes the migrations applied, the failed tasks, the open anomalies and the upcoming scheduled tasks.
+type ReportSubscription struct {
+	ID int `jsonapi:"primary,reportSubscription"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectID   int `jsonapi:"attr,projectId"`
+	PrincipalID int `jsonapi:"attr,principalId"`
+
+	// Domain specific fields
+	Enabled bool `jsonapi:"attr,enabled"`
+	// LastSentTs is when the last report was sent, 0 means never.
+	LastSentTs int64 `jsonapi:"attr,lastSentTs"`
+}
+
+// ReportSubscriptionUpsert is the API message for creating or updating the subscription of a principal to the report of a project.
+type ReportSubscriptionUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	ProjectID   int
+	PrincipalID int
+
+	// Domain specific fields
+	Enabled bool `jsonapi:"attr,enabled"`
+}
+
+// ReportSubscriptionFind is the API message for finding report subscriptions.
+type ReportSubscriptionFind struct {
+	ID *int
+
+	// Related fields
+	ProjectID   *int
+	PrincipalID *int
+
+	// Domain specific fields
+	Enabled *bool
+}
+
+func (find *ReportSubscriptionFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// ReportSubscriptionPatch is the API message for patching a report subscription.
+type ReportSubscriptionPatch struct {
+	ID int
+
+	// Standard fields
+	UpdaterID int
+
+	// Domain specific fields
+	LastSentTs *int64
+}
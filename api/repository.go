@@ -88,6 +88,7 @@ type RepositoryFind struct {
 
 	// Domain specific fields
 	WebhookEndpointID *string
+	ExternalID        *string
 }
 
 func (find *RepositoryFind) String() string {
@@ -128,3 +129,62 @@ type RepositoryDelete struct {
 	// Value is assigned from the jwt subject field passed by the client.
 	DeleterID int
 }
+
+// RepositoryFile is the API message for a file in the repository.
+type RepositoryFile struct {
+	// Path is the file path relative to the repository root, which is unique in the repository.
+	Path string `jsonapi:"primary,repositoryFile"`
+
+	// Domain specific fields
+	Name string `jsonapi:"attr,name"`
+	Size int64  `jsonapi:"attr,size"`
+	// LastCommitID and Content are only returned when fetching a single file.
+	LastCommitID string `jsonapi:"attr,lastCommitId"`
+	Content      string `jsonapi:"attr,content"`
+}
+
+// RepositoryCheckType is the type of a repository check item.
+type RepositoryCheckType string
+
+const (
+	// RepositoryCheckAccessToken checks the access token of the repository is valid.
+	RepositoryCheckAccessToken RepositoryCheckType = "bb.repository.access-token"
+	// RepositoryCheckTokenScope checks the access token has the permission to manage the webhook of the repository.
+	RepositoryCheckTokenScope RepositoryCheckType = "bb.repository.token-scope"
+	// RepositoryCheckWebhook checks the webhook exists and delivers the events to Bytebase.
+	RepositoryCheckWebhook RepositoryCheckType = "bb.repository.webhook"
+	// RepositoryCheckWebhookReachable checks the webhook URL is reachable from the VCS.
+	RepositoryCheckWebhookReachable RepositoryCheckType = "bb.repository.webhook-reachable"
+	// RepositoryCheckBaseDirectory checks the base directory exists on the branch.
+	RepositoryCheckBaseDirectory RepositoryCheckType = "bb.repository.base-directory"
+	// RepositoryCheckFilePathTemplate checks the file path template and the schema path template are valid.
+	RepositoryCheckFilePathTemplate RepositoryCheckType = "bb.repository.file-path-template"
+)
+
+// RepositoryCheckStatus is the status of a repository check item.
+type RepositoryCheckStatus string
+
+const (
+	// RepositoryCheckStatusSuccess is the status of the passed check.
+	RepositoryCheckStatusSuccess RepositoryCheckStatus = "SUCCESS"
+	// RepositoryCheckStatusWarn is the status of the check which may break the GitOps workflow.
+	RepositoryCheckStatusWarn RepositoryCheckStatus = "WARN"
+	// RepositoryCheckStatusError is the status of the check which breaks the GitOps workflow.
+	RepositoryCheckStatusError RepositoryCheckStatus = "ERROR"
+)
+
+// RepositoryCheckItem is the result of a single check on the repository configuration.
+type RepositoryCheckItem struct {
+	Type   RepositoryCheckType   `json:"type"`
+	Status RepositoryCheckStatus `json:"status"`
+	// Message explains the failure, or is empty if the check passes.
+	Message string `json:"message"`
+}
+
+// RepositoryCheckResult is the API message for the result of checking the GitOps configuration of the project end-to-end.
+type RepositoryCheckResult struct {
+	ProjectID int `jsonapi:"primary,repositoryCheckResult"`
+
+	// Domain specific fields
+	ItemList []*RepositoryCheckItem `jsonapi:"attr,itemList"`
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// SchemaSnapshotType is the type of a schema snapshot.
+type SchemaSnapshotType string
+
+const (
+	// SchemaSnapshotDaily is the schema snapshot taken every day.
+	SchemaSnapshotDaily SchemaSnapshotType = "DAILY"
+	// SchemaSnapshotMigration is the schema snapshot taken after a migration.
+	SchemaSnapshotMigration SchemaSnapshotType = "MIGRATION"
+)
+
+// SchemaSnapshot is the API message for a schema snapshot of a database.
+type SchemaSnapshot struct {
+	ID int `jsonapi:"primary,schemaSnapshot"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	Type             SchemaSnapshotType `jsonapi:"attr,type"`
+	MigrationVersion string             `jsonapi:"attr,migrationVersion"`
+	// Schema is only loaded when fetching a single snapshot.
+	Schema string `jsonapi:"attr,schema,omitempty"`
+}
+
+// SchemaSnapshotCreate is the API message for creating a schema snapshot.
+type SchemaSnapshotCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Type             SchemaSnapshotType
+	MigrationVersion string
+	Schema           string
+}
+
+// SchemaSnapshotFind is the API message for finding schema snapshots.
+type SchemaSnapshotFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Type *SchemaSnapshotType
+	// CreatedTsBefore finds the snapshots created at or before the timestamp, used to find the schema as of a point in time.
+	CreatedTsBefore *int64
+	// CreatedTsAfter finds the snapshots created at or after the timestamp.
+	CreatedTsAfter *int64
+	Limit          *int
+}
+
+func (find *SchemaSnapshotFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// SchemaSnapshotDiff is the API message for the diff between two schema snapshots.
+type SchemaSnapshotDiff struct {
+	FromSnapshotID int `json:"fromSnapshotId"`
+	ToSnapshotID   int `json:"toSnapshotId"`
+	// Diff is the unified diff from the schema of the from snapshot to the one of the to snapshot, empty if they are the same.
+	Diff string `json:"diff"`
+}
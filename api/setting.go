@@ -16,8 +16,96 @@ const (
 	SettingWorkspaceID SettingName = "bb.workspace.id"
 	// SettingEnterpriseLicense is the setting name for enterprise license.
 	SettingEnterpriseLicense SettingName = "bb.enterprise.license"
+	// SettingTaskConcurrencyGlobal is the setting name for the maximum number of tasks running simultaneously in the workspace.
+	// 0 means unlimited.
+	SettingTaskConcurrencyGlobal SettingName = "bb.task.concurrency.global"
+	// SettingTaskConcurrencyInstance is the setting name for the maximum number of tasks running simultaneously on a single instance.
+	// 0 means unlimited.
+	SettingTaskConcurrencyInstance SettingName = "bb.task.concurrency.instance"
+	// SettingWorkspaceMetricOptOut is the setting name for opting out of sending the usage metrics outside the workspace.
+	// The usage metrics are still persisted locally for the usage dashboard.
+	SettingWorkspaceMetricOptOut SettingName = "bb.workspace.metric.opt-out"
+	// SettingWorkspaceMetricCollectorURL is the setting name for the endpoint receiving the usage metrics.
+	// Empty means not sending the usage metrics to any endpoint.
+	SettingWorkspaceMetricCollectorURL SettingName = "bb.workspace.metric.collector-url"
+	// SettingMaintenance is the setting name for the read-only maintenance mode, e.g. during the metadata database maintenance.
+	// The value is MaintenanceSetting in JSON.
+	SettingMaintenance SettingName = "bb.maintenance"
+	// SettingQueryAuditLogRetentionDays is the setting name for the number of days to keep the query audit logs.
+	// 0 means keeping the query audit logs forever.
+	SettingQueryAuditLogRetentionDays SettingName = "bb.query-audit-log.retention-days"
+	// SettingSQLEditorExportMaxRows is the setting name for the maximum number of rows exported from the SQL editor at once.
+	// 0 means unlimited.
+	SettingSQLEditorExportMaxRows SettingName = "bb.sql-editor.export-max-rows"
+	// SettingWorkspaceProxy is the setting name for the outbound HTTP proxy used by the VCS providers and the webhooks.
+	// The value is ProxySetting in JSON.
+	SettingWorkspaceProxy SettingName = "bb.workspace.proxy"
+	// SettingWorkspaceCACertificates is the setting name for the PEM encoded CA certificates trusted besides the system ones,
+	// when connecting to the VCS providers, the webhooks and the databases with SSL.
+	SettingWorkspaceCACertificates SettingName = "bb.workspace.ca-certificates"
+	// SettingTaskHeartbeatTimeoutSeconds is the setting name for the number of seconds without heartbeat after which
+	// the running task is considered orphaned, e.g. its executor crashed. 0 means the orphaned tasks are re-run right away.
+	SettingTaskHeartbeatTimeoutSeconds SettingName = "bb.task.heartbeat-timeout-seconds"
+	// SettingMailSMTP is the setting name for the SMTP server sending the emails, e.g. the weekly project reports.
+	// The value is SMTPSetting in JSON.
+	SettingMailSMTP SettingName = "bb.mail.smtp"
+	// SettingInstanceDiskFullDays is the setting name for the number of days within which the instance disk is projected to fill up
+	// to raise the disk full anomaly. 0 means the anomaly is disabled.
+	SettingInstanceDiskFullDays SettingName = "bb.instance.disk-full-days"
+	// SettingTaskCheckCustom is the setting name for the custom task check plugins run against the schema and data update tasks.
+	// The value is TaskCheckCustomSetting in JSON.
+	SettingTaskCheckCustom SettingName = "bb.task-check.custom"
 )
 
+// MaintenanceSetting is the value of the maintenance setting.
+// While enabled, the mutating requests are rejected and the background runners are paused.
+type MaintenanceSetting struct {
+	Enabled bool `json:"enabled"`
+	// Message is displayed in the banner while the maintenance mode is enabled.
+	Message string `json:"message"`
+}
+
+// ProxySetting is the value of the workspace proxy setting.
+// Without any proxy URL, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used instead.
+type ProxySetting struct {
+	HTTPProxy  string `json:"httpProxy"`
+	HTTPSProxy string `json:"httpsProxy"`
+	// NoProxy is the list of the destinations bypassing the proxy, e.g. "gitlab.example.com", ".corp.example.com", "10.0.0.0/8".
+	NoProxy []string `json:"noProxy"`
+}
+
+// SMTPSetting is the value of the SMTP setting.
+// Empty host means no email is sent.
+type SMTPSetting struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// From is the sender address, e.g. "Bytebase <bytebase@example.com>".
+	From string `json:"from"`
+}
+
+// TaskCheckCustomSetting is the value of the custom task check setting.
+// Without any plugin, the custom task check isn't scheduled.
+type TaskCheckCustomSetting struct {
+	PluginList []TaskCheckCustomPlugin `json:"pluginList"`
+}
+
+// TaskCheckCustomPlugin is a custom task check plugin, which receives TaskCheckCustomPayload in JSON
+// and replies TaskCheckCustomResponse in JSON.
+// Exactly one of the URL and the command is set.
+type TaskCheckCustomPlugin struct {
+	// Name is the unique name of the plugin, which prefixes the titles of its results.
+	Name string `json:"name"`
+	// URL is the HTTP handler receiving the payload in the POST body and replying the response in the body with status 200.
+	URL string `json:"url"`
+	// Command is the executable and its arguments run on the Bytebase host, receiving the payload from stdin and writing the response to stdout.
+	// It's only allowed if the server is started with --enable-task-check-command.
+	Command []string `json:"command"`
+	// TimeoutSeconds is the timeout of a single run, 0 means the default 60 seconds.
+	TimeoutSeconds int `json:"timeoutSeconds"`
+}
+
 // Setting is the API message for a setting.
 type Setting struct {
 	ID int `jsonapi:"primary,setting"`
@@ -128,6 +128,8 @@ type SheetFind struct {
 	// Related fields
 	ProjectID  *int
 	DatabaseID *int
+	// WorkspaceID finds the sheets created by the members of the workspace.
+	WorkspaceID *int
 
 	// Domain fields
 	Name       *string
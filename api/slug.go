@@ -21,6 +21,11 @@ func ProjectShortSlug(project *Project) string {
 	return slug.Make(project.Name)
 }
 
+// DatabaseSlug is the slug formatter for databases.
+func DatabaseSlug(database *Database) string {
+	return fmt.Sprintf("%s-%d", slug.Make(database.Name), database.ID)
+}
+
 // EnvSlug is the slug formatter for environments.
 func EnvSlug(env *Environment) string {
 	return slug.Make(env.Name)
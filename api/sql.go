@@ -19,6 +19,12 @@ type ConnectionInfo struct {
 	SslCa            *string `jsonapi:"attr,sslCa"`
 	SslCert          *string `jsonapi:"attr,sslCert"`
 	SslKey           *string `jsonapi:"attr,sslKey"`
+	SSHHost          string  `jsonapi:"attr,sshHost"`
+	SSHPort          string  `jsonapi:"attr,sshPort"`
+	SSHUser          string  `jsonapi:"attr,sshUser"`
+	// SSHPrivateKey is empty for the existing private key of the instance, which is not returned to the client.
+	SSHPrivateKey string `jsonapi:"attr,sshPrivateKey"`
+	SSHHostKey    string `jsonapi:"attr,sshHostKey"`
 }
 
 // SQLSyncSchema is the API message for sync schemas.
@@ -28,17 +34,43 @@ type SQLSyncSchema struct {
 }
 
 // SQLExecute is the API message for execute SQL.
-// For now, we only support readonly / SELECT.
+// The readonly statement is limited to SELECT, SHOW and EXPLAIN, and the write statement requires the elevated write access of the database.
 type SQLExecute struct {
 	InstanceID int `jsonapi:"attr,instanceId"`
 	// For engines such as MySQL, databaseName can be empty.
 	DatabaseName string `jsonapi:"attr,databaseName"`
 	Statement    string `jsonapi:"attr,statement"`
-	// For now, Readonly must be true
+	// Readonly is false for executing the write statement with the elevated write access, which requires databaseName.
 	Readonly bool `jsonapi:"attr,readonly"`
 	// The maximum row count returned, only applicable to SELECT query.
 	// Not enforced if limit <= 0.
 	Limit int `jsonapi:"attr,limit"`
+	// Timeout is the query timeout in seconds. The server default applies if timeout <= 0, and it's capped by the server max.
+	Timeout int `jsonapi:"attr,timeout"`
+}
+
+// SQLExportFormat is the file format of the exported query results.
+type SQLExportFormat string
+
+const (
+	// SQLExportFormatCSV exports the query results as CSV with the column names as the header.
+	SQLExportFormatCSV SQLExportFormat = "CSV"
+	// SQLExportFormatJSON exports the query results as a JSON array of the row objects keyed by the column names.
+	SQLExportFormatJSON SQLExportFormat = "JSON"
+)
+
+// SQLExport is the API message for exporting the results of a readonly / SELECT statement as a file.
+type SQLExport struct {
+	InstanceID int `jsonapi:"attr,instanceId"`
+	// For engines such as MySQL, databaseName can be empty.
+	DatabaseName string          `jsonapi:"attr,databaseName"`
+	Statement    string          `jsonapi:"attr,statement"`
+	Format       SQLExportFormat `jsonapi:"attr,format"`
+	// The maximum row count exported, capped by the export max rows setting and the query limit policy.
+	// Not enforced by the request if limit <= 0.
+	Limit int `jsonapi:"attr,limit"`
+	// Timeout is the query timeout in seconds. The server default applies if timeout <= 0, and it's capped by the server max.
+	Timeout int `jsonapi:"attr,timeout"`
 }
 
 // SQLResultSet is the API message for SQL results.
@@ -49,9 +81,35 @@ type SQLResultSet struct {
 	Error string `jsonapi:"attr,error"`
 	// A list of SQL check advice.
 	AdviceList []advisor.Advice `jsonapi:"attr,adviceList"`
+	// The metadata of the result columns joined from the synced schema, in the same order as the column names.
+	ColumnList []SQLResultColumn `jsonapi:"attr,columnList"`
+}
+
+// SQLResultColumn is the metadata of a column in the SQL results.
+// Database, Table and Comment are empty if the column doesn't match exactly one synced column, e.g. an expression.
+type SQLResultColumn struct {
+	Name     string `json:"name"`
+	Database string `json:"database"`
+	Table    string `json:"table"`
+	Comment  string `json:"comment"`
+}
+
+// SQLFormat is the API message for formatting SQL.
+type SQLFormat struct {
+	Engine    db.Type `jsonapi:"attr,engine"`
+	Statement string  `jsonapi:"attr,statement"`
+}
+
+// SQLFormatResult is the API message for the formatted SQL.
+type SQLFormatResult struct {
+	Statement string `jsonapi:"attr,statement"`
+	// The statement may have syntax errors and there is no proper http status code for it, so we return error in the response body.
+	Error string `jsonapi:"attr,error"`
 }
 
 // SQLService is the service for SQL.
 type SQLService interface {
 	Ping(ctx context.Context, config *ConnectionInfo) (*SQLResultSet, error)
+	// Execute executes the read-only query, e.g. SELECT, SHOW and EXPLAIN, and returns the rows as JSON.
+	Execute(ctx context.Context, exec *SQLExecute) (*SQLResultSet, error)
 }
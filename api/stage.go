@@ -25,6 +25,11 @@ type Stage struct {
 
 	// Domain specific fields
 	Name string `jsonapi:"attr,name"`
+	// Paused holds the pipeline before the stage, no task in the stage is scheduled until it's resumed.
+	Paused bool `jsonapi:"attr,paused"`
+	// SoakSeconds is the time the paused stage waits after the previous stage finishes before it's resumed automatically.
+	// 0 means the paused stage is only resumed manually.
+	SoakSeconds int64 `jsonapi:"attr,soakSeconds"`
 }
 
 // StageCreate is the API message for creating a stage.
@@ -41,6 +46,9 @@ type StageCreate struct {
 
 	// Domain specific fields
 	Name string `jsonapi:"attr,name"`
+	// Paused and SoakSeconds are set by the server, e.g. holding the pipeline after the canary stage.
+	Paused      bool
+	SoakSeconds int64
 }
 
 // StageFind is the API message for finding stages.
@@ -51,6 +59,21 @@ type StageFind struct {
 	PipelineID *int
 }
 
+// StagePatch is the API message for patching a stage.
+type StagePatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Paused  *bool  `jsonapi:"attr,paused"`
+	Comment string `jsonapi:"attr,comment"`
+	// SoakSeconds is set by the server, the soak time is cleared once the stage is resumed, so that pausing it again holds it until resumed manually.
+	SoakSeconds *int64
+}
+
 // StageAllTaskStatusPatch is the API message for patching task status for all tasks in a stage.
 type StageAllTaskStatusPatch struct {
 	ID int
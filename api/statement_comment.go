@@ -0,0 +1,101 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// StatementComment is the API message for a review comment on a line of the task statement in an issue.
+// The unresolved comments block approving the task, like the conversations in a code review.
+type StatementComment struct {
+	ID int `jsonapi:"primary,statementComment"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	IssueID int `jsonapi:"attr,issueId"`
+	TaskID  int `jsonapi:"attr,taskId"`
+
+	// Domain specific fields
+	// StatementHash is the hash of the task statement the comment is created on, see GetStatementHash.
+	StatementHash string `jsonapi:"attr,statementHash"`
+	// Line is the 1-based line in the statement.
+	Line     int    `jsonapi:"attr,line"`
+	Content  string `jsonapi:"attr,content"`
+	Resolved bool   `jsonapi:"attr,resolved"`
+	// Outdated is true if the statement has been changed since the comment was created, so that the line may have moved.
+	// It's not persisted, but derived from the statement hash.
+	Outdated bool `jsonapi:"attr,outdated"`
+}
+
+// StatementCommentCreate is the API message for creating a statement comment.
+type StatementCommentCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	IssueID int
+	TaskID  int `jsonapi:"attr,taskId"`
+
+	// Domain specific fields
+	// StatementHash is assigned from the current statement of the task.
+	StatementHash string
+	Line          int    `jsonapi:"attr,line"`
+	Content       string `jsonapi:"attr,content"`
+}
+
+// StatementCommentFind is the API message for finding statement comments.
+type StatementCommentFind struct {
+	ID *int
+
+	// Related fields
+	IssueID *int
+	TaskID  *int
+
+	// Domain specific fields
+	Resolved *bool
+}
+
+func (find *StatementCommentFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// StatementCommentPatch is the API message for patching a statement comment.
+type StatementCommentPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Content  *string `jsonapi:"attr,content"`
+	Resolved *bool   `jsonapi:"attr,resolved"`
+}
+
+// StatementCommentDelete is the API message for deleting a statement comment.
+type StatementCommentDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// GetStatementHash returns the hex-encoded SHA-256 hash of the statement.
+func GetStatementHash(statement string) string {
+	hash := sha256.Sum256([]byte(statement))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// StatementDBNameVariable is the statement variable resolved to the name of the target database.
+	StatementDBNameVariable = "{{DB_NAME}}"
+	// StatementTenantIDVariable is the statement variable resolved to the tenant label value of the target database.
+	StatementTenantIDVariable = "{{TENANT_ID}}"
+)
+
+// statementSecretVariableRegexp matches the secret statement variable {{secret.NAME}}.
+var statementSecretVariableRegexp = regexp.MustCompile(`\{\{secret\.([A-Za-z_][A-Za-z0-9_]*)\}\}`)
+
+// ResolveStatementVariables replaces the statement variables such as {{DB_NAME}} and {{TENANT_ID}} with the values of the target database.
+// It returns an error if any variable in the statement cannot be resolved for the database, e.g. {{TENANT_ID}} for the database without the tenant label.
+// The text which looks like a variable but isn't a known one is left as is, because it may be a part of the string literal.
+func ResolveStatementVariables(statement string, database *Database) (string, error) {
+	if !strings.Contains(statement, "{{") {
+		return statement, nil
+	}
+
+	var labels []*DatabaseLabel
+	if database.Labels != "" {
+		if err := json.Unmarshal([]byte(database.Labels), &labels); err != nil {
+			return "", errors.Wrapf(err, "failed to unmarshal labels for database %q", database.Name)
+		}
+	}
+	valueMap := map[string]string{
+		StatementDBNameVariable: database.Name,
+	}
+	for _, label := range labels {
+		if label.Key == TenantLabelKey && label.Value != "" {
+			valueMap[StatementTenantIDVariable] = label.Value
+		}
+	}
+
+	var unresolved []string
+	for _, variable := range []string{StatementDBNameVariable, StatementTenantIDVariable} {
+		if !strings.Contains(statement, variable) {
+			continue
+		}
+		value, ok := valueMap[variable]
+		if !ok {
+			unresolved = append(unresolved, variable)
+			continue
+		}
+		statement = strings.ReplaceAll(statement, variable, value)
+	}
+	if len(unresolved) > 0 {
+		return "", errors.Errorf("cannot resolve %s for database %q", strings.Join(unresolved, ", "), database.Name)
+	}
+	return statement, nil
+}
+
+// ResolveStatementSecrets replaces the secret statement variables {{secret.NAME}} with the secret values of the target database.
+// It returns an error if any secret referenced in the statement doesn't exist.
+// The resolved statement contains the secret values, so it must only be executed, never persisted or logged.
+func ResolveStatementSecrets(statement string, secrets map[string]string) (string, error) {
+	if !strings.Contains(statement, "{{secret.") {
+		return statement, nil
+	}
+
+	unresolvedMap := make(map[string]bool)
+	resolved := statementSecretVariableRegexp.ReplaceAllStringFunc(statement, func(variable string) string {
+		name := statementSecretVariableRegexp.FindStringSubmatch(variable)[1]
+		value, ok := secrets[name]
+		if !ok {
+			unresolvedMap[name] = true
+			return variable
+		}
+		return value
+	})
+	if len(unresolvedMap) > 0 {
+		var unresolved []string
+		for name := range unresolvedMap {
+			unresolved = append(unresolved, name)
+		}
+		sort.Strings(unresolved)
+		return "", errors.Errorf("cannot find secret %s", strings.Join(unresolved, ", "))
+	}
+	return resolved, nil
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveStatementVariables(t *testing.T) {
+	tenantDatabase := &Database{
+		Name:   "db_tenant1",
+		Labels: "[{\"key\":\"bb.location\",\"value\":\"us-central1\"},{\"key\":\"bb.tenant\",\"value\":\"tenant1\"}]",
+	}
+	database := &Database{
+		Name: "db",
+	}
+
+	tests := []struct {
+		statement string
+		database  *Database
+		want      string
+		wantErr   bool
+	}{
+		{
+			statement: "CREATE TABLE t(id INT);",
+			database:  database,
+			want:      "CREATE TABLE t(id INT);",
+		},
+		{
+			statement: "INSERT INTO {{DB_NAME}}.config VALUES ('{{TENANT_ID}}', '{{TENANT_ID}}');",
+			database:  tenantDatabase,
+			want:      "INSERT INTO db_tenant1.config VALUES ('tenant1', 'tenant1');",
+		},
+		{
+			statement: "UPDATE t SET template = '{{NAME}}' WHERE db = '{{DB_NAME}}';",
+			database:  database,
+			want:      "UPDATE t SET template = '{{NAME}}' WHERE db = 'db';",
+		},
+		{
+			statement: "INSERT INTO config VALUES ('{{TENANT_ID}}');",
+			database:  database,
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ResolveStatementVariables(test.statement, test.database)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.want, got)
+	}
+}
+
+func TestResolveStatementSecrets(t *testing.T) {
+	secrets := map[string]string{
+		"ENCRYPTION_KEY": "s3cr3t",
+		"api_token":      "token",
+	}
+
+	tests := []struct {
+		statement string
+		want      string
+		wantErr   bool
+	}{
+		{
+			statement: "CREATE TABLE t(id INT);",
+			want:      "CREATE TABLE t(id INT);",
+		},
+		{
+			statement: "SELECT pgp_sym_encrypt(name, '{{secret.ENCRYPTION_KEY}}'), '{{secret.api_token}}', '{{secret.ENCRYPTION_KEY}}' FROM t;",
+			want:      "SELECT pgp_sym_encrypt(name, 's3cr3t'), 'token', 's3cr3t' FROM t;",
+		},
+		{
+			statement: "UPDATE t SET template = '{{secret.}}';",
+			want:      "UPDATE t SET template = '{{secret.}}';",
+		},
+		{
+			statement: "INSERT INTO config VALUES ('{{secret.MISSING}}');",
+			wantErr:   true,
+		},
+	}
+	for _, test := range tests {
+		got, err := ResolveStatementSecrets(test.statement, secrets)
+		if test.wantErr {
+			require.Error(t, err, test.statement)
+			continue
+		}
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.want, got)
+	}
+}
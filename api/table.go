@@ -22,6 +22,7 @@ type Table struct {
 
 	// Domain specific fields
 	Name          string    `jsonapi:"attr,name"`
+	Schema        string    `jsonapi:"attr,schema"`
 	Type          string    `jsonapi:"attr,type"`
 	Engine        string    `jsonapi:"attr,engine"`
 	Collation     string    `jsonapi:"attr,collation"`
@@ -48,6 +49,7 @@ type TableCreate struct {
 
 	// Domain specific fields
 	Name          string
+	Schema        string
 	Type          string
 	Engine        string
 	Collation     string
@@ -67,7 +69,8 @@ type TableFind struct {
 	DatabaseID *int
 
 	// Domain specific fields
-	Name *string
+	Name   *string
+	Schema *string
 }
 
 func (find *TableFind) String() string {
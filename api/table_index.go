@@ -71,6 +71,20 @@ func (find *IndexFind) String() string {
 	return string(str)
 }
 
+// IndexPatch is the API message for patching an index.
+type IndexPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Name    *string `jsonapi:"attr,name"`
+	Visible *bool   `jsonapi:"attr,visible"`
+	Comment *string `jsonapi:"attr,comment"`
+}
+
 // IndexDelete is the API message for deleting an index.
 type IndexDelete struct {
 	ID int
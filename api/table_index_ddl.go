@@ -0,0 +1,188 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// TableIndexDDL is the API message for the statements creating the indexes of a table.
+type TableIndexDDL struct {
+	Statement string `json:"statement"`
+}
+
+// GenerateIndexDDL generates the statement creating the index of the table in the dialect of the engine.
+// Each Index in the IndexList is a key part of an index, the key parts of the same index are ordered by position.
+// The key part is taken as a column if the table has the column with the same name, otherwise as an expression.
+func (table *Table) GenerateIndexDDL(engine db.Type, indexName string) (string, error) {
+	var keyList []*Index
+	for _, index := range table.IndexList {
+		if index.Name == indexName {
+			keyList = append(keyList, index)
+		}
+	}
+	if len(keyList) == 0 {
+		return "", errors.Errorf("index %q not found in table %q", indexName, table.Name)
+	}
+	sort.Slice(keyList, func(i, j int) bool {
+		return keyList[i].Position < keyList[j].Position
+	})
+
+	columnSet := make(map[string]bool)
+	for _, column := range table.ColumnList {
+		columnSet[column.Name] = true
+	}
+	index := keyList[0]
+
+	switch engine {
+	case db.MySQL, db.TiDB:
+		return generateMySQLIndexDDL(table.Name, index, keyList, columnSet), nil
+	case db.Postgres:
+		return generatePostgreSQLIndexDDL(table.Name, index, keyList), nil
+	case db.SQLite:
+		if index.Primary {
+			return "", errors.Errorf("primary key of table %q can only be defined on table creation in SQLite", table.Name)
+		}
+		return generateSQLiteIndexDDL(table.Name, index, keyList, columnSet), nil
+	default:
+		return "", errors.Errorf("generating index DDL is not supported for engine %s", engine)
+	}
+}
+
+// GenerateIndexDDLList generates the statements creating all the indexes of the table, ordered by the index name.
+func (table *Table) GenerateIndexDDLList(engine db.Type) ([]string, error) {
+	var nameList []string
+	nameSet := make(map[string]bool)
+	for _, index := range table.IndexList {
+		if !nameSet[index.Name] {
+			nameSet[index.Name] = true
+			nameList = append(nameList, index.Name)
+		}
+	}
+	sort.Strings(nameList)
+
+	var statementList []string
+	for _, name := range nameList {
+		statement, err := table.GenerateIndexDDL(engine, name)
+		if err != nil {
+			return nil, err
+		}
+		statementList = append(statementList, statement)
+	}
+	return statementList, nil
+}
+
+func generateMySQLIndexDDL(tableName string, index *Index, keyList []*Index, columnSet map[string]bool) string {
+	var keyPartList []string
+	for _, key := range keyList {
+		if columnSet[key.Expression] {
+			keyPartList = append(keyPartList, quoteMySQLIdentifier(key.Expression))
+		} else {
+			// The functional key part must be enclosed within parentheses.
+			keyPartList = append(keyPartList, fmt.Sprintf("(%s)", key.Expression))
+		}
+	}
+	keyParts := strings.Join(keyPartList, ", ")
+
+	if index.Primary {
+		return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", quoteMySQLIdentifier(tableName), keyParts)
+	}
+
+	var buf strings.Builder
+	buf.WriteString("CREATE ")
+	indexType := strings.ToUpper(index.Type)
+	switch {
+	case indexType == "FULLTEXT" || indexType == "SPATIAL":
+		buf.WriteString(indexType + " ")
+	case index.Unique:
+		buf.WriteString("UNIQUE ")
+	}
+	fmt.Fprintf(&buf, "INDEX %s ON %s (%s)", quoteMySQLIdentifier(index.Name), quoteMySQLIdentifier(tableName), keyParts)
+	if indexType == "BTREE" || indexType == "HASH" {
+		fmt.Fprintf(&buf, " USING %s", indexType)
+	}
+	if index.Comment != "" {
+		fmt.Fprintf(&buf, " COMMENT %s", quoteStringLiteral(index.Comment))
+	}
+	if !index.Visible {
+		buf.WriteString(" INVISIBLE")
+	}
+	buf.WriteString(";")
+	return buf.String()
+}
+
+func generatePostgreSQLIndexDDL(tableName string, index *Index, keyList []*Index) string {
+	// The key parts are synced from the index definition, which are already in the SQL form.
+	var keyPartList []string
+	for _, key := range keyList {
+		keyPartList = append(keyPartList, key.Expression)
+	}
+	keyParts := strings.Join(keyPartList, ", ")
+
+	// The table name is in the form of "schema.table", and the index lives in the schema of the table.
+	schemaName := ""
+	if i := strings.Index(tableName, "."); i >= 0 {
+		schemaName, tableName = tableName[:i], tableName[i+1:]
+	}
+	qualifiedTableName := quotePostgreSQLIdentifier(tableName)
+	qualifiedIndexName := quotePostgreSQLIdentifier(index.Name)
+	if schemaName != "" {
+		qualifiedTableName = fmt.Sprintf("%s.%s", quotePostgreSQLIdentifier(schemaName), qualifiedTableName)
+		qualifiedIndexName = fmt.Sprintf("%s.%s", quotePostgreSQLIdentifier(schemaName), qualifiedIndexName)
+	}
+
+	var buf strings.Builder
+	if index.Primary {
+		fmt.Fprintf(&buf, "ALTER TABLE %s ADD CONSTRAINT %s PRIMARY KEY (%s);", qualifiedTableName, quotePostgreSQLIdentifier(index.Name), keyParts)
+	} else {
+		buf.WriteString("CREATE ")
+		if index.Unique {
+			buf.WriteString("UNIQUE ")
+		}
+		fmt.Fprintf(&buf, "INDEX %s ON %s", quotePostgreSQLIdentifier(index.Name), qualifiedTableName)
+		if index.Type != "" {
+			fmt.Fprintf(&buf, " USING %s", strings.ToLower(index.Type))
+		}
+		fmt.Fprintf(&buf, " (%s);", keyParts)
+	}
+	if index.Comment != "" {
+		fmt.Fprintf(&buf, "\nCOMMENT ON INDEX %s IS %s;", qualifiedIndexName, quoteStringLiteral(index.Comment))
+	}
+	return buf.String()
+}
+
+func generateSQLiteIndexDDL(tableName string, index *Index, keyList []*Index, columnSet map[string]bool) string {
+	var keyPartList []string
+	for _, key := range keyList {
+		if columnSet[key.Expression] {
+			keyPartList = append(keyPartList, quotePostgreSQLIdentifier(key.Expression))
+		} else {
+			keyPartList = append(keyPartList, key.Expression)
+		}
+	}
+
+	var buf strings.Builder
+	buf.WriteString("CREATE ")
+	if index.Unique {
+		buf.WriteString("UNIQUE ")
+	}
+	fmt.Fprintf(&buf, "INDEX %s ON %s (%s);", quotePostgreSQLIdentifier(index.Name), quotePostgreSQLIdentifier(tableName), strings.Join(keyPartList, ", "))
+	return buf.String()
+}
+
+func quoteMySQLIdentifier(identifier string) string {
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(identifier, "`", "``"))
+}
+
+// quotePostgreSQLIdentifier quotes the identifier in double quotes, which is the standard SQL also used by SQLite.
+func quotePostgreSQLIdentifier(identifier string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(identifier, `"`, `""`))
+}
+
+func quoteStringLiteral(s string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGenerateIndexDDL(t *testing.T) {
+	mysqlTable := &Table{
+		Name:       "book",
+		ColumnList: []*Column{{Name: "id"}, {Name: "author_id"}, {Name: "title"}},
+		IndexList: []*Index{
+			{Name: "PRIMARY", Expression: "id", Position: 1, Type: "BTREE", Unique: true, Primary: true, Visible: true},
+			{Name: "idx_author_title", Expression: "title", Position: 2, Type: "BTREE", Visible: true},
+			{Name: "idx_author_title", Expression: "author_id", Position: 1, Type: "BTREE", Visible: true, Comment: "author's books"},
+			{Name: "uk_lower_title", Expression: "lower(`title`)", Position: 1, Type: "BTREE", Unique: true, Visible: false},
+			{Name: "ft_title", Expression: "title", Position: 1, Type: "FULLTEXT", Visible: true},
+		},
+	}
+	pgTable := &Table{
+		Name:       "public.book",
+		ColumnList: []*Column{{Name: "id"}, {Name: "title"}},
+		IndexList: []*Index{
+			{Name: "book_pkey", Expression: "id", Position: 1, Type: "btree", Unique: true, Primary: true},
+			{Name: "idx_title", Expression: "lower(title)", Position: 1, Type: "btree", Comment: "search"},
+		},
+	}
+
+	tests := []struct {
+		table     *Table
+		engine    db.Type
+		indexName string
+		want      string
+		wantErr   bool
+	}{
+		{
+			table:     mysqlTable,
+			engine:    db.MySQL,
+			indexName: "PRIMARY",
+			want:      "ALTER TABLE `book` ADD PRIMARY KEY (`id`);",
+		},
+		{
+			table:     mysqlTable,
+			engine:    db.MySQL,
+			indexName: "idx_author_title",
+			want:      "CREATE INDEX `idx_author_title` ON `book` (`author_id`, `title`) USING BTREE COMMENT 'author''s books';",
+		},
+		{
+			table:     mysqlTable,
+			engine:    db.TiDB,
+			indexName: "uk_lower_title",
+			want:      "CREATE UNIQUE INDEX `uk_lower_title` ON `book` ((lower(`title`))) USING BTREE INVISIBLE;",
+		},
+		{
+			table:     mysqlTable,
+			engine:    db.MySQL,
+			indexName: "ft_title",
+			want:      "CREATE FULLTEXT INDEX `ft_title` ON `book` (`title`);",
+		},
+		{
+			table:     pgTable,
+			engine:    db.Postgres,
+			indexName: "book_pkey",
+			want:      `ALTER TABLE "public"."book" ADD CONSTRAINT "book_pkey" PRIMARY KEY (id);`,
+		},
+		{
+			table:     pgTable,
+			engine:    db.Postgres,
+			indexName: "idx_title",
+			want:      "CREATE INDEX \"idx_title\" ON \"public\".\"book\" USING btree (lower(title));\nCOMMENT ON INDEX \"public\".\"idx_title\" IS 'search';",
+		},
+		{
+			table:     mysqlTable,
+			engine:    db.SQLite,
+			indexName: "idx_author_title",
+			want:      `CREATE INDEX "idx_author_title" ON "book" ("author_id", "title");`,
+		},
+		{
+			table:     mysqlTable,
+			engine:    db.SQLite,
+			indexName: "PRIMARY",
+			wantErr:   true,
+		},
+		{
+			table:     mysqlTable,
+			engine:    db.Snowflake,
+			indexName: "PRIMARY",
+			wantErr:   true,
+		},
+		{
+			table:     mysqlTable,
+			engine:    db.MySQL,
+			indexName: "not_exist",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := test.table.GenerateIndexDDL(test.engine, test.indexName)
+		if test.wantErr {
+			require.Error(t, err, test.indexName)
+			continue
+		}
+		require.NoError(t, err, test.indexName)
+		require.Equal(t, test.want, got)
+	}
+}
+
+func TestGenerateIndexDDLList(t *testing.T) {
+	table := &Table{
+		Name:       "book",
+		ColumnList: []*Column{{Name: "id"}, {Name: "title"}},
+		IndexList: []*Index{
+			{Name: "uk_title", Expression: "title", Position: 1, Unique: true, Visible: true},
+			{Name: "idx_id_title", Expression: "id", Position: 1, Visible: true},
+			{Name: "idx_id_title", Expression: "title", Position: 2, Visible: true},
+		},
+	}
+	got, err := table.GenerateIndexDDLList(db.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"CREATE INDEX `idx_id_title` ON `book` (`id`, `title`);",
+		"CREATE UNIQUE INDEX `uk_title` ON `book` (`title`);",
+	}, got)
+}
@@ -32,6 +32,9 @@ const (
 	TaskFailed TaskStatus = "FAILED"
 	// TaskCanceled is the task status for CANCELED.
 	TaskCanceled TaskStatus = "CANCELED"
+	// TaskPaused is the task status for PAUSED.
+	// The task is paused at a statement boundary on request, and can be resumed by running it again.
+	TaskPaused TaskStatus = "PAUSED"
 )
 
 // TaskType is the type of a task.
@@ -109,6 +112,16 @@ type TaskDatabaseSchemaUpdatePayload struct {
 	Statement     string           `json:"statement,omitempty"`
 	SchemaVersion string           `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent   `json:"pushEvent,omitempty"`
+	// RollbackStatement is the statement reverting the executed statement, empty if it cannot be generated.
+	RollbackStatement string `json:"rollbackStatement,omitempty"`
+	// ExecutedStatementCount is the number of the leading statements executed before the task was paused.
+	// They are skipped when the task is resumed.
+	ExecutedStatementCount int `json:"executedStatementCount,omitempty"`
+	// TimeoutSeconds is the execution timeout of the task, 0 means no timeout.
+	// The running statement is canceled on the database server once the timeout is reached.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+	// SchemaName is the Postgres schema set as the search_path to execute the statement, empty for the default search_path.
+	SchemaName string `json:"schemaName,omitempty"`
 }
 
 // TaskDatabaseSchemaUpdateGhostSyncPayload is the task payload for gh-ost syncing ghost table.
@@ -131,6 +144,11 @@ type TaskDatabaseDataUpdatePayload struct {
 	Statement     string         `json:"statement,omitempty"`
 	SchemaVersion string         `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// ExecutedStatementCount is the number of the leading statements executed before the task was paused.
+	// They are skipped when the task is resumed.
+	ExecutedStatementCount int `json:"executedStatementCount,omitempty"`
+	// SchemaName is the Postgres schema set as the search_path to execute the statement, empty for the default search_path.
+	SchemaName string `json:"schemaName,omitempty"`
 }
 
 // TaskDatabaseBackupPayload is the task payload for database backup.
@@ -181,6 +199,9 @@ type Task struct {
 	BlockedBy []string `jsonapi:"attr,blockedBy"`
 	// Progress is loaded from the task scheduler in memory, NOT from the database
 	Progress Progress `jsonapi:"attr,progress"`
+	// QueuePosition is the 1-based position of the task waiting for a free slot due to the task concurrency limits.
+	// It's 0 if the task isn't queued. Like Progress, it's loaded from the task scheduler in memory.
+	QueuePosition int `jsonapi:"attr,queuePosition"`
 }
 
 // Progress is a generalized struct which can track the progress of a task.
@@ -235,6 +256,7 @@ type TaskFind struct {
 	// Related fields
 	PipelineID *int
 	StageID    *int
+	InstanceID *int
 
 	// Domain specific fields
 	StatusList *[]TaskStatus
@@ -69,6 +69,8 @@ const (
 	TaskCheckDatabaseStatementAdvise TaskCheckType = "bb.task-check.database.statement.advise"
 	// TaskCheckDatabaseStatementType is the task check type for statement type.
 	TaskCheckDatabaseStatementType TaskCheckType = "bb.task-check.database.statement.type"
+	// TaskCheckDatabaseStatementDryRun is the task check type for dry-running the statement against the database.
+	TaskCheckDatabaseStatementDryRun TaskCheckType = "bb.task-check.database.statement.dry-run"
 	// TaskCheckDatabaseConnect is the task check type for database connection.
 	TaskCheckDatabaseConnect TaskCheckType = "bb.task-check.database.connect"
 	// TaskCheckInstanceMigrationSchema is the task check type for migrating schemas.
@@ -77,6 +79,8 @@ const (
 	TaskCheckGhostSync TaskCheckType = "bb.task-check.database.ghost.sync"
 	// TaskCheckGeneralEarliestAllowedTime is the task check type for earliest allowed time.
 	TaskCheckGeneralEarliestAllowedTime TaskCheckType = "bb.task-check.general.earliest-allowed-time"
+	// TaskCheckCustom is the task check type for the custom task check plugins configured by the workspace.
+	TaskCheckCustom TaskCheckType = "bb.task-check.custom"
 )
 
 // TaskCheckEarliestAllowedTimePayload is the task check payload for earliest allowed time.
@@ -105,6 +109,45 @@ type TaskCheckDatabaseStatementTypePayload struct {
 	Collation string `json:"collation,omitempty"`
 }
 
+// TaskCheckDatabaseStatementDryRunPayload is the task check payload for statement dry run.
+type TaskCheckDatabaseStatementDryRunPayload struct {
+	Statement string  `json:"statement,omitempty"`
+	DbType    db.Type `json:"dbType,omitempty"`
+
+	// MySQL special fields.
+	Charset   string `json:"charset,omitempty"`
+	Collation string `json:"collation,omitempty"`
+}
+
+// TaskCheckCustomPayload is the task check payload for the custom task check plugins.
+// It's sent to every plugin as is in JSON, the statement is the one with the variables resolved but not the secrets.
+type TaskCheckCustomPayload struct {
+	TaskID          int      `json:"taskId"`
+	TaskName        string   `json:"taskName"`
+	TaskType        TaskType `json:"taskType"`
+	PipelineID      int      `json:"pipelineId"`
+	EnvironmentName string   `json:"environmentName"`
+	InstanceName    string   `json:"instanceName"`
+	DatabaseName    string   `json:"databaseName"`
+	DbType          db.Type  `json:"dbType"`
+	Statement       string   `json:"statement"`
+}
+
+// TaskCheckCustomResponse is the JSON output of a custom task check plugin.
+// The empty result list means the check passed.
+type TaskCheckCustomResponse struct {
+	ResultList []TaskCheckCustomResult `json:"resultList"`
+}
+
+// TaskCheckCustomResult is a result reported by a custom task check plugin.
+type TaskCheckCustomResult struct {
+	Status  TaskCheckStatus `json:"status"`
+	Code    int             `json:"code"`
+	Title   string          `json:"title"`
+	Content string          `json:"content"`
+	Line    int             `json:"line"`
+}
+
 // Namespace is the namespace for task check result.
 type Namespace string
 
@@ -113,6 +156,8 @@ const (
 	AdvisorNamespace Namespace = "bb.advisor"
 	// BBNamespace is task check result namespace for bytebase.
 	BBNamespace Namespace = "bb.core"
+	// CustomNamespace is task check result namespace for the custom task check plugins.
+	CustomNamespace Namespace = "bb.custom"
 )
 
 // TaskCheckResult is the result of task checks.
@@ -122,6 +167,19 @@ type TaskCheckResult struct {
 	Status    TaskCheckStatus `json:"status,omitempty"`
 	Title     string          `json:"title,omitempty"`
 	Content   string          `json:"content,omitempty"`
+	// Line is the line of the statement the result refers to, 0 if not applicable.
+	Line int `json:"line,omitempty"`
+
+	// Suppression is set if the result has been suppressed.
+	Suppression *TaskCheckResultSuppression `json:"suppression,omitempty"`
+}
+
+// TaskCheckResultSuppression is the suppression of a task check result signed off by an approver.
+// The suppressed result no longer blocks the task.
+type TaskCheckResultSuppression struct {
+	Justification string `json:"justification,omitempty"`
+	ApproverID    int    `json:"approverId,omitempty"`
+	SuppressedTs  int64  `json:"suppressedTs,omitempty"`
 }
 
 // TaskCheckRunResultPayload is the result payload of a task check run.
@@ -204,12 +262,28 @@ type TaskCheckRunStatusPatch struct {
 	// Standard fields
 	UpdaterID int
 
+	// Related fields
+	// TaskID patches all the RUNNING task check runs of the task in batch, used by BatchPatchTaskCheckRunStatus.
+	TaskID *int
+
 	// Domain specific fields
 	Status TaskCheckRunStatus
 	Code   common.Code
 	Result string
 }
 
+// TaskCheckResultSuppress is the API message for suppressing a task check result.
+type TaskCheckResultSuppress struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	ApproverID int
+
+	// Domain specific fields
+	// Index is the index of the result in the result list of the task check run.
+	Index         int    `jsonapi:"attr,index"`
+	Justification string `jsonapi:"attr,justification"`
+}
+
 // IsSyntaxCheckSupported checks the engine type if syntax check supports it.
 func IsSyntaxCheckSupported(dbType db.Type, _ common.ReleaseMode) bool {
 	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB {
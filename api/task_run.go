@@ -20,6 +20,8 @@ const (
 	TaskRunFailed TaskRunStatus = "FAILED"
 	// TaskRunCanceled is the task run status for CANCELED.
 	TaskRunCanceled TaskRunStatus = "CANCELED"
+	// TaskRunPaused is the task run status for PAUSED.
+	TaskRunPaused TaskRunStatus = "PAUSED"
 )
 
 // TaskRunResultPayload is the result payload for a task run.
@@ -27,6 +29,50 @@ type TaskRunResultPayload struct {
 	Detail      string `json:"detail,omitempty"`
 	MigrationID int64  `json:"migrationId,omitempty"`
 	Version     string `json:"version,omitempty"`
+	// ExecutionLog is the execution log persisted after the task run completes.
+	ExecutionLog []*TaskRunLog `json:"executionLog,omitempty"`
+	// Progress is the last progress of the task run, so that it's kept after the task scheduler drops the in-memory one.
+	Progress *Progress `json:"progress,omitempty"`
+	// FailedStatementIndex is the 1-based position of the statement failing the task run, 0 if no statement failed.
+	// It's only set if the statements are executed one by one, i.e. the DATA migrations and the pausable schema migrations.
+	// The other migrations are executed as a whole, e.g. the PostgreSQL schema migrations in a single transaction, and leave it unset.
+	FailedStatementIndex int `json:"failedStatementIndex,omitempty"`
+	// ExecutionDurationNs is the total execution time of the migration, the duration of each statement is in the execution log
+	// if the statements are executed one by one.
+	ExecutionDurationNs int64 `json:"executionDurationNs,omitempty"`
+	// DDLJobIDList is the IDs of the TiDB DDL jobs created by the migration, which are listed by ADMIN SHOW DDL JOBS.
+	DDLJobIDList []int64 `json:"ddlJobIdList,omitempty"`
+	// DDLAlgorithmList is the algorithms used by the MySQL ALTER TABLE statements of the migration, in the execution order.
+	DDLAlgorithmList []*TaskRunDDLAlgorithm `json:"ddlAlgorithmList,omitempty"`
+}
+
+// TaskRunDDLAlgorithm is the algorithm used by a MySQL ALTER TABLE statement, e.g. INSTANT, INPLACE or COPY.
+type TaskRunDDLAlgorithm struct {
+	Table     string `json:"table"`
+	Algorithm string `json:"algorithm"`
+}
+
+// TaskRunLogLevel is the level of a task run log.
+type TaskRunLogLevel string
+
+const (
+	// TaskRunLogInfo is the task run log level for INFO.
+	TaskRunLogInfo TaskRunLogLevel = "INFO"
+	// TaskRunLogError is the task run log level for ERROR.
+	TaskRunLogError TaskRunLogLevel = "ERROR"
+)
+
+// TaskRunLog is a single line of the task run execution log.
+// It's streamed to the client while the task is running.
+type TaskRunLog struct {
+	CreatedTs int64           `json:"createdTs"`
+	Level     TaskRunLogLevel `json:"level"`
+	Message   string          `json:"message"`
+	// StatementIndex is the 1-based position of the executed statement, 0 if the log is not about a statement.
+	StatementIndex int   `json:"statementIndex,omitempty"`
+	StatementCount int   `json:"statementCount,omitempty"`
+	DurationNs     int64 `json:"durationNs,omitempty"`
+	RowsAffected   int64 `json:"rowsAffected,omitempty"`
 }
 
 // TaskRun is the API message for a task run.
@@ -52,6 +98,10 @@ type TaskRun struct {
 	Comment string        `jsonapi:"attr,comment"`
 	Result  string        `jsonapi:"attr,result"`
 	Payload string        `jsonapi:"attr,payload"`
+	// Progress is the JSON encoded TaskRunProgress.
+	Progress string `jsonapi:"attr,progress"`
+	// HeartbeatTs is when the executor last reported the running task run alive, 0 means no executor is running it.
+	HeartbeatTs int64 `jsonapi:"attr,heartbeatTs"`
 }
 
 // TaskRunCreate is the API message for creating a task run.
@@ -105,3 +155,52 @@ type TaskRunStatusPatch struct {
 	Comment *string
 	Result  *string
 }
+
+// TaskRunProgress is the progress reported by the executor of a running task run periodically.
+// It's persisted in the task run, so that it survives the server restart and is kept after the task run completes.
+type TaskRunProgress struct {
+	// ID is the ID of the task run.
+	ID int `jsonapi:"primary,taskRunProgress" json:"-"`
+
+	// Domain specific fields
+	// RowsCopied is the rows copied so far, e.g. by gh-ost, or the rows affected by the executed statements.
+	RowsCopied int64 `jsonapi:"attr,rowsCopied" json:"rowsCopied,omitempty"`
+	// RowsTotal is the estimated total rows to copy, 0 if unknown.
+	RowsTotal int64 `jsonapi:"attr,rowsTotal" json:"rowsTotal,omitempty"`
+	// CurrentStatement is the statement most recently reported by the executor.
+	CurrentStatement string `jsonapi:"attr,currentStatement" json:"currentStatement,omitempty"`
+	// StatementIndex is the 1-based position of the current statement, 0 if the executor doesn't run statements one by one.
+	StatementIndex int `jsonapi:"attr,statementIndex" json:"statementIndex,omitempty"`
+	StatementCount int `jsonapi:"attr,statementCount" json:"statementCount,omitempty"`
+	// EtaSeconds is the estimated remaining time in seconds, 0 if unknown.
+	EtaSeconds int64 `jsonapi:"attr,etaSeconds" json:"etaSeconds,omitempty"`
+	// UpdatedTs is when the progress is reported most recently.
+	UpdatedTs int64 `jsonapi:"attr,updatedTs" json:"updatedTs,omitempty"`
+}
+
+// TaskRunProgressPatch is the API message for patching the progress of the running task run of a task.
+// Only the non-nil fields are updated, the others are kept as previously reported.
+type TaskRunProgressPatch struct {
+	// Related fields
+	TaskID int `json:"-"`
+
+	// Domain specific fields
+	RowsCopied       *int64  `json:"rowsCopied,omitempty"`
+	RowsTotal        *int64  `json:"rowsTotal,omitempty"`
+	CurrentStatement *string `json:"currentStatement,omitempty"`
+	StatementIndex   *int    `json:"statementIndex,omitempty"`
+	StatementCount   *int    `json:"statementCount,omitempty"`
+	EtaSeconds       *int64  `json:"etaSeconds,omitempty"`
+	// UpdatedTs is assigned when the progress is patched.
+	UpdatedTs int64 `json:"updatedTs"`
+}
+
+// TaskRunHeartbeatPatch is the API message for patching the heartbeat of the running task run of a task.
+type TaskRunHeartbeatPatch struct {
+	// Related fields
+	TaskID int
+
+	// Domain specific fields
+	// HeartbeatTs is 0 if the executor stops running the task run.
+	HeartbeatTs int64
+}
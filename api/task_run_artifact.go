@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// TaskRunArtifactType is the type of a task run artifact.
+type TaskRunArtifactType string
+
+const (
+	// TaskRunArtifactGhostLog is the artifact type for the gh-ost migration log.
+	TaskRunArtifactGhostLog TaskRunArtifactType = "bb.task-run-artifact.ghost-log"
+	// TaskRunArtifactDumpFile is the artifact type for the database dump file.
+	TaskRunArtifactDumpFile TaskRunArtifactType = "bb.task-run-artifact.dump-file"
+	// TaskRunArtifactVerificationOutput is the artifact type for the output of the verification query.
+	TaskRunArtifactVerificationOutput TaskRunArtifactType = "bb.task-run-artifact.verification-output"
+)
+
+// TaskRunArtifact is the API message for a task run artifact.
+// The artifact is a file attached to the task run by the executor, and the content is stored in the blob storage.
+type TaskRunArtifact struct {
+	ID int `jsonapi:"primary,taskRunArtifact"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	TaskRunID int `jsonapi:"attr,taskRunId"`
+	TaskID    int `jsonapi:"attr,taskId"`
+
+	// Domain specific fields
+	Type TaskRunArtifactType `jsonapi:"attr,type"`
+	// Name is the file name used when downloading the artifact.
+	Name           string               `jsonapi:"attr,name"`
+	Size           int64                `jsonapi:"attr,size"`
+	StorageBackend BackupStorageBackend `jsonapi:"attr,storageBackend"`
+	// Path is the path of the blob, relative to the data directory for the local storage, or the object key for the S3 storage.
+	Path string
+	// Reference is true if the blob is owned by another resource, e.g. the dump file of a backup.
+	// The blob of a reference artifact is left as is when the artifact expires.
+	Reference bool `jsonapi:"attr,reference"`
+	// ExpireTs is the UNIX timestamp in seconds when the artifact is purged.
+	ExpireTs int64 `jsonapi:"attr,expireTs"`
+}
+
+// TaskRunArtifactCreate is the API message for creating a task run artifact.
+type TaskRunArtifactCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	TaskRunID int
+	TaskID    int
+
+	// Domain specific fields
+	Type           TaskRunArtifactType
+	Name           string
+	Size           int64
+	StorageBackend BackupStorageBackend
+	Path           string
+	Reference      bool
+	ExpireTs       int64
+}
+
+// TaskRunArtifactFind is the API message for finding task run artifacts.
+type TaskRunArtifactFind struct {
+	ID *int
+
+	// Related fields
+	TaskRunID *int
+	TaskID    *int
+
+	// Domain specific fields
+	// Find the artifacts which expire no later than this UNIX timestamp in seconds.
+	ExpireTsBefore *int64
+}
+
+func (find *TaskRunArtifactFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// TaskRunArtifactDelete is the API message for deleting a task run artifact.
+type TaskRunArtifactDelete struct {
+	ID int
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// UsageMetric is the API message for a workspace usage metric persisted locally by the metric reporter.
+type UsageMetric struct {
+	ID int `json:"-"`
+
+	// Standard fields
+	// CreatedTs is the time of the collection round, the metrics collected in the same round share it.
+	CreatedTs int64 `json:"createdTs"`
+
+	// Domain specific fields
+	Name   string            `json:"name"`
+	Value  int               `json:"value"`
+	Labels map[string]string `json:"labels"`
+}
+
+// UsageMetricCreate is the API message for creating a workspace usage metric.
+type UsageMetricCreate struct {
+	// Standard fields
+	CreatedTs int64
+
+	// Domain specific fields
+	Name   string
+	Value  int
+	Labels map[string]string
+}
+
+// UsageMetricFind is the API message for finding workspace usage metrics.
+type UsageMetricFind struct {
+	// Standard fields
+	CreatedTs *int64
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *UsageMetricFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// UsageCount is the count of a key in the workspace usage dashboard, e.g. the instance count of an engine.
+type UsageCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// WorkspaceUsage is the API message for the workspace usage dashboard.
+// It's built from the usage metrics of the latest collection round.
+type WorkspaceUsage struct {
+	// CollectedTs is the time of the latest collection round, 0 if the usage metrics have never been collected.
+	CollectedTs int64 `jsonapi:"attr,collectedTs"`
+	// OptOut is true if the workspace opts out of sending the usage metrics outside the workspace.
+	OptOut        bool `jsonapi:"attr,optOut"`
+	InstanceCount int  `jsonapi:"attr,instanceCount"`
+	// EngineMix is the count of the active instances grouped by the engine.
+	EngineMix []*UsageCount `jsonapi:"attr,engineMix"`
+	// WeeklyIssueCountList is the count of the issues created in each week, keyed by the first day of the week.
+	WeeklyIssueCountList []*UsageCount  `jsonapi:"attr,weeklyIssueCountList"`
+	MetricList           []*UsageMetric `jsonapi:"attr,metricList"`
+}
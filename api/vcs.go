@@ -18,6 +18,9 @@ type VCS struct {
 	Updater   *Principal `jsonapi:"relation,updater"`
 	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
 
+	// Related fields
+	WorkspaceID int `jsonapi:"attr,workspaceId"`
+
 	// Domain specific fields
 	Name          string   `jsonapi:"attr,name"`
 	Type          vcs.Type `jsonapi:"attr,type"`
@@ -34,6 +37,10 @@ type VCSCreate struct {
 	// Value is assigned from the jwt subject field passed by the client.
 	CreatorID int
 
+	// Related fields
+	// WorkspaceID is assigned from the workspace of the creator.
+	WorkspaceID int
+
 	// Domain specific fields
 	Name        string   `jsonapi:"attr,name"`
 	Type        vcs.Type `jsonapi:"attr,type"`
@@ -47,6 +54,9 @@ type VCSCreate struct {
 // VCSFind is the API message for finding VCSs.
 type VCSFind struct {
 	ID *int
+
+	// Related fields
+	WorkspaceID *int
 }
 
 func (find *VCSFind) String() string {
@@ -22,6 +22,7 @@ type View struct {
 
 	// Domain specific fields
 	Name       string `jsonapi:"attr,name"`
+	Schema     string `jsonapi:"attr,schema"`
 	Definition string `jsonapi:"attr,definition"`
 	Comment    string `jsonapi:"attr,comment"`
 }
@@ -39,6 +40,7 @@ type ViewCreate struct {
 
 	// Domain specific fields
 	Name       string
+	Schema     string
 	Definition string
 	Comment    string
 }
@@ -51,7 +53,8 @@ type ViewFind struct {
 	DatabaseID *int
 
 	// Domain specific fields
-	Name *string
+	Name   *string
+	Schema *string
 }
 
 func (find *ViewFind) String() string {
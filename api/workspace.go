@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// DefaultWorkspaceID is the ID for the default workspace.
+// The existing resources are migrated to it, and its owners manage the other workspaces in the deployment.
+const DefaultWorkspaceID = 1
+
+// Workspace is the API message for a workspace.
+// A deployment hosts isolated workspaces with separate members, environments, instances and projects.
+type Workspace struct {
+	ID int `jsonapi:"primary,workspace"`
+
+	// Standard fields
+	RowStatus RowStatus `jsonapi:"attr,rowStatus"`
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Domain specific fields
+	Name string `jsonapi:"attr,name"`
+}
+
+// WorkspaceCreate is the API message for creating a workspace.
+type WorkspaceCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	Name string `jsonapi:"attr,name"`
+	// The owner of the workspace is signed up along with the workspace,
+	// since a principal is the member of exactly one workspace.
+	OwnerName     string `jsonapi:"attr,ownerName"`
+	OwnerEmail    string `jsonapi:"attr,ownerEmail"`
+	OwnerPassword string `jsonapi:"attr,ownerPassword"`
+}
+
+// WorkspaceFind is the API message for finding workspaces.
+type WorkspaceFind struct {
+	ID *int
+
+	// Standard fields
+	RowStatus *RowStatus
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *WorkspaceFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// WorkspacePatch is the API message for patching a workspace.
+type WorkspacePatch struct {
+	ID int `jsonapi:"primary,workspacePatch"`
+
+	// Standard fields
+	RowStatus *string `jsonapi:"attr,rowStatus"`
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Name *string `jsonapi:"attr,name"`
+}
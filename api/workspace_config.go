@@ -0,0 +1,44 @@
+package api
+
+// WorkspaceConfig is the configuration of the workspace as code, which is exported and applied as a YAML bundle.
+// The environments are matched by name and the projects by key, so that the bundle can be promoted between deployments.
+// Applying the bundle never deletes the environments or the projects missing from it.
+type WorkspaceConfig struct {
+	// EnvironmentList is ordered as the environments in the workspace.
+	EnvironmentList []*EnvironmentConfig `yaml:"environments"`
+	ProjectList     []*ProjectConfig     `yaml:"projects"`
+}
+
+// EnvironmentConfig is the configuration of an environment.
+type EnvironmentConfig struct {
+	Name string `yaml:"name"`
+	// PolicyList includes the SQL review policy, the policies missing from the list are left unchanged.
+	PolicyList []*PolicyConfig `yaml:"policies,omitempty"`
+}
+
+// PolicyConfig is the configuration of an environment policy.
+type PolicyConfig struct {
+	Type PolicyType `yaml:"type"`
+	// Payload is the policy payload as a mapping instead of the JSON string, so that it's readable in the bundle.
+	Payload map[string]interface{} `yaml:"payload"`
+}
+
+// ProjectConfig is the configuration of a project.
+// The project members aren't included, because the principals differ between deployments.
+type ProjectConfig struct {
+	Key                 string                     `yaml:"key"`
+	Name                string                     `yaml:"name"`
+	TenantMode          ProjectTenantMode          `yaml:"tenantMode"`
+	DBNameTemplate      string                     `yaml:"dbNameTemplate,omitempty"`
+	SchemaMigrationType ProjectSchemaMigrationType `yaml:"schemaMigrationType"`
+	// SQLReviewOverride is the payload of the project SQL review override as a mapping, nil if the project has no override.
+	SQLReviewOverride map[string]interface{} `yaml:"sqlReviewOverride,omitempty"`
+}
+
+// WorkspaceConfigApplyResult is the API message for the result of applying the workspace configuration.
+type WorkspaceConfigApplyResult struct {
+	// ChangeList describes the changes, which is empty if the workspace already matches the configuration.
+	ChangeList []string `json:"changeList"`
+	// ValidateOnly is true if the changes are only validated without being applied.
+	ValidateOnly bool `json:"validateOnly"`
+}
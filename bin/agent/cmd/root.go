@@ -0,0 +1,85 @@
+// Package cmd provides the cobra CLI implementation for Bytebase agent.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/agent"
+)
+
+// -----------------------------------Command Line Config BEGIN------------------------------------.
+var (
+	flags struct {
+		// server is the external URL of Bytebase the agent connects to.
+		server string
+		// token is the agent token generated for the instance.
+		token string
+		// diskPath is the path on the disk of the database data, reported to Bytebase for the disk usage.
+		diskPath string
+		debug    bool
+	}
+	rootCmd = &cobra.Command{
+		Use:   "agent",
+		Short: "Bytebase agent relays the database connections of Bytebase inside the private network",
+		Run: func(_ *cobra.Command, _ []string) {
+			start()
+		},
+	}
+)
+
+// Execute executes the root command.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&flags.server, "server", "", "the external URL of Bytebase, must start with http:// or https://.")
+	rootCmd.PersistentFlags().StringVar(&flags.token, "token", "", "the agent token generated on the instance page of Bytebase.")
+	rootCmd.PersistentFlags().StringVar(&flags.diskPath, "disk-path", "", "the path on the disk of the database data, e.g. /var/lib/mysql. Set it to report the disk usage to Bytebase when the agent runs on the database host.")
+	rootCmd.PersistentFlags().BoolVar(&flags.debug, "debug", false, "whether to enable debug level logging")
+}
+
+// -----------------------------------Command Line Config END--------------------------------------
+
+// -----------------------------------Main Entry Point---------------------------------------------
+
+func start() {
+	if flags.debug {
+		log.SetLevel(zap.DebugLevel)
+	}
+	defer log.Sync()
+
+	// check flags
+	if !common.HasPrefixes(flags.server, "http://", "https://") {
+		log.Error(fmt.Sprintf("--server %s must start with http:// or https://", flags.server))
+		return
+	}
+
+	client, err := agent.NewClient(flags.server, flags.token, flags.diskPath)
+	if err != nil {
+		log.Error("Cannot new agent client", zap.Error(err))
+		return
+	}
+
+	// Setup signal handlers.
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-c
+		log.Info(fmt.Sprintf("%s received.", sig.String()))
+		cancel()
+	}()
+
+	log.Info("Bytebase agent started", zap.String("server", flags.server))
+	client.Run(ctx)
+}
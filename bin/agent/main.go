@@ -0,0 +1,14 @@
+// Package main is the main binary for Bytebase agent.
+package main
+
+import (
+	"os"
+
+	"github.com/bytebase/bytebase/bin/agent/cmd"
+)
+
+func main() {
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
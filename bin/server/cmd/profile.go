@@ -18,31 +18,30 @@ func getBaseProfile() server.Profile {
 		}
 		demoDataDir = fmt.Sprintf("demo/%s", demoName)
 	}
-	backupStorageBackend := api.BackupStorageBackendLocal
-	if flags.backupBucket != "" {
-		backupStorageBackend = api.BackupStorageBackendS3
-	}
 	// Using flags.port + 1 as our datastore port
 	datastorePort := flags.port + 1
 
 	return server.Profile{
-		BackendHost:          flags.host,
-		BackendPort:          flags.port,
-		FrontendHost:         flags.frontendHost,
-		FrontendPort:         flags.frontendPort,
-		DatastorePort:        datastorePort,
-		Readonly:             flags.readonly,
-		Debug:                flags.debug,
-		Demo:                 flags.demo,
-		DemoDataDir:          demoDataDir,
-		Version:              version,
-		GitCommit:            gitcommit,
-		PgURL:                flags.pgURL,
-		DisableMetric:        flags.disableMetric,
-		BackupStorageBackend: backupStorageBackend,
-		BackupRegion:         flags.backupRegion,
-		BackupBucket:         flags.backupBucket,
-		BackupCredentialFile: flags.backupCredential,
+		BackendHost:            flags.host,
+		BackendPort:            flags.port,
+		FrontendHost:           flags.frontendHost,
+		FrontendPort:           flags.frontendPort,
+		DatastorePort:          datastorePort,
+		Readonly:               flags.readonly,
+		Debug:                  flags.debug,
+		Demo:                   flags.demo,
+		DemoDataDir:            demoDataDir,
+		Version:                version,
+		GitCommit:              gitcommit,
+		PgURL:                  flags.pgURL,
+		DisableMetric:          flags.disableMetric,
+		BackupStorageBackend:   flags.backupStorageBackend,
+		BackupRegion:           flags.backupRegion,
+		BackupBucket:           flags.backupBucket,
+		BackupCredentialFile:   flags.backupCredential,
+		SecretKey:              flags.secretKey,
+		SMTPPassword:           flags.smtpPassword,
+		EnableTaskCheckCommand: flags.enableTaskCheckCommand,
 	}
 }
 
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common/log"
+)
+
+// minSecretKeyLength is the minimum length of the secret key signing the JWT auth token.
+const minSecretKeyLength = 32
+
+// serverSecret is a sensitive server config which can be read from a file, e.g. the mounted Kubernetes secret,
+// or an environment variable, so that it isn't exposed in the command line. The sources in the order of precedence are:
+//  1. The file specified by the --<name>-file flag.
+//  2. The file specified by the BB_<NAME>_FILE environment variable.
+//  3. The BB_<NAME> environment variable.
+//
+// The server falls back to the default source if none is specified.
+type serverSecret struct {
+	// flagName is the name of the flag specifying the file, e.g. "pg-password-file".
+	flagName string
+	// envName is the name of the environment variable specifying the value, e.g. "BB_PG_PASSWORD".
+	// The environment variable with the "_FILE" suffix specifies the file.
+	envName string
+}
+
+var (
+	// pgPasswordSecret is the password of the external PostgreSQL instance, which overrides the one in --pg.
+	pgPasswordSecret = serverSecret{flagName: "pg-password-file", envName: "BB_PG_PASSWORD"}
+	// secretKeySecret is the key signing the JWT auth token, which overrides the one generated on the first start.
+	secretKeySecret = serverSecret{flagName: "secret-key-file", envName: "BB_SECRET_KEY"}
+	// smtpPasswordSecret is the password of the SMTP server, which overrides the one in the SMTP setting.
+	smtpPasswordSecret = serverSecret{flagName: "smtp-password-file", envName: "BB_SMTP_PASSWORD"}
+)
+
+// resolve returns the secret and the description of its source, the file is the value of the --<name>-file flag.
+// An empty secret is returned if none of the sources is specified.
+func (s serverSecret) resolve(file string, getenv func(string) string) (string, string, error) {
+	var value, source string
+	switch {
+	case file != "":
+		source = "--" + s.flagName
+	case getenv(s.envName+"_FILE") != "":
+		file = getenv(s.envName + "_FILE")
+		source = s.envName + "_FILE"
+	case getenv(s.envName) != "":
+		value = getenv(s.envName)
+		source = s.envName
+	default:
+		return "", "", nil
+	}
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "failed to read the file specified by %s", source)
+		}
+		// The trailing newline is usually added by the editors and the secret managers.
+		value = strings.TrimRight(string(content), "\r\n")
+	}
+	if value == "" {
+		return "", "", errors.Errorf("the secret specified by %s is empty", source)
+	}
+	return value, source, nil
+}
+
+// checkSecrets resolves and validates the secrets from the files and the environment variables looked up by getenv.
+// The password of the external PostgreSQL instance is set into --pg.
+func checkSecrets(getenv func(string) string) error {
+	pgPassword, source, err := pgPasswordSecret.resolve(flags.pgPasswordFile, getenv)
+	if err != nil {
+		return err
+	}
+	if pgPassword != "" {
+		pgURL, err := setPgURLPassword(flags.pgURL, pgPassword)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", source)
+		}
+		flags.pgURL = pgURL
+		log.Info("Using the external PostgreSQL password", zap.String("source", source))
+	}
+
+	secretKey, source, err := secretKeySecret.resolve(flags.secretKeyFile, getenv)
+	if err != nil {
+		return err
+	}
+	if secretKey != "" {
+		if len(secretKey) < minSecretKeyLength {
+			return errors.Errorf("the secret key specified by %s must be at least %d characters", source, minSecretKeyLength)
+		}
+		flags.secretKey = secretKey
+		log.Info("Using the secret key", zap.String("source", source))
+	}
+
+	smtpPassword, source, err := smtpPasswordSecret.resolve(flags.smtpPasswordFile, getenv)
+	if err != nil {
+		return err
+	}
+	if smtpPassword != "" {
+		flags.smtpPassword = smtpPassword
+		log.Info("Using the SMTP password", zap.String("source", source))
+	}
+	return nil
+}
+
+// setPgURLPassword returns the PostgreSQL connection url with the password replaced.
+func setPgURLPassword(pgURL string, password string) (string, error) {
+	if pgURL == "" {
+		return "", errors.Errorf("the password is only used for the external PostgreSQL instance specified by --pg")
+	}
+	u, err := url.Parse(pgURL)
+	if err != nil {
+		return "", err
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", errors.Errorf("missing user in the --pg connection string")
+	}
+	u.User = url.UserPassword(u.User.Username(), password)
+	return u.String(), nil
+}
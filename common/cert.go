@@ -0,0 +1,83 @@
+package common
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	caMu sync.RWMutex
+	// caCertList is the list of the workspace CA certificates trusted besides the system ones.
+	caCertList []*x509.Certificate
+)
+
+// ParseCACertificates parses the PEM encoded CA certificates, an empty PEM has no certificates.
+func ParseCACertificates(caPEM string) ([]*x509.Certificate, error) {
+	var certList []*x509.Certificate
+	rest := []byte(strings.TrimSpace(caPEM))
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, errors.Errorf("invalid PEM data at certificate %d", len(certList)+1)
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, errors.Errorf("unexpected PEM block type %q at certificate %d", block.Type, len(certList)+1)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse certificate %d", len(certList)+1)
+		}
+		if !cert.IsCA {
+			return nil, errors.Errorf("certificate %d %q is not a CA certificate", len(certList)+1, cert.Subject.String())
+		}
+		certList = append(certList, cert)
+		rest = []byte(strings.TrimSpace(string(rest)))
+	}
+	return certList, nil
+}
+
+// SetCACertificates replaces the workspace CA certificates and reloads OutboundTransport,
+// so that the new connections trust them right away.
+func SetCACertificates(caPEM string) error {
+	certList, err := ParseCACertificates(caPEM)
+	if err != nil {
+		return err
+	}
+	caMu.Lock()
+	caCertList = certList
+	caMu.Unlock()
+
+	reloadOutboundTransport()
+	return nil
+}
+
+// AppendCACertificates adds the workspace CA certificates to the pool.
+func AppendCACertificates(pool *x509.CertPool) {
+	caMu.RLock()
+	defer caMu.RUnlock()
+	for _, cert := range caCertList {
+		pool.AddCert(cert)
+	}
+}
+
+// RootCAs returns the system CA certificates with the workspace ones, or nil to use the system ones if no workspace CA certificates.
+func RootCAs() *x509.CertPool {
+	caMu.RLock()
+	defer caMu.RUnlock()
+	if len(caCertList) == 0 {
+		return nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	for _, cert := range caCertList {
+		pool.AddCert(cert)
+	}
+	return pool
+}
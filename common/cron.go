@@ -0,0 +1,160 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CronSchedule is a parsed cron expression with the standard five fields: minute, hour, day of month, month and day of week.
+type CronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// If both day of month and day of week are restricted, a time matches if either field matches.
+	dayOfMonthStar bool
+	dayOfWeekStar  bool
+}
+
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	// Both 0 and 7 are Sunday.
+	{name: "day of week", min: 0, max: 7},
+}
+
+// ParseCronSchedule parses the cron expression, e.g. "0 3 * * 1-5" for 03:00 on weekdays.
+// Each field supports "*", values, ranges "a-b", lists "a,b" and steps "*/n" or "a-b/n".
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	fieldList := strings.Fields(expr)
+	if len(fieldList) != len(cronFields) {
+		return nil, errors.Errorf("cron expression %q should have %d fields, got %d", expr, len(cronFields), len(fieldList))
+	}
+	var bitsList []uint64
+	for i, field := range fieldList {
+		bits, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cron expression %q", expr)
+		}
+		bitsList = append(bitsList, bits)
+	}
+	schedule := &CronSchedule{
+		minute:         bitsList[0],
+		hour:           bitsList[1],
+		dayOfMonth:     bitsList[2],
+		month:          bitsList[3],
+		dayOfWeek:      bitsList[4],
+		dayOfMonthStar: strings.HasPrefix(fieldList[2], "*"),
+		dayOfWeekStar:  strings.HasPrefix(fieldList[4], "*"),
+	}
+	// Sunday can be either 0 or 7.
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	return schedule, nil
+}
+
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return 0, errors.Errorf("invalid step %q in %s field", part[i+1:], f.name)
+			}
+			rangePart, step = part[:i], s
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = parseCronValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(bounds[1], f); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, errors.Errorf("invalid range %q in %s field", rangePart, f.name)
+			}
+		default:
+			value, err := parseCronValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			start = value
+			// "a/n" means from a to the max value.
+			if step == 1 {
+				end = value
+			}
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, f cronField) (int, error) {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q in %s field", s, f.name)
+	}
+	if value < f.min || value > f.max {
+		return 0, errors.Errorf("value %d out of range [%d, %d] in %s field", value, f.min, f.max, f.name)
+	}
+	return value, nil
+}
+
+// Next returns the earliest time matching the schedule strictly after t, truncated to the minute.
+// It returns the zero time if there's no matching time within the next five years, e.g. for "0 0 30 2 *".
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	domMatch := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dowMatch := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.dayOfMonthStar || s.dayOfWeekStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
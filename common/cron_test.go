@@ -0,0 +1,90 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// 2022-09-01 is a Thursday.
+	now := time.Date(2022, 9, 1, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{
+			expr: "* * * * *",
+			want: time.Date(2022, 9, 1, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			expr: "*/15 * * * *",
+			want: time.Date(2022, 9, 1, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 3 * * *",
+			want: time.Date(2022, 9, 2, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "30 10 * * *",
+			want: time.Date(2022, 9, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 0 * * 0",
+			want: time.Date(2022, 9, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 0 * * 7",
+			want: time.Date(2022, 9, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 9 * * 1-5",
+			want: time.Date(2022, 9, 2, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 0 1 1,7 *",
+			want: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// Either day of month or day of week matches if both are restricted.
+			expr: "0 0 15 * 6",
+			want: time.Date(2022, 9, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 0 29 2 *",
+			want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 0 30 2 *",
+			want: time.Time{},
+		},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseCronSchedule(test.expr)
+		require.NoError(t, err, test.expr)
+		require.Equal(t, test.want, schedule.Next(now), test.expr)
+	}
+}
+
+func TestParseCronScheduleError(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	}
+
+	for _, expr := range tests {
+		_, err := ParseCronSchedule(expr)
+		require.Error(t, err, expr)
+	}
+}
@@ -23,6 +23,8 @@ const (
 	// 101 ~ 199 db error.
 	DbConnectionFailure Code = 101
 	DbExecutionError    Code = 102
+	// DbQueryResultTooLarge means the query result exceeds the maximum size allowed.
+	DbQueryResultTooLarge Code = 103
 
 	// 201 db migration error
 	// Db migration is a core feature, so we separate it from the db error.
@@ -33,9 +35,12 @@ const (
 	// MigrationBaselineMissing Code = 204.
 	MigrationPending Code = 205
 	MigrationFailed  Code = 206
+	MigrationPaused  Code = 207
 
 	// 301 task error.
 	TaskTimingNotAllowed Code = 301
+	// TaskOrphaned is the code of the task failed because its executor stopped heartbeating.
+	TaskOrphaned Code = 302
 
 	// 401 task sql type error.
 	TaskTypeNotDML Code = 401
@@ -0,0 +1,171 @@
+package common
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ProxyConfig is the outbound HTTP proxy configuration of the workspace.
+type ProxyConfig struct {
+	// HTTPProxy is the proxy URL for the http requests.
+	HTTPProxy string
+	// HTTPSProxy is the proxy URL for the https requests.
+	HTTPSProxy string
+	// NoProxy is the list of the destinations bypassing the proxy. Each entry is one of
+	// "*" for all destinations, an IP address, a CIDR, or a host name matching the host and its subdomains,
+	// optionally followed by ":port". A host name with a leading "." only matches the subdomains.
+	NoProxy []string
+}
+
+var (
+	proxyMu     sync.RWMutex
+	proxyConfig *proxyConfigParsed
+)
+
+type proxyConfigParsed struct {
+	httpProxy  *url.URL
+	httpsProxy *url.URL
+	noProxy    []string
+}
+
+// SetProxyConfig validates and applies the proxy configuration to OutboundTransport.
+// A nil config or one without any proxy URL falls back to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func SetProxyConfig(config *ProxyConfig) error {
+	parsed, err := parseProxyConfig(config)
+	if err != nil {
+		return err
+	}
+	proxyMu.Lock()
+	defer proxyMu.Unlock()
+	proxyConfig = parsed
+	return nil
+}
+
+// ValidateProxyConfig validates the proxy configuration without applying it.
+func ValidateProxyConfig(config *ProxyConfig) error {
+	_, err := parseProxyConfig(config)
+	return err
+}
+
+func parseProxyConfig(config *ProxyConfig) (*proxyConfigParsed, error) {
+	if config == nil || (config.HTTPProxy == "" && config.HTTPSProxy == "") {
+		return nil, nil
+	}
+	parsed := &proxyConfigParsed{}
+	var err error
+	if parsed.httpProxy, err = parseProxyURL(config.HTTPProxy); err != nil {
+		return nil, err
+	}
+	if parsed.httpsProxy, err = parseProxyURL(config.HTTPSProxy); err != nil {
+		return nil, err
+	}
+	for _, entry := range config.NoProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return nil, errors.Wrapf(err, "invalid no proxy CIDR %q", entry)
+			}
+		}
+		parsed.noProxy = append(parsed.noProxy, entry)
+	}
+	return parsed, nil
+}
+
+func parseProxyURL(proxy string) (*url.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid proxy URL %q", proxy)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.Errorf("invalid proxy URL %q, the scheme must be http, https or socks5", proxy)
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("invalid proxy URL %q, missing host", proxy)
+	}
+	return u, nil
+}
+
+// Proxy returns the proxy URL for the request according to the proxy configuration, nil means connecting directly.
+// It's used as the http.Transport Proxy.
+func Proxy(req *http.Request) (*url.URL, error) {
+	proxyMu.RLock()
+	config := proxyConfig
+	proxyMu.RUnlock()
+	if config == nil {
+		return http.ProxyFromEnvironment(req)
+	}
+
+	proxy := config.httpProxy
+	if req.URL.Scheme == "https" {
+		proxy = config.httpsProxy
+	}
+	if proxy == nil {
+		return nil, nil
+	}
+	if isProxyBypassed(req.URL, config.noProxy) {
+		return nil, nil
+	}
+	return proxy, nil
+}
+
+// isProxyBypassed returns whether the destination matches the no proxy list.
+// The requests to localhost and the loopback addresses are never proxied, the same as http.ProxyFromEnvironment.
+func isProxyBypassed(u *url.URL, noProxy []string) bool {
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, entry := range noProxy {
+		if entry == "*" {
+			return true
+		}
+		if strings.Contains(entry, "/") {
+			if _, cidr, err := net.ParseCIDR(entry); err == nil && ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if strings.HasPrefix(entryHost, ".") {
+			if strings.HasSuffix(host, entryHost) {
+				return true
+			}
+			continue
+		}
+		if host == entryHost || strings.HasSuffix(host, "."+entryHost) {
+			return true
+		}
+	}
+	return false
+}
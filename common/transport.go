@@ -0,0 +1,43 @@
+package common
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync/atomic"
+)
+
+// OutboundTransport is the HTTP transport for the outbound integrations such as the VCS providers and the webhook receivers.
+// It honors the workspace proxy configuration and trusts the workspace CA certificates besides the system ones.
+var OutboundTransport http.RoundTripper = &outboundTransport{}
+
+func init() {
+	outboundTransportValue.Store(newOutboundTransport())
+}
+
+// outboundTransportValue holds the current *http.Transport, which is replaced when the CA certificates change,
+// since the TLS config of a transport cannot be changed after use.
+var outboundTransportValue atomic.Value
+
+type outboundTransport struct{}
+
+func (*outboundTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return outboundTransportValue.Load().(*http.Transport).RoundTrip(req)
+}
+
+func newOutboundTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = Proxy
+	if pool := RootCAs(); pool != nil {
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return transport
+}
+
+// reloadOutboundTransport replaces the transport so that the new connections use the current CA certificates.
+func reloadOutboundTransport() {
+	old := outboundTransportValue.Swap(newOutboundTransport()).(*http.Transport)
+	old.CloseIdleConnections()
+}
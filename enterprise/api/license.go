@@ -24,6 +24,10 @@ type License struct {
 	Plan          api.PlanType
 	Trialing      bool
 	OrgName       string
+	// Seat is the maximum number of the workspace members, 0 means unlimited.
+	Seat int
+	// Features are the features granted by the license in addition to the plan.
+	Features []api.FeatureType
 }
 
 // Valid will check if license expired or has correct plan type.
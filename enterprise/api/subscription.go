@@ -17,4 +17,8 @@ type Subscription struct {
 	Trialing      bool         `jsonapi:"attr,trialing"`
 	OrgID         string       `jsonapi:"attr,orgId"`
 	OrgName       string       `jsonapi:"attr,orgName"`
+	// Seat is the maximum number of the workspace members, 0 means unlimited.
+	Seat int `jsonapi:"attr,seat"`
+	// Features are the features granted by the license in addition to the plan.
+	Features []api.FeatureType `jsonapi:"attr,features"`
 }
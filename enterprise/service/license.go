@@ -23,10 +23,12 @@ type LicenseService struct {
 // Claims creates a struct that will be encoded to a JWT.
 // We add jwt.RegisteredClaims as an embedded type, to provide fields such as name.
 type Claims struct {
-	InstanceCount int    `json:"instanceCount"`
-	Trialing      bool   `json:"trialing"`
-	Plan          string `json:"plan"`
-	OrgName       string `json:"orgName"`
+	InstanceCount int      `json:"instanceCount"`
+	Seat          int      `json:"seat"`
+	Features      []string `json:"features"`
+	Trialing      bool     `json:"trialing"`
+	Plan          string   `json:"plan"`
+	OrgName       string   `json:"orgName"`
 	jwt.RegisteredClaims
 }
 
@@ -123,8 +125,14 @@ func (s *LicenseService) parseClaims(claims *Claims) (*enterpriseAPI.License, er
 		return nil, common.Errorf(common.Invalid, "plan type %q is not valid", planType)
 	}
 
+	if claims.Seat < 0 {
+		return nil, common.Errorf(common.Invalid, "license seat '%v' is not valid, expect a non-negative number", claims.Seat)
+	}
+
 	license := &enterpriseAPI.License{
 		InstanceCount: instanceCount,
+		Seat:          claims.Seat,
+		Features:      convertFeatureList(claims.Features),
 		ExpiresTs:     claims.ExpiresAt.Unix(),
 		IssuedTs:      claims.IssuedAt.Unix(),
 		Plan:          planType,
@@ -176,3 +184,15 @@ func convertPlanType(candidate string) (api.PlanType, error) {
 		return api.FREE, errors.Errorf("cannot conver plan type %q", candidate)
 	}
 }
+
+// convertFeatureList converts the features in the license claims, the features unknown to this version are ignored.
+func convertFeatureList(candidateList []string) []api.FeatureType {
+	var featureList []api.FeatureType
+	for _, candidate := range candidateList {
+		feature := api.FeatureType(candidate)
+		if _, ok := api.FeatureMatrix[feature]; ok {
+			featureList = append(featureList, feature)
+		}
+	}
+	return featureList
+}
@@ -42,6 +42,11 @@ export function taskCheckRunSummary(task: Task): TaskCheckRunSummary {
         break;
       case "DONE":
         for (const result of checkRun.result.resultList) {
+          // The suppressed result has been signed off by the approver.
+          if (result.suppression) {
+            summary.successCount++;
+            continue;
+          }
           switch (result.status) {
             case "SUCCESS":
               summary.successCount++;
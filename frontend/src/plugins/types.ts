@@ -19,6 +19,7 @@ export enum IssueBuiltinFieldId {
   PROJECT = "5",
   SUBSCRIBER_LIST = "6",
   SQL = "7",
+  LABEL_LIST = "8",
 }
 
 export const INPUT_CUSTOM_FIELD_ID_BEGIN = "100";
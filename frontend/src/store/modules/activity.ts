@@ -7,16 +7,20 @@ import {
   ActivityId,
   ActivityPatch,
   ActivityState,
+  empty,
+  isPagedResponse,
   Issue,
   IssueId,
   PrincipalId,
   ProjectId,
+  ResourceIdentifier,
   ResourceObject,
   UNKNOWN_ID,
 } from "@/types";
 import { useAuthStore } from "./auth";
 import { getPrincipalFromIncludedList } from "./principal";
 import { useIssueStore } from "./issue";
+import { convertEntityList } from "./utils";
 
 function convert(
   activity: ResourceObject,
@@ -40,6 +44,27 @@ function convert(
   };
 }
 
+function getActivityFromIncludedList(
+  data:
+    | ResourceIdentifier<ResourceObject>
+    | ResourceIdentifier<ResourceObject>[]
+    | undefined,
+  includedList: ResourceObject[]
+): Activity {
+  if (data == null) {
+    return empty("ACTIVITY");
+  }
+  for (const item of includedList || []) {
+    if (item.type !== "activity") {
+      continue;
+    }
+    if (item.id == (data as ResourceIdentifier).id) {
+      return convert(item, includedList);
+    }
+  }
+  return empty("ACTIVITY");
+}
+
 export const useActivityStore = defineStore("activity", {
   state: (): ActivityState => ({
     activityListByUser: new Map(),
@@ -102,6 +127,35 @@ export const useActivityStore = defineStore("activity", {
       );
       return activityList;
     },
+    // Fetches a page of the activity list, pass the returned nextToken to
+    // fetch the next page. The nextToken is empty if there are no more pages.
+    async fetchPagedActivityList(params: {
+      typePrefix?: string;
+      container?: number | string;
+      user?: PrincipalId;
+      order?: "ASC" | "DESC";
+      limit: number;
+      token?: string;
+    }) {
+      const url = `/api/activity?${stringify({
+        ...params,
+        token: params.token ?? "",
+      })}`;
+      const responseData = (await axios.get(url)).data;
+      const activityList = convertEntityList(
+        responseData,
+        "activities",
+        convert,
+        getActivityFromIncludedList
+      );
+      const nextToken = isPagedResponse(responseData, "activities")
+        ? responseData.data.attributes.nextToken
+        : "";
+      return {
+        nextToken,
+        activityList,
+      };
+    },
     async fetchActivityListForIssue(issue: Issue) {
       const requestListForIssue = this.fetchActivityList({
         typePrefix: "bb.issue.",
@@ -25,6 +25,9 @@ export const useActuatorStore = defineStore("actuator", {
     needAdminSetup: (state) => {
       return state.serverInfo?.needAdminSetup || false;
     },
+    isInMaintenance: (state) => {
+      return state.serverInfo?.maintenance?.enabled || false;
+    },
   },
   actions: {
     setServerInfo(serverInfo: ServerInfo) {
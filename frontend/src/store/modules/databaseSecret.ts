@@ -0,0 +1,62 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  DatabaseId,
+  DatabaseSecret,
+  DatabaseSecretUpsert,
+  ResourceObject,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  databaseSecret: ResourceObject,
+  includedList: ResourceObject[]
+): DatabaseSecret {
+  return {
+    ...(databaseSecret.attributes as Omit<
+      DatabaseSecret,
+      "id" | "creator" | "updater"
+    >),
+    creator: getPrincipalFromIncludedList(
+      databaseSecret.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      databaseSecret.relationships!.updater.data,
+      includedList
+    ),
+    id: parseInt(databaseSecret.id),
+  };
+}
+
+export const useDatabaseSecretStore = defineStore("databaseSecret", {
+  actions: {
+    async fetchDatabaseSecretList(
+      databaseId: DatabaseId
+    ): Promise<DatabaseSecret[]> {
+      const data = (await axios.get(`/api/database/${databaseId}/secret`))
+        .data;
+      return data.data.map((databaseSecret: ResourceObject) => {
+        return convert(databaseSecret, data.included);
+      });
+    },
+    async upsertDatabaseSecret(
+      databaseId: DatabaseId,
+      name: string,
+      upsert: DatabaseSecretUpsert
+    ): Promise<DatabaseSecret> {
+      const data = (
+        await axios.patch(`/api/database/${databaseId}/secret/${name}`, {
+          data: {
+            type: "databaseSecretUpsert",
+            attributes: upsert,
+          },
+        })
+      ).data;
+      return convert(data.data, data.included);
+    },
+    async deleteDatabaseSecret(databaseId: DatabaseId, name: string) {
+      await axios.delete(`/api/database/${databaseId}/secret/${name}`);
+    },
+  },
+});
@@ -0,0 +1,84 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  DatabaseId,
+  DBQueryGrant,
+  DBQueryGrantCreate,
+  DBQueryGrantId,
+  DBQueryGrantPatch,
+  DBQueryGrantStatus,
+  ResourceObject,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  dbQueryGrant: ResourceObject,
+  includedList: ResourceObject[]
+): DBQueryGrant {
+  return {
+    ...(dbQueryGrant.attributes as Omit<
+      DBQueryGrant,
+      "id" | "creator" | "updater"
+    >),
+    creator: getPrincipalFromIncludedList(
+      dbQueryGrant.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      dbQueryGrant.relationships!.updater.data,
+      includedList
+    ),
+    id: parseInt(dbQueryGrant.id),
+  };
+}
+
+export const useDBQueryGrantStore = defineStore("dbQueryGrant", {
+  actions: {
+    async fetchDBQueryGrantList(
+      databaseId: DatabaseId,
+      status?: DBQueryGrantStatus
+    ): Promise<DBQueryGrant[]> {
+      const data = (
+        await axios.get(`/api/database/${databaseId}/query-grant`, {
+          params: { status },
+        })
+      ).data;
+      return data.data.map((dbQueryGrant: ResourceObject) => {
+        return convert(dbQueryGrant, data.included);
+      });
+    },
+    // Requests the query grant, which is pending until approved.
+    async createDBQueryGrant(
+      databaseId: DatabaseId,
+      create: DBQueryGrantCreate
+    ): Promise<DBQueryGrant> {
+      const data = (
+        await axios.post(`/api/database/${databaseId}/query-grant`, {
+          data: {
+            type: "dbQueryGrantCreate",
+            attributes: create,
+          },
+        })
+      ).data;
+      return convert(data.data, data.included);
+    },
+    async patchDBQueryGrant(
+      databaseId: DatabaseId,
+      dbQueryGrantId: DBQueryGrantId,
+      patch: DBQueryGrantPatch
+    ): Promise<DBQueryGrant> {
+      const data = (
+        await axios.patch(
+          `/api/database/${databaseId}/query-grant/${dbQueryGrantId}`,
+          {
+            data: {
+              type: "dbQueryGrantPatch",
+              attributes: patch,
+            },
+          }
+        )
+      ).data;
+      return convert(data.data, data.included);
+    },
+  },
+});
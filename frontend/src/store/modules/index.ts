@@ -6,6 +6,9 @@ export * from "./backup";
 export * from "./bookmark";
 export * from "./command";
 export * from "./database";
+export * from "./databaseSecret";
+export * from "./managedGrant";
+export * from "./dbQueryGrant";
 export * from "./dataSource";
 export * from "./debug";
 export * from "./deployment";
@@ -13,9 +16,11 @@ export * from "./environment";
 export * from "./gitlab";
 export * from "./help";
 export * from "./issue";
+export * from "./issueView";
 export * from "./issueSubscriber";
 export * from "./inbox";
 export * from "./instance";
+export * from "./instanceAgent";
 export * from "./label";
 export * from "./member";
 export * from "./notification";
@@ -25,14 +30,20 @@ export * from "./policy";
 export * from "./principal";
 export * from "./project";
 export * from "./projectWebhook";
+export * from "./reportSubscription";
 export * from "./repository";
 export * from "./router";
+export * from "./schemaSnapshot";
 export * from "./setting";
 export * from "./sheet";
 export * from "./stage";
 export * from "./sql";
 export * from "./sqlEditor";
 export * from "./subscription";
+export * from "./usage";
+export * from "./migrationStat";
+export * from "./issueSLA";
+export * from "./workspace";
 export * from "./tab";
 export * from "./table";
 export * from "./task";
@@ -41,4 +52,6 @@ export * from "./vcs";
 export * from "./view";
 export * from "./db_extension";
 export * from "./sqlReview";
+export * from "./statementComment";
+export * from "./queryAuditLog";
 export * from "./onboardingGuide";
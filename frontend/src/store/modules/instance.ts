@@ -3,6 +3,7 @@ import axios from "axios";
 import { computed, onBeforeMount } from "vue";
 import {
   Anomaly,
+  DatabaseId,
   DataSource,
   empty,
   EMPTY_ID,
@@ -18,6 +19,7 @@ import {
   INSTANCE_OPERATION_TIMEOUT,
   MigrationHistory,
   MigrationHistoryId,
+  MigrationHistoryImportFormat,
   ResourceIdentifier,
   ResourceObject,
   RowStatus,
@@ -378,6 +380,31 @@ export const useInstanceStore = defineStore("instance", {
 
       return updatedInstance;
     },
+    // Fails if any pipeline is in flight against the instance.
+    async moveInstanceEnvironment({
+      instanceId,
+      environmentId,
+    }: {
+      instanceId: InstanceId;
+      environmentId: EnvironmentId;
+    }) {
+      const data = (
+        await axios.patch(`/api/instance/${instanceId}/environment`, {
+          data: {
+            type: "instanceEnvironmentPatch",
+            attributes: { environmentId },
+          },
+        })
+      ).data;
+      const updatedInstance = convert(data.data, data.included);
+
+      this.setInstanceById({
+        instanceId: updatedInstance.id,
+        instance: updatedInstance,
+      });
+
+      return updatedInstance;
+    },
     async deleteInstanceById(instanceId: InstanceId) {
       await axios.delete(`/api/instance/${instanceId}`);
       this.instanceById.delete(instanceId);
@@ -512,6 +539,48 @@ export const useInstanceStore = defineStore("instance", {
 
       return historyList;
     },
+    async importMigrationHistory({
+      instanceId,
+      databaseId,
+      databaseName,
+      format,
+      table,
+    }: {
+      instanceId: InstanceId;
+      databaseId: DatabaseId;
+      databaseName: string;
+      format: MigrationHistoryImportFormat;
+      // Empty uses the default history table of the migration tool.
+      table?: string;
+    }): Promise<MigrationHistory[]> {
+      const data = (
+        await axios.post(
+          `/api/database/${databaseId}/change-history/import`,
+          {
+            data: {
+              type: "migrationHistoryImport",
+              attributes: {
+                format,
+                table: table ?? "",
+              },
+            },
+          },
+          {
+            timeout: INSTANCE_OPERATION_TIMEOUT,
+          }
+        )
+      ).data.data;
+      const importedList: MigrationHistory[] = data.map(
+        (history: ResourceObject) => {
+          return convertMigrationHistory(history);
+        }
+      );
+
+      // Refresh the cached history list to include the imported ones.
+      await this.fetchMigrationHistory({ instanceId, databaseName });
+
+      return importedList;
+    },
     async createEmbeddedPostgresInstance() {
       const data = (
         await axios.post<{
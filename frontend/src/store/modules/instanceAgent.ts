@@ -0,0 +1,54 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import { InstanceAgent, InstanceId, ResourceObject } from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  instanceAgent: ResourceObject,
+  includedList: ResourceObject[]
+): InstanceAgent {
+  return {
+    ...(instanceAgent.attributes as Omit<
+      InstanceAgent,
+      "id" | "creator" | "updater"
+    >),
+    creator: getPrincipalFromIncludedList(
+      instanceAgent.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      instanceAgent.relationships!.updater.data,
+      includedList
+    ),
+    id: parseInt(instanceAgent.id),
+  };
+}
+
+export const useInstanceAgentStore = defineStore("instanceAgent", {
+  actions: {
+    // Returns undefined if the instance is not in agent mode.
+    async fetchInstanceAgent(
+      instanceId: InstanceId
+    ): Promise<InstanceAgent | undefined> {
+      try {
+        const data = (await axios.get(`/api/instance/${instanceId}/agent`))
+          .data;
+        return convert(data.data, data.included);
+      } catch (error: any) {
+        if (error.response?.status === 404) {
+          return undefined;
+        }
+        throw error;
+      }
+    },
+    // Enables the agent mode, or regenerates the token. The returned token is only shown once.
+    async createInstanceAgent(instanceId: InstanceId): Promise<InstanceAgent> {
+      const data = (await axios.post(`/api/instance/${instanceId}/agent`))
+        .data;
+      return convert(data.data, data.included);
+    },
+    async deleteInstanceAgent(instanceId: InstanceId) {
+      await axios.delete(`/api/instance/${instanceId}/agent`);
+    },
+  },
+});
@@ -3,14 +3,17 @@ import axios from "axios";
 import {
   empty,
   EMPTY_ID,
+  EnvironmentId,
   isPagedResponse,
   Issue,
   IssueCreate,
   IssueId,
   IssuePatch,
+  IssueReferenceType,
   IssueState,
   IssueStatus,
   IssueStatusPatch,
+  IssueViewId,
   Pipeline,
   Principal,
   PrincipalId,
@@ -137,16 +140,44 @@ export const useIssueStore = defineStore("issue", {
       issueStatusList,
       userId,
       projectId,
+      viewId,
+      assigneeId,
+      environmentId,
+      label,
+      reference,
       limit,
       token,
     }: {
       issueStatusList?: IssueStatus[];
       userId?: PrincipalId;
       projectId?: ProjectId;
+      // The other filters take precedence over the saved filter of the view.
+      viewId?: IssueViewId;
+      assigneeId?: PrincipalId;
+      environmentId?: EnvironmentId;
+      label?: string;
+      // Finds the issues referencing the target in the description.
+      reference?: { type: IssueReferenceType; targetId: number };
       limit?: number;
       token?: string;
     }) {
       const queryList = [];
+      if (viewId) {
+        queryList.push(`view=${viewId}`);
+      }
+      if (assigneeId) {
+        queryList.push(`assignee=${assigneeId}`);
+      }
+      if (environmentId) {
+        queryList.push(`environment=${environmentId}`);
+      }
+      if (label) {
+        queryList.push(`label=${encodeURIComponent(label)}`);
+      }
+      if (reference) {
+        queryList.push(`referenceType=${reference.type}`);
+        queryList.push(`referenceId=${reference.targetId}`);
+      }
       if (issueStatusList && issueStatusList.length > 0) {
         queryList.push(`status=${issueStatusList.join(",")}`);
       }
@@ -159,9 +190,8 @@ export const useIssueStore = defineStore("issue", {
       if (limit) {
         queryList.push(`limit=${limit}`);
       }
-      if (token) {
-        queryList.push(`token=${token}`);
-      }
+      // An empty token requests the first page.
+      queryList.push(`token=${token ?? ""}`);
 
       let url = "/api/issue";
       if (queryList.length > 0) {
@@ -191,6 +221,11 @@ export const useIssueStore = defineStore("issue", {
       issueStatusList?: IssueStatus[];
       userId?: PrincipalId;
       projectId?: ProjectId;
+      viewId?: IssueViewId;
+      assigneeId?: PrincipalId;
+      environmentId?: EnvironmentId;
+      label?: string;
+      reference?: { type: IssueReferenceType; targetId: number };
       limit?: number;
     }) {
       const result = await this.fetchPagedIssueList(params);
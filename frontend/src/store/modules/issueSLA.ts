@@ -0,0 +1,38 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  IssueId,
+  IssueSLA,
+  IssueSLAReport,
+  IssueSLAReportFind,
+  IssueSLAState,
+} from "@/types";
+
+export const useIssueSLAStore = defineStore("issueSLA", {
+  state: (): IssueSLAState => ({
+    issueSLAById: new Map(),
+  }),
+  actions: {
+    setIssueSLA(issueId: IssueId, issueSLA: IssueSLA) {
+      this.issueSLAById.set(issueId, issueSLA);
+    },
+    async fetchIssueSLA(issueId: IssueId) {
+      const data = (await axios.get(`/api/issue/${issueId}/sla`)).data.data;
+      const issueSLA = data.attributes as IssueSLA;
+      this.setIssueSLA(issueId, issueSLA);
+      return issueSLA;
+    },
+    async fetchIssueSLAReport(find: IssueSLAReportFind = {}) {
+      const data = (
+        await axios.get(`/api/issue-sla-report`, {
+          params: {
+            project: find.projectId,
+            startTs: find.startTs,
+            endTs: find.endTs,
+          },
+        })
+      ).data.data;
+      return data.attributes as IssueSLAReport;
+    },
+  },
+});
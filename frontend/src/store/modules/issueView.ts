@@ -0,0 +1,87 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  IssueView,
+  IssueViewCreate,
+  IssueViewId,
+  IssueViewPatch,
+  ProjectId,
+  ResourceObject,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  issueView: ResourceObject,
+  includedList: ResourceObject[]
+): IssueView {
+  const filter = issueView.attributes.filter
+    ? JSON.parse((issueView.attributes.filter as string) || "{}")
+    : {};
+
+  return {
+    ...(issueView.attributes as Omit<
+      IssueView,
+      "id" | "filter" | "creator" | "updater"
+    >),
+    creator: getPrincipalFromIncludedList(
+      issueView.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      issueView.relationships!.updater.data,
+      includedList
+    ),
+    id: parseInt(issueView.id),
+    filter,
+  };
+}
+
+export const useIssueViewStore = defineStore("issueView", {
+  actions: {
+    // Returns the views of the project if specified, otherwise the views created by the current user.
+    async fetchIssueViewList(projectId?: ProjectId): Promise<IssueView[]> {
+      const url = projectId
+        ? `/api/issue-view?project=${projectId}`
+        : "/api/issue-view";
+      const data = (await axios.get(url)).data;
+      return data.data.map((issueView: ResourceObject) => {
+        return convert(issueView, data.included);
+      });
+    },
+    async createIssueView(create: IssueViewCreate): Promise<IssueView> {
+      const data = (
+        await axios.post(`/api/issue-view`, {
+          data: {
+            type: "issueViewCreate",
+            attributes: {
+              ...create,
+              // Server expects filter as string, so we stringify first.
+              filter: JSON.stringify(create.filter),
+            },
+          },
+        })
+      ).data;
+      return convert(data.data, data.included);
+    },
+    async patchIssueView(
+      issueViewId: IssueViewId,
+      patch: IssueViewPatch
+    ): Promise<IssueView> {
+      const data = (
+        await axios.patch(`/api/issue-view/${issueViewId}`, {
+          data: {
+            type: "issueViewPatch",
+            attributes: {
+              ...patch,
+              filter: patch.filter ? JSON.stringify(patch.filter) : undefined,
+            },
+          },
+        })
+      ).data;
+      return convert(data.data, data.included);
+    },
+    async deleteIssueView(issueViewId: IssueViewId) {
+      await axios.delete(`/api/issue-view/${issueViewId}`);
+    },
+  },
+});
@@ -0,0 +1,83 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  DatabaseId,
+  IssueId,
+  ManagedGrant,
+  ManagedGrantId,
+  ManagedGrantUpsert,
+  ResourceObject,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  managedGrant: ResourceObject,
+  includedList: ResourceObject[]
+): ManagedGrant {
+  return {
+    ...(managedGrant.attributes as Omit<
+      ManagedGrant,
+      "id" | "creator" | "updater"
+    >),
+    creator: getPrincipalFromIncludedList(
+      managedGrant.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      managedGrant.relationships!.updater.data,
+      includedList
+    ),
+    id: parseInt(managedGrant.id),
+  };
+}
+
+export const useManagedGrantStore = defineStore("managedGrant", {
+  actions: {
+    async fetchManagedGrantList(
+      databaseId: DatabaseId
+    ): Promise<ManagedGrant[]> {
+      const data = (
+        await axios.get(`/api/database/${databaseId}/managed-grant`)
+      ).data;
+      return data.data.map((managedGrant: ResourceObject) => {
+        return convert(managedGrant, data.included);
+      });
+    },
+    async upsertManagedGrant(
+      databaseId: DatabaseId,
+      upsert: ManagedGrantUpsert
+    ): Promise<ManagedGrant> {
+      const data = (
+        await axios.post(`/api/database/${databaseId}/managed-grant`, {
+          data: {
+            type: "managedGrantUpsert",
+            attributes: upsert,
+          },
+        })
+      ).data;
+      return convert(data.data, data.included);
+    },
+    async deleteManagedGrant(
+      databaseId: DatabaseId,
+      managedGrantId: ManagedGrantId
+    ) {
+      await axios.delete(
+        `/api/database/${databaseId}/managed-grant/${managedGrantId}`
+      );
+    },
+    // Creates the issue remediating the grant drift, and returns the issue ID and name.
+    async remediateManagedGrant(
+      databaseId: DatabaseId
+    ): Promise<{ id: IssueId; name: string }> {
+      const data = (
+        await axios.post(
+          `/api/database/${databaseId}/managed-grant/remediation`
+        )
+      ).data;
+      return {
+        id: parseInt(data.data.id),
+        name: data.data.attributes.name as string,
+      };
+    },
+  },
+});
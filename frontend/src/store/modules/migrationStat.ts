@@ -0,0 +1,29 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import { MigrationStat, MigrationStatFind, MigrationStatState } from "@/types";
+
+export const useMigrationStatStore = defineStore("migrationStat", {
+  state: (): MigrationStatState => ({
+    migrationStat: undefined,
+  }),
+  actions: {
+    setMigrationStat(migrationStat: MigrationStat) {
+      this.migrationStat = migrationStat;
+    },
+    async fetchMigrationStat(find: MigrationStatFind = {}) {
+      const data = (
+        await axios.get(`/api/migration-stat`, {
+          params: {
+            environment: find.environmentId,
+            project: find.projectId,
+            startTs: find.startTs,
+            endTs: find.endTs,
+          },
+        })
+      ).data.data;
+      const migrationStat = data.attributes as MigrationStat;
+      this.setMigrationStat(migrationStat);
+      return migrationStat;
+    },
+  },
+});
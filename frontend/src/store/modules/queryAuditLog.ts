@@ -0,0 +1,53 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import { stringify } from "qs";
+import {
+  QueryAuditLog,
+  QueryAuditLogExport,
+  QueryAuditLogFind,
+  ResourceObject,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  queryAuditLog: ResourceObject,
+  includedList: ResourceObject[]
+): QueryAuditLog {
+  return {
+    ...(queryAuditLog.attributes as Omit<QueryAuditLog, "id" | "creator">),
+    creator: getPrincipalFromIncludedList(
+      queryAuditLog.relationships!.creator.data,
+      includedList
+    ),
+    id: parseInt(queryAuditLog.id),
+  };
+}
+
+export const useQueryAuditLogStore = defineStore("queryAuditLog", {
+  actions: {
+    async fetchQueryAuditLogList(
+      find: QueryAuditLogFind
+    ): Promise<QueryAuditLog[]> {
+      const queryString = stringify(find);
+      const data = (await axios.get(`/api/query-audit-log?${queryString}`))
+        .data;
+      const queryAuditLogList: QueryAuditLog[] = data.data.map(
+        (queryAuditLog: ResourceObject) => {
+          return convert(queryAuditLog, data.included);
+        }
+      );
+      return queryAuditLogList;
+    },
+    getExportURL(find: QueryAuditLogFind): string {
+      return `/api/query-audit-log/export?${stringify(find)}`;
+    },
+    async reportExport(queryAuditLogExport: QueryAuditLogExport) {
+      await axios.post(`/api/query-audit-log/export`, {
+        data: {
+          type: "queryAuditLogCreate",
+          attributes: queryAuditLogExport,
+        },
+      });
+    },
+  },
+});
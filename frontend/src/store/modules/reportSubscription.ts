@@ -0,0 +1,58 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  ProjectId,
+  ReportSubscription,
+  ReportSubscriptionPatch,
+  ResourceObject,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  subscription: ResourceObject,
+  includedList: ResourceObject[]
+): ReportSubscription {
+  return {
+    ...(subscription.attributes as Omit<
+      ReportSubscription,
+      "id" | "creator" | "updater"
+    >),
+    creator: getPrincipalFromIncludedList(
+      subscription.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      subscription.relationships!.updater.data,
+      includedList
+    ),
+    id: parseInt(subscription.id),
+  };
+}
+
+export const useReportSubscriptionStore = defineStore("reportSubscription", {
+  actions: {
+    // Returns the subscription of the current user, which is disabled if not subscribed yet.
+    async fetchReportSubscription(
+      projectId: ProjectId
+    ): Promise<ReportSubscription> {
+      const data = (
+        await axios.get(`/api/project/${projectId}/report-subscription`)
+      ).data;
+      return convert(data.data, data.included);
+    },
+    async patchReportSubscription(
+      projectId: ProjectId,
+      patch: ReportSubscriptionPatch
+    ): Promise<ReportSubscription> {
+      const data = (
+        await axios.patch(`/api/project/${projectId}/report-subscription`, {
+          data: {
+            type: "reportSubscriptionPatch",
+            attributes: patch,
+          },
+        })
+      ).data;
+      return convert(data.data, data.included);
+    },
+  },
+});
@@ -1,10 +1,13 @@
 import { defineStore } from "pinia";
 import axios from "axios";
+import { stringify } from "qs";
 import {
   Project,
   ProjectId,
   Repository,
+  RepositoryCheckItem,
   RepositoryCreate,
+  RepositoryFile,
   RepositoryPatch,
   RepositoryState,
   ResourceIdentifier,
@@ -17,6 +20,13 @@ import { getPrincipalFromIncludedList } from "./principal";
 import { useProjectStore } from "./project";
 import { useVCSStore } from "./vcs";
 
+function convertFile(file: ResourceObject): RepositoryFile {
+  return {
+    ...(file.attributes as Omit<RepositoryFile, "path">),
+    path: file.id,
+  };
+}
+
 function convert(
   repository: ResourceObject,
   includedList: ResourceObject[]
@@ -158,6 +168,42 @@ export const useRepositoryStore = defineStore("repository", {
 
       return unknown("REPOSITORY") as Repository;
     },
+    async checkRepositoryByProjectId(
+      projectId: ProjectId
+    ): Promise<RepositoryCheckItem[]> {
+      const data = (
+        await axios.get(`/api/project/${projectId}/repository/check`)
+      ).data;
+      return data.data.attributes.itemList as RepositoryCheckItem[];
+    },
+    async fetchRepositoryFileListByProjectId({
+      projectId,
+      path,
+      ref,
+    }: {
+      projectId: ProjectId;
+      path?: string;
+      ref?: string;
+    }): Promise<RepositoryFile[]> {
+      const params = stringify({ path, ref });
+      const url = `/api/project/${projectId}/repository/file?${params}`;
+      const data = (await axios.get(url)).data;
+      return data.data.map((file: ResourceObject) => convertFile(file));
+    },
+    async fetchRepositoryFileByProjectId({
+      projectId,
+      path,
+      ref,
+    }: {
+      projectId: ProjectId;
+      path: string;
+      ref?: string;
+    }): Promise<RepositoryFile> {
+      const params = stringify({ path, ref });
+      const url = `/api/project/${projectId}/repository/file/content?${params}`;
+      const data = (await axios.get(url)).data;
+      return convertFile(data.data);
+    },
     async updateRepositoryByProjectId({
       projectId,
       repositoryPatch,
@@ -0,0 +1,82 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  DatabaseId,
+  ResourceObject,
+  SchemaSnapshot,
+  SchemaSnapshotDiff,
+  SchemaSnapshotId,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  schemaSnapshot: ResourceObject,
+  includedList: ResourceObject[]
+): SchemaSnapshot {
+  return {
+    ...(schemaSnapshot.attributes as Omit<
+      SchemaSnapshot,
+      "id" | "creator" | "updater"
+    >),
+    creator: getPrincipalFromIncludedList(
+      schemaSnapshot.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      schemaSnapshot.relationships!.updater.data,
+      includedList
+    ),
+    id: parseInt(schemaSnapshot.id),
+  };
+}
+
+export const useSchemaSnapshotStore = defineStore("schemaSnapshot", {
+  actions: {
+    async fetchSchemaSnapshotList(
+      databaseId: DatabaseId,
+      limit?: number
+    ): Promise<SchemaSnapshot[]> {
+      const data = (
+        await axios.get(`/api/database/${databaseId}/schema-snapshot`, {
+          params: { limit },
+        })
+      ).data;
+      return data.data.map((schemaSnapshot: ResourceObject) => {
+        return convert(schemaSnapshot, data.included);
+      });
+    },
+    async fetchSchemaSnapshotById(
+      databaseId: DatabaseId,
+      schemaSnapshotId: SchemaSnapshotId
+    ): Promise<SchemaSnapshot> {
+      const data = (
+        await axios.get(
+          `/api/database/${databaseId}/schema-snapshot/${schemaSnapshotId}`
+        )
+      ).data;
+      return convert(data.data, data.included);
+    },
+    // Fetches the schema as of the timestamp in seconds.
+    async fetchSchemaSnapshotAsOf(
+      databaseId: DatabaseId,
+      ts: number
+    ): Promise<SchemaSnapshot> {
+      const data = (
+        await axios.get(`/api/database/${databaseId}/schema-snapshot/as-of`, {
+          params: { ts },
+        })
+      ).data;
+      return convert(data.data, data.included);
+    },
+    async fetchSchemaSnapshotDiff(
+      fromSnapshotId: SchemaSnapshotId,
+      toSnapshotId: SchemaSnapshotId
+    ): Promise<SchemaSnapshotDiff> {
+      return (
+        await axios.get(`/api/schema-snapshot/diff`, {
+          params: { from: fromSnapshotId, to: toSnapshotId },
+        })
+      ).data;
+    },
+  },
+});
@@ -3,11 +3,14 @@ import axios from "axios";
 import {
   ConnectionInfo,
   DatabaseId,
+  EngineType,
   InstanceId,
   INSTANCE_OPERATION_TIMEOUT,
   QueryInfo,
   ResourceObject,
   SQLResultSet,
+  SQLFormatResult,
+  SQLResultColumn,
   Advice,
 } from "@/types";
 import { useDatabaseStore } from "./database";
@@ -18,6 +21,7 @@ function convert(resultSet: ResourceObject): SQLResultSet {
     data: JSON.parse((resultSet.attributes.data as string) || "null"),
     error: resultSet.attributes.error as string,
     adviceList: resultSet.attributes.adviceList as Advice[],
+    columnList: (resultSet.attributes.columnList as SQLResultColumn[]) || [],
   };
 }
 
@@ -92,6 +96,27 @@ export const useSQLStore = defineStore("sql", {
 
       return resultSet;
     },
+    async format(
+      engine: EngineType,
+      statement: string
+    ): Promise<SQLFormatResult> {
+      const res = (
+        await axios.post(`/api/sql/format`, {
+          data: {
+            type: "sqlFormat",
+            attributes: {
+              engine,
+              statement,
+            },
+          },
+        })
+      ).data;
+
+      return {
+        statement: res.data.attributes.statement as string,
+        error: res.data.attributes.error as string,
+      };
+    },
     async query(queryInfo: QueryInfo): Promise<SQLResultSet> {
       const res = (
         await axios.post(
@@ -116,6 +141,34 @@ export const useSQLStore = defineStore("sql", {
         throw new Error(resultSet.error);
       }
 
+      return resultSet;
+    },
+    // Executes the write statement, which requires the elevated write access
+    // of the database.
+    async execute(queryInfo: QueryInfo): Promise<SQLResultSet> {
+      const res = (
+        await axios.post(
+          `/api/sql/execute`,
+          {
+            data: {
+              type: "sqlExecute",
+              attributes: {
+                ...queryInfo,
+                readonly: false,
+              },
+            },
+          },
+          {
+            timeout: INSTANCE_OPERATION_TIMEOUT,
+          }
+        )
+      ).data;
+
+      const resultSet = convert(res.data);
+      if (resultSet.error) {
+        throw new Error(resultSet.error);
+      }
+
       return resultSet;
     },
   },
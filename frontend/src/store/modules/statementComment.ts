@@ -0,0 +1,91 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  IssueId,
+  ResourceObject,
+  StatementComment,
+  StatementCommentCreate,
+  StatementCommentId,
+  StatementCommentPatch,
+  TaskId,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  statementComment: ResourceObject,
+  includedList: ResourceObject[]
+): StatementComment {
+  return {
+    ...(statementComment.attributes as Omit<
+      StatementComment,
+      "id" | "creator" | "updater"
+    >),
+    creator: getPrincipalFromIncludedList(
+      statementComment.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      statementComment.relationships!.updater.data,
+      includedList
+    ),
+    id: parseInt(statementComment.id),
+  };
+}
+
+export const useStatementCommentStore = defineStore("statementComment", {
+  actions: {
+    // Returns the comments of the task if specified, otherwise all the comments of the issue.
+    async fetchStatementCommentList(
+      issueId: IssueId,
+      taskId?: TaskId
+    ): Promise<StatementComment[]> {
+      const url = taskId
+        ? `/api/issue/${issueId}/statement-comment?task=${taskId}`
+        : `/api/issue/${issueId}/statement-comment`;
+      const data = (await axios.get(url)).data;
+      return data.data.map((statementComment: ResourceObject) => {
+        return convert(statementComment, data.included);
+      });
+    },
+    async createStatementComment(
+      issueId: IssueId,
+      create: StatementCommentCreate
+    ): Promise<StatementComment> {
+      const data = (
+        await axios.post(`/api/issue/${issueId}/statement-comment`, {
+          data: {
+            type: "statementCommentCreate",
+            attributes: create,
+          },
+        })
+      ).data;
+      return convert(data.data, data.included);
+    },
+    async patchStatementComment(
+      issueId: IssueId,
+      statementCommentId: StatementCommentId,
+      patch: StatementCommentPatch
+    ): Promise<StatementComment> {
+      const data = (
+        await axios.patch(
+          `/api/issue/${issueId}/statement-comment/${statementCommentId}`,
+          {
+            data: {
+              type: "statementCommentPatch",
+              attributes: patch,
+            },
+          }
+        )
+      ).data;
+      return convert(data.data, data.included);
+    },
+    async deleteStatementComment(
+      issueId: IssueId,
+      statementCommentId: StatementCommentId
+    ) {
+      await axios.delete(
+        `/api/issue/${issueId}/statement-comment/${statementCommentId}`
+      );
+    },
+  },
+});
@@ -58,7 +58,14 @@ export const useSubscriptionStore = defineStore("subscription", {
   },
   actions: {
     hasFeature(type: FeatureType) {
-      return FEATURE_MATRIX.get(type)![this.currentPlan];
+      if (FEATURE_MATRIX.get(type)![this.currentPlan]) {
+        return true;
+      }
+      // The license can grant features in addition to the plan.
+      if (this.isExpired) {
+        return false;
+      }
+      return !!this.subscription?.features?.includes(type);
     },
     setSubscription(subscription: Subscription) {
       this.subscription = subscription;
@@ -88,6 +95,15 @@ export const useSubscriptionStore = defineStore("subscription", {
       this.setSubscription(subscription);
       return subscription;
     },
+    async uploadLicenseFile(file: File) {
+      const formData = new FormData();
+      formData.append("file", file);
+      const data = (await axios.post(`/api/subscription/license`, formData))
+        .data.data;
+      const subscription = data.attributes as Subscription;
+      this.setSubscription(subscription);
+      return subscription;
+    },
   },
 });
 
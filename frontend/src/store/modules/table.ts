@@ -8,6 +8,7 @@ import {
   ResourceObject,
   Table,
   TableIndex,
+  TableIndexPatch,
   TableState,
   unknown,
 } from "@/types";
@@ -132,5 +133,55 @@ export const useTableStore = defineStore("table", {
       this.setTableListByDatabaseId({ databaseId, tableList });
       return tableList;
     },
+
+    async patchTableIndex({
+      databaseId,
+      tableName,
+      indexName,
+      indexPatch,
+    }: {
+      databaseId: DatabaseId;
+      tableName: string;
+      indexName: string;
+      indexPatch: TableIndexPatch;
+    }) {
+      const data = (
+        await axios.patch(
+          `/api/database/${databaseId}/table/${tableName}/index/${indexName}`,
+          {
+            data: {
+              type: "indexPatch",
+              attributes: indexPatch,
+            },
+          }
+        )
+      ).data;
+      const table = convert(data.data, data.included);
+
+      this.setTableByDatabaseIdAndTableName({
+        databaseId,
+        tableName,
+        table,
+      });
+      return table;
+    },
+
+    async fetchTableIndexDDL({
+      databaseId,
+      tableName,
+      indexName,
+    }: {
+      databaseId: DatabaseId;
+      tableName: string;
+      indexName?: string;
+    }): Promise<string> {
+      const data = (
+        await axios.get(
+          `/api/database/${databaseId}/table/${tableName}/index-ddl`,
+          { params: { index: indexName } }
+        )
+      ).data;
+      return data.statement;
+    },
   },
 });
@@ -12,12 +12,17 @@ import {
   Stage,
   StageAllTaskStatusPatch,
   StageId,
+  StagePatch,
   Task,
+  TaskCheckResultSuppress,
   TaskCheckRun,
+  TaskCheckRunId,
   TaskId,
   TaskPatch,
   TaskProgress,
   TaskRun,
+  TaskRunArtifact,
+  TaskRunProgress,
   TaskState,
   TaskStatusPatch,
   unknown,
@@ -37,11 +42,14 @@ function convertTaskRun(
   const payload = taskRun.attributes.payload
     ? JSON.parse((taskRun.attributes.payload as string) || "{}")
     : {};
+  const progress = taskRun.attributes.progress
+    ? JSON.parse((taskRun.attributes.progress as string) || "{}")
+    : {};
 
   return {
     ...(taskRun.attributes as Omit<
       TaskRun,
-      "id" | "result" | "payload" | "creator" | "updater"
+      "id" | "result" | "payload" | "progress" | "creator" | "updater"
     >),
     id: parseInt(taskRun.id),
     creator: getPrincipalFromIncludedList(
@@ -54,6 +62,21 @@ function convertTaskRun(
     ),
     result,
     payload,
+    progress,
+  };
+}
+
+function convertTaskRunArtifact(
+  artifact: ResourceObject,
+  includedList: ResourceObject[]
+): TaskRunArtifact {
+  return {
+    ...(artifact.attributes as Omit<TaskRunArtifact, "id" | "creator">),
+    id: parseInt(artifact.id),
+    creator: getPrincipalFromIncludedList(
+      artifact.relationships!.creator.data,
+      includedList
+    ),
   };
 }
 
@@ -250,6 +273,24 @@ export const useTaskStore = defineStore("task", {
 
       return task;
     },
+    async pauseTask({
+      issueId,
+      pipelineId,
+      taskId,
+    }: {
+      issueId: IssueId;
+      pipelineId: PipelineId;
+      taskId: TaskId;
+    }) {
+      const data = (
+        await axios.post(`/api/pipeline/${pipelineId}/task/${taskId}/pause`)
+      ).data;
+      const task = this.convertPartial(data.data, data.included);
+
+      useIssueStore().fetchIssueById(issueId);
+
+      return task;
+    },
     async updateStageAllTaskStatus({
       issue,
       stage,
@@ -272,6 +313,51 @@ export const useTaskStore = defineStore("task", {
 
       useIssueStore().fetchIssueById(issue.id);
     },
+    async patchStage({
+      issue,
+      stage,
+      patch,
+    }: {
+      issue: Issue;
+      stage: Stage;
+      patch: StagePatch;
+    }) {
+      const { pipeline } = stage;
+      await axios.patch(`/api/pipeline/${pipeline.id}/stage/${stage.id}`, {
+        data: {
+          type: "stagePatch",
+          attributes: patch,
+        },
+      });
+
+      useIssueStore().fetchIssueById(issue.id);
+    },
+    async fetchTaskRunArtifactList(task: Task): Promise<TaskRunArtifact[]> {
+      const data = (
+        await axios.get(
+          `/api/pipeline/${task.pipeline.id}/task/${task.id}/artifact`
+        )
+      ).data;
+      const artifactList: TaskRunArtifact[] = data.data.map(
+        (artifact: ResourceObject) => {
+          return convertTaskRunArtifact(artifact, data.included);
+        }
+      );
+
+      return artifactList;
+    },
+    async fetchTaskRunProgress(
+      task: Task,
+      taskRun: TaskRun
+    ): Promise<TaskRunProgress> {
+      const data = (
+        await axios.get(
+          `/api/pipeline/${task.pipeline.id}/task/${task.id}/run/${taskRun.id}/progress`
+        )
+      ).data;
+
+      return data.data.attributes as TaskRunProgress;
+    },
     async patchTask({
       issueId,
       pipelineId,
@@ -333,5 +419,47 @@ export const useTaskStore = defineStore("task", {
 
       return task;
     },
+    async suppressCheckResult({
+      issueId,
+      pipelineId,
+      taskId,
+      taskCheckRunId,
+      suppress,
+    }: {
+      issueId: IssueId;
+      pipelineId: PipelineId;
+      taskId: TaskId;
+      taskCheckRunId: TaskCheckRunId;
+      suppress: TaskCheckResultSuppress;
+    }) {
+      await axios.post(
+        `/api/pipeline/${pipelineId}/task/${taskId}/check/${taskCheckRunId}/suppress`,
+        {
+          data: {
+            type: "taskCheckResultSuppress",
+            attributes: suppress,
+          },
+        }
+      );
+
+      useIssueStore().fetchIssueById(issueId);
+    },
+    async cancelCheckRun({
+      issueId,
+      pipelineId,
+      taskId,
+      taskCheckRunId,
+    }: {
+      issueId: IssueId;
+      pipelineId: PipelineId;
+      taskId: TaskId;
+      taskCheckRunId: TaskCheckRunId;
+    }) {
+      await axios.patch(
+        `/api/pipeline/${pipelineId}/task/${taskId}/check-run/${taskCheckRunId}/cancel`
+      );
+
+      useIssueStore().fetchIssueById(issueId);
+    },
   },
 });
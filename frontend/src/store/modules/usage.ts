@@ -0,0 +1,20 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import { UsageState, WorkspaceUsage } from "@/types";
+
+export const useUsageStore = defineStore("usage", {
+  state: (): UsageState => ({
+    usage: undefined,
+  }),
+  actions: {
+    setUsage(usage: WorkspaceUsage) {
+      this.usage = usage;
+    },
+    async fetchUsage() {
+      const data = (await axios.get(`/api/usage`)).data.data;
+      const usage = data.attributes as WorkspaceUsage;
+      this.setUsage(usage);
+      return usage;
+    },
+  },
+});
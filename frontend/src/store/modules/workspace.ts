@@ -0,0 +1,129 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  ResourceObject,
+  RowStatus,
+  Workspace,
+  WorkspaceConfigApplyResult,
+  WorkspaceCreate,
+  WorkspaceId,
+  WorkspacePatch,
+  WorkspaceState,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  workspace: ResourceObject,
+  includedList: ResourceObject[]
+): Workspace {
+  return {
+    ...(workspace.attributes as Omit<Workspace, "id" | "creator" | "updater">),
+    id: parseInt(workspace.id),
+    creator: getPrincipalFromIncludedList(
+      workspace.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      workspace.relationships!.updater.data,
+      includedList
+    ),
+  };
+}
+
+export const useWorkspaceStore = defineStore("workspace", {
+  state: (): WorkspaceState => ({
+    workspaceList: [],
+  }),
+
+  actions: {
+    setWorkspaceList(workspaceList: Workspace[]) {
+      this.workspaceList = workspaceList;
+    },
+
+    upsertWorkspace(workspace: Workspace) {
+      const i = this.workspaceList.findIndex(
+        (item: Workspace) => item.id == workspace.id
+      );
+      if (i != -1) {
+        this.workspaceList[i] = workspace;
+      } else {
+        this.workspaceList.push(workspace);
+      }
+    },
+
+    // The owners of the default workspace get all workspaces,
+    // the others only get their own workspace.
+    async fetchWorkspaceList(rowStatus?: RowStatus) {
+      const path =
+        "/api/workspace" +
+        (rowStatus ? "?rowstatus=" + rowStatus : "");
+      const data = (await axios.get(path)).data;
+      const workspaceList = data.data.map((workspace: ResourceObject) => {
+        return convert(workspace, data.included);
+      });
+      this.setWorkspaceList(workspaceList);
+      return workspaceList;
+    },
+
+    async createWorkspace(newWorkspace: WorkspaceCreate) {
+      const data = (
+        await axios.post(`/api/workspace`, {
+          data: {
+            type: "WorkspaceCreate",
+            attributes: newWorkspace,
+          },
+        })
+      ).data;
+      const createdWorkspace = convert(data.data, data.included);
+      this.upsertWorkspace(createdWorkspace);
+      return createdWorkspace;
+    },
+
+    async patchWorkspace({
+      workspaceId,
+      workspacePatch,
+    }: {
+      workspaceId: WorkspaceId;
+      workspacePatch: WorkspacePatch;
+    }) {
+      const data = (
+        await axios.patch(`/api/workspace/${workspaceId}`, {
+          data: {
+            type: "workspacePatch",
+            attributes: workspacePatch,
+          },
+        })
+      ).data;
+      const updatedWorkspace = convert(data.data, data.included);
+      this.upsertWorkspace(updatedWorkspace);
+      return updatedWorkspace;
+    },
+
+    // Exports the config of the current workspace as a YAML bundle.
+    async exportWorkspaceConfig(): Promise<string> {
+      return (
+        await axios.get(`/api/workspace/config`, {
+          responseType: "text",
+        })
+      ).data;
+    },
+
+    // Applies the YAML bundle to the current workspace, nothing is changed
+    // with validateOnly.
+    async applyWorkspaceConfig(
+      config: string,
+      validateOnly: boolean
+    ): Promise<WorkspaceConfigApplyResult> {
+      return (
+        await axios.post(
+          `/api/workspace/config` +
+            (validateOnly ? "?validateOnly=true" : ""),
+          config,
+          {
+            headers: { "Content-Type": "application/x-yaml" },
+          }
+        )
+      ).data;
+    },
+  },
+});
@@ -1,8 +1,18 @@
 import { FieldId } from "../plugins";
-import { ActivityId, ContainerId, PrincipalId, TaskId } from "./id";
+import {
+  ActivityId,
+  ContainerId,
+  DatabaseId,
+  DBQueryGrantId,
+  PrincipalId,
+  StageId,
+  TaskCheckRunId,
+  TaskId,
+} from "./id";
+import { DBQueryGrantStatus } from "./dbQueryGrant";
 import { IssueStatus } from "./issue";
 import { MemberStatus, RoleType } from "./member";
-import { TaskStatus } from "./pipeline";
+import { TaskCheckStatus, TaskStatus } from "./pipeline";
 import { Principal } from "./principal";
 import { VCSPushEvent } from "./vcs";
 import { t } from "../plugins/i18n";
@@ -15,7 +25,10 @@ export type IssueActivityType =
   | "bb.pipeline.task.status.update"
   | "bb.pipeline.task.file.commit"
   | "bb.pipeline.task.statement.update"
-  | "bb.pipeline.task.general.earliest-allowed-time.update";
+  | "bb.pipeline.task.general.earliest-allowed-time.update"
+  | "bb.pipeline.task.check-result.suppress"
+  | "bb.pipeline.stage.pause"
+  | "bb.pipeline.stage.resume";
 
 export type MemberActivityType =
   | "bb.member.create"
@@ -30,13 +43,21 @@ export type ProjectActivityType =
   | "bb.project.member.delete"
   | "bb.project.member.role.update";
 
-export type DatabaseActivityType = "bb.database.recovery.pitr.done";
+export type DatabaseActivityType =
+  | "bb.database.recovery.pitr.done"
+  | "bb.database.write-grant.update";
+
+export type InstanceActivityType = "bb.instance.environment.update";
+
+export type SettingActivityType = "bb.setting.maintenance.update";
 
 export type ActivityType =
   | IssueActivityType
   | MemberActivityType
   | ProjectActivityType
-  | DatabaseActivityType;
+  | DatabaseActivityType
+  | InstanceActivityType
+  | SettingActivityType;
 
 export function activityName(type: ActivityType): string {
   switch (type) {
@@ -56,6 +77,12 @@ export function activityName(type: ActivityType): string {
       return t("activity.type.pipeline-task-statement-update");
     case "bb.pipeline.task.general.earliest-allowed-time.update":
       return t("activity.type.pipeline-task-earliest-allowed-time-update");
+    case "bb.pipeline.task.check-result.suppress":
+      return t("activity.type.pipeline-task-check-result-suppress");
+    case "bb.pipeline.stage.pause":
+      return t("activity.type.pipeline-stage-pause");
+    case "bb.pipeline.stage.resume":
+      return t("activity.type.pipeline-stage-resume");
     case "bb.member.create":
       return t("activity.type.member-create");
     case "bb.member.role.update":
@@ -76,6 +103,12 @@ export function activityName(type: ActivityType): string {
       return t("activity.type.project-member-role-update");
     case "bb.database.recovery.pitr.done":
       return t("activity.type.database-recovery-pitr-done");
+    case "bb.database.write-grant.update":
+      return t("activity.type.database-write-grant-update");
+    case "bb.instance.environment.update":
+      return t("activity.type.instance-environment-update");
+    case "bb.setting.maintenance.update":
+      return t("activity.type.setting-maintenance-update");
   }
 }
 
@@ -110,6 +143,12 @@ export type ActivityTaskStatusUpdatePayload = {
   taskName: string;
 };
 
+export type ActivityStagePauseResumePayload = {
+  stageId: StageId;
+  issueName: string;
+  stageName: string;
+};
+
 export type ActivityTaskFileCommitPayload = {
   taskId: TaskId;
   vcsInstanceUrl: string;
@@ -135,6 +174,16 @@ export type ActivityTaskEarliestAllowedTimeUpdatePayload = {
   taskName: string;
 };
 
+export type ActivityTaskCheckResultSuppressPayload = {
+  taskId: TaskId;
+  taskCheckRunId: TaskCheckRunId;
+  status: TaskCheckStatus;
+  title: string;
+  content: string;
+  issueName: string;
+  taskName: string;
+};
+
 export type ActivityMemberCreatePayload = {
   principalId: PrincipalId;
   principalName: string;
@@ -169,6 +218,33 @@ export type ActivityProjectDatabaseTransferPayload = {
   databaseName: string;
 };
 
+export type ActivityDatabaseWriteGrantUpdatePayload = {
+  databaseId: DatabaseId;
+  grantId: DBQueryGrantId;
+  principalId: PrincipalId;
+  status: DBQueryGrantStatus;
+  expireTs: number;
+  databaseName: string;
+};
+
+export type ActivityInstanceEnvironmentUpdatePayload = {
+  oldEnvironmentId: number;
+  newEnvironmentId: number;
+  instanceName: string;
+  oldEnvironmentName: string;
+  newEnvironmentName: string;
+  approvalPolicy: string;
+  backupPlanSchedule: string;
+  backupSettingUpdatedDatabaseList: string[];
+};
+
+export type ActivitySettingMaintenanceUpdatePayload = {
+  oldEnabled: boolean;
+  newEnabled: boolean;
+  oldMessage: string;
+  newMessage: string;
+};
+
 export type ActionPayloadType =
   | ActivityIssueCreatePayload
   | ActivityIssueCommentCreatePayload
@@ -178,11 +254,16 @@ export type ActionPayloadType =
   | ActivityTaskFileCommitPayload
   | ActivityTaskStatementUpdatePayload
   | ActivityTaskEarliestAllowedTimeUpdatePayload
+  | ActivityTaskCheckResultSuppressPayload
+  | ActivityStagePauseResumePayload
   | ActivityMemberCreatePayload
   | ActivityMemberRoleUpdatePayload
   | ActivityMemberActivateDeactivatePayload
   | ActivityProjectRepositoryPushPayload
-  | ActivityProjectDatabaseTransferPayload;
+  | ActivityProjectDatabaseTransferPayload
+  | ActivityDatabaseWriteGrantUpdatePayload
+  | ActivityInstanceEnvironmentUpdatePayload
+  | ActivitySettingMaintenanceUpdatePayload;
 
 export type Activity = {
   id: ActivityId;
@@ -1,3 +1,5 @@
+import { MaintenanceSetting } from "./setting";
+
 export type ServerInfo = {
   version: string;
   gitCommit: string;
@@ -7,5 +9,6 @@ export type ServerInfo = {
   host: string;
   port: string;
   needAdminSetup: boolean;
+  maintenance: MaintenanceSetting;
   startedTs: number;
 };
@@ -6,21 +6,30 @@ import {
   EnvironmentId,
   Instance,
   InstanceId,
+  ManagedGrantDrift,
   Principal,
 } from ".";
 
 export type AnomalyType =
   | "bb.anomaly.instance.connection"
   | "bb.anomaly.instance.migration-schema"
+  | "bb.anomaly.instance.disk-full"
   | "bb.anomaly.database.backup.policy-violation"
   | "bb.anomaly.database.backup.missing"
   | "bb.anomaly.database.connection"
-  | "bb.anomaly.database.schema.drift";
+  | "bb.anomaly.database.schema.drift"
+  | "bb.anomaly.database.grant.drift";
 
 export type AnomalyInstanceConnectionPayload = {
   detail: string;
 };
 
+export type AnomalyInstanceDiskFullPayload = {
+  totalBytes: number;
+  freeBytes: number;
+  daysToFull: number;
+};
+
 export type AnomalyDatabaseBackupPolicyViolationPayload = {
   environmentId: EnvironmentId;
   expectedSchedule: BackupPlanPolicySchedule;
@@ -42,11 +51,17 @@ export type AnomalyDatabaseSchemaDriftPayload = {
   actual: string;
 };
 
+export type AnomalyDatabaseGrantDriftPayload = {
+  driftList: ManagedGrantDrift[];
+};
+
 export type AnomalyPayload =
+  | AnomalyInstanceDiskFullPayload
   | AnomalyDatabaseBackupPolicyViolationPayload
   | AnomalyDatabaseBackupMissingPayload
   | AnomalyDatabaseConnectionPayload
-  | AnomalyDatabaseSchemaDriftPayload;
+  | AnomalyDatabaseSchemaDriftPayload
+  | AnomalyDatabaseGrantDriftPayload;
 
 export type AnomalySeverity = "MEDIUM" | "HIGH" | "CRITICAL";
 
@@ -262,6 +262,11 @@ const makeUnknown = (type: ResourceType) => {
     engine: "MYSQL",
     engineVersion: "",
     host: "",
+    backupStorageBackend: "",
+    sshHost: "",
+    sshPort: "",
+    sshUser: "",
+    sshHostKey: "",
   };
 
   const UNKNOWN_DATABASE: Database = {
@@ -273,6 +278,7 @@ const makeUnknown = (type: ResourceType) => {
     labels: [],
     dataSourceList: [],
     anomalyList: [],
+    rowStatus: "NORMAL",
     creator: UNKNOWN_PRINCIPAL,
     createdTs: 0,
     updater: UNKNOWN_PRINCIPAL,
@@ -283,6 +289,7 @@ const makeUnknown = (type: ResourceType) => {
     syncStatus: "NOT_FOUND",
     lastSuccessfulSyncTs: 0,
     schemaVersion: "",
+    connectionOption: "{}",
   };
 
   const UNKNOWN_DATA_SOURCE: DataSource = {
@@ -666,6 +673,11 @@ const makeEmpty = (type: ResourceType) => {
     engine: "MYSQL",
     engineVersion: "",
     host: "",
+    backupStorageBackend: "",
+    sshHost: "",
+    sshPort: "",
+    sshUser: "",
+    sshHostKey: "",
   };
 
   const EMPTY_DATABASE: Database = {
@@ -677,6 +689,7 @@ const makeEmpty = (type: ResourceType) => {
     dataSourceList: [],
     anomalyList: [],
     labels: [],
+    rowStatus: "NORMAL",
     creator: EMPTY_PRINCIPAL,
     createdTs: 0,
     updater: EMPTY_PRINCIPAL,
@@ -687,6 +700,7 @@ const makeEmpty = (type: ResourceType) => {
     syncStatus: "NOT_FOUND",
     lastSuccessfulSyncTs: 0,
     schemaVersion: "",
+    connectionOption: "{}",
   };
 
   const EMPTY_DATA_SOURCE: DataSource = {
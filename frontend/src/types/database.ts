@@ -4,6 +4,7 @@
 
 import { Anomaly } from ".";
 import { Backup } from "./backup";
+import { RowStatus } from "./common";
 import { DataSource } from "./dataSource";
 import { DatabaseId, InstanceId, IssueId, ProjectId } from "./id";
 import { Instance } from "./instance";
@@ -28,6 +29,7 @@ export type Database = {
   anomalyList: Anomaly[];
 
   // Standard fields
+  rowStatus: RowStatus;
   creator: Principal;
   createdTs: number;
   updater: Principal;
@@ -41,6 +43,8 @@ export type Database = {
   collation: string;
   schemaVersion: string;
   labels: DatabaseLabel[];
+  // JSON encoded DatabaseConnectionOption.
+  connectionOption: string;
 };
 
 export type DatabaseCreate = {
@@ -70,4 +74,7 @@ export type DatabasePatch = {
   // Related fields
   projectId?: ProjectId;
   labels?: DatabaseLabel[];
+
+  // Domain specific fields
+  connectionOption?: string;
 };
@@ -0,0 +1,25 @@
+import { DatabaseId, DatabaseSecretId } from "./id";
+import { Principal } from "./principal";
+
+// The secret is referenced in the statements as {{secret.NAME}}, its value is never returned.
+export type DatabaseSecret = {
+  id: DatabaseSecretId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  databaseId: DatabaseId;
+
+  // Domain specific fields
+  name: string;
+  description: string;
+};
+
+export type DatabaseSecretUpsert = {
+  value: string;
+  description: string;
+};
@@ -0,0 +1,55 @@
+import { DatabaseTemplateId, SheetId } from "./id";
+import { EngineType } from "./instance";
+import { Principal } from "./principal";
+
+// The template provisions the new databases with the same character set, collation, extensions, grants and baseline schema.
+export type DatabaseTemplate = {
+  id: DatabaseTemplateId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  // Baseline schema sheet, undefined means an empty database.
+  sheetId?: SheetId;
+
+  // Domain specific fields
+  name: string;
+  engine: EngineType;
+  characterSet: string;
+  collation: string;
+  // Only applicable to PostgreSQL.
+  extensionList: string[];
+  // Only applicable to MySQL, TiDB and PostgreSQL, MySQL and TiDB grantees are in the form of "user@host".
+  granteeList: string[];
+};
+
+export type DatabaseTemplateCreate = {
+  // Related fields
+  sheetId?: SheetId;
+
+  // Domain specific fields
+  name: string;
+  engine: EngineType;
+  characterSet: string;
+  collation: string;
+  extensionList: string[];
+  granteeList: string[];
+};
+
+export type DatabaseTemplatePatch = {
+  // Related fields
+  // 0 clears the baseline schema.
+  sheetId?: SheetId;
+
+  // Domain specific fields
+  name?: string;
+  characterSet?: string;
+  collation?: string;
+  // Comma separated
+  extensionList?: string;
+  granteeList?: string;
+};
@@ -0,0 +1,59 @@
+import { DatabaseId, DBQueryGrantId, PrincipalId } from "./id";
+import { RoleType } from "./member";
+import { Principal } from "./principal";
+
+export type DBQueryGrantStatus =
+  | "PENDING"
+  | "APPROVED"
+  | "REJECTED"
+  | "REVOKED"
+  | "EXPIRED";
+
+// The WRITE grant is the temporary elevated access to execute the write
+// statements in the SQL editor, which is required by everyone and approved by
+// someone other than the grantee.
+export type DBQueryGrantAccess = "QUERY" | "WRITE";
+
+// The query grant allows the developers to query the database in the SQL
+// editor once approved until it expires. The grantee is either a principal or
+// all the members of a role. The owners and the DBAs don't need any QUERY
+// grant.
+export type DBQueryGrant = {
+  id: DBQueryGrantId;
+
+  // Standard fields
+  // The creator is the requester, and the updater is the approver once
+  // approved.
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  databaseId: DatabaseId;
+
+  // Domain specific fields
+  // 0 for the role grant.
+  principalId: PrincipalId;
+  // Empty for the principal grant.
+  role: RoleType | "";
+  status: DBQueryGrantStatus;
+  expireTs: number;
+  reason: string;
+  access: DBQueryGrantAccess;
+};
+
+// Exactly one of principalId and role is set, the developers can only request
+// for themselves.
+export type DBQueryGrantCreate = {
+  principalId?: PrincipalId;
+  role?: RoleType;
+  expireTs: number;
+  reason: string;
+  // QUERY by default.
+  access?: DBQueryGrantAccess;
+};
+
+export type DBQueryGrantPatch = {
+  status: DBQueryGrantStatus;
+};
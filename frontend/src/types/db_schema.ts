@@ -0,0 +1,21 @@
+import { Database } from "./database";
+import { DBSchemaId } from "./id";
+import { Principal } from "./principal";
+
+// DBSchema is a Postgres schema of the database.
+export type DBSchema = {
+  id: DBSchemaId;
+
+  // Related fields
+  database: Database;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Domain specific fields
+  name: string;
+  owner: string;
+};
@@ -29,10 +29,30 @@ export type TaskId = IdType;
 
 export type TaskRunId = IdType;
 
+export type TaskRunArtifactId = IdType;
+
 export type TaskCheckRunId = IdType;
 
 export type ActivityId = IdType;
 
+export type QueryAuditLogId = IdType;
+
+export type DatabaseSecretId = IdType;
+
+export type DatabaseTemplateId = IdType;
+
+export type ManagedGrantId = IdType;
+
+export type DBQueryGrantId = IdType;
+
+export type IssueViewId = IdType;
+
+export type StatementCommentId = IdType;
+
+export type ReportSubscriptionId = IdType;
+
+export type SchemaSnapshotId = IdType;
+
 export type InboxId = IdType;
 
 export type EnvironmentId = IdType;
@@ -41,6 +61,8 @@ export type InstanceId = IdType;
 
 export type InstanceUserId = IdType;
 
+export type InstanceAgentId = IdType;
+
 export type DataSourceId = IdType;
 
 export type DatabaseId = IdType;
@@ -51,6 +73,8 @@ export type ViewId = IdType;
 
 export type DBExtensionId = IdType;
 
+export type DBSchemaId = IdType;
+
 export type ColumnId = IdType;
 
 export type TableIndexId = IdType;
@@ -80,3 +104,5 @@ export type ContainerId = IssueId;
 export type LabelId = IdType;
 
 export type DeploymentConfigId = IdType;
+
+export type WorkspaceId = IdType;
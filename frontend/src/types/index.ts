@@ -7,6 +7,10 @@ export * from "./bookmark";
 export * from "./column";
 export * from "./common";
 export * from "./database";
+export * from "./databaseSecret";
+export * from "./databaseTemplate";
+export * from "./managedGrant";
+export * from "./dbQueryGrant";
 export * from "./dataSource";
 export * from "./environment";
 export * from "./error";
@@ -15,8 +19,10 @@ export * from "./help";
 export * from "./id";
 export * from "./inbox";
 export * from "./instance";
+export * from "./instanceAgent";
 export * from "./issue";
 export * from "./issueSubscriber";
+export * from "./issueView";
 export * from "./jsonapi";
 export * from "./member";
 export * from "./notification";
@@ -27,7 +33,9 @@ export * from "./policy";
 export * from "./principal";
 export * from "./project";
 export * from "./projectWebhook";
+export * from "./reportSubscription";
 export * from "./repository";
+export * from "./schemaSnapshot";
 export * from "./sql";
 export * from "./store";
 export * from "./table";
@@ -35,13 +43,20 @@ export * from "./tableIndex";
 export * from "./vcs";
 export * from "./view";
 export * from "./db_extension";
+export * from "./db_schema";
 export * from "./label";
 export * from "./deployment";
 export * from "./sqlEditor";
 export * from "./tab";
 export * from "./subscription";
+export * from "./usage";
+export * from "./migrationStat";
+export * from "./issueSLA";
+export * from "./workspace";
 export * from "./sheet";
 export * from "./sheetOrganizer";
 export * from "./sqlReview";
+export * from "./statementComment";
+export * from "./queryAuditLog";
 export * from "./utils";
 export * from "./onboardingGuide";
@@ -1,4 +1,5 @@
 import { Anomaly, DataSource } from ".";
+import { BackupStorageBackend } from "./backup";
 import { RowStatus } from "./common";
 import { Environment } from "./environment";
 import { EnvironmentId, InstanceId, MigrationHistoryId } from "./id";
@@ -64,6 +65,15 @@ export type Instance = {
   externalLink?: string;
   host: string;
   port?: string;
+  // Empty means the storage backend configured for the server.
+  backupStorageBackend: BackupStorageBackend | "";
+  // Empty means connecting the instance directly instead of the SSH tunnel.
+  // The SSH private key is not returned to the client.
+  sshHost: string;
+  sshPort: string;
+  sshUser: string;
+  // The public key of the SSH server in the authorized_keys format, or its SHA256 fingerprint.
+  sshHostKey: string;
 };
 
 export type InstanceCreate = {
@@ -82,6 +92,12 @@ export type InstanceCreate = {
   sslCa?: string;
   sslCert?: string;
   sslKey?: string;
+  backupStorageBackend?: BackupStorageBackend | "";
+  sshHost?: string;
+  sshPort?: string;
+  sshUser?: string;
+  sshPrivateKey?: string;
+  sshHostKey?: string;
 
   syncSchema: boolean;
 };
@@ -95,6 +111,13 @@ export type InstancePatch = {
   externalLink?: string;
   host?: string;
   port?: string;
+  backupStorageBackend?: BackupStorageBackend | "";
+  // Setting sshHost to empty disables the SSH tunnel.
+  sshHost?: string;
+  sshPort?: string;
+  sshUser?: string;
+  sshPrivateKey?: string;
+  sshHostKey?: string;
   syncSchema?: boolean;
 };
 
@@ -135,3 +158,19 @@ export type MigrationHistory = {
   issueId: number;
   payload?: MigrationHistoryPayload;
 };
+
+// The execution of a statement of the migration history, recorded if the statements are executed one by one.
+export type MigrationHistoryStatement = {
+  id: number;
+  createdTs: number;
+  databaseId: number;
+  migrationHistoryId: MigrationHistoryId;
+  // The 1-based position of the statement in the migration.
+  statementIndex: number;
+  statement: string;
+  durationNs: number;
+  rowsAffected: number;
+};
+
+// The format of the migration history recorded by other migration tools.
+export type MigrationHistoryImportFormat = "FLYWAY" | "LIQUIBASE";
@@ -0,0 +1,21 @@
+import { InstanceAgentId, InstanceId } from "./id";
+import { Principal } from "./principal";
+
+// Bytebase connects to the instance in agent mode through the agent running inside its private network.
+export type InstanceAgent = {
+  id: InstanceAgentId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  instanceId: InstanceId;
+
+  // Domain specific fields
+  // Only returned when the agent is created or its token is regenerated.
+  token?: string;
+  connected: boolean;
+};
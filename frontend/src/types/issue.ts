@@ -1,6 +1,7 @@
 import {
   BackupId,
   DatabaseId,
+  DatabaseTemplateId,
   InstanceId,
   IssueId,
   PrincipalId,
@@ -11,6 +12,7 @@ import { Principal } from "./principal";
 import { Project } from "./project";
 import { MigrationType } from "./instance";
 import { VCSPushEvent } from "./vcs";
+import { LabelSelector } from "./deployment";
 
 type IssueTypeGeneral = "bb.issue.general";
 
@@ -42,6 +44,7 @@ export type CreateDatabaseContext = {
   backupId?: BackupId;
   backupName?: string;
   labels?: string; // JSON encoded
+  templateId?: DatabaseTemplateId;
 };
 
 export type UpdateSchemaDetail = {
@@ -49,6 +52,9 @@ export type UpdateSchemaDetail = {
   databaseName: string;
   statement: string;
   earliestAllowedTs: number;
+  timeoutSeconds?: number;
+  // Postgres only, empty for the default search_path.
+  schemaName?: string;
 };
 
 export type UpdateSchemaGhostDetail = UpdateSchemaDetail & {
@@ -59,7 +65,22 @@ export type UpdateSchemaGhostDetail = UpdateSchemaDetail & {
 export type UpdateSchemaContext = {
   migrationType: MigrationType;
   updateSchemaDetailList: UpdateSchemaDetail[];
+  // Shorthand of updateSchemaDetailList for applying the same statement to
+  // multiple databases, mutually exclusive to updateSchemaDetailList.
+  databaseIdList?: DatabaseId[];
+  statement?: string;
   vcsPushEvent?: VCSPushEvent;
+  // Only for tenant mode projects.
+  canary?: CanaryConfig;
+};
+
+// The canary databases are updated in the first stage, the remaining databases
+// wait for the soak time, or the manual confirmation if soakSeconds is 0.
+// percentage and selector are mutually exclusive.
+export type CanaryConfig = {
+  percentage?: number;
+  selector?: LabelSelector;
+  soakSeconds: number;
 };
 
 export type UpdateSchemaGhostContext = {
@@ -85,6 +106,19 @@ export type IssueCreateContext =
 
 export type IssuePayload = { [key: string]: any };
 
+export type IssueReferenceType = "ISSUE" | "DATABASE" | "SHEET";
+
+// A reference in the issue description resolved to the target, e.g. #123,
+// db:prod/orders and sheet:123.
+export type IssueReference = {
+  type: IssueReferenceType;
+  // As written in the description
+  text: string;
+  // The ID of the issue, the database or the sheet by the type
+  targetId: number;
+  name: string;
+};
+
 export type Issue = {
   id: IssueId;
 
@@ -106,6 +140,9 @@ export type Issue = {
   assignee: Principal;
   subscriberList: Principal[];
   payload: IssuePayload;
+  // Free-form labels for grouping the issues, e.g. the kanban columns
+  labelList: string[];
+  referenceList?: IssueReference[];
 };
 
 export type IssueCreate = {
@@ -120,6 +157,7 @@ export type IssueCreate = {
   assigneeId: PrincipalId;
   createContext: IssueCreateContext;
   payload: IssuePayload;
+  labelList?: string[];
 };
 
 export type IssuePatch = {
@@ -128,6 +166,8 @@ export type IssuePatch = {
   description?: string;
   assigneeId?: PrincipalId;
   payload?: IssuePayload;
+  // Comma separated, and the empty string clears the labels
+  labelList?: string;
 };
 
 export type IssueStatusPatch = {
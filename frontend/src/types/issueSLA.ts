@@ -0,0 +1,51 @@
+import { EnvironmentId, IssueId, StageId } from "./id";
+
+export type IssueSLATimerType = "APPROVAL" | "DONE";
+
+// The timer starts when the issue is created, and stops when the stage is
+// approved or done, or the issue is closed.
+export type IssueSLATimer = {
+  stageId: StageId;
+  environmentId: EnvironmentId;
+  type: IssueSLATimerType;
+  targetSeconds: number;
+  startTs: number;
+  dueTs: number;
+  // stopTs is 0 if the timer is still running.
+  stopTs: number;
+  elapsedSeconds: number;
+  breached: boolean;
+};
+
+export type IssueSLA = {
+  issueId: IssueId;
+  breached: boolean;
+  timerList: IssueSLATimer[];
+};
+
+export type IssueSLAReportItem = {
+  environmentId: EnvironmentId;
+  type: IssueSLATimerType;
+  timerCount: number;
+  breachedCount: number;
+  // averageLeadSeconds is averaged over the stopped timers.
+  averageLeadSeconds: number;
+};
+
+export type IssueSLAReport = {
+  startTs: number;
+  endTs: number;
+  issueCount: number;
+  breachedIssueIdList: IssueId[];
+  itemList: IssueSLAReportItem[];
+};
+
+export type IssueSLAReportFind = {
+  projectId?: number;
+  startTs?: number;
+  endTs?: number;
+};
+
+export interface IssueSLAState {
+  issueSLAById: Map<IssueId, IssueSLA>;
+}
@@ -0,0 +1,45 @@
+import { EnvironmentId, IssueViewId, PrincipalId, ProjectId } from "./id";
+import { IssueStatus } from "./issue";
+import { Principal } from "./principal";
+
+// The unset fields don't filter the issues.
+export type IssueViewFilter = {
+  statusList?: IssueStatus[];
+  assigneeId?: PrincipalId;
+  environmentId?: EnvironmentId;
+  label?: string;
+};
+
+// The issue view is a saved filter of the issues, e.g. a column of the kanban board.
+export type IssueView = {
+  id: IssueViewId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  // The view of a project is shared with the workspace, and the others are private to the creator.
+  projectId?: ProjectId;
+
+  // Domain specific fields
+  name: string;
+  filter: IssueViewFilter;
+};
+
+export type IssueViewCreate = {
+  // Related fields
+  projectId?: ProjectId;
+
+  // Domain specific fields
+  name: string;
+  filter: IssueViewFilter;
+};
+
+export type IssueViewPatch = {
+  // Domain specific fields
+  name?: string;
+  filter?: IssueViewFilter;
+};
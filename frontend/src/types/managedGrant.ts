@@ -0,0 +1,33 @@
+import { DatabaseId, ManagedGrantId } from "./id";
+import { Principal } from "./principal";
+
+// The managed grant declares the database level privileges of the grantee, and the actual grants are compared with it on the database sync.
+export type ManagedGrant = {
+  id: ManagedGrantId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  databaseId: DatabaseId;
+
+  // Domain specific fields
+  // Role name for PostgreSQL, and "user@host" for MySQL and TiDB.
+  grantee: string;
+  // Upper cased, e.g. "SELECT" for MySQL and "CONNECT" for PostgreSQL.
+  privilegeList: string[];
+};
+
+export type ManagedGrantUpsert = {
+  grantee: string;
+  privilegeList: string[];
+};
+
+export type ManagedGrantDrift = {
+  grantee: string;
+  missingPrivilegeList: string[] | null;
+  extraPrivilegeList: string[] | null;
+};
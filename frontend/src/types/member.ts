@@ -4,7 +4,8 @@ import { Principal } from "./principal";
 
 export type MemberStatus = "INVITED" | "ACTIVE";
 
-export type RoleType = "OWNER" | "DBA" | "DEVELOPER";
+// AUDITOR can read every resource but can't change anything
+export type RoleType = "OWNER" | "DBA" | "DEVELOPER" | "AUDITOR";
 
 export type Member = {
   id: MemberId;
@@ -0,0 +1,37 @@
+import { TaskCheckType } from "./pipeline";
+
+export type MigrationCheckFailureCount = {
+  type: TaskCheckType;
+  count: number;
+};
+
+export type MigrationStatSummary = {
+  // id is the environment or project ID, 0 for the whole workspace.
+  id: number;
+  migrationCount: number;
+  failedCount: number;
+  // failureRate is in [0, 1].
+  failureRate: number;
+  // averageDurationSeconds is averaged over the succeeded migrations.
+  averageDurationSeconds: number;
+  checkFailureList: MigrationCheckFailureCount[];
+};
+
+export type MigrationStat = {
+  startTs: number;
+  endTs: number;
+  total: MigrationStatSummary;
+  environmentList: MigrationStatSummary[];
+  projectList: MigrationStatSummary[];
+};
+
+export type MigrationStatFind = {
+  environmentId?: number;
+  projectId?: number;
+  startTs?: number;
+  endTs?: number;
+};
+
+export interface MigrationStatState {
+  migrationStat: MigrationStat | undefined;
+}
@@ -23,6 +23,11 @@ export type Stage = {
 
   // Domain specific fields
   name: string;
+  // paused holds the pipeline before the stage until it's resumed.
+  paused: boolean;
+  // soakSeconds is the time the paused stage waits after the previous stage
+  // finishes before it's resumed automatically, 0 for manual resume only.
+  soakSeconds: number;
 };
 
 export type StageCreate = {
@@ -34,6 +39,11 @@ export type StageCreate = {
   name: string;
 };
 
+export type StagePatch = {
+  paused: boolean;
+  comment?: string;
+};
+
 export type StageAllTaskStatusPatch = {
   id: StageId;
 
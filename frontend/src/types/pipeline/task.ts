@@ -1,4 +1,9 @@
-import { ErrorCode, MigrationHistoryId, TaskCheckRunId } from "..";
+import {
+  ErrorCode,
+  MigrationHistoryId,
+  PrincipalId,
+  TaskCheckRunId,
+} from "..";
 import { Database } from "../database";
 import {
   BackupId,
@@ -6,8 +11,10 @@ import {
   InstanceId,
   ProjectId,
   TaskId,
+  TaskRunArtifactId,
   TaskRunId,
 } from "../id";
+import { BackupStorageBackend } from "../backup";
 import { Instance, MigrationType } from "../instance";
 import { Principal } from "../principal";
 import { VCSPushEvent } from "../vcs";
@@ -31,7 +38,8 @@ export type TaskStatus =
   | "RUNNING"
   | "DONE"
   | "FAILED"
-  | "CANCELED";
+  | "CANCELED"
+  | "PAUSED";
 
 export type TaskGeneralPayload = {
   statement: string;
@@ -53,6 +61,10 @@ export type TaskDatabaseSchemaUpdatePayload = {
   migrationType: MigrationType;
   statement: string;
   pushEvent?: VCSPushEvent;
+  rollbackStatement?: string;
+  executedStatementCount?: number;
+  timeoutSeconds?: number;
+  schemaName?: string;
 };
 
 export type TaskDatabaseSchemaUpdateGhostSyncPayload = {
@@ -85,6 +97,9 @@ export type TaskDatabasePITRDeletePayload = {
 export type TaskDatabaseDataUpdatePayload = {
   statement: string;
   pushEvent?: VCSPushEvent;
+  executedStatementCount?: number;
+  timeoutSeconds?: number;
+  schemaName?: string;
 };
 
 export type TaskDatabaseRestorePayload = {
@@ -180,12 +195,32 @@ export type TaskStatusPatch = {
 };
 
 // TaskRun is one run of a particular task
-export type TaskRunStatus = "RUNNING" | "DONE" | "FAILED" | "CANCELED";
+export type TaskRunStatus =
+  | "RUNNING"
+  | "DONE"
+  | "FAILED"
+  | "CANCELED"
+  | "PAUSED";
 
 export type TaskRunResultPayload = {
   detail: string;
   migrationId?: MigrationHistoryId;
   version?: string;
+  progress?: TaskProgress;
+  // The 1-based position of the failed statement. It's only set if the statements
+  // are executed one by one, e.g. unset for the PostgreSQL schema migrations.
+  failedStatementIndex?: number;
+  // The total execution time of the migration.
+  executionDurationNs?: number;
+  // The TiDB DDL jobs created by the migration.
+  ddlJobIdList?: number[];
+  // The algorithms used by the MySQL ALTER TABLE statements of the migration.
+  ddlAlgorithmList?: TaskRunDDLAlgorithm[];
+};
+
+export type TaskRunDDLAlgorithm = {
+  table: string;
+  algorithm: "INSTANT" | "INPLACE" | "COPY";
 };
 
 export type TaskRun = {
@@ -205,6 +240,47 @@ export type TaskRun = {
   comment: string;
   result: TaskRunResultPayload;
   payload?: TaskPayload;
+  progress: TaskRunProgress;
+  // The last time the executor reported the running task run alive, 0 if not
+  // running.
+  heartbeatTs: number;
+};
+
+// TaskRunProgress is reported periodically by the executor of the running task run
+export type TaskRunProgress = {
+  rowsCopied?: number;
+  rowsTotal?: number;
+  currentStatement?: string;
+  statementIndex?: number;
+  statementCount?: number;
+  etaSeconds?: number;
+  updatedTs?: number;
+};
+
+// TaskRunArtifact is a file attached to the task run by the executor
+export type TaskRunArtifactType =
+  | "bb.task-run-artifact.ghost-log"
+  | "bb.task-run-artifact.dump-file"
+  | "bb.task-run-artifact.verification-output";
+
+export type TaskRunArtifact = {
+  id: TaskRunArtifactId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+
+  // Related fields
+  taskRunId: TaskRunId;
+  taskId: TaskId;
+
+  // Domain specific fields
+  type: TaskRunArtifactType;
+  name: string;
+  size: number;
+  storageBackend: BackupStorageBackend;
+  reference: boolean;
+  expireTs: number;
 };
 
 export type TaskCheckRunStatus = "RUNNING" | "DONE" | "FAILED" | "CANCELED";
@@ -215,10 +291,12 @@ export type TaskCheckType =
   | "bb.task-check.database.statement.compatibility"
   | "bb.task-check.database.statement.advise"
   | "bb.task-check.database.statement.type"
+  | "bb.task-check.database.statement.dry-run"
   | "bb.task-check.database.connect"
   | "bb.task-check.instance.migration-schema"
   | "bb.task-check.general.earliest-allowed-time"
-  | "bb.task-check.database.ghost.sync";
+  | "bb.task-check.database.ghost.sync"
+  | "bb.task-check.custom";
 
 export type TaskCheckDatabaseStatementAdvisePayload = {
   statement: string;
@@ -243,14 +321,28 @@ export type TaskCheckDatabaseStatementTypePayload = {
 
 export type TaskCheckStatus = "SUCCESS" | "WARN" | "ERROR";
 
-export type TaskCheckNamespace = "bb.advisor" | "bb.core";
+export type TaskCheckNamespace = "bb.advisor" | "bb.core" | "bb.custom";
+
+export type TaskCheckResultSuppression = {
+  justification: string;
+  approverId: PrincipalId;
+  suppressedTs: number;
+};
+
+export type TaskCheckResultSuppress = {
+  index: number;
+  justification: string;
+};
 
 export type TaskCheckResult = {
   status: TaskCheckStatus;
   code: ErrorCode;
   title: string;
   content: string;
+  // The line of the statement, omitted if not applicable.
+  line?: number;
   namespace: TaskCheckNamespace;
+  suppression?: TaskCheckResultSuppression;
 };
 
 export type TaskCheckRunResultPayload = {
@@ -17,6 +17,7 @@ export type FeatureType =
   // Admin & Security
   | "bb.feature.rbac"
   | "bb.feature.3rd-party-auth"
+  | "bb.feature.multi-workspace"
   // Branding
   | "bb.feature.branding";
 
@@ -66,6 +67,7 @@ export const FEATURE_MATRIX: Map<FeatureType, boolean[]> = new Map([
   // Admin & Security
   ["bb.feature.rbac", [false, true, true]],
   ["bb.feature.3rd-party-auth", [false, true, true]],
+  ["bb.feature.multi-workspace", [false, false, true]],
   // Branding
   ["bb.feature.branding", [false, true, true]],
 ]);
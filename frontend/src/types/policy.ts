@@ -1,5 +1,6 @@
 import {
   RowStatus,
+  RoleType,
   Environment,
   IssueType,
   PolicyId,
@@ -13,7 +14,10 @@ export type PolicyType =
   | "bb.policy.pipeline-approval"
   | "bb.policy.backup-plan"
   | "bb.policy.sql-review"
-  | "bb.policy.environment-tier";
+  | "bb.policy.environment-tier"
+  | "bb.policy.query-limit"
+  | "bb.policy.task-failure"
+  | "bb.policy.issue-sla";
 
 export type PipelineApprovalPolicyValue =
   | "MANUAL_APPROVAL_NEVER"
@@ -35,6 +39,31 @@ export type EnvironmentTierPolicyPayload = {
 
 export const DefaultEnvironmentTier: EnvironmentTier = "UNPROTECTED";
 
+// The limits on the SQL editor query results for a role, 0 means unlimited.
+export type QueryLimit = {
+  role: RoleType;
+  maxRows: number;
+  maxBytes: number;
+};
+
+export type QueryLimitPolicyPayload = {
+  limitList: QueryLimit[];
+};
+
+// PAUSE_STAGE pauses the stage of the failed task in the tenant mode projects,
+// holding its remaining pending tasks until the stage is resumed.
+export type TaskFailureAction = "CONTINUE" | "PAUSE_STAGE";
+
+export type TaskFailurePolicyPayload = {
+  action: TaskFailureAction;
+};
+
+// The SLA targets are the durations since the issue is created, 0 means no target.
+export type IssueSLAPolicyPayload = {
+  approvalSeconds: number;
+  doneSeconds: number;
+};
+
 export type BackupPlanPolicySchedule = "UNSET" | "DAILY" | "WEEKLY";
 
 export type BackupPlanPolicyPayload = {
@@ -72,7 +101,10 @@ export type PolicyPayload =
   | PipelineApprovalPolicyPayload
   | BackupPlanPolicyPayload
   | SQLReviewPolicyPayload
-  | EnvironmentTierPolicyPayload;
+  | EnvironmentTierPolicyPayload
+  | QueryLimitPolicyPayload
+  | TaskFailurePolicyPayload
+  | IssueSLAPolicyPayload;
 
 export type Policy = {
   id: PolicyId;
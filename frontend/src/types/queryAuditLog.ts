@@ -0,0 +1,44 @@
+import { InstanceId, QueryAuditLogId } from "./id";
+import { Principal } from "./principal";
+
+export type QueryAuditLogType = "QUERY" | "EXPORT" | "EXECUTE";
+
+export type QueryAuditLog = {
+  id: QueryAuditLogId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+
+  // Related fields
+  instanceId: InstanceId;
+
+  // Domain specific fields
+  type: QueryAuditLogType;
+  databaseName: string;
+  statement: string;
+  // The statement with the literals replaced by placeholders.
+  fingerprint: string;
+  rowCount: number;
+  durationNs: number;
+  error: string;
+};
+
+// The export of the query result is reported by the client.
+export type QueryAuditLogExport = {
+  instanceId: InstanceId;
+  databaseName: string;
+  statement: string;
+  rowCount: number;
+};
+
+export type QueryAuditLogFind = {
+  user?: number;
+  instance?: InstanceId;
+  database?: string;
+  type?: QueryAuditLogType;
+  fingerprint?: string;
+  createdTsAfter?: number;
+  createdTsBefore?: number;
+  limit?: number;
+};
@@ -0,0 +1,27 @@
+import { PrincipalId, ProjectId, ReportSubscriptionId } from "./id";
+import { Principal } from "./principal";
+
+// The subscription of the current user to the weekly report of the project.
+// The weekly report summarizes the migrations applied, the failed tasks, the open anomalies and the upcoming scheduled tasks.
+export type ReportSubscription = {
+  id: ReportSubscriptionId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  projectId: ProjectId;
+  principalId: PrincipalId;
+
+  // Domain specific fields
+  enabled: boolean;
+  // 0 means never sent.
+  lastSentTs: number;
+};
+
+export type ReportSubscriptionPatch = {
+  enabled: boolean;
+};
@@ -60,6 +60,33 @@ export type RepositoryPatch = {
   sheetPathTemplate?: string;
 };
 
+// RepositoryFile is a migration file in the repository linked to the project.
+// lastCommitId and content are only returned when fetching a single file.
+export type RepositoryFile = {
+  // e.g. bytebase/prod/v1__db1__migrate__init.sql
+  path: string;
+  name: string;
+  size: number;
+  lastCommitId: string;
+  content: string;
+};
+
+export type RepositoryCheckType =
+  | "bb.repository.access-token"
+  | "bb.repository.token-scope"
+  | "bb.repository.webhook"
+  | "bb.repository.webhook-reachable"
+  | "bb.repository.base-directory"
+  | "bb.repository.file-path-template";
+
+export type RepositoryCheckStatus = "SUCCESS" | "WARN" | "ERROR";
+
+export type RepositoryCheckItem = {
+  type: RepositoryCheckType;
+  status: RepositoryCheckStatus;
+  message: string;
+};
+
 export type RepositoryConfig = {
   baseDirectory: string;
   branchFilter: string;
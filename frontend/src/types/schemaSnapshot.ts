@@ -0,0 +1,30 @@
+import { DatabaseId, SchemaSnapshotId } from "./id";
+import { Principal } from "./principal";
+
+export type SchemaSnapshotType = "DAILY" | "MIGRATION";
+
+export type SchemaSnapshot = {
+  id: SchemaSnapshotId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  databaseId: DatabaseId;
+
+  // Domain specific fields
+  type: SchemaSnapshotType;
+  migrationVersion: string;
+  // The schema is only returned when fetching a single snapshot.
+  schema?: string;
+};
+
+export type SchemaSnapshotDiff = {
+  fromSnapshotId: SchemaSnapshotId;
+  toSnapshotId: SchemaSnapshotId;
+  // The unified diff, empty if the schemas are the same.
+  diff: string;
+};
@@ -19,3 +19,56 @@ export type Setting = {
 };
 
 export const brandingLogoSettingName: SettingName = "bb.branding.logo";
+export const metricOptOutSettingName: SettingName =
+  "bb.workspace.metric.opt-out";
+export const metricCollectorURLSettingName: SettingName =
+  "bb.workspace.metric.collector-url";
+export const maintenanceSettingName: SettingName = "bb.maintenance";
+// The PEM encoded CA certificates trusted by the outbound integrations.
+export const caCertificatesSettingName: SettingName =
+  "bb.workspace.ca-certificates";
+// The seconds without heartbeat after which the running task is orphaned, 0
+// disables the orphaned task detection.
+export const taskHeartbeatTimeoutSettingName: SettingName =
+  "bb.task.heartbeat-timeout-seconds";
+// The max rows exported from the SQL editor at once, 0 means unlimited.
+export const sqlExportMaxRowsSettingName: SettingName =
+  "bb.sql-editor.export-max-rows";
+// The anomaly is raised if the instance disk is projected to fill up within
+// the days, 0 disables it.
+export const instanceDiskFullDaysSettingName: SettingName =
+  "bb.instance.disk-full-days";
+// The SMTP server sending the emails, e.g. the weekly project reports.
+// It's not returned by the setting list because it contains the password.
+export const smtpSettingName: SettingName = "bb.mail.smtp";
+// The custom task check plugins, not returned by the setting list since the
+// plugin URLs may contain credentials.
+export const taskCheckCustomSettingName: SettingName = "bb.task-check.custom";
+
+// Empty host means no email is sent.
+export type SMTPSetting = {
+  host: string;
+  port: number;
+  username: string;
+  password: string;
+  from: string;
+};
+
+// Exactly one of url and command is set, the command is only allowed if the
+// server is started with --enable-task-check-command.
+export type TaskCheckCustomPlugin = {
+  name: string;
+  url: string;
+  command: string[];
+  timeoutSeconds: number;
+};
+
+export type TaskCheckCustomSetting = {
+  pluginList: TaskCheckCustomPlugin[];
+};
+
+// The value of the maintenance setting in JSON.
+export type MaintenanceSetting = {
+  enabled: boolean;
+  message: string;
+};
@@ -17,6 +17,12 @@ export type ConnectionInfo = {
   sslCa?: string;
   sslCert?: string;
   sslKey?: string;
+  sshHost?: string;
+  sshPort?: string;
+  sshUser?: string;
+  // Empty means the existing SSH private key of the instance.
+  sshPrivateKey?: string;
+  sshHostKey?: string;
 };
 
 export type QueryInfo = {
@@ -24,12 +30,36 @@ export type QueryInfo = {
   databaseName?: string;
   statement: string;
   limit?: number;
+  // The query timeout in seconds, the server default applies if unset.
+  timeout?: number;
+};
+
+export type SQLExportFormat = "CSV" | "JSON";
+
+// The rows exported are capped by the export max rows setting and the query limit policy.
+export type SQLExportInfo = QueryInfo & {
+  format: SQLExportFormat;
 };
 
 export type Advice = TaskCheckResult;
 
+// The metadata of a result column joined from the synced schema.
+// database, table and comment are empty if the column doesn't match exactly one synced column.
+export type SQLResultColumn = {
+  name: string;
+  database: string;
+  table: string;
+  comment: string;
+};
+
 export type SQLResultSet = {
   data: any[];
   error: string;
   adviceList: Advice[];
+  columnList: SQLResultColumn[];
+};
+
+export type SQLFormatResult = {
+  statement: string;
+  error: string;
 };
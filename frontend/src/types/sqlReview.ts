@@ -78,6 +78,7 @@ export type RuleType =
   | "engine.mysql.use-innodb"
   | "table.require-pk"
   | "table.no-foreign-key"
+  | "table.fk-require-index"
   | "table.drop-naming-convention"
   | "naming.table"
   | "naming.column"
@@ -87,10 +88,18 @@ export type RuleType =
   | "naming.index.idx"
   | "column.required"
   | "column.no-null"
+  | "column.set-default-for-not-null"
+  | "column.disallow-large-type"
+  | "column.timestamp-convention"
   | "statement.select.no-select-all"
   | "statement.where.require"
   | "statement.where.no-leading-wildcard-like"
+  | "statement.affected-row-limit"
+  | "statement.disallow-drop.table"
+  | "statement.disallow-drop.database"
+  | "statement.disallow-drop.column"
   | "schema.backward-compatibility"
+  | "schema.disallow-rename"
   | "database.drop-empty-database";
 
 // The naming format rule payload.
@@ -106,12 +115,36 @@ interface RequiredColumnPayload {
   columnList: string[];
 }
 
+// The large column type rule payload.
+// Used by the backend.
+interface LargeTypePayload {
+  maxVarcharLength: number;
+}
+
+// The timestamp column convention rule payload.
+// Used by the backend.
+interface TimestampConventionPayload {
+  createdColumn: string;
+  updatedColumn: string;
+}
+
+// The affected row limit rule payload.
+// Used by the backend.
+interface AffectedRowLimitPayload {
+  maxRows: number;
+}
+
 // The SchemaPolicyRule stores the rule configuration by users.
 // Used by the backend
 export interface SchemaPolicyRule {
   type: RuleType;
   level: RuleLevel;
-  payload?: NamingFormatPayload | RequiredColumnPayload;
+  payload?:
+    | NamingFormatPayload
+    | RequiredColumnPayload
+    | LargeTypePayload
+    | TimestampConventionPayload
+    | AffectedRowLimitPayload;
 }
 
 // The API for SQL review policy in backend.
@@ -355,6 +388,57 @@ export const convertPolicyRuleToRuleTemplate = (
           },
         ],
       };
+    case "column.disallow-large-type":
+      if (!numberComponent) {
+        throw new Error(`Invalid rule ${ruleTemplate.type}`);
+      }
+
+      return {
+        ...res,
+        componentList: [
+          {
+            ...numberComponent,
+            payload: {
+              ...numberComponent.payload,
+              value: (policyRule.payload as LargeTypePayload).maxVarcharLength,
+            } as NumberPayload,
+          },
+        ],
+      };
+    case "statement.affected-row-limit":
+      if (!numberComponent) {
+        throw new Error(`Invalid rule ${ruleTemplate.type}`);
+      }
+
+      return {
+        ...res,
+        componentList: [
+          {
+            ...numberComponent,
+            payload: {
+              ...numberComponent.payload,
+              value: (policyRule.payload as AffectedRowLimitPayload).maxRows,
+            } as NumberPayload,
+          },
+        ],
+      };
+    case "column.timestamp-convention": {
+      const timestampConventionPayload =
+        policyRule.payload as TimestampConventionPayload;
+      return {
+        ...res,
+        componentList: ruleTemplate.componentList.map((component) => ({
+          ...component,
+          payload: {
+            ...component.payload,
+            value:
+              component.key === "createdColumn"
+                ? timestampConventionPayload.createdColumn
+                : timestampConventionPayload.updatedColumn,
+          } as StringPayload,
+        })),
+      };
+    }
     case "column.required": {
       const requiredColumnComponent = ruleTemplate.componentList[0];
       const requiredColumnPayload = {
@@ -447,6 +531,40 @@ export const convertRuleTemplateToPolicyRule = (
           maxLength: numberPayload.value ?? numberPayload.default,
         },
       };
+    case "column.disallow-large-type":
+      if (!numberPayload) {
+        throw new Error(`Invalid rule ${rule.type}`);
+      }
+      return {
+        ...base,
+        payload: {
+          maxVarcharLength: numberPayload.value ?? numberPayload.default,
+        },
+      };
+    case "statement.affected-row-limit":
+      if (!numberPayload) {
+        throw new Error(`Invalid rule ${rule.type}`);
+      }
+      return {
+        ...base,
+        payload: {
+          maxRows: numberPayload.value ?? numberPayload.default,
+        },
+      };
+    case "column.timestamp-convention": {
+      const getColumn = (key: string) => {
+        const payload = rule.componentList.find((c) => c.key === key)
+          ?.payload as StringPayload | undefined;
+        return payload?.value ?? payload?.default ?? "";
+      };
+      return {
+        ...base,
+        payload: {
+          createdColumn: getColumn("createdColumn"),
+          updatedColumn: getColumn("updatedColumn"),
+        },
+      };
+    }
     case "column.required": {
       const stringArrayPayload = rule.componentList[0]
         .payload as StringArrayPayload;
@@ -0,0 +1,42 @@
+import { IssueId, StatementCommentId, TaskId } from "./id";
+import { Principal } from "./principal";
+
+// The statement comment is a review comment on a line of the task statement.
+// The task can't be approved until all its comments are resolved.
+export type StatementComment = {
+  id: StatementCommentId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  issueId: IssueId;
+  taskId: TaskId;
+
+  // Domain specific fields
+  statementHash: string;
+  // 1-based line in the statement.
+  line: number;
+  content: string;
+  resolved: boolean;
+  // The statement has changed since the comment was created.
+  outdated: boolean;
+};
+
+export type StatementCommentCreate = {
+  // Related fields
+  taskId: TaskId;
+
+  // Domain specific fields
+  line: number;
+  content: string;
+};
+
+export type StatementCommentPatch = {
+  // Domain specific fields
+  content?: string;
+  resolved?: boolean;
+};
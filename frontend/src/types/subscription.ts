@@ -1,4 +1,4 @@
-import { PlanType } from "./plan";
+import { FeatureType, PlanType } from "./plan";
 
 export interface Subscription {
   instanceCount: number;
@@ -6,8 +6,22 @@ export interface Subscription {
   startedTs: number;
   plan: PlanType;
   trialing: boolean;
+  // seat is the maximum number of the workspace members, 0 means unlimited.
+  seat: number;
+  // features are granted by the license in addition to the plan.
+  features: FeatureType[];
 }
 
 export interface SubscriptionState {
   subscription: Subscription | undefined;
 }
+
+// FeatureError is the response body when the feature isn't available
+// in the current plan.
+export interface FeatureError {
+  message: string;
+  code: "bb.error.feature-not-available";
+  feature: FeatureType;
+  currentPlan: string;
+  requiredPlan: string;
+}
@@ -22,6 +22,8 @@ export type Table = {
 
   // Domain specific fields
   name: string;
+  // Postgres only, empty for the other engines.
+  schema: string;
   type: TableType;
   engine: TableEngineType;
   collation: string;
@@ -23,3 +23,10 @@ export type TableIndex = {
   visible: boolean;
   comment: string;
 };
+
+export type TableIndexPatch = {
+  // Domain specific fields
+  name?: string;
+  visible?: boolean;
+  comment?: string;
+};
@@ -0,0 +1,27 @@
+export type UsageMetric = {
+  // createdTs is the time of the collection round.
+  createdTs: number;
+  name: string;
+  value: number;
+  labels: { [key: string]: string };
+};
+
+export type UsageCount = {
+  key: string;
+  count: number;
+};
+
+export type WorkspaceUsage = {
+  // collectedTs is 0 if the usage metrics have never been collected.
+  collectedTs: number;
+  optOut: boolean;
+  instanceCount: number;
+  engineMix: UsageCount[];
+  // The key is the first day of the week, formatted as YYYY-MM-DD.
+  weeklyIssueCountList: UsageCount[];
+  metricList: UsageMetric[];
+};
+
+export interface UsageState {
+  usage: WorkspaceUsage | undefined;
+}
@@ -17,6 +17,8 @@ export type View = {
 
   // Domain specific fields
   name: string;
+  // Postgres only, empty for the other engines.
+  schema: string;
   definition: string;
   comment: string;
 };
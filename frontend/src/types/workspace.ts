@@ -0,0 +1,50 @@
+import { RowStatus } from "./common";
+import { WorkspaceId } from "./id";
+import { Principal } from "./principal";
+
+// The default workspace holds the existing resources,
+// and its owners manage the other workspaces in the deployment.
+export const DEFAULT_WORKSPACE_ID = 1;
+
+export type Workspace = {
+  id: WorkspaceId;
+
+  // Standard fields
+  rowStatus: RowStatus;
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Domain specific fields
+  name: string;
+};
+
+export type WorkspaceCreate = {
+  // Domain specific fields
+  name: string;
+  // The owner is signed up along with the workspace.
+  ownerName: string;
+  ownerEmail: string;
+  ownerPassword: string;
+};
+
+export type WorkspacePatch = {
+  // Standard fields
+  rowStatus?: RowStatus;
+
+  // Domain specific fields
+  name?: string;
+};
+
+// The result of applying the workspace config YAML bundle, which has the
+// environments with their policies and the projects.
+export type WorkspaceConfigApplyResult = {
+  // Empty if the workspace already matches the config.
+  changeList: string[];
+  validateOnly: boolean;
+};
+
+export interface WorkspaceState {
+  workspaceList: Workspace[];
+}
@@ -64,6 +64,7 @@ export function issueActivityActionSentence(
         case IssueBuiltinFieldId.NAME:
         case IssueBuiltinFieldId.PROJECT:
         case IssueBuiltinFieldId.SQL:
+        case IssueBuiltinFieldId.LABEL_LIST:
       }
 
       return ["activity.sentence.updated", {}];
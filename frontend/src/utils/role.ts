@@ -1,23 +1,34 @@
 import { ProjectRoleType, RoleType } from "../types";
 import { hasFeature } from "@/store";
 
-// Returns true if admin feature is NOT supported or the principal is OWNER
+// Returns true if admin feature is NOT supported or the principal is OWNER, except for AUDITOR
 export function isOwner(role: RoleType): boolean {
-  return !hasFeature("bb.feature.rbac") || role == "OWNER";
+  return (
+    !isAuditor(role) && (!hasFeature("bb.feature.rbac") || role == "OWNER")
+  );
 }
 
-// Returns true if admin feature is NOT supported or the principal is DBA
+// Returns true if admin feature is NOT supported or the principal is DBA, except for AUDITOR
 export function isDBA(role: RoleType): boolean {
-  return !hasFeature("bb.feature.rbac") || role == "DBA";
+  return (
+    !isAuditor(role) && (!hasFeature("bb.feature.rbac") || role == "DBA")
+  );
 }
 
 export function isDBAOrOwner(role: RoleType): boolean {
   return isDBA(role) || isOwner(role);
 }
 
-// Returns true if admin feature is NOT supported or the principal is DEVELOPER
+// Returns true if admin feature is NOT supported or the principal is DEVELOPER, except for AUDITOR
 export function isDeveloper(role: RoleType): boolean {
-  return !hasFeature("bb.feature.rbac") || role == "DEVELOPER";
+  return (
+    !isAuditor(role) && (!hasFeature("bb.feature.rbac") || role == "DEVELOPER")
+  );
+}
+
+// Returns true if the principal is AUDITOR, who stays read-only even if admin feature is NOT supported
+export function isAuditor(role: RoleType): boolean {
+  return role == "AUDITOR";
 }
 
 export function roleName(role: RoleType): string {
@@ -28,16 +39,22 @@ export function roleName(role: RoleType): string {
       return "DBA";
     case "DEVELOPER":
       return "Developer";
+    case "AUDITOR":
+      return "Auditor";
   }
 }
 
 // Project Role
 export function isProjectOwner(role: ProjectRoleType): boolean {
-  return !hasFeature("bb.feature.rbac") || role == "OWNER";
+  return (
+    !isAuditor(role) && (!hasFeature("bb.feature.rbac") || role == "OWNER")
+  );
 }
 
 export function isProjectDeveloper(role: ProjectRoleType): boolean {
-  return !hasFeature("bb.feature.rbac") || role == "DEVELOPER";
+  return (
+    !isAuditor(role) && (!hasFeature("bb.feature.rbac") || role == "DEVELOPER")
+  );
 }
 
 export function projectRoleName(role: ProjectRoleType): string {
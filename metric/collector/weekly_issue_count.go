@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	metricAPI "github.com/bytebase/bytebase/metric"
+	"github.com/bytebase/bytebase/plugin/metric"
+	"github.com/bytebase/bytebase/store"
+)
+
+const (
+	// weeklyIssueCountWeeks is the number of the recent weeks to count the issues.
+	weeklyIssueCountWeeks = 12
+)
+
+var _ metric.Collector = (*weeklyIssueCountCollector)(nil)
+
+// weeklyIssueCountCollector is the metric data collector for the issues created in each week.
+type weeklyIssueCountCollector struct {
+	store *store.Store
+}
+
+// NewWeeklyIssueCountCollector creates a new instance of weeklyIssueCountCollector.
+func NewWeeklyIssueCountCollector(store *store.Store) metric.Collector {
+	return &weeklyIssueCountCollector{
+		store: store,
+	}
+}
+
+// Collect will collect the metric for the issues created in each of the recent weeks.
+func (c *weeklyIssueCountCollector) Collect(ctx context.Context) ([]*metric.Metric, error) {
+	var res []*metric.Metric
+
+	sinceTs := time.Now().AddDate(0, 0, -7*weeklyIssueCountWeeks).Unix()
+	weeklyIssueCountMetricList, err := c.store.CountIssueGroupByWeek(ctx, sinceTs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, weeklyIssueCountMetric := range weeklyIssueCountMetricList {
+		res = append(res, &metric.Metric{
+			Name:  metricAPI.WeeklyIssueCountMetricName,
+			Value: weeklyIssueCountMetric.Count,
+			Labels: map[string]string{
+				"week": time.Unix(weeklyIssueCountMetric.WeekStartTs, 0).UTC().Format("2006-01-02"),
+			},
+		})
+	}
+
+	return res, nil
+}
@@ -12,6 +12,8 @@ const (
 	InstanceCountMetricName metric.Name = "bb.instance.count"
 	// IssueCountMetricName is the metric name for issue count.
 	IssueCountMetricName metric.Name = "bb.issue.count"
+	// WeeklyIssueCountMetricName is the metric name for the count of the issues created in each week.
+	WeeklyIssueCountMetricName metric.Name = "bb.issue.weekly.count"
 	// PolicyCountMetricName is the metric name for policy count.
 	PolicyCountMetricName metric.Name = "bb.policy.count"
 	// ProjectCountMetricName is the metric name for project count.
@@ -45,6 +47,13 @@ type IssueCountMetric struct {
 	Count  int
 }
 
+// WeeklyIssueCountMetric is the API message for bb.issue.weekly.count.
+type WeeklyIssueCountMetric struct {
+	// WeekStartTs is the UNIX timestamp in seconds of the first day (Monday) of the week in UTC.
+	WeekStartTs int64
+	Count       int
+}
+
 // ProjectCountMetric is the API message for project count metric.
 type ProjectCountMetric struct {
 	TenantMode   api.ProjectTenantMode
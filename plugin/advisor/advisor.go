@@ -8,6 +8,7 @@ import (
 
 	"github.com/bytebase/bytebase/plugin/advisor/catalog"
 	"github.com/bytebase/bytebase/plugin/advisor/db"
+	dbdriver "github.com/bytebase/bytebase/plugin/db"
 	"github.com/pkg/errors"
 	"go.uber.org/zap/zapcore"
 )
@@ -57,6 +58,9 @@ const (
 	// MySQLMigrationCompatibility is an advisor type for MySQL migration compatibility.
 	MySQLMigrationCompatibility Type = "bb.plugin.advisor.mysql.migration-compatibility"
 
+	// MySQLDisallowRename is an advisor type for MySQL disallow renaming tables and columns.
+	MySQLDisallowRename Type = "bb.plugin.advisor.mysql.schema.disallow-rename"
+
 	// MySQLWhereRequirement is an advisor type for MySQL WHERE clause requirement.
 	MySQLWhereRequirement Type = "bb.plugin.advisor.mysql.where.require"
 
@@ -84,18 +88,42 @@ const (
 	// MySQLColumnNoNull is an advisor type for MySQL column no NULL value.
 	MySQLColumnNoNull Type = "bb.plugin.advisor.mysql.column.no-null"
 
+	// MySQLColumnSetDefaultForNotNull is an advisor type for MySQL set default value for not null column.
+	MySQLColumnSetDefaultForNotNull Type = "bb.plugin.advisor.mysql.column.set-default-for-not-null"
+
 	// MySQLColumnDisallowChangingType is an advisor type for MySQL disallow changing column type.
 	MySQLColumnDisallowChangingType Type = "bb.plugin.advisor.mysql.column.disallow-changing-type"
 
+	// MySQLColumnDisallowLargeType is an advisor type for MySQL disallow BLOB/TEXT and overly wide VARCHAR columns.
+	MySQLColumnDisallowLargeType Type = "bb.plugin.advisor.mysql.column.disallow-large-type"
+
+	// MySQLColumnTimestampConvention is an advisor type for MySQL created and updated timestamp column convention.
+	MySQLColumnTimestampConvention Type = "bb.plugin.advisor.mysql.column.timestamp-convention"
+
 	// MySQLNoSelectAll is an advisor type for MySQL no select all.
 	MySQLNoSelectAll Type = "bb.plugin.advisor.mysql.select.no-select-all"
 
+	// MySQLAffectedRowLimit is an advisor type for MySQL UPDATE and DELETE locked row limit.
+	MySQLAffectedRowLimit Type = "bb.plugin.advisor.mysql.statement.affected-row-limit"
+
+	// MySQLDisallowDropTable is an advisor type for MySQL disallow dropping tables.
+	MySQLDisallowDropTable Type = "bb.plugin.advisor.mysql.statement.disallow-drop.table"
+
+	// MySQLDisallowDropDatabase is an advisor type for MySQL disallow dropping databases.
+	MySQLDisallowDropDatabase Type = "bb.plugin.advisor.mysql.statement.disallow-drop.database"
+
+	// MySQLDisallowDropColumn is an advisor type for MySQL disallow dropping columns.
+	MySQLDisallowDropColumn Type = "bb.plugin.advisor.mysql.statement.disallow-drop.column"
+
 	// MySQLTableRequirePK is an advisor type for MySQL table require primary key.
 	MySQLTableRequirePK Type = "bb.plugin.advisor.mysql.table.require-pk"
 
 	// MySQLTableNoFK is an advisor type for MySQL table disallow foreign key.
 	MySQLTableNoFK Type = "bb.plugin.advisor.mysql.table.no-foreign-key"
 
+	// MySQLTableFKRequireIndex is an advisor type for MySQL table require index on foreign key columns.
+	MySQLTableFKRequireIndex Type = "bb.plugin.advisor.mysql.table.fk-require-index"
+
 	// MySQLTableDropNamingConvention is an advisor type for MySQL table drop with naming convention.
 	MySQLTableDropNamingConvention Type = "bb.plugin.advisor.mysql.table.drop-naming-convention"
 
@@ -128,6 +156,9 @@ const (
 	// PostgreSQLColumnNoNull is an advisor type for PostgreSQL column no NULL value.
 	PostgreSQLColumnNoNull Type = "bb.plugin.advisor.postgresql.column.no-null"
 
+	// PostgreSQLColumnSetDefaultForNotNull is an advisor type for PostgreSQL set default value for not null column.
+	PostgreSQLColumnSetDefaultForNotNull Type = "bb.plugin.advisor.postgresql.column.set-default-for-not-null"
+
 	// PostgreSQLColumnRequirement is an advisor type for PostgreSQL column requirement.
 	PostgreSQLColumnRequirement Type = "bb.plugin.advisor.postgresql.column.require"
 
@@ -143,11 +174,26 @@ const (
 	// PostgreSQLNoSelectAll is an advisor type for PostgreSQL no select all.
 	PostgreSQLNoSelectAll Type = "bb.plugin.advisor.postgresql.select.no-select-all"
 
+	// PostgreSQLDisallowDropTable is an advisor type for PostgreSQL disallow dropping tables.
+	PostgreSQLDisallowDropTable Type = "bb.plugin.advisor.postgresql.statement.disallow-drop.table"
+
+	// PostgreSQLDisallowDropDatabase is an advisor type for PostgreSQL disallow dropping databases.
+	PostgreSQLDisallowDropDatabase Type = "bb.plugin.advisor.postgresql.statement.disallow-drop.database"
+
+	// PostgreSQLDisallowDropColumn is an advisor type for PostgreSQL disallow dropping columns.
+	PostgreSQLDisallowDropColumn Type = "bb.plugin.advisor.postgresql.statement.disallow-drop.column"
+
 	// PostgreSQLMigrationCompatibility is an advisor type for PostgreSQL migration compatibility.
 	PostgreSQLMigrationCompatibility Type = "bb.plugin.advisor.postgresql.migration-compatibility"
 
+	// PostgreSQLDisallowRename is an advisor type for PostgreSQL disallow renaming tables and columns.
+	PostgreSQLDisallowRename Type = "bb.plugin.advisor.postgresql.schema.disallow-rename"
+
 	// PostgreSQLTableNoFK is an advisor type for PostgreSQL table disallow foreign key.
 	PostgreSQLTableNoFK Type = "bb.plugin.advisor.postgresql.table.no-foreign-key"
+
+	// PostgreSQLTableFKRequireIndex is an advisor type for PostgreSQL table require index on foreign key columns.
+	PostgreSQLTableFKRequireIndex Type = "bb.plugin.advisor.postgresql.table.fk-require-index"
 )
 
 // Advice is the result of an advisor.
@@ -188,12 +234,21 @@ func (array ZapAdviceArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
 type Context struct {
 	Charset   string
 	Collation string
+	// DbType and EngineVersion are the engine of the database, the version is empty if not synced yet.
+	DbType        db.Type
+	EngineVersion string
 
 	// SQL review rule special fields.
 	Rule     *SQLReviewRule
 	Database *catalog.Database
 }
 
+// HasCapability returns true if the engine version supports the capability, and false if the version is unknown.
+func (ctx Context) HasCapability(capability dbdriver.Capability) bool {
+	supported, err := dbdriver.HasCapability(dbdriver.Type(ctx.DbType), ctx.EngineVersion, capability)
+	return err == nil && supported
+}
+
 // Advisor is the interface for advisor.
 type Advisor interface {
 	Check(ctx Context, statement string) ([]Advice, error)
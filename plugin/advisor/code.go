@@ -23,10 +23,11 @@ const (
 	CompatibilityAlterColumn   Code = 111
 
 	// 201 ~ 299 statement error code.
-	StatementSyntaxError         Code = 201
-	StatementNoWhere             Code = 202
-	StatementSelectAll           Code = 203
-	StatementLeadingWildcardLike Code = 204
+	StatementSyntaxError             Code = 201
+	StatementNoWhere                 Code = 202
+	StatementSelectAll               Code = 203
+	StatementLeadingWildcardLike     Code = 204
+	StatementAffectedRowExceedsLimit Code = 205
 
 	// 301 ～ 399 naming error code
 	// 301 table naming advisor error code.
@@ -43,8 +44,11 @@ const (
 	NamingPKConventionMismatch Code = 306
 
 	// 401 ~ 499 column error code.
-	NoRequiredColumn Code = 401
-	ColumnCanNotNull Code = 402
+	NoRequiredColumn                  Code = 401
+	ColumnCanNotNull                  Code = 402
+	NotNullColumnWithNoDefault        Code = 403
+	ColumnLargeType                   Code = 404
+	ColumnTimestampConventionMismatch Code = 405
 
 	// 501 engine error code.
 	NotInnoDBEngine Code = 501
@@ -53,6 +57,7 @@ const (
 	TableNoPK                         Code = 601
 	TableHasFK                        Code = 602
 	TableDropNamingConventionMismatch Code = 603
+	TableFKNoIndex                    Code = 604
 
 	// 701 ~ 799 database advisor error code.
 	DatabaseNotEmpty   Code = 701
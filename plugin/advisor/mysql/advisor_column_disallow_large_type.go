@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+)
+
+var (
+	_ advisor.Advisor = (*ColumnDisallowLargeTypeAdvisor)(nil)
+	_ ast.Visitor     = (*columnDisallowLargeTypeChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLColumnDisallowLargeType, &ColumnDisallowLargeTypeAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLColumnDisallowLargeType, &ColumnDisallowLargeTypeAdvisor{})
+}
+
+// ColumnDisallowLargeTypeAdvisor is the advisor checking for BLOB/TEXT and overly wide VARCHAR columns.
+type ColumnDisallowLargeTypeAdvisor struct {
+}
+
+// Check checks for BLOB/TEXT and overly wide VARCHAR columns.
+func (*ColumnDisallowLargeTypeAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalLargeTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &columnDisallowLargeTypeChecker{
+		level:            level,
+		title:            string(ctx.Rule.Type),
+		maxVarcharLength: payload.MaxVarcharLength,
+	}
+
+	for _, stmtNode := range root {
+		(stmtNode).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type columnDisallowLargeTypeChecker struct {
+	adviceList       []advisor.Advice
+	level            advisor.Status
+	title            string
+	maxVarcharLength int
+}
+
+// Enter implements the ast.Visitor interface.
+func (v *columnDisallowLargeTypeChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		for _, column := range node.Cols {
+			v.checkColumn(node.Table.Name.String(), column, column.OriginTextPosition())
+		}
+	// ALTER TABLE
+	case *ast.AlterTableStmt:
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			// ADD COLUMNS, CHANGE COLUMN, MODIFY COLUMN
+			case ast.AlterTableAddColumns, ast.AlterTableChangeColumn, ast.AlterTableModifyColumn:
+				for _, column := range spec.NewColumns {
+					v.checkColumn(node.Table.Name.String(), column, node.OriginTextPosition())
+				}
+			}
+		}
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*columnDisallowLargeTypeChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *columnDisallowLargeTypeChecker) checkColumn(tableName string, column *ast.ColumnDef, line int) {
+	if column.Tp == nil {
+		return
+	}
+	switch column.Tp.Tp {
+	// TINYBLOB and TINYTEXT are no longer than 255 bytes, so we let them go.
+	case mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    advisor.ColumnLargeType,
+			Title:   v.title,
+			Content: fmt.Sprintf("`%s`.`%s` is %s, please consider storing the large object separately", tableName, column.Name.Name.String(), strings.ToUpper(types.TypeToStr(column.Tp.Tp, column.Tp.Charset))),
+			Line:    line,
+		})
+	case mysql.TypeVarchar:
+		if column.Tp.Flen > v.maxVarcharLength {
+			v.adviceList = append(v.adviceList, advisor.Advice{
+				Status:  v.level,
+				Code:    advisor.ColumnLargeType,
+				Title:   v.title,
+				Content: fmt.Sprintf("`%s`.`%s` is VARCHAR(%d) which exceeds the maximum length %d, please consider storing the large object separately", tableName, column.Name.Name.String(), column.Tp.Flen, v.maxVarcharLength),
+				Line:    line,
+			})
+		}
+	}
+}
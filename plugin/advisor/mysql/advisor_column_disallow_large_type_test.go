@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestColumnDisallowLargeType(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: `CREATE TABLE book(
+				id int,
+				title varchar(255),
+				summary varchar(2048),
+				content TEXT,
+				cover BLOB,
+				note TINYTEXT)`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnLargeType,
+					Title:   "column.disallow-large-type",
+					Content: "`book`.`summary` is VARCHAR(2048) which exceeds the maximum length 1024, please consider storing the large object separately",
+					Line:    4,
+				},
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnLargeType,
+					Title:   "column.disallow-large-type",
+					Content: "`book`.`content` is TEXT, please consider storing the large object separately",
+					Line:    5,
+				},
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnLargeType,
+					Title:   "column.disallow-large-type",
+					Content: "`book`.`cover` is BLOB, please consider storing the large object separately",
+					Line:    6,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book ADD COLUMN (content MEDIUMTEXT, title varchar(1024))",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnLargeType,
+					Title:   "column.disallow-large-type",
+					Content: "`book`.`content` is MEDIUMTEXT, please consider storing the large object separately",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book MODIFY COLUMN cover LONGBLOB",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnLargeType,
+					Title:   "column.disallow-large-type",
+					Content: "`book`.`cover` is LONGBLOB, please consider storing the large object separately",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book CHANGE COLUMN title name varchar(4096)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnLargeType,
+					Title:   "column.disallow-large-type",
+					Content: "`book`.`name` is VARCHAR(4096) which exceeds the maximum length 1024, please consider storing the large object separately",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE book(id int, title varchar(1024), note TINYBLOB)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &ColumnDisallowLargeTypeAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleColumnDisallowLargeType,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: `{"maxVarcharLength":1024}`,
+	}, advisor.MockMySQLDatabase)
+}
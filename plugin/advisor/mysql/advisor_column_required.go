@@ -49,6 +49,7 @@ func (*ColumnRequirementAdvisor) Check(ctx advisor.Context, statement string) ([
 		requiredColumns: requiredColumns,
 		tables:          make(tableState),
 		line:            make(map[string]int),
+		suggestion:      make(map[string]string),
 	}
 
 	for _, stmtNode := range root {
@@ -65,8 +66,17 @@ type columnRequirementChecker struct {
 	requiredColumns columnSet
 	tables          tableState
 	line            map[string]int
+	// suggestion is the fix suggestion of the table, which depends on the last statement making the required columns missing.
+	suggestion map[string]string
 }
 
+// The fix suggestions in the advice content.
+const (
+	requiredColumnCreateSuggestion = "Add the missing columns to the CREATE TABLE statement"
+	requiredColumnDropSuggestion   = "Keep the required columns instead of dropping them"
+	requiredColumnRenameSuggestion = "Keep the required column names instead of renaming them"
+)
+
 // Enter implements the ast.Visitor interface.
 func (v *columnRequirementChecker) Enter(in ast.Node) (ast.Node, bool) {
 	switch node := in.(type) {
@@ -85,7 +95,9 @@ func (v *columnRequirementChecker) Enter(in ast.Node) (ast.Node, bool) {
 			switch spec.Tp {
 			// RENAME COLUMN
 			case ast.AlterTableRenameColumn:
-				v.renameColumn(table, spec.OldColumnName.Name.O, spec.NewColumnName.Name.O)
+				if v.renameColumn(table, spec.OldColumnName.Name.O, spec.NewColumnName.Name.O) {
+					v.suggestion[table] = requiredColumnRenameSuggestion
+				}
 				v.line[table] = node.OriginTextPosition()
 			// ADD COLUMNS
 			case ast.AlterTableAddColumns:
@@ -96,11 +108,13 @@ func (v *columnRequirementChecker) Enter(in ast.Node) (ast.Node, bool) {
 			case ast.AlterTableDropColumn:
 				if v.dropColumn(table, spec.OldColumnName.Name.O) {
 					v.line[table] = node.OriginTextPosition()
+					v.suggestion[table] = requiredColumnDropSuggestion
 				}
 			// CHANGE COLUMN
 			case ast.AlterTableChangeColumn:
 				if v.renameColumn(table, spec.OldColumnName.Name.O, spec.NewColumns[0].Name.Name.O) {
 					v.line[table] = node.OriginTextPosition()
+					v.suggestion[table] = requiredColumnRenameSuggestion
 				}
 			}
 		}
@@ -131,7 +145,7 @@ func (v *columnRequirementChecker) generateAdviceList() []advisor.Advice {
 				Status:  v.level,
 				Code:    advisor.NoRequiredColumn,
 				Title:   v.title,
-				Content: fmt.Sprintf("Table `%s` requires columns: %s", tableName, strings.Join(missingColumns, ", ")),
+				Content: fmt.Sprintf("Table `%s` requires columns: %s. %s", tableName, strings.Join(missingColumns, ", "), v.suggestion[tableName]),
 				Line:    v.line[tableName],
 			})
 		}
@@ -213,6 +227,7 @@ func (v *columnRequirementChecker) addColumn(table string, column string) {
 
 func (v *columnRequirementChecker) createTable(node *ast.CreateTableStmt) {
 	v.line[node.Table.Name.O] = node.OriginTextPosition()
+	v.suggestion[node.Table.Name.O] = requiredColumnCreateSuggestion
 	v.initEmptyTable(node.Table.Name.O)
 	for _, column := range node.Cols {
 		v.addColumn(node.Table.Name.O, column.Name.Name.O)
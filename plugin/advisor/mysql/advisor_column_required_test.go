@@ -17,7 +17,7 @@ func TestColumnRequirement(t *testing.T) {
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table `book` requires columns: created_ts, creator_id, updated_ts, updater_id",
+					Content: "Table `book` requires columns: created_ts, creator_id, updated_ts, updater_id. Add the missing columns to the CREATE TABLE statement",
 					Line:    1,
 				},
 			},
@@ -51,7 +51,7 @@ func TestColumnRequirement(t *testing.T) {
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table `book` requires columns: creator_id",
+					Content: "Table `book` requires columns: creator_id. Keep the required column names instead of renaming them",
 					Line:    7,
 				},
 			},
@@ -86,7 +86,7 @@ func TestColumnRequirement(t *testing.T) {
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table `book` requires columns: creator_id",
+					Content: "Table `book` requires columns: creator_id. Keep the required column names instead of renaming them",
 					Line:    7,
 				},
 			},
@@ -121,7 +121,7 @@ func TestColumnRequirement(t *testing.T) {
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table `book` requires columns: creator_id",
+					Content: "Table `book` requires columns: creator_id. Keep the required columns instead of dropping them",
 					Line:    7,
 				},
 			},
@@ -156,7 +156,7 @@ func TestColumnRequirement(t *testing.T) {
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table `book` requires columns: updater_id",
+					Content: "Table `book` requires columns: updater_id. Add the missing columns to the CREATE TABLE statement",
 					Line:    1,
 				},
 			},
@@ -193,14 +193,14 @@ func TestColumnRequirement(t *testing.T) {
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table `book` requires columns: creator_id",
+					Content: "Table `book` requires columns: creator_id. Add the missing columns to the CREATE TABLE statement",
 					Line:    1,
 				},
 				{
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table `student` requires columns: creator_id, updater_id",
+					Content: "Table `student` requires columns: creator_id, updater_id. Add the missing columns to the CREATE TABLE statement",
 					Line:    6,
 				},
 			},
@@ -0,0 +1,211 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	dbdriver "github.com/bytebase/bytebase/plugin/db"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+)
+
+var (
+	_ advisor.Advisor = (*ColumnSetDefaultForNotNullAdvisor)(nil)
+	_ ast.Visitor     = (*columnSetDefaultForNotNullChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLColumnSetDefaultForNotNull, &ColumnSetDefaultForNotNullAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLColumnSetDefaultForNotNull, &ColumnSetDefaultForNotNullAdvisor{})
+}
+
+// ColumnSetDefaultForNotNullAdvisor is the advisor checking for set default value for not null column.
+type ColumnSetDefaultForNotNullAdvisor struct {
+}
+
+// Check checks for set default value for not null column.
+func (*ColumnSetDefaultForNotNullAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &columnSetDefaultForNotNullChecker{
+		level:        level,
+		title:        string(ctx.Rule.Type),
+		database:     ctx.Database,
+		createdTable: make(map[string]bool),
+		instantDDL:   ctx.HasCapability(dbdriver.CapabilityInstantDDL),
+	}
+
+	for _, stmtNode := range root {
+		(stmtNode).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type columnSetDefaultForNotNullChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	database   *catalog.Database
+	// createdTable is the tables created in the same statements, which are empty.
+	createdTable map[string]bool
+	// instantDDL is true if the engine version adds the columns without rebuilding the table.
+	instantDDL bool
+}
+
+// Enter implements the ast.Visitor interface.
+func (v *columnSetDefaultForNotNullChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		v.createdTable[node.Table.Name.String()] = true
+		pkColumns := make(columnSet)
+		for _, constraint := range node.Constraints {
+			if constraint.Tp != ast.ConstraintPrimaryKey {
+				continue
+			}
+			for _, key := range constraint.Keys {
+				if key.Column != nil {
+					pkColumns[key.Column.Name.String()] = true
+				}
+			}
+		}
+		for _, column := range node.Cols {
+			if pkColumns[column.Name.Name.String()] {
+				continue
+			}
+			v.checkColumn(node.Table.Name.String(), column, column.OriginTextPosition())
+		}
+	// ALTER TABLE
+	case *ast.AlterTableStmt:
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			// ADD COLUMNS
+			case ast.AlterTableAddColumns:
+				for _, column := range spec.NewColumns {
+					v.checkAddColumn(node.Table.Name.String(), column, node.OriginTextPosition())
+				}
+			// CHANGE COLUMN, MODIFY COLUMN
+			case ast.AlterTableChangeColumn, ast.AlterTableModifyColumn:
+				for _, column := range spec.NewColumns {
+					v.checkColumn(node.Table.Name.String(), column, node.OriginTextPosition())
+				}
+			}
+		}
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*columnSetDefaultForNotNullChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *columnSetDefaultForNotNullChecker) checkColumn(tableName string, column *ast.ColumnDef, line int) {
+	if !requireDefault(column) {
+		return
+	}
+	v.adviceList = append(v.adviceList, advisor.Advice{
+		Status:  v.level,
+		Code:    advisor.NotNullColumnWithNoDefault,
+		Title:   v.title,
+		Content: fmt.Sprintf("Column `%s`.`%s` is NOT NULL but doesn't have DEFAULT", tableName, column.Name.Name.String()),
+		Line:    line,
+	})
+}
+
+// checkAddColumn checks the column added to the table, which fills the column for the existing rows.
+func (v *columnSetDefaultForNotNullChecker) checkAddColumn(tableName string, column *ast.ColumnDef, line int) {
+	// The tables created in the same statements are empty, and the tables unknown to the catalog are either created by other statements or not synced yet.
+	var table *catalog.Table
+	if !v.createdTable[tableName] {
+		table = v.database.FindTable(&catalog.TableFind{TableName: tableName})
+	}
+	if table == nil {
+		v.checkColumn(tableName, column, line)
+		return
+	}
+	if !requireDefault(column) {
+		return
+	}
+	// The row count is synced from the table statistics and may be stale, so we only warn if the table seems empty.
+	if table.RowCount > 0 {
+		impact := "may lock the table or fail"
+		if v.instantDDL {
+			impact = "may fail"
+		}
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    advisor.NotNullColumnWithNoDefault,
+			Title:   v.title,
+			Content: fmt.Sprintf("Adding NOT NULL column `%s`.`%s` without default value to the table with about %d rows %s", tableName, column.Name.Name.String(), table.RowCount, impact),
+			Line:    line,
+		})
+		return
+	}
+	v.adviceList = append(v.adviceList, advisor.Advice{
+		Status:  advisor.Warn,
+		Code:    advisor.NotNullColumnWithNoDefault,
+		Title:   v.title,
+		Content: fmt.Sprintf("Adding NOT NULL column `%s`.`%s` without default value may fail if the table is not empty", tableName, column.Name.Name.String()),
+		Line:    line,
+	})
+}
+
+// requireDefault returns true if the column is NOT NULL without default value, and a default value can be set.
+func requireDefault(column *ast.ColumnDef) bool {
+	if !isNotNullWithoutDefault(column) || !canSetDefault(column) {
+		return false
+	}
+	for _, option := range column.Options {
+		// The primary key values are supplied on insertion.
+		if option.Tp == ast.ColumnOptionPrimaryKey {
+			return false
+		}
+	}
+	return true
+}
+
+// isNotNullWithoutDefault returns true if the column is NOT NULL and its value cannot be filled for the existing rows.
+func isNotNullWithoutDefault(column *ast.ColumnDef) bool {
+	notNull := false
+	for _, option := range column.Options {
+		switch option.Tp {
+		case ast.ColumnOptionNotNull:
+			notNull = true
+		case ast.ColumnOptionDefaultValue, ast.ColumnOptionAutoIncrement, ast.ColumnOptionGenerated:
+			return false
+		}
+	}
+	return notNull
+}
+
+// canSetDefault returns false for the BLOB, TEXT, GEOMETRY and JSON columns, which can't have a literal default value.
+func canSetDefault(column *ast.ColumnDef) bool {
+	if column.Tp == nil {
+		return true
+	}
+	switch column.Tp.Tp {
+	case mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeGeometry, mysql.TypeJSON:
+		return false
+	}
+	return true
+}
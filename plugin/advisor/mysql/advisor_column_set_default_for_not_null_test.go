@@ -0,0 +1,218 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+func TestColumnSetDefaultForNotNull(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE t(a int NOT NULL DEFAULT 0, b int, c text NOT NULL, d int NOT NULL AUTO_INCREMENT, e int AS (a + 1) NOT NULL)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		// The primary key columns are skipped.
+		{
+			Statement: `CREATE TABLE t(
+				id int NOT NULL,
+				seq int PRIMARY KEY,
+				name varchar(255) NOT NULL,
+				PRIMARY KEY (id)
+			)`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: "Column `t`.`name` is NOT NULL but doesn't have DEFAULT",
+					Line:    4,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book ADD COLUMN (name varchar(255) NOT NULL, price int NOT NULL DEFAULT 0)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: "Column `book`.`name` is NOT NULL but doesn't have DEFAULT",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book CHANGE COLUMN name title varchar(255) NOT NULL; ALTER TABLE book MODIFY COLUMN price int NOT NULL",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: "Column `book`.`title` is NOT NULL but doesn't have DEFAULT",
+					Line:    1,
+				},
+				{
+					Status:  advisor.Error,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: "Column `book`.`price` is NOT NULL but doesn't have DEFAULT",
+					Line:    1,
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &ColumnSetDefaultForNotNullAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleColumnSetDefaultForNotNull,
+		Level:   advisor.SchemaRuleLevelError,
+		Payload: "",
+	}, advisor.MockMySQLDatabase)
+}
+
+func TestColumnSetDefaultForNotNullAddColumn(t *testing.T) {
+	database := &catalog.Database{
+		Name:   "test",
+		DbType: db.MySQL,
+		SchemaList: []*catalog.Schema{
+			{
+				TableList: []*catalog.Table{
+					{
+						Name:     "book",
+						RowCount: 1000,
+					},
+					{
+						Name:     "author",
+						RowCount: 0,
+					},
+				},
+			},
+		},
+	}
+
+	tests := []advisor.TestCase{
+		{
+			Statement: "ALTER TABLE book ADD COLUMN name varchar(255) NOT NULL",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: "Adding NOT NULL column `book`.`name` without default value to the table with about 1000 rows may lock the table or fail",
+					Line:    1,
+				},
+			},
+		},
+		// The row count may be stale, so the empty tables are warned only.
+		{
+			Statement: "ALTER TABLE author ADD COLUMN name varchar(255) NOT NULL",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: "Adding NOT NULL column `author`.`name` without default value may fail if the table is not empty",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book ADD COLUMN (name varchar(255) NOT NULL DEFAULT '', price int, seq int NOT NULL AUTO_INCREMENT)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		// The tables created in the same statements and the unknown tables are checked regardless of the row count.
+		{
+			Statement: `CREATE TABLE tech_book(id int);
+			ALTER TABLE tech_book ADD COLUMN name varchar(255) NOT NULL;
+			ALTER TABLE unknown ADD COLUMN name varchar(255) NOT NULL`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: "Column `tech_book`.`name` is NOT NULL but doesn't have DEFAULT",
+					Line:    2,
+				},
+				{
+					Status:  advisor.Error,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: "Column `unknown`.`name` is NOT NULL but doesn't have DEFAULT",
+					Line:    3,
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &ColumnSetDefaultForNotNullAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleColumnSetDefaultForNotNull,
+		Level:   advisor.SchemaRuleLevelError,
+		Payload: "",
+	}, database)
+}
+
+func TestColumnSetDefaultForNotNullInstantDDL(t *testing.T) {
+	a := require.New(t)
+	database := &catalog.Database{
+		Name:   "test",
+		DbType: db.MySQL,
+		SchemaList: []*catalog.Schema{
+			{
+				TableList: []*catalog.Table{
+					{
+						Name:     "book",
+						RowCount: 1000,
+					},
+				},
+			},
+		},
+	}
+	rule := &advisor.SQLReviewRule{
+		Type:  advisor.SchemaRuleColumnSetDefaultForNotNull,
+		Level: advisor.SchemaRuleLevelError,
+	}
+	tests := []struct {
+		engineVersion string
+		want          string
+	}{
+		{
+			// The column is added without rebuilding the table.
+			engineVersion: "8.0.28",
+			want:          "Adding NOT NULL column `book`.`name` without default value to the table with about 1000 rows may fail",
+		},
+		{
+			engineVersion: "5.7.38-log",
+			want:          "Adding NOT NULL column `book`.`name` without default value to the table with about 1000 rows may lock the table or fail",
+		},
+	}
+
+	for _, test := range tests {
+		adviceList, err := (&ColumnSetDefaultForNotNullAdvisor{}).Check(advisor.Context{
+			DbType:        db.MySQL,
+			EngineVersion: test.engineVersion,
+			Rule:          rule,
+			Database:      database,
+		}, "ALTER TABLE book ADD COLUMN name varchar(255) NOT NULL")
+		a.NoError(err)
+		a.Len(adviceList, 1)
+		a.Equal(test.want, adviceList[0].Content, test.engineVersion)
+	}
+}
@@ -0,0 +1,175 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/pingcap/tidb/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*ColumnTimestampConventionAdvisor)(nil)
+	_ ast.Visitor     = (*columnTimestampConventionChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLColumnTimestampConvention, &ColumnTimestampConventionAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLColumnTimestampConvention, &ColumnTimestampConventionAdvisor{})
+}
+
+// ColumnTimestampConventionAdvisor is the advisor checking for the created and updated timestamp column convention.
+type ColumnTimestampConventionAdvisor struct {
+}
+
+// Check checks for the created and updated timestamp column convention.
+func (*ColumnTimestampConventionAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalTimestampConventionRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &columnTimestampConventionChecker{
+		level:         level,
+		title:         string(ctx.Rule.Type),
+		createdColumn: payload.CreatedColumn,
+		updatedColumn: payload.UpdatedColumn,
+	}
+
+	for _, stmtNode := range root {
+		(stmtNode).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type columnTimestampConventionChecker struct {
+	adviceList    []advisor.Advice
+	level         advisor.Status
+	title         string
+	createdColumn string
+	updatedColumn string
+}
+
+// Enter implements the ast.Visitor interface.
+func (v *columnTimestampConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		tableName := node.Table.Name.String()
+		// CREATE TABLE ... LIKE copies the column definitions from the existing table.
+		if node.ReferTable != nil {
+			break
+		}
+		hasCreatedColumn, hasUpdatedColumn := false, false
+		for _, column := range node.Cols {
+			switch column.Name.Name.String() {
+			case v.createdColumn:
+				hasCreatedColumn = true
+			case v.updatedColumn:
+				hasUpdatedColumn = true
+			}
+			v.checkColumn(tableName, column, column.OriginTextPosition())
+		}
+		if v.createdColumn != "" && !hasCreatedColumn {
+			v.addMissingColumnAdvice(tableName, v.createdColumn, node.OriginTextPosition())
+		}
+		if v.updatedColumn != "" && !hasUpdatedColumn {
+			v.addMissingColumnAdvice(tableName, v.updatedColumn, node.OriginTextPosition())
+		}
+	// ALTER TABLE
+	case *ast.AlterTableStmt:
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			// ADD COLUMNS, CHANGE COLUMN, MODIFY COLUMN
+			case ast.AlterTableAddColumns, ast.AlterTableChangeColumn, ast.AlterTableModifyColumn:
+				for _, column := range spec.NewColumns {
+					v.checkColumn(node.Table.Name.String(), column, node.OriginTextPosition())
+				}
+			}
+		}
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*columnTimestampConventionChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *columnTimestampConventionChecker) checkColumn(tableName string, column *ast.ColumnDef, line int) {
+	columnName := column.Name.Name.String()
+	if columnName != v.createdColumn && columnName != v.updatedColumn {
+		return
+	}
+	defaultCurrentTimestamp, onUpdateCurrentTimestamp := false, false
+	for _, option := range column.Options {
+		switch option.Tp {
+		case ast.ColumnOptionDefaultValue:
+			defaultCurrentTimestamp = isCurrentTimestamp(option.Expr)
+		case ast.ColumnOptionOnUpdate:
+			onUpdateCurrentTimestamp = isCurrentTimestamp(option.Expr)
+		}
+	}
+
+	if !defaultCurrentTimestamp {
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    advisor.ColumnTimestampConventionMismatch,
+			Title:   v.title,
+			Content: fmt.Sprintf("`%s`.`%s` should have DEFAULT CURRENT_TIMESTAMP", tableName, columnName),
+			Line:    line,
+		})
+	}
+	switch {
+	case columnName == v.updatedColumn && !onUpdateCurrentTimestamp:
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    advisor.ColumnTimestampConventionMismatch,
+			Title:   v.title,
+			Content: fmt.Sprintf("`%s`.`%s` should have ON UPDATE CURRENT_TIMESTAMP", tableName, columnName),
+			Line:    line,
+		})
+	case columnName == v.createdColumn && onUpdateCurrentTimestamp:
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    advisor.ColumnTimestampConventionMismatch,
+			Title:   v.title,
+			Content: fmt.Sprintf("`%s`.`%s` should not have ON UPDATE CURRENT_TIMESTAMP", tableName, columnName),
+			Line:    line,
+		})
+	}
+}
+
+func (v *columnTimestampConventionChecker) addMissingColumnAdvice(tableName string, columnName string, line int) {
+	v.adviceList = append(v.adviceList, advisor.Advice{
+		Status:  v.level,
+		Code:    advisor.ColumnTimestampConventionMismatch,
+		Title:   v.title,
+		Content: fmt.Sprintf("Table `%s` requires the timestamp column `%s`", tableName, columnName),
+		Line:    line,
+	})
+}
+
+// isCurrentTimestamp returns true if the expression is CURRENT_TIMESTAMP or its synonyms, which are all parsed as CURRENT_TIMESTAMP.
+func isCurrentTimestamp(expr ast.ExprNode) bool {
+	funcCall, ok := expr.(*ast.FuncCallExpr)
+	return ok && funcCall.FnName.L == ast.CurrentTimestamp
+}
@@ -0,0 +1,96 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestColumnTimestampConvention(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: `CREATE TABLE book(
+				id int,
+				created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at timestamp NOT NULL DEFAULT NOW() ON UPDATE CURRENT_TIMESTAMP)`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: `CREATE TABLE book(
+				id int,
+				created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				updated_at timestamp NOT NULL)`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnTimestampConventionMismatch,
+					Title:   "column.timestamp-convention",
+					Content: "`book`.`created_at` should not have ON UPDATE CURRENT_TIMESTAMP",
+					Line:    3,
+				},
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnTimestampConventionMismatch,
+					Title:   "column.timestamp-convention",
+					Content: "`book`.`updated_at` should have DEFAULT CURRENT_TIMESTAMP",
+					Line:    4,
+				},
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnTimestampConventionMismatch,
+					Title:   "column.timestamp-convention",
+					Content: "`book`.`updated_at` should have ON UPDATE CURRENT_TIMESTAMP",
+					Line:    4,
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE book(id int, created_at timestamp DEFAULT CURRENT_TIMESTAMP)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnTimestampConventionMismatch,
+					Title:   "column.timestamp-convention",
+					Content: "Table `book` requires the timestamp column `updated_at`",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book MODIFY COLUMN updated_at timestamp DEFAULT CURRENT_TIMESTAMP",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.ColumnTimestampConventionMismatch,
+					Title:   "column.timestamp-convention",
+					Content: "`book`.`updated_at` should have ON UPDATE CURRENT_TIMESTAMP",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book ADD COLUMN (name varchar(255), updated_at datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &ColumnTimestampConventionAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleColumnTimestampConvention,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: `{"createdColumn":"created_at","updatedColumn":"updated_at"}`,
+	}, advisor.MockMySQLDatabase)
+}
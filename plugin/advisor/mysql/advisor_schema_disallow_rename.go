@@ -0,0 +1,131 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/pingcap/tidb/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*DisallowRenameAdvisor)(nil)
+	_ ast.Visitor     = (*disallowRenameChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLDisallowRename, &DisallowRenameAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLDisallowRename, &DisallowRenameAdvisor{})
+}
+
+// DisallowRenameAdvisor is the advisor checking for renaming tables and columns.
+type DisallowRenameAdvisor struct {
+}
+
+// Check checks for renaming tables and columns.
+func (*DisallowRenameAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &disallowRenameChecker{
+		level:        level,
+		title:        string(ctx.Rule.Type),
+		createdTable: make(map[string]bool),
+	}
+
+	for _, stmtNode := range root {
+		(stmtNode).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type disallowRenameChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	// createdTable is the tables created in the same statements, which are not used by the deployed application yet.
+	createdTable map[string]bool
+}
+
+// Enter implements the ast.Visitor interface.
+func (v *disallowRenameChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		v.createdTable[node.Table.Name.String()] = true
+	// RENAME TABLE
+	case *ast.RenameTableStmt:
+		for _, tableToTable := range node.TableToTables {
+			v.checkRenameTable(tableToTable.OldTable.Name.String(), tableToTable.NewTable.Name.String(), node.OriginTextPosition())
+		}
+	// ALTER TABLE
+	case *ast.AlterTableStmt:
+		tableName := node.Table.Name.String()
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			// RENAME TABLE
+			case ast.AlterTableRenameTable:
+				v.checkRenameTable(tableName, spec.NewTable.Name.String(), node.OriginTextPosition())
+			// RENAME COLUMN
+			case ast.AlterTableRenameColumn:
+				v.checkRenameColumn(tableName, spec.OldColumnName.Name.String(), spec.NewColumnName.Name.String(), node.OriginTextPosition())
+			// CHANGE COLUMN
+			case ast.AlterTableChangeColumn:
+				for _, column := range spec.NewColumns {
+					v.checkRenameColumn(tableName, spec.OldColumnName.Name.String(), column.Name.Name.String(), node.OriginTextPosition())
+				}
+			}
+		}
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*disallowRenameChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *disallowRenameChecker) checkRenameTable(oldName string, newName string, line int) {
+	if v.createdTable[oldName] {
+		v.createdTable[newName] = true
+		return
+	}
+	v.adviceList = append(v.adviceList, advisor.Advice{
+		Status:  v.level,
+		Code:    advisor.CompatibilityRenameTable,
+		Title:   v.title,
+		Content: fmt.Sprintf("Renaming table `%s` to `%s` may break the deployed application code", oldName, newName),
+		Line:    line,
+	})
+}
+
+func (v *disallowRenameChecker) checkRenameColumn(tableName string, oldName string, newName string, line int) {
+	// Column names are case-insensitive in MySQL.
+	if v.createdTable[tableName] || strings.EqualFold(oldName, newName) {
+		return
+	}
+	v.adviceList = append(v.adviceList, advisor.Advice{
+		Status:  v.level,
+		Code:    advisor.CompatibilityRenameColumn,
+		Title:   v.title,
+		Content: fmt.Sprintf("Renaming column `%s`.`%s` to `%s` may break the deployed application code", tableName, oldName, newName),
+		Line:    line,
+	})
+}
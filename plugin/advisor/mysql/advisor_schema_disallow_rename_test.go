@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestDisallowRename(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "RENAME TABLE book TO tech_book",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityRenameTable,
+					Title:   "schema.disallow-rename",
+					Content: "Renaming table `book` to `tech_book` may break the deployed application code",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book RENAME TO tech_book",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityRenameTable,
+					Title:   "schema.disallow-rename",
+					Content: "Renaming table `book` to `tech_book` may break the deployed application code",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book RENAME COLUMN name TO title",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityRenameColumn,
+					Title:   "schema.disallow-rename",
+					Content: "Renaming column `book`.`name` to `title` may break the deployed application code",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book CHANGE COLUMN name title varchar(255)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityRenameColumn,
+					Title:   "schema.disallow-rename",
+					Content: "Renaming column `book`.`name` to `title` may break the deployed application code",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: `ALTER TABLE book CHANGE COLUMN name NAME varchar(255);
+				CREATE TABLE author(id int, name varchar(255));
+				RENAME TABLE author TO tech_author;
+				ALTER TABLE tech_author RENAME COLUMN name TO full_name`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &DisallowRenameAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleSchemaDisallowRename,
+		Level:   advisor.SchemaRuleLevelError,
+		Payload: "",
+	}, advisor.MockMySQLDatabase)
+}
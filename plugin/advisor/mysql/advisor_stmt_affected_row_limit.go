@@ -0,0 +1,167 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+
+	"github.com/pingcap/tidb/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*AffectedRowLimitAdvisor)(nil)
+	_ ast.Visitor     = (*affectedRowLimitChecker)(nil)
+	_ ast.Visitor     = (*columnNameCollector)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLAffectedRowLimit, &AffectedRowLimitAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLAffectedRowLimit, &AffectedRowLimitAdvisor{})
+}
+
+// AffectedRowLimitAdvisor is the advisor checking for the estimated rows locked by the UPDATE and DELETE statements.
+type AffectedRowLimitAdvisor struct {
+}
+
+// Check checks for the estimated rows locked by the UPDATE and DELETE statements.
+func (*AffectedRowLimitAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalAffectedRowLimitRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &affectedRowLimitChecker{
+		level:    level,
+		title:    string(ctx.Rule.Type),
+		maxRows:  payload.MaxRows,
+		database: ctx.Database,
+	}
+	for _, stmtNode := range root {
+		checker.text = stmtNode.Text()
+		checker.line = stmtNode.OriginTextPosition()
+		(stmtNode).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type affectedRowLimitChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	text       string
+	line       int
+	maxRows    int
+	database   *catalog.Database
+}
+
+// Enter implements the ast.Visitor interface.
+func (v *affectedRowLimitChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	// UPDATE
+	case *ast.UpdateStmt:
+		v.checkTable(node.TableRefs, node.Where)
+	// DELETE
+	case *ast.DeleteStmt:
+		if !node.IsMultiTable {
+			v.checkTable(node.TableRefs, node.Where)
+		}
+	}
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*affectedRowLimitChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *affectedRowLimitChecker) checkTable(tableRefs *ast.TableRefsClause, where ast.ExprNode) {
+	tableName := getSingleTableName(tableRefs)
+	if tableName == "" {
+		return
+	}
+	// Skip the tables unknown to the catalog, which are either created by other statements or not synced yet.
+	table := v.database.FindTable(&catalog.TableFind{TableName: tableName})
+	if table == nil {
+		return
+	}
+	if where != nil && useIndex(table, where) {
+		return
+	}
+	// Without an index to locate the rows, InnoDB scans and locks all rows in the table.
+	// The row count is synced from the table statistics, so it's an estimation.
+	if table.RowCount > int64(v.maxRows) {
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    advisor.StatementAffectedRowExceedsLimit,
+			Title:   v.title,
+			Content: fmt.Sprintf("\"%s\" may lock about %d rows in the table `%s` which exceeds the limit %d, please consider splitting it into batches", v.text, table.RowCount, tableName, v.maxRows),
+			Line:    v.line,
+		})
+	}
+}
+
+// getSingleTableName returns the table name if the statement only references one table, otherwise returns empty string.
+func getSingleTableName(tableRefs *ast.TableRefsClause) string {
+	if tableRefs == nil || tableRefs.TableRefs == nil || tableRefs.TableRefs.Right != nil {
+		return ""
+	}
+	tableSource, ok := tableRefs.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return ""
+	}
+	tableName, ok := tableSource.Source.(*ast.TableName)
+	if !ok {
+		return ""
+	}
+	return tableName.Name.String()
+}
+
+// useIndex returns true if the WHERE clause references the leading column of any index in the table.
+// It's a rough estimation without EXPLAIN, so we assume the index can narrow down the locked rows.
+func useIndex(table *catalog.Table, where ast.ExprNode) bool {
+	collector := &columnNameCollector{columnSet: make(columnSet)}
+	where.Accept(collector)
+	for _, index := range table.IndexList {
+		if len(index.ExpressionList) > 0 && collector.columnSet[index.ExpressionList[0]] {
+			return true
+		}
+	}
+	return false
+}
+
+// columnNameCollector collects the column names referenced in the expression.
+type columnNameCollector struct {
+	columnSet columnSet
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *columnNameCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if node, ok := in.(*ast.ColumnNameExpr); ok {
+		c.columnSet[node.Name.Name.String()] = true
+	}
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*columnNameCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+func TestAffectedRowLimit(t *testing.T) {
+	database := &catalog.Database{
+		Name:   "test",
+		DbType: db.MySQL,
+		SchemaList: []*catalog.Schema{
+			{
+				TableList: []*catalog.Table{
+					{
+						Name:     "book",
+						RowCount: 5000,
+						IndexList: []*catalog.Index{
+							{
+								Name:           "PRIMARY",
+								ExpressionList: []string{"id"},
+								Unique:         true,
+								Primary:        true,
+							},
+							{
+								Name:           "idx_book_author_id_name",
+								ExpressionList: []string{"author_id", "name"},
+							},
+						},
+					},
+					{
+						Name:     "author",
+						RowCount: 100,
+					},
+				},
+			},
+		},
+	}
+
+	tests := []advisor.TestCase{
+		{
+			Statement: "DELETE FROM book",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementAffectedRowExceedsLimit,
+					Title:   "statement.affected-row-limit",
+					Content: "\"DELETE FROM book\" may lock about 5000 rows in the table `book` which exceeds the limit 1000, please consider splitting it into batches",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "UPDATE book SET price = 0 WHERE name = 'bytebase'",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementAffectedRowExceedsLimit,
+					Title:   "statement.affected-row-limit",
+					Content: "\"UPDATE book SET price = 0 WHERE name = 'bytebase'\" may lock about 5000 rows in the table `book` which exceeds the limit 1000, please consider splitting it into batches",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: `UPDATE book SET price = 0 WHERE id = 1;
+				DELETE FROM book WHERE author_id IN (1, 2);
+				DELETE FROM author;
+				UPDATE book, author SET book.price = 0 WHERE book.author_id = author.id;
+				DELETE FROM unknown`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &AffectedRowLimitAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementAffectedRowLimit,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: `{"maxRows":1000}`,
+	}, database)
+}
@@ -0,0 +1,150 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/pingcap/tidb/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*DisallowDropTableAdvisor)(nil)
+	_ advisor.Advisor = (*DisallowDropDatabaseAdvisor)(nil)
+	_ advisor.Advisor = (*DisallowDropColumnAdvisor)(nil)
+	_ ast.Visitor     = (*disallowDropChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLDisallowDropTable, &DisallowDropTableAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLDisallowDropTable, &DisallowDropTableAdvisor{})
+	advisor.Register(db.MySQL, advisor.MySQLDisallowDropDatabase, &DisallowDropDatabaseAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLDisallowDropDatabase, &DisallowDropDatabaseAdvisor{})
+	advisor.Register(db.MySQL, advisor.MySQLDisallowDropColumn, &DisallowDropColumnAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLDisallowDropColumn, &DisallowDropColumnAdvisor{})
+}
+
+// DisallowDropTableAdvisor is the advisor checking for dropping tables.
+type DisallowDropTableAdvisor struct {
+}
+
+// Check checks for dropping tables.
+func (*DisallowDropTableAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	return checkDisallowDrop(ctx, statement, advisor.CompatibilityDropTable)
+}
+
+// DisallowDropDatabaseAdvisor is the advisor checking for dropping databases.
+type DisallowDropDatabaseAdvisor struct {
+}
+
+// Check checks for dropping databases.
+func (*DisallowDropDatabaseAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	return checkDisallowDrop(ctx, statement, advisor.CompatibilityDropDatabase)
+}
+
+// DisallowDropColumnAdvisor is the advisor checking for dropping columns.
+type DisallowDropColumnAdvisor struct {
+}
+
+// Check checks for dropping columns.
+func (*DisallowDropColumnAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	return checkDisallowDrop(ctx, statement, advisor.CompatibilityDropColumn)
+}
+
+// checkDisallowDrop checks for dropping the objects of the kind identified by the advice code.
+func checkDisallowDrop(ctx advisor.Context, statement string, code advisor.Code) ([]advisor.Advice, error) {
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &disallowDropChecker{
+		level:        level,
+		title:        string(ctx.Rule.Type),
+		code:         code,
+		createdTable: make(map[string]bool),
+	}
+
+	for _, stmtNode := range root {
+		(stmtNode).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type disallowDropChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	code       advisor.Code
+	// createdTable is the tables created in the same statements, which have no data to lose yet.
+	createdTable map[string]bool
+}
+
+// Enter implements the ast.Visitor interface.
+func (v *disallowDropChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		v.createdTable[node.Table.Name.String()] = true
+	// DROP TABLE
+	case *ast.DropTableStmt:
+		// Dropping the views and the temporary tables doesn't lose data.
+		if v.code != advisor.CompatibilityDropTable || node.IsView || node.TemporaryKeyword != ast.TemporaryNone {
+			break
+		}
+		for _, table := range node.Tables {
+			if v.createdTable[table.Name.String()] {
+				continue
+			}
+			v.addAdvice(fmt.Sprintf("Dropping table `%s` is disallowed", table.Name.String()), node.OriginTextPosition())
+		}
+	// DROP DATABASE
+	case *ast.DropDatabaseStmt:
+		if v.code != advisor.CompatibilityDropDatabase {
+			break
+		}
+		v.addAdvice(fmt.Sprintf("Dropping database `%s` is disallowed", node.Name), node.OriginTextPosition())
+	// ALTER TABLE
+	case *ast.AlterTableStmt:
+		tableName := node.Table.Name.String()
+		if v.code != advisor.CompatibilityDropColumn || v.createdTable[tableName] {
+			break
+		}
+		for _, spec := range node.Specs {
+			// DROP COLUMN
+			if spec.Tp == ast.AlterTableDropColumn {
+				v.addAdvice(fmt.Sprintf("Dropping column `%s`.`%s` is disallowed", tableName, spec.OldColumnName.Name.String()), node.OriginTextPosition())
+			}
+		}
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*disallowDropChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *disallowDropChecker) addAdvice(content string, line int) {
+	v.adviceList = append(v.adviceList, advisor.Advice{
+		Status:  v.level,
+		Code:    v.code,
+		Title:   v.title,
+		Content: content,
+		Line:    line,
+	})
+}
@@ -31,6 +31,7 @@ func (*SyntaxAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Ad
 				Code:    advisor.StatementSyntaxError,
 				Title:   "Syntax error",
 				Content: err.Error(),
+				Line:    syntaxErrorLine(err),
 			},
 		}, nil
 	}
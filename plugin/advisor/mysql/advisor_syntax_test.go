@@ -0,0 +1,38 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestMySQLSyntax(t *testing.T) {
+	tests := []struct {
+		statement string
+		status    advisor.Status
+		line      int
+	}{
+		{
+			statement: "CREATE TABLE book(id int);",
+			status:    advisor.Success,
+			line:      0,
+		},
+		{
+			statement: "CREATE TABLE book(id int);\nCREATE TABLE author(id int)\nENGINE=INNODB DEFAULT;",
+			status:    advisor.Error,
+			line:      3,
+		},
+	}
+
+	adv := &SyntaxAdvisor{}
+
+	for _, test := range tests {
+		adviceList, err := adv.Check(advisor.Context{}, test.statement)
+		require.NoError(t, err)
+		require.Len(t, adviceList, 1)
+		require.Equal(t, test.status, adviceList[0].Status, adviceList[0].Content)
+		require.Equal(t, test.line, adviceList[0].Line, adviceList[0].Content)
+	}
+}
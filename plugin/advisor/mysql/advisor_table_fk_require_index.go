@@ -0,0 +1,178 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/pingcap/tidb/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*TableFKRequireIndexAdvisor)(nil)
+	_ ast.Visitor     = (*tableFKRequireIndexChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLTableFKRequireIndex, &TableFKRequireIndexAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLTableFKRequireIndex, &TableFKRequireIndexAdvisor{})
+}
+
+// TableFKRequireIndexAdvisor is the advisor checking for the supporting index of foreign keys.
+type TableFKRequireIndexAdvisor struct {
+}
+
+// Check checks for the supporting index of foreign keys.
+func (*TableFKRequireIndexAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &tableFKRequireIndexChecker{
+		level:    level,
+		title:    string(ctx.Rule.Type),
+		database: ctx.Database,
+		indexMap: make(map[string][][]string),
+	}
+
+	for _, stmtNode := range root {
+		(stmtNode).Accept(checker)
+	}
+	// The index may be created after the foreign key in the same statements, so we check the foreign keys at last.
+	for _, fk := range checker.fkList {
+		if checker.hasSupportingIndex(fk.table, fk.columnList) {
+			continue
+		}
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.TableFKNoIndex,
+			Title:   checker.title,
+			Content: fmt.Sprintf("Foreign key columns (%s) in the table `%s` have no supporting index", strings.Join(fk.columnList, ", "), fk.table),
+			Line:    fk.line,
+		})
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type foreignKeyDef struct {
+	table      string
+	columnList []string
+	line       int
+}
+
+type tableFKRequireIndexChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	database   *catalog.Database
+	fkList     []foreignKeyDef
+	// indexMap is the column lists of the indexes defined in the statements for each table.
+	indexMap map[string][][]string
+}
+
+// Enter implements the ast.Visitor interface.
+func (v *tableFKRequireIndexChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.CreateTableStmt:
+		table := node.Table.Name.String()
+		for _, column := range node.Cols {
+			for _, option := range column.Options {
+				if option.Tp == ast.ColumnOptionPrimaryKey || option.Tp == ast.ColumnOptionUniqKey {
+					v.indexMap[table] = append(v.indexMap[table], []string{column.Name.Name.String()})
+				}
+			}
+		}
+		for _, constraint := range node.Constraints {
+			v.addConstraint(table, constraint, constraint.OriginTextPosition())
+		}
+	case *ast.AlterTableStmt:
+		table := node.Table.Name.String()
+		for _, spec := range node.Specs {
+			if spec.Tp == ast.AlterTableAddConstraint {
+				v.addConstraint(table, spec.Constraint, node.OriginTextPosition())
+			}
+		}
+	case *ast.CreateIndexStmt:
+		table := node.Table.Name.String()
+		v.indexMap[table] = append(v.indexMap[table], indexColumnList(node.IndexPartSpecifications))
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*tableFKRequireIndexChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *tableFKRequireIndexChecker) addConstraint(table string, constraint *ast.Constraint, line int) {
+	switch constraint.Tp {
+	case ast.ConstraintForeignKey:
+		v.fkList = append(v.fkList, foreignKeyDef{
+			table:      table,
+			columnList: indexColumnList(constraint.Keys),
+			line:       line,
+		})
+	case ast.ConstraintPrimaryKey, ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		v.indexMap[table] = append(v.indexMap[table], indexColumnList(constraint.Keys))
+	}
+}
+
+// hasSupportingIndex returns true if there's an index whose leading columns are the foreign key columns.
+func (v *tableFKRequireIndexChecker) hasSupportingIndex(table string, fkColumnList []string) bool {
+	indexList := v.indexMap[table]
+	if t := v.database.FindTable(&catalog.TableFind{TableName: table}); t != nil {
+		for _, index := range t.IndexList {
+			indexList = append(indexList, index.ExpressionList)
+		}
+	}
+	for _, indexColumnList := range indexList {
+		if isLeadingColumnList(indexColumnList, fkColumnList) {
+			return true
+		}
+	}
+	return false
+}
+
+func indexColumnList(keyList []*ast.IndexPartSpecification) []string {
+	var columnList []string
+	for _, key := range keyList {
+		// The expression index cannot support the foreign key lookup.
+		if key.Column == nil {
+			columnList = append(columnList, "")
+			continue
+		}
+		columnList = append(columnList, key.Column.Name.String())
+	}
+	return columnList
+}
+
+// isLeadingColumnList returns true if the leading columns of the index are the column list in any order.
+func isLeadingColumnList(indexColumnList []string, columnList []string) bool {
+	if len(columnList) == 0 || len(indexColumnList) < len(columnList) {
+		return false
+	}
+	columnSet := newColumnSet(columnList)
+	for _, column := range indexColumnList[:len(columnList)] {
+		if !columnSet[column] {
+			return false
+		}
+	}
+	return true
+}
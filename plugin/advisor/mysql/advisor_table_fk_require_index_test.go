@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestTableFKRequireIndex(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: `CREATE TABLE book(
+				id int PRIMARY KEY,
+				author_id int,
+				publisher_id int,
+				FOREIGN KEY (author_id) REFERENCES author(id),
+				INDEX idx_publisher (publisher_id, id),
+				FOREIGN KEY (publisher_id) REFERENCES publisher(id))`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableFKNoIndex,
+					Title:   "table.fk-require-index",
+					Content: "Foreign key columns (author_id) in the table `book` have no supporting index",
+					Line:    5,
+				},
+			},
+		},
+		{
+			Statement: `CREATE TABLE book(id int PRIMARY KEY, author_id int, FOREIGN KEY (author_id) REFERENCES author(id));
+				CREATE INDEX idx_author ON book(author_id)`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE tech_book ADD CONSTRAINT fk_name FOREIGN KEY (name) REFERENCES author(name)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableFKNoIndex,
+					Title:   "table.fk-require-index",
+					Content: "Foreign key columns (name) in the table `tech_book` have no supporting index",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE tech_book ADD CONSTRAINT fk_book FOREIGN KEY (name, id) REFERENCES book(name, id)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &TableFKRequireIndexAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleTableFKRequireIndex,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: "",
+	}, advisor.MockMySQLDatabase)
+}
@@ -2,6 +2,8 @@ package mysql
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/bytebase/bytebase/plugin/parser"
@@ -9,6 +11,9 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 )
 
+// syntaxErrorLineRegexp matches the line of the TiDB parser syntax error, e.g. "line 1 column 19 near ...".
+var syntaxErrorLineRegexp = regexp.MustCompile(`line (\d+) column \d+`)
+
 // Wrapper for parser.New().
 func newParser() *tidbparser.Parser {
 	p := tidbparser.New()
@@ -31,6 +36,7 @@ func parseStatement(statement string, charset string, collation string) ([]ast.S
 				Code:    advisor.StatementSyntaxError,
 				Title:   advisor.SyntaxErrorTitle,
 				Content: err.Error(),
+				Line:    syntaxErrorLine(err),
 			},
 		}
 	}
@@ -73,3 +79,16 @@ func parseStatement(statement string, charset string, collation string) ([]ast.S
 	}
 	return root, nil
 }
+
+// syntaxErrorLine returns the line of the syntax error, or 0 if unknown.
+func syntaxErrorLine(err error) int {
+	match := syntaxErrorLineRegexp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	line, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return line
+}
@@ -21,6 +21,13 @@ func init() {
 
 type columnSet map[string]bool
 
+// The fix suggestions in the advice content, which depend on how the required columns go missing.
+const (
+	requiredColumnCreateSuggestion = "Add the missing columns to the CREATE TABLE statement"
+	requiredColumnDropSuggestion   = "Keep the required columns instead of dropping them"
+	requiredColumnRenameSuggestion = "Keep the required column names instead of renaming them"
+)
+
 // ColumnRequirementAdvisor is the advisor checking for column requirement.
 type ColumnRequirementAdvisor struct {
 }
@@ -77,6 +84,7 @@ type columnRequirementChecker struct {
 func (checker *columnRequirementChecker) Visit(node ast.Node) ast.Visitor {
 	var table *ast.TableDef
 	var missingColumns []string
+	var suggestion string
 	switch n := node.(type) {
 	// CREATE TABLE
 	case *ast.CreateTableStmt:
@@ -88,18 +96,21 @@ func (checker *columnRequirementChecker) Visit(node ast.Node) ast.Visitor {
 			for column := range checker.requiredColumns {
 				missingColumns = append(missingColumns, column)
 			}
+			suggestion = requiredColumnCreateSuggestion
 		}
 	// ALTER TABLE DROP COLUMN
 	case *ast.DropColumnStmt:
 		if _, yes := checker.requiredColumns[n.ColumnName]; yes {
 			table = n.Table
 			missingColumns = append(missingColumns, n.ColumnName)
+			suggestion = requiredColumnDropSuggestion
 		}
 	// ALTER TABLE RENAME COLUMN
 	case *ast.RenameColumnStmt:
 		if _, yes := checker.requiredColumns[n.ColumnName]; yes && n.ColumnName != n.NewName {
 			table = n.Table
 			missingColumns = append(missingColumns, n.ColumnName)
+			suggestion = requiredColumnRenameSuggestion
 		}
 	}
 
@@ -110,7 +121,7 @@ func (checker *columnRequirementChecker) Visit(node ast.Node) ast.Visitor {
 			Status:  checker.level,
 			Code:    advisor.NoRequiredColumn,
 			Title:   checker.title,
-			Content: fmt.Sprintf("Table %q requires columns: %s", table.Name, strings.Join(missingColumns, ", ")),
+			Content: fmt.Sprintf("Table %q requires columns: %s. %s", table.Name, strings.Join(missingColumns, ", "), suggestion),
 			Line:    node.Line(),
 		})
 	}
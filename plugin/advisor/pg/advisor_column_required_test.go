@@ -17,7 +17,7 @@ func TestColumnRequirement(t *testing.T) {
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table \"book\" requires columns: created_ts, creator_id, updated_ts, updater_id",
+					Content: "Table \"book\" requires columns: created_ts, creator_id, updated_ts, updater_id. Add the missing columns to the CREATE TABLE statement",
 					Line:    1,
 				},
 			},
@@ -45,7 +45,7 @@ func TestColumnRequirement(t *testing.T) {
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table \"book\" requires columns: creator_id",
+					Content: "Table \"book\" requires columns: creator_id. Keep the required column names instead of renaming them",
 					Line:    1,
 				},
 			},
@@ -57,7 +57,7 @@ func TestColumnRequirement(t *testing.T) {
 					Status:  advisor.Warn,
 					Code:    advisor.NoRequiredColumn,
 					Title:   "column.required",
-					Content: "Table \"book\" requires columns: creator_id",
+					Content: "Table \"book\" requires columns: creator_id. Keep the required columns instead of dropping them",
 					Line:    1,
 				},
 			},
@@ -0,0 +1,113 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*ColumnSetDefaultForNotNullAdvisor)(nil)
+	_ ast.Visitor     = (*columnSetDefaultForNotNullChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLColumnSetDefaultForNotNull, &ColumnSetDefaultForNotNullAdvisor{})
+}
+
+// ColumnSetDefaultForNotNullAdvisor is the advisor checking for set default value for not null column.
+type ColumnSetDefaultForNotNullAdvisor struct {
+}
+
+// Check checks for set default value for not null column.
+func (*ColumnSetDefaultForNotNullAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &columnSetDefaultForNotNullChecker{
+		level: level,
+		title: string(ctx.Rule.Type),
+	}
+	for _, stmt := range stmts {
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type columnSetDefaultForNotNullChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *columnSetDefaultForNotNullChecker) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		pkColumns := make(map[string]bool)
+		for _, constraint := range n.ConstraintList {
+			if constraint.Type != ast.ConstraintTypePrimary {
+				continue
+			}
+			for _, column := range constraint.KeyList {
+				pkColumns[column] = true
+			}
+		}
+		for _, column := range n.ColumnList {
+			if pkColumns[column.ColumnName] {
+				continue
+			}
+			checker.checkColumn(n.Name, column, column.Line())
+		}
+	// ALTER TABLE ADD COLUMN
+	case *ast.AddColumnListStmt:
+		for _, column := range n.ColumnList {
+			checker.checkColumn(n.Table, column, n.Line())
+		}
+	}
+
+	return checker
+}
+
+func (checker *columnSetDefaultForNotNullChecker) checkColumn(table *ast.TableDef, column *ast.ColumnDef, line int) {
+	notNull := false
+	for _, constraint := range column.ConstraintList {
+		switch constraint.Type {
+		case ast.ConstraintTypeNotNull:
+			notNull = true
+		// The primary key values are supplied on insertion.
+		case ast.ConstraintTypePrimary, ast.ConstraintTypeDefault, ast.ConstraintTypeGenerated:
+			return
+		}
+	}
+	if !notNull {
+		return
+	}
+
+	checker.adviceList = append(checker.adviceList, advisor.Advice{
+		Status:  checker.level,
+		Code:    advisor.NotNullColumnWithNoDefault,
+		Title:   checker.title,
+		Content: fmt.Sprintf("Column %q in %q.%q is NOT NULL but doesn't have DEFAULT", column.ColumnName, normalizeSchemaName(table.Schema), table.Name),
+		Line:    line,
+	})
+}
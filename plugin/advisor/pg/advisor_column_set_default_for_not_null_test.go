@@ -0,0 +1,59 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestColumnSetDefaultForNotNull(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE t(a int NOT NULL DEFAULT 0, b int, c serial NOT NULL, d int NOT NULL GENERATED ALWAYS AS IDENTITY)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		// The primary key columns are skipped.
+		{
+			Statement: `CREATE TABLE t(
+				id int NOT NULL,
+				seq int PRIMARY KEY,
+				name text NOT NULL,
+				PRIMARY KEY (id)
+			)`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: `Column "name" in "public"."t" is NOT NULL but doesn't have DEFAULT`,
+					Line:    4,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE s.book ADD COLUMN name text NOT NULL, ADD COLUMN price int NOT NULL DEFAULT 0",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: `Column "name" in "s"."book" is NOT NULL but doesn't have DEFAULT`,
+					Line:    1,
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &ColumnSetDefaultForNotNullAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleColumnSetDefaultForNotNull,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: "",
+	}, advisor.MockPostgreSQLDatabase)
+}
@@ -0,0 +1,100 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*DisallowRenameAdvisor)(nil)
+	_ ast.Visitor     = (*disallowRenameChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLDisallowRename, &DisallowRenameAdvisor{})
+}
+
+// DisallowRenameAdvisor is the advisor checking for renaming tables and columns.
+type DisallowRenameAdvisor struct {
+}
+
+// Check checks for renaming tables and columns.
+func (*DisallowRenameAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &disallowRenameChecker{
+		level:        level,
+		title:        string(ctx.Rule.Type),
+		createdTable: make(map[tableKey]bool),
+	}
+	for _, stmt := range stmts {
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type disallowRenameChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	// createdTable is the tables created in the same statements, which are not used by the deployed application yet.
+	createdTable map[tableKey]bool
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *disallowRenameChecker) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		checker.createdTable[newTableKey(n.Name)] = true
+	// ALTER TABLE/VIEW RENAME
+	case *ast.RenameTableStmt:
+		table := newTableKey(n.Table)
+		if checker.createdTable[table] {
+			checker.createdTable[tableKey{schema: table.schema, table: n.NewName}] = true
+			break
+		}
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.CompatibilityRenameTable,
+			Title:   checker.title,
+			Content: fmt.Sprintf("Renaming table %q.%q to %q may break the deployed application code", table.schema, table.table, n.NewName),
+			Line:    node.Line(),
+		})
+	// ALTER TABLE RENAME COLUMN
+	case *ast.RenameColumnStmt:
+		table := newTableKey(n.Table)
+		if checker.createdTable[table] {
+			break
+		}
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.CompatibilityRenameColumn,
+			Title:   checker.title,
+			Content: fmt.Sprintf("Renaming column %q in table %q.%q to %q may break the deployed application code", n.ColumnName, table.schema, table.table, n.NewName),
+			Line:    node.Line(),
+		})
+	}
+
+	return checker
+}
@@ -0,0 +1,55 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestDisallowRename(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "ALTER TABLE book RENAME TO tech_book",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityRenameTable,
+					Title:   "schema.disallow-rename",
+					Content: "Renaming table \"public\".\"book\" to \"tech_book\" may break the deployed application code",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE book RENAME COLUMN name TO title",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityRenameColumn,
+					Title:   "schema.disallow-rename",
+					Content: "Renaming column \"name\" in table \"public\".\"book\" to \"title\" may break the deployed application code",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: `CREATE TABLE author(id INT, name TEXT);
+				ALTER TABLE author RENAME COLUMN name TO full_name;
+				ALTER TABLE author RENAME TO tech_author`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &DisallowRenameAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleSchemaDisallowRename,
+		Level:   advisor.SchemaRuleLevelError,
+		Payload: "",
+	}, advisor.MockPostgreSQLDatabase)
+}
@@ -0,0 +1,138 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*DisallowDropTableAdvisor)(nil)
+	_ advisor.Advisor = (*DisallowDropDatabaseAdvisor)(nil)
+	_ advisor.Advisor = (*DisallowDropColumnAdvisor)(nil)
+	_ ast.Visitor     = (*disallowDropChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLDisallowDropTable, &DisallowDropTableAdvisor{})
+	advisor.Register(db.Postgres, advisor.PostgreSQLDisallowDropDatabase, &DisallowDropDatabaseAdvisor{})
+	advisor.Register(db.Postgres, advisor.PostgreSQLDisallowDropColumn, &DisallowDropColumnAdvisor{})
+}
+
+// DisallowDropTableAdvisor is the advisor checking for dropping tables.
+type DisallowDropTableAdvisor struct {
+}
+
+// Check checks for dropping tables.
+func (*DisallowDropTableAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	return checkDisallowDrop(ctx, statement, advisor.CompatibilityDropTable)
+}
+
+// DisallowDropDatabaseAdvisor is the advisor checking for dropping databases.
+type DisallowDropDatabaseAdvisor struct {
+}
+
+// Check checks for dropping databases.
+func (*DisallowDropDatabaseAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	return checkDisallowDrop(ctx, statement, advisor.CompatibilityDropDatabase)
+}
+
+// DisallowDropColumnAdvisor is the advisor checking for dropping columns.
+type DisallowDropColumnAdvisor struct {
+}
+
+// Check checks for dropping columns.
+func (*DisallowDropColumnAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	return checkDisallowDrop(ctx, statement, advisor.CompatibilityDropColumn)
+}
+
+// checkDisallowDrop checks for dropping the objects of the kind identified by the advice code.
+func checkDisallowDrop(ctx advisor.Context, statement string, code advisor.Code) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &disallowDropChecker{
+		level:        level,
+		title:        string(ctx.Rule.Type),
+		code:         code,
+		createdTable: make(map[tableKey]bool),
+	}
+	for _, stmt := range stmts {
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type disallowDropChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	code       advisor.Code
+	// createdTable is the tables created in the same statements, which have no data to lose yet.
+	createdTable map[tableKey]bool
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *disallowDropChecker) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		checker.createdTable[newTableKey(n.Name)] = true
+	// DROP TABLE
+	case *ast.DropTableStmt:
+		if checker.code != advisor.CompatibilityDropTable {
+			break
+		}
+		for _, tableDef := range n.TableList {
+			table := newTableKey(tableDef)
+			// Dropping the views doesn't lose data.
+			if tableDef.Type == ast.TableTypeView || checker.createdTable[table] {
+				continue
+			}
+			checker.addAdvice(fmt.Sprintf("Dropping table %q.%q is disallowed", table.schema, table.table), node.Line())
+		}
+	// DROP DATABASE
+	case *ast.DropDatabaseStmt:
+		if checker.code != advisor.CompatibilityDropDatabase {
+			break
+		}
+		checker.addAdvice(fmt.Sprintf("Dropping database %q is disallowed", n.DatabaseName), node.Line())
+	// ALTER TABLE DROP COLUMN
+	case *ast.DropColumnStmt:
+		table := newTableKey(n.Table)
+		if checker.code != advisor.CompatibilityDropColumn || checker.createdTable[table] {
+			break
+		}
+		checker.addAdvice(fmt.Sprintf("Dropping column %q in table %q.%q is disallowed", n.ColumnName, table.schema, table.table), node.Line())
+	}
+
+	return checker
+}
+
+func (checker *disallowDropChecker) addAdvice(content string, line int) {
+	checker.adviceList = append(checker.adviceList, advisor.Advice{
+		Status:  checker.level,
+		Code:    checker.code,
+		Title:   checker.title,
+		Content: content,
+		Line:    line,
+	})
+}
@@ -0,0 +1,129 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestDisallowDropTable(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "DROP TABLE book, xschema.author",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityDropTable,
+					Title:   "statement.disallow-drop.table",
+					Content: "Dropping table \"public\".\"book\" is disallowed",
+					Line:    1,
+				},
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityDropTable,
+					Title:   "statement.disallow-drop.table",
+					Content: "Dropping table \"xschema\".\"author\" is disallowed",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: `CREATE TABLE author(id INT);
+				DROP TABLE author;
+				DROP VIEW book_view;
+				ALTER TABLE book DROP COLUMN name;
+				DROP DATABASE test`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &DisallowDropTableAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementDisallowDropTable,
+		Level:   advisor.SchemaRuleLevelError,
+		Payload: "",
+	}, advisor.MockPostgreSQLDatabase)
+}
+
+func TestDisallowDropDatabase(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "DROP DATABASE IF EXISTS test",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.CompatibilityDropDatabase,
+					Title:   "statement.disallow-drop.database",
+					Content: "Dropping database \"test\" is disallowed",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "DROP TABLE book",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &DisallowDropDatabaseAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementDisallowDropDatabase,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: "",
+	}, advisor.MockPostgreSQLDatabase)
+}
+
+func TestDisallowDropColumn(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "ALTER TABLE book DROP COLUMN name, DROP COLUMN author",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityDropColumn,
+					Title:   "statement.disallow-drop.column",
+					Content: "Dropping column \"name\" in table \"public\".\"book\" is disallowed",
+					Line:    1,
+				},
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CompatibilityDropColumn,
+					Title:   "statement.disallow-drop.column",
+					Content: "Dropping column \"author\" in table \"public\".\"book\" is disallowed",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: `CREATE TABLE author(id INT, name TEXT);
+				ALTER TABLE author DROP COLUMN name;
+				DROP TABLE book`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &DisallowDropColumnAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementDisallowDropColumn,
+		Level:   advisor.SchemaRuleLevelError,
+		Payload: "",
+	}, advisor.MockPostgreSQLDatabase)
+}
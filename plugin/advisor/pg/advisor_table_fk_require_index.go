@@ -0,0 +1,168 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*TableFKRequireIndexAdvisor)(nil)
+	_ ast.Visitor     = (*tableFKRequireIndexChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLTableFKRequireIndex, &TableFKRequireIndexAdvisor{})
+}
+
+// TableFKRequireIndexAdvisor is the advisor checking for the supporting index of foreign keys.
+type TableFKRequireIndexAdvisor struct {
+}
+
+// Check checks for the supporting index of foreign keys.
+func (*TableFKRequireIndexAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &tableFKRequireIndexChecker{
+		level:    level,
+		title:    string(ctx.Rule.Type),
+		database: ctx.Database,
+		indexMap: make(map[tableKey][][]string),
+	}
+
+	for _, stmt := range stmts {
+		ast.Walk(checker, stmt)
+	}
+	// The index may be created after the foreign key in the same statements, so we check the foreign keys at last.
+	for _, fk := range checker.fkList {
+		if checker.hasSupportingIndex(fk.table, fk.columnList) {
+			continue
+		}
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.TableFKNoIndex,
+			Title:   checker.title,
+			Content: fmt.Sprintf("Foreign key columns (%s) in the table %q.%q have no supporting index", strings.Join(fk.columnList, ", "), fk.table.schema, fk.table.table),
+			Line:    fk.line,
+		})
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type foreignKeyDef struct {
+	table      tableKey
+	columnList []string
+	line       int
+}
+
+type tableFKRequireIndexChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	database   *catalog.Database
+	fkList     []foreignKeyDef
+	// indexMap is the column lists of the indexes defined in the statements for each table.
+	indexMap map[tableKey][][]string
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *tableFKRequireIndexChecker) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		table := newTableKey(n.Name)
+		for _, column := range n.ColumnList {
+			for _, constraint := range column.ConstraintList {
+				checker.addConstraint(table, constraint, node.Line())
+			}
+		}
+		for _, constraint := range n.ConstraintList {
+			checker.addConstraint(table, constraint, node.Line())
+		}
+	// ADD CONSTRAINT
+	case *ast.AddConstraintStmt:
+		checker.addConstraint(newTableKey(n.Table), n.Constraint, node.Line())
+	// CREATE INDEX
+	case *ast.CreateIndexStmt:
+		var columnList []string
+		for _, key := range n.Index.KeyList {
+			// The expression index cannot support the foreign key lookup.
+			if key.Type != ast.IndexKeyTypeColumn {
+				columnList = append(columnList, "")
+				continue
+			}
+			columnList = append(columnList, key.Key)
+		}
+		table := newTableKey(n.Index.Table)
+		checker.indexMap[table] = append(checker.indexMap[table], columnList)
+	}
+
+	return checker
+}
+
+func (checker *tableFKRequireIndexChecker) addConstraint(table tableKey, constraint *ast.ConstraintDef, line int) {
+	switch constraint.Type {
+	case ast.ConstraintTypeForeign:
+		checker.fkList = append(checker.fkList, foreignKeyDef{
+			table:      table,
+			columnList: constraint.KeyList,
+			line:       line,
+		})
+	case ast.ConstraintTypePrimary, ast.ConstraintTypeUnique:
+		checker.indexMap[table] = append(checker.indexMap[table], constraint.KeyList)
+	}
+}
+
+// hasSupportingIndex returns true if there's an index whose leading columns are the foreign key columns.
+func (checker *tableFKRequireIndexChecker) hasSupportingIndex(table tableKey, fkColumnList []string) bool {
+	indexList := checker.indexMap[table]
+	if t := checker.database.FindTable(&catalog.TableFind{SchemaName: table.schema, TableName: table.table}); t != nil {
+		for _, index := range t.IndexList {
+			indexList = append(indexList, index.ExpressionList)
+		}
+	}
+	for _, indexColumnList := range indexList {
+		if isLeadingColumnList(indexColumnList, fkColumnList) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLeadingColumnList returns true if the leading columns of the index are the column list in any order.
+func isLeadingColumnList(indexColumnList []string, columnList []string) bool {
+	if len(columnList) == 0 || len(indexColumnList) < len(columnList) {
+		return false
+	}
+	columnSet := make(map[string]bool)
+	for _, column := range columnList {
+		columnSet[column] = true
+	}
+	for _, column := range indexColumnList[:len(columnList)] {
+		if !columnSet[column] {
+			return false
+		}
+	}
+	return true
+}
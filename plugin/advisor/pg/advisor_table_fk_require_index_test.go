@@ -0,0 +1,65 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestTableFKRequireIndex(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE book(id INT PRIMARY KEY, author_id INT REFERENCES author (id), publisher_id INT, UNIQUE (publisher_id, id), CONSTRAINT fk_book_publisher_id FOREIGN KEY (publisher_id) REFERENCES publisher (id))",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableFKNoIndex,
+					Title:   "table.fk-require-index",
+					Content: "Foreign key columns (author_id) in the table \"public\".\"book\" have no supporting index",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: `CREATE TABLE book(id INT PRIMARY KEY, author_id INT, CONSTRAINT fk_book_author_id FOREIGN KEY (author_id) REFERENCES author (id));
+				CREATE INDEX idx_book_author_id ON book (author_id)`,
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE tech_book ADD CONSTRAINT fk_tech_book_name FOREIGN KEY (name) REFERENCES author (name)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableFKNoIndex,
+					Title:   "table.fk-require-index",
+					Content: "Foreign key columns (name) in the table \"public\".\"tech_book\" have no supporting index",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE tech_book ADD CONSTRAINT fk_tech_book_name_id FOREIGN KEY (name, id) REFERENCES book (name, id)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &TableFKRequireIndexAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleTableFKRequireIndex,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: "",
+	}, advisor.MockPostgreSQLDatabase)
+}
@@ -3,6 +3,8 @@ package pg
 
 import (
 	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/parser/ast"
 )
 
 const (
@@ -26,6 +28,19 @@ func (c columnName) normalizeTableName() string {
 
 type columnMap map[columnName]int
 
+// tableKey is the table identifier with the normalized schema name.
+type tableKey struct {
+	schema string
+	table  string
+}
+
+func newTableKey(table *ast.TableDef) tableKey {
+	return tableKey{
+		schema: normalizeSchemaName(table.Schema),
+		table:  table.Name,
+	}
+}
+
 func normalizeSchemaName(name string) string {
 	if name != "" {
 		return name
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"log"
 	"regexp"
+	"strings"
 
 	"github.com/bytebase/bytebase/plugin/advisor/catalog"
 	"github.com/bytebase/bytebase/plugin/advisor/db"
@@ -53,6 +54,14 @@ const (
 	SchemaRuleStatementRequireWhere SQLReviewRuleType = "statement.where.require"
 	// SchemaRuleStatementNoLeadingWildcardLike disallow leading '%' in LIKE, e.g. LIKE foo = '%x' is not allowed.
 	SchemaRuleStatementNoLeadingWildcardLike SQLReviewRuleType = "statement.where.no-leading-wildcard-like"
+	// SchemaRuleStatementAffectedRowLimit limit the estimated rows locked by a single UPDATE or DELETE statement.
+	SchemaRuleStatementAffectedRowLimit SQLReviewRuleType = "statement.affected-row-limit"
+	// SchemaRuleStatementDisallowDropTable disallow 'DROP TABLE', which deletes the data permanently.
+	SchemaRuleStatementDisallowDropTable SQLReviewRuleType = "statement.disallow-drop.table"
+	// SchemaRuleStatementDisallowDropDatabase disallow 'DROP DATABASE', which deletes the data permanently.
+	SchemaRuleStatementDisallowDropDatabase SQLReviewRuleType = "statement.disallow-drop.database"
+	// SchemaRuleStatementDisallowDropColumn disallow 'DROP COLUMN', which deletes the data permanently.
+	SchemaRuleStatementDisallowDropColumn SQLReviewRuleType = "statement.disallow-drop.column"
 
 	// SchemaRuleTableRequirePK require the table to have a primary key.
 	SchemaRuleTableRequirePK SQLReviewRuleType = "table.require-pk"
@@ -60,14 +69,25 @@ const (
 	SchemaRuleTableNoFK SQLReviewRuleType = "table.no-foreign-key"
 	// SchemaRuleTableDropNamingConvention require only the table following the naming convention can be deleted.
 	SchemaRuleTableDropNamingConvention SQLReviewRuleType = "table.drop-naming-convention"
+	// SchemaRuleTableFKRequireIndex require the foreign key columns to have a supporting index on the referencing table.
+	SchemaRuleTableFKRequireIndex SQLReviewRuleType = "table.fk-require-index"
 
 	// SchemaRuleRequiredColumn enforce the required columns in each table.
 	SchemaRuleRequiredColumn SQLReviewRuleType = "column.required"
 	// SchemaRuleColumnNotNull enforce the columns cannot have NULL value.
 	SchemaRuleColumnNotNull SQLReviewRuleType = "column.no-null"
+	// SchemaRuleColumnSetDefaultForNotNull require the NOT NULL columns in the new tables and the new columns to have a default value.
+	// For the columns added to the existing MySQL tables, the severity is decided by the synced row count of the table.
+	SchemaRuleColumnSetDefaultForNotNull SQLReviewRuleType = "column.set-default-for-not-null"
+	// SchemaRuleColumnDisallowLargeType disallow the BLOB/TEXT columns and the VARCHAR columns longer than the limit.
+	SchemaRuleColumnDisallowLargeType SQLReviewRuleType = "column.disallow-large-type"
+	// SchemaRuleColumnTimestampConvention enforce the created and updated timestamp columns with the expected DEFAULT and ON UPDATE clauses.
+	SchemaRuleColumnTimestampConvention SQLReviewRuleType = "column.timestamp-convention"
 
 	// SchemaRuleSchemaBackwardCompatibility enforce the MySQL and TiDB support check whether the schema change is backward compatible.
 	SchemaRuleSchemaBackwardCompatibility SQLReviewRuleType = "schema.backward-compatibility"
+	// SchemaRuleSchemaDisallowRename disallow renaming tables and columns, which breaks the deployed application code during rollout.
+	SchemaRuleSchemaDisallowRename SQLReviewRuleType = "schema.disallow-rename"
 
 	// SchemaRuleDropEmptyDatabase enforce the MySQL and TiDB support check if the database is empty before users drop it.
 	SchemaRuleDropEmptyDatabase SQLReviewRuleType = "database.drop-empty-database"
@@ -152,7 +172,7 @@ func (rule *SQLReviewRule) Validate() error {
 		if _, _, err := UnamrshalNamingRulePayloadAsRegexp(rule.Payload); err != nil {
 			return err
 		}
-	case SchemaRuleFKNaming, SchemaRuleIDXNaming, SchemaRuleUKNaming:
+	case SchemaRulePKNaming, SchemaRuleFKNaming, SchemaRuleIDXNaming, SchemaRuleUKNaming:
 		if _, _, _, err := UnmarshalNamingRulePayloadAsTemplate(rule.Type, rule.Payload); err != nil {
 			return err
 		}
@@ -160,10 +180,82 @@ func (rule *SQLReviewRule) Validate() error {
 		if _, err := UnmarshalRequiredColumnRulePayload(rule.Payload); err != nil {
 			return err
 		}
+	case SchemaRuleColumnDisallowLargeType:
+		if _, err := UnmarshalLargeTypeRulePayload(rule.Payload); err != nil {
+			return err
+		}
+	case SchemaRuleColumnTimestampConvention:
+		if _, err := UnmarshalTimestampConventionRulePayload(rule.Payload); err != nil {
+			return err
+		}
+	case SchemaRuleStatementAffectedRowLimit:
+		if _, err := UnmarshalAffectedRowLimitRulePayload(rule.Payload); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// SQLReviewRuleOverride is the project level override for a SQL review rule.
+// The override can only disable the rule or lower its level, but never make the rule stricter.
+type SQLReviewRuleOverride struct {
+	Type  SQLReviewRuleType  `json:"type"`
+	Level SQLReviewRuleLevel `json:"level"`
+}
+
+// ValidateSQLReviewRuleOverrideList validates the SQL review rule override list.
+func ValidateSQLReviewRuleOverrideList(overrideList []*SQLReviewRuleOverride) error {
+	typeSet := make(map[SQLReviewRuleType]bool)
+	for _, override := range overrideList {
+		if override.Type == "" {
+			return errors.Errorf("invalid override, rule type cannot be empty")
+		}
+		if typeSet[override.Type] {
+			return errors.Errorf("invalid override, duplicate rule type %q", override.Type)
+		}
+		typeSet[override.Type] = true
+		// The ERROR level is the highest level, so overriding with it cannot lower anything.
+		if override.Level != SchemaRuleLevelWarning && override.Level != SchemaRuleLevelDisabled {
+			return errors.Errorf("invalid override level %q for rule %q, expect %q or %q", override.Level, override.Type, SchemaRuleLevelWarning, SchemaRuleLevelDisabled)
+		}
+	}
+	return nil
+}
+
+// MergeSQLReviewRuleOverride returns the effective rule list after applying the overrides on the rule list.
+// The override is ignored if it would raise the rule level, e.g. the rule is disabled in the environment policy.
+func MergeSQLReviewRuleOverride(ruleList []*SQLReviewRule, overrideList []*SQLReviewRuleOverride) []*SQLReviewRule {
+	overrideMap := make(map[SQLReviewRuleType]SQLReviewRuleLevel)
+	for _, override := range overrideList {
+		overrideMap[override.Type] = override.Level
+	}
+
+	var res []*SQLReviewRule
+	for _, rule := range ruleList {
+		level, ok := overrideMap[rule.Type]
+		if !ok || ruleLevelOrder(level) >= ruleLevelOrder(rule.Level) {
+			res = append(res, rule)
+			continue
+		}
+		res = append(res, &SQLReviewRule{
+			Type:    rule.Type,
+			Level:   level,
+			Payload: rule.Payload,
+		})
+	}
+	return res
+}
+
+func ruleLevelOrder(level SQLReviewRuleLevel) int {
+	switch level {
+	case SchemaRuleLevelError:
+		return 2
+	case SchemaRuleLevelWarning:
+		return 1
+	}
+	return 0
+}
+
 // NamingRulePayload is the payload for naming rule.
 type NamingRulePayload struct {
 	MaxLength int    `json:"maxLength"`
@@ -175,6 +267,23 @@ type RequiredColumnRulePayload struct {
 	ColumnList []string `json:"columnList"`
 }
 
+// LargeTypeRulePayload is the payload for the large column type rule.
+type LargeTypeRulePayload struct {
+	MaxVarcharLength int `json:"maxVarcharLength"`
+}
+
+// TimestampConventionRulePayload is the payload for the timestamp column convention rule.
+// The empty column name means the column is not required.
+type TimestampConventionRulePayload struct {
+	CreatedColumn string `json:"createdColumn"`
+	UpdatedColumn string `json:"updatedColumn"`
+}
+
+// AffectedRowLimitRulePayload is the payload for the affected row limit rule.
+type AffectedRowLimitRulePayload struct {
+	MaxRows int `json:"maxRows"`
+}
+
 // UnamrshalNamingRulePayloadAsRegexp will unmarshal payload to NamingRulePayload and compile it as regular expression.
 func UnamrshalNamingRulePayloadAsRegexp(payload string) (*regexp.Regexp, int, error) {
 	var nr NamingRulePayload
@@ -213,6 +322,15 @@ func UnmarshalNamingRulePayloadAsTemplate(ruleType SQLReviewRuleType, payload st
 			return "", nil, 0, errors.Errorf("invalid template %s for rule %s", key, ruleType)
 		}
 	}
+	// The template is matched as a regular expression after the tokens are replaced with the names,
+	// so it's compiled here to reject the invalid one on saving the policy rather than on reviewing.
+	pattern := template
+	for _, key := range keys {
+		pattern = strings.ReplaceAll(pattern, key, "name")
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", nil, 0, errors.Wrapf(err, "failed to compile regular expression \"%s\"", template)
+	}
 
 	// We need to be compatible with existed naming rules in the database. 0 means using the default length limit.
 	maxLength := nr.MaxLength
@@ -256,12 +374,52 @@ func UnmarshalRequiredColumnRulePayload(payload string) (*RequiredColumnRulePayl
 	return &rcr, nil
 }
 
+// UnmarshalLargeTypeRulePayload will unmarshal payload to LargeTypeRulePayload.
+func UnmarshalLargeTypeRulePayload(payload string) (*LargeTypeRulePayload, error) {
+	var ltr LargeTypeRulePayload
+	if err := json.Unmarshal([]byte(payload), &ltr); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal large type rule payload %q", payload)
+	}
+	if ltr.MaxVarcharLength <= 0 {
+		return nil, errors.Errorf("invalid large type rule payload, max VARCHAR length must be positive")
+	}
+	return &ltr, nil
+}
+
+// UnmarshalTimestampConventionRulePayload will unmarshal payload to TimestampConventionRulePayload.
+func UnmarshalTimestampConventionRulePayload(payload string) (*TimestampConventionRulePayload, error) {
+	var tcr TimestampConventionRulePayload
+	if err := json.Unmarshal([]byte(payload), &tcr); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal timestamp convention rule payload %q", payload)
+	}
+	if tcr.CreatedColumn == "" && tcr.UpdatedColumn == "" {
+		return nil, errors.Errorf("invalid timestamp convention rule payload, created column and updated column cannot both be empty")
+	}
+	if tcr.CreatedColumn == tcr.UpdatedColumn {
+		return nil, errors.Errorf("invalid timestamp convention rule payload, created column and updated column cannot be the same")
+	}
+	return &tcr, nil
+}
+
+// UnmarshalAffectedRowLimitRulePayload will unmarshal payload to AffectedRowLimitRulePayload.
+func UnmarshalAffectedRowLimitRulePayload(payload string) (*AffectedRowLimitRulePayload, error) {
+	var arl AffectedRowLimitRulePayload
+	if err := json.Unmarshal([]byte(payload), &arl); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal affected row limit rule payload %q", payload)
+	}
+	if arl.MaxRows <= 0 {
+		return nil, errors.Errorf("invalid affected row limit rule payload, max rows must be positive")
+	}
+	return &arl, nil
+}
+
 // SQLReviewCheckContext is the context for SQL review check.
 type SQLReviewCheckContext struct {
-	Charset   string
-	Collation string
-	DbType    db.Type
-	Catalog   catalog.Catalog
+	Charset       string
+	Collation     string
+	DbType        db.Type
+	EngineVersion string
+	Catalog       catalog.Catalog
 }
 
 // SQLReviewCheck checks the statements with sql review rules.
@@ -286,10 +444,12 @@ func SQLReviewCheck(statements string, ruleList []*SQLReviewRule, checkContext S
 			checkContext.DbType,
 			advisorType,
 			Context{
-				Charset:   checkContext.Charset,
-				Collation: checkContext.Collation,
-				Rule:      rule,
-				Database:  database,
+				Charset:       checkContext.Charset,
+				Collation:     checkContext.Collation,
+				DbType:        checkContext.DbType,
+				EngineVersion: checkContext.EngineVersion,
+				Rule:          rule,
+				Database:      database,
 			},
 			statements,
 		)
@@ -331,6 +491,32 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		case db.Postgres:
 			return PostgreSQLNoLeadingWildcardLike, nil
 		}
+	case SchemaRuleStatementAffectedRowLimit:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLAffectedRowLimit, nil
+		}
+	case SchemaRuleStatementDisallowDropTable:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLDisallowDropTable, nil
+		case db.Postgres:
+			return PostgreSQLDisallowDropTable, nil
+		}
+	case SchemaRuleStatementDisallowDropDatabase:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLDisallowDropDatabase, nil
+		case db.Postgres:
+			return PostgreSQLDisallowDropDatabase, nil
+		}
+	case SchemaRuleStatementDisallowDropColumn:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLDisallowDropColumn, nil
+		case db.Postgres:
+			return PostgreSQLDisallowDropColumn, nil
+		}
 	case SchemaRuleStatementNoSelectAll:
 		switch engine {
 		case db.MySQL, db.TiDB:
@@ -345,6 +531,13 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		case db.Postgres:
 			return PostgreSQLMigrationCompatibility, nil
 		}
+	case SchemaRuleSchemaDisallowRename:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLDisallowRename, nil
+		case db.Postgres:
+			return PostgreSQLDisallowRename, nil
+		}
 	case SchemaRuleTableNaming:
 		switch engine {
 		case db.MySQL, db.TiDB:
@@ -398,6 +591,23 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		case db.Postgres:
 			return PostgreSQLColumnNoNull, nil
 		}
+	case SchemaRuleColumnSetDefaultForNotNull:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLColumnSetDefaultForNotNull, nil
+		case db.Postgres:
+			return PostgreSQLColumnSetDefaultForNotNull, nil
+		}
+	case SchemaRuleColumnDisallowLargeType:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLColumnDisallowLargeType, nil
+		}
+	case SchemaRuleColumnTimestampConvention:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLColumnTimestampConvention, nil
+		}
 	case SchemaRuleTableRequirePK:
 		switch engine {
 		case db.MySQL, db.TiDB:
@@ -412,6 +622,13 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		case db.Postgres:
 			return PostgreSQLTableNoFK, nil
 		}
+	case SchemaRuleTableFKRequireIndex:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLTableFKRequireIndex, nil
+		case db.Postgres:
+			return PostgreSQLTableFKRequireIndex, nil
+		}
 	case SchemaRuleTableDropNamingConvention:
 		switch engine {
 		case db.MySQL, db.TiDB:
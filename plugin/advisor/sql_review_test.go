@@ -0,0 +1,116 @@
+package advisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSQLReviewRuleOverride(t *testing.T) {
+	ruleList := []*SQLReviewRule{
+		{Type: SchemaRuleStatementNoSelectAll, Level: SchemaRuleLevelError, Payload: "{}"},
+		{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError, Payload: "{}"},
+		{Type: SchemaRuleRequiredColumn, Level: SchemaRuleLevelWarning, Payload: `{"columnList":["id"]}`},
+		{Type: SchemaRuleColumnNotNull, Level: SchemaRuleLevelDisabled, Payload: "{}"},
+	}
+	overrideList := []*SQLReviewRuleOverride{
+		{Type: SchemaRuleStatementNoSelectAll, Level: SchemaRuleLevelDisabled},
+		{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelWarning},
+		{Type: SchemaRuleRequiredColumn, Level: SchemaRuleLevelWarning},
+		// The override cannot enable the rule disabled by the environment policy.
+		{Type: SchemaRuleColumnNotNull, Level: SchemaRuleLevelWarning},
+		// The override for the rule not in the environment policy is ignored.
+		{Type: SchemaRuleTableNoFK, Level: SchemaRuleLevelWarning},
+	}
+	require.NoError(t, ValidateSQLReviewRuleOverrideList(overrideList))
+
+	want := []*SQLReviewRule{
+		{Type: SchemaRuleStatementNoSelectAll, Level: SchemaRuleLevelDisabled, Payload: "{}"},
+		{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelWarning, Payload: "{}"},
+		{Type: SchemaRuleRequiredColumn, Level: SchemaRuleLevelWarning, Payload: `{"columnList":["id"]}`},
+		{Type: SchemaRuleColumnNotNull, Level: SchemaRuleLevelDisabled, Payload: "{}"},
+	}
+	assert.Equal(t, want, MergeSQLReviewRuleOverride(ruleList, overrideList))
+	// The original rule list is untouched.
+	assert.Equal(t, SchemaRuleLevelError, ruleList[0].Level)
+}
+
+func TestValidateSQLReviewRuleOverrideList(t *testing.T) {
+	tests := []struct {
+		overrideList []*SQLReviewRuleOverride
+		wantErr      bool
+	}{
+		{
+			overrideList: nil,
+			wantErr:      false,
+		},
+		{
+			overrideList: []*SQLReviewRuleOverride{
+				{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError},
+			},
+			wantErr: true,
+		},
+		{
+			overrideList: []*SQLReviewRuleOverride{
+				{Type: "", Level: SchemaRuleLevelDisabled},
+			},
+			wantErr: true,
+		},
+		{
+			overrideList: []*SQLReviewRuleOverride{
+				{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelDisabled},
+				{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelWarning},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateSQLReviewRuleOverrideList(test.overrideList)
+		if test.wantErr {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestSQLReviewRuleValidateNaming(t *testing.T) {
+	tests := []struct {
+		rule    *SQLReviewRule
+		wantErr bool
+	}{
+		{
+			rule: &SQLReviewRule{Type: SchemaRuleTableNaming, Payload: `{"format":"^[a-z]+(_[a-z]+)*$","maxLength":64}`},
+		},
+		{
+			rule:    &SQLReviewRule{Type: SchemaRuleColumnNaming, Payload: `{"format":"^[a-z]+(_[a-z]+*$"}`},
+			wantErr: true,
+		},
+		{
+			rule: &SQLReviewRule{Type: SchemaRuleIDXNaming, Payload: `{"format":"^idx_{{table}}_{{column_list}}$"}`},
+		},
+		{
+			rule:    &SQLReviewRule{Type: SchemaRuleIDXNaming, Payload: `{"format":"^idx_{{table}}_({{column_list}}$"}`},
+			wantErr: true,
+		},
+		{
+			rule:    &SQLReviewRule{Type: SchemaRuleUKNaming, Payload: `{"format":"^uk_{{referenced_table}}$"}`},
+			wantErr: true,
+		},
+		{
+			rule:    &SQLReviewRule{Type: SchemaRulePKNaming, Payload: `{"format":"^pk_{{table}}[$"}`},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := test.rule.Validate()
+		if test.wantErr {
+			assert.Error(t, err, test.rule.Payload)
+		} else {
+			assert.NoError(t, err, test.rule.Payload)
+		}
+	}
+}
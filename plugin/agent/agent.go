@@ -0,0 +1,339 @@
+// Package agent provides the agent relaying the database connections of Bytebase inside the private networks.
+//
+// The agent keeps a control connection out to Bytebase. When Bytebase opens a connection to the instance,
+// it asks the agent to dial the database address, and the agent opens a tunnel connection out to Bytebase
+// and relays the bytes between the tunnel and the database. So Bytebase never needs the inbound access to the network.
+// Both connections are HTTP connections upgraded to the agent protocol, which pass through the usual HTTP load balancers.
+package agent
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// UpgradeProtocol is the protocol in the Upgrade header of the agent connections.
+	UpgradeProtocol = "bytebase-agent"
+	// ConnectPath is the path of the control connection.
+	ConnectPath = "/agent/connect"
+	// TunnelPath is the path prefix of the tunnel connections, followed by the connection ID.
+	TunnelPath = "/agent/tunnel/"
+	// TunnelErrorParam is the query parameter of the tunnel connection reporting the failure to dial the database.
+	TunnelErrorParam = "error"
+
+	// CommandDial is the command asking the agent to dial an address, in the form of "DIAL <connection ID> <address>".
+	CommandDial = "DIAL"
+	// CommandPing is the command checking the agent is alive, answered with CommandPong.
+	CommandPing = "PING"
+	// CommandPong is the answer of CommandPing.
+	CommandPong = "PONG"
+	// CommandDisk is the command asking the agent for the disk usage of its disk path, answered with
+	// "DISK <total bytes> <free bytes>", or "DISK ERROR <message>" if the agent can't get it.
+	CommandDisk = "DISK"
+
+	// PingInterval is the interval Bytebase pings the agent, the control connection is closed if no answer in two intervals.
+	PingInterval = 30 * time.Second
+
+	dialTimeout       = 10 * time.Second
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+// Client is the agent client running inside the private network.
+type Client struct {
+	serverURL *url.URL
+	token     string
+	// diskPath is the path on the disk of the database data reported to Bytebase, empty if the agent doesn't run on the database host.
+	diskPath string
+	dialer   net.Dialer
+}
+
+// NewClient creates an agent client connecting to the Bytebase at serverURL with the agent token.
+// diskPath is the path on the disk of the database data, e.g. /var/lib/mysql, empty to not report the disk usage.
+func NewClient(serverURL string, token string, diskPath string) (*Client, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid server URL %q", serverURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("invalid server URL %q, the scheme must be http or https", serverURL)
+	}
+	if token == "" {
+		return nil, errors.New("agent token is required")
+	}
+	return &Client{
+		serverURL: u,
+		token:     token,
+		diskPath:  diskPath,
+		dialer:    net.Dialer{Timeout: dialTimeout},
+	}, nil
+}
+
+// Run keeps the control connection to Bytebase and serves the dial commands until the context is canceled.
+// It reconnects with backoff if the control connection is lost.
+func (c *Client) Run(ctx context.Context) {
+	delay := minReconnectDelay
+	for {
+		start := time.Now()
+		err := c.serve(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		// Reset the backoff if the connection has been up for a while.
+		if time.Since(start) > maxReconnectDelay {
+			delay = minReconnectDelay
+		}
+		log.Warn("Agent disconnected from Bytebase, reconnecting",
+			zap.String("server", c.serverURL.String()),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// serve serves a single control connection until it's closed.
+func (c *Client) serve(ctx context.Context) error {
+	conn, err := c.upgrade(ctx, ConnectPath, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	log.Info("Agent connected to Bytebase", zap.String("server", c.serverURL.String()))
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case CommandPing:
+			if _, err := fmt.Fprintf(conn, "%s\n", CommandPong); err != nil {
+				return err
+			}
+		case CommandDial:
+			if len(fields) != 3 {
+				log.Warn("Agent received malformed dial command", zap.String("command", scanner.Text()))
+				continue
+			}
+			go c.relay(ctx, fields[1], fields[2])
+		case CommandDisk:
+			if _, err := fmt.Fprintf(conn, "%s\n", c.getDiskReply()); err != nil {
+				return err
+			}
+		default:
+			log.Warn("Agent received unknown command", zap.String("command", scanner.Text()))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// getDiskReply returns the answer of CommandDisk.
+func (c *Client) getDiskReply() string {
+	if c.diskPath == "" {
+		return fmt.Sprintf("%s ERROR the agent is started without the disk path", CommandDisk)
+	}
+	total, free, err := GetDiskUsage(c.diskPath)
+	if err != nil {
+		return fmt.Sprintf("%s ERROR %s", CommandDisk, strings.ReplaceAll(err.Error(), "\n", " "))
+	}
+	return fmt.Sprintf("%s %d %d", CommandDisk, total, free)
+}
+
+// GetDiskUsage returns the total and the free bytes of the file system containing the path.
+// The free bytes are the ones available to the unprivileged users, which is what the database process can use.
+func GetDiskUsage(path string) (total int64, free int64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to stat the file system of %q", path)
+	}
+	// The field types of Statfs_t differ between the platforms.
+	blockSize := int64(stat.Bsize)
+	return int64(stat.Blocks) * blockSize, int64(stat.Bavail) * blockSize, nil
+}
+
+// ParseDiskReply parses the answer of CommandDisk into the total and the free bytes.
+func ParseDiskReply(reply string) (total int64, free int64, err error) {
+	fields := strings.SplitN(strings.TrimSpace(reply), " ", 3)
+	if len(fields) == 0 || fields[0] != CommandDisk {
+		return 0, 0, errors.Errorf("invalid disk reply %q", reply)
+	}
+	if len(fields) == 3 && fields[1] == "ERROR" {
+		return 0, 0, errors.Errorf("agent failed to get the disk usage: %s", fields[2])
+	}
+	if len(fields) != 3 {
+		return 0, 0, errors.Errorf("invalid disk reply %q", reply)
+	}
+	if total, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid total bytes in disk reply %q", reply)
+	}
+	if free, err = strconv.ParseInt(fields[2], 10, 64); err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid free bytes in disk reply %q", reply)
+	}
+	if total <= 0 || free < 0 || free > total {
+		return 0, 0, errors.Errorf("invalid disk usage in disk reply %q", reply)
+	}
+	return total, free, nil
+}
+
+// relay dials the database address and relays it with a new tunnel connection to Bytebase.
+func (c *Client) relay(ctx context.Context, connID string, address string) {
+	dbConn, err := c.dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		log.Warn("Agent failed to dial database", zap.String("address", address), zap.Error(err))
+		// Report the failure so that Bytebase fails the connection right away instead of timing out.
+		query := url.Values{}
+		query.Set(TunnelErrorParam, err.Error())
+		if conn, err := c.upgrade(ctx, TunnelPath+connID, query); err == nil {
+			conn.Close()
+		}
+		return
+	}
+	tunnel, err := c.upgrade(ctx, TunnelPath+connID, nil)
+	if err != nil {
+		dbConn.Close()
+		log.Warn("Agent failed to open tunnel", zap.String("address", address), zap.Error(err))
+		return
+	}
+	Relay(dbConn, tunnel)
+}
+
+// upgrade opens a connection to Bytebase and upgrades it to the agent protocol.
+// The error tunnel connection is answered with 200 OK instead of switching protocols, and nil error is returned.
+func (c *Client) upgrade(ctx context.Context, path string, query url.Values) (net.Conn, error) {
+	host := c.serverURL.Host
+	if c.serverURL.Port() == "" {
+		if c.serverURL.Scheme == "https" {
+			host = net.JoinHostPort(c.serverURL.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(c.serverURL.Hostname(), "80")
+		}
+	}
+	conn, err := c.dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial %s", host)
+	}
+	if c.serverURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: c.serverURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, errors.Wrapf(err, "failed to handshake with %s", host)
+		}
+		conn = tlsConn
+	}
+
+	u := *c.serverURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", UpgradeProtocol)
+
+	// The handshake must not hang forever on a stuck server.
+	if err := conn.SetDeadline(time.Now().Add(dialTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "failed to send request to %s", u.Path)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "failed to read response from %s", u.Path)
+	}
+	if len(query) > 0 && resp.StatusCode == http.StatusOK {
+		return conn, nil
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		conn.Close()
+		return nil, errors.Errorf("failed to upgrade %s, status %s: %s", u.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return NewBufferedConn(conn, reader), nil
+}
+
+// Relay copies the bytes between the two connections in both directions, and closes both when either ends.
+func Relay(a, b net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		a.Close()
+		b.Close()
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(a, b)
+		once.Do(closeBoth)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(b, a)
+		once.Do(closeBoth)
+	}()
+	wg.Wait()
+}
+
+// NewBufferedConn returns the connection reading from the reader first,
+// which may have buffered the bytes following the upgrade handshake.
+func NewBufferedConn(conn net.Conn, reader *bufio.Reader) net.Conn {
+	return &bufferedConn{Conn: conn, reader: reader}
+}
+
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgrade(t *testing.T) {
+	a := require.New(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get(TunnelErrorParam) != "" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: %s\r\n\r\n%s\n", UpgradeProtocol, CommandPing)
+		if err := rw.Flush(); err != nil {
+			return
+		}
+		// Echo back the lines.
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fmt.Fprint(rw, line)
+		_ = rw.Flush()
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, "token", "")
+	a.NoError(err)
+	conn, err := client.upgrade(context.Background(), ConnectPath, nil)
+	a.NoError(err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	// The bytes sent right after the handshake must not be lost.
+	line, err := reader.ReadString('\n')
+	a.NoError(err)
+	a.Equal(CommandPing+"\n", line)
+	_, err = fmt.Fprintf(conn, "%s\n", CommandPong)
+	a.NoError(err)
+	line, err = reader.ReadString('\n')
+	a.NoError(err)
+	a.Equal(CommandPong+"\n", line)
+
+	errConn, err := client.upgrade(context.Background(), TunnelPath+"id", map[string][]string{TunnelErrorParam: {"refused"}})
+	a.NoError(err)
+	errConn.Close()
+
+	unauthorized, err := NewClient(ts.URL, "wrong", "")
+	a.NoError(err)
+	_, err = unauthorized.upgrade(context.Background(), ConnectPath, nil)
+	a.Error(err)
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		serverURL string
+		token     string
+		wantErr   bool
+	}{
+		{serverURL: "https://bytebase.example.com", token: "token", wantErr: false},
+		{serverURL: "ftp://bytebase.example.com", token: "token", wantErr: true},
+		{serverURL: "http://localhost:8080", token: "", wantErr: true},
+	}
+	for _, test := range tests {
+		_, err := NewClient(test.serverURL, test.token, "")
+		require.Equal(t, test.wantErr, err != nil, test.serverURL)
+	}
+}
+
+func TestParseDiskReply(t *testing.T) {
+	tests := []struct {
+		reply     string
+		wantTotal int64
+		wantFree  int64
+		wantErr   bool
+	}{
+		{reply: "DISK 1000 400", wantTotal: 1000, wantFree: 400},
+		{reply: "DISK 1000 400\n", wantTotal: 1000, wantFree: 400},
+		{reply: "DISK ERROR the agent is started without the disk path", wantErr: true},
+		{reply: "DISK 1000", wantErr: true},
+		{reply: "DISK 1000 2000", wantErr: true},
+		{reply: "DISK abc 400", wantErr: true},
+		{reply: "PONG", wantErr: true},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		total, free, err := ParseDiskReply(test.reply)
+		if test.wantErr {
+			a.Error(err, test.reply)
+			continue
+		}
+		a.NoError(err, test.reply)
+		a.Equal(test.wantTotal, total, test.reply)
+		a.Equal(test.wantFree, free, test.reply)
+	}
+}
+
+func TestGetDiskReply(t *testing.T) {
+	a := require.New(t)
+	client, err := NewClient("http://localhost:8080", "token", t.TempDir())
+	a.NoError(err)
+	total, free, err := ParseDiskReply(client.getDiskReply())
+	a.NoError(err)
+	a.Greater(total, int64(0))
+	a.LessOrEqual(free, total)
+
+	client, err = NewClient("http://localhost:8080", "token", "")
+	a.NoError(err)
+	_, _, err = ParseDiskReply(client.getDiskReply())
+	a.Error(err)
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+)
+
+// Capability is a feature which depends on the database engine and its version.
+type Capability string
+
+const (
+	// CapabilityTransactionalDDL means the DDL statements are rolled back with the transaction.
+	CapabilityTransactionalDDL Capability = "TRANSACTIONAL_DDL"
+	// CapabilityInstantDDL means adding a column only changes the metadata without rebuilding the table.
+	CapabilityInstantDDL Capability = "INSTANT_DDL"
+	// CapabilityInvisibleIndex means the index can be made invisible to the optimizer before dropping it.
+	CapabilityInvisibleIndex Capability = "INVISIBLE_INDEX"
+	// CapabilityMultiSchemaChange means a single ALTER TABLE can make different changes, e.g. adding a column and an index.
+	CapabilityMultiSchemaChange Capability = "MULTI_SCHEMA_CHANGE"
+)
+
+// tidbVersionSeparator separates the MySQL compatible version and the TiDB version, e.g. "5.7.25-TiDB-v6.1.0".
+const tidbVersionSeparator = "-TiDB-v"
+
+// engineVersionRegexp matches the numeric prefix of the engine version, e.g. "8.0.28" of "8.0.28-0ubuntu0.20.04.3".
+var engineVersionRegexp = regexp.MustCompile(`^\d+(\.\d+){0,2}`)
+
+// capabilityMatrix is the first version of each engine supporting the capability, the zero version means all versions.
+// The capabilities not listed are unsupported.
+var capabilityMatrix = map[Type]map[Capability]semver.Version{
+	MySQL: {
+		CapabilityInstantDDL:        semver.MustParse("8.0.12"),
+		CapabilityInvisibleIndex:    semver.MustParse("8.0.0"),
+		CapabilityMultiSchemaChange: {},
+	},
+	TiDB: {
+		CapabilityInstantDDL:        {},
+		CapabilityInvisibleIndex:    semver.MustParse("5.0.0"),
+		CapabilityMultiSchemaChange: semver.MustParse("6.2.0"),
+	},
+	Postgres: {
+		CapabilityTransactionalDDL:  {},
+		CapabilityInstantDDL:        semver.MustParse("11.0.0"),
+		CapabilityMultiSchemaChange: {},
+	},
+	SQLite: {
+		CapabilityTransactionalDDL: {},
+	},
+}
+
+// ParseEngineVersion parses the version reported by the engine, e.g. "14.5 (Debian 14.5-1.pgdg110+1)" is 14.5.0.
+// The TiDB version is the one after the MySQL compatible version, e.g. "5.7.25-TiDB-v6.1.0" is 6.1.0.
+func ParseEngineVersion(engine Type, version string) (semver.Version, error) {
+	text := version
+	if engine == TiDB {
+		i := strings.Index(text, tidbVersionSeparator)
+		if i < 0 {
+			return semver.Version{}, errors.Errorf("%q is not a TiDB version", version)
+		}
+		text = text[i+len(tidbVersionSeparator):]
+	}
+	text = engineVersionRegexp.FindString(strings.TrimSpace(text))
+	if text == "" {
+		return semver.Version{}, errors.Errorf("invalid %s version %q", engine, version)
+	}
+	return semver.ParseTolerant(text)
+}
+
+// GetCapabilityMinVersion returns the first version of the engine supporting the capability,
+// false if no version of the engine supports it.
+func GetCapabilityMinVersion(engine Type, capability Capability) (semver.Version, bool) {
+	minVersion, ok := capabilityMatrix[engine][capability]
+	return minVersion, ok
+}
+
+// HasCapability returns true if the engine of the version supports the capability.
+// An error is returned if the support depends on the version which cannot be parsed, e.g. not synced yet.
+func HasCapability(engine Type, version string, capability Capability) (bool, error) {
+	minVersion, ok := GetCapabilityMinVersion(engine, capability)
+	if !ok {
+		return false, nil
+	}
+	if minVersion.Equals(semver.Version{}) {
+		return true, nil
+	}
+	v, err := ParseEngineVersion(engine, version)
+	if err != nil {
+		return false, err
+	}
+	return v.GTE(minVersion), nil
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEngineVersion(t *testing.T) {
+	a := require.New(t)
+	tests := []struct {
+		engine  Type
+		version string
+		want    string
+		err     bool
+	}{
+		{
+			engine:  MySQL,
+			version: "8.0.28-0ubuntu0.20.04.3",
+			want:    "8.0.28",
+		},
+		{
+			engine:  MySQL,
+			version: "5.7.38-log",
+			want:    "5.7.38",
+		},
+		{
+			engine:  Postgres,
+			version: "14.5 (Debian 14.5-1.pgdg110+1)",
+			want:    "14.5.0",
+		},
+		{
+			engine:  Postgres,
+			version: "10.21",
+			want:    "10.21.0",
+		},
+		{
+			engine:  TiDB,
+			version: "5.7.25-TiDB-v6.1.0",
+			want:    "6.1.0",
+		},
+		{
+			engine:  TiDB,
+			version: "5.7.25-TiDB-v6.3.0-alpha",
+			want:    "6.3.0",
+		},
+		{
+			engine:  TiDB,
+			version: "8.0.28",
+			err:     true,
+		},
+		{
+			engine:  TiDB,
+			version: "5.7.25-TiDB-None",
+			err:     true,
+		},
+		{
+			engine:  MySQL,
+			version: "",
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		version, err := ParseEngineVersion(test.engine, test.version)
+		if test.err {
+			a.Error(err, test.version)
+			continue
+		}
+		a.NoError(err, test.version)
+		a.Equal(test.want, version.String())
+	}
+}
+
+func TestHasCapability(t *testing.T) {
+	a := require.New(t)
+	tests := []struct {
+		engine     Type
+		version    string
+		capability Capability
+		want       bool
+		err        bool
+	}{
+		{
+			engine:     MySQL,
+			version:    "8.0.11",
+			capability: CapabilityInstantDDL,
+			want:       false,
+		},
+		{
+			engine:     MySQL,
+			version:    "8.0.12",
+			capability: CapabilityInstantDDL,
+			want:       true,
+		},
+		{
+			engine:     MySQL,
+			version:    "5.7.38-log",
+			capability: CapabilityInvisibleIndex,
+			want:       false,
+		},
+		{
+			engine:     MySQL,
+			version:    "8.0.28",
+			capability: CapabilityTransactionalDDL,
+			want:       false,
+		},
+		{
+			engine:     TiDB,
+			version:    "5.7.25-TiDB-v6.1.0",
+			capability: CapabilityMultiSchemaChange,
+			want:       false,
+		},
+		{
+			engine:     TiDB,
+			version:    "5.7.25-TiDB-v6.2.0",
+			capability: CapabilityMultiSchemaChange,
+			want:       true,
+		},
+		{
+			engine:     Postgres,
+			version:    "10.21",
+			capability: CapabilityInstantDDL,
+			want:       false,
+		},
+		{
+			engine:     Postgres,
+			version:    "14.5 (Debian 14.5-1.pgdg110+1)",
+			capability: CapabilityInstantDDL,
+			want:       true,
+		},
+		{
+			// The capability of all versions doesn't need the version.
+			engine:     Postgres,
+			version:    "",
+			capability: CapabilityTransactionalDDL,
+			want:       true,
+		},
+		{
+			engine:     TiDB,
+			version:    "",
+			capability: CapabilityMultiSchemaChange,
+			err:        true,
+		},
+		{
+			engine:     Snowflake,
+			version:    "6.30.1",
+			capability: CapabilityInstantDDL,
+			want:       false,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := HasCapability(test.engine, test.version, test.capability)
+		if test.err {
+			a.Error(err)
+			continue
+		}
+		a.NoError(err)
+		a.Equal(test.want, got, "%s %s %s", test.engine, test.version, test.capability)
+	}
+}
@@ -70,6 +70,7 @@ func (driver *Driver) Open(_ context.Context, dbType db.Type, config db.Connecti
 			"max_execution_time": 300,
 		},
 		DialTimeout: 10 * time.Second,
+		DialContext: config.Dialer,
 	})
 
 	log.Debug("Opening ClickHouse driver",
@@ -140,6 +141,6 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	return util.Query(ctx, driver.db, statement, queryContext)
 }
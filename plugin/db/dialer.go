@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// DialContextFunc connects the database address instead of the network dialer of the driver, e.g. through the instance agent
+// or the SSH tunnel.
+type DialContextFunc func(ctx context.Context, address string) (net.Conn, error)
+
+// dialerSupportedSet is the set of the engines whose drivers connect the database with the dialer of the connection config.
+var dialerSupportedSet = map[Type]bool{
+	MySQL:      true,
+	TiDB:       true,
+	Postgres:   true,
+	ClickHouse: true,
+}
+
+// IsDialerSupported returns true if the engine can be connected with the dialer of the connection config.
+func IsDialerSupported(dbType Type) bool {
+	return dialerSupportedSet[dbType]
+}
+
+// CheckExternalTool returns an error if the external tool, e.g. pg_dump, can't connect the database of the config.
+// The external tools connect the database address on their own, so they don't work with the dialer.
+func (c ConnectionConfig) CheckExternalTool(tool string) error {
+	if c.Dialer != nil {
+		return errors.Errorf("%s is not supported for the database connected through the dialer", tool)
+	}
+	return nil
+}
@@ -42,9 +42,20 @@ type User struct {
 	Grant string
 }
 
+// Grant is a privilege granted to a grantee on a database.
+type Grant struct {
+	// Grantee is the role name for Postgres, and "user@host" for MySQL and TiDB.
+	Grantee string
+	// Privilege is the privilege type in upper case, e.g. "SELECT".
+	Privilege string
+}
+
 // View is the database view.
 type View struct {
 	Name string
+	// Schema is the schema the view belongs to, it's only supported for Postgres.
+	// The Name is qualified by the schema for Postgres, e.g. public.v1.
+	Schema string
 	// CreatedTs isn't supported for ClickHouse.
 	CreatedTs  int64
 	UpdatedTs  int64
@@ -52,6 +63,12 @@ type View struct {
 	Comment    string
 }
 
+// Namespace is the Postgres schema, which is a namespace of the tables and views in the database.
+type Namespace struct {
+	Name  string
+	Owner string
+}
+
 // Extension is the database extension.
 type Extension struct {
 	Name        string
@@ -95,6 +112,9 @@ type Column struct {
 // Table is the database table.
 type Table struct {
 	Name string
+	// Schema is the schema the table belongs to, it's only supported for Postgres.
+	// The Name is qualified by the schema for Postgres, e.g. public.t1.
+	Schema string
 	// CreatedTs isn't supported for ClickHouse, SQLite.
 	CreatedTs int64
 	// UpdatedTs isn't supported for SQLite.
@@ -146,6 +166,8 @@ type Schema struct {
 	TableList     []Table
 	ViewList      []View
 	ExtensionList []Extension
+	// NamespaceList is the schemas of the database, it's only supported for Postgres.
+	NamespaceList []Namespace
 }
 
 var (
@@ -240,6 +262,36 @@ type MigrationInfo struct {
 	// This applies to BASELINE and MIGRATE types of migrations because most of these migrations are retry-able.
 	// We don't use force option for DATA type of migrations yet till there's customer needs.
 	Force bool
+	// StatementLogger is called after each statement is executed for DATA type of migrations,
+	// and for the non-DATA migrations executed one by one, see ShouldPause.
+	// If it's nil, the statement is executed as a whole without per-statement reporting.
+	// The other non-DATA migrations are always executed as a whole, so that the transactional DDL stays atomic, and no statement is reported.
+	StatementLogger func(*StatementLog)
+	// MaxAffectedRows is the limit of the total affected rows for DATA type of migrations, 0 means unlimited.
+	// The migration fails and is rolled back if the statements affect more rows than the limit.
+	MaxAffectedRows int64
+	// ResolveStatement resolves the statement right before executing it, e.g. injecting the database secrets.
+	// The migration history and the statement logs keep the unresolved statement, so that the secret values are never persisted.
+	ResolveStatement func(statement string) (string, error)
+	// ShouldPause is checked at each statement boundary when the statements are executed one by one.
+	// If it returns true, the executed statements are kept, and the migration fails with the MigrationPaused error.
+	// The statements of the non-DATA migrations are executed one by one only if it's set.
+	ShouldPause func() bool
+	// ExecutedStatementCount is the number of the leading statements executed by the paused migration. They are skipped.
+	ExecutedStatementCount int
+}
+
+// StatementLog is the execution log of a single statement in a migration.
+type StatementLog struct {
+	// Index is the 1-based position of the statement.
+	Index int
+	// Count is the total number of statements in the migration.
+	Count        int
+	Statement    string
+	DurationNs   int64
+	RowsAffected int64
+	// Error is the execution error message, empty on success.
+	Error string
 }
 
 // ParseMigrationInfo matches filePath against filePathTemplate
@@ -370,10 +422,24 @@ type ConnectionConfig struct {
 	Password  string
 	Database  string
 	TLSConfig TLSConfig
+	// SSHConfig is the SSH tunnel through which the database is connected, it's set up by Open.
+	SSHConfig SSHConfig
+	// Dialer connects the database instead of the network if set, it's only supported by the engines of IsDialerSupported.
+	// The Host and Port are kept as the database address, e.g. for the TLS server name.
+	Dialer DialContextFunc `json:"-"`
 	// ReadOnly is only supported for Postgres at the moment.
 	ReadOnly bool
 	// StrictUseDb will only set as true if the user gives only a database instead of a whole instance to access.
 	StrictUseDb bool
+	// SessionVariableList is set on each connection at setup.
+	// It's only supported for MySQL, TiDB and Postgres at the moment.
+	SessionVariableList []SessionVariable
+}
+
+// SessionVariable is a session variable set on the connection setup.
+type SessionVariable struct {
+	Name  string
+	Value string
 }
 
 // ConnectionContext is the context for connection.
@@ -383,6 +449,15 @@ type ConnectionContext struct {
 	InstanceName    string
 }
 
+// QueryContext is the context to execute a readonly query.
+type QueryContext struct {
+	// Limit is the maximum row count returned. No limit enforced if Limit <= 0.
+	Limit int
+	// MaxBytes is the maximum size of the rows returned in bytes, which is counted while reading the rows
+	// so that an oversized result fails before being buffered entirely. No limit enforced if MaxBytes <= 0.
+	MaxBytes int64
+}
+
 // Driver is the interface for database driver.
 type Driver interface {
 	// General execution
@@ -397,8 +472,7 @@ type Driver interface {
 	// will not use transactions to execute the statement but will still use transactions to execute the rest of statements.
 	Execute(ctx context.Context, statement string) error
 	// Used for execute readonly SELECT statement
-	// limit is the maximum row count returned. No limit enforced if limit <= 0
-	Query(ctx context.Context, statement string, limit int) ([]interface{}, error)
+	Query(ctx context.Context, statement string, queryContext *QueryContext) ([]interface{}, error)
 
 	// Sync schema
 	// SyncInstance syncs the instance metadata.
@@ -427,6 +501,19 @@ type Driver interface {
 	Restore(ctx context.Context, src io.Reader) error
 }
 
+// GrantSyncer is the interface for the drivers supporting syncing the database level grants.
+type GrantSyncer interface {
+	// SyncDBGrant syncs the privileges granted on a single database.
+	SyncDBGrant(ctx context.Context, database string) ([]Grant, error)
+}
+
+// Resetter is implemented by the drivers that can be reused after use, e.g. kept in the connection pool of the server.
+type Resetter interface {
+	// Reset restores the driver to the state right after Open, e.g. connected to the database of the connection config,
+	// so that the next user of the driver isn't affected by the previous one.
+	Reset(ctx context.Context) error
+}
+
 // Register makes a database driver available by the provided type.
 // If Register is called twice with the same name or if driver is nil,
 // it panics.
@@ -451,6 +538,19 @@ func Open(ctx context.Context, dbType Type, driverConfig DriverConfig, connectio
 		return nil, errors.Errorf("db: unknown driver %v", dbType)
 	}
 
+	if connectionConfig.Dialer != nil {
+		if !IsDialerSupported(dbType) {
+			return nil, errors.Errorf("connecting through the dialer is not supported for %s", dbType)
+		}
+		if !connectionConfig.SSHConfig.IsEmpty() {
+			return nil, errors.New("the dialer can't be used with the SSH tunnel")
+		}
+	}
+	// The drivers connect the database through the SSH tunnel as if it's the database address.
+	connectionConfig, err := openSSHTunnel(dbType, connectionConfig)
+	if err != nil {
+		return nil, err
+	}
 	driver, err := f(driverConfig).Open(ctx, dbType, connectionConfig, connCtx)
 	if err != nil {
 		return nil, err
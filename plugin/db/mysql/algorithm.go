@@ -0,0 +1,143 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// DDLAlgorithm is the algorithm MySQL uses to run the ALTER TABLE statement.
+type DDLAlgorithm string
+
+const (
+	// DDLAlgorithmInstant only changes the metadata, the table isn't rebuilt.
+	DDLAlgorithmInstant DDLAlgorithm = "INSTANT"
+	// DDLAlgorithmInplace rebuilds the table in place and allows the concurrent writes for most changes.
+	DDLAlgorithmInplace DDLAlgorithm = "INPLACE"
+	// DDLAlgorithmCopy copies the table, and the concurrent writes are blocked until it's done.
+	DDLAlgorithmCopy DDLAlgorithm = "COPY"
+)
+
+const (
+	// erAlterOperationNotSupported is returned if the ALTER TABLE isn't supported by the requested algorithm.
+	erAlterOperationNotSupported = 1845
+	// erAlterOperationNotSupportedReason is the same as erAlterOperationNotSupported with the reason.
+	erAlterOperationNotSupportedReason = 1846
+)
+
+// ddlAlgorithmPreferenceList is the algorithms tried in order for the ALTER TABLE statement without the explicit algorithm.
+// The statement runs with the default algorithm if none of them is supported, which is COPY.
+var ddlAlgorithmPreferenceList = []DDLAlgorithm{DDLAlgorithmInstant, DDLAlgorithmInplace}
+
+var (
+	// alterTableRegexp matches the ALTER TABLE statement, the submatches are the table name and the alter commands.
+	alterTableRegexp = regexp.MustCompile("(?is)^\\s*ALTER\\s+TABLE\\s+((?:(?:`[^`]*`)+|[\\w$]+)(?:\\s*\\.\\s*(?:(?:`[^`]*`)+|[\\w$]+))?)\\s+(\\S.*)$")
+	// explicitAlgorithmRegexp matches the explicit algorithm of the ALTER TABLE statement.
+	explicitAlgorithmRegexp = regexp.MustCompile(`(?i)\bALGORITHM\s*=?\s*(DEFAULT|INSTANT|INPLACE|COPY)\b`)
+	// partitionRegexp matches the partition commands, which are left to the default algorithm.
+	partitionRegexp = regexp.MustCompile(`(?i)\bPARTITION`)
+)
+
+// DDLAlgorithmLog is the algorithm used by an ALTER TABLE statement of the migration.
+type DDLAlgorithmLog struct {
+	Table     string
+	Algorithm DDLAlgorithm
+}
+
+// PreferOnlineDDLAlgorithm makes Execute run each ALTER TABLE statement without the explicit algorithm with ALGORITHM=INSTANT,
+// then ALGORITHM=INPLACE, and then the default algorithm if the former isn't supported for the change. The unsupported algorithm
+// fails before changing anything, so the fallback is safe. The algorithm used by each statement is reported to logger.
+// It requires MySQL 8.0.12 or later, where ALGORITHM=INSTANT is supported.
+func (driver *Driver) PreferOnlineDDLAlgorithm(logger func(*DDLAlgorithmLog)) {
+	driver.ddlAlgorithmLogger = logger
+}
+
+// alterTableWithAlgorithm returns the ALTER TABLE statement running with the algorithm, and the name of the altered table.
+// It returns false if the statement isn't an ALTER TABLE statement whose algorithm can be chosen,
+// e.g. the algorithm is explicit or it changes the partitions.
+func alterTableWithAlgorithm(statement string, algorithm DDLAlgorithm) (string, string, bool) {
+	match := alterTableRegexp.FindStringSubmatchIndex(statement)
+	if match == nil {
+		return "", "", false
+	}
+	if explicitAlgorithmRegexp.MatchString(statement) || partitionRegexp.MatchString(statement) {
+		return "", "", false
+	}
+	table := statement[match[2]:match[3]]
+	commandsStart := match[4]
+	// The algorithm goes before the alter commands, so that it doesn't interfere with the trailing comments or the ORDER BY list.
+	return statement[:commandsStart] + "ALGORITHM=" + string(algorithm) + ", " + statement[commandsStart:], table, true
+}
+
+// isAlgorithmNotSupported returns true if the error is the algorithm not supported for the ALTER TABLE statement.
+func isAlgorithmNotSupported(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == erAlterOperationNotSupported || mysqlErr.Number == erAlterOperationNotSupportedReason
+}
+
+// executeWithPreferredAlgorithm executes the statements one by one in the transaction, and the ALTER TABLE statements run
+// with the preferred algorithms. It returns false without executing anything if there is no ALTER TABLE statement
+// whose algorithm can be chosen, so that the statement is executed as a whole.
+func (driver *Driver) executeWithPreferredAlgorithm(ctx context.Context, tx *sql.Tx, statement string) (bool, error) {
+	var stmtList []string
+	hasAlterTable := false
+	if err := util.ApplyMultiStatements(strings.NewReader(statement), func(stmt string) error {
+		stmtList = append(stmtList, stmt)
+		if _, _, ok := alterTableWithAlgorithm(stmt, DDLAlgorithmInstant); ok {
+			hasAlterTable = true
+		}
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	if !hasAlterTable {
+		return false, nil
+	}
+
+	for _, stmt := range stmtList {
+		if _, table, ok := alterTableWithAlgorithm(stmt, DDLAlgorithmInstant); ok {
+			algorithm, err := executeAlterTable(ctx, tx, stmt)
+			if err != nil {
+				return true, err
+			}
+			driver.ddlAlgorithmLogger(&DDLAlgorithmLog{
+				Table:     table,
+				Algorithm: algorithm,
+			})
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// executeAlterTable executes the ALTER TABLE statement with the first supported algorithm of the preference list,
+// and returns the algorithm used.
+func executeAlterTable(ctx context.Context, tx *sql.Tx, statement string) (DDLAlgorithm, error) {
+	for _, algorithm := range ddlAlgorithmPreferenceList {
+		stmt, _, _ := alterTableWithAlgorithm(statement, algorithm)
+		_, err := tx.ExecContext(ctx, stmt)
+		if err == nil {
+			return algorithm, nil
+		}
+		if !isAlgorithmNotSupported(err) {
+			return "", err
+		}
+	}
+	// MySQL chooses COPY as none of the online algorithms is supported.
+	if _, err := tx.ExecContext(ctx, statement); err != nil {
+		return "", err
+	}
+	return DDLAlgorithmCopy, nil
+}
@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlterTableWithAlgorithm(t *testing.T) {
+	a := require.New(t)
+	tests := []struct {
+		statement string
+		want      string
+		table     string
+		ok        bool
+	}{
+		{
+			statement: "ALTER TABLE t ADD COLUMN c INT;",
+			want:      "ALTER TABLE t ALGORITHM=INSTANT, ADD COLUMN c INT;",
+			table:     "t",
+			ok:        true,
+		},
+		{
+			statement: "alter table `db`.`my table` drop column c",
+			want:      "alter table `db`.`my table` ALGORITHM=INSTANT, drop column c",
+			table:     "`db`.`my table`",
+			ok:        true,
+		},
+		{
+			statement: "ALTER TABLE t\n  ADD COLUMN c INT, -- the new column\n  ADD INDEX idx_c (c);",
+			want:      "ALTER TABLE t\n  ALGORITHM=INSTANT, ADD COLUMN c INT, -- the new column\n  ADD INDEX idx_c (c);",
+			table:     "t",
+			ok:        true,
+		},
+		{
+			statement: "ALTER TABLE t ORDER BY a, b;",
+			want:      "ALTER TABLE t ALGORITHM=INSTANT, ORDER BY a, b;",
+			table:     "t",
+			ok:        true,
+		},
+		{
+			// The column named algorithm isn't the explicit algorithm.
+			statement: "ALTER TABLE t ADD COLUMN algorithm VARCHAR(20);",
+			want:      "ALTER TABLE t ALGORITHM=INSTANT, ADD COLUMN algorithm VARCHAR(20);",
+			table:     "t",
+			ok:        true,
+		},
+		{
+			statement: "ALTER TABLE t ADD COLUMN c INT, ALGORITHM=INPLACE;",
+		},
+		{
+			statement: "ALTER TABLE t ALGORITHM COPY, ADD COLUMN c INT;",
+		},
+		{
+			statement: "ALTER TABLE t ADD PARTITION (PARTITION p3 VALUES LESS THAN (2000));",
+		},
+		{
+			statement: "ALTER TABLE t;",
+		},
+		{
+			statement: "CREATE TABLE t (id INT);",
+		},
+		{
+			statement: "INSERT INTO t VALUES ('ALTER TABLE t ADD COLUMN c INT');",
+		},
+	}
+
+	for _, test := range tests {
+		got, table, ok := alterTableWithAlgorithm(test.statement, DDLAlgorithmInstant)
+		a.Equal(test.ok, ok, test.statement)
+		a.Equal(test.want, got, test.statement)
+		a.Equal(test.table, table, test.statement)
+	}
+}
+
+func TestIsAlgorithmNotSupported(t *testing.T) {
+	a := require.New(t)
+	a.True(isAlgorithmNotSupported(&mysql.MySQLError{Number: erAlterOperationNotSupported}))
+	a.True(isAlgorithmNotSupported(errors.Wrap(&mysql.MySQLError{Number: erAlterOperationNotSupportedReason}, "failed")))
+	// ER_DUP_FIELDNAME
+	a.False(isAlgorithmNotSupported(&mysql.MySQLError{Number: 1060}))
+	a.False(isAlgorithmNotSupported(errors.New("bad connection")))
+}
@@ -668,6 +668,9 @@ func (driver *Driver) Restore(ctx context.Context, backup io.Reader) error {
 }
 
 func (driver *Driver) restoreImpl(ctx context.Context, backup io.Reader, databaseName string) error {
+	if err := driver.connCfg.CheckExternalTool("mysql"); err != nil {
+		return err
+	}
 	mysqlArgs := []string{
 		"--host", driver.connCfg.Host,
 		"--user", driver.connCfg.Username,
@@ -12,6 +12,7 @@ import (
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -20,8 +21,13 @@ var (
 	migrationSchema string
 
 	_ util.MigrationExecutor = (*Driver)(nil)
+	_ util.MigrationLocker   = (*Driver)(nil)
 )
 
+// migrationLockTimeoutSeconds is the timeout of waiting for the migration lock.
+// It's effectively unlimited, because the wait is canceled with the migration.
+const migrationLockTimeoutSeconds = 24 * 60 * 60
+
 // NeedsSetupMigration returns whether it needs to setup migration.
 func (driver *Driver) NeedsSetupMigration(ctx context.Context) (bool, error) {
 	const query = `
@@ -201,6 +207,29 @@ func (driver *Driver) ExecuteMigration(ctx context.Context, m *db.MigrationInfo,
 	return util.ExecuteMigration(ctx, driver, m, statement, db.BytebaseDatabase)
 }
 
+// LockMigration acquires the migration lock of the database with GET_LOCK, the lock names are shared by the whole server.
+func (driver *Driver) LockMigration(ctx context.Context, database string) (func(), error) {
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lockName := util.GetMigrationLockName(database)
+	return util.LockMigrationOnConn(ctx, driver, conn, func(ctx context.Context) error {
+		// GET_LOCK returns 1 if the lock is acquired, 0 if the wait times out and NULL on error.
+		var acquired sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, migrationLockTimeoutSeconds).Scan(&acquired); err != nil {
+			return err
+		}
+		if !acquired.Valid || acquired.Int64 != 1 {
+			return errors.Errorf("failed to acquire lock %q", lockName)
+		}
+		return nil
+	}, func(ctx context.Context) error {
+		var released sql.NullInt64
+		return conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", lockName).Scan(&released)
+	})
+}
+
 // FindMigrationHistoryList finds the migration history.
 func (driver *Driver) FindMigrationHistoryList(ctx context.Context, find *db.MigrationHistoryFind) ([]*db.MigrationHistory, error) {
 	baseQuery := `
@@ -5,13 +5,18 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
 	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -20,12 +25,36 @@ var (
 	baseTableType = "BASE TABLE"
 	viewTableType = "VIEW"
 
-	_ db.Driver = (*Driver)(nil)
+	_ db.Driver   = (*Driver)(nil)
+	_ db.Resetter = (*Driver)(nil)
+
+	// dialerMap is the map from the dialer key to the dialer of the drivers opened with the dialer.
+	dialerMap sync.Map
+)
+
+const (
+	// dialerNetwork is the network of the DSN for the drivers opened with the dialer, whose address is the dialer key.
+	// The MySQL driver only looks up the custom dialers by the network.
+	dialerNetwork = "bytebase-dialer"
 )
 
+// registeredDialer is the dialer of a driver and the database address it dials.
+type registeredDialer struct {
+	dial    db.DialContextFunc
+	address string
+}
+
 func init() {
 	db.Register(db.MySQL, newDriver)
 	db.Register(db.TiDB, newDriver)
+	mysql.RegisterDialContext(dialerNetwork, func(ctx context.Context, key string) (net.Conn, error) {
+		v, ok := dialerMap.Load(key)
+		if !ok {
+			return nil, errors.Errorf("dialer %q not found, the driver may be closed", key)
+		}
+		dialer := v.(registeredDialer)
+		return dialer.dial(ctx, dialer.address)
+	})
 }
 
 // Driver is the MySQL driver.
@@ -36,8 +65,12 @@ type Driver struct {
 	resourceDir   string
 	binlogDir     string
 	db            *sql.DB
+	// dialerKey is the key of the dialer in dialerMap if the driver is opened with the dialer.
+	dialerKey string
 
 	replayBinlogCounter *common.CountingReader
+	// ddlAlgorithmLogger is set by PreferOnlineDDLAlgorithm.
+	ddlAlgorithmLogger func(*DDLAlgorithmLog)
 }
 
 func newDriver(dc db.DriverConfig) db.Driver {
@@ -55,6 +88,10 @@ func (driver *Driver) Open(_ context.Context, dbType db.Type, connCfg db.Connect
 	}
 
 	params := []string{"multiStatements=true"}
+	// The MySQL driver sets the unknown params as the system variables on each connection.
+	for _, variable := range connCfg.SessionVariableList {
+		params = append(params, fmt.Sprintf("%s=%s", variable.Name, url.QueryEscape(formatSessionVariableValue(variable.Value))))
+	}
 
 	port := connCfg.Port
 	if port == "" {
@@ -70,19 +107,32 @@ func (driver *Driver) Open(_ context.Context, dbType db.Type, connCfg db.Connect
 		return nil, errors.Wrap(err, "sql: tls config error")
 	}
 
-	loggedDSN := fmt.Sprintf("%s:<<redacted password>>@%s(%s:%s)/%s?%s", connCfg.Username, protocol, connCfg.Host, port, connCfg.Database, strings.Join(params, "&"))
-	dsn := fmt.Sprintf("%s@%s(%s:%s)/%s?%s", connCfg.Username, protocol, connCfg.Host, port, connCfg.Database, strings.Join(params, "&"))
+	address := fmt.Sprintf("%s:%s", connCfg.Host, port)
+	if connCfg.Dialer != nil {
+		driver.dialerKey = uuid.New().String()
+		dialerMap.Store(driver.dialerKey, registeredDialer{dial: connCfg.Dialer, address: address})
+		protocol, address = dialerNetwork, driver.dialerKey
+		// The address of the DSN is the dialer key, so the server name is set from the database host.
+		if tlsConfig != nil {
+			tlsConfig.ServerName = connCfg.Host
+		}
+	}
+
+	loggedDSN := fmt.Sprintf("%s:<<redacted password>>@%s(%s)/%s?%s", connCfg.Username, protocol, address, connCfg.Database, strings.Join(params, "&"))
+	dsn := fmt.Sprintf("%s@%s(%s)/%s?%s", connCfg.Username, protocol, address, connCfg.Database, strings.Join(params, "&"))
 	if connCfg.Password != "" {
-		dsn = fmt.Sprintf("%s:%s@%s(%s:%s)/%s?%s", connCfg.Username, connCfg.Password, protocol, connCfg.Host, port, connCfg.Database, strings.Join(params, "&"))
+		dsn = fmt.Sprintf("%s:%s@%s(%s)/%s?%s", connCfg.Username, connCfg.Password, protocol, address, connCfg.Database, strings.Join(params, "&"))
 	}
-	tlsKey := "db.mysql.tls"
 	if tlsConfig != nil {
+		// The key is unique for each driver, so that the concurrently opened drivers don't deregister the TLS config of each other.
+		tlsKey := fmt.Sprintf("db.mysql.tls.%s", uuid.New().String())
 		if err := mysql.RegisterTLSConfig(tlsKey, tlsConfig); err != nil {
+			driver.deregisterDialer()
 			return nil, errors.Wrap(err, "sql: failed to register tls config")
 		}
 		// TLS config is only used during sql.Open, so should be safe to deregister afterwards.
 		defer mysql.DeregisterTLSConfig(tlsKey)
-		dsn += fmt.Sprintf("?tls=%s", tlsKey)
+		dsn += fmt.Sprintf("&tls=%s", tlsKey)
 	}
 	log.Debug("Opening MySQL driver",
 		zap.String("dsn", loggedDSN),
@@ -91,6 +141,7 @@ func (driver *Driver) Open(_ context.Context, dbType db.Type, connCfg db.Connect
 	)
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
+		driver.deregisterDialer()
 		return nil, err
 	}
 	driver.dbType = dbType
@@ -101,9 +152,32 @@ func (driver *Driver) Open(_ context.Context, dbType db.Type, connCfg db.Connect
 	return driver, nil
 }
 
+// formatSessionVariableValue formats the session variable value as a literal in the SET statement.
+// The numbers are kept as is because the integer variables reject the string literals, e.g. lock_wait_timeout.
+func formatSessionVariableValue(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return quoteStringLiteral(value)
+}
+
+// quoteStringLiteral quotes the value as a string literal.
+func quoteStringLiteral(value string) string {
+	return fmt.Sprintf("'%s'", strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value))
+}
+
 // Close closes the driver.
 func (driver *Driver) Close(context.Context) error {
-	return driver.db.Close()
+	err := driver.db.Close()
+	driver.deregisterDialer()
+	return err
+}
+
+// deregisterDialer removes the dialer of the driver if it's opened with the dialer.
+func (driver *Driver) deregisterDialer() {
+	if driver.dialerKey != "" {
+		dialerMap.Delete(driver.dialerKey)
+	}
 }
 
 // Ping pings the database.
@@ -154,13 +228,30 @@ func (driver *Driver) getVersion(ctx context.Context) (string, error) {
 
 // Execute executes a SQL statement.
 func (driver *Driver) Execute(ctx context.Context, statement string) error {
-	tx, err := driver.db.BeginTx(ctx, nil)
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop, err := util.CancelQueryOnDone(ctx, driver, conn)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	tx, err := conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	_, err = tx.ExecContext(ctx, statement)
+	executed := false
+	if driver.ddlAlgorithmLogger != nil {
+		executed, err = driver.executeWithPreferredAlgorithm(ctx, tx, statement)
+	}
+	if !executed && err == nil {
+		_, err = tx.ExecContext(ctx, statement)
+	}
 
 	if err == nil {
 		if err := tx.Commit(); err != nil {
@@ -171,7 +262,55 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 	return err
 }
 
+// Reset clears the per-use options, e.g. set by PreferOnlineDDLAlgorithm, and the session state of the connections.
+func (driver *Driver) Reset(ctx context.Context) error {
+	driver.ddlAlgorithmLogger = nil
+	driver.replayBinlogCounter = nil
+	return util.ResetIdleConnections(ctx, driver.db, driver.getResetSessionStatementList()...)
+}
+
+// resetSessionVariableList is the session variables commonly changed by the migrations and the SQL editor.
+// MySQL can't reset all the session variables by SQL, so these are restored to the global values.
+var resetSessionVariableList = []string{"foreign_key_checks", "unique_checks", "sql_mode", "time_zone", "autocommit"}
+
+// getResetSessionStatementList returns the statements restoring the session state of a connection to the state right after connecting,
+// i.e. the database and the session variables of the connection config.
+func (driver *Driver) getResetSessionStatementList() []string {
+	var stmtList []string
+	if driver.connCfg.Database != "" {
+		stmtList = append(stmtList, fmt.Sprintf("USE `%s`", driver.connCfg.Database))
+	}
+	var assignmentList []string
+	for _, name := range resetSessionVariableList {
+		assignmentList = append(assignmentList, fmt.Sprintf("%s = DEFAULT", name))
+	}
+	// The session variables of the connection config are set on connecting, and assigned after the defaults to override them.
+	for _, variable := range driver.connCfg.SessionVariableList {
+		assignmentList = append(assignmentList, fmt.Sprintf("%s = %s", variable.Name, formatSessionVariableValue(variable.Value)))
+	}
+	return append(stmtList, fmt.Sprintf("SET SESSION %s", strings.Join(assignmentList, ", ")))
+}
+
+// GetBackendID returns the ID of the backend connection conn.
+func (*Driver) GetBackendID(ctx context.Context, conn *sql.Conn) (int64, error) {
+	var id int64
+	if err := conn.QueryRowContext(ctx, "SELECT CONNECTION_ID()").Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// CancelQuery kills the query running on the backend connection with backendID.
+func (driver *Driver) CancelQuery(ctx context.Context, backendID int64) error {
+	stmt := fmt.Sprintf("KILL QUERY %d", backendID)
+	if driver.dbType == db.TiDB {
+		stmt = fmt.Sprintf("KILL TIDB QUERY %d", backendID)
+	}
+	_, err := driver.db.ExecContext(ctx, stmt)
+	return err
+}
+
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	return util.Query(ctx, driver.db, statement, queryContext)
 }
@@ -0,0 +1,186 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestOpenWithDialer(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	var addressList []string
+	connCfg := db.ConnectionConfig{
+		Host:     "db.internal",
+		Username: "bytebase",
+		Dialer: func(_ context.Context, address string) (net.Conn, error) {
+			addressList = append(addressList, address)
+			return nil, errors.New("agent is not connected")
+		},
+	}
+	driver, err := newDriver(db.DriverConfig{}).Open(ctx, db.MySQL, connCfg, db.ConnectionContext{})
+	a.NoError(err)
+	err = driver.Ping(ctx)
+	a.Error(err)
+	a.Contains(err.Error(), "agent is not connected")
+	a.NotEmpty(addressList)
+	for _, address := range addressList {
+		a.Equal("db.internal:3306", address)
+	}
+
+	dialerKey := driver.(*Driver).dialerKey
+	_, ok := dialerMap.Load(dialerKey)
+	a.True(ok)
+	a.NoError(driver.Close(ctx))
+	_, ok = dialerMap.Load(dialerKey)
+	a.False(ok)
+}
+
+func TestResetKeepsConnectionWithoutSessionState(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	sqldb, err := sql.Open("fake-mysql-session", "")
+	a.NoError(err)
+	defer sqldb.Close()
+	mysqlDriver := &Driver{
+		connCfg: db.ConnectionConfig{
+			Database: "shop",
+			SessionVariableList: []db.SessionVariable{
+				{Name: "sql_mode", Value: "ANSI"},
+			},
+		},
+		db: sqldb,
+	}
+	getSession := func() (int64, string) {
+		var id int64
+		a.NoError(sqldb.QueryRowContext(ctx, "SELECT CONNECTION_ID()").Scan(&id))
+		var session string
+		a.NoError(sqldb.QueryRowContext(ctx, "SELECT @@session").Scan(&session))
+		return id, session
+	}
+
+	_, err = sqldb.ExecContext(ctx, "USE `shop`")
+	a.NoError(err)
+	_, err = sqldb.ExecContext(ctx, "SET SESSION sql_mode = 'ANSI'")
+	a.NoError(err)
+	id, session := getSession()
+	a.Equal("shop;sql_mode='ANSI'", session)
+
+	// The previous user of the driver switches the database and changes the session variables.
+	_, err = sqldb.ExecContext(ctx, "USE `other`; SET SESSION foreign_key_checks = 0, sql_mode = ''")
+	a.NoError(err)
+	_, session = getSession()
+	a.Equal("other;foreign_key_checks=0;sql_mode=''", session)
+
+	a.NoError(mysqlDriver.Reset(ctx))
+	resetID, session := getSession()
+	a.Equal(id, resetID)
+	a.Equal("shop;sql_mode='ANSI'", session)
+}
+
+// fakeMySQLSessionDriver is a database/sql driver whose connections keep the database and the session variables
+// changed by "USE `db`" and "SET SESSION name = value, ...", like MySQL.
+type fakeMySQLSessionDriver struct {
+	connCount int64
+}
+
+func init() {
+	sql.Register("fake-mysql-session", &fakeMySQLSessionDriver{})
+}
+
+type fakeMySQLSessionConn struct {
+	id        int64
+	database  string
+	variables map[string]string
+}
+
+type fakeMySQLSessionRows struct {
+	value interface{}
+	done  bool
+}
+
+func (d *fakeMySQLSessionDriver) Open(string) (driver.Conn, error) {
+	return &fakeMySQLSessionConn{
+		id:        atomic.AddInt64(&d.connCount, 1),
+		variables: make(map[string]string),
+	}, nil
+}
+
+func (*fakeMySQLSessionConn) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (*fakeMySQLSessionConn) Close() error {
+	return nil
+}
+
+func (*fakeMySQLSessionConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *fakeMySQLSessionConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	for _, stmt := range strings.Split(query, ";") {
+		stmt = strings.TrimSpace(stmt)
+		switch {
+		case strings.HasPrefix(stmt, "USE "):
+			c.database = strings.Trim(strings.TrimPrefix(stmt, "USE "), "`")
+		case strings.HasPrefix(stmt, "SET SESSION "):
+			for _, assignment := range strings.Split(strings.TrimPrefix(stmt, "SET SESSION "), ", ") {
+				parts := strings.SplitN(assignment, " = ", 2)
+				if len(parts) != 2 {
+					return nil, errors.Errorf("invalid assignment %q", assignment)
+				}
+				if parts[1] == "DEFAULT" {
+					delete(c.variables, parts[0])
+				} else {
+					c.variables[parts[0]] = parts[1]
+				}
+			}
+		default:
+			return nil, errors.Errorf("unexpected statement %q", stmt)
+		}
+	}
+	return driver.RowsAffected(0), nil
+}
+
+// QueryContext returns the connection ID for "SELECT CONNECTION_ID()", otherwise the database and the sorted session variables.
+func (c *fakeMySQLSessionConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if query == "SELECT CONNECTION_ID()" {
+		return &fakeMySQLSessionRows{value: c.id}, nil
+	}
+	session := []string{c.database}
+	for _, name := range []string{"foreign_key_checks", "sql_mode"} {
+		if value, ok := c.variables[name]; ok {
+			session = append(session, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+	return &fakeMySQLSessionRows{value: strings.Join(session, ";")}, nil
+}
+
+func (*fakeMySQLSessionRows) Columns() []string {
+	return []string{"value"}
+}
+
+func (*fakeMySQLSessionRows) Close() error {
+	return nil
+}
+
+func (r *fakeMySQLSessionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
@@ -106,6 +106,9 @@ func (driver *Driver) replayBinlog(ctx context.Context, originalDatabase, target
 
 // replayBinlogReadFromDir replays the binlog for `originDatabase` from `startBinlogInfo.Position` to `targetTs`, read binlog from `binlogDir`.
 func (driver *Driver) replayBinlogReadFromDir(ctx context.Context, originalDatabase, targetDatabase string, startBinlogInfo api.BinlogInfo, targetTs int64, binlogDir string) error {
+	if err := driver.connCfg.CheckExternalTool("mysql"); err != nil {
+		return err
+	}
 	replayBinlogPaths, err := GetBinlogReplayList(startBinlogInfo, binlogDir)
 	if err != nil {
 		return err
@@ -574,6 +577,9 @@ func (driver *Driver) FetchAllBinlogFiles(ctx context.Context, downloadLatestBin
 // It may keep growing as there are ongoing writes to the database. So we just need to check that
 // the file size is larger or equal to the binlog file size we queried from the MySQL server earlier.
 func (driver *Driver) downloadBinlogFile(ctx context.Context, binlogFileToDownload BinlogFile, isLast bool) error {
+	if err := driver.connCfg.CheckExternalTool("mysqlbinlog"); err != nil {
+		return err
+	}
 	tempDir := os.TempDir()
 	// for mysqlbinlog binary, --result-file must end with '/'
 	mysqlbinlogResultFileDir := strings.TrimRight(tempDir, "/") + "/"
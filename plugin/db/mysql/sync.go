@@ -452,3 +452,45 @@ func (driver *Driver) getUserList(ctx context.Context) ([]db.User, error) {
 	}
 	return userList, nil
 }
+
+// SyncDBGrant syncs the privileges granted on the database.
+func (driver *Driver) SyncDBGrant(ctx context.Context, databaseName string) ([]db.Grant, error) {
+	query := `
+		SELECT
+			GRANTEE,
+			PRIVILEGE_TYPE
+		FROM information_schema.SCHEMA_PRIVILEGES
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY GRANTEE, PRIVILEGE_TYPE
+	`
+	rows, err := driver.db.QueryContext(ctx, query, databaseName)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var grantList []db.Grant
+	for rows.Next() {
+		var grantee, privilege string
+		if err := rows.Scan(&grantee, &privilege); err != nil {
+			return nil, err
+		}
+		grantList = append(grantList, db.Grant{
+			Grantee:   formatGrantee(grantee),
+			Privilege: strings.ToUpper(privilege),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return grantList, nil
+}
+
+// formatGrantee converts the grantee in the form of 'user'@'host' to user@host.
+func formatGrantee(grantee string) string {
+	i := strings.LastIndex(grantee, "@")
+	if i < 0 {
+		return strings.Trim(grantee, "'")
+	}
+	return fmt.Sprintf("%s@%s", strings.Trim(grantee[:i], "'"), strings.Trim(grantee[i+1:], "'"))
+}
@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// tidbDDLJobSearchLimit is the number of the latest DDL jobs searched for the jobs created by the migration.
+const tidbDDLJobSearchLimit = 1000
+
+// GetLatestTiDBDDLJobID returns the ID of the latest DDL job of the TiDB cluster, 0 if no DDL job has run.
+func (driver *Driver) GetLatestTiDBDDLJobID(ctx context.Context) (int64, error) {
+	if driver.dbType != db.TiDB {
+		return 0, errors.Errorf("DDL jobs are only supported for TiDB, got %s", driver.dbType)
+	}
+	// The running jobs are listed in addition to the latest history job.
+	idList, err := driver.showDDLJobIDList(ctx, "ADMIN SHOW DDL JOBS 1")
+	if err != nil {
+		return 0, err
+	}
+	var latestID int64
+	for _, id := range idList {
+		if id > latestID {
+			latestID = id
+		}
+	}
+	return latestID, nil
+}
+
+// FindTiDBDDLJobIDList returns the IDs of the DDL jobs on the database created after the job with afterJobID, in ascending order.
+func (driver *Driver) FindTiDBDDLJobIDList(ctx context.Context, database string, afterJobID int64) ([]int64, error) {
+	if driver.dbType != db.TiDB {
+		return nil, errors.Errorf("DDL jobs are only supported for TiDB, got %s", driver.dbType)
+	}
+	query := fmt.Sprintf("ADMIN SHOW DDL JOBS %d WHERE DB_NAME = %s AND JOB_ID > %d", tidbDDLJobSearchLimit, quoteStringLiteral(database), afterJobID)
+	idList, err := driver.showDDLJobIDList(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(idList, func(i, j int) bool {
+		return idList[i] < idList[j]
+	})
+	return idList, nil
+}
+
+// showDDLJobIDList returns the job IDs listed by the ADMIN SHOW DDL JOBS query.
+// The columns are looked up by name, because they vary across the TiDB versions.
+func (driver *Driver) showDDLJobIDList(ctx context.Context, query string) ([]int64, error) {
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	columnList, err := rows.Columns()
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	jobIDIndex := -1
+	for i, column := range columnList {
+		if strings.EqualFold(column, "JOB_ID") {
+			jobIDIndex = i
+			break
+		}
+	}
+	if jobIDIndex < 0 {
+		return nil, errors.Errorf("column JOB_ID not found in the result of %q", query)
+	}
+
+	var idList []int64
+	for rows.Next() {
+		valueList := make([]sql.NullString, len(columnList))
+		dest := make([]interface{}, len(columnList))
+		for i := range valueList {
+			dest[i] = &valueList[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		id, err := strconv.ParseInt(valueList[jobIDIndex].String, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid DDL job ID %q", valueList[jobIDIndex].String)
+		}
+		idList = append(idList, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return idList, nil
+}
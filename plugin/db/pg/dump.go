@@ -19,6 +19,10 @@ import (
 func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) (string, error) {
 	// pg_dump -d dbName --schema-only+
 
+	if err := driver.config.CheckExternalTool("pg_dump"); err != nil {
+		return "", err
+	}
+
 	// Find all dumpable databases
 	databases, err := driver.getDatabases(ctx)
 	if err != nil {
@@ -20,6 +20,7 @@ var (
 	migrationSchema string
 
 	_ util.MigrationExecutor = (*Driver)(nil)
+	_ util.MigrationLocker   = (*Driver)(nil)
 )
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -248,6 +249,37 @@ func (driver *Driver) ExecuteMigration(ctx context.Context, m *db.MigrationInfo,
 	return util.ExecuteMigration(ctx, driver, m, statement, db.BytebaseDatabase)
 }
 
+// LockMigration acquires the migration lock of the database with pg_advisory_lock on the key hashtext(lock name).
+// The advisory locks are scoped to the database, so the lock is held on a dedicated connection to the database,
+// which isn't closed by switching the database of the driver.
+func (driver *Driver) LockMigration(ctx context.Context, database string) (func(), error) {
+	lockDB, err := openDB(getDatabaseDSN(driver.baseDSN, database), driver.tlsConfig, driver.dialer)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := lockDB.Conn(ctx)
+	if err != nil {
+		lockDB.Close()
+		return nil, err
+	}
+	lockName := util.GetMigrationLockName(database)
+	unlock, err := util.LockMigrationOnConn(ctx, driver, conn, func(ctx context.Context) error {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", lockName)
+		return err
+	}, func(ctx context.Context) error {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", lockName)
+		return err
+	})
+	if err != nil {
+		lockDB.Close()
+		return nil, err
+	}
+	return func() {
+		unlock()
+		lockDB.Close()
+	}, nil
+}
+
 // FindMigrationHistoryList finds the migration history.
 func (driver *Driver) FindMigrationHistoryList(ctx context.Context, find *db.MigrationHistoryFind) ([]*db.MigrationHistory, error) {
 	baseQuery := `
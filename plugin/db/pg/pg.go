@@ -3,13 +3,16 @@ package pg
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"fmt"
+	"net"
 	"strings"
 
+	"github.com/jackc/pgx/v4"
 	// Import pg driver.
 	// init() in pgx/v4/stdlib will register it's pgx driver.
-	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/jackc/pgx/v4/stdlib"
 	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/common"
@@ -28,7 +31,8 @@ var (
 	// driverName is the driver name that our driver dependence register, now is "pgx".
 	driverName = "pgx"
 
-	_ db.Driver = (*Driver)(nil)
+	_ db.Driver   = (*Driver)(nil)
+	_ db.Resetter = (*Driver)(nil)
 )
 
 func init() {
@@ -43,7 +47,11 @@ type Driver struct {
 
 	db           *sql.DB
 	baseDSN      string
+	tlsConfig    *tls.Config
+	dialer       db.DialContextFunc
 	databaseName string
+	// openDatabaseName is the database connected on Open, the driver switches to other databases on demand.
+	openDatabaseName string
 
 	// strictDatabase should be used only if the user gives only a database instead of a whole instance to access.
 	strictDatabase string
@@ -57,9 +65,12 @@ func newDriver(config db.DriverConfig) db.Driver {
 
 // Open opens a Postgres driver.
 func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
-	if (config.TLSConfig.SslCert == "" && config.TLSConfig.SslKey != "") ||
-		(config.TLSConfig.SslCert != "" && config.TLSConfig.SslKey == "") {
-		return nil, errors.Errorf("ssl-cert and ssl-key must be both set or unset")
+	if err := config.TLSConfig.Validate(); err != nil {
+		return nil, err
+	}
+	tlsConfig, err := config.TLSConfig.GetSslConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql: tls config error")
 	}
 
 	databaseName, dsn, err := guessDSN(
@@ -68,9 +79,8 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 		config.Host,
 		config.Port,
 		config.Database,
-		config.TLSConfig.SslCA,
-		config.TLSConfig.SslCert,
-		config.TLSConfig.SslKey,
+		tlsConfig,
+		config.Dialer,
 	)
 	if err != nil {
 		return nil, err
@@ -78,15 +88,22 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 	if config.ReadOnly {
 		dsn = fmt.Sprintf("%s default_transaction_read_only=true", dsn)
 	}
+	// The unknown DSN keys are sent as the run-time parameters on each connection.
+	for _, variable := range config.SessionVariableList {
+		dsn = fmt.Sprintf("%s %s=%s", dsn, variable.Name, quoteDSNValue(variable.Value))
+	}
 	driver.databaseName = databaseName
+	driver.openDatabaseName = databaseName
 	driver.baseDSN = dsn
+	driver.tlsConfig = tlsConfig
+	driver.dialer = config.Dialer
 	driver.connectionCtx = connCtx
 	driver.config = config
 	if config.StrictUseDb {
 		driver.strictDatabase = config.Database
 	}
 
-	db, err := sql.Open(driverName, dsn)
+	db, err := openDB(dsn, tlsConfig, config.Dialer)
 	if err != nil {
 		return nil, err
 	}
@@ -94,8 +111,45 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 	return driver, nil
 }
 
+// openDB opens the database with the DSN, the TLS config and the dialer. The TLS config is set on the connection config,
+// because sslrootcert, sslcert and sslkey in the DSN are the file paths while the TLS config is built from the contents.
+func openDB(dsn string, tlsConfig *tls.Config, dialer db.DialContextFunc) (*sql.DB, error) {
+	if tlsConfig == nil && dialer == nil {
+		return sql.Open(driverName, dsn)
+	}
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		connConfig.TLSConfig = tlsConfig
+		// The fallbacks of the default sslmode "prefer" connect without TLS, which shouldn't happen if the CA is specified.
+		connConfig.Fallbacks = nil
+	}
+	if dialer != nil {
+		// The host may only be resolvable on the other side of the dialer, so it's dialed as is.
+		connConfig.LookupFunc = func(_ context.Context, host string) ([]string, error) {
+			return []string{host}, nil
+		}
+		connConfig.DialFunc = func(ctx context.Context, _, address string) (net.Conn, error) {
+			return dialer(ctx, address)
+		}
+	}
+	return stdlib.OpenDB(*connConfig), nil
+}
+
+// quoteDSNValue quotes the value in the keyword/value connection string.
+func quoteDSNValue(value string) string {
+	return fmt.Sprintf("'%s'", strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value))
+}
+
+// getDatabaseDSN returns the DSN connecting the database, the database name is quoted since it may contain spaces or quotes.
+func getDatabaseDSN(baseDSN, database string) string {
+	return fmt.Sprintf("%s dbname=%s", baseDSN, quoteDSNValue(database))
+}
+
 // guessDSN will guess a valid DB connection and its database name.
-func guessDSN(username, password, hostname, port, database, sslCA, sslCert, sslKey string) (string, string, error) {
+func guessDSN(username, password, hostname, port, database string, tlsConfig *tls.Config, dialer db.DialContextFunc) (string, string, error) {
 	// dbname is guessed if not specified.
 	m := map[string]string{
 		"host":     hostname,
@@ -108,14 +162,7 @@ func guessDSN(username, password, hostname, port, database, sslCA, sslCert, sslK
 	// Some provider might still perform default SSL check at the server side so we
 	// shouldn't disable sslmode at the client side.
 	// m["sslmode"] = "disable"
-	if sslCA != "" {
-		m["sslmode"] = "verify-ca"
-		m["sslrootcert"] = sslCA
-		if sslCert != "" && sslKey != "" {
-			m["sslcert"] = sslCert
-			m["sslkey"] = sslKey
-		}
-	}
+	// The TLS config verifying the CA is set by openDB if specified.
 	var tokens []string
 	for k, v := range m {
 		if v != "" {
@@ -139,10 +186,10 @@ func guessDSN(username, password, hostname, port, database, sslCA, sslCert, sslK
 	for _, guess := range guesses {
 		guessDSN := dsn
 		if guess != "" {
-			guessDSN = fmt.Sprintf("%s dbname=%s", dsn, guess)
+			guessDSN = getDatabaseDSN(dsn, guess)
 		}
 		if err := func() error {
-			db, err := sql.Open(driverName, guessDSN)
+			db, err := openDB(guessDSN, tlsConfig, dialer)
 			if err != nil {
 				return err
 			}
@@ -178,6 +225,16 @@ func (driver *Driver) GetDBConnection(_ context.Context, database string) (*sql.
 	return driver.db, nil
 }
 
+// Reset switches back to the database connected on Open, and clears the session state of the connections.
+// The session parameters are restored to the values on connecting and the temporary tables are dropped,
+// while the prepared statements are kept for the statement cache of pgx.
+func (driver *Driver) Reset(ctx context.Context) error {
+	if driver.databaseName == driver.openDatabaseName {
+		return util.ResetIdleConnections(ctx, driver.db, "RESET ALL", "DISCARD TEMP")
+	}
+	return driver.switchDatabase(driver.openDatabaseName)
+}
+
 // getDatabases gets all databases of an instance.
 func (driver *Driver) getDatabases(ctx context.Context) ([]*pgDatabaseSchema, error) {
 	var dbs []*pgDatabaseSchema
@@ -282,7 +339,18 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 		return nil
 	}
 
-	tx, err := driver.db.BeginTx(ctx, nil)
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop, err := util.CancelQueryOnDone(ctx, driver, conn)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	tx, err := conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -300,6 +368,21 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 	return tx.Commit()
 }
 
+// GetBackendID returns the process ID of the backend connection conn.
+func (*Driver) GetBackendID(ctx context.Context, conn *sql.Conn) (int64, error) {
+	var pid int64
+	if err := conn.QueryRowContext(ctx, "SELECT pg_backend_pid()").Scan(&pid); err != nil {
+		return 0, err
+	}
+	return pid, nil
+}
+
+// CancelQuery cancels the query running on the backend process with backendID.
+func (driver *Driver) CancelQuery(ctx context.Context, backendID int64) error {
+	_, err := driver.db.ExecContext(ctx, "SELECT pg_cancel_backend($1)", backendID)
+	return err
+}
+
 func isSuperuserStatement(stmt string) bool {
 	upperCaseStmt := strings.ToUpper(stmt)
 	if strings.Contains(upperCaseStmt, "CREATE EVENT TRIGGER") || strings.Contains(upperCaseStmt, "CREATE EXTENSION") || strings.Contains(upperCaseStmt, "COMMENT ON EXTENSION") || strings.Contains(upperCaseStmt, "COMMENT ON EVENT TRIGGER") {
@@ -354,8 +437,8 @@ func (driver *Driver) GetCurrentDatabaseOwner() (string, error) {
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	return util.Query(ctx, driver.db, statement, queryContext)
 }
 
 func (driver *Driver) switchDatabase(dbName string) error {
@@ -365,8 +448,8 @@ func (driver *Driver) switchDatabase(dbName string) error {
 		}
 	}
 
-	dsn := driver.baseDSN + " dbname=" + dbName
-	db, err := sql.Open(driverName, dsn)
+	dsn := getDatabaseDSN(driver.baseDSN, dbName)
+	db, err := openDB(dsn, driver.tlsConfig, driver.dialer)
 	if err != nil {
 		return err
 	}
@@ -1,8 +1,12 @@
 package pg
 
 import (
+	"context"
+	"net"
 	"testing"
 
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,3 +53,60 @@ func TestGetDatabaseInCreateDatabaseStatement(t *testing.T) {
 		require.Equal(t, test.want, got)
 	}
 }
+
+func TestQuoteDSNValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", `''`},
+		{"30s", `'30s'`},
+		{`it's`, `'it\'s'`},
+		{`a\b`, `'a\\b'`},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, quoteDSNValue(test.value))
+	}
+}
+
+func TestGetDatabaseDSN(t *testing.T) {
+	tests := []struct {
+		database string
+		want     string
+	}{
+		{"employee", `host=localhost dbname='employee'`},
+		{"my db", `host=localhost dbname='my db'`},
+		{`x' host=evil`, `host=localhost dbname='x\' host=evil'`},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		dsn := getDatabaseDSN("host=localhost", test.database)
+		a.Equal(test.want, dsn)
+		connConfig, err := pgx.ParseConfig(dsn)
+		a.NoError(err)
+		a.Equal("localhost", connConfig.Host)
+		a.Equal(test.database, connConfig.Database)
+	}
+}
+
+func TestOpenDBWithDialer(t *testing.T) {
+	a := require.New(t)
+	var addressList []string
+	dialer := func(_ context.Context, address string) (net.Conn, error) {
+		addressList = append(addressList, address)
+		return nil, errors.New("agent is not connected")
+	}
+	// The host is only resolvable by the dialer, it's dialed as is instead of being looked up.
+	db, err := openDB("host=db.internal port=5433 user=bytebase dbname=postgres", nil, dialer)
+	a.NoError(err)
+	defer db.Close()
+	err = db.PingContext(context.Background())
+	a.Error(err)
+	a.Contains(err.Error(), "agent is not connected")
+	a.NotEmpty(addressList)
+	for _, address := range addressList {
+		a.Equal("db.internal:5433", address)
+	}
+}
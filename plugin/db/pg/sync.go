@@ -190,6 +190,7 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 	for _, tbl := range tables {
 		var dbTable db.Table
 		dbTable.Name = fmt.Sprintf("%s.%s", tbl.schemaName, tbl.name)
+		dbTable.Schema = tbl.schemaName
 		dbTable.Type = "BASE TABLE"
 		dbTable.Comment = tbl.comment
 		dbTable.RowCount = tbl.rowCount
@@ -231,6 +232,7 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 	for _, view := range views {
 		var dbView db.View
 		dbView.Name = fmt.Sprintf("%s.%s", view.schemaName, view.name)
+		dbView.Schema = view.schemaName
 		// Postgres does not store
 		dbView.CreatedTs = time.Now().Unix()
 		dbView.Definition = view.definition
@@ -244,6 +246,12 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 		return nil, errors.Wrapf(err, "failed to get extensions from database %q", databaseName)
 	}
 	schema.ExtensionList = extensions
+	// Schemas.
+	namespaces, err := getNamespaces(txn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get schemas from database %q", databaseName)
+	}
+	schema.NamespaceList = namespaces
 
 	if err := txn.Commit(); err != nil {
 		return nil, err
@@ -585,6 +593,35 @@ func getExtensions(txn *sql.Tx) ([]db.Extension, error) {
 	return extensions, nil
 }
 
+// getNamespaces gets all schemas of a database except the system schemas.
+func getNamespaces(txn *sql.Tx) ([]db.Namespace, error) {
+	query := "" +
+		"SELECT nspname, pg_catalog.pg_get_userbyid(nspowner) " +
+		"FROM pg_catalog.pg_namespace " +
+		"WHERE nspname NOT IN ('pg_catalog', 'information_schema') AND nspname !~ '^pg_toast' AND nspname !~ '^pg_temp_' " +
+		"ORDER BY nspname;"
+
+	var namespaces []db.Namespace
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n db.Namespace
+		if err := rows.Scan(&n.Name, &n.Owner); err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return namespaces, nil
+}
+
 // getIndices gets all indices of a database.
 func getIndices(txn *sql.Tx) ([]*indexSchema, error) {
 	query := "" +
@@ -730,3 +767,37 @@ func getIndexColumnExpressions(stmt string) ([]string, error) {
 
 	return cols, nil
 }
+
+// SyncDBGrant syncs the privileges granted on the database.
+// The privileges granted to PUBLIC are skipped.
+func (driver *Driver) SyncDBGrant(ctx context.Context, databaseName string) ([]db.Grant, error) {
+	query := `
+		SELECT r.rolname, a.privilege_type
+		FROM pg_catalog.pg_database d
+		CROSS JOIN LATERAL aclexplode(d.datacl) a
+		JOIN pg_catalog.pg_roles r ON r.oid = a.grantee
+		WHERE d.datname = $1
+		ORDER BY r.rolname, a.privilege_type;
+	`
+	rows, err := driver.db.QueryContext(ctx, query, databaseName)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var grantList []db.Grant
+	for rows.Next() {
+		var grantee, privilege string
+		if err := rows.Scan(&grantee, &privilege); err != nil {
+			return nil, err
+		}
+		grantList = append(grantList, db.Grant{
+			Grantee:   grantee,
+			Privilege: privilege,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return grantList, nil
+}
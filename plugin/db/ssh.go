@@ -0,0 +1,273 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// defaultSSHPort is the port of the SSH server if not specified.
+	defaultSSHPort = "22"
+	// sshDialTimeout is the timeout of connecting and authenticating with the SSH server.
+	sshDialTimeout = 10 * time.Second
+	// sshFingerprintSHA256Prefix is the prefix of the SHA256 fingerprint of the SSH host key.
+	sshFingerprintSHA256Prefix = "SHA256:"
+)
+
+// sshTunnelDefaultPortMap is the default database port of the engines supporting the SSH tunnel,
+// which is forwarded if the port isn't specified.
+var sshTunnelDefaultPortMap = map[Type]string{
+	MySQL:      "3306",
+	TiDB:       "4000",
+	Postgres:   "5432",
+	ClickHouse: "9000",
+}
+
+// IsSSHTunnelSupported returns true if the engine can be connected through the SSH tunnel.
+func IsSSHTunnelSupported(dbType Type) bool {
+	_, ok := sshTunnelDefaultPortMap[dbType]
+	return ok
+}
+
+// SSHConfig is the config of the SSH tunnel through which the database is connected,
+// e.g. the bastion host in front of the production databases. The zero value connects the database directly.
+type SSHConfig struct {
+	Host string
+	// Port is 22 if not specified.
+	Port string
+	User string
+	// PrivateKey is the PEM encoded private key authenticating the user.
+	PrivateKey string
+	// HostKey verifies the SSH server, so that the database credentials aren't sent through a man-in-the-middle.
+	// It's the public key of the server in the authorized_keys format, e.g. "ssh-ed25519 AAAA...",
+	// or its SHA256 fingerprint printed by "ssh-keygen -l", e.g. "SHA256:...".
+	HostKey string
+}
+
+// IsEmpty returns true if the database is connected without the SSH tunnel.
+func (c SSHConfig) IsEmpty() bool {
+	return c == SSHConfig{}
+}
+
+// Validate validates the SSH config, the empty config is valid.
+func (c SSHConfig) Validate() error {
+	if c.IsEmpty() {
+		return nil
+	}
+	if c.Host == "" {
+		return errors.Errorf("SSH host is required")
+	}
+	if c.Port != "" {
+		if port, err := strconv.Atoi(c.Port); err != nil || port <= 0 || port > 65535 {
+			return errors.Errorf("invalid SSH port %q", c.Port)
+		}
+	}
+	if c.User == "" {
+		return errors.Errorf("SSH user is required")
+	}
+	if c.PrivateKey == "" {
+		return errors.Errorf("SSH private key is required")
+	}
+	if _, err := ssh.ParsePrivateKey([]byte(c.PrivateKey)); err != nil {
+		return errors.Wrap(err, "invalid SSH private key")
+	}
+	if _, err := c.hostKeyCallback(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// hostKeyCallback returns the callback accepting only the host key of the config.
+func (c SSHConfig) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	hostKey := strings.TrimSpace(c.HostKey)
+	if hostKey == "" {
+		return nil, errors.Errorf("SSH host key is required")
+	}
+	if strings.HasPrefix(hostKey, sshFingerprintSHA256Prefix) {
+		return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			if fingerprint := ssh.FingerprintSHA256(key); fingerprint != hostKey {
+				return errors.Errorf("SSH host key mismatch, got fingerprint %s", fingerprint)
+			}
+			return nil
+		}, nil
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid SSH host key")
+	}
+	return ssh.FixedHostKey(key), nil
+}
+
+func (c SSHConfig) address() string {
+	port := c.Port
+	if port == "" {
+		port = defaultSSHPort
+	}
+	return net.JoinHostPort(c.Host, port)
+}
+
+// key identifies the SSH connection of the config. The private key is hashed so that it isn't kept in the key.
+func (c SSHConfig) key() string {
+	sum := sha256.Sum256([]byte(c.PrivateKey))
+	return fmt.Sprintf("%s@%s#%s#%s", c.User, c.address(), hex.EncodeToString(sum[:]), strings.TrimSpace(c.HostKey))
+}
+
+// dial connects and authenticates with the SSH server.
+func (c SSHConfig) dial() (*ssh.Client, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(c.PrivateKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid SSH private key")
+	}
+	hostKeyCallback, err := c.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	client, err := ssh.Dial("tcp", c.address(), &ssh.ClientConfig{
+		User:            c.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshDialTimeout,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect SSH server %q as %q", c.address(), c.User)
+	}
+	return client, nil
+}
+
+// sshTunnelManager dials the database connections through the SSH connections in process. The drivers connect the
+// database with the dialer of the connection config, so that the tunnel isn't exposed to the other local processes.
+// The SSH connection is shared by the tunnels of the same SSH config, and kept for reuse until CloseSSHTunnel is called,
+// e.g. on changing the instance.
+type sshTunnelManager struct {
+	mu sync.Mutex
+	// clientMap is the map from the SSH config key to the SSH connection.
+	clientMap map[string]*ssh.Client
+}
+
+var sshTunnels = &sshTunnelManager{
+	clientMap: make(map[string]*ssh.Client),
+}
+
+// openSSHTunnel returns the connection config dialing the database address through the SSH tunnel, and the SSH config
+// is cleared. The connection config without the SSH config is returned as is.
+// The SSH server is connected on opening the tunnel, so that the wrong SSH config fails early.
+func openSSHTunnel(dbType Type, connCfg ConnectionConfig) (ConnectionConfig, error) {
+	if connCfg.SSHConfig.IsEmpty() {
+		return connCfg, nil
+	}
+	if !IsSSHTunnelSupported(dbType) {
+		return ConnectionConfig{}, errors.Errorf("SSH tunnel is not supported for %s", dbType)
+	}
+	if strings.HasPrefix(connCfg.Host, "/") {
+		return ConnectionConfig{}, errors.Errorf("SSH tunnel is not supported for the unix socket %q", connCfg.Host)
+	}
+	if err := connCfg.SSHConfig.Validate(); err != nil {
+		return ConnectionConfig{}, err
+	}
+	config := connCfg.SSHConfig
+	port := connCfg.Port
+	if port == "" {
+		port = sshTunnelDefaultPortMap[dbType]
+	}
+	dbAddress := net.JoinHostPort(connCfg.Host, port)
+	if _, err := sshTunnels.getClient(config); err != nil {
+		return ConnectionConfig{}, err
+	}
+	// The Host and Port are kept as the database address, e.g. for the TLS server name.
+	connCfg.Dialer = func(_ context.Context, _ string) (net.Conn, error) {
+		return sshTunnels.dial(config, dbAddress)
+	}
+	connCfg.SSHConfig = SSHConfig{}
+	return connCfg, nil
+}
+
+// CloseSSHTunnel closes the SSH connection of the config, and the database connections through it.
+// It's called when the config is no longer used, e.g. the instance is changed or archived.
+func CloseSSHTunnel(config SSHConfig) {
+	if config.IsEmpty() {
+		return
+	}
+	sshTunnels.closeClient(config.key())
+}
+
+// getClient returns the SSH connection of the config, and connects the SSH server if not connected.
+func (m *sshTunnelManager) getClient(config SSHConfig) (*ssh.Client, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := config.key()
+	if client, ok := m.clientMap[key]; ok {
+		return client, nil
+	}
+	client, err := config.dial()
+	if err != nil {
+		return nil, err
+	}
+	m.clientMap[key] = client
+	log.Info("Connected SSH server for the SSH tunnel",
+		zap.String("ssh", config.address()),
+		zap.String("user", config.User),
+	)
+	// The broken SSH connection is removed, so that the next database connection reconnects the SSH server.
+	go func() {
+		_ = client.Wait()
+		m.removeClient(key, client)
+	}()
+	return client, nil
+}
+
+// removeClient removes the SSH connection of the config key if it's still the client.
+func (m *sshTunnelManager) removeClient(key string, client *ssh.Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.clientMap[key] == client {
+		delete(m.clientMap, key)
+	}
+}
+
+// closeClient closes and removes the SSH connection of the config key.
+func (m *sshTunnelManager) closeClient(key string) {
+	m.mu.Lock()
+	client, ok := m.clientMap[key]
+	delete(m.clientMap, key)
+	m.mu.Unlock()
+	if ok {
+		_ = client.Close()
+	}
+}
+
+// dial dials the database address through the SSH connection of the config.
+func (m *sshTunnelManager) dial(config SSHConfig, dbAddress string) (net.Conn, error) {
+	client, err := m.getClient(config)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := client.Dial("tcp", dbAddress)
+	if err == nil {
+		return conn, nil
+	}
+	// The SSH connection may be broken without being noticed, e.g. dropped by the idle timeout of the firewall,
+	// so it's reconnected once.
+	_ = client.Close()
+	m.removeClient(config.key(), client)
+	if client, err = m.getClient(config); err != nil {
+		return nil, err
+	}
+	conn, err = client.Dial("tcp", dbAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect %q through SSH server %q", dbAddress, config.address())
+	}
+	return conn, nil
+}
@@ -0,0 +1,276 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHConfigValidate(t *testing.T) {
+	a := require.New(t)
+	privateKey, _ := generateSSHKey(t)
+	_, hostKey := generateSSHKey(t)
+	authorizedHostKey := string(ssh.MarshalAuthorizedKey(hostKey))
+	tests := []struct {
+		config  SSHConfig
+		wantErr bool
+	}{
+		{
+			config: SSHConfig{},
+		},
+		{
+			config: SSHConfig{Host: "bastion.example.com", User: "bytebase", PrivateKey: privateKey, HostKey: authorizedHostKey},
+		},
+		{
+			config: SSHConfig{Host: "bastion.example.com", Port: "2222", User: "bytebase", PrivateKey: privateKey, HostKey: authorizedHostKey},
+		},
+		{
+			config:  SSHConfig{Host: "bastion.example.com", Port: "ssh", User: "bytebase", PrivateKey: privateKey, HostKey: authorizedHostKey},
+			wantErr: true,
+		},
+		{
+			config:  SSHConfig{User: "bytebase", PrivateKey: privateKey, HostKey: authorizedHostKey},
+			wantErr: true,
+		},
+		{
+			config:  SSHConfig{Host: "bastion.example.com", PrivateKey: privateKey, HostKey: authorizedHostKey},
+			wantErr: true,
+		},
+		{
+			config:  SSHConfig{Host: "bastion.example.com", User: "bytebase", HostKey: authorizedHostKey},
+			wantErr: true,
+		},
+		{
+			config:  SSHConfig{Host: "bastion.example.com", User: "bytebase", PrivateKey: "invalid", HostKey: authorizedHostKey},
+			wantErr: true,
+		},
+		{
+			config: SSHConfig{Host: "bastion.example.com", User: "bytebase", PrivateKey: privateKey, HostKey: ssh.FingerprintSHA256(hostKey)},
+		},
+		// The host key is required, so that the SSH server is verified.
+		{
+			config:  SSHConfig{Host: "bastion.example.com", User: "bytebase", PrivateKey: privateKey},
+			wantErr: true,
+		},
+		{
+			config:  SSHConfig{Host: "bastion.example.com", User: "bytebase", PrivateKey: privateKey, HostKey: "invalid"},
+			wantErr: true,
+		},
+	}
+
+	for i, test := range tests {
+		err := test.config.Validate()
+		if test.wantErr {
+			a.Error(err, i)
+		} else {
+			a.NoError(err, i)
+		}
+	}
+}
+
+func TestOpenSSHTunnel(t *testing.T) {
+	a := require.New(t)
+	privateKey, publicKey := generateSSHKey(t)
+	sshHost, sshPort, hostKey := startTestSSHServer(t, "bytebase", publicKey)
+
+	// The database echoes the data back.
+	dbListener, err := net.Listen("tcp", "127.0.0.1:0")
+	a.NoError(err)
+	defer dbListener.Close()
+	go func() {
+		for {
+			conn, err := dbListener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	dbHost, dbPort, err := net.SplitHostPort(dbListener.Addr().String())
+	a.NoError(err)
+
+	connCfg := ConnectionConfig{
+		Host: dbHost,
+		Port: dbPort,
+		SSHConfig: SSHConfig{
+			Host:       sshHost,
+			Port:       sshPort,
+			User:       "bytebase",
+			PrivateKey: privateKey,
+			HostKey:    ssh.FingerprintSHA256(hostKey),
+		},
+	}
+	tunnelCfg, err := openSSHTunnel(MySQL, connCfg)
+	a.NoError(err)
+	a.True(tunnelCfg.SSHConfig.IsEmpty())
+	a.NotNil(tunnelCfg.Dialer)
+	// The database address is kept, it's dialed through the SSH connection in process without any local listener.
+	a.Equal(dbHost, tunnelCfg.Host)
+	a.Equal(dbPort, tunnelCfg.Port)
+
+	ping := func(conn net.Conn) error {
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			return err
+		}
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return err
+		}
+		if string(buf) != "ping" {
+			return errors.Errorf("unexpected echo %q", buf)
+		}
+		return nil
+	}
+	conn, err := tunnelCfg.Dialer(context.Background(), "")
+	a.NoError(err)
+	defer conn.Close()
+	a.NoError(ping(conn))
+
+	// The SSH connection is reused.
+	client, err := sshTunnels.getClient(connCfg.SSHConfig)
+	a.NoError(err)
+	reusedCfg, err := openSSHTunnel(MySQL, connCfg)
+	a.NoError(err)
+	reusedClient, err := sshTunnels.getClient(connCfg.SSHConfig)
+	a.NoError(err)
+	a.Same(client, reusedClient)
+
+	// Closing the tunnel closes the database connections through it, and the next dial reconnects the SSH server.
+	CloseSSHTunnel(connCfg.SSHConfig)
+	a.Error(ping(conn))
+	reconnected, err := reusedCfg.Dialer(context.Background(), "")
+	a.NoError(err)
+	defer reconnected.Close()
+	a.NoError(ping(reconnected))
+	reconnectedClient, err := sshTunnels.getClient(connCfg.SSHConfig)
+	a.NoError(err)
+	a.NotSame(client, reconnectedClient)
+	CloseSSHTunnel(connCfg.SSHConfig)
+
+	// The unauthorized user fails on opening the tunnel.
+	unauthorizedCfg := connCfg
+	unauthorizedCfg.SSHConfig.User = "guest"
+	_, err = openSSHTunnel(MySQL, unauthorizedCfg)
+	a.Error(err)
+
+	// The SSH server with another host key, e.g. a man-in-the-middle, is rejected before authenticating.
+	_, otherHostKey := generateSSHKey(t)
+	mismatchedCfg := connCfg
+	mismatchedCfg.SSHConfig.HostKey = string(ssh.MarshalAuthorizedKey(otherHostKey))
+	_, err = openSSHTunnel(MySQL, mismatchedCfg)
+	a.ErrorContains(err, "host key")
+
+	_, err = openSSHTunnel(Snowflake, connCfg)
+	a.Error(err)
+
+	// The connection config without SSH config is returned as is.
+	directCfg, err := openSSHTunnel(MySQL, ConnectionConfig{Host: dbHost, Port: dbPort})
+	a.NoError(err)
+	a.Equal(dbPort, directCfg.Port)
+	a.Nil(directCfg.Dialer)
+}
+
+// generateSSHKey returns the PEM encoded private key and its public key.
+func generateSSHKey(t *testing.T) (string, ssh.PublicKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), sshPublicKey
+}
+
+// startTestSSHServer starts an SSH server authenticating the user with the public key and forwarding the TCP connections.
+// It returns the address and the host key of the server.
+func startTestSSHServer(t *testing.T, user string, publicKey ssh.PublicKey) (string, string, ssh.PublicKey) {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == user && bytes.Equal(key.Marshal(), publicKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.Errorf("unauthorized user %q", conn.User())
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config)
+		}
+	}()
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return host, port, hostSigner.PublicKey()
+}
+
+func serveTestSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, channelList, requestList, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requestList)
+	for newChannel := range channelList {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "")
+			continue
+		}
+		var payload struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		remote, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			remote.Close()
+			continue
+		}
+		go ssh.DiscardRequests(requests)
+		go func() {
+			defer channel.Close()
+			defer remote.Close()
+			go func() {
+				_, _ = io.Copy(remote, channel)
+			}()
+			_, _ = io.Copy(channel, remote)
+		}()
+	}
+}
@@ -5,15 +5,30 @@ import (
 	"crypto/x509"
 
 	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
 )
 
 // TLSConfig is the configuration for SSL connection.
+// The fields are the PEM encoded contents rather than the file paths.
 type TLSConfig struct {
 	SslCA   string
 	SslCert string
 	SslKey  string
 }
 
+// Validate validates the TLS config, the client certificate and key are only used along with the CA certificate.
+func (tc TLSConfig) Validate() error {
+	if tc.SslCA == "" {
+		if tc.SslCert != "" || tc.SslKey != "" {
+			return errors.Errorf("ssl-ca must be set when ssl-cert and ssl-key are set")
+		}
+		return nil
+	}
+	_, err := tc.GetSslConfig()
+	return err
+}
+
 // GetSslConfig gets the SSL config for connection.
 func (tc TLSConfig) GetSslConfig() (*tls.Config, error) {
 	if tc.SslCA == "" {
@@ -21,8 +36,10 @@ func (tc TLSConfig) GetSslConfig() (*tls.Config, error) {
 	}
 	rootCertPool := x509.NewCertPool()
 	if ok := rootCertPool.AppendCertsFromPEM([]byte(tc.SslCA)); !ok {
-		return nil, errors.Errorf("rootCertPool.AppendCertsFromPEM() failed to append server CA pem")
+		return nil, errors.Errorf("ssl-ca contains no valid PEM encoded certificate")
 	}
+	// The workspace CA certificates are trusted as well, e.g. the intermediate CA of the server certificate.
+	common.AppendCACertificates(rootCertPool)
 
 	cfg := &tls.Config{
 		RootCAs: rootCertPool,
@@ -34,7 +51,7 @@ func (tc TLSConfig) GetSslConfig() (*tls.Config, error) {
 		var clientCert []tls.Certificate
 		certs, err := tls.X509KeyPair([]byte(tc.SslCert), []byte(tc.SslKey))
 		if err != nil {
-			return nil, err
+			return nil, errors.Wrap(err, "invalid ssl-cert and ssl-key")
 		}
 		clientCert = append(clientCert, certs)
 
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfigValidate(t *testing.T) {
+	a := require.New(t)
+	caPEM, _ := generateTestCertificate(t)
+	certPEM, keyPEM := generateTestCertificate(t)
+	_, otherKeyPEM := generateTestCertificate(t)
+
+	tests := []struct {
+		config  TLSConfig
+		wantErr bool
+	}{
+		{
+			config: TLSConfig{},
+		},
+		{
+			config: TLSConfig{SslCA: caPEM},
+		},
+		{
+			config: TLSConfig{SslCA: caPEM, SslCert: certPEM, SslKey: keyPEM},
+		},
+		{
+			config:  TLSConfig{SslCert: certPEM, SslKey: keyPEM},
+			wantErr: true,
+		},
+		{
+			config:  TLSConfig{SslCA: caPEM, SslCert: certPEM},
+			wantErr: true,
+		},
+		{
+			config:  TLSConfig{SslCA: caPEM, SslCert: certPEM, SslKey: otherKeyPEM},
+			wantErr: true,
+		},
+		{
+			config:  TLSConfig{SslCA: "not a certificate"},
+			wantErr: true,
+		},
+	}
+
+	for i, test := range tests {
+		err := test.config.Validate()
+		if test.wantErr {
+			a.Error(err, i)
+		} else {
+			a.NoError(err, i)
+		}
+	}
+}
+
+// generateTestCertificate returns a PEM encoded self-signed certificate and its private key.
+func generateTestCertificate(t *testing.T) (string, string) {
+	a := require.New(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	a.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "bytebase-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	a.NoError(err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	a.NoError(err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
@@ -0,0 +1,272 @@
+package util
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	liquibaseXMLNS             = "http://www.liquibase.org/xml/ns/dbchangelog"
+	liquibaseXMLNSXSI          = "http://www.w3.org/2001/XMLSchema-instance"
+	liquibaseXSISchemaLocation = "http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-4.9.xsd"
+
+	// flywayMigrationDir is the default location Flyway scans for the migrations.
+	flywayMigrationDir = "db/migration"
+	// flywayMaxDescriptionLength keeps the file names of the exported migrations readable.
+	flywayMaxDescriptionLength = 100
+)
+
+var (
+	flywayDescriptionReplacer  = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	externalHistoryTableRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ExternalMigration is a migration applied by an external migration tool such as Flyway or Liquibase.
+type ExternalMigration struct {
+	// Rank is the order in which the migration was applied.
+	Rank int
+	// Version is the Flyway version or the Liquibase change set ID.
+	Version     string
+	Description string
+	// Script is the Flyway script or the Liquibase changelog file applying the migration.
+	Script    string
+	Creator   string
+	AppliedTs int64
+}
+
+type liquibaseChangelog struct {
+	XMLName        xml.Name              `xml:"databaseChangeLog"`
+	XMLNS          string                `xml:"xmlns,attr"`
+	XMLNSXSI       string                `xml:"xmlns:xsi,attr"`
+	SchemaLocation string                `xml:"xsi:schemaLocation,attr"`
+	ChangeSetList  []*liquibaseChangeSet `xml:"changeSet"`
+}
+
+type liquibaseChangeSet struct {
+	ID      string        `xml:"id,attr"`
+	Author  string        `xml:"author,attr"`
+	Comment string        `xml:"comment,omitempty"`
+	SQL     *liquibaseSQL `xml:"sql"`
+}
+
+type liquibaseSQL struct {
+	SplitStatements bool   `xml:"splitStatements,attr"`
+	StripComments   bool   `xml:"stripComments,attr"`
+	Statement       string `xml:",cdata"`
+}
+
+// ExportLiquibaseChangelog writes the migration history list as a Liquibase XML changelog.
+// Each applied migration becomes a change set identified by its version.
+func ExportLiquibaseChangelog(w io.Writer, historyList []*db.MigrationHistory) error {
+	changelog := &liquibaseChangelog{
+		XMLNS:          liquibaseXMLNS,
+		XMLNSXSI:       liquibaseXMLNSXSI,
+		SchemaLocation: liquibaseXSISchemaLocation,
+	}
+	for _, history := range getExportableMigrationHistoryList(historyList) {
+		changelog.ChangeSetList = append(changelog.ChangeSetList, &liquibaseChangeSet{
+			ID:      history.Version,
+			Author:  history.Creator,
+			Comment: getExportedMigrationComment(history),
+			SQL: &liquibaseSQL{
+				SplitStatements: true,
+				StripComments:   false,
+				Statement:       history.Statement,
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(changelog); err != nil {
+		return errors.Wrap(err, "failed to encode Liquibase changelog")
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ExportFlywayMigrationList writes the migration history list as Flyway versioned migrations in a zip archive.
+// Flyway requires numeric versions, so the migrations are versioned by their sequence and the original version
+// is kept in the description, e.g. db/migration/V3__20220901120000_add_email_column.sql.
+func ExportFlywayMigrationList(w io.Writer, historyList []*db.MigrationHistory) error {
+	zipWriter := zip.NewWriter(w)
+	for _, history := range getExportableMigrationHistoryList(historyList) {
+		name := fmt.Sprintf("%s/V%d__%s.sql", flywayMigrationDir, history.Sequence, getFlywayDescription(history))
+		file, err := zipWriter.Create(name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create file %q in the zip archive", name)
+		}
+		content := history.Statement
+		if comment := getExportedMigrationComment(history); comment != "" {
+			content = fmt.Sprintf("-- %s\n%s", comment, content)
+		}
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if _, err := io.WriteString(file, content); err != nil {
+			return errors.Wrapf(err, "failed to write file %q in the zip archive", name)
+		}
+	}
+	return zipWriter.Close()
+}
+
+// getExportableMigrationHistoryList returns the applied migrations changing the database, ordered by sequence.
+// Baselines only record the existing schema and branches are restored from backups, so neither has a statement to replay.
+func getExportableMigrationHistoryList(historyList []*db.MigrationHistory) []*db.MigrationHistory {
+	var result []*db.MigrationHistory
+	for _, history := range historyList {
+		if history.Status != db.Done {
+			continue
+		}
+		if history.Type == db.Baseline || history.Type == db.Branch {
+			continue
+		}
+		if strings.TrimSpace(history.Statement) == "" {
+			continue
+		}
+		result = append(result, history)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Sequence < result[j].Sequence
+	})
+	return result
+}
+
+func getExportedMigrationComment(history *db.MigrationHistory) string {
+	comment := strings.Join(strings.Fields(history.Description), " ")
+	if history.IssueID != "" {
+		comment = strings.TrimSpace(fmt.Sprintf("%s (issue %s)", comment, history.IssueID))
+	}
+	return comment
+}
+
+func getFlywayDescription(history *db.MigrationHistory) string {
+	description := flywayDescriptionReplacer.ReplaceAllString(fmt.Sprintf("%s %s", history.Version, history.Description), "_")
+	description = strings.Trim(description, "_")
+	if len(description) > flywayMaxDescriptionLength {
+		description = strings.TrimRight(description[:flywayMaxDescriptionLength], "_")
+	}
+	return description
+}
+
+// FindFlywayMigrationList finds the successful versioned migrations in the Flyway schema history table, ordered by rank.
+// The repeatable migrations have no version and the undo or deleted entries don't change the schema forward, so they're skipped.
+func FindFlywayMigrationList(ctx context.Context, sqldb *sql.DB, engine db.Type, table string) ([]*ExternalMigration, error) {
+	appliedTsExpr, err := getExternalHistoryTimestampExpr(engine, "installed_on")
+	if err != nil {
+		return nil, err
+	}
+	if !externalHistoryTableRegexp.MatchString(table) {
+		return nil, errors.Errorf("invalid Flyway schema history table name %q", table)
+	}
+	query := fmt.Sprintf(`
+		SELECT installed_rank, version, description, type, script, installed_by, %s
+		FROM %s
+		WHERE success AND version IS NOT NULL
+		ORDER BY installed_rank`, appliedTsExpr, table)
+	rows, err := sqldb.QueryContext(ctx, query)
+	if err != nil {
+		return nil, FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var migrationList []*ExternalMigration
+	for rows.Next() {
+		var migration ExternalMigration
+		var migrationType string
+		var creator sql.NullString
+		if err := rows.Scan(
+			&migration.Rank,
+			&migration.Version,
+			&migration.Description,
+			&migrationType,
+			&migration.Script,
+			&creator,
+			&migration.AppliedTs,
+		); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(migrationType, "UNDO_") || migrationType == "DELETE" {
+			continue
+		}
+		migration.Creator = creator.String
+		migrationList = append(migrationList, &migration)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return migrationList, nil
+}
+
+// FindLiquibaseMigrationList finds the applied change sets in the Liquibase changelog table, ordered by execution.
+func FindLiquibaseMigrationList(ctx context.Context, sqldb *sql.DB, engine db.Type, table string) ([]*ExternalMigration, error) {
+	appliedTsExpr, err := getExternalHistoryTimestampExpr(engine, "DATEEXECUTED")
+	if err != nil {
+		return nil, err
+	}
+	if !externalHistoryTableRegexp.MatchString(table) {
+		return nil, errors.Errorf("invalid Liquibase changelog table name %q", table)
+	}
+	query := fmt.Sprintf(`
+		SELECT ORDEREXECUTED, ID, AUTHOR, FILENAME, DESCRIPTION, COMMENTS, %s
+		FROM %s
+		WHERE EXECTYPE IN ('EXECUTED', 'RERAN', 'MARK_RAN')
+		ORDER BY ORDEREXECUTED`, appliedTsExpr, table)
+	rows, err := sqldb.QueryContext(ctx, query)
+	if err != nil {
+		return nil, FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var migrationList []*ExternalMigration
+	for rows.Next() {
+		var migration ExternalMigration
+		var description, comments sql.NullString
+		if err := rows.Scan(
+			&migration.Rank,
+			&migration.Version,
+			&migration.Creator,
+			&migration.Script,
+			&description,
+			&comments,
+			&migration.AppliedTs,
+		); err != nil {
+			return nil, err
+		}
+		// The comments are written by the author, so they're preferred to the generated description.
+		migration.Description = comments.String
+		if migration.Description == "" {
+			migration.Description = description.String
+		}
+		migrationList = append(migrationList, &migration)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return migrationList, nil
+}
+
+func getExternalHistoryTimestampExpr(engine db.Type, column string) (string, error) {
+	switch engine {
+	case db.MySQL, db.TiDB:
+		return fmt.Sprintf("CAST(UNIX_TIMESTAMP(%s) AS SIGNED)", column), nil
+	case db.Postgres:
+		return fmt.Sprintf("CAST(EXTRACT(EPOCH FROM %s) AS BIGINT)", column), nil
+	default:
+		return "", errors.Errorf("importing the migration history of other migration tools is not supported for %s", engine)
+	}
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+var changelogTestHistoryList = []*db.MigrationHistory{
+	{Sequence: 3, Creator: "bob", Type: db.Data, Status: db.Done, Version: "20220902000000", Description: "Backfill ]]> names", Statement: "UPDATE t SET name = 'a';"},
+	{Sequence: 2, Creator: "alice", Type: db.Migrate, Status: db.Done, Version: "20220901000000", Description: "Add name column", Statement: "ALTER TABLE t ADD COLUMN name TEXT;", IssueID: "101"},
+	{Sequence: 1, Creator: "alice", Type: db.Baseline, Status: db.Done, Version: "20220831000000", Description: "Establish baseline"},
+	{Sequence: 4, Creator: "bob", Type: db.Migrate, Status: db.Failed, Version: "20220903000000", Description: "Failed", Statement: "ALTER TABLE t DROP COLUMN x;"},
+}
+
+func TestExportLiquibaseChangelog(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, ExportLiquibaseChangelog(&buf, changelogTestHistoryList))
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<databaseChangeLog xmlns="http://www.liquibase.org/xml/ns/dbchangelog" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-4.9.xsd">
+  <changeSet id="20220901000000" author="alice">
+    <comment>Add name column (issue 101)</comment>
+    <sql splitStatements="true" stripComments="false"><![CDATA[ALTER TABLE t ADD COLUMN name TEXT;]]></sql>
+  </changeSet>
+  <changeSet id="20220902000000" author="bob">
+    <comment>Backfill ]]&gt; names</comment>
+    <sql splitStatements="true" stripComments="false"><![CDATA[UPDATE t SET name = 'a';]]></sql>
+  </changeSet>
+</databaseChangeLog>
+`
+	require.Equal(t, want, buf.String())
+}
+
+func TestExportFlywayMigrationList(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, ExportFlywayMigrationList(&buf, changelogTestHistoryList))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	got := make(map[string]string)
+	var nameList []string
+	for _, file := range reader.File {
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		f.Close()
+		got[file.Name] = string(content)
+		nameList = append(nameList, file.Name)
+	}
+	require.Equal(t, []string{
+		"db/migration/V2__20220901000000_Add_name_column.sql",
+		"db/migration/V3__20220902000000_Backfill_names.sql",
+	}, nameList)
+	require.Equal(t, "-- Add name column (issue 101)\nALTER TABLE t ADD COLUMN name TEXT;\n", got["db/migration/V2__20220901000000_Add_name_column.sql"])
+	require.Equal(t, "-- Backfill ]]> names\nUPDATE t SET name = 'a';\n", got["db/migration/V3__20220902000000_Backfill_names.sql"])
+}
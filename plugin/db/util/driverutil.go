@@ -5,11 +5,13 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha1"
 	"database/sql"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/blang/semver/v4"
@@ -21,6 +23,15 @@ import (
 	"github.com/bytebase/bytebase/plugin/db"
 )
 
+const (
+	// cancelQueryTimeout is the timeout for canceling the running query.
+	cancelQueryTimeout = 10 * time.Second
+	// endMigrationTimeout is the timeout for updating the migration history of the canceled migration.
+	endMigrationTimeout = 10 * time.Second
+	// releaseMigrationLockTimeout is the timeout for releasing the migration lock of the canceled migration.
+	releaseMigrationLockTimeout = 10 * time.Second
+)
+
 // FormatErrorWithQuery will format the error with failed query.
 func FormatErrorWithQuery(err error, query string) error {
 	return common.Wrapf(err, common.DbExecutionError, "failed to execute query %q", query)
@@ -129,9 +140,37 @@ type MigrationExecutor interface {
 	UpdateHistoryAsFailed(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, insertedID int64) error
 }
 
+// MigrationLocker is implemented by the drivers supporting the advisory lock serializing the migrations of a database.
+type MigrationLocker interface {
+	// LockMigration blocks until the advisory lock of the database is acquired or ctx is done.
+	// It returns the function releasing the lock.
+	LockMigration(ctx context.Context, database string) (func(), error)
+}
+
+// GetMigrationLockName returns the name of the advisory lock serializing the migrations of the database.
+// The external tools can take the same lock to avoid interleaving with the migrations applied by Bytebase.
+// The name is at most 64 characters as limited by MySQL, so the long database name is replaced by its SHA-1 digest.
+func GetMigrationLockName(database string) string {
+	const prefix = "bytebase_migration_"
+	if len(prefix)+len(database) > 64 {
+		return fmt.Sprintf("%s%x", prefix, sha1.Sum([]byte(database)))
+	}
+	return prefix + database
+}
+
 // ExecuteMigration will execute the database migration.
 // Returns the created migration history id and the updated schema on success.
 func ExecuteMigration(ctx context.Context, executor MigrationExecutor, m *db.MigrationInfo, statement string, databaseName string) (migrationHistoryID int64, updatedSchema string, resErr error) {
+	// Serialize the migrations of the database, so that the schema dumped before and after the migration
+	// isn't interleaved with the concurrent migrations. The database to be created doesn't have anything to race on.
+	if locker, ok := executor.(MigrationLocker); ok && !m.CreateDatabase {
+		unlock, err := locker.LockMigration(ctx, m.Database)
+		if err != nil {
+			return -1, "", errors.Wrapf(err, "failed to acquire the migration lock of database %q", m.Database)
+		}
+		defer unlock()
+	}
+
 	var prevSchemaBuf bytes.Buffer
 	// Don't record schema if the database hasn't exist yet.
 	if !m.CreateDatabase {
@@ -155,7 +194,14 @@ func ExecuteMigration(ctx context.Context, executor MigrationExecutor, m *db.Mig
 	startedNs := time.Now().UnixNano()
 
 	defer func() {
-		if err := EndMigration(ctx, executor, startedNs, insertedID, updatedSchema, databaseName, resErr == nil /*isDone*/); err != nil {
+		// The migration history is updated even if the migration is canceled, otherwise it's left PENDING and blocks the retry.
+		endCtx := ctx
+		if ctx.Err() != nil {
+			var cancel context.CancelFunc
+			endCtx, cancel = context.WithTimeout(context.Background(), endMigrationTimeout)
+			defer cancel()
+		}
+		if err := EndMigration(endCtx, executor, startedNs, insertedID, updatedSchema, databaseName, resErr == nil /*isDone*/); err != nil {
 			log.Error("Failed to update migration history record",
 				zap.Error(err),
 				zap.Int64("migration_id", migrationHistoryID),
@@ -181,8 +227,23 @@ func ExecuteMigration(ctx context.Context, executor MigrationExecutor, m *db.Mig
 				return -1, "", err
 			}
 		}
-		if err := executor.Execute(ctx, statement); err != nil {
-			return -1, "", FormatError(err)
+		if m.Type == db.Data && (m.StatementLogger != nil || m.MaxAffectedRows > 0 || m.ShouldPause != nil) {
+			if err := executeStatementsWithLog(ctx, executor, m, statement); err != nil {
+				return -1, "", FormatError(err)
+			}
+		} else if m.ShouldPause != nil {
+			if err := executeStatementsOneByOne(ctx, executor, m, statement); err != nil {
+				return -1, "", FormatError(err)
+			}
+		} else {
+			// The statement is executed as a whole, so the failed statement is unknown and m.StatementLogger isn't called.
+			resolvedStatement, err := resolveStatement(m, statement)
+			if err != nil {
+				return -1, "", err
+			}
+			if err := executor.Execute(ctx, resolvedStatement); err != nil {
+				return -1, "", FormatError(err)
+			}
 		}
 	}
 
@@ -195,6 +256,250 @@ func ExecuteMigration(ctx context.Context, executor MigrationExecutor, m *db.Mig
 	return insertedID, afterSchemaBuf.String(), nil
 }
 
+// executeStatementsWithLog executes the statements one by one in a single transaction and reports each statement to m.StatementLogger.
+// The transaction is rolled back if the total affected rows exceed m.MaxAffectedRows.
+// If the migration is paused, the transaction is committed with the executed statements.
+func executeStatementsWithLog(ctx context.Context, executor MigrationExecutor, m *db.MigrationInfo, statement string) error {
+	stmtList, err := splitStatements(statement)
+	if err != nil {
+		return err
+	}
+
+	sqldb, err := executor.GetDBConnection(ctx, m.Database)
+	if err != nil {
+		return err
+	}
+	// Pin a single connection so that session states are kept across statements.
+	conn, err := sqldb.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if canceler, ok := executor.(QueryCanceler); ok {
+		stop, err := CancelQueryOnDone(ctx, canceler, conn)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	logStatement := func(stmtLog *db.StatementLog) {
+		if m.StatementLogger != nil {
+			m.StatementLogger(stmtLog)
+		}
+	}
+	var totalRowsAffected int64
+	for i, stmt := range stmtList {
+		if i < m.ExecutedStatementCount {
+			continue
+		}
+		if shouldPause(m, i) {
+			if err := tx.Commit(); err != nil {
+				return err
+			}
+			return pausedError(i, len(stmtList))
+		}
+		stmtLog := &db.StatementLog{
+			Index:     i + 1,
+			Count:     len(stmtList),
+			Statement: stmt,
+		}
+		resolvedStmt, err := resolveStatement(m, stmt)
+		if err != nil {
+			return err
+		}
+		startedNs := time.Now().UnixNano()
+		result, err := tx.ExecContext(ctx, resolvedStmt)
+		stmtLog.DurationNs = time.Now().UnixNano() - startedNs
+		if err != nil {
+			stmtLog.Error = err.Error()
+			logStatement(stmtLog)
+			return FormatErrorWithQuery(err, stmt)
+		}
+		// Not all drivers support RowsAffected, so we just ignore the error.
+		if rowsAffected, err := result.RowsAffected(); err == nil {
+			stmtLog.RowsAffected = rowsAffected
+			totalRowsAffected += rowsAffected
+		}
+		if m.MaxAffectedRows > 0 && totalRowsAffected > m.MaxAffectedRows {
+			stmtLog.Error = fmt.Sprintf("affected %d rows in total, exceeding the limit %d", totalRowsAffected, m.MaxAffectedRows)
+			logStatement(stmtLog)
+			return errors.Errorf("statements affected %d rows in total, exceeding the limit %d, rolled back", totalRowsAffected, m.MaxAffectedRows)
+		}
+		logStatement(stmtLog)
+	}
+
+	return tx.Commit()
+}
+
+// executeStatementsOneByOne executes the statements one by one, each in its own transaction, so that the migration can be paused
+// at the statement boundaries. Each statement is reported to m.StatementLogger.
+func executeStatementsOneByOne(ctx context.Context, executor MigrationExecutor, m *db.MigrationInfo, statement string) error {
+	stmtList, err := splitStatements(statement)
+	if err != nil {
+		return err
+	}
+
+	for i, stmt := range stmtList {
+		if i < m.ExecutedStatementCount {
+			continue
+		}
+		if shouldPause(m, i) {
+			return pausedError(i, len(stmtList))
+		}
+		stmtLog := &db.StatementLog{
+			Index:     i + 1,
+			Count:     len(stmtList),
+			Statement: stmt,
+		}
+		resolvedStmt, err := resolveStatement(m, stmt)
+		if err != nil {
+			return err
+		}
+		startedNs := time.Now().UnixNano()
+		err = executor.Execute(ctx, resolvedStmt)
+		stmtLog.DurationNs = time.Now().UnixNano() - startedNs
+		if err != nil {
+			stmtLog.Error = err.Error()
+		}
+		if m.StatementLogger != nil {
+			m.StatementLogger(stmtLog)
+		}
+		if err != nil {
+			return FormatErrorWithQuery(err, stmt)
+		}
+	}
+	return nil
+}
+
+// QueryCanceler is implemented by the drivers that can cancel the query running on a backend connection from another connection.
+type QueryCanceler interface {
+	// GetBackendID returns the ID of the backend connection conn.
+	GetBackendID(ctx context.Context, conn *sql.Conn) (int64, error)
+	// CancelQuery cancels the query running on the backend connection with backendID.
+	CancelQuery(ctx context.Context, backendID int64) error
+}
+
+// ResetIdleConnections executes the statements resetting the session state on each idle connection of sqldb, so that the state
+// left on them by the previous user, e.g. USE db or SET FOREIGN_KEY_CHECKS=0, doesn't leak to the next user of the same sqldb.
+// The connections are kept open for reuse. It's called when the driver is returned for reuse, so no connection is expected to be in use.
+func ResetIdleConnections(ctx context.Context, sqldb *sql.DB, stmtList ...string) error {
+	// The connections are held until all are reset, so that each idle connection is taken once.
+	var connList []*sql.Conn
+	defer func() {
+		for _, conn := range connList {
+			conn.Close()
+		}
+	}()
+	idle := sqldb.Stats().Idle
+	for i := 0; i < idle; i++ {
+		conn, err := sqldb.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		connList = append(connList, conn)
+		for _, stmt := range stmtList {
+			if _, err := conn.ExecContext(ctx, stmt); err != nil {
+				return FormatErrorWithQuery(err, stmt)
+			}
+		}
+	}
+	return nil
+}
+
+// CancelQueryOnDone cancels the query running on conn once ctx is done. Canceling ctx only closes the client side
+// of the connection, and the query keeps running on the database server, e.g. the long-running DDL holding the metadata lock.
+// The returned stop function must be called before conn is released.
+func CancelQueryOnDone(ctx context.Context, canceler QueryCanceler, conn *sql.Conn) (func(), error) {
+	backendID, err := canceler.GetBackendID(ctx, conn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the backend connection ID")
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-ctx.Done():
+			// ctx is done already, so the query is canceled with a new context.
+			cancelCtx, cancel := context.WithTimeout(context.Background(), cancelQueryTimeout)
+			defer cancel()
+			if err := canceler.CancelQuery(cancelCtx, backendID); err != nil {
+				log.Warn("Failed to cancel the query", zap.Int64("backend_id", backendID), zap.Error(err))
+			}
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		// Wait for the cancellation in flight, so that it won't hit the next user of the connection.
+		wg.Wait()
+	}, nil
+}
+
+// LockMigrationOnConn acquires the advisory lock on conn with lock, and returns the function releasing it with unlock.
+// The lock is held by the session, so conn is closed after the release.
+// The query waiting for the lock is canceled on the server once ctx is done, if the executor is a QueryCanceler.
+func LockMigrationOnConn(ctx context.Context, executor interface{}, conn *sql.Conn, lock func(context.Context) error, unlock func(context.Context) error) (func(), error) {
+	acquire := func() error {
+		if canceler, ok := executor.(QueryCanceler); ok {
+			stop, err := CancelQueryOnDone(ctx, canceler, conn)
+			if err != nil {
+				return err
+			}
+			defer stop()
+		}
+		return lock(ctx)
+	}
+	if err := acquire(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return func() {
+		// The lock is released even if the migration is canceled, otherwise it's held until the connection is closed.
+		releaseCtx, cancel := context.WithTimeout(context.Background(), releaseMigrationLockTimeout)
+		defer cancel()
+		if err := unlock(releaseCtx); err != nil {
+			log.Warn("Failed to release the migration lock", zap.Error(err))
+		}
+		conn.Close()
+	}, nil
+}
+
+func splitStatements(statement string) ([]string, error) {
+	var stmtList []string
+	if err := ApplyMultiStatements(strings.NewReader(statement), func(stmt string) error {
+		stmtList = append(stmtList, stmt)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return stmtList, nil
+}
+
+// shouldPause returns true if the migration should be paused before the i-th (0-based) statement.
+// The migration isn't paused before the first statement of the run, so that the resumed migration always makes progress.
+func shouldPause(m *db.MigrationInfo, i int) bool {
+	return m.ShouldPause != nil && i > m.ExecutedStatementCount && m.ShouldPause()
+}
+
+func pausedError(executedCount int, count int) error {
+	return common.Errorf(common.MigrationPaused, "migration paused after executing %d of %d statements", executedCount, count)
+}
+
+func resolveStatement(m *db.MigrationInfo, statement string) (string, error) {
+	if m.ResolveStatement == nil {
+		return statement, nil
+	}
+	return m.ResolveStatement(statement)
+}
+
 // BeginMigration checks before executing migration and inserts a migration history record with pending status.
 func BeginMigration(ctx context.Context, executor MigrationExecutor, m *db.MigrationInfo, prevSchema string, statement string, databaseName string) (insertedID int64, err error) {
 	// Convert version to stored version.
@@ -302,7 +607,7 @@ func EndMigration(ctx context.Context, executor MigrationExecutor, startedNs int
 }
 
 // Query will execute a readonly / SELECT query.
-func Query(ctx context.Context, sqldb *sql.DB, statement string, limit int) ([]interface{}, error) {
+func Query(ctx context.Context, sqldb *sql.DB, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
 	// Not all sql engines support ReadOnly flag, so we will use tx rollback semantics to enforce readonly.
 	tx, err := sqldb.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
@@ -326,8 +631,6 @@ func Query(ctx context.Context, sqldb *sql.DB, statement string, limit int) ([]i
 		return nil, FormatError(err)
 	}
 
-	colCount := len(columnTypes)
-
 	var columnTypeNames []string
 	for _, v := range columnTypes {
 		// DatabaseTypeName returns the database system name of the column type.
@@ -336,58 +639,21 @@ func Query(ctx context.Context, sqldb *sql.DB, statement string, limit int) ([]i
 	}
 
 	rowCount := 0
+	var rowSize int64
 	data := []interface{}{}
 	for rows.Next() {
-		scanArgs := make([]interface{}, colCount)
-		for i, v := range columnTypeNames {
-			// TODO(steven need help): Consult a common list of data types from database driver documentation. e.g. MySQL,PostgreSQL.
-			switch v {
-			case "VARCHAR", "TEXT", "UUID", "TIMESTAMP":
-				scanArgs[i] = new(sql.NullString)
-			case "BOOL":
-				scanArgs[i] = new(sql.NullBool)
-			case "INT", "INTEGER":
-				scanArgs[i] = new(sql.NullInt64)
-			case "FLOAT":
-				scanArgs[i] = new(sql.NullFloat64)
-			default:
-				scanArgs[i] = new(sql.NullString)
-			}
-		}
-
-		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, FormatError(err)
+		rowData, err := scanRow(rows, columnTypeNames)
+		if err != nil {
+			return nil, err
 		}
 
-		rowData := []interface{}{}
-		for i := range columnTypes {
-			if v, ok := (scanArgs[i]).(*sql.NullBool); ok && v.Valid {
-				rowData = append(rowData, v.Bool)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullString); ok && v.Valid {
-				rowData = append(rowData, v.String)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt64); ok && v.Valid {
-				rowData = append(rowData, v.Int64)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt32); ok && v.Valid {
-				rowData = append(rowData, v.Int32)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullFloat64); ok && v.Valid {
-				rowData = append(rowData, v.Float64)
-				continue
-			}
-			// If none of them match, set nil to its value.
-			rowData = append(rowData, nil)
+		rowSize += getRowSize(rowData)
+		if queryContext.MaxBytes > 0 && rowSize > queryContext.MaxBytes {
+			return nil, common.Errorf(common.DbQueryResultTooLarge, "the query result exceeds the maximum size of %d bytes after %d rows, please add a LIMIT or select fewer columns", queryContext.MaxBytes, rowCount)
 		}
-
 		data = append(data, rowData)
 		rowCount++
-		if rowCount == limit {
+		if rowCount == queryContext.Limit {
 			break
 		}
 	}
@@ -398,6 +664,127 @@ func Query(ctx context.Context, sqldb *sql.DB, statement string, limit int) ([]i
 	return []interface{}{columnNames, columnTypeNames, data}, nil
 }
 
+// StreamQuery executes a readonly / SELECT query like Query, but calls rowFunc for each row instead of keeping the rows in memory,
+// e.g. for exporting the large query result. columnFunc is called with the column names before the first row.
+// At most limit rows are read, not enforced if limit <= 0. It returns the number of the rows read.
+func StreamQuery(ctx context.Context, sqldb *sql.DB, statement string, limit int, columnFunc func(columnNames []string) error, rowFunc func(rowData []interface{}) error) (int64, error) {
+	// Not all sql engines support ReadOnly flag, so we will use tx rollback semantics to enforce readonly.
+	tx, err := sqldb.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, statement)
+	if err != nil {
+		return 0, FormatErrorWithQuery(err, statement)
+	}
+	defer rows.Close()
+
+	columnNames, err := rows.Columns()
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	var columnTypeNames []string
+	for _, v := range columnTypes {
+		columnTypeNames = append(columnTypeNames, strings.ToUpper(v.DatabaseTypeName()))
+	}
+	if err := columnFunc(columnNames); err != nil {
+		return 0, err
+	}
+
+	var rowCount int64
+	for rows.Next() {
+		rowData, err := scanRow(rows, columnTypeNames)
+		if err != nil {
+			return rowCount, err
+		}
+		if err := rowFunc(rowData); err != nil {
+			return rowCount, err
+		}
+		rowCount++
+		if rowCount == int64(limit) {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return rowCount, err
+	}
+	return rowCount, nil
+}
+
+// scanRow scans the current row into the values by the column types, the NULL values are nil.
+func scanRow(rows *sql.Rows, columnTypeNames []string) ([]interface{}, error) {
+	scanArgs := make([]interface{}, len(columnTypeNames))
+	for i, v := range columnTypeNames {
+		// TODO(steven need help): Consult a common list of data types from database driver documentation. e.g. MySQL,PostgreSQL.
+		switch v {
+		case "VARCHAR", "TEXT", "UUID", "TIMESTAMP":
+			scanArgs[i] = new(sql.NullString)
+		case "BOOL":
+			scanArgs[i] = new(sql.NullBool)
+		case "INT", "INTEGER":
+			scanArgs[i] = new(sql.NullInt64)
+		case "FLOAT":
+			scanArgs[i] = new(sql.NullFloat64)
+		default:
+			scanArgs[i] = new(sql.NullString)
+		}
+	}
+
+	if err := rows.Scan(scanArgs...); err != nil {
+		return nil, FormatError(err)
+	}
+
+	rowData := []interface{}{}
+	for i := range columnTypeNames {
+		if v, ok := (scanArgs[i]).(*sql.NullBool); ok && v.Valid {
+			rowData = append(rowData, v.Bool)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullString); ok && v.Valid {
+			rowData = append(rowData, v.String)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullInt64); ok && v.Valid {
+			rowData = append(rowData, v.Int64)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullInt32); ok && v.Valid {
+			rowData = append(rowData, v.Int32)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullFloat64); ok && v.Valid {
+			rowData = append(rowData, v.Float64)
+			continue
+		}
+		// If none of them match, set nil to its value.
+		rowData = append(rowData, nil)
+	}
+	return rowData, nil
+}
+
+// getRowSize estimates the size of a row in bytes.
+func getRowSize(rowData []interface{}) int64 {
+	var size int64
+	for _, v := range rowData {
+		switch v := v.(type) {
+		case string:
+			size += int64(len(v))
+		case bool:
+			size++
+		case nil:
+		default:
+			size += 8
+		}
+	}
+	return size
+}
+
 // FindMigrationHistoryList will find the list of migration history.
 func FindMigrationHistoryList(ctx context.Context, findMigrationHistoryListQuery string, queryParams []interface{}, driver db.Driver, database string) ([]*db.MigrationHistory, error) {
 	// To support `pg` option, the util layer will not know which database where `migration_history` table is,
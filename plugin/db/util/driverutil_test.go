@@ -1,9 +1,17 @@
 package util
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func TestToStoredVersion(t *testing.T) {
@@ -68,3 +76,174 @@ func TestFromStoredVersion(t *testing.T) {
 		require.Equal(t, tc.wantSemanticVersionSuffix, gotSemanticVersionSuffix)
 	}
 }
+
+func TestShouldPause(t *testing.T) {
+	type test struct {
+		executedStatementCount int
+		index                  int
+		pauseRequested         bool
+		want                   bool
+	}
+	tests := []test{
+		{0, 0, true, false},
+		{0, 1, true, true},
+		{0, 1, false, false},
+		{2, 2, true, false},
+		{2, 3, true, true},
+	}
+	for _, tc := range tests {
+		pauseRequested := tc.pauseRequested
+		m := &db.MigrationInfo{
+			ExecutedStatementCount: tc.executedStatementCount,
+			ShouldPause: func() bool {
+				return pauseRequested
+			},
+		}
+		require.Equal(t, tc.want, shouldPause(m, tc.index))
+	}
+	require.False(t, shouldPause(&db.MigrationInfo{}, 1))
+}
+
+type fakeQueryCanceler struct {
+	canceled chan int64
+}
+
+func (*fakeQueryCanceler) GetBackendID(_ context.Context, _ *sql.Conn) (int64, error) {
+	return 42, nil
+}
+
+func (c *fakeQueryCanceler) CancelQuery(_ context.Context, backendID int64) error {
+	c.canceled <- backendID
+	return nil
+}
+
+func TestCancelQueryOnDone(t *testing.T) {
+	canceler := &fakeQueryCanceler{canceled: make(chan int64, 1)}
+	stop, err := CancelQueryOnDone(context.Background(), canceler, nil)
+	require.NoError(t, err)
+	stop()
+	require.Empty(t, canceler.canceled)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stop, err = CancelQueryOnDone(ctx, canceler, nil)
+	require.NoError(t, err)
+	cancel()
+	select {
+	case backendID := <-canceler.canceled:
+		require.Equal(t, int64(42), backendID)
+	case <-time.After(time.Second):
+		t.Fatal("the query is not canceled")
+	}
+	stop()
+}
+
+func TestGetMigrationLockName(t *testing.T) {
+	require.Equal(t, "bytebase_migration_shop", GetMigrationLockName("shop"))
+
+	// The long database name is replaced by its SHA-1 digest within the 64 characters limit.
+	name := GetMigrationLockName(strings.Repeat("a", 64))
+	require.Equal(t, "bytebase_migration_0098ba824b5c16427bd7a1122a5a442a25ec644d", name)
+	require.LessOrEqual(t, len(name), 64)
+	require.Len(t, GetMigrationLockName(strings.Repeat("a", 45)), 64)
+}
+
+// fakeSessionDriver is a database/sql driver whose connections keep the session variable set by "SET <value>"
+// until "RESET ALL", and return it for any query, like the session state of the database.
+type fakeSessionDriver struct{}
+
+func init() {
+	sql.Register("fake-session", fakeSessionDriver{})
+}
+
+type fakeSessionConn struct {
+	session string
+}
+
+type fakeSessionRows struct {
+	value string
+	done  bool
+}
+
+func (fakeSessionDriver) Open(string) (driver.Conn, error) {
+	return &fakeSessionConn{}, nil
+}
+
+func (*fakeSessionConn) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (*fakeSessionConn) Close() error {
+	return nil
+}
+
+func (*fakeSessionConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *fakeSessionConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if query == "RESET ALL" {
+		c.session = ""
+		return driver.RowsAffected(0), nil
+	}
+	c.session = strings.TrimPrefix(query, "SET ")
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeSessionConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &fakeSessionRows{value: c.session}, nil
+}
+
+func (*fakeSessionRows) Columns() []string {
+	return []string{"session"}
+}
+
+func (*fakeSessionRows) Close() error {
+	return nil
+}
+
+func (r *fakeSessionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func TestResetIdleConnections(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	sqldb, err := sql.Open("fake-session", "")
+	a.NoError(err)
+	defer sqldb.Close()
+
+	// Leave the session state on two idle connections.
+	conn1, err := sqldb.Conn(ctx)
+	a.NoError(err)
+	conn2, err := sqldb.Conn(ctx)
+	a.NoError(err)
+	_, err = conn1.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS=0")
+	a.NoError(err)
+	_, err = conn2.ExecContext(ctx, "SET sql_mode=''")
+	a.NoError(err)
+	a.NoError(conn1.Close())
+	a.NoError(conn2.Close())
+
+	a.NoError(ResetIdleConnections(ctx, sqldb, "RESET ALL"))
+	a.Equal(2, sqldb.Stats().Idle)
+
+	// The connections are reused without the session state.
+	conn1, err = sqldb.Conn(ctx)
+	a.NoError(err)
+	defer conn1.Close()
+	conn2, err = sqldb.Conn(ctx)
+	a.NoError(err)
+	defer conn2.Close()
+	for _, conn := range []*sql.Conn{conn1, conn2} {
+		var session string
+		a.NoError(conn.QueryRowContext(ctx, "SELECT @@session").Scan(&session))
+		a.Equal("", session)
+	}
+	a.Equal(2, sqldb.Stats().OpenConnections)
+	a.Equal(int64(0), sqldb.Stats().MaxIdleClosed)
+}
@@ -0,0 +1,97 @@
+// Package mail provides the client sending the HTML emails via SMTP.
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Config is the SMTP server configuration.
+type Config struct {
+	Host string
+	Port int
+	// Username and Password are used for the PLAIN authentication, the authentication is skipped if Username is empty.
+	Username string
+	Password string
+	// From is the sender address, e.g. "Bytebase <bytebase@example.com>".
+	From string
+}
+
+// Message is the HTML email message.
+type Message struct {
+	To       []string
+	Subject  string
+	HTMLBody string
+}
+
+// Validate validates the SMTP server configuration.
+func (c *Config) Validate() error {
+	if c.Host == "" {
+		return errors.Errorf("host is required")
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		return errors.Errorf("invalid port %d", c.Port)
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return errors.Wrapf(err, "invalid from address %q", c.From)
+	}
+	return nil
+}
+
+// Send sends the message via the SMTP server.
+// The connection is upgraded with STARTTLS if the server supports it.
+func Send(config *Config, message *Message) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if len(message.To) == 0 {
+		return errors.Errorf("no recipient")
+	}
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return errors.Wrapf(err, "invalid from address %q", config.From)
+	}
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	if err := smtp.SendMail(addr, auth, from.Address, message.To, buildMessage(config.From, message, time.Now())); err != nil {
+		return errors.Wrapf(err, "failed to send email to %v via %s", message.To, addr)
+	}
+	return nil
+}
+
+// buildMessage builds the MIME message with the base64 encoded HTML body.
+func buildMessage(from string, message *Message, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(message.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	// RFC 2045 limits the encoded lines to 76 characters.
+	encoded := base64.StdEncoding.EncodeToString([]byte(message.HTMLBody))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
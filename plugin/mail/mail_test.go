@@ -0,0 +1,74 @@
+package mail
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		config  Config
+		wantErr bool
+	}{
+		{
+			config: Config{Host: "smtp.example.com", Port: 587, From: "Bytebase <bytebase@example.com>"},
+		},
+		{
+			config:  Config{Port: 587, From: "bytebase@example.com"},
+			wantErr: true,
+		},
+		{
+			config:  Config{Host: "smtp.example.com", Port: 0, From: "bytebase@example.com"},
+			wantErr: true,
+		},
+		{
+			config:  Config{Host: "smtp.example.com", Port: 587, From: "bytebase"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := test.config.Validate()
+		if test.wantErr {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	body := strings.Repeat("<p>Weekly report</p>", 10)
+	date := time.Date(2022, 9, 26, 8, 0, 0, 0, time.UTC)
+	got := string(buildMessage("bytebase@example.com", &Message{
+		To:       []string{"alice@example.com", "bob@example.com"},
+		Subject:  "Weekly report",
+		HTMLBody: body,
+	}, date))
+
+	parts := strings.SplitN(got, "\r\n\r\n", 2)
+	require.Len(t, parts, 2)
+	header, encoded := parts[0], parts[1]
+	assert.Equal(t, strings.Join([]string{
+		"From: bytebase@example.com",
+		"To: alice@example.com, bob@example.com",
+		"Subject: Weekly report",
+		"Date: Mon, 26 Sep 2022 08:00:00 +0000",
+		"MIME-Version: 1.0",
+		`Content-Type: text/html; charset="utf-8"`,
+		"Content-Transfer-Encoding: base64",
+	}, "\r\n"), header)
+
+	lines := strings.Split(strings.TrimSuffix(encoded, "\r\n"), "\r\n")
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
@@ -0,0 +1,137 @@
+// Package endpoint implements the reporter sending the metrics to a collector endpoint as JSON.
+package endpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/metric"
+)
+
+var _ metric.Reporter = (*reporter)(nil)
+
+const (
+	// eventTypeMetric is the event type for the metric.
+	eventTypeMetric = "metric"
+	// eventTypeIdentify is the event type for the workspace identifier.
+	eventTypeIdentify = "identify"
+
+	// eventQueueSize is the max number of the events waiting to be sent.
+	// The reporter drops the new events when the queue is full, so that reporting never blocks the caller.
+	eventQueueSize = 1000
+	timeout        = 10 * time.Second
+)
+
+// Event is the JSON message posted to the collector endpoint.
+type Event struct {
+	Type        string            `json:"type"`
+	WorkspaceID string            `json:"workspaceId"`
+	Timestamp   int64             `json:"timestamp"`
+	Name        string            `json:"name,omitempty"`
+	Value       int               `json:"value,omitempty"`
+	Email       string            `json:"email,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// reporter posts the metrics to the collector endpoint in the background.
+type reporter struct {
+	url         string
+	workspaceID string
+	client      *http.Client
+	eventCh     chan *Event
+	done        chan struct{}
+}
+
+// NewReporter creates a new reporter posting to the url.
+func NewReporter(url string, workspaceID string) metric.Reporter {
+	r := &reporter{
+		url:         url,
+		workspaceID: workspaceID,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		eventCh: make(chan *Event, eventQueueSize),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Close will send the queued events and stop the reporter.
+func (r *reporter) Close() {
+	close(r.eventCh)
+	<-r.done
+}
+
+// Report will queue the metric to be sent.
+func (r *reporter) Report(metric *metric.Metric) error {
+	return r.enqueue(&Event{
+		Type:        eventTypeMetric,
+		WorkspaceID: r.workspaceID,
+		Timestamp:   time.Now().Unix(),
+		Name:        string(metric.Name),
+		Value:       metric.Value,
+		Labels:      metric.Labels,
+	})
+}
+
+// Identify will queue the workspace identifier to be sent.
+func (r *reporter) Identify(identifier *metric.Identifier) error {
+	return r.enqueue(&Event{
+		Type:        eventTypeIdentify,
+		WorkspaceID: r.workspaceID,
+		Timestamp:   time.Now().Unix(),
+		Name:        identifier.Name,
+		Email:       identifier.Email,
+		Labels:      identifier.Labels,
+	})
+}
+
+func (r *reporter) enqueue(event *Event) error {
+	select {
+	case r.eventCh <- event:
+		return nil
+	default:
+		return errors.Errorf("the event queue of the collector endpoint %s is full", r.url)
+	}
+}
+
+func (r *reporter) run() {
+	defer close(r.done)
+	for event := range r.eventCh {
+		if err := r.post(event); err != nil {
+			log.Debug("Failed to post the event to the collector endpoint", zap.String("type", event.Type), zap.Error(err))
+		}
+	}
+}
+
+func (r *reporter) post(event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event")
+	}
+	req, err := http.NewRequest("POST", r.url, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct POST request to %s", r.url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to POST to %s", r.url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("failed to POST to %s, status code: %d, response body: %.100s", r.url, resp.StatusCode, b)
+	}
+	return nil
+}
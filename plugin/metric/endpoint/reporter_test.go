@@ -0,0 +1,53 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/metric"
+)
+
+func TestReporter(t *testing.T) {
+	var mu sync.Mutex
+	var eventList []*Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		event := &Event{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(event))
+		mu.Lock()
+		eventList = append(eventList, event)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	r := NewReporter(server.URL, "workspace-1")
+	require.NoError(t, r.Identify(&metric.Identifier{
+		ID:    "workspace-1",
+		Email: "demo@example.com",
+		Name:  "Demo",
+	}))
+	require.NoError(t, r.Report(&metric.Metric{
+		Name:  "bb.instance.count",
+		Value: 3,
+		Labels: map[string]string{
+			"engine": "MYSQL",
+		},
+	}))
+	// Close sends the queued events before returning.
+	r.Close()
+
+	require.Len(t, eventList, 2)
+	require.Equal(t, eventTypeIdentify, eventList[0].Type)
+	require.Equal(t, "workspace-1", eventList[0].WorkspaceID)
+	require.Equal(t, "demo@example.com", eventList[0].Email)
+	require.Equal(t, eventTypeMetric, eventList[1].Type)
+	require.Equal(t, "bb.instance.count", eventList[1].Name)
+	require.Equal(t, 3, eventList[1].Value)
+	require.Equal(t, map[string]string{"engine": "MYSQL"}, eventList[1].Labels)
+}
@@ -22,6 +22,10 @@ const (
 	ConstraintTypeNotNull
 	// ConstraintTypeCheck is the check constraint.
 	ConstraintTypeCheck
+	// ConstraintTypeDefault is the default value of a column.
+	ConstraintTypeDefault
+	// ConstraintTypeGenerated is the identity or the generated column, whose value is generated by the database.
+	ConstraintTypeGenerated
 )
 
 // ConstraintDef is struct for constraint definition.
@@ -729,6 +729,10 @@ func convertConstraintType(in pgquery.ConstrType, usingIndex bool) ast.Constrain
 		return ast.ConstraintTypeNotNull
 	case pgquery.ConstrType_CONSTR_CHECK:
 		return ast.ConstraintTypeCheck
+	case pgquery.ConstrType_CONSTR_DEFAULT:
+		return ast.ConstraintTypeDefault
+	case pgquery.ConstrType_CONSTR_IDENTITY, pgquery.ConstrType_CONSTR_GENERATED:
+		return ast.ConstraintTypeGenerated
 	}
 	return ast.ConstraintTypeUndefined
 }
@@ -750,10 +754,32 @@ func convertColumnDef(in *pgquery.Node_ColumnDef) (*ast.ColumnDef, error) {
 		columnCons.KeyList = append(columnCons.KeyList, in.ColumnDef.Colname)
 		column.ConstraintList = append(column.ConstraintList, columnCons)
 	}
+	// The serial types are the shorthand of the integer types with the default value from a sequence.
+	if isSerialType(in.ColumnDef.TypeName) {
+		column.ConstraintList = append(column.ConstraintList, &ast.ConstraintDef{
+			Type:    ast.ConstraintTypeDefault,
+			KeyList: []string{in.ColumnDef.Colname},
+		})
+	}
 
 	return column, nil
 }
 
+func isSerialType(typeName *pgquery.TypeName) bool {
+	if typeName == nil || len(typeName.Names) != 1 {
+		return false
+	}
+	name, ok := typeName.Names[0].Node.(*pgquery.Node_String_)
+	if !ok {
+		return false
+	}
+	switch name.String_.Str {
+	case "smallserial", "serial2", "serial", "serial4", "bigserial", "serial8":
+		return true
+	}
+	return false
+}
+
 func convertToTableType(relationType pgquery.ObjectType) (ast.TableType, error) {
 	switch relationType {
 	case pgquery.ObjectType_OBJECT_TABLE:
@@ -321,6 +321,81 @@ func TestPGAddColumnStmt(t *testing.T) {
 				},
 			},
 		},
+		// The serial column has the implicit default value.
+		{
+			stmt: "ALTER TABLE techbook ADD COLUMN a int NOT NULL DEFAULT 0, ADD COLUMN b bigserial, ADD COLUMN c int GENERATED ALWAYS AS IDENTITY",
+			want: []ast.Node{
+				&ast.AlterTableStmt{
+					Table: &ast.TableDef{
+						Type: ast.TableTypeBaseTable,
+						Name: "techbook",
+					},
+					AlterItemList: []ast.Node{
+						&ast.AddColumnListStmt{
+							Table: &ast.TableDef{
+								Type: ast.TableTypeBaseTable,
+								Name: "techbook",
+							},
+							ColumnList: []*ast.ColumnDef{
+								{
+									ColumnName: "a",
+									ConstraintList: []*ast.ConstraintDef{
+										{
+											Type:    ast.ConstraintTypeNotNull,
+											KeyList: []string{"a"},
+										},
+										{
+											Type:    ast.ConstraintTypeDefault,
+											KeyList: []string{"a"},
+										},
+									},
+								},
+							},
+						},
+						&ast.AddColumnListStmt{
+							Table: &ast.TableDef{
+								Type: ast.TableTypeBaseTable,
+								Name: "techbook",
+							},
+							ColumnList: []*ast.ColumnDef{
+								{
+									ColumnName: "b",
+									ConstraintList: []*ast.ConstraintDef{
+										{
+											Type:    ast.ConstraintTypeDefault,
+											KeyList: []string{"b"},
+										},
+									},
+								},
+							},
+						},
+						&ast.AddColumnListStmt{
+							Table: &ast.TableDef{
+								Type: ast.TableTypeBaseTable,
+								Name: "techbook",
+							},
+							ColumnList: []*ast.ColumnDef{
+								{
+									ColumnName: "c",
+									ConstraintList: []*ast.ConstraintDef{
+										{
+											Type:    ast.ConstraintTypeGenerated,
+											KeyList: []string{"c"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text: "ALTER TABLE techbook ADD COLUMN a int NOT NULL DEFAULT 0, ADD COLUMN b bigserial, ADD COLUMN c int GENERATED ALWAYS AS IDENTITY",
+					Line: 1,
+				},
+			},
+		},
 	}
 
 	runTests(t, tests)
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"strings"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	tidbparser "github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pkg/errors"
+)
+
+// Format pretty-prints the statement in the canonical form of the engine, one statement per line.
+// The keywords are uppercased and the identifiers are quoted if the engine requires.
+// Comments are not part of the AST, so they are dropped from the formatted statement.
+func Format(engineType EngineType, statement string) (string, error) {
+	switch engineType {
+	case Postgres:
+		return formatPostgreSQL(statement)
+	case MySQL, TiDB:
+		return formatMySQL(statement)
+	default:
+		return "", errors.Errorf("engine type is not supported: %s", engineType)
+	}
+}
+
+func formatMySQL(statement string) (string, error) {
+	p := tidbparser.New()
+	// To support MySQL8 window function syntax.
+	// See https://github.com/bytebase/bytebase/issues/175.
+	p.EnableWindowFunc(true)
+
+	nodeList, _, err := p.Parse(statement, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	var stmtList []string
+	for _, node := range nodeList {
+		var buf strings.Builder
+		if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &buf)); err != nil {
+			return "", errors.Wrapf(err, "failed to restore statement %q", node.Text())
+		}
+		stmtList = append(stmtList, buf.String()+";")
+	}
+	return strings.Join(stmtList, "\n"), nil
+}
+
+func formatPostgreSQL(statement string) (string, error) {
+	res, err := pgquery.Parse(statement)
+	if err != nil {
+		return "", err
+	}
+
+	var stmtList []string
+	for _, stmt := range res.Stmts {
+		// Deparse the statements one by one so that each of them starts on a new line.
+		text, err := pgquery.Deparse(&pgquery.ParseResult{Stmts: []*pgquery.RawStmt{stmt}})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to deparse statement")
+		}
+		stmtList = append(stmtList, text+";")
+	}
+	return strings.Join(stmtList, "\n"), nil
+}
@@ -0,0 +1,67 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/stretchr/testify/require"
+
+	// Register pingcap parser driver.
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+type formatTestData struct {
+	statement string
+	want      string
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		engineType parser.EngineType
+		data       []formatTestData
+	}{
+		{
+			engineType: parser.MySQL,
+			data: []formatTestData{
+				{
+					statement: "select a from t where a=1",
+					want:      "SELECT `a` FROM `t` WHERE `a`=1;",
+				},
+				{
+					statement: `
+					select a   from t;
+					-- comment
+					delete from t`,
+					want: "SELECT `a` FROM `t`;\nDELETE FROM `t`;",
+				},
+			},
+		},
+		{
+			engineType: parser.Postgres,
+			data: []formatTestData{
+				{
+					statement: "select a from t where a=1",
+					want:      "SELECT a FROM t WHERE a = 1;",
+				},
+				{
+					statement: `
+					select a   from t;
+					-- comment
+					delete from t`,
+					want: "SELECT a FROM t;\nDELETE FROM t;",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		for _, data := range test.data {
+			res, err := parser.Format(test.engineType, data.statement)
+			require.NoError(t, err)
+			require.Equal(t, data.want, res, data.statement)
+		}
+	}
+
+	_, err := parser.Format(parser.MySQL, "select from")
+	require.Error(t, err)
+}
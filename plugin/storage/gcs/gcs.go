@@ -0,0 +1,36 @@
+// Package gcs provides the client for Google Cloud Storage (GCS).
+// GCS is accessed via its S3 compatible XML API with the HMAC keys of a service account,
+// see https://cloud.google.com/storage/docs/interoperability.
+package gcs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/bytebase/bytebase/plugin/storage"
+	"github.com/bytebase/bytebase/plugin/storage/s3"
+)
+
+var _ storage.Backend = (*Client)(nil)
+
+const (
+	// endpoint is the endpoint of the GCS XML API.
+	endpoint = "https://storage.googleapis.com"
+	// region is the region for signing the requests. GCS ignores it, the location is decided by the bucket.
+	region = "auto"
+)
+
+// Client wraps the client of the GCS XML API.
+type Client struct {
+	*s3.Client
+}
+
+// NewClient returns a new GCS client. The credentials are the HMAC access ID and secret of the service account.
+func NewClient(ctx context.Context, bucket string, credentials aws.Credentials) (*Client, error) {
+	client, err := s3.NewClientWithEndpoint(ctx, endpoint, region, bucket, credentials)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Client: client}, nil
+}
@@ -0,0 +1,79 @@
+// Package local provides the blob storage backend on the local disk.
+package local
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/storage"
+)
+
+var _ storage.Backend = (*Client)(nil)
+
+// Client stores the objects as the files under the directory.
+type Client struct {
+	dir string
+}
+
+// NewClient returns a new client storing the objects under the directory.
+func NewClient(dir string) *Client {
+	return &Client{dir: dir}
+}
+
+// Upload writes the body to the file at the path.
+func (c *Client) Upload(_ context.Context, path string, body io.Reader) error {
+	absPath := filepath.Join(c.dir, path)
+	if err := os.MkdirAll(filepath.Dir(absPath), os.ModePerm); err != nil {
+		return errors.Wrapf(err, "failed to create directory for %q", absPath)
+	}
+	file, err := os.OpenFile(absPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open file %q", absPath)
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, body); err != nil {
+		return errors.Wrapf(err, "failed to write file %q", absPath)
+	}
+	return nil
+}
+
+// Download copies the file at the path to w.
+func (c *Client) Download(_ context.Context, path string, w io.WriterAt) error {
+	absPath := filepath.Join(c.dir, path)
+	file, err := os.Open(absPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open file %q", absPath)
+	}
+	defer file.Close()
+
+	buf := make([]byte, 32*1024)
+	var offset int64
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			if _, err := w.WriteAt(buf[:n], offset); err != nil {
+				return errors.Wrapf(err, "failed to write the content of file %q", absPath)
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file %q", absPath)
+		}
+	}
+}
+
+// Delete removes the file at the path.
+func (c *Client) Delete(_ context.Context, path string) error {
+	absPath := filepath.Join(c.dir, path)
+	if err := os.Remove(absPath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove file %q", absPath)
+	}
+	return nil
+}
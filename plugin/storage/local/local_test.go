@@ -0,0 +1,37 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalOperations(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	client := NewClient(dir)
+	path := filepath.Join("backup", "db", "101", "test.sql")
+	content := "CREATE TABLE t (id INT);"
+
+	a.NoError(client.Upload(ctx, path, strings.NewReader(content)))
+
+	file, err := os.Create(filepath.Join(dir, "download.sql"))
+	a.NoError(err)
+	defer file.Close()
+	a.NoError(client.Download(ctx, path, file))
+	got, err := os.ReadFile(file.Name())
+	a.NoError(err)
+	a.Equal(content, string(got))
+
+	a.NoError(client.Delete(ctx, path))
+	_, err = os.Stat(filepath.Join(dir, path))
+	a.True(os.IsNotExist(err))
+	// Deleting the absent object is not an error.
+	a.NoError(client.Delete(ctx, path))
+	a.Error(client.Download(ctx, path, file))
+}
@@ -12,12 +12,18 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/storage"
 )
 
+var _ storage.Backend = (*Client)(nil)
+
 // Client wraps the AWS S3 client.
 type Client struct {
 	c      *s3.Client
 	bucket string
+	// checksumAlgorithm is the checksum algorithm of the uploaded objects, empty for the S3 compatible storages not supporting it.
+	checksumAlgorithm types.ChecksumAlgorithm
 }
 
 // GetCredentialsFromFile load AWS credentials from file.
@@ -45,7 +51,26 @@ func NewClient(ctx context.Context, region, bucket string, credentials aws.Crede
 		return nil, errors.Wrap(err, "failed to load AWS S3 config")
 	}
 	return &Client{
-		c:      s3.NewFromConfig(cfg),
+		c:                 s3.NewFromConfig(cfg),
+		bucket:            bucket,
+		checksumAlgorithm: types.ChecksumAlgorithmSha256,
+	}, nil
+}
+
+// NewClientWithEndpoint returns a new client of the S3 compatible storage at the endpoint, e.g. the XML API of Google Cloud Storage.
+func NewClientWithEndpoint(ctx context.Context, endpoint, region, bucket string, credentials aws.Credentials) (*Client, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx,
+		awsconfig.WithRegion(region),
+		awsconfig.WithCredentialsProvider(awscredentials.NewStaticCredentialsProvider(credentials.AccessKeyID, credentials.SecretAccessKey, "")),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load S3 config for endpoint %q", endpoint)
+	}
+	return &Client{
+		c: s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.EndpointResolver = s3.EndpointResolverFromURL(endpoint)
+			o.UsePathStyle = true
+		}),
 		bucket: bucket,
 	}, nil
 }
@@ -76,7 +101,7 @@ func (c *Client) UploadObject(ctx context.Context, path string, body io.Reader)
 		Bucket:            &c.bucket,
 		Key:               &path,
 		Body:              body,
-		ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+		ChecksumAlgorithm: c.checksumAlgorithm,
 	})
 }
 
@@ -88,6 +113,31 @@ func (c *Client) DeleteObject(ctx context.Context, path string) (*s3.DeleteObjec
 	})
 }
 
+// Upload uploads the body to the path, which implements storage.Backend.
+func (c *Client) Upload(ctx context.Context, path string, body io.Reader) error {
+	if _, err := c.UploadObject(ctx, path, body); err != nil {
+		return errors.Wrapf(err, "failed to upload %q to bucket %q", path, c.bucket)
+	}
+	return nil
+}
+
+// Download downloads the object at the path to w, which implements storage.Backend.
+func (c *Client) Download(ctx context.Context, path string, w io.WriterAt) error {
+	if _, err := c.DownloadObject(ctx, path, w); err != nil {
+		return errors.Wrapf(err, "failed to download %q from bucket %q", path, c.bucket)
+	}
+	return nil
+}
+
+// Delete deletes the object at the path, which implements storage.Backend.
+// S3 doesn't report the absent objects when deleting them.
+func (c *Client) Delete(ctx context.Context, path string) error {
+	if _, err := c.DeleteObject(ctx, path); err != nil {
+		return errors.Wrapf(err, "failed to delete %q from bucket %q", path, c.bucket)
+	}
+	return nil
+}
+
 // GetBucket returns the bucket.
 func (c *Client) GetBucket() string {
 	return c.bucket
@@ -0,0 +1,18 @@
+// Package storage provides the blob storage backends for the backup files and the task run artifacts.
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Backend is the blob storage backend, e.g. the local disk or a cloud bucket.
+// The path is the key of the object relative to the root of the backend, e.g. the data dir or the bucket.
+type Backend interface {
+	// Upload uploads the body to the path, and overwrites the existing object.
+	Upload(ctx context.Context, path string, body io.Reader) error
+	// Download downloads the object at the path to w.
+	Download(ctx context.Context, path string, w io.WriterAt) error
+	// Delete deletes the object at the path. It's not an error if the object doesn't exist.
+	Delete(ctx context.Context, path string) error
+}
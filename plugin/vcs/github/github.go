@@ -42,7 +42,9 @@ type Provider struct {
 
 func newProvider(config vcs.ProviderConfig) vcs.Provider {
 	if config.Client == nil {
-		config.Client = &http.Client{}
+		config.Client = &http.Client{
+			Transport: common.OutboundTransport,
+		}
 	}
 	return &Provider{
 		client: config.Client,
@@ -130,6 +132,11 @@ const (
 // WebhookInfo represents a GitHub API response for the webhook information.
 type WebhookInfo struct {
 	ID int `json:"id"`
+	// Config is not the WebhookConfig because GitHub returns the "insecure_ssl"
+	// as a string in the response.
+	Config struct {
+		URL string `json:"url"`
+	} `json:"config"`
 }
 
 // WebhookConfig represents the GitHub API message for webhook configuration.
@@ -824,6 +831,52 @@ func (p *Provider) CreateWebhook(ctx context.Context, oauthCtx common.OauthConte
 	return strconv.Itoa(webhookInfo.ID), nil
 }
 
+// FetchWebhook fetches the webhook in the repository.
+//
+// Docs: https://docs.github.com/en/rest/webhooks/repos#get-a-repository-webhook
+func (p *Provider) FetchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string) (*vcs.Webhook, error) {
+	url := fmt.Sprintf("%s/repos/%s/hooks/%s", p.APIURL(instanceURL), repositoryID, webhookID)
+	code, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to fetch webhook from URL %s", url)
+	} else if code == http.StatusForbidden {
+		return nil, common.Errorf(common.NotAuthorized, "failed to fetch webhook from URL %s, body: %s", url, body)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to fetch webhook from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var webhookInfo WebhookInfo
+	if err := json.Unmarshal([]byte(body), &webhookInfo); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	return &vcs.Webhook{
+		ID:  strconv.Itoa(webhookInfo.ID),
+		URL: webhookInfo.Config.URL,
+	}, nil
+}
+
 // PatchWebhook patches the webhook in the repository with given payload.
 //
 // Docs: https://docs.github.com/en/rest/webhooks/repos#update-a-repository-webhook
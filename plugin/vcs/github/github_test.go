@@ -1042,6 +1042,52 @@ func TestProvider_CreateWebhook(t *testing.T) {
 	assert.Equal(t, "12345678", got)
 }
 
+func TestProvider_FetchWebhook(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/repos/octocat/Hello-World/hooks/12345678", r.URL.Path)
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							// Example response taken from https://docs.github.com/en/rest/webhooks/repos#get-a-repository-webhook
+							Body: io.NopCloser(strings.NewReader(`
+{
+  "type": "Repository",
+  "id": 12345678,
+  "name": "web",
+  "active": true,
+  "events": [
+    "push",
+    "pull_request"
+  ],
+  "config": {
+    "content_type": "json",
+    "insecure_ssl": "0",
+    "url": "https://example.com/webhook"
+  },
+  "updated_at": "2019-06-03T00:57:16Z",
+  "created_at": "2019-06-03T00:57:16Z"
+}
+`)),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchWebhook(ctx, common.OauthContext{}, githubComURL, "octocat/Hello-World", "12345678")
+	require.NoError(t, err)
+	want := &vcs.Webhook{
+		ID:  "12345678",
+		URL: "https://example.com/webhook",
+	}
+	assert.Equal(t, want, got)
+}
+
 func TestProvider_PatchWebhook(t *testing.T) {
 	p := newProvider(
 		vcs.ProviderConfig{
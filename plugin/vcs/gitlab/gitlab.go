@@ -43,7 +43,8 @@ const (
 
 // WebhookInfo represents a GitLab API response for the webhook information.
 type WebhookInfo struct {
-	ID int `json:"id"`
+	ID  int    `json:"id"`
+	URL string `json:"url"`
 }
 
 // WebhookCreate represents a GitLab API request for creating a new webhook.
@@ -159,6 +160,49 @@ type RepositoryMember struct {
 	AccessLevel int32     `json:"access_level"`
 }
 
+// MergeRequestVersion represents a GitLab API response for a merge request
+// diff version.
+type MergeRequestVersion struct {
+	ID             int    `json:"id"`
+	HeadCommitSHA  string `json:"head_commit_sha"`
+	BaseCommitSHA  string `json:"base_commit_sha"`
+	StartCommitSHA string `json:"start_commit_sha"`
+}
+
+// DiscussionPosition is the position of a merge request discussion anchored to
+// a line of the diff.
+type DiscussionPosition struct {
+	PositionType string `json:"position_type"`
+	BaseSHA      string `json:"base_sha"`
+	StartSHA     string `json:"start_sha"`
+	HeadSHA      string `json:"head_sha"`
+	OldPath      string `json:"old_path"`
+	NewPath      string `json:"new_path"`
+	NewLine      int    `json:"new_line,omitempty"`
+}
+
+// DiscussionCreate represents a GitLab API request for creating a merge
+// request discussion.
+type DiscussionCreate struct {
+	Body     string              `json:"body"`
+	Position *DiscussionPosition `json:"position,omitempty"`
+}
+
+// DiscussionNote represents a GitLab API response for a note of the merge
+// request discussion.
+type DiscussionNote struct {
+	ID       int                 `json:"id"`
+	Body     string              `json:"body"`
+	Resolved bool                `json:"resolved"`
+	Position *DiscussionPosition `json:"position"`
+}
+
+// Discussion represents a GitLab API response for a merge request discussion.
+type Discussion struct {
+	ID    string           `json:"id"`
+	Notes []DiscussionNote `json:"notes"`
+}
+
 // gitLabRepository represents a GitLab API response for a repository.
 type gitLabRepository struct {
 	ID                int64  `json:"id"`
@@ -178,7 +222,9 @@ type Provider struct {
 
 func newProvider(config vcs.ProviderConfig) vcs.Provider {
 	if config.Client == nil {
-		config.Client = &http.Client{}
+		config.Client = &http.Client{
+			Transport: common.OutboundTransport,
+		}
 	}
 	return &Provider{
 		client: config.Client,
@@ -825,6 +871,52 @@ func (p *Provider) CreateWebhook(ctx context.Context, oauthCtx common.OauthConte
 	return strconv.Itoa(webhookInfo.ID), nil
 }
 
+// FetchWebhook fetches the webhook in the repository.
+//
+// Docs: https://docs.gitlab.com/ee/api/projects.html#get-project-hook
+func (p *Provider) FetchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string) (*vcs.Webhook, error) {
+	url := fmt.Sprintf("%s/projects/%s/hooks/%s", p.APIURL(instanceURL), repositoryID, webhookID)
+	code, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to fetch webhook from URL %s", url)
+	} else if code == http.StatusForbidden {
+		return nil, common.Errorf(common.NotAuthorized, "failed to fetch webhook from URL %s, body: %s", url, body)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to fetch webhook from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var webhookInfo WebhookInfo
+	if err := json.Unmarshal([]byte(body), &webhookInfo); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	return &vcs.Webhook{
+		ID:  strconv.Itoa(webhookInfo.ID),
+		URL: webhookInfo.URL,
+	}, nil
+}
+
 // PatchWebhook patches the webhook in the repository with given payload.
 //
 // Docs: https://docs.gitlab.com/ee/api/projects.html#edit-project-hook
@@ -898,6 +990,236 @@ func (p *Provider) DeleteWebhook(ctx context.Context, oauthCtx common.OauthConte
 	return nil
 }
 
+// FetchMergeRequestLatestVersion fetches the latest diff version of the merge
+// request, the commit SHAs of which are required to anchor a discussion to the
+// diff.
+//
+// Docs: https://docs.gitlab.com/ee/api/merge_requests.html#get-mr-diff-versions
+func (p *Provider) FetchMergeRequestLatestVersion(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, mergeRequestIID int) (*MergeRequestVersion, error) {
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%d/versions", p.APIURL(instanceURL), repositoryID, mergeRequestIID)
+	code, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to fetch merge request versions from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to fetch merge request versions from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var versions []MergeRequestVersion
+	if err := json.Unmarshal([]byte(body), &versions); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	// The versions are sorted from the newest to the oldest.
+	if len(versions) == 0 {
+		return nil, common.Errorf(common.NotFound, "merge request %d has no diff version", mergeRequestIID)
+	}
+	return &versions[0], nil
+}
+
+// FetchMergeRequestDiscussionList fetches all discussions of the merge request.
+//
+// Docs: https://docs.gitlab.com/ee/api/discussions.html#list-project-merge-request-discussion-items
+func (p *Provider) FetchMergeRequestDiscussionList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, mergeRequestIID int) ([]Discussion, error) {
+	var allDiscussions []Discussion
+	page := 1
+	for {
+		discussions, hasNextPage, err := p.fetchPaginatedMergeRequestDiscussionList(ctx, oauthCtx, instanceURL, repositoryID, mergeRequestIID, page)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetch paginated list")
+		}
+		allDiscussions = append(allDiscussions, discussions...)
+
+		if !hasNextPage {
+			break
+		}
+		page++
+	}
+	return allDiscussions, nil
+}
+
+// fetchPaginatedMergeRequestDiscussionList fetches discussions of a merge
+// request in given page. It return the paginated results along with a boolean
+// indicating whether the next page exists.
+func (p *Provider) fetchPaginatedMergeRequestDiscussionList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, mergeRequestIID, page int) (discussions []Discussion, hasNextPage bool, err error) {
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%d/discussions?page=%d&per_page=%d", p.APIURL(instanceURL), repositoryID, mergeRequestIID, page, apiPageSize)
+	code, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, false, common.Errorf(common.NotFound, "failed to fetch merge request discussions from URL %s", url)
+	} else if code >= 300 {
+		return nil, false,
+			errors.Errorf("failed to fetch merge request discussions from URL %s, status code: %d, body: %s",
+				url,
+				code,
+				body,
+			)
+	}
+
+	if err := json.Unmarshal([]byte(body), &discussions); err != nil {
+		return nil, false, errors.Wrap(err, "unmarshal body")
+	}
+	return discussions, len(discussions) >= apiPageSize, nil
+}
+
+// CreateMergeRequestDiscussion creates a discussion in the merge request. The
+// discussion is anchored to the diff line if the position is given.
+//
+// Docs: https://docs.gitlab.com/ee/api/discussions.html#create-new-merge-request-thread
+func (p *Provider) CreateMergeRequestDiscussion(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, mergeRequestIID int, discussionCreate *DiscussionCreate) error {
+	body, err := json.Marshal(discussionCreate)
+	if err != nil {
+		return errors.Wrap(err, "marshal discussion create")
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%d/discussions", p.APIURL(instanceURL), repositoryID, mergeRequestIID)
+	code, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code >= 300 {
+		return errors.Errorf("failed to create merge request discussion through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// UpdateMergeRequestDiscussionNote updates the body of a note in the merge
+// request discussion.
+//
+// Docs: https://docs.gitlab.com/ee/api/discussions.html#modify-an-existing-merge-request-thread-note
+func (p *Provider) UpdateMergeRequestDiscussionNote(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, mergeRequestIID int, discussionID string, noteID int, noteBody string) error {
+	body, err := json.Marshal(map[string]string{"body": noteBody})
+	if err != nil {
+		return errors.Wrap(err, "marshal note update")
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%d/discussions/%s/notes/%d", p.APIURL(instanceURL), repositoryID, mergeRequestIID, discussionID, noteID)
+	code, resp, err := oauth.Put(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to update merge request discussion note through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to update merge request discussion note through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// ResolveMergeRequestDiscussion resolves the merge request discussion.
+//
+// Docs: https://docs.gitlab.com/ee/api/discussions.html#resolve-a-merge-request-thread
+func (p *Provider) ResolveMergeRequestDiscussion(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, mergeRequestIID int, discussionID string) error {
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%d/discussions/%s?resolved=true", p.APIURL(instanceURL), repositoryID, mergeRequestIID, discussionID)
+	code, resp, err := oauth.Put(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		nil,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to resolve merge request discussion through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to resolve merge request discussion through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
 // readFile reads the given file in the repository.
 //
 // TODO: The same GitLab API endpoint supports using the HEAD request to only
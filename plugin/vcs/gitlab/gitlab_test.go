@@ -710,6 +710,44 @@ func TestProvider_CreateWebhook(t *testing.T) {
 	assert.Equal(t, "1", got)
 }
 
+func TestProvider_FetchWebhook(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/api/v4/projects/1/hooks/1", r.URL.Path)
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							// Example response taken from https://docs.gitlab.com/ee/api/projects.html#get-project-hook
+							Body: io.NopCloser(strings.NewReader(`
+{
+  "id": 1,
+  "url": "http://example.com/hook",
+  "project_id": 3,
+  "push_events": true,
+  "push_events_branch_filter": "",
+  "enable_ssl_verification": true,
+  "created_at": "2012-10-12T17:04:47Z"
+}
+`)),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchWebhook(ctx, common.OauthContext{}, "", "1", "1")
+	require.NoError(t, err)
+	want := &vcs.Webhook{
+		ID:  "1",
+		URL: "http://example.com/hook",
+	}
+	assert.Equal(t, want, got)
+}
+
 func TestProvider_PatchWebhook(t *testing.T) {
 	p := newProvider(
 		vcs.ProviderConfig{
@@ -754,6 +792,206 @@ func TestProvider_DeleteWebhook(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestProvider_FetchMergeRequestLatestVersion(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/api/v4/projects/1/merge_requests/2/versions", r.URL.Path)
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							// Example response taken from https://docs.gitlab.com/ee/api/merge_requests.html#get-mr-diff-versions
+							Body: io.NopCloser(strings.NewReader(`
+[{
+  "id": 110,
+  "head_commit_sha": "33e2ee8579fda5bc36accc9c6fbd0b4fefda9e30",
+  "base_commit_sha": "eeb57dffe83deb686a60a71c16c32f71046868fd",
+  "start_commit_sha": "eeb57dffe83deb686a60a71c16c32f71046868fd",
+  "created_at": "2016-07-26T14:44:48.926Z",
+  "merge_request_id": 105,
+  "state": "collected",
+  "real_size": "1"
+}, {
+  "id": 108,
+  "head_commit_sha": "3eed087b29835c48015768f839d76e5ea8f07a24",
+  "base_commit_sha": "eeb57dffe83deb686a60a71c16c32f71046868fd",
+  "start_commit_sha": "eeb57dffe83deb686a60a71c16c32f71046868fd",
+  "created_at": "2016-07-25T14:21:33.028Z",
+  "merge_request_id": 105,
+  "state": "collected",
+  "real_size": "1"
+}]
+`)),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.(*Provider).FetchMergeRequestLatestVersion(ctx, common.OauthContext{}, "", "1", 2)
+	require.NoError(t, err)
+
+	want := &MergeRequestVersion{
+		ID:             110,
+		HeadCommitSHA:  "33e2ee8579fda5bc36accc9c6fbd0b4fefda9e30",
+		BaseCommitSHA:  "eeb57dffe83deb686a60a71c16c32f71046868fd",
+		StartCommitSHA: "eeb57dffe83deb686a60a71c16c32f71046868fd",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchMergeRequestDiscussionList(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/api/v4/projects/1/merge_requests/2/discussions", r.URL.Path)
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body: io.NopCloser(strings.NewReader(`
+[{
+  "id": "6a9c1750b37d513a43987b574953fceb50b03ce7",
+  "individual_note": false,
+  "notes": [{
+    "id": 1126,
+    "type": "DiffNote",
+    "body": "discussion text",
+    "resolvable": true,
+    "resolved": false,
+    "position": {
+      "base_sha": "b5d6e7b1613fca24d250fa8e5bc7bcc3dd6002ef",
+      "start_sha": "7c9c2ead8a320fb7ba0b4e234bd9529a2614e306",
+      "head_sha": "4803c71e6b1833ca72b8b26ef2ecd5adc8a38031",
+      "old_path": "package.json",
+      "new_path": "package.json",
+      "position_type": "text",
+      "new_line": 27
+    }
+  }]
+}]
+`)),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.(*Provider).FetchMergeRequestDiscussionList(ctx, common.OauthContext{}, "", "1", 2)
+	require.NoError(t, err)
+
+	want := []Discussion{
+		{
+			ID: "6a9c1750b37d513a43987b574953fceb50b03ce7",
+			Notes: []DiscussionNote{
+				{
+					ID:   1126,
+					Body: "discussion text",
+					Position: &DiscussionPosition{
+						PositionType: "text",
+						BaseSHA:      "b5d6e7b1613fca24d250fa8e5bc7bcc3dd6002ef",
+						StartSHA:     "7c9c2ead8a320fb7ba0b4e234bd9529a2614e306",
+						HeadSHA:      "4803c71e6b1833ca72b8b26ef2ecd5adc8a38031",
+						OldPath:      "package.json",
+						NewPath:      "package.json",
+						NewLine:      27,
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreateMergeRequestDiscussion(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/api/v4/projects/1/merge_requests/2/discussions", r.URL.Path)
+						body, err := io.ReadAll(r.Body)
+						require.NoError(t, err)
+						assert.JSONEq(t, `{"body":"text","position":{"position_type":"text","base_sha":"a","start_sha":"b","head_sha":"c","old_path":"x.sql","new_path":"x.sql","new_line":3}}`, string(body))
+						return &http.Response{
+							StatusCode: http.StatusCreated,
+							Body:       io.NopCloser(strings.NewReader("")),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	err := p.(*Provider).CreateMergeRequestDiscussion(ctx, common.OauthContext{}, "", "1", 2, &DiscussionCreate{
+		Body: "text",
+		Position: &DiscussionPosition{
+			PositionType: "text",
+			BaseSHA:      "a",
+			StartSHA:     "b",
+			HeadSHA:      "c",
+			OldPath:      "x.sql",
+			NewPath:      "x.sql",
+			NewLine:      3,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestProvider_UpdateMergeRequestDiscussionNote(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/api/v4/projects/1/merge_requests/2/discussions/abc/notes/3", r.URL.Path)
+						body, err := io.ReadAll(r.Body)
+						require.NoError(t, err)
+						assert.JSONEq(t, `{"body":"text"}`, string(body))
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(strings.NewReader("")),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	err := p.(*Provider).UpdateMergeRequestDiscussionNote(ctx, common.OauthContext{}, "", "1", 2, "abc", 3, "text")
+	require.NoError(t, err)
+}
+
+func TestProvider_ResolveMergeRequestDiscussion(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/api/v4/projects/1/merge_requests/2/discussions/abc", r.URL.Path)
+						assert.Equal(t, "true", r.URL.Query().Get("resolved"))
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(strings.NewReader("")),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	err := p.(*Provider).ResolveMergeRequestDiscussion(ctx, common.OauthContext{}, "", "1", 2, "abc")
+	require.NoError(t, err)
+}
+
 func TestOAuth_RefreshToken(t *testing.T) {
 	ctx := context.Background()
 	client := &http.Client{
@@ -75,6 +75,13 @@ type RepositoryTreeNode struct {
 	Type string
 }
 
+// Webhook records the webhook of the repository.
+type Webhook struct {
+	ID string
+	// URL is the URL to which the events are delivered.
+	URL string
+}
+
 // PushEvent is the API message for a VCS push event.
 type PushEvent struct {
 	VCSType            Type       `json:"vcsType"`
@@ -209,6 +216,13 @@ type Provider interface {
 	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
 	// payload: the webhook payload
 	CreateWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, payload []byte) (string, error)
+	// Fetches a webhook.
+	//
+	// oauthCtx: OAuth context to fetch the webhook
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// webhookID: the webhook ID from the external VCS system
+	FetchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string) (*Webhook, error)
 	// Patches a webhook.
 	//
 	// The payload stores the patched field(s).
@@ -7,6 +7,8 @@ import (
 	"net/http"
 
 	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
 )
 
 // CustomWebhookResponse is the API message for Custom webhook response.
@@ -27,6 +29,7 @@ type CustomWebhookRequest struct {
 	CreatedTS    int64    `json:"created_ts"`
 	Issue        *Issue   `json:"issue"`
 	Project      *Project `json:"project"`
+	OnCall       string   `json:"on_call,omitempty"`
 }
 
 func init() {
@@ -49,6 +52,7 @@ func (*CustomReceiver) post(context Context) error {
 		CreatedTS:    context.CreatedTs,
 		Issue:        context.Issue,
 		Project:      context.Project,
+		OnCall:       context.OnCall,
 	}
 
 	body, err := json.Marshal(&payload)
@@ -63,7 +67,8 @@ func (*CustomReceiver) post(context Context) error {
 
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: common.OutboundTransport,
 	}
 	resp, err := client.Do(req)
 	if err != nil {
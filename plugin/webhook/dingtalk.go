@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
 )
 
 // DingTalkWebhookResponse is the API message for DingTalk webhook response.
@@ -68,7 +70,8 @@ func (*DingTalkReceiver) post(context Context) error {
 
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: common.OutboundTransport,
 	}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -8,6 +8,8 @@ import (
 	"net/http"
 
 	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
 )
 
 // DiscordWebhookResponse is the API message for Discord webhook response.
@@ -94,7 +96,8 @@ func (*DiscordReceiver) post(context Context) error {
 
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: common.OutboundTransport,
 	}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -8,6 +8,8 @@ import (
 	"net/http"
 
 	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
 )
 
 // FeishuWebhookResponse is the API message for Feishu webhook response.
@@ -127,7 +129,8 @@ func (*FeishuReceiver) post(context Context) error {
 
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: common.OutboundTransport,
 	}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -8,6 +8,8 @@ import (
 	"net/http"
 
 	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
 )
 
 // SlackWebhookBlockMarkdown is the API message for Slack webhook block markdown.
@@ -128,7 +130,8 @@ func (*SlackReceiver) post(context Context) error {
 
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: common.OutboundTransport,
 	}
 	resp, err := client.Do(req)
 	if err != nil {
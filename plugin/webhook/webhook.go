@@ -64,6 +64,8 @@ type Context struct {
 	CreatedTs    int64
 	Issue        *Issue
 	Project      *Project
+	// OnCall is the external handle of the on-call rotation responsible for the event, e.g. the database on-call.
+	OnCall string
 }
 
 // Receiver is the webhook receiver.
@@ -88,6 +90,13 @@ func (c *Context) getMetaList() []meta {
 		})
 	}
 
+	if c.OnCall != "" {
+		m = append(m, meta{
+			Name:  "On-call",
+			Value: c.OnCall,
+		})
+	}
+
 	return m
 }
 
@@ -73,11 +73,16 @@ func aclMiddleware(s *Server, ce *casbin.Enforcer, next echo.HandlerFunc, readon
 
 		role := member.Role
 		// If admin feature is not enabled, then we treat all user as OWNER.
-		if !s.feature("bb.feature.rbac") {
+		// The auditors stay read-only regardless, otherwise they would gain the write access on downgrade.
+		if !s.feature("bb.feature.rbac") && role != api.Auditor {
 			role = api.Owner
 		}
+		policyRole, err := getACLPolicyRole(role, c.Request().Method)
+		if err != nil {
+			return err
+		}
 		// Performs the ACL check.
-		pass, err := ce.Enforce(string(role), path, method)
+		pass, err := ce.Enforce(string(policyRole), path, method)
 
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
@@ -88,13 +93,33 @@ func aclMiddleware(s *Server, ce *casbin.Enforcer, next echo.HandlerFunc, readon
 				errors.Errorf("rejected by the ACL policy; %s %s u%d/%s", method, path, principalID, role))
 		}
 
-		// Stores role into context.
+		// Resources belonging to another workspace are reported as not found.
+		if err := checkWorkspaceResource(c, member.WorkspaceID, func(resourceType workspaceResourceType, id int) (*int, error) {
+			return s.getResourceWorkspaceID(ctx, resourceType, id)
+		}); err != nil {
+			return err
+		}
+
+		// Stores role and workspace into context.
 		c.Set(getRoleContextKey(), role)
+		c.Set(getWorkspaceIDContextKey(), member.WorkspaceID)
 
 		return next(c)
 	}
 }
 
+// getACLPolicyRole returns the role whose ACL policy applies to the request of the role.
+// The auditors can read everything the owners can read, and nothing else.
+func getACLPolicyRole(role api.Role, method string) (api.Role, error) {
+	if role != api.Auditor {
+		return role, nil
+	}
+	if method != http.MethodGet {
+		return "", echo.NewHTTPError(http.StatusForbidden, "Auditor is only allowed to read")
+	}
+	return api.Owner, nil
+}
+
 func isOperatingSelf(ctx context.Context, c echo.Context, s *Server, curPrincipalID int, method string) (bool, error) {
 	switch method {
 	case http.MethodGet:
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/labstack/echo/v4"
+	scas "github.com/qiangmzsx/string-adapter/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestACLPolicy(t *testing.T) {
+	tests := []struct {
+		path   string
+		method string
+		want   map[api.Role]bool
+	}{
+		{
+			path:   "/database/101/change-history/5/statement",
+			method: "GET",
+			want:   map[api.Role]bool{api.Owner: true, api.DBA: true, api.Developer: true, api.Auditor: true},
+		},
+		{
+			path:   "/database/101/change-history/import",
+			method: "POST",
+			want:   map[api.Role]bool{api.Owner: true, api.DBA: true, api.Developer: false, api.Auditor: false},
+		},
+		{
+			path:   "/database/101/query-grant",
+			method: "POST",
+			want:   map[api.Role]bool{api.Owner: true, api.DBA: true, api.Developer: true, api.Auditor: false},
+		},
+		{
+			path:   "/database/101/query-grant/102",
+			method: "PATCH",
+			want:   map[api.Role]bool{api.Owner: true, api.DBA: true, api.Developer: false, api.Auditor: false},
+		},
+		{
+			path:   "/workspace/config",
+			method: "GET",
+			want:   map[api.Role]bool{api.Owner: true, api.DBA: true, api.Developer: false, api.Auditor: true},
+		},
+		{
+			path:   "/workspace/config",
+			method: "POST",
+			want:   map[api.Role]bool{api.Owner: true, api.DBA: true, api.Developer: false, api.Auditor: false},
+		},
+		// The auditors can read the routes only the owners and the DBAs can read.
+		{
+			path:   "/issue-sla-report",
+			method: "GET",
+			want:   map[api.Role]bool{api.Owner: true, api.DBA: true, api.Developer: false, api.Auditor: true},
+		},
+		{
+			path:   "/member/101",
+			method: "PATCH",
+			want:   map[api.Role]bool{api.Owner: true, api.DBA: false, api.Developer: false, api.Auditor: false},
+		},
+	}
+
+	a := require.New(t)
+	m, err := model.NewModelFromString(casbinModel)
+	a.NoError(err)
+	ce, err := casbin.NewEnforcer(m, scas.NewAdapter(strings.Join([]string{casbinOwnerPolicy, casbinDBAPolicy, casbinDeveloperPolicy}, "\n")))
+	a.NoError(err)
+	for _, test := range tests {
+		for role, want := range test.want {
+			policyRole, err := getACLPolicyRole(role, test.method)
+			if err != nil {
+				// The auditors are rejected before the ACL policy on any request other than GET.
+				httpErr, ok := err.(*echo.HTTPError)
+				a.True(ok, "%s %s %s", role, test.method, test.path)
+				a.Equal(http.StatusForbidden, httpErr.Code, "%s %s %s", role, test.method, test.path)
+				a.False(want, "%s %s %s", role, test.method, test.path)
+				continue
+			}
+			got, err := ce.Enforce(string(policyRole), test.path, test.method)
+			a.NoError(err)
+			a.Equal(want, got, "%s %s %s", role, test.method, test.path)
+		}
+	}
+}
+
+func TestGetACLPolicyRole(t *testing.T) {
+	tests := []struct {
+		role    api.Role
+		method  string
+		want    api.Role
+		wantErr bool
+	}{
+		{api.Developer, http.MethodPost, api.Developer, false},
+		{api.DBA, http.MethodGet, api.DBA, false},
+		{api.Auditor, http.MethodGet, api.Owner, false},
+		{api.Auditor, http.MethodPost, "", true},
+		{api.Auditor, http.MethodPatch, "", true},
+		{api.Auditor, http.MethodDelete, "", true},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := getACLPolicyRole(test.role, test.method)
+		if test.wantErr {
+			a.Error(err, "%s %s", test.role, test.method)
+			continue
+		}
+		a.NoError(err, "%s %s", test.role, test.method)
+		a.Equal(test.want, got, "%s %s", test.role, test.method)
+	}
+}
+
+func TestCheckWorkspaceResource(t *testing.T) {
+	const workspaceA, workspaceB = 101, 102
+	// The workspaces of the resources, the missing ones are not found or shared by all workspaces.
+	workspaceMap := map[workspaceResourceType]map[int]int{
+		workspaceResourcePrincipal:        {1: api.DefaultWorkspaceID, 11: workspaceA, 12: workspaceB},
+		workspaceResourceVCS:              {21: workspaceA, 22: workspaceB},
+		workspaceResourceDatabase:         {31: workspaceA, 32: workspaceB},
+		workspaceResourceQueryGrant:       {41: workspaceA, 42: workspaceB},
+		workspaceResourceInstance:         {51: workspaceA},
+		workspaceResourceInstanceUser:     {61: workspaceB},
+		workspaceResourceLabel:            {71: api.DefaultWorkspaceID},
+		workspaceResourceIssue:            {81: workspaceA},
+		workspaceResourceStatementComment: {91: workspaceB},
+	}
+	getWorkspaceID := func(resourceType workspaceResourceType, id int) (*int, error) {
+		if workspaceID, ok := workspaceMap[resourceType][id]; ok {
+			return &workspaceID, nil
+		}
+		return nil, nil
+	}
+
+	tests := []struct {
+		method      string
+		routePath   string
+		paramNames  []string
+		paramValues []string
+		workspaceID int
+		wantCode    int
+	}{
+		{http.MethodPatch, "/api/principal/:principalID", []string{"principalID"}, []string{"11"}, workspaceA, 0},
+		// The owner of workspace B can't take over the principals of workspace A or the default workspace.
+		{http.MethodPatch, "/api/principal/:principalID", []string{"principalID"}, []string{"11"}, workspaceB, http.StatusNotFound},
+		{http.MethodPatch, "/api/principal/:principalID", []string{"principalID"}, []string{"1"}, workspaceB, http.StatusNotFound},
+		{http.MethodGet, "/api/principal/:principalID", []string{"principalID"}, []string{"12"}, workspaceA, http.StatusNotFound},
+		// The principal without a member, e.g. the system bot, is shared.
+		{http.MethodGet, "/api/principal/:principalID", []string{"principalID"}, []string{"2"}, workspaceB, 0},
+		{http.MethodGet, "/api/inbox/user/:userID", []string{"userID"}, []string{"11"}, workspaceB, http.StatusNotFound},
+		{http.MethodGet, "/api/vcs/:vcsID", []string{"vcsID"}, []string{"21"}, workspaceA, 0},
+		{http.MethodDelete, "/api/vcs/:vcsID", []string{"vcsID"}, []string{"21"}, workspaceB, http.StatusNotFound},
+		{http.MethodPatch, "/api/database/:id/query-grant/:grantID", []string{"id", "grantID"}, []string{"31", "41"}, workspaceA, 0},
+		// The grant of workspace B is addressed through the database of workspace A.
+		{http.MethodPatch, "/api/database/:id/query-grant/:grantID", []string{"id", "grantID"}, []string{"31", "42"}, workspaceA, http.StatusNotFound},
+		{http.MethodPatch, "/api/database/:id/query-grant/:grantID", []string{"id", "grantID"}, []string{"32", "42"}, workspaceA, http.StatusNotFound},
+		{http.MethodDelete, "/api/instance/:instanceID/user/:userID", []string{"instanceID", "userID"}, []string{"51", "61"}, workspaceA, http.StatusNotFound},
+		{http.MethodPatch, "/api/label/:id", []string{"id"}, []string{"71"}, api.DefaultWorkspaceID, 0},
+		{http.MethodPatch, "/api/label/:id", []string{"id"}, []string{"71"}, workspaceA, http.StatusNotFound},
+		{http.MethodPatch, "/api/issue/:issueID/statement-comment/:commentID", []string{"issueID", "commentID"}, []string{"81", "91"}, workspaceA, http.StatusNotFound},
+	}
+
+	a := require.New(t)
+	e := echo.New()
+	for _, test := range tests {
+		c := e.NewContext(httptest.NewRequest(test.method, "/", nil), httptest.NewRecorder())
+		c.SetPath(test.routePath)
+		c.SetParamNames(test.paramNames...)
+		c.SetParamValues(test.paramValues...)
+		err := checkWorkspaceResource(c, test.workspaceID, getWorkspaceID)
+		if test.wantCode == 0 {
+			a.NoError(err, "%s %s %v", test.method, test.routePath, test.paramValues)
+			continue
+		}
+		httpErr, ok := err.(*echo.HTTPError)
+		a.True(ok, "%s %s %v", test.method, test.routePath, test.paramValues)
+		a.Equal(test.wantCode, httpErr.Code, "%s %s %v", test.method, test.routePath, test.paramValues)
+	}
+}
@@ -23,6 +23,16 @@ func (s *Server) registerActivityRoutes(g *echo.Group) {
 
 		activityCreate.Level = api.ActivityInfo
 		activityCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		// The activity can only be created in the container of the workspace.
+		if containerType, ok := getActivityContainerType(activityCreate.Type); ok {
+			containerWorkspaceID, err := s.getResourceWorkspaceID(ctx, containerType, activityCreate.ContainerID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the activity container ID: %d", activityCreate.ContainerID)).SetInternal(err)
+			}
+			if containerWorkspaceID != nil && *containerWorkspaceID != c.Get(getWorkspaceIDContextKey()).(int) {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Activity container ID not found: %d", activityCreate.ContainerID))
+			}
+		}
 		var foundIssue *api.Issue
 		if activityCreate.Type == api.ActivityIssueCommentCreate {
 			issue, err := s.store.GetIssueByID(ctx, activityCreate.ContainerID)
@@ -59,7 +69,8 @@ func (s *Server) registerActivityRoutes(g *echo.Group) {
 
 	g.GET("/activity", func(c echo.Context) error {
 		ctx := c.Request().Context()
-		activityFind := &api.ActivityFind{}
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		activityFind := &api.ActivityFind{WorkspaceID: &workspaceID}
 		if creatorIDStr := c.QueryParams().Get("user"); creatorIDStr != "" {
 			creatorID, err := strconv.Atoi(creatorIDStr)
 			if err != nil {
@@ -95,12 +106,29 @@ func (s *Server) registerActivityRoutes(g *echo.Group) {
 			}
 			activityFind.Order = &order
 		}
+		paged, cursor, err := getPageToken(c)
+		if err != nil {
+			return err
+		}
+		activityFind.After = cursor
 		activityList, err := s.store.FindActivity(ctx, activityFind)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch activity list").SetInternal(err)
 		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if paged {
+			activityResponse := &api.ActivityResponse{Activities: activityList}
+			// A full page may be followed by more activities.
+			if activityFind.Limit != nil && len(activityList) > 0 && len(activityList) == *activityFind.Limit {
+				last := activityList[len(activityList)-1]
+				activityResponse.NextToken = (&api.PageCursor{Ts: last.CreatedTs, ID: last.ID}).Encode()
+			}
+			if err := jsonapi.MarshalPayload(c.Response().Writer, activityResponse); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal activity list response").SetInternal(err)
+			}
+			return nil
+		}
 		if err := jsonapi.MarshalPayload(c.Response().Writer, activityList); err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal activity list response").SetInternal(err)
 		}
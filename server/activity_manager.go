@@ -53,6 +53,9 @@ func (m *ActivityManager) CreateActivity(ctx context.Context, create *api.Activi
 		if err := m.s.postInboxIssueActivity(ctx, meta.issue, activity.ID); err != nil {
 			return nil, err
 		}
+		if err := m.postInboxDatabaseOwnerActivity(ctx, meta.issue, activity); err != nil {
+			return nil, err
+		}
 	}
 
 	hookFind := &api.ProjectWebhookFind{
@@ -104,6 +107,7 @@ func (m *ActivityManager) getWebhookContext(ctx context.Context, activity *api.A
 	var webhookCtx webhook.Context
 	level := webhook.WebhookInfo
 	title := ""
+	onCall := ""
 	link := fmt.Sprintf("%s:%d/issue/%s", m.s.profile.FrontendHost, m.s.profile.FrontendPort, api.IssueSlug(meta.issue))
 	switch activity.Type {
 	case api.ActivityIssueCreate:
@@ -245,6 +249,22 @@ func (m *ActivityManager) getWebhookContext(ctx context.Context, activity *api.A
 		case api.TaskFailed:
 			level = webhook.WebhookError
 			title = "Task failed - " + task.Name
+			if task.Database != nil {
+				onCall = task.Database.OnCall
+			}
+		}
+	case api.ActivityPipelineStagePause, api.ActivityPipelineStageResume:
+		update := &api.ActivityPipelineStagePauseResumePayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
+			log.Warn("Failed to post webhook event after pausing or resuming the stage, failed to unmarshal payload",
+				zap.String("issue_name", meta.issue.Name),
+				zap.Error(err))
+			return webhookCtx, err
+		}
+		if activity.Type == api.ActivityPipelineStagePause {
+			title = "Stage paused - " + update.StageName
+		} else {
+			title = "Stage resumed - " + update.StageName
 		}
 	}
 
@@ -268,10 +288,130 @@ func (m *ActivityManager) getWebhookContext(ctx context.Context, activity *api.A
 		CreatorID:    updater.ID,
 		CreatorName:  updater.Name,
 		CreatorEmail: updater.Email,
+		OnCall:       onCall,
 	}
 	return webhookCtx, nil
 }
 
+// postInboxDatabaseOwnerActivity posts the task failure activity to the inbox of the owner of the task database.
+// The owner is skipped if the activity has been posted to the owner as the issue creator, assignee or subscriber.
+func (m *ActivityManager) postInboxDatabaseOwnerActivity(ctx context.Context, issue *api.Issue, activity *api.Activity) error {
+	if activity.Type != api.ActivityPipelineTaskStatusUpdate {
+		return nil
+	}
+	update := &api.ActivityPipelineTaskStatusUpdatePayload{}
+	if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal activity payload: %s", activity.Payload)
+	}
+	if update.NewStatus != api.TaskFailed {
+		return nil
+	}
+	task, err := m.store.GetTaskByID(ctx, update.TaskID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find task with ID %d", update.TaskID)
+	}
+	if task == nil || task.Database == nil {
+		return nil
+	}
+
+	ownerID := task.Database.OwnerID
+	if ownerID == api.UnknownID || ownerID == api.SystemBotID || ownerID == issue.CreatorID || ownerID == issue.AssigneeID {
+		return nil
+	}
+	for _, subscriber := range issue.SubscriberList {
+		if subscriber.ID == ownerID {
+			return nil
+		}
+	}
+	if _, err := m.store.CreateInbox(ctx, &api.InboxCreate{
+		ReceiverID: ownerID,
+		ActivityID: activity.ID,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to post activity to database owner inbox: %d", ownerID)
+	}
+	return nil
+}
+
+// notifyDatabaseAnomaly creates the activity for the newly detected database anomaly, and posts the activity to
+// the inbox of the database owner and the project webhooks. The webhook message includes the database on-call.
+func (m *ActivityManager) notifyDatabaseAnomaly(ctx context.Context, database *api.Database, anomaly *api.Anomaly) error {
+	payload, err := json.Marshal(api.ActivityDatabaseAnomalyCreatePayload{
+		DatabaseID:   database.ID,
+		DatabaseName: database.Name,
+		AnomalyType:  anomaly.Type,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal database anomaly activity payload")
+	}
+	activity, err := m.store.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: database.ProjectID,
+		Type:        api.ActivityDatabaseAnomalyCreate,
+		Level:       api.ActivityWarn,
+		Comment:     fmt.Sprintf("Detected anomaly %q on database %q.", anomaly.Type, database.Name),
+		Payload:     string(payload),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create database anomaly activity")
+	}
+
+	if database.OwnerID != api.UnknownID && database.OwnerID != api.SystemBotID {
+		if _, err := m.store.CreateInbox(ctx, &api.InboxCreate{
+			ReceiverID: database.OwnerID,
+			ActivityID: activity.ID,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to post activity to database owner inbox: %d", database.OwnerID)
+		}
+	}
+
+	webhookList, err := m.store.FindProjectWebhook(ctx, &api.ProjectWebhookFind{
+		ProjectID:    &database.ProjectID,
+		ActivityType: &activity.Type,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find project webhook for database anomaly: %s", database.Name)
+	}
+	if len(webhookList) == 0 {
+		return nil
+	}
+	creator, err := m.store.GetPrincipalByID(ctx, api.SystemBotID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find system bot for posting webhook event")
+	}
+	webhookCtx := webhook.Context{
+		Level:        webhook.WebhookWarn,
+		ActivityType: string(activity.Type),
+		Title:        "Database anomaly detected - " + database.Name,
+		Description:  activity.Comment,
+		Link:         fmt.Sprintf("%s:%d/db/%s", m.s.profile.FrontendHost, m.s.profile.FrontendPort, api.DatabaseSlug(database)),
+		CreatorID:    creator.ID,
+		CreatorName:  creator.Name,
+		CreatorEmail: creator.Email,
+		Project: &webhook.Project{
+			ID:   database.ProjectID,
+			Name: database.Project.Name,
+		},
+		OnCall: database.OnCall,
+	}
+	// Call external webhook endpoint in Go routine to avoid blocking the anomaly scanner.
+	go func() {
+		for _, hook := range webhookList {
+			webhookCtx.URL = hook.URL
+			webhookCtx.CreatedTs = time.Now().Unix()
+			if err := webhook.Post(hook.Type, webhookCtx); err != nil {
+				// The external webhook endpoint might be invalid which is out of our code control, so we just emit a warning
+				log.Warn("Failed to post webhook event after detecting database anomaly",
+					zap.String("webhook_type", hook.Type),
+					zap.String("webhook_name", hook.Name),
+					zap.String("database_name", database.Name),
+					zap.String("anomaly_type", string(anomaly.Type)),
+					zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
 func shouldPostInbox(activity *api.Activity, createType api.ActivityType) (bool, error) {
 	switch createType {
 	case api.ActivityIssueCreate:
@@ -286,6 +426,10 @@ func shouldPostInbox(activity *api.Activity, createType api.ActivityType) (bool,
 		return true, nil
 	case api.ActivityPipelineTaskEarliestAllowedTimeUpdate:
 		return true, nil
+	case api.ActivityPipelineTaskCheckResultSuppress:
+		return true, nil
+	case api.ActivityPipelineStagePause, api.ActivityPipelineStageResume:
+		return true, nil
 	case api.ActivityPipelineTaskStatusUpdate:
 		update := new(api.ActivityPipelineTaskStatusUpdatePayload)
 		if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
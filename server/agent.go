@@ -0,0 +1,412 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/agent"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// agentTunnelTimeout is the time to wait for the agent to open the tunnel connection after the dial command.
+	agentTunnelTimeout = 30 * time.Second
+	// agentDiskTimeout is the time to wait for the agent to answer the disk command.
+	agentDiskTimeout = 10 * time.Second
+)
+
+// AgentManager manages the connected instance agents, and relays the database connections of the instances in agent mode through them.
+// The drivers dial the database through the connected agent in process instead of the network, so no port is opened for the relay,
+// and the instance address is kept for the TLS server name.
+type AgentManager struct {
+	server *Server
+
+	mu sync.RWMutex
+	// agentInstanceSet is the set of the instances in agent mode, whose connections must go through the agents.
+	agentInstanceSet map[int]bool
+	// sessionMap is the map from the instance ID to the connected agent session.
+	sessionMap map[int]*agentSession
+}
+
+// agentSession is the control connection of a connected agent.
+type agentSession struct {
+	instanceID int
+	conn       net.Conn
+	writeMu    sync.Mutex
+
+	mu sync.Mutex
+	// pendingMap is the map from the connection ID to the channel waiting for the tunnel connection.
+	pendingMap map[string]chan agentTunnel
+	closeOnce  sync.Once
+
+	// diskMu serializes the disk commands, so that each answer goes to the command waiting on diskCh.
+	diskMu sync.Mutex
+	diskCh chan string
+}
+
+type agentTunnel struct {
+	conn net.Conn
+	err  error
+}
+
+// NewAgentManager creates an agent manager, loading the instances in agent mode.
+func NewAgentManager(ctx context.Context, server *Server) (*AgentManager, error) {
+	m := &AgentManager{
+		server:           server,
+		agentInstanceSet: make(map[int]bool),
+		sessionMap:       make(map[int]*agentSession),
+	}
+	instanceAgentList, err := server.store.FindInstanceAgent(ctx, &api.InstanceAgentFind{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find instance agents")
+	}
+	for _, instanceAgent := range instanceAgentList {
+		m.agentInstanceSet[instanceAgent.InstanceID] = true
+	}
+	return m, nil
+}
+
+// applyAgent sets the dialer of the connection config to dial through the agent if the instance is in agent mode.
+func (m *AgentManager) applyAgent(instanceID int, connCfg *db.ConnectionConfig) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.agentInstanceSet[instanceID] {
+		return nil
+	}
+	if _, ok := m.sessionMap[instanceID]; !ok {
+		return common.Errorf(common.DbConnectionFailure, "the agent of instance %d is not connected", instanceID)
+	}
+	// The agent dials the instance address from its network, so it's not connected through the SSH tunnel.
+	connCfg.SSHConfig = db.SSHConfig{}
+	connCfg.Dialer = func(ctx context.Context, _ string) (net.Conn, error) {
+		return m.dial(ctx, instanceID)
+	}
+	return nil
+}
+
+// dial opens a database connection through the connected agent of the instance.
+// The session is looked up on each dial, so that the opened drivers keep working after the agent reconnects.
+func (m *AgentManager) dial(ctx context.Context, instanceID int) (net.Conn, error) {
+	m.mu.RLock()
+	session := m.sessionMap[instanceID]
+	m.mu.RUnlock()
+	if session == nil {
+		return nil, common.Errorf(common.DbConnectionFailure, "the agent of instance %d is not connected", instanceID)
+	}
+	return m.server.dialAgentTunnel(ctx, session)
+}
+
+// getDiskUsage returns the total and the free bytes of the database disk reported by the connected agent of the instance.
+// It returns false if the instance isn't in agent mode or the agent isn't connected.
+func (m *AgentManager) getDiskUsage(ctx context.Context, instanceID int) (total int64, free int64, ok bool, err error) {
+	m.mu.RLock()
+	session := m.sessionMap[instanceID]
+	m.mu.RUnlock()
+	if session == nil {
+		return 0, 0, false, nil
+	}
+	reply, err := session.requestDisk(ctx)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	total, free, err = agent.ParseDiskReply(reply)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return total, free, true, nil
+}
+
+// isConnected returns whether the agent of the instance is connected.
+func (m *AgentManager) isConnected(instanceID int) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.sessionMap[instanceID]
+	return ok
+}
+
+// enable puts the instance in agent mode.
+func (m *AgentManager) enable(instanceID int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.agentInstanceSet[instanceID] = true
+}
+
+// disable puts the instance out of agent mode and disconnects its agent.
+func (m *AgentManager) disable(instanceID int) {
+	m.mu.Lock()
+	session := m.sessionMap[instanceID]
+	delete(m.agentInstanceSet, instanceID)
+	delete(m.sessionMap, instanceID)
+	m.mu.Unlock()
+	if session != nil {
+		session.close()
+	}
+}
+
+// authenticate returns the instance ID of the agent token in the Authorization header.
+func (m *AgentManager) authenticate(c echo.Context) (int, error) {
+	token := strings.TrimPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if token == "" {
+		return 0, echo.NewHTTPError(http.StatusUnauthorized, "Missing agent token")
+	}
+	tokenHash := hashAgentToken(token)
+	instanceAgent, err := m.server.store.GetInstanceAgent(c.Request().Context(), &api.InstanceAgentFind{TokenHash: &tokenHash})
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusInternalServerError, "Failed to find instance agent").SetInternal(err)
+	}
+	if instanceAgent == nil {
+		return 0, echo.NewHTTPError(http.StatusUnauthorized, "Invalid agent token")
+	}
+	return instanceAgent.InstanceID, nil
+}
+
+func (s *Server) registerAgentRoutes(e *echo.Echo) {
+	// The agent routes are authenticated with the agent token instead of the user session.
+	e.GET(agent.ConnectPath, func(c echo.Context) error {
+		m := s.AgentManager
+		instanceID, err := m.authenticate(c)
+		if err != nil {
+			return err
+		}
+		conn, err := hijackAgentConnection(c)
+		if err != nil {
+			return err
+		}
+		session := &agentSession{
+			instanceID: instanceID,
+			conn:       conn,
+			pendingMap: make(map[string]chan agentTunnel),
+			diskCh:     make(chan string, 1),
+		}
+
+		m.mu.Lock()
+		if !m.agentInstanceSet[instanceID] {
+			m.mu.Unlock()
+			session.close()
+			return nil
+		}
+		// The latest agent wins, e.g. the agent reconnects before the old connection times out.
+		oldSession := m.sessionMap[instanceID]
+		m.sessionMap[instanceID] = session
+		m.mu.Unlock()
+		if oldSession != nil {
+			oldSession.close()
+		}
+		log.Info("Agent connected", zap.Int("instance", instanceID), zap.String("remote", c.RealIP()))
+
+		go session.ping()
+		// Serve the control connection until it's closed.
+		session.readReply()
+
+		m.mu.Lock()
+		if m.sessionMap[instanceID] == session {
+			delete(m.sessionMap, instanceID)
+		}
+		m.mu.Unlock()
+		session.close()
+		log.Info("Agent disconnected", zap.Int("instance", instanceID))
+		return nil
+	})
+
+	e.GET(agent.TunnelPath+":connID", func(c echo.Context) error {
+		m := s.AgentManager
+		instanceID, err := m.authenticate(c)
+		if err != nil {
+			return err
+		}
+		m.mu.RLock()
+		session := m.sessionMap[instanceID]
+		m.mu.RUnlock()
+		if session == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Agent of instance %d is not connected", instanceID))
+		}
+		ch := session.takePending(c.Param("connID"))
+		if ch == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Agent connection not found: %s", c.Param("connID")))
+		}
+
+		if errMsg := c.QueryParam(agent.TunnelErrorParam); errMsg != "" {
+			ch <- agentTunnel{err: errors.Errorf("agent failed to dial: %s", errMsg)}
+			return c.NoContent(http.StatusOK)
+		}
+		conn, err := hijackAgentConnection(c)
+		if err != nil {
+			ch <- agentTunnel{err: err}
+			return err
+		}
+		ch <- agentTunnel{conn: conn}
+		return nil
+	})
+}
+
+// hijackAgentConnection takes over the connection of the upgrade request and switches to the agent protocol.
+func hijackAgentConnection(c echo.Context) (net.Conn, error) {
+	if !strings.EqualFold(c.Request().Header.Get("Upgrade"), agent.UpgradeProtocol) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expect upgrade to %s", agent.UpgradeProtocol))
+	}
+	conn, rw, err := c.Response().Hijack()
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to hijack agent connection").SetInternal(err)
+	}
+	// Clear the deadlines of the HTTP server, the connection lives as long as the agent.
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(rw, "HTTP/1.1 %d %s\r\nConnection: Upgrade\r\nUpgrade: %s\r\n\r\n", http.StatusSwitchingProtocols, http.StatusText(http.StatusSwitchingProtocols), agent.UpgradeProtocol); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return agent.NewBufferedConn(conn, rw.Reader), nil
+}
+
+// dialAgentTunnel asks the agent to dial the instance and waits for the tunnel connection.
+func (s *Server) dialAgentTunnel(ctx context.Context, session *agentSession) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, agentTunnelTimeout)
+	defer cancel()
+	// Read the address on each dial so that the instance changes apply right away.
+	instance, err := s.store.GetInstanceByID(ctx, session.instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, errors.Errorf("instance %d not found", session.instanceID)
+	}
+	address := instance.Host
+	if instance.Port != "" {
+		address = net.JoinHostPort(instance.Host, instance.Port)
+	}
+
+	connID, err := common.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan agentTunnel, 1)
+	session.mu.Lock()
+	session.pendingMap[connID] = ch
+	session.mu.Unlock()
+
+	if err := session.send(fmt.Sprintf("%s %s %s", agent.CommandDial, connID, address)); err != nil {
+		session.takePending(connID)
+		return nil, err
+	}
+	select {
+	case tunnel := <-ch:
+		return tunnel.conn, tunnel.err
+	case <-ctx.Done():
+		if session.takePending(connID) == nil {
+			// The tunnel arrived right at the timeout, close it since nobody is waiting.
+			if tunnel := <-ch; tunnel.conn != nil {
+				tunnel.conn.Close()
+			}
+		}
+		return nil, errors.Errorf("timeout waiting for the agent to dial %s", address)
+	}
+}
+
+// takePending removes and returns the channel waiting for the tunnel connection, or nil if not found.
+func (session *agentSession) takePending(connID string) chan agentTunnel {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	ch, ok := session.pendingMap[connID]
+	if !ok {
+		return nil
+	}
+	delete(session.pendingMap, connID)
+	return ch
+}
+
+func (session *agentSession) send(command string) error {
+	session.writeMu.Lock()
+	defer session.writeMu.Unlock()
+	if err := session.conn.SetWriteDeadline(time.Now().Add(agent.PingInterval)); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(session.conn, "%s\n", command)
+	return err
+}
+
+// ping pings the agent periodically until the session is closed.
+func (session *agentSession) ping() {
+	ticker := time.NewTicker(agent.PingInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := session.send(agent.CommandPing); err != nil {
+			session.close()
+			return
+		}
+	}
+}
+
+// requestDisk sends the disk command and waits for the answer.
+func (session *agentSession) requestDisk(ctx context.Context) (string, error) {
+	session.diskMu.Lock()
+	defer session.diskMu.Unlock()
+	// Drop the late answer of the previous command that timed out.
+	select {
+	case <-session.diskCh:
+	default:
+	}
+	if err := session.send(agent.CommandDisk); err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, agentDiskTimeout)
+	defer cancel()
+	select {
+	case reply := <-session.diskCh:
+		return reply, nil
+	case <-ctx.Done():
+		return "", errors.New("timeout waiting for the agent to answer the disk usage")
+	}
+}
+
+// readReply reads the answers of the agent until the connection is closed or the agent stops answering the pings.
+func (session *agentSession) readReply() {
+	scanner := bufio.NewScanner(session.conn)
+	for {
+		if err := session.conn.SetReadDeadline(time.Now().Add(2 * agent.PingInterval)); err != nil {
+			return
+		}
+		if !scanner.Scan() {
+			return
+		}
+		if line := scanner.Text(); strings.HasPrefix(line, agent.CommandDisk+" ") {
+			// Nobody is waiting if the command has timed out.
+			select {
+			case session.diskCh <- line:
+			default:
+			}
+		}
+	}
+}
+
+func (session *agentSession) close() {
+	session.closeOnce.Do(func() {
+		session.conn.Close()
+	})
+}
+
+// hashAgentToken returns the hex encoded SHA-256 digest of the agent token.
+func hashAgentToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
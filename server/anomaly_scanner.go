@@ -45,6 +45,10 @@ func (s *AnomalyScanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	for {
 		select {
 		case <-ticker.C:
+			if s.server.isInMaintenance() {
+				log.Debug("Anomaly scanner paused in maintenance mode")
+				continue
+			}
 			log.Debug("New anomaly scanner round started...")
 			func() {
 				defer func() {
@@ -87,6 +91,10 @@ func (s *AnomalyScanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 					return
 				}
 
+				if err := s.server.store.DeleteInstanceDiskUsageBefore(ctx, time.Now().Add(-instanceDiskUsageWindow).Unix()); err != nil {
+					log.Error("Failed to delete the expired instance disk usage", zap.Error(err))
+				}
+
 				for _, instance := range instanceList {
 					foundEnv := false
 					for _, env := range envList {
@@ -121,6 +129,7 @@ func (s *AnomalyScanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 						}()
 
 						s.checkInstanceAnomaly(ctx, instance)
+						s.checkInstanceDiskAnomaly(ctx, instance)
 
 						databaseFind := &api.DatabaseFind{
 							InstanceID: &instance.ID,
@@ -242,7 +251,7 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 				zap.String("type", string(api.AnomalyDatabaseConnection)),
 				zap.Error(err))
 		} else {
-			if _, err = s.server.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+			if err = s.server.upsertDatabaseAnomaly(ctx, database, &api.AnomalyUpsert{
 				CreatorID:  api.SystemBotID,
 				InstanceID: instance.ID,
 				DatabaseID: &database.ID,
@@ -331,7 +340,7 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 						zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
 						zap.Error(err))
 				} else {
-					if _, err = s.server.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+					if err = s.server.upsertDatabaseAnomaly(ctx, database, &api.AnomalyUpsert{
 						CreatorID:  api.SystemBotID,
 						InstanceID: instance.ID,
 						DatabaseID: &database.ID,
@@ -411,7 +420,7 @@ func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.I
 					zap.String("type", string(api.AnomalyDatabaseBackupPolicyViolation)),
 					zap.Error(err))
 			} else {
-				if _, err = s.server.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if err = s.server.upsertDatabaseAnomaly(ctx, database, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.ID,
 					DatabaseID: &database.ID,
@@ -494,7 +503,7 @@ func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.I
 					zap.String("type", string(api.AnomalyDatabaseBackupMissing)),
 					zap.Error(err))
 			} else {
-				if _, err = s.server.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if err = s.server.upsertDatabaseAnomaly(ctx, database, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.ID,
 					DatabaseID: &database.ID,
@@ -523,3 +532,22 @@ func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.I
 		}
 	}
 }
+
+// upsertDatabaseAnomaly upserts the active anomaly of the database, and notifies the database owner if the anomaly is new.
+func (s *Server) upsertDatabaseAnomaly(ctx context.Context, database *api.Database, upsert *api.AnomalyUpsert) error {
+	anomaly, err := s.store.UpsertActiveAnomaly(ctx, upsert)
+	if err != nil {
+		return err
+	}
+	// An active anomaly is patched on every scan, so it's new only if it has never been patched.
+	if anomaly.CreatedTs != anomaly.UpdatedTs {
+		return nil
+	}
+	if err := s.ActivityManager.notifyDatabaseAnomaly(ctx, database, anomaly); err != nil {
+		log.Warn("Failed to notify the new database anomaly",
+			zap.String("database", database.Name),
+			zap.String("type", string(anomaly.Type)),
+			zap.Error(err))
+	}
+	return nil
+}
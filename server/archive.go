@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// getArchivableDatabaseList returns the databases without a successful sync since the cutoff and matching the label selector.
+// A zero cutoff or a nil selector skips the corresponding check.
+func getArchivableDatabaseList(databaseList []*api.Database, cutoffTs int64, selector *api.LabelSelector) ([]*api.Database, error) {
+	var archivableList []*api.Database
+	for _, database := range databaseList {
+		if cutoffTs > 0 && database.LastSuccessfulSyncTs >= cutoffTs {
+			continue
+		}
+		if selector != nil {
+			var labelList []*api.DatabaseLabel
+			if err := json.Unmarshal([]byte(database.Labels), &labelList); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal labels of database %q", database.Name)
+			}
+			labels := make(map[string]string)
+			for _, label := range labelList {
+				labels[label.Key] = label.Value
+			}
+			if !isMatchExpressions(labels, selector.MatchExpressions) {
+				continue
+			}
+		}
+		archivableList = append(archivableList, database)
+	}
+	return archivableList, nil
+}
+
+// getStaleInstanceList returns the instances none of whose databases has synced successfully since the cutoff.
+// An instance without databases is stale if it was created before the cutoff.
+// The instances having databases outside the default project are skipped, since they must be transferred before archiving.
+func getStaleInstanceList(instanceList []*api.Instance, databaseList []*api.Database, cutoffTs int64) []*api.Instance {
+	instanceDatabaseMap := make(map[int][]*api.Database)
+	for _, database := range databaseList {
+		instanceDatabaseMap[database.InstanceID] = append(instanceDatabaseMap[database.InstanceID], database)
+	}
+
+	var staleList []*api.Instance
+	for _, instance := range instanceList {
+		databaseList, ok := instanceDatabaseMap[instance.ID]
+		if !ok {
+			if instance.CreatedTs < cutoffTs {
+				staleList = append(staleList, instance)
+			}
+			continue
+		}
+		stale := true
+		for _, database := range databaseList {
+			if database.ProjectID != api.DefaultProjectID || database.LastSuccessfulSyncTs >= cutoffTs {
+				stale = false
+				break
+			}
+		}
+		if stale {
+			staleList = append(staleList, instance)
+		}
+	}
+	return staleList
+}
+
+// getStaleCutoffTs returns the cutoff timestamp for the stale days, or 0 if the stale days is not specified.
+func getStaleCutoffTs(staleDays int) int64 {
+	if staleDays <= 0 {
+		return 0
+	}
+	return time.Now().AddDate(0, 0, -staleDays).Unix()
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetArchivableDatabaseList(t *testing.T) {
+	databaseList := []*api.Database{
+		{ID: 1, Name: "fresh", LastSuccessfulSyncTs: 200, Labels: `[{"key":"bb.environment","value":"Test"}]`},
+		{ID: 2, Name: "stale_test", LastSuccessfulSyncTs: 50, Labels: `[{"key":"bb.environment","value":"Test"}]`},
+		{ID: 3, Name: "stale_prod", LastSuccessfulSyncTs: 50, Labels: `[{"key":"bb.environment","value":"Prod"}]`},
+	}
+	selector := &api.LabelSelector{
+		MatchExpressions: []*api.LabelSelectorRequirement{
+			{Key: "bb.environment", Operator: api.InOperatorType, Values: []string{"Test"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		cutoffTs int64
+		selector *api.LabelSelector
+		want     []int
+	}{
+		{name: "stale", cutoffTs: 100, want: []int{2, 3}},
+		{name: "label", selector: selector, want: []int{1, 2}},
+		{name: "stale and label", cutoffTs: 100, selector: selector, want: []int{2}},
+	}
+	for _, test := range tests {
+		archivableList, err := getArchivableDatabaseList(databaseList, test.cutoffTs, test.selector)
+		require.NoError(t, err)
+		var idList []int
+		for _, database := range archivableList {
+			idList = append(idList, database.ID)
+		}
+		assert.Equal(t, test.want, idList, test.name)
+	}
+}
+
+func TestGetStaleInstanceList(t *testing.T) {
+	instanceList := []*api.Instance{
+		{ID: 1, Name: "fresh"},
+		{ID: 2, Name: "stale"},
+		{ID: 3, Name: "stale with project database"},
+		{ID: 4, Name: "old without database", CreatedTs: 50},
+		{ID: 5, Name: "new without database", CreatedTs: 200},
+	}
+	databaseList := []*api.Database{
+		{InstanceID: 1, ProjectID: api.DefaultProjectID, LastSuccessfulSyncTs: 50},
+		{InstanceID: 1, ProjectID: api.DefaultProjectID, LastSuccessfulSyncTs: 200},
+		{InstanceID: 2, ProjectID: api.DefaultProjectID, LastSuccessfulSyncTs: 50},
+		{InstanceID: 3, ProjectID: 101, LastSuccessfulSyncTs: 50},
+	}
+
+	var idList []int
+	for _, instance := range getStaleInstanceList(instanceList, databaseList, 100) {
+		idList = append(idList, instance.ID)
+	}
+	assert.Equal(t, []int{2, 4}, idList)
+}
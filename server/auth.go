@@ -161,7 +161,7 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 						Name:     userInfo.Name,
 					}
 					var httpError *echo.HTTPError
-					user, httpError = trySignUp(ctx, s, signUp, api.SystemBotID)
+					user, httpError = trySignUp(ctx, s, signUp, api.DefaultWorkspaceID, api.SystemBotID)
 					if httpError != nil {
 						return httpError
 					}
@@ -212,7 +212,7 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sign up request").SetInternal(err)
 		}
 
-		user, err := trySignUp(ctx, s, signUp, api.SystemBotID)
+		user, err := trySignUp(ctx, s, signUp, api.DefaultWorkspaceID, api.SystemBotID)
 		if err != nil {
 			return err
 		}
@@ -229,7 +229,11 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 	})
 }
 
-func trySignUp(ctx context.Context, s *Server, signUp *api.SignUp, creatorID int) (*api.Principal, *echo.HTTPError) {
+func trySignUp(ctx context.Context, s *Server, signUp *api.SignUp, workspaceID int, creatorID int) (*api.Principal, *echo.HTTPError) {
+	if err := s.seatCountGuard(ctx); err != nil {
+		return nil, err
+	}
+
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(signUp.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate password hash").SetInternal(err)
@@ -253,14 +257,15 @@ func trySignUp(ctx context.Context, s *Server, signUp *api.SignUp, creatorID int
 
 	findRole := api.Owner
 	find := &api.MemberFind{
-		Role: &findRole,
+		Role:        &findRole,
+		WorkspaceID: &workspaceID,
 	}
 	memberList, err := s.store.FindMember(ctx, find)
 	if err != nil {
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to sign up").SetInternal(err)
 	}
 
-	// Grant the member Owner role if there is no existing Owner member.
+	// Grant the member Owner role if there is no existing Owner member in the workspace.
 	role := api.Developer
 	if len(memberList) == 0 {
 		role = api.Owner
@@ -270,6 +275,7 @@ func trySignUp(ctx context.Context, s *Server, signUp *api.SignUp, creatorID int
 		Status:      api.Active,
 		Role:        role,
 		PrincipalID: user.ID,
+		WorkspaceID: workspaceID,
 	}
 
 	member, err := s.store.CreateMember(ctx, memberCreate)
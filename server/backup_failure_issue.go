@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// backupFailureIssueThreshold is the number of the consecutive failed automatic backups to create a maintenance issue.
+const backupFailureIssueThreshold = 2
+
+// getConsecutiveFailedAutomaticBackupList returns the failed automatic backups since the last successful one, the latest first.
+// The manual backups and the backups in progress are ignored.
+func getConsecutiveFailedAutomaticBackupList(backupList []*api.Backup) []*api.Backup {
+	var automaticList []*api.Backup
+	for _, backup := range backupList {
+		if backup.Type == api.BackupTypeAutomatic && backup.Status != api.BackupStatusPendingCreate {
+			automaticList = append(automaticList, backup)
+		}
+	}
+	sort.SliceStable(automaticList, func(i, j int) bool {
+		return automaticList[i].CreatedTs > automaticList[j].CreatedTs
+	})
+
+	var failedList []*api.Backup
+	for _, backup := range automaticList {
+		if backup.Status != api.BackupStatusFailed {
+			break
+		}
+		failedList = append(failedList, backup)
+	}
+	return failedList
+}
+
+// getBackupFailureIssueDescription returns the issue description with the failure logs of the backups.
+func getBackupFailureIssueDescription(database *api.Database, failedList []*api.Backup) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The last %d automatic backups of database db:%s/%s failed in a row. ", len(failedList), api.EnvSlug(database.Instance.Environment), database.Name)
+	b.WriteString("Please fix the cause, and approve the task to resolve the issue.\n\n### Failure logs\n")
+	for _, backup := range failedList {
+		comment := backup.Comment
+		if comment == "" {
+			comment = "(no log)"
+		}
+		fmt.Fprintf(&b, "\n- Backup %q at %s\n\n```\n%s\n```\n", backup.Name, time.Unix(backup.CreatedTs, 0).UTC().Format(time.RFC3339), comment)
+	}
+	return b.String()
+}
+
+// createBackupFailureIssueIfNeeded creates a maintenance issue assigned to the database owner when the automatic backup
+// of the database fails for the backupFailureIssueThreshold-th time in a row. It's a no-op if an open issue has been
+// created for the database already.
+func (s *Server) createBackupFailureIssueIfNeeded(ctx context.Context, databaseID int) error {
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &databaseID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find database %d", databaseID)
+	}
+	if database == nil {
+		return nil
+	}
+
+	backupList, err := s.store.FindBackup(ctx, &api.BackupFind{DatabaseID: &database.ID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find backups of database %q", database.Name)
+	}
+	// Only the failure reaching the threshold creates the issue, so that the issue isn't recreated on the further
+	// failures after being resolved or canceled.
+	failedList := getConsecutiveFailedAutomaticBackupList(backupList)
+	if len(failedList) != backupFailureIssueThreshold {
+		return nil
+	}
+
+	issueList, err := s.store.FindIssueStripped(ctx, &api.IssueFind{
+		ProjectID:  &database.ProjectID,
+		StatusList: []api.IssueStatus{api.IssueOpen},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find open issues in project %d", database.ProjectID)
+	}
+	for _, issue := range issueList {
+		payload := &api.IssuePayload{}
+		if err := json.Unmarshal([]byte(issue.Payload), payload); err != nil {
+			continue
+		}
+		if payload.BackupFailureDatabaseID == database.ID {
+			return nil
+		}
+	}
+
+	createContext, err := json.Marshal(&api.DatabaseMaintenanceContext{
+		DatabaseID: database.ID,
+		TaskName:   fmt.Sprintf("Fix the automatic backup of database %q", database.Name),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal issue create context")
+	}
+	payload, err := json.Marshal(&api.IssuePayload{BackupFailureDatabaseID: database.ID})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal issue payload")
+	}
+
+	// Assign the issue to the database owner if possible, otherwise to the default assignee with the owner subscribed.
+	assigneeID := api.SystemBotID
+	var subscriberIDList []int
+	if ownerID := database.OwnerID; ownerID != api.UnknownID && ownerID != api.SystemBotID {
+		ok, err := s.canPrincipalBeAssignee(ctx, ownerID, database.Instance.EnvironmentID, database.ProjectID, api.IssueGeneral)
+		if err != nil && common.ErrorCode(err) != common.NotFound {
+			return errors.Wrapf(err, "failed to check if the owner of database %q can be the assignee", database.Name)
+		}
+		if ok {
+			assigneeID = ownerID
+		} else {
+			subscriberIDList = append(subscriberIDList, ownerID)
+		}
+	}
+
+	issueCreate := &api.IssueCreate{
+		ProjectID:        database.ProjectID,
+		Name:             fmt.Sprintf("[Backup] Fix the failed automatic backups of database %q", database.Name),
+		Type:             api.IssueGeneral,
+		Description:      getBackupFailureIssueDescription(database, failedList),
+		AssigneeID:       assigneeID,
+		SubscriberIDList: subscriberIDList,
+		Payload:          string(payload),
+		CreateContext:    string(createContext),
+	}
+	if _, err := s.createIssue(ctx, issueCreate, api.SystemBotID); err != nil {
+		return errors.Wrapf(err, "failed to create issue for the failed backups of database %q", database.Name)
+	}
+	return nil
+}
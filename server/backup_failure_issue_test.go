@@ -0,0 +1,74 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetConsecutiveFailedAutomaticBackupList(t *testing.T) {
+	backup := func(id int, backupType api.BackupType, status api.BackupStatus) *api.Backup {
+		return &api.Backup{ID: id, CreatedTs: int64(id), Type: backupType, Status: status}
+	}
+	tests := []struct {
+		backupList []*api.Backup
+		want       []int
+	}{
+		{
+			backupList: nil,
+			want:       nil,
+		},
+		{
+			backupList: []*api.Backup{
+				backup(1, api.BackupTypeAutomatic, api.BackupStatusFailed),
+				backup(2, api.BackupTypeAutomatic, api.BackupStatusDone),
+			},
+			want: nil,
+		},
+		// The manual backups and the backups in progress don't break the failures.
+		{
+			backupList: []*api.Backup{
+				backup(1, api.BackupTypeAutomatic, api.BackupStatusDone),
+				backup(2, api.BackupTypeAutomatic, api.BackupStatusFailed),
+				backup(3, api.BackupTypeManual, api.BackupStatusDone),
+				backup(5, api.BackupTypeAutomatic, api.BackupStatusPendingCreate),
+				backup(4, api.BackupTypeAutomatic, api.BackupStatusFailed),
+			},
+			want: []int{4, 2},
+		},
+	}
+
+	for _, test := range tests {
+		var got []int
+		for _, backup := range getConsecutiveFailedAutomaticBackupList(test.backupList) {
+			got = append(got, backup.ID)
+		}
+		require.Equal(t, test.want, got)
+	}
+}
+
+func TestGetBackupFailureIssueDescription(t *testing.T) {
+	database := &api.Database{
+		Name: "orders",
+		Instance: &api.Instance{
+			Environment: &api.Environment{Name: "Prod"},
+		},
+	}
+	failedList := []*api.Backup{
+		{Name: "orders-auto-2", CreatedTs: 1664845200, Comment: "failed to dump database"},
+		{Name: "orders-auto-1", CreatedTs: 1664758800},
+	}
+	want := "The last 2 automatic backups of database db:prod/orders failed in a row. " +
+		"Please fix the cause, and approve the task to resolve the issue.\n\n### Failure logs\n" +
+		"\n- Backup \"orders-auto-2\" at 2022-10-04T01:00:00Z\n\n```\nfailed to dump database\n```\n" +
+		"\n- Backup \"orders-auto-1\" at 2022-10-03T01:00:00Z\n\n```\n(no log)\n```\n"
+	require.Equal(t, want, getBackupFailureIssueDescription(database, failedList))
+
+	// The database in the description is resolved as a reference of the issue.
+	textList := parseIssueReferenceText(want)
+	require.Equal(t, []*issueReferenceText{
+		{referenceType: api.IssueReferenceDatabase, text: "db:prod/orders", environment: "prod", database: "orders"},
+	}, textList)
+}
@@ -50,6 +50,10 @@ func (r *BackupRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	for {
 		select {
 		case <-ticker.C:
+			if r.server.isInMaintenance() {
+				log.Debug("Auto backup runner paused in maintenance mode")
+				continue
+			}
 			log.Debug("New auto backup round started...")
 			func() {
 				defer func() {
@@ -64,6 +68,10 @@ func (r *BackupRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
 				r.startAutoBackups(ctx, runningTasks, &mu)
 				r.downloadBinlogFiles(ctx)
 				r.purgeExpiredBackupData(ctx)
+				r.server.purgeExpiredTaskRunArtifact(ctx)
+				r.server.purgeExpiredQueryAuditLog(ctx)
+				r.server.purgeExpiredIdempotencyKey(ctx)
+				r.server.expireDBQueryGrant(ctx)
 			}()
 		case <-ctx.Done(): // if cancel() execute
 			r.backupWg.Wait()
@@ -190,12 +198,15 @@ func (r *BackupRunner) purgeBackup(ctx context.Context, backup *api.Backup) erro
 		return errors.Wrapf(err, "failed to update status for deleted backup %q for database with ID %d", backup.Name, backup.DatabaseID)
 	}
 
-	backupFilePath := getBackupAbsFilePath(r.server.profile.DataDir, backup.DatabaseID, backup.Name)
-	if err := os.Remove(backupFilePath); err != nil {
-		log.Error("Failed to delete an expired backup file.", zap.String("path", backupFilePath), zap.Error(err))
-		return errors.Wrapf(err, "failed to delete an expired backup file %q", backupFilePath)
+	backend, err := r.server.getStorageBackend(backup.StorageBackend)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete an expired backup file %q", backup.Path)
+	}
+	if err := backend.Delete(ctx, backup.Path); err != nil {
+		log.Error("Failed to delete an expired backup file.", zap.String("storage_backend", string(backup.StorageBackend)), zap.String("path", backup.Path), zap.Error(err))
+		return errors.Wrapf(err, "failed to delete an expired backup file %q", backup.Path)
 	}
-	log.Info("Deleted expired backup file.", zap.String("path", backupFilePath))
+	log.Info("Deleted expired backup file.", zap.String("storage_backend", string(backup.StorageBackend)), zap.String("path", backup.Path))
 
 	return nil
 }
@@ -237,7 +248,7 @@ func (r *BackupRunner) downloadBinlogFilesForInstance(ctx context.Context, insta
 	}
 	defer driver.Close(ctx)
 
-	mysqlDriver, ok := driver.(*mysql.Driver)
+	mysqlDriver, ok := unwrapDriver(driver).(*mysql.Driver)
 	if !ok {
 		log.Error("Failed to cast driver to mysql.Driver", zap.String("instance", instance.Name))
 		return
@@ -328,7 +339,7 @@ func (s *Server) scheduleBackupTask(ctx context.Context, database *api.Database,
 		CreatorID:               creatorID,
 		DatabaseID:              database.ID,
 		Name:                    backupName,
-		StorageBackend:          s.profile.BackupStorageBackend,
+		StorageBackend:          s.getInstanceBackupStorageBackend(database.Instance),
 		Type:                    backupType,
 		Path:                    path,
 		MigrationHistoryVersion: migrationHistoryVersion,
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/storage"
+	"github.com/bytebase/bytebase/plugin/storage/gcs"
+	"github.com/bytebase/bytebase/plugin/storage/local"
+	"github.com/bytebase/bytebase/plugin/storage/s3"
+)
+
+// initStorageBackends creates the storage backends for the backups and the task run artifacts.
+// The local backend storing the objects in the data dir is always available, and the cloud backend is available
+// if the server is configured with the backup bucket.
+func (s *Server) initStorageBackends(ctx context.Context) error {
+	s.storageBackends = map[api.BackupStorageBackend]storage.Backend{
+		api.BackupStorageBackendLocal: local.NewClient(s.profile.DataDir),
+	}
+	if s.profile.BackupBucket == "" {
+		return nil
+	}
+
+	// The GCS HMAC keys are stored in the same format as the AWS credentials file.
+	credentials, err := s3.GetCredentialsFromFile(ctx, s.profile.BackupCredentialFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to get credentials from file")
+	}
+	switch s.profile.BackupStorageBackend {
+	case api.BackupStorageBackendS3:
+		client, err := s3.NewClient(ctx, s.profile.BackupRegion, s.profile.BackupBucket, credentials)
+		if err != nil {
+			return errors.Wrap(err, "failed to create AWS S3 client")
+		}
+		s.storageBackends[api.BackupStorageBackendS3] = client
+	case api.BackupStorageBackendGCS:
+		client, err := gcs.NewClient(ctx, s.profile.BackupBucket, credentials)
+		if err != nil {
+			return errors.Wrap(err, "failed to create GCS client")
+		}
+		s.storageBackends[api.BackupStorageBackendGCS] = client
+	default:
+		return errors.Errorf("backup bucket is not supported for storage backend %q", s.profile.BackupStorageBackend)
+	}
+	return nil
+}
+
+// getStorageBackend returns the storage backend, and errors if it's not configured for the server.
+func (s *Server) getStorageBackend(backend api.BackupStorageBackend) (storage.Backend, error) {
+	if v, ok := s.storageBackends[backend]; ok {
+		return v, nil
+	}
+	return nil, errors.Errorf("storage backend %q is not configured for the server", backend)
+}
+
+// getDefaultStorageBackend returns the storage backend configured for the server.
+func (s *Server) getDefaultStorageBackend() api.BackupStorageBackend {
+	if s.profile.BackupStorageBackend == "" {
+		return api.BackupStorageBackendLocal
+	}
+	return s.profile.BackupStorageBackend
+}
+
+// getInstanceBackupStorageBackend returns the storage backend for the backups of the databases in the instance.
+func (s *Server) getInstanceBackupStorageBackend(instance *api.Instance) api.BackupStorageBackend {
+	if instance.BackupStorageBackend != "" {
+		return instance.BackupStorageBackend
+	}
+	return s.getDefaultStorageBackend()
+}
+
+// validateInstanceBackupStorageBackend makes sure the storage backend of the instance is configured for the server.
+// The empty backend means the one configured for the server.
+func (s *Server) validateInstanceBackupStorageBackend(backend api.BackupStorageBackend) error {
+	if backend == "" {
+		return nil
+	}
+	if _, err := s.getStorageBackend(backend); err != nil {
+		return err
+	}
+	return nil
+}
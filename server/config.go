@@ -68,8 +68,15 @@ type Profile struct {
 	PgURL string
 	// MetricConnectionKey is the connection key for metric.
 	MetricConnectionKey string
-	// DisableMetric will disable the metric collector.
+	// DisableMetric will disable sending the metrics to Bytebase, the metrics are still collected for the usage dashboard.
 	DisableMetric bool
+	// SecretKey is the optional key signing the JWT auth token, which overrides the one generated on the first start.
+	SecretKey string
+	// SMTPPassword is the optional password of the SMTP server, which overrides the one in the SMTP setting.
+	SMTPPassword string
+	// EnableTaskCheckCommand allows the custom task check plugins to run commands on the host,
+	// since the workspace owner configuring the plugins isn't necessarily the host administrator.
+	EnableTaskCheckCommand bool
 }
 
 func (prof *Profile) useEmbedDB() bool {
@@ -0,0 +1,554 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// defaultDataDiffChunkSize is the default expected row count of each chunk.
+	defaultDataDiffChunkSize = 10000
+	// maxDataDiffChunkCount limits the memory used by the chunk checksums of a table.
+	maxDataDiffChunkCount = 100000
+	// maxDataDiffJobCount is the number of data diff jobs kept in memory, the oldest finished jobs are dropped beyond it.
+	maxDataDiffJobCount = 100
+)
+
+func (s *Server) registerDataDiffRoutes(g *echo.Group) {
+	g.POST("/data-diff", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		dataDiffCreate := &api.DataDiffCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, dataDiffCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create data diff request").SetInternal(err)
+		}
+
+		dataDiffCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		if dataDiffCreate.ChunkSize < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Chunk size must not be negative, got %d", dataDiffCreate.ChunkSize))
+		}
+		if dataDiffCreate.ChunkSize == 0 {
+			dataDiffCreate.ChunkSize = defaultDataDiffChunkSize
+		}
+		if dataDiffCreate.SourceDatabaseID == dataDiffCreate.TargetDatabaseID {
+			return echo.NewHTTPError(http.StatusBadRequest, "Source and target database must be different")
+		}
+
+		sourceDatabase, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &dataDiffCreate.SourceDatabaseID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", dataDiffCreate.SourceDatabaseID)).SetInternal(err)
+		}
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		if sourceDatabase == nil || sourceDatabase.Instance.WorkspaceID != workspaceID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", dataDiffCreate.SourceDatabaseID))
+		}
+		targetDatabase, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &dataDiffCreate.TargetDatabaseID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", dataDiffCreate.TargetDatabaseID)).SetInternal(err)
+		}
+		if targetDatabase == nil || targetDatabase.Instance.WorkspaceID != workspaceID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", dataDiffCreate.TargetDatabaseID))
+		}
+		// The checksums are calculated on the values in text format, which are only comparable from the same engine.
+		if sourceDatabase.Instance.Engine != targetDatabase.Instance.Engine {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot compare data between %s and %s databases", sourceDatabase.Instance.Engine, targetDatabase.Instance.Engine))
+		}
+		if !isDataDiffSupported(sourceDatabase.Instance.Engine) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Data diff is not supported for %s", sourceDatabase.Instance.Engine))
+		}
+
+		dataDiff := s.DataDiffManager.Create(dataDiffCreate, sourceDatabase, targetDatabase)
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dataDiff); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create data diff response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/data-diff/:dataDiffID", func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("dataDiffID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("dataDiffID"))).SetInternal(err)
+		}
+
+		dataDiff := s.DataDiffManager.Get(id)
+		if dataDiff == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Data diff ID not found: %d", id))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dataDiff); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal data diff ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+func isDataDiffSupported(engine db.Type) bool {
+	switch engine {
+	case db.MySQL, db.TiDB, db.Postgres:
+		return true
+	}
+	return false
+}
+
+// NewDataDiffManager creates a data diff manager.
+func NewDataDiffManager(server *Server) *DataDiffManager {
+	return &DataDiffManager{
+		server: server,
+	}
+}
+
+// DataDiffManager runs the data diff jobs in the background and keeps them in memory.
+type DataDiffManager struct {
+	server *Server
+
+	mu      sync.RWMutex
+	nextID  int
+	jobList []*api.DataDiff
+}
+
+// Create creates a data diff job and starts comparing the data in the background.
+func (m *DataDiffManager) Create(create *api.DataDiffCreate, source, target *api.Database) *api.DataDiff {
+	now := time.Now().Unix()
+
+	m.mu.Lock()
+	m.nextID++
+	dataDiff := &api.DataDiff{
+		ID:               m.nextID,
+		CreatorID:        create.CreatorID,
+		CreatedTs:        now,
+		UpdatedTs:        now,
+		SourceDatabaseID: source.ID,
+		TargetDatabaseID: target.ID,
+		ChunkSize:        create.ChunkSize,
+		Status:           api.DataDiffRunning,
+		Progress: api.Progress{
+			CreatedTs: now,
+			UpdatedTs: now,
+		},
+		TableResultList: []*api.DataDiffTableResult{},
+	}
+	m.jobList = append(m.jobList, dataDiff)
+	m.evictLocked()
+	m.mu.Unlock()
+
+	// The job outlives the request, so it doesn't inherit the request context.
+	go m.run(context.Background(), dataDiff.ID, create, source, target)
+
+	return m.Get(dataDiff.ID)
+}
+
+// Get returns a snapshot of the data diff job, nil if not found.
+func (m *DataDiffManager) Get(id int) *api.DataDiff {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, dataDiff := range m.jobList {
+		if dataDiff.ID == id {
+			snapshot := *dataDiff
+			snapshot.TableResultList = append([]*api.DataDiffTableResult{}, dataDiff.TableResultList...)
+			return &snapshot
+		}
+	}
+	return nil
+}
+
+// evictLocked drops the oldest finished jobs beyond maxDataDiffJobCount.
+func (m *DataDiffManager) evictLocked() {
+	for i := 0; len(m.jobList) > maxDataDiffJobCount && i < len(m.jobList); {
+		if m.jobList[i].Status == api.DataDiffRunning {
+			i++
+			continue
+		}
+		m.jobList = append(m.jobList[:i], m.jobList[i+1:]...)
+	}
+}
+
+func (m *DataDiffManager) update(id int, f func(dataDiff *api.DataDiff)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, dataDiff := range m.jobList {
+		if dataDiff.ID == id {
+			f(dataDiff)
+			dataDiff.UpdatedTs = time.Now().Unix()
+			return
+		}
+	}
+}
+
+func (m *DataDiffManager) run(ctx context.Context, id int, create *api.DataDiffCreate, source, target *api.Database) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			log.Error("Data diff PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+			m.fail(id, err)
+		}
+	}()
+
+	if err := m.diffDatabase(ctx, id, create, source, target); err != nil {
+		log.Warn("Failed to compare database data",
+			zap.Int("id", id),
+			zap.String("source", source.Name),
+			zap.String("target", target.Name),
+			zap.Error(err))
+		m.fail(id, err)
+		return
+	}
+	m.update(id, func(dataDiff *api.DataDiff) {
+		dataDiff.Status = api.DataDiffDone
+	})
+}
+
+func (m *DataDiffManager) fail(id int, err error) {
+	m.update(id, func(dataDiff *api.DataDiff) {
+		dataDiff.Status = api.DataDiffFailed
+		dataDiff.Error = err.Error()
+	})
+}
+
+func (m *DataDiffManager) diffDatabase(ctx context.Context, id int, create *api.DataDiffCreate, source, target *api.Database) error {
+	sourceTableMap, err := m.getTableMap(ctx, source.ID)
+	if err != nil {
+		return err
+	}
+	targetTableMap, err := m.getTableMap(ctx, target.ID)
+	if err != nil {
+		return err
+	}
+
+	tableNameList := create.TableList
+	if len(tableNameList) == 0 {
+		for name := range sourceTableMap {
+			tableNameList = append(tableNameList, name)
+		}
+		for name := range targetTableMap {
+			if _, ok := sourceTableMap[name]; !ok {
+				tableNameList = append(tableNameList, name)
+			}
+		}
+		sort.Strings(tableNameList)
+	}
+	m.update(id, func(dataDiff *api.DataDiff) {
+		dataDiff.Progress.TotalUnit = int64(len(tableNameList))
+		dataDiff.Progress.UpdatedTs = time.Now().Unix()
+	})
+
+	sourceDriver, err := m.server.tryGetReadOnlyDatabaseDriver(ctx, source.Instance, source.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect source database %q", source.Name)
+	}
+	defer sourceDriver.Close(ctx)
+	sourceDB, err := sourceDriver.GetDBConnection(ctx, source.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect source database %q", source.Name)
+	}
+	targetDriver, err := m.server.tryGetReadOnlyDatabaseDriver(ctx, target.Instance, target.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect target database %q", target.Name)
+	}
+	defer targetDriver.Close(ctx)
+	targetDB, err := targetDriver.GetDBConnection(ctx, target.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect target database %q", target.Name)
+	}
+
+	for _, name := range tableNameList {
+		result, err := m.diffTable(ctx, source.Instance.Engine, sourceDB, targetDB, sourceTableMap[name], targetTableMap[name], create.ChunkSize)
+		if err != nil {
+			result = &api.DataDiffTableResult{
+				Status: api.DataDiffTableError,
+				Error:  err.Error(),
+			}
+		}
+		result.Table = name
+		m.update(id, func(dataDiff *api.DataDiff) {
+			dataDiff.TableResultList = append(dataDiff.TableResultList, result)
+			dataDiff.Progress.CompletedUnit++
+			dataDiff.Progress.UpdatedTs = time.Now().Unix()
+		})
+	}
+	return nil
+}
+
+func (m *DataDiffManager) getTableMap(ctx context.Context, databaseID int) (map[string]*api.Table, error) {
+	tableList, err := m.server.store.FindTable(ctx, &api.TableFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find tables of database %d", databaseID)
+	}
+	tableMap := make(map[string]*api.Table)
+	for _, table := range tableList {
+		tableMap[table.Name] = table
+	}
+	return tableMap, nil
+}
+
+func (m *DataDiffManager) diffTable(ctx context.Context, engine db.Type, sourceDB, targetDB *sql.DB, sourceTable, targetTable *api.Table, chunkSize int) (*api.DataDiffTableResult, error) {
+	switch {
+	case sourceTable == nil && targetTable == nil:
+		return nil, errors.Errorf("table not found in either database")
+	case targetTable == nil:
+		return &api.DataDiffTableResult{
+			Status:         api.DataDiffTableSourceOnly,
+			SourceRowCount: sourceTable.RowCount,
+		}, nil
+	case sourceTable == nil:
+		return &api.DataDiffTableResult{
+			Status:         api.DataDiffTableTargetOnly,
+			TargetRowCount: targetTable.RowCount,
+		}, nil
+	}
+
+	columnList, err := m.getColumnNameList(ctx, sourceTable)
+	if err != nil {
+		return nil, err
+	}
+	targetColumnList, err := m.getColumnNameList(ctx, targetTable)
+	if err != nil {
+		return nil, err
+	}
+	sortedColumnList := append([]string{}, columnList...)
+	sortedTargetColumnList := append([]string{}, targetColumnList...)
+	sort.Strings(sortedColumnList)
+	sort.Strings(sortedTargetColumnList)
+	if strings.Join(sortedColumnList, ",") != strings.Join(sortedTargetColumnList, ",") {
+		return nil, errors.Errorf("column list differs, source: %v, target: %v", columnList, targetColumnList)
+	}
+	keyIndexList, err := m.getPrimaryKeyIndexList(ctx, sourceTable, columnList)
+	if err != nil {
+		return nil, err
+	}
+
+	// The chunk count is estimated from the synced row counts, which only affects the granularity of the result.
+	rowCount := sourceTable.RowCount
+	if targetTable.RowCount > rowCount {
+		rowCount = targetTable.RowCount
+	}
+	chunkCount := int(rowCount/int64(chunkSize)) + 1
+	if chunkCount > maxDataDiffChunkCount {
+		chunkCount = maxDataDiffChunkCount
+	}
+
+	query := getDataDiffQuery(engine, sourceTable.Name, columnList)
+	sourceChunkList, err := getDataDiffChunkList(ctx, sourceDB, query, len(columnList), keyIndexList, chunkCount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate source checksums")
+	}
+	targetChunkList, err := getDataDiffChunkList(ctx, targetDB, query, len(columnList), keyIndexList, chunkCount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate target checksums")
+	}
+
+	result := &api.DataDiffTableResult{
+		Status:              api.DataDiffTableMatch,
+		ChunkCount:          chunkCount,
+		SourceRowCount:      sourceChunkList.rowCount(),
+		TargetRowCount:      targetChunkList.rowCount(),
+		MismatchedChunkList: diffDataDiffChunkList(sourceChunkList, targetChunkList),
+	}
+	if len(result.MismatchedChunkList) > 0 {
+		result.Status = api.DataDiffTableMismatch
+	}
+	return result, nil
+}
+
+// getColumnNameList returns the column names of the table ordered by position.
+func (m *DataDiffManager) getColumnNameList(ctx context.Context, table *api.Table) ([]string, error) {
+	columnList, err := m.server.store.FindColumn(ctx, &api.ColumnFind{
+		DatabaseID: &table.DatabaseID,
+		TableID:    &table.ID,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find columns of table %q", table.Name)
+	}
+	sort.Slice(columnList, func(i, j int) bool {
+		return columnList[i].Position < columnList[j].Position
+	})
+	var nameList []string
+	for _, column := range columnList {
+		nameList = append(nameList, column.Name)
+	}
+	return nameList, nil
+}
+
+// getPrimaryKeyIndexList returns the indexes of the primary key columns in the column list, nil if the table has no primary key.
+func (m *DataDiffManager) getPrimaryKeyIndexList(ctx context.Context, table *api.Table, columnList []string) ([]int, error) {
+	indexList, err := m.server.store.FindIndex(ctx, &api.IndexFind{
+		DatabaseID: &table.DatabaseID,
+		TableID:    &table.ID,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find indexes of table %q", table.Name)
+	}
+	sort.Slice(indexList, func(i, j int) bool {
+		return indexList[i].Position < indexList[j].Position
+	})
+	var keyIndexList []int
+	for _, index := range indexList {
+		if !index.Primary {
+			continue
+		}
+		found := false
+		for i, column := range columnList {
+			if column == index.Expression {
+				keyIndexList = append(keyIndexList, i)
+				found = true
+				break
+			}
+		}
+		// Fall back to the whole row if the primary key contains an expression.
+		if !found {
+			return nil, nil
+		}
+	}
+	return keyIndexList, nil
+}
+
+func getDataDiffQuery(engine db.Type, table string, columnList []string) string {
+	quote := func(identifier string) string {
+		if engine == db.Postgres {
+			return fmt.Sprintf(`"%s"`, strings.ReplaceAll(identifier, `"`, `""`))
+		}
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(identifier, "`", "``"))
+	}
+	var quotedColumnList []string
+	for _, column := range columnList {
+		quotedColumnList = append(quotedColumnList, quote(column))
+	}
+	quotedTable := quote(table)
+	// Postgres tables are synced as "schema.table".
+	if engine == db.Postgres {
+		if i := strings.Index(table, "."); i >= 0 {
+			quotedTable = fmt.Sprintf("%s.%s", quote(table[:i]), quote(table[i+1:]))
+		}
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(quotedColumnList, ", "), quotedTable)
+}
+
+func getDataDiffChunkList(ctx context.Context, sqldb *sql.DB, query string, columnCount int, keyIndexList []int, chunkCount int) (dataDiffChunkList, error) {
+	rows, err := sqldb.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chunkList := newDataDiffChunkList(chunkCount)
+	row := make([]sql.RawBytes, columnCount)
+	dest := make([]interface{}, columnCount)
+	for i := range row {
+		dest[i] = &row[i]
+	}
+	values := make([][]byte, columnCount)
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range row {
+			values[i] = v
+		}
+		chunkList.addRow(values, keyIndexList)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return chunkList, nil
+}
+
+// dataDiffChunk is the checksum of a chunk of rows.
+// The checksum is the sum of the row hashes, so it doesn't depend on the row order and counts duplicate rows.
+type dataDiffChunk struct {
+	rowCount int64
+	checksum uint64
+}
+
+type dataDiffChunkList []dataDiffChunk
+
+func newDataDiffChunkList(chunkCount int) dataDiffChunkList {
+	return make(dataDiffChunkList, chunkCount)
+}
+
+// addRow adds the row into the chunk chosen by the hash of the key columns, or of the whole row if keyIndexList is empty.
+// A nil value is NULL.
+func (l dataDiffChunkList) addRow(values [][]byte, keyIndexList []int) {
+	rowHash := hashDataDiffValues(values, nil)
+	keyHash := rowHash
+	if len(keyIndexList) > 0 {
+		keyHash = hashDataDiffValues(values, keyIndexList)
+	}
+	chunk := &l[keyHash%uint64(len(l))]
+	chunk.rowCount++
+	chunk.checksum += rowHash
+}
+
+func (l dataDiffChunkList) rowCount() int64 {
+	var count int64
+	for _, chunk := range l {
+		count += chunk.rowCount
+	}
+	return count
+}
+
+// hashDataDiffValues hashes the values at indexList, or all values if indexList is nil.
+// Each value is length-prefixed so that ("ab", "c") and ("a", "bc") have different hashes.
+func hashDataDiffValues(values [][]byte, indexList []int) uint64 {
+	h := fnv.New64a()
+	var lenBuf [binary.MaxVarintLen64]byte
+	write := func(v []byte) {
+		if v == nil {
+			_, _ = h.Write([]byte{0})
+			return
+		}
+		_, _ = h.Write([]byte{1})
+		n := binary.PutUvarint(lenBuf[:], uint64(len(v)))
+		_, _ = h.Write(lenBuf[:n])
+		_, _ = h.Write(v)
+	}
+	if indexList == nil {
+		for _, v := range values {
+			write(v)
+		}
+	} else {
+		for _, i := range indexList {
+			write(values[i])
+		}
+	}
+	return h.Sum64()
+}
+
+// diffDataDiffChunkList returns the mismatched chunks, both lists must have the same length.
+func diffDataDiffChunkList(source, target dataDiffChunkList) []*api.DataDiffChunk {
+	mismatchedChunkList := []*api.DataDiffChunk{}
+	for i := range source {
+		if source[i] == target[i] {
+			continue
+		}
+		mismatchedChunkList = append(mismatchedChunkList, &api.DataDiffChunk{
+			Index:          i,
+			SourceRowCount: source[i].rowCount,
+			TargetRowCount: target[i].rowCount,
+			SourceChecksum: fmt.Sprintf("%016x", source[i].checksum),
+			TargetChecksum: fmt.Sprintf("%016x", target[i].checksum),
+		})
+	}
+	return mismatchedChunkList
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestDiffDataDiffChunkList(t *testing.T) {
+	rows := [][][]byte{
+		{[]byte("1"), []byte("alice"), nil},
+		{[]byte("2"), []byte("bob"), []byte("2022-09-01")},
+		{[]byte("3"), []byte("carol"), nil},
+		{[]byte("4"), []byte("dave"), []byte("")},
+	}
+	keyIndexList := []int{0}
+	chunkCount := 3
+
+	newChunkList := func(rows [][][]byte) dataDiffChunkList {
+		chunkList := newDataDiffChunkList(chunkCount)
+		for _, row := range rows {
+			chunkList.addRow(row, keyIndexList)
+		}
+		return chunkList
+	}
+
+	source := newChunkList(rows)
+	require.Equal(t, int64(4), source.rowCount())
+
+	// The row order doesn't matter.
+	reversed := newChunkList([][][]byte{rows[3], rows[2], rows[1], rows[0]})
+	require.Empty(t, diffDataDiffChunkList(source, reversed))
+
+	// NULL differs from the empty string.
+	nullified := newChunkList([][][]byte{rows[0], rows[1], rows[2], {[]byte("4"), []byte("dave"), nil}})
+	require.Len(t, diffDataDiffChunkList(source, nullified), 1)
+
+	// A changed row only affects the chunk of its primary key.
+	changed := newChunkList([][][]byte{rows[0], {[]byte("2"), []byte("bob"), []byte("2022-09-02")}, rows[2], rows[3]})
+	mismatchedChunkList := diffDataDiffChunkList(source, changed)
+	require.Len(t, mismatchedChunkList, 1)
+	require.Equal(t, mismatchedChunkList[0].SourceRowCount, mismatchedChunkList[0].TargetRowCount)
+
+	// A missing row.
+	missing := newChunkList(rows[:3])
+	mismatchedChunkList = diffDataDiffChunkList(source, missing)
+	require.Len(t, mismatchedChunkList, 1)
+	require.Equal(t, mismatchedChunkList[0].SourceRowCount-1, mismatchedChunkList[0].TargetRowCount)
+
+	// Duplicate rows are counted.
+	duplicated := newChunkList(append(rows, rows[0]))
+	require.Len(t, diffDataDiffChunkList(source, duplicated), 1)
+}
+
+func TestHashDataDiffValues(t *testing.T) {
+	require.NotEqual(t,
+		hashDataDiffValues([][]byte{[]byte("ab"), []byte("c")}, nil),
+		hashDataDiffValues([][]byte{[]byte("a"), []byte("bc")}, nil),
+	)
+	require.Equal(t,
+		hashDataDiffValues([][]byte{[]byte("1"), []byte("x")}, []int{0}),
+		hashDataDiffValues([][]byte{[]byte("1"), []byte("y")}, []int{0}),
+	)
+}
+
+func TestGetDataDiffQuery(t *testing.T) {
+	require.Equal(t, "SELECT `id`, `na``me` FROM `t1`", getDataDiffQuery(db.MySQL, "t1", []string{"id", "na`me"}))
+	require.Equal(t, `SELECT "id", "name" FROM "public"."t1"`, getDataDiffQuery(db.Postgres, "public.t1", []string{"id", "name"}))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -17,6 +18,7 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
 )
 
 func (s *Server) registerDatabaseRoutes(g *echo.Group) {
@@ -45,8 +47,10 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			err := errors.Errorf("project ID not found %v", databaseCreate.ProjectID)
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
-		if project.TenantMode == api.TenantModeTenant && !s.feature(api.FeatureMultiTenancy) {
-			return echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
+		if project.TenantMode == api.TenantModeTenant {
+			if err := s.checkFeature(api.FeatureMultiTenancy); err != nil {
+				return featureHTTPError(err)
+			}
 		}
 		// Pre-validate database labels.
 		if databaseCreate.Labels != nil && *databaseCreate.Labels != "" {
@@ -78,9 +82,77 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// Archives the databases matching the filters in one transaction, or only lists them in the dry-run mode.
+	g.POST("/database/archive", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		databaseArchive := &api.DatabaseArchive{
+			UpdaterID:   c.Get(getPrincipalIDContextKey()).(int),
+			WorkspaceID: c.Get(getWorkspaceIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, databaseArchive); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed archive database request").SetInternal(err)
+		}
+		if databaseArchive.StaleDays < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid stale days %d", databaseArchive.StaleDays))
+		}
+		if databaseArchive.StaleDays == 0 && databaseArchive.LabelSelector == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed archive database request, either staleDays or labelSelector is required")
+		}
+		var selector *api.LabelSelector
+		if databaseArchive.LabelSelector != "" {
+			selector = &api.LabelSelector{}
+			if err := json.Unmarshal([]byte(databaseArchive.LabelSelector), selector); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed label selector").SetInternal(err)
+			}
+			if len(selector.MatchExpressions) == 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "Label selector must have at least one match expression")
+			}
+		}
+
+		rowStatus := api.Normal
+		databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{
+			RowStatus:   &rowStatus,
+			WorkspaceID: &databaseArchive.WorkspaceID,
+			ProjectID:   databaseArchive.ProjectID,
+			InstanceID:  databaseArchive.InstanceID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch database list").SetInternal(err)
+		}
+		archivableList, err := getArchivableDatabaseList(databaseList, getStaleCutoffTs(databaseArchive.StaleDays), selector)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to filter database list").SetInternal(err)
+		}
+
+		if !databaseArchive.DryRun && len(archivableList) > 0 {
+			var idList []int
+			for _, database := range archivableList {
+				idList = append(idList, database.ID)
+			}
+			if archivableList, err = s.store.ArchiveDatabaseList(ctx, idList, databaseArchive.UpdaterID); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to archive database list").SetInternal(err)
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, archivableList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal archived database list response").SetInternal(err)
+		}
+		return nil
+	})
+
 	g.GET("/database", func(c echo.Context) error {
 		ctx := c.Request().Context()
-		databaseFind := new(api.DatabaseFind)
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		databaseFind := &api.DatabaseFind{
+			WorkspaceID: &workspaceID,
+		}
+		// Archived databases are hidden unless requested explicitly.
+		rowStatus := api.Normal
+		if rowStatusStr := c.QueryParam("rowstatus"); rowStatusStr != "" {
+			rowStatus = api.RowStatus(rowStatusStr)
+		}
+		databaseFind.RowStatus = &rowStatus
 		if instanceIDStr := c.QueryParam("instance"); instanceIDStr != "" {
 			instanceID, err := strconv.Atoi(instanceIDStr)
 			if err != nil {
@@ -180,6 +252,11 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, dbPatch); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch database request").SetInternal(err)
 		}
+		if dbPatch.ConnectionOption != nil {
+			if _, err := api.UnmarshalDatabaseConnectionOption(*dbPatch.ConnectionOption); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid connection option: %v", err)).SetInternal(err)
+			}
+		}
 
 		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
 		if err != nil {
@@ -210,8 +287,8 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			targetProject = toProject
 
 			if toProject.TenantMode == api.TenantModeTenant {
-				if !s.feature(api.FeatureMultiTenancy) {
-					return echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
+				if err := s.checkFeature(api.FeatureMultiTenancy); err != nil {
+					return featureHTTPError(err)
 				}
 
 				labels := database.Labels
@@ -248,6 +325,16 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			}
 		}
 
+		if dbPatch.OwnerID != nil && *dbPatch.OwnerID != api.UnknownID {
+			owner, err := s.store.GetPrincipalByID(ctx, *dbPatch.OwnerID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find principal with ID %d", *dbPatch.OwnerID)).SetInternal(err)
+			}
+			if owner == nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database owner not found with principal ID %d", *dbPatch.OwnerID))
+			}
+		}
+
 		// Patch database labels
 		// We will completely replace the old labels with the new ones, except bb.environment is immutable and
 		// must match instance environment.
@@ -346,6 +433,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		tableFind := &api.TableFind{
 			DatabaseID: &id,
 		}
+		if schema := c.QueryParam("schema"); schema != "" {
+			tableFind.Schema = &schema
+		}
 		tableList, err := s.store.FindTable(ctx, tableFind)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table list for database id: %d", id)).SetInternal(err)
@@ -395,43 +485,396 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
 		}
 
+		table, err := s.getTableWithColumnAndIndex(ctx, id, c.Param("tableName"))
+		if err != nil {
+			return err
+		}
+		table.Database = database
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, table); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal fetch table response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Patches all the key parts of the index, and returns the table with the patched index.
+	g.PATCH("/database/:id/table/:tableName/index/:indexName", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
 		tableName := c.Param("tableName")
-		tableFind := &api.TableFind{
-			DatabaseID: &id,
-			Name:       &tableName,
+		table, err := s.getTableWithColumnAndIndex(ctx, id, tableName)
+		if err != nil {
+			return err
 		}
-		table, err := s.store.GetTable(ctx, tableFind)
+
+		indexPatch := &api.IndexPatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, indexPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch index request").SetInternal(err)
+		}
+		if indexPatch.Name != nil && *indexPatch.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Index name must not be empty")
+		}
+
+		indexName := c.Param("indexName")
+		var patchList []*api.IndexPatch
+		for _, index := range table.IndexList {
+			if index.Name != indexName {
+				continue
+			}
+			patchList = append(patchList, &api.IndexPatch{
+				ID:        index.ID,
+				UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+				Name:      indexPatch.Name,
+				Visible:   indexPatch.Visible,
+				Comment:   indexPatch.Comment,
+			})
+		}
+		if len(patchList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Index %q not found in table %q", indexName, tableName))
+		}
+
+		if _, err := s.store.PatchIndexList(ctx, patchList); err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Index %q already exists in table %q", *indexPatch.Name, tableName))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch index %q of table %q", indexName, tableName)).SetInternal(err)
+		}
+
+		tableNew, err := s.getTableWithColumnAndIndex(ctx, id, tableName)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table for database id: %d, table name: %s", id, tableName)).SetInternal(err)
+			return err
 		}
-		if table == nil {
-			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("table %q not found from database %v", tableName, id)).SetInternal(err)
+		tableNew.Database = database
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, tableNew); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal patch index response: %v", id)).SetInternal(err)
 		}
-		table.Database = database
+		return nil
+	})
 
-		columnFind := &api.ColumnFind{
-			DatabaseID: &id,
-			TableID:    &table.ID,
+	// Generates the statements creating the index of the table, or all the indexes if not specified, in the dialect of the database engine.
+	g.GET("/database/:id/table/:tableName/index-ddl", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
 		}
-		columnList, err := s.store.FindColumn(ctx, columnFind)
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch column list for database id: %d, table name: %s", id, tableName)).SetInternal(err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		table, err := s.getTableWithColumnAndIndex(ctx, id, c.Param("tableName"))
+		if err != nil {
+			return err
+		}
+
+		var statementList []string
+		if indexName := c.QueryParam("index"); indexName != "" {
+			statement, err := table.GenerateIndexDDL(database.Instance.Engine, indexName)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			statementList = append(statementList, statement)
+		} else {
+			statementList, err = table.GenerateIndexDDLList(database.Instance.Engine)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
 		}
-		table.ColumnList = columnList
 
-		indexFind := &api.IndexFind{
+		return c.JSON(http.StatusOK, &api.TableIndexDDL{
+			Statement: strings.Join(statementList, "\n"),
+		})
+	})
+
+	// Lists the migration histories changing the table, or the column if specified, the latest first.
+	g.GET("/database/:id/change-history", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		tableName := c.QueryParam("table")
+		if tableName == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing query parameter table")
+		}
+		objectFind := &api.MigrationHistoryObjectFind{
 			DatabaseID: &id,
-			TableID:    &table.ID,
+			TableName:  &tableName,
+		}
+		if schemaName := c.QueryParam("schema"); schemaName != "" {
+			objectFind.SchemaName = &schemaName
+		}
+		if columnName := c.QueryParam("column"); columnName != "" {
+			objectFind.ColumnName = &columnName
+		}
+		limit := 0
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit query parameter is not a number: %s", limitStr)).SetInternal(err)
+			}
 		}
-		indexList, err := s.store.FindIndex(ctx, indexFind)
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+
+		objectList, err := s.store.FindMigrationHistoryObject(ctx, objectFind)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch index list for database id: %d, table name: %s", id, table.Name)).SetInternal(err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch change history for database ID: %v", id)).SetInternal(err)
+		}
+		// The objects are ordered by the migration history ID descending.
+		var migrationIDList []int
+		for _, object := range objectList {
+			if n := len(migrationIDList); n > 0 && migrationIDList[n-1] == object.MigrationHistoryID {
+				continue
+			}
+			if limit > 0 && len(migrationIDList) >= limit {
+				break
+			}
+			migrationIDList = append(migrationIDList, object.MigrationHistoryID)
+		}
+
+		historyList := []*api.MigrationHistory{}
+		if len(migrationIDList) > 0 {
+			driver, err := s.getAdminDatabaseDriver(ctx, database.Instance, "" /* databaseName */)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch migration history for instance %q", database.Instance.Name)).SetInternal(err)
+			}
+			defer driver.Close(ctx)
+			for _, migrationID := range migrationIDList {
+				migrationID := migrationID
+				list, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{ID: &migrationID})
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch migration history list").SetInternal(err)
+				}
+				for _, entry := range list {
+					historyList = append(historyList, &api.MigrationHistory{
+						ID:                    entry.ID,
+						Creator:               entry.Creator,
+						CreatedTs:             entry.CreatedTs,
+						Updater:               entry.Updater,
+						UpdatedTs:             entry.UpdatedTs,
+						ReleaseVersion:        entry.ReleaseVersion,
+						Database:              entry.Namespace,
+						Source:                entry.Source,
+						Type:                  entry.Type,
+						Status:                entry.Status,
+						Version:               entry.Version,
+						UseSemanticVersion:    entry.UseSemanticVersion,
+						SemanticVersionSuffix: entry.SemanticVersionSuffix,
+						Description:           entry.Description,
+						Statement:             entry.Statement,
+						Schema:                entry.Schema,
+						SchemaPrev:            entry.SchemaPrev,
+						ExecutionDurationNs:   entry.ExecutionDurationNs,
+						IssueID:               entry.IssueID,
+						Payload:               entry.Payload,
+					})
+				}
+			}
 		}
-		table.IndexList = indexList
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
-		if err := jsonapi.MarshalPayload(c.Response().Writer, table); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal fetch table response: %v", id)).SetInternal(err)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, historyList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal change history response for database ID: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/change-history/:historyID/object", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		historyID, err := strconv.Atoi(c.Param("historyID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("History ID is not a number: %s", c.Param("historyID"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+
+		objectList, err := s.store.FindMigrationHistoryObject(ctx, &api.MigrationHistoryObjectFind{
+			DatabaseID:         &id,
+			MigrationHistoryID: &historyID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch objects of migration history ID %v for database ID: %v", historyID, id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, objectList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal migration history object list response for database ID: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/change-history/:historyID/statement", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		historyID, err := strconv.Atoi(c.Param("historyID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("History ID is not a number: %s", c.Param("historyID"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+
+		statementList, err := s.store.FindMigrationHistoryStatement(ctx, &api.MigrationHistoryStatementFind{
+			DatabaseID:         &id,
+			MigrationHistoryID: &historyID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch statements of migration history ID %v for database ID: %v", historyID, id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, statementList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal migration history statement list response for database ID: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/change-history/export", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		format := c.QueryParam("format")
+		if format != "liquibase" && format != "flyway" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid export format %q, should be liquibase or flyway", format))
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+
+		driver, err := s.getAdminDatabaseDriver(ctx, database.Instance, "" /* databaseName */)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch migration history for instance %q", database.Instance.Name)).SetInternal(err)
+		}
+		defer driver.Close(ctx)
+		list, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{Database: &database.Name})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch migration history list").SetInternal(err)
+		}
+
+		var buf bytes.Buffer
+		var filename, contentType string
+		switch format {
+		case "liquibase":
+			filename, contentType = fmt.Sprintf("%s-changelog.xml", database.Name), echo.MIMEApplicationXMLCharsetUTF8
+			err = util.ExportLiquibaseChangelog(&buf, list)
+		case "flyway":
+			filename, contentType = fmt.Sprintf("%s-flyway.zip", database.Name), "application/zip"
+			err = util.ExportFlywayMigrationList(&buf, list)
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to export migration history of database %q", database.Name)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+		return c.Blob(http.StatusOK, contentType, buf.Bytes())
+	})
+
+	g.POST("/database/:id/change-history/import", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		historyImport := &api.MigrationHistoryImport{
+			CreatorID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, historyImport); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed import migration history request").SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+
+		list, err := s.importMigrationHistory(ctx, database, historyImport)
+		if err != nil {
+			return err
+		}
+		historyList := []*api.MigrationHistory{}
+		for _, entry := range list {
+			historyList = append(historyList, &api.MigrationHistory{
+				ID:                    entry.ID,
+				Creator:               entry.Creator,
+				CreatedTs:             entry.CreatedTs,
+				Updater:               entry.Updater,
+				UpdatedTs:             entry.UpdatedTs,
+				ReleaseVersion:        entry.ReleaseVersion,
+				Database:              entry.Namespace,
+				Source:                entry.Source,
+				Type:                  entry.Type,
+				Status:                entry.Status,
+				Version:               entry.Version,
+				UseSemanticVersion:    entry.UseSemanticVersion,
+				SemanticVersionSuffix: entry.SemanticVersionSuffix,
+				Description:           entry.Description,
+				Statement:             entry.Statement,
+				Schema:                entry.Schema,
+				SchemaPrev:            entry.SchemaPrev,
+				ExecutionDurationNs:   entry.ExecutionDurationNs,
+				IssueID:               entry.IssueID,
+				Payload:               entry.Payload,
+			})
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, historyList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal imported migration history response for database ID: %v", id)).SetInternal(err)
 		}
 		return nil
 	})
@@ -446,6 +889,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		viewFind := &api.ViewFind{
 			DatabaseID: &id,
 		}
+		if schema := c.QueryParam("schema"); schema != "" {
+			viewFind.Schema = &schema
+		}
 		viewList, err := s.store.FindView(ctx, viewFind)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch view list for database ID: %d", id)).SetInternal(err)
@@ -480,6 +926,28 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		return nil
 	})
 
+	g.GET("/database/:id/schema", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		dbSchemaFind := &api.DBSchemaFind{
+			DatabaseID: &id,
+		}
+		dbSchemaList, err := s.store.FindDBSchema(ctx, dbSchemaFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch dbSchema list for database ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dbSchemaList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal fetch dbSchema list response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.POST("/database/:id/backup", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		id, err := strconv.Atoi(c.Param("id"))
@@ -680,6 +1148,14 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 
 		dataSourceCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
 		dataSourceCreate.DatabaseID = databaseID
+		tlsConfig := db.TLSConfig{
+			SslCA:   dataSourceCreate.SslCa,
+			SslCert: dataSourceCreate.SslCert,
+			SslKey:  dataSourceCreate.SslKey,
+		}
+		if err := tlsConfig.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid SSL config: %v", err)).SetInternal(err)
+		}
 
 		dataSource, err := s.store.CreateDataSource(ctx, dataSourceCreate)
 		if err != nil {
@@ -752,6 +1228,24 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			password := ""
 			dataSourcePatch.Password = &password
 		}
+		// The SSL config is validated as a whole, the unchanged fields are kept.
+		tlsConfig := db.TLSConfig{
+			SslCA:   dataSourceOld.SslCa,
+			SslCert: dataSourceOld.SslCert,
+			SslKey:  dataSourceOld.SslKey,
+		}
+		if v := dataSourcePatch.SslCa; v != nil {
+			tlsConfig.SslCA = *v
+		}
+		if v := dataSourcePatch.SslCert; v != nil {
+			tlsConfig.SslCert = *v
+		}
+		if v := dataSourcePatch.SslKey; v != nil {
+			tlsConfig.SslKey = *v
+		}
+		if err := tlsConfig.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid SSL config: %v", err)).SetInternal(err)
+		}
 
 		dataSourceNew, err := s.store.PatchDataSource(ctx, dataSourcePatch)
 		if err != nil {
@@ -829,30 +1323,56 @@ func (s *Server) setDatabaseLabels(ctx context.Context, labelsJSON string, datab
 // Try to get database driver using the instance's admin data source.
 // Upon successful return, caller MUST call driver.Close, otherwise, it will leak the database connection.
 func (s *Server) getAdminDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName string) (db.Driver, error) {
+	return s.getAdminDatabaseDriverWithSchema(ctx, instance, databaseName, "")
+}
+
+// getAdminDatabaseDriverWithSchema is getAdminDatabaseDriver resolving the unqualified objects in the Postgres schemaName, empty for the default search_path.
+func (s *Server) getAdminDatabaseDriverWithSchema(ctx context.Context, instance *api.Instance, databaseName string, schemaName string) (db.Driver, error) {
 	connCfg, err := getConnectionConfig(instance, databaseName)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.AgentManager.applyAgent(instance.ID, &connCfg); err != nil {
+		return nil, err
+	}
+	if databaseName != "" {
+		connectionOption, err := s.store.GetDatabaseConnectionOption(ctx, instance.ID, databaseName)
+		if err != nil {
+			return nil, err
+		}
+		if connectionOption != nil {
+			for _, variable := range connectionOption.SessionVariableList {
+				connCfg.SessionVariableList = append(connCfg.SessionVariableList, db.SessionVariable{
+					Name:  variable.Name,
+					Value: variable.Value,
+				})
+			}
+		}
+	}
+	// It's set after the connection options so that it overrides the search_path of the database.
+	if schemaName != "" {
+		connCfg.SessionVariableList = append(connCfg.SessionVariableList, getSearchPathSessionVariable(schemaName))
+	}
 
-	driver, err := getDatabaseDriver(
+	return s.getPooledDatabaseDriver(
 		ctx,
-		instance.Engine,
+		instance,
 		db.DriverConfig{
 			PgInstanceDir: s.pgInstance.BaseDir,
 			ResourceDir:   common.GetResourceDir(s.profile.DataDir),
 			BinlogDir:     getBinlogAbsDir(s.profile.DataDir, instance.ID),
 		},
 		connCfg,
-		db.ConnectionContext{
-			EnvironmentName: instance.Environment.Name,
-			InstanceName:    instance.Name,
-		},
 	)
-	if err != nil {
-		return nil, err
-	}
+}
 
-	return driver, nil
+// getSearchPathSessionVariable returns the Postgres search_path resolving the unqualified objects in the schema.
+// The public schema is kept in the search_path for the objects shared by the schemas, e.g. the extensions.
+func getSearchPathSessionVariable(schemaName string) db.SessionVariable {
+	return db.SessionVariable{
+		Name:  "search_path",
+		Value: fmt.Sprintf(`"%s", public`, strings.ReplaceAll(schemaName, `"`, `""`)),
+	}
 }
 
 // getConnectionConfig returns the connection config of the `databaseName` on `instance`.
@@ -870,15 +1390,16 @@ func getConnectionConfig(instance *api.Instance, databaseName string) (db.Connec
 			SslCert: adminDataSource.SslCert,
 			SslKey:  adminDataSource.SslKey,
 		},
-		Host:     instance.Host,
-		Port:     instance.Port,
-		Database: databaseName,
+		Host:      instance.Host,
+		Port:      instance.Port,
+		Database:  databaseName,
+		SSHConfig: api.SSHConfigFromInstance(instance),
 	}, nil
 }
 
 // We'd like to use read-only data source whenever possible, but fallback to admin data source if there's no read-only data source.
 // Upon successful return, caller MUST call driver.Close, otherwise, it will leak the database connection.
-func tryGetReadOnlyDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName string) (db.Driver, error) {
+func (s *Server) tryGetReadOnlyDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName string) (db.Driver, error) {
 	dataSource := api.DataSourceFromInstanceWithType(instance, api.RO)
 	// If there are no read-only data source, fall back to admin data source.
 	if dataSource == nil {
@@ -888,37 +1409,48 @@ func tryGetReadOnlyDatabaseDriver(ctx context.Context, instance *api.Instance, d
 		return nil, common.Errorf(common.Internal, "data source not found for instance %d", instance.ID)
 	}
 
-	driver, err := getDatabaseDriver(
-		ctx,
-		instance.Engine,
-		// We don't need postgres installation for query.
-		db.DriverConfig{},
-		db.ConnectionConfig{
-			Username: dataSource.Username,
-			Password: dataSource.Password,
-			Host:     instance.Host,
-			Port:     instance.Port,
-			Database: databaseName,
-			TLSConfig: db.TLSConfig{
-				SslCA:   dataSource.SslCa,
-				SslCert: dataSource.SslCert,
-				SslKey:  dataSource.SslKey,
-			},
-			ReadOnly: true,
-		},
-		db.ConnectionContext{
-			EnvironmentName: instance.Environment.Name,
-			InstanceName:    instance.Name,
+	connCfg := db.ConnectionConfig{
+		Username: dataSource.Username,
+		Password: dataSource.Password,
+		Host:     instance.Host,
+		Port:     instance.Port,
+		Database: databaseName,
+		TLSConfig: db.TLSConfig{
+			SslCA:   dataSource.SslCa,
+			SslCert: dataSource.SslCert,
+			SslKey:  dataSource.SslKey,
 		},
-	)
-	if err != nil {
+		SSHConfig: api.SSHConfigFromInstance(instance),
+		ReadOnly:  true,
+	}
+	if err := s.AgentManager.applyAgent(instance.ID, &connCfg); err != nil {
 		return nil, err
 	}
+	// We don't need postgres installation for query.
+	return s.getPooledDatabaseDriver(ctx, instance, db.DriverConfig{}, connCfg)
+}
 
-	return driver, nil
+// getPooledDatabaseDriver returns the driver from the server driver pool, which opens a new one if there is no idle driver.
+// Upon successful return, caller MUST call driver.Close, otherwise, the driver is never returned to the pool.
+func (s *Server) getPooledDatabaseDriver(ctx context.Context, instance *api.Instance, driverConfig db.DriverConfig, connCfg db.ConnectionConfig) (db.Driver, error) {
+	key, err := getDriverPoolKey(instance.ID, driverConfig, connCfg)
+	if err != nil {
+		return nil, err
+	}
+	return s.driverPool.get(ctx, key, func(ctx context.Context) (db.Driver, error) {
+		return getDatabaseDriver(
+			ctx,
+			instance.Engine,
+			driverConfig,
+			connCfg,
+			db.ConnectionContext{
+				EnvironmentName: instance.Environment.Name,
+				InstanceName:    instance.Name,
+			},
+		)
+	})
 }
 
-// Retrieve db.Driver connection with standard parameters for all type data source.
 func getDatabaseDriver(ctx context.Context, engine db.Type, driverConfig db.DriverConfig, connectionConfig db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
 	driver, err := db.Open(
 		ctx,
@@ -970,3 +1502,39 @@ func validateDatabaseLabelList(labelList []*api.DatabaseLabel, labelKeyList []*a
 
 	return nil
 }
+
+// getTableWithColumnAndIndex gets the table of the database with its columns and indexes.
+func (s *Server) getTableWithColumnAndIndex(ctx context.Context, databaseID int, tableName string) (*api.Table, error) {
+	tableFind := &api.TableFind{
+		DatabaseID: &databaseID,
+		Name:       &tableName,
+	}
+	table, err := s.store.GetTable(ctx, tableFind)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table for database id: %d, table name: %s", databaseID, tableName)).SetInternal(err)
+	}
+	if table == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("table %q not found from database %v", tableName, databaseID)).SetInternal(err)
+	}
+
+	columnFind := &api.ColumnFind{
+		DatabaseID: &databaseID,
+		TableID:    &table.ID,
+	}
+	columnList, err := s.store.FindColumn(ctx, columnFind)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch column list for database id: %d, table name: %s", databaseID, tableName)).SetInternal(err)
+	}
+	table.ColumnList = columnList
+
+	indexFind := &api.IndexFind{
+		DatabaseID: &databaseID,
+		TableID:    &table.ID,
+	}
+	indexList, err := s.store.FindIndex(ctx, indexFind)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch index list for database id: %d, table name: %s", databaseID, table.Name)).SetInternal(err)
+	}
+	table.IndexList = indexList
+	return table, nil
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerDatabaseSecretRoutes(g *echo.Group) {
+	// Lists the secrets of the database without the values.
+	g.GET("/database/:id/secret", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		databaseSecretList, err := s.store.FindDatabaseSecret(ctx, &api.DatabaseSecretFind{DatabaseID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch secret list for database ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, databaseSecretList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal secret list response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Creates the secret, or updates it if it already exists.
+	g.PATCH("/database/:id/secret/:secretName", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		secretName := c.Param("secretName")
+		if !api.DatabaseSecretNameRegexp.MatchString(secretName) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid secret name %q, it must consist of letters, digits and underscores, and not start with a digit", secretName))
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+
+		upsert := &api.DatabaseSecretUpsert{
+			UpdaterID:  c.Get(getPrincipalIDContextKey()).(int),
+			DatabaseID: id,
+			Name:       secretName,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed upsert database secret request").SetInternal(err)
+		}
+		if upsert.Value == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed upsert database secret request, missing value")
+		}
+
+		databaseSecret, err := s.store.UpsertDatabaseSecret(ctx, upsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to upsert secret %q for database ID: %d", secretName, id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, databaseSecret); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal secret response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/database/:id/secret/:secretName", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		secretName := c.Param("secretName")
+
+		if err := s.store.DeleteDatabaseSecret(ctx, &api.DatabaseSecretDelete{
+			DatabaseID: id,
+			Name:       secretName,
+		}); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Secret %q not found in database ID: %d", secretName, id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete secret %q for database ID: %d", secretName, id)).SetInternal(err)
+		}
+
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
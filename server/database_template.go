@@ -0,0 +1,289 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+var (
+	// extensionNameRegexp matches the Postgres extension names, e.g. "uuid-ossp".
+	extensionNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_\-]*$`)
+)
+
+func (s *Server) registerDatabaseTemplateRoutes(g *echo.Group) {
+	g.POST("/database-template", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		templateCreate := &api.DatabaseTemplateCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, templateCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create database template request").SetInternal(err)
+		}
+		templateCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		templateCreate.WorkspaceID = c.Get(getWorkspaceIDContextKey()).(int)
+
+		if err := s.validateDatabaseTemplate(ctx, &api.DatabaseTemplate{
+			SheetID:       templateCreate.SheetID,
+			Name:          templateCreate.Name,
+			Engine:        templateCreate.Engine,
+			CharacterSet:  templateCreate.CharacterSet,
+			Collation:     templateCreate.Collation,
+			ExtensionList: templateCreate.ExtensionList,
+			GranteeList:   templateCreate.GranteeList,
+		}); err != nil {
+			return err
+		}
+
+		template, err := s.store.CreateDatabaseTemplate(ctx, templateCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Database template name already exists: %s", templateCreate.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create database template").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, template); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create database template response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database-template", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		templateList, err := s.store.FindDatabaseTemplate(ctx, &api.DatabaseTemplateFind{WorkspaceID: &workspaceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch database template list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, templateList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal database template list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database-template/:databaseTemplateID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseTemplateID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseTemplateID"))).SetInternal(err)
+		}
+
+		template, err := s.store.GetDatabaseTemplateByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database template ID: %v", id)).SetInternal(err)
+		}
+		if template == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database template ID not found: %d", id))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, template); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database template ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/database-template/:databaseTemplateID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseTemplateID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseTemplateID"))).SetInternal(err)
+		}
+
+		templatePatch := &api.DatabaseTemplatePatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, templatePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch database template request").SetInternal(err)
+		}
+
+		template, err := s.store.GetDatabaseTemplateByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database template ID: %v", id)).SetInternal(err)
+		}
+		if template == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database template ID not found: %d", id))
+		}
+		// Validates the template as patched.
+		if v := templatePatch.SheetID; v != nil {
+			template.SheetID = v
+			if *v == 0 {
+				template.SheetID = nil
+			}
+		}
+		if v := templatePatch.Name; v != nil {
+			template.Name = *v
+		}
+		if v := templatePatch.CharacterSet; v != nil {
+			template.CharacterSet = *v
+		}
+		if v := templatePatch.Collation; v != nil {
+			template.Collation = *v
+		}
+		if v := templatePatch.ExtensionList; v != nil {
+			template.ExtensionList = splitCommaSeparatedList(*v)
+		}
+		if v := templatePatch.GranteeList; v != nil {
+			template.GranteeList = splitCommaSeparatedList(*v)
+		}
+		if err := s.validateDatabaseTemplate(ctx, template); err != nil {
+			return err
+		}
+
+		template, err = s.store.PatchDatabaseTemplate(ctx, templatePatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database template ID not found: %d", id))
+			}
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Database template name already exists: %s", *templatePatch.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch database template ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, template); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database template ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/database-template/:databaseTemplateID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseTemplateID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseTemplateID"))).SetInternal(err)
+		}
+
+		templateDelete := &api.DatabaseTemplateDelete{
+			ID:        id,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeleteDatabaseTemplate(ctx, templateDelete); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database template ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete database template ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// validateDatabaseTemplate validates the template and returns the HTTP error if it's invalid.
+func (s *Server) validateDatabaseTemplate(ctx context.Context, template *api.DatabaseTemplate) error {
+	if template.Name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Database template name is required")
+	}
+	if err := checkDatabaseTemplate(template); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid database template: %v", err)).SetInternal(err)
+	}
+	if template.SheetID != nil {
+		sheet, err := s.store.GetSheet(ctx, &api.SheetFind{ID: template.SheetID}, api.SystemBotID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sheet ID: %v", *template.SheetID)).SetInternal(err)
+		}
+		if sheet == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Baseline schema sheet ID not found: %d", *template.SheetID))
+		}
+	}
+	return nil
+}
+
+// checkDatabaseTemplate checks the template settings are supported by the engine.
+// The extension names are quoted in the statement, so only the letters, digits, underscores and hyphens are allowed.
+func checkDatabaseTemplate(template *api.DatabaseTemplate) error {
+	switch template.Engine {
+	case db.MySQL, db.TiDB, db.Postgres, db.SQLite:
+	case db.ClickHouse, db.Snowflake:
+		if template.CharacterSet != "" || template.Collation != "" {
+			return errors.Errorf("%s does not support character set and collation", template.Engine)
+		}
+	default:
+		return errors.Errorf("unsupported engine %q", template.Engine)
+	}
+
+	if len(template.ExtensionList) > 0 && template.Engine != db.Postgres {
+		return errors.Errorf("extensions are only supported for %s", db.Postgres)
+	}
+	for _, extension := range template.ExtensionList {
+		if !extensionNameRegexp.MatchString(extension) {
+			return errors.Errorf("invalid extension name %q", extension)
+		}
+	}
+
+	if len(template.GranteeList) > 0 && template.Engine != db.MySQL && template.Engine != db.TiDB && template.Engine != db.Postgres {
+		return errors.Errorf("grantees are only supported for %s, %s and %s", db.MySQL, db.TiDB, db.Postgres)
+	}
+	for _, grantee := range template.GranteeList {
+		if err := checkGrantee(template.Engine, grantee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkGrantee checks the grantee is a Postgres role name, or in the form of "user@host" for MySQL and TiDB.
+// The grantees are quoted in the statements, so the quotes and the backslashes are rejected.
+func checkGrantee(dbType db.Type, grantee string) error {
+	if grantee == "" || strings.ContainsAny(grantee, "'\"`\\,") {
+		return errors.Errorf("invalid grantee %q", grantee)
+	}
+	if dbType != db.Postgres && strings.HasPrefix(grantee, "@") {
+		return errors.Errorf("invalid grantee %q, the user is missing", grantee)
+	}
+	return nil
+}
+
+// splitGrantee splits the MySQL and TiDB grantee in the form of "user@host", and the host defaults to "%" if omitted.
+func splitGrantee(grantee string) (string, string) {
+	if i := strings.LastIndex(grantee, "@"); i >= 0 {
+		return grantee[:i], grantee[i+1:]
+	}
+	return grantee, "%"
+}
+
+// getDatabaseTemplateStatement returns the statement creating the extensions and granting the privileges on the new database
+// as the template requires. It's executed right after switching to the new database, before the baseline schema.
+func getDatabaseTemplateStatement(dbType db.Type, databaseName string, template *api.DatabaseTemplate) string {
+	var stmtList []string
+	switch dbType {
+	case db.MySQL, db.TiDB:
+		for _, grantee := range template.GranteeList {
+			user, host := splitGrantee(grantee)
+			stmtList = append(stmtList, fmt.Sprintf("GRANT ALL PRIVILEGES ON `%s`.* TO '%s'@'%s';", databaseName, user, host))
+		}
+	case db.Postgres:
+		for _, extension := range template.ExtensionList {
+			stmtList = append(stmtList, fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS \"%s\";", extension))
+		}
+		for _, grantee := range template.GranteeList {
+			stmtList = append(stmtList, fmt.Sprintf("GRANT ALL PRIVILEGES ON DATABASE \"%s\" TO \"%s\";", databaseName, grantee))
+		}
+	}
+	return strings.Join(stmtList, "\n")
+}
+
+// splitCommaSeparatedList splits the comma separated list, and the empty string is an empty list.
+func splitCommaSeparatedList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestCheckDatabaseTemplate(t *testing.T) {
+	tests := []struct {
+		template *api.DatabaseTemplate
+		wantErr  bool
+	}{
+		{
+			template: &api.DatabaseTemplate{Engine: db.Postgres, CharacterSet: "UTF8", ExtensionList: []string{"uuid-ossp", "pg_trgm"}, GranteeList: []string{"app"}},
+		},
+		{
+			template: &api.DatabaseTemplate{Engine: db.MySQL, CharacterSet: "utf8mb4", Collation: "utf8mb4_general_ci", GranteeList: []string{"app@10.0.0.%", "reader"}},
+		},
+		{
+			template: &api.DatabaseTemplate{Engine: db.MySQL, ExtensionList: []string{"pg_trgm"}},
+			wantErr:  true,
+		},
+		{
+			template: &api.DatabaseTemplate{Engine: db.ClickHouse, GranteeList: []string{"app"}},
+			wantErr:  true,
+		},
+		{
+			template: &api.DatabaseTemplate{Engine: db.Snowflake, CharacterSet: "UTF8"},
+			wantErr:  true,
+		},
+		{
+			template: &api.DatabaseTemplate{Engine: db.Postgres, ExtensionList: []string{`pg_trgm"; DROP TABLE t; --`}},
+			wantErr:  true,
+		},
+		{
+			template: &api.DatabaseTemplate{Engine: db.MySQL, GranteeList: []string{"app'@'%"}},
+			wantErr:  true,
+		},
+		{
+			template: &api.DatabaseTemplate{Engine: db.TiDB, GranteeList: []string{"@%"}},
+			wantErr:  true,
+		},
+		{
+			template: &api.DatabaseTemplate{Engine: "ORACLE"},
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		err := checkDatabaseTemplate(test.template)
+		if test.wantErr {
+			assert.Error(t, err, "%+v", test.template)
+		} else {
+			assert.NoError(t, err, "%+v", test.template)
+		}
+	}
+}
+
+func TestGetDatabaseTemplateStatement(t *testing.T) {
+	tests := []struct {
+		dbType   db.Type
+		template *api.DatabaseTemplate
+		want     string
+	}{
+		{
+			dbType:   db.MySQL,
+			template: &api.DatabaseTemplate{GranteeList: []string{"app@10.0.0.%", "reader"}},
+			want:     "GRANT ALL PRIVILEGES ON `db1`.* TO 'app'@'10.0.0.%';\nGRANT ALL PRIVILEGES ON `db1`.* TO 'reader'@'%';",
+		},
+		{
+			dbType:   db.Postgres,
+			template: &api.DatabaseTemplate{ExtensionList: []string{"uuid-ossp"}, GranteeList: []string{"app"}},
+			want:     "CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";\nGRANT ALL PRIVILEGES ON DATABASE \"db1\" TO \"app\";",
+		},
+		{
+			dbType:   db.ClickHouse,
+			template: &api.DatabaseTemplate{},
+			want:     "",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, getDatabaseTemplateStatement(test.dbType, "db1", test.template))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -151,3 +152,18 @@ func TestValidateDatabaseLabelList(t *testing.T) {
 		}
 	}
 }
+
+func TestGetSearchPathSessionVariable(t *testing.T) {
+	tests := []struct {
+		schemaName string
+		want       string
+	}{
+		{schemaName: "sales", want: `"sales", public`},
+		{schemaName: "Sales Team", want: `"Sales Team", public`},
+		{schemaName: `a"b`, want: `"a""b", public`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, db.SessionVariable{Name: "search_path", Value: test.want}, getSearchPathSessionVariable(test.schemaName))
+	}
+}
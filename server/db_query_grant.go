@@ -0,0 +1,318 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+// maxDBWriteGrantDuration is the max duration of the elevated write access, which is revoked automatically after it expires.
+const maxDBWriteGrantDuration = 8 * time.Hour
+
+func (s *Server) registerDBQueryGrantRoutes(g *echo.Group) {
+	g.GET("/database/:id/query-grant", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		find := &api.DBQueryGrantFind{DatabaseID: &id}
+		if statusStr := c.QueryParams().Get("status"); statusStr != "" {
+			status := api.DBQueryGrantStatus(statusStr)
+			find.Status = &status
+		}
+		dbQueryGrantList, err := s.store.FindDBQueryGrant(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch query grant list for database ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dbQueryGrantList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal query grant list response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Requests the query grant or the elevated write access of the database, which is pending until approved by an owner or a DBA.
+	g.POST("/database/:id/query-grant", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		create := &api.DBQueryGrantCreate{
+			CreatorID:  principalID,
+			DatabaseID: id,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create query grant request").SetInternal(err)
+		}
+		// Requesting for oneself by default.
+		if create.PrincipalID == 0 && create.Role == "" {
+			create.PrincipalID = principalID
+		}
+		if create.Access == "" {
+			create.Access = api.DBQueryGrantAccessQuery
+		}
+		if err := validateDBQueryGrantCreate(create, c.Get(getRoleContextKey()).(api.Role), principalID, time.Now().Unix()); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid query grant: %v", err))
+		}
+		if create.PrincipalID != 0 {
+			member, err := s.store.GetMemberByPrincipalID(ctx, create.PrincipalID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch member for principal ID: %d", create.PrincipalID)).SetInternal(err)
+			}
+			if member == nil || member.WorkspaceID != c.Get(getWorkspaceIDContextKey()).(int) {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Principal ID %d is not a member of the workspace", create.PrincipalID))
+			}
+		}
+
+		dbQueryGrant, err := s.store.CreateDBQueryGrant(ctx, create)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create query grant for database ID: %d", id)).SetInternal(err)
+		}
+		if dbQueryGrant.Access == api.DBQueryGrantAccessWrite {
+			if err := s.createDBWriteGrantActivity(ctx, principalID, database, dbQueryGrant); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity for query grant ID: %d", dbQueryGrant.ID)).SetInternal(err)
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dbQueryGrant); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal query grant response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Approves or rejects the pending query grant, or revokes the approved one.
+	g.PATCH("/database/:id/query-grant/:grantID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		grantID, err := strconv.Atoi(c.Param("grantID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Grant ID is not a number: %s", c.Param("grantID"))).SetInternal(err)
+		}
+
+		patch := &api.DBQueryGrantPatch{
+			ID:         grantID,
+			UpdaterID:  c.Get(getPrincipalIDContextKey()).(int),
+			DatabaseID: id,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, patch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch query grant request").SetInternal(err)
+		}
+
+		dbQueryGrantList, err := s.store.FindDBQueryGrant(ctx, &api.DBQueryGrantFind{ID: &grantID, DatabaseID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch query grant ID: %d", grantID)).SetInternal(err)
+		}
+		if len(dbQueryGrantList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Query grant ID %d not found in database ID %d", grantID, id))
+		}
+		if err := validateDBQueryGrantTransition(dbQueryGrantList[0].Status, patch.Status); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid query grant status: %v", err))
+		}
+		if err := validateDBQueryGrantApprover(dbQueryGrantList[0], patch.Status, patch.UpdaterID); err != nil {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+
+		dbQueryGrant, err := s.store.PatchDBQueryGrant(ctx, patch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Query grant ID %d not found in database ID %d", grantID, id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch query grant ID: %d", grantID)).SetInternal(err)
+		}
+		if dbQueryGrant.Access == api.DBQueryGrantAccessWrite {
+			database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+			}
+			if database == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+			}
+			if err := s.createDBWriteGrantActivity(ctx, patch.UpdaterID, database, dbQueryGrant); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity for query grant ID: %d", grantID)).SetInternal(err)
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dbQueryGrant); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal query grant response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// validateDBQueryGrantCreate validates the query grant requested by the principal of the role.
+// The developers can only request the grants for themselves, and the role grants are requested by the owners and the DBAs.
+// The elevated write access is only granted to a principal for a stated reason, and expires within maxDBWriteGrantDuration.
+func validateDBQueryGrantCreate(create *api.DBQueryGrantCreate, role api.Role, principalID int, now int64) error {
+	if (create.PrincipalID == 0) == (create.Role == "") {
+		return errors.Errorf("exactly one of principalId and role must be set")
+	}
+	switch create.Access {
+	case api.DBQueryGrantAccessQuery:
+	case api.DBQueryGrantAccessWrite:
+		if create.Role != "" {
+			return errors.Errorf("the elevated write access can only be granted to a principal")
+		}
+		if create.Reason == "" {
+			return errors.Errorf("reason is required for the elevated write access")
+		}
+		if maxExpireTs := now + int64(maxDBWriteGrantDuration.Seconds()); create.ExpireTs > maxExpireTs {
+			return errors.Errorf("the elevated write access must expire within %v, expireTs must be no later than %d", maxDBWriteGrantDuration, maxExpireTs)
+		}
+	default:
+		return errors.Errorf("access must be %s or %s, got %q", api.DBQueryGrantAccessQuery, api.DBQueryGrantAccessWrite, create.Access)
+	}
+	// The owners and the DBAs can query every database without any grant.
+	if create.Role != "" && create.Role != api.Developer {
+		return errors.Errorf("role must be %s, got %q", api.Developer, create.Role)
+	}
+	if role == api.Developer && (create.Role != "" || create.PrincipalID != principalID) {
+		return errors.Errorf("developers can only request the query grant for themselves")
+	}
+	if create.ExpireTs <= now {
+		return errors.Errorf("expireTs must be in the future, got %d", create.ExpireTs)
+	}
+	return nil
+}
+
+// validateDBQueryGrantTransition validates the status change of the query grant.
+// The pending grant is approved or rejected, and the approved grant is revoked.
+func validateDBQueryGrantTransition(from, to api.DBQueryGrantStatus) error {
+	switch {
+	case from == api.DBQueryGrantPending && (to == api.DBQueryGrantApproved || to == api.DBQueryGrantRejected):
+		return nil
+	case from == api.DBQueryGrantApproved && to == api.DBQueryGrantRevoked:
+		return nil
+	}
+	return errors.Errorf("cannot change the status from %s to %s", from, to)
+}
+
+// validateDBQueryGrantApprover validates the updater changing the status of the query grant.
+// The elevated write access can't be approved by the requester or the grantee.
+func validateDBQueryGrantApprover(dbQueryGrant *api.DBQueryGrant, status api.DBQueryGrantStatus, updaterID int) error {
+	if dbQueryGrant.Access != api.DBQueryGrantAccessWrite || status != api.DBQueryGrantApproved {
+		return nil
+	}
+	if updaterID == dbQueryGrant.CreatorID || updaterID == dbQueryGrant.PrincipalID {
+		return errors.Errorf("the elevated write access must be approved by someone other than the requester and the grantee")
+	}
+	return nil
+}
+
+// hasActiveDBQueryGrant returns true if any grant of the list allows the principal of the role to query at the time.
+func hasActiveDBQueryGrant(dbQueryGrantList []*api.DBQueryGrant, principalID int, role api.Role, ts int64) bool {
+	for _, dbQueryGrant := range dbQueryGrantList {
+		if !dbQueryGrant.IsActive(ts) {
+			continue
+		}
+		if (dbQueryGrant.PrincipalID != 0 && dbQueryGrant.PrincipalID == principalID) || (dbQueryGrant.Role != "" && dbQueryGrant.Role == role) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDBQueryGrant returns true if the principal of the role has an active query grant of the access on the database.
+func (s *Server) checkDBQueryGrant(ctx context.Context, databaseID int, principalID int, role api.Role, access api.DBQueryGrantAccess) (bool, error) {
+	status := api.DBQueryGrantApproved
+	dbQueryGrantList, err := s.store.FindDBQueryGrant(ctx, &api.DBQueryGrantFind{
+		DatabaseID: &databaseID,
+		Status:     &status,
+		Access:     &access,
+	})
+	if err != nil {
+		return false, err
+	}
+	return hasActiveDBQueryGrant(dbQueryGrantList, principalID, role, time.Now().Unix()), nil
+}
+
+// expireDBQueryGrant revokes the approved query grants after they expire, and records the activities for the elevated write access.
+func (s *Server) expireDBQueryGrant(ctx context.Context) {
+	dbQueryGrantList, err := s.store.ExpireDBQueryGrant(ctx, time.Now().Unix())
+	if err != nil {
+		log.Error("Failed to expire the query grants.", zap.Error(err))
+		return
+	}
+	for _, dbQueryGrant := range dbQueryGrantList {
+		if dbQueryGrant.Access != api.DBQueryGrantAccessWrite {
+			continue
+		}
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &dbQueryGrant.DatabaseID})
+		if err != nil || database == nil {
+			log.Error("Failed to fetch the database of the expired query grant.", zap.Int("grant_id", dbQueryGrant.ID), zap.Int("database_id", dbQueryGrant.DatabaseID), zap.Error(err))
+			continue
+		}
+		if err := s.createDBWriteGrantActivity(ctx, api.SystemBotID, database, dbQueryGrant); err != nil {
+			log.Error("Failed to create activity for the expired query grant.", zap.Int("grant_id", dbQueryGrant.ID), zap.Error(err))
+		}
+	}
+}
+
+// createDBWriteGrantActivity records the status of the elevated write access as a warning in the project of the database.
+func (s *Server) createDBWriteGrantActivity(ctx context.Context, creatorID int, database *api.Database, dbQueryGrant *api.DBQueryGrant) error {
+	payload, err := json.Marshal(api.ActivityDatabaseWriteGrantUpdatePayload{
+		DatabaseID:   database.ID,
+		GrantID:      dbQueryGrant.ID,
+		PrincipalID:  dbQueryGrant.PrincipalID,
+		Status:       dbQueryGrant.Status,
+		ExpireTs:     dbQueryGrant.ExpireTs,
+		DatabaseName: database.Name,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal elevated write access activity payload")
+	}
+	var comment string
+	switch dbQueryGrant.Status {
+	case api.DBQueryGrantPending:
+		comment = fmt.Sprintf("Requested elevated write access to database %q until %s, reason: %q.", database.Name, time.Unix(dbQueryGrant.ExpireTs, 0).UTC().Format(time.RFC3339), dbQueryGrant.Reason)
+	case api.DBQueryGrantApproved:
+		comment = fmt.Sprintf("Approved elevated write access to database %q until %s.", database.Name, time.Unix(dbQueryGrant.ExpireTs, 0).UTC().Format(time.RFC3339))
+	case api.DBQueryGrantRejected:
+		comment = fmt.Sprintf("Rejected elevated write access to database %q.", database.Name)
+	case api.DBQueryGrantRevoked:
+		comment = fmt.Sprintf("Revoked elevated write access to database %q.", database.Name)
+	case api.DBQueryGrantExpired:
+		comment = fmt.Sprintf("Elevated write access to database %q expired and was revoked.", database.Name)
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   creatorID,
+		ContainerID: database.ProjectID,
+		Type:        api.ActivityDatabaseWriteGrantUpdate,
+		Level:       api.ActivityWarn,
+		Comment:     comment,
+		Payload:     string(payload),
+	}, &ActivityMeta{}); err != nil {
+		return errors.Wrapf(err, "failed to create elevated write access activity")
+	}
+	return nil
+}
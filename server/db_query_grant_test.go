@@ -0,0 +1,163 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestValidateDBQueryGrantCreate(t *testing.T) {
+	now := int64(1664500000)
+	tests := []struct {
+		create  *api.DBQueryGrantCreate
+		role    api.Role
+		wantErr bool
+	}{
+		{
+			create: &api.DBQueryGrantCreate{PrincipalID: 101, ExpireTs: now + 3600, Access: api.DBQueryGrantAccessQuery},
+			role:   api.Developer,
+		},
+		{
+			create: &api.DBQueryGrantCreate{Role: api.Developer, ExpireTs: now + 3600, Access: api.DBQueryGrantAccessQuery},
+			role:   api.DBA,
+		},
+		{
+			create: &api.DBQueryGrantCreate{PrincipalID: 102, ExpireTs: now + 3600, Access: api.DBQueryGrantAccessQuery},
+			role:   api.Owner,
+		},
+		// Developers can't request for others.
+		{
+			create:  &api.DBQueryGrantCreate{PrincipalID: 102, ExpireTs: now + 3600, Access: api.DBQueryGrantAccessQuery},
+			role:    api.Developer,
+			wantErr: true,
+		},
+		{
+			create:  &api.DBQueryGrantCreate{Role: api.Developer, ExpireTs: now + 3600, Access: api.DBQueryGrantAccessQuery},
+			role:    api.Developer,
+			wantErr: true,
+		},
+		{
+			create:  &api.DBQueryGrantCreate{Role: api.DBA, ExpireTs: now + 3600, Access: api.DBQueryGrantAccessQuery},
+			role:    api.Owner,
+			wantErr: true,
+		},
+		{
+			create:  &api.DBQueryGrantCreate{PrincipalID: 101, Role: api.Developer, ExpireTs: now + 3600, Access: api.DBQueryGrantAccessQuery},
+			role:    api.Owner,
+			wantErr: true,
+		},
+		{
+			create:  &api.DBQueryGrantCreate{PrincipalID: 101, ExpireTs: now, Access: api.DBQueryGrantAccessQuery},
+			role:    api.Developer,
+			wantErr: true,
+		},
+		{
+			create:  &api.DBQueryGrantCreate{PrincipalID: 101, ExpireTs: now + 3600, Access: "ADMIN"},
+			role:    api.Developer,
+			wantErr: true,
+		},
+		// The elevated write access.
+		{
+			create: &api.DBQueryGrantCreate{PrincipalID: 101, ExpireTs: now + 3600, Reason: "Fix the order status", Access: api.DBQueryGrantAccessWrite},
+			role:   api.Developer,
+		},
+		{
+			create: &api.DBQueryGrantCreate{PrincipalID: 101, ExpireTs: now + int64(maxDBWriteGrantDuration.Seconds()), Reason: "Fix the order status", Access: api.DBQueryGrantAccessWrite},
+			role:   api.Owner,
+		},
+		{
+			create:  &api.DBQueryGrantCreate{PrincipalID: 101, ExpireTs: now + int64(maxDBWriteGrantDuration.Seconds()) + 1, Reason: "Fix the order status", Access: api.DBQueryGrantAccessWrite},
+			role:    api.Owner,
+			wantErr: true,
+		},
+		{
+			create:  &api.DBQueryGrantCreate{PrincipalID: 101, ExpireTs: now + 3600, Access: api.DBQueryGrantAccessWrite},
+			role:    api.Developer,
+			wantErr: true,
+		},
+		{
+			create:  &api.DBQueryGrantCreate{Role: api.Developer, ExpireTs: now + 3600, Reason: "Fix the order status", Access: api.DBQueryGrantAccessWrite},
+			role:    api.DBA,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := validateDBQueryGrantCreate(test.create, test.role, 101, now)
+		if test.wantErr {
+			require.Error(t, err, "%+v", test.create)
+		} else {
+			require.NoError(t, err, "%+v", test.create)
+		}
+	}
+}
+
+func TestValidateDBQueryGrantTransition(t *testing.T) {
+	a := require.New(t)
+	a.NoError(validateDBQueryGrantTransition(api.DBQueryGrantPending, api.DBQueryGrantApproved))
+	a.NoError(validateDBQueryGrantTransition(api.DBQueryGrantPending, api.DBQueryGrantRejected))
+	a.NoError(validateDBQueryGrantTransition(api.DBQueryGrantApproved, api.DBQueryGrantRevoked))
+	a.Error(validateDBQueryGrantTransition(api.DBQueryGrantPending, api.DBQueryGrantRevoked))
+	a.Error(validateDBQueryGrantTransition(api.DBQueryGrantRejected, api.DBQueryGrantApproved))
+	a.Error(validateDBQueryGrantTransition(api.DBQueryGrantRevoked, api.DBQueryGrantApproved))
+	a.Error(validateDBQueryGrantTransition(api.DBQueryGrantApproved, api.DBQueryGrantPending))
+	// The grant is only expired by the server.
+	a.Error(validateDBQueryGrantTransition(api.DBQueryGrantApproved, api.DBQueryGrantExpired))
+}
+
+func TestValidateDBQueryGrantApprover(t *testing.T) {
+	a := require.New(t)
+	writeGrant := &api.DBQueryGrant{CreatorID: 101, PrincipalID: 102, Status: api.DBQueryGrantPending, Access: api.DBQueryGrantAccessWrite}
+	a.NoError(validateDBQueryGrantApprover(writeGrant, api.DBQueryGrantApproved, 103))
+	a.Error(validateDBQueryGrantApprover(writeGrant, api.DBQueryGrantApproved, 101))
+	a.Error(validateDBQueryGrantApprover(writeGrant, api.DBQueryGrantApproved, 102))
+	// The grantee can reject or revoke the elevated write access.
+	a.NoError(validateDBQueryGrantApprover(writeGrant, api.DBQueryGrantRejected, 102))
+	a.NoError(validateDBQueryGrantApprover(writeGrant, api.DBQueryGrantRevoked, 102))
+
+	queryGrant := &api.DBQueryGrant{CreatorID: 101, PrincipalID: 101, Status: api.DBQueryGrantPending, Access: api.DBQueryGrantAccessQuery}
+	a.NoError(validateDBQueryGrantApprover(queryGrant, api.DBQueryGrantApproved, 101))
+}
+
+func TestHasActiveDBQueryGrant(t *testing.T) {
+	now := int64(1664500000)
+	tests := []struct {
+		grant *api.DBQueryGrant
+		want  bool
+	}{
+		{
+			grant: &api.DBQueryGrant{PrincipalID: 101, Status: api.DBQueryGrantApproved, ExpireTs: now + 1},
+			want:  true,
+		},
+		{
+			grant: &api.DBQueryGrant{Role: api.Developer, Status: api.DBQueryGrantApproved, ExpireTs: now + 1},
+			want:  true,
+		},
+		// Expired.
+		{
+			grant: &api.DBQueryGrant{PrincipalID: 101, Status: api.DBQueryGrantApproved, ExpireTs: now},
+			want:  false,
+		},
+		{
+			grant: &api.DBQueryGrant{PrincipalID: 101, Status: api.DBQueryGrantPending, ExpireTs: now + 1},
+			want:  false,
+		},
+		{
+			grant: &api.DBQueryGrant{PrincipalID: 101, Status: api.DBQueryGrantRevoked, ExpireTs: now + 1},
+			want:  false,
+		},
+		// Granted to another principal.
+		{
+			grant: &api.DBQueryGrant{PrincipalID: 102, Status: api.DBQueryGrantApproved, ExpireTs: now + 1},
+			want:  false,
+		},
+	}
+
+	for _, test := range tests {
+		got := hasActiveDBQueryGrant([]*api.DBQueryGrant{test.grant}, 101, api.Developer, now)
+		require.Equal(t, test.want, got, "%+v", test.grant)
+	}
+	require.False(t, hasActiveDBQueryGrant(nil, 101, api.Developer, now))
+}
@@ -4,9 +4,15 @@ import (
 	"encoding/json"
 	"sort"
 
+	"github.com/pkg/errors"
+
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
 )
 
+// canaryStageName is the name of the stage of the canary databases in the tenant batch change.
+const canaryStageName = "Canary"
+
 // isMatchExpression checks whether a databases matches the query.
 // labels is a mapping from database label key to value.
 func isMatchExpression(labels map[string]string, expression *api.LabelSelectorRequirement) bool {
@@ -55,16 +61,11 @@ func getDatabaseMatrixFromDeploymentSchedule(schedule *api.DeploymentSchedule, b
 	databaseMap := make(map[int]*api.Database)
 	for _, database := range databaseList {
 		databaseMap[database.ID] = database
-		if _, ok := idToLabels[database.ID]; !ok {
-			idToLabels[database.ID] = make(map[string]string)
-		}
-		var labelList []*api.DatabaseLabel
-		if err := json.Unmarshal([]byte(database.Labels), &labelList); err != nil {
+		labels, err := getDatabaseLabelMap(database)
+		if err != nil {
 			return nil, nil, err
 		}
-		for _, label := range labelList {
-			idToLabels[database.ID][label.Key] = label.Value
-		}
+		idToLabels[database.ID] = labels
 	}
 
 	// idsSeen records database id which is already in a stage.
@@ -114,6 +115,80 @@ func getDatabaseMatrixFromDeploymentSchedule(schedule *api.DeploymentSchedule, b
 	return deployments, matrix, nil
 }
 
+// getCanaryDatabaseMatrix takes the canary databases out of the deployment matrix.
+// It returns the canary databases, and the remaining deployments and matrix without the deployments left empty.
+func getCanaryDatabaseMatrix(canary *api.CanaryConfig, deployments []*api.Deployment, matrix [][]*api.Database) ([]*api.Database, []*api.Deployment, [][]*api.Database, error) {
+	if (canary.Percentage == 0) == (canary.Selector == nil) {
+		return nil, nil, nil, common.Errorf(common.Invalid, "canary should have either percentage or selector")
+	}
+	if canary.Percentage < 0 || canary.Percentage >= 100 {
+		return nil, nil, nil, common.Errorf(common.Invalid, "canary percentage should be between 1 and 99, got %d", canary.Percentage)
+	}
+	if canary.SoakSeconds < 0 {
+		return nil, nil, nil, common.Errorf(common.Invalid, "canary soak seconds should not be negative, got %d", canary.SoakSeconds)
+	}
+
+	total := 0
+	for _, databaseList := range matrix {
+		total += len(databaseList)
+	}
+	// The percentage is rounded up so that there is at least one canary database.
+	canaryCount := (total*canary.Percentage + 99) / 100
+
+	var canaryList []*api.Database
+	var remainingDeployments []*api.Deployment
+	var remainingMatrix [][]*api.Database
+	for i, databaseList := range matrix {
+		var remainingList []*api.Database
+		for _, database := range databaseList {
+			isCanary := len(canaryList) < canaryCount
+			if canary.Selector != nil {
+				labels, err := getDatabaseLabelMap(database)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				isCanary = isMatchExpressions(labels, canary.Selector.MatchExpressions)
+			}
+			if isCanary {
+				canaryList = append(canaryList, database)
+			} else {
+				remainingList = append(remainingList, database)
+			}
+		}
+		if len(remainingList) > 0 {
+			remainingDeployments = append(remainingDeployments, deployments[i])
+			remainingMatrix = append(remainingMatrix, remainingList)
+		}
+	}
+
+	if len(canaryList) == 0 {
+		return nil, nil, nil, common.Errorf(common.Invalid, "canary selector matches no database")
+	}
+	if len(remainingMatrix) == 0 {
+		return nil, nil, nil, common.Errorf(common.Invalid, "canary should not include all the %d databases", total)
+	}
+	// The canary databases make up a single stage, which has one environment.
+	for _, database := range canaryList {
+		if database.Instance.EnvironmentID != canaryList[0].Instance.EnvironmentID {
+			return nil, nil, nil, common.Errorf(common.Invalid, "canary databases should be in the same environment, got %q and %q", canaryList[0].Name, database.Name)
+		}
+	}
+	return canaryList, remainingDeployments, remainingMatrix, nil
+}
+
+// getDatabaseLabelMap returns the mapping from the label key to value of the database.
+func getDatabaseLabelMap(database *api.Database) (map[string]string, error) {
+	var labelList []*api.DatabaseLabel
+	if err := json.Unmarshal([]byte(database.Labels), &labelList); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal labels of database %q", database.Name)
+	}
+	labels := make(map[string]string)
+	for _, label := range labelList {
+		labels[label.Key] = label.Value
+	}
+	return labels, nil
+}
+
 // formatDatabaseName will return the full database name given the dbNameTemplate, base database name, and labels.
 func formatDatabaseName(baseDatabaseName, dbNameTemplate string, labels map[string]string) (string, error) {
 	if dbNameTemplate == "" {
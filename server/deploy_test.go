@@ -247,3 +247,84 @@ func TestGetDatabaseMatrixFromDeploymentSchedule(t *testing.T) {
 		assert.Equal(t, matrix, test.want)
 	}
 }
+
+func TestGetCanaryDatabaseMatrix(t *testing.T) {
+	instance := &api.Instance{EnvironmentID: 1}
+	dbs := []*api.Database{
+		{ID: 0, Name: "db0", Instance: instance, Labels: "[{\"key\":\"bb.location\",\"value\":\"us\"}]"},
+		{ID: 1, Name: "db1", Instance: instance, Labels: "[{\"key\":\"bb.location\",\"value\":\"eu\"}]"},
+		{ID: 2, Name: "db2", Instance: instance, Labels: "[{\"key\":\"bb.location\",\"value\":\"us\"}]"},
+		{ID: 3, Name: "db3", Instance: instance, Labels: "[{\"key\":\"bb.location\",\"value\":\"asia\"}]"},
+	}
+	deployments := []*api.Deployment{{Name: "d0"}, {Name: "d1"}}
+	matrix := [][]*api.Database{{dbs[0], dbs[1]}, {dbs[2], dbs[3]}}
+
+	tests := []struct {
+		name            string
+		canary          *api.CanaryConfig
+		wantCanary      []*api.Database
+		wantDeployments []*api.Deployment
+		wantMatrix      [][]*api.Database
+		wantErr         bool
+	}{
+		{
+			name:            "percentage rounded up",
+			canary:          &api.CanaryConfig{Percentage: 30},
+			wantCanary:      []*api.Database{dbs[0], dbs[1]},
+			wantDeployments: []*api.Deployment{deployments[1]},
+			wantMatrix:      [][]*api.Database{{dbs[2], dbs[3]}},
+		},
+		{
+			name:            "percentage",
+			canary:          &api.CanaryConfig{Percentage: 10},
+			wantCanary:      []*api.Database{dbs[0]},
+			wantDeployments: deployments,
+			wantMatrix:      [][]*api.Database{{dbs[1]}, {dbs[2], dbs[3]}},
+		},
+		{
+			name: "selector",
+			canary: &api.CanaryConfig{Selector: &api.LabelSelector{
+				MatchExpressions: []*api.LabelSelectorRequirement{{Key: "bb.location", Operator: api.InOperatorType, Values: []string{"us"}}},
+			}},
+			wantCanary:      []*api.Database{dbs[0], dbs[2]},
+			wantDeployments: deployments,
+			wantMatrix:      [][]*api.Database{{dbs[1]}, {dbs[3]}},
+		},
+		{
+			name: "selector matching no database",
+			canary: &api.CanaryConfig{Selector: &api.LabelSelector{
+				MatchExpressions: []*api.LabelSelectorRequirement{{Key: "bb.location", Operator: api.InOperatorType, Values: []string{"mars"}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "selector matching all databases",
+			canary: &api.CanaryConfig{Selector: &api.LabelSelector{
+				MatchExpressions: []*api.LabelSelectorRequirement{{Key: "bb.location", Operator: api.ExistsOperatorType}},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "both percentage and selector",
+			canary:  &api.CanaryConfig{Percentage: 10, Selector: &api.LabelSelector{}},
+			wantErr: true,
+		},
+		{
+			name:    "percentage out of range",
+			canary:  &api.CanaryConfig{Percentage: 100},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		canaryList, remainingDeployments, remainingMatrix, err := getCanaryDatabaseMatrix(test.canary, deployments, matrix)
+		if test.wantErr {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.wantCanary, canaryList, test.name)
+		assert.Equal(t, test.wantDeployments, remainingDeployments, test.name)
+		assert.Equal(t, test.wantMatrix, remainingMatrix, test.name)
+	}
+}
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// driverPoolMaxOpen is the max number of the opened drivers of each instance database and data source, both in use and idle.
+	driverPoolMaxOpen = 8
+	// driverPoolIdleTimeout is the time after which the idle driver is closed.
+	driverPoolIdleTimeout = 5 * time.Minute
+	// driverPoolWaitTimeout is the max time to wait for a driver if there are driverPoolMaxOpen drivers in use.
+	// It fails instead of waiting forever, e.g. if the callers holding the drivers are waiting for another driver themselves.
+	driverPoolWaitTimeout = 30 * time.Second
+	// driverPoolSweepInterval is the interval of closing the idle drivers timed out.
+	driverPoolSweepInterval = time.Minute
+)
+
+// driverPool keeps the opened database drivers for reuse, so that the schema syncer, the task checks and the task executors
+// don't open new connections to the database for each use. The drivers are exclusive to the caller until it calls Close,
+// which returns the driver to the pool. Only the drivers implementing db.Resetter are reused, the others are closed on Close.
+type driverPool struct {
+	maxOpen     int
+	idleTimeout time.Duration
+	waitTimeout time.Duration
+
+	mu sync.Mutex
+	// entryMap is the map from the pool key to the drivers of the same connection config.
+	entryMap map[string]*driverPoolEntry
+}
+
+// driverPoolEntry is the drivers of the same connection config.
+type driverPoolEntry struct {
+	// openCount is the number of the opened drivers, both in use and idle.
+	openCount int
+	// idleList is the idle drivers, the most recently used is the last.
+	idleList []*idleDriver
+	// released is closed and replaced when a driver is released, waking up the callers waiting for a driver.
+	released chan struct{}
+}
+
+type idleDriver struct {
+	driver     db.Driver
+	releasedTs time.Time
+}
+
+// pooledDriver is the driver lent by the pool, Close returns it to the pool.
+type pooledDriver struct {
+	db.Driver
+	pool      *driverPool
+	key       string
+	closeOnce sync.Once
+}
+
+func newDriverPool() *driverPool {
+	return &driverPool{
+		maxOpen:     driverPoolMaxOpen,
+		idleTimeout: driverPoolIdleTimeout,
+		waitTimeout: driverPoolWaitTimeout,
+		entryMap:    make(map[string]*driverPoolEntry),
+	}
+}
+
+// getDriverPoolKey returns the pool key of the instance database connected with the config.
+// The config is hashed so that the drivers opened before changing the instance, e.g. the password, aren't reused.
+func getDriverPoolKey(instanceID int, driverConfig db.DriverConfig, connCfg db.ConnectionConfig) (string, error) {
+	configBytes, err := json.Marshal(struct {
+		DriverConfig     db.DriverConfig
+		ConnectionConfig db.ConnectionConfig
+		// The dialer isn't marshaled, the drivers dialing through it are kept apart from the others.
+		Dialer bool
+	}{driverConfig, connCfg, connCfg.Dialer != nil})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(configBytes)
+	return fmt.Sprintf("%d/%s/%s", instanceID, connCfg.Database, hex.EncodeToString(sum[:])), nil
+}
+
+// Close returns the driver to the pool, it's safe to call more than once.
+func (d *pooledDriver) Close(ctx context.Context) error {
+	d.closeOnce.Do(func() {
+		d.pool.release(ctx, d.key, d.Driver)
+	})
+	return nil
+}
+
+// unwrapDriver returns the driver opened by the engine plugin, for the type assertion of the engine specific features.
+func unwrapDriver(driver db.Driver) db.Driver {
+	if d, ok := driver.(*pooledDriver); ok {
+		return d.Driver
+	}
+	return driver
+}
+
+// get returns an idle driver of the key, or opens a new one with open if there is none.
+// It waits for a driver to be released if there are maxOpen drivers in use.
+func (p *driverPool) get(ctx context.Context, key string, open func(context.Context) (db.Driver, error)) (db.Driver, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.waitTimeout)
+	defer cancel()
+	for {
+		p.mu.Lock()
+		entry, ok := p.entryMap[key]
+		if !ok {
+			entry = &driverPoolEntry{released: make(chan struct{})}
+			p.entryMap[key] = entry
+		}
+		if n := len(entry.idleList); n > 0 {
+			idle := entry.idleList[n-1]
+			entry.idleList = entry.idleList[:n-1]
+			p.mu.Unlock()
+			// The idle connections may be closed by the database, e.g. wait_timeout of MySQL.
+			if err := idle.driver.Ping(ctx); err != nil {
+				p.discard(ctx, key, idle.driver)
+				continue
+			}
+			return &pooledDriver{Driver: idle.driver, pool: p, key: key}, nil
+		}
+		if entry.openCount < p.maxOpen {
+			entry.openCount++
+			p.mu.Unlock()
+			driver, err := open(ctx)
+			if err != nil {
+				p.discard(ctx, key, nil)
+				return nil, err
+			}
+			return &pooledDriver{Driver: driver, pool: p, key: key}, nil
+		}
+		released := entry.released
+		p.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, common.Errorf(common.DbConnectionFailure, "timed out waiting for the database connection, %d connections are in use", p.maxOpen)
+		}
+	}
+}
+
+// release returns the driver to the idle list if it can be reused, otherwise closes it.
+func (p *driverPool) release(ctx context.Context, key string, driver db.Driver) {
+	resetter, ok := driver.(db.Resetter)
+	if !ok {
+		p.discard(ctx, key, driver)
+		return
+	}
+	if err := resetter.Reset(ctx); err != nil {
+		log.Warn("Failed to reset the database driver", zap.Error(err))
+		p.discard(ctx, key, driver)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry := p.entryMap[key]
+	entry.idleList = append(entry.idleList, &idleDriver{
+		driver:     driver,
+		releasedTs: time.Now(),
+	})
+	entry.notifyReleased()
+}
+
+// discard closes the driver which isn't returned to the pool, the driver is nil if it failed to open.
+func (p *driverPool) discard(ctx context.Context, key string, driver db.Driver) {
+	if driver != nil {
+		if err := driver.Close(ctx); err != nil {
+			log.Warn("Failed to close the database driver", zap.Error(err))
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry := p.entryMap[key]
+	entry.openCount--
+	entry.notifyReleased()
+	if entry.openCount == 0 {
+		delete(p.entryMap, key)
+	}
+}
+
+func (e *driverPoolEntry) notifyReleased() {
+	close(e.released)
+	e.released = make(chan struct{})
+}
+
+// Run closes the idle drivers timed out periodically, and all the idle drivers on exit.
+func (p *driverPool) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(driverPoolSweepInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	for {
+		select {
+		case <-ticker.C:
+			p.closeIdle(ctx, time.Now().Add(-p.idleTimeout))
+		case <-ctx.Done():
+			// The context is canceled, closing the drivers with a fresh one.
+			p.closeIdle(context.Background(), time.Now())
+			return
+		}
+	}
+}
+
+// closeIdle closes the idle drivers released before the time.
+func (p *driverPool) closeIdle(ctx context.Context, before time.Time) {
+	p.closeIdleIf(ctx, func(_ string, idle *idleDriver) bool {
+		return !idle.releasedTs.After(before)
+	})
+}
+
+// closeInstance closes the idle drivers of the instance, e.g. after the instance is changed, so that they don't
+// reconnect the database with the previous connection config.
+func (p *driverPool) closeInstance(ctx context.Context, instanceID int) {
+	prefix := fmt.Sprintf("%d/", instanceID)
+	p.closeIdleIf(ctx, func(key string, _ *idleDriver) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// closeIdleIf closes the idle drivers matching the filter.
+func (p *driverPool) closeIdleIf(ctx context.Context, filter func(key string, idle *idleDriver) bool) {
+	p.mu.Lock()
+	type expiredDriver struct {
+		key    string
+		driver db.Driver
+	}
+	var expiredList []expiredDriver
+	for key, entry := range p.entryMap {
+		var idleList []*idleDriver
+		for _, idle := range entry.idleList {
+			if !filter(key, idle) {
+				idleList = append(idleList, idle)
+				continue
+			}
+			expiredList = append(expiredList, expiredDriver{key: key, driver: idle.driver})
+		}
+		entry.idleList = idleList
+	}
+	p.mu.Unlock()
+
+	for _, expired := range expiredList {
+		p.discard(ctx, expired.key, expired.driver)
+	}
+}
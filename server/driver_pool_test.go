@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// fakeDriver implements the methods of db.Driver used by the driver pool.
+type fakeDriver struct {
+	db.Driver
+	pingErr error
+	closed  bool
+}
+
+func (d *fakeDriver) Ping(context.Context) error {
+	return d.pingErr
+}
+
+func (d *fakeDriver) Close(context.Context) error {
+	d.closed = true
+	return nil
+}
+
+type fakeResettableDriver struct {
+	fakeDriver
+	resetCount int
+}
+
+func (d *fakeResettableDriver) Reset(context.Context) error {
+	d.resetCount++
+	return nil
+}
+
+func TestDriverPoolReuse(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newDriverPool()
+	openCount := 0
+	open := func(context.Context) (db.Driver, error) {
+		openCount++
+		return &fakeResettableDriver{}, nil
+	}
+
+	driver, err := pool.get(ctx, "1/db", open)
+	a.NoError(err)
+	rawDriver := unwrapDriver(driver).(*fakeResettableDriver)
+	a.NoError(driver.Close(ctx))
+	// Closing twice doesn't return the driver twice.
+	a.NoError(driver.Close(ctx))
+	a.Equal(1, rawDriver.resetCount)
+	a.False(rawDriver.closed)
+
+	reusedDriver, err := pool.get(ctx, "1/db", open)
+	a.NoError(err)
+	a.Same(rawDriver, unwrapDriver(reusedDriver))
+	a.Equal(1, openCount)
+
+	// The driver in use isn't lent to others.
+	otherDriver, err := pool.get(ctx, "1/db", open)
+	a.NoError(err)
+	a.NotSame(rawDriver, unwrapDriver(otherDriver))
+	a.Equal(2, openCount)
+	a.NoError(reusedDriver.Close(ctx))
+	a.NoError(otherDriver.Close(ctx))
+
+	// The broken idle drivers are closed and a new one is opened.
+	for _, idle := range pool.entryMap["1/db"].idleList {
+		idle.driver.(*fakeResettableDriver).pingErr = errors.New("connection reset")
+	}
+	driver, err = pool.get(ctx, "1/db", open)
+	a.NoError(err)
+	a.Equal(3, openCount)
+	a.True(rawDriver.closed)
+	a.NoError(driver.Close(ctx))
+	a.Equal(1, pool.entryMap["1/db"].openCount)
+}
+
+func TestDriverPoolNotResettable(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newDriverPool()
+	rawDriver := &fakeDriver{}
+	driver, err := pool.get(ctx, "1/db", func(context.Context) (db.Driver, error) {
+		return rawDriver, nil
+	})
+	a.NoError(err)
+	a.NoError(driver.Close(ctx))
+	a.True(rawDriver.closed)
+	a.NotContains(pool.entryMap, "1/db")
+
+	_, err = pool.get(ctx, "1/db", func(context.Context) (db.Driver, error) {
+		return nil, errors.New("access denied")
+	})
+	a.Error(err)
+	a.NotContains(pool.entryMap, "1/db")
+}
+
+func TestDriverPoolMaxOpen(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newDriverPool()
+	pool.maxOpen = 1
+	pool.waitTimeout = 100 * time.Millisecond
+	open := func(context.Context) (db.Driver, error) {
+		return &fakeResettableDriver{}, nil
+	}
+
+	driver, err := pool.get(ctx, "1/db", open)
+	a.NoError(err)
+	_, err = pool.get(ctx, "1/db", open)
+	a.Error(err)
+	// The other databases aren't limited.
+	otherDriver, err := pool.get(ctx, "1/other", open)
+	a.NoError(err)
+	a.NoError(otherDriver.Close(ctx))
+
+	// The waiting caller gets the released driver.
+	pool.waitTimeout = 10 * time.Second
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = driver.Close(ctx)
+	}()
+	waitedDriver, err := pool.get(ctx, "1/db", open)
+	a.NoError(err)
+	a.Same(unwrapDriver(driver), unwrapDriver(waitedDriver))
+	a.NoError(waitedDriver.Close(ctx))
+}
+
+func TestDriverPoolCloseIdle(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newDriverPool()
+	rawDriver := &fakeResettableDriver{}
+	driver, err := pool.get(ctx, "1/db", func(context.Context) (db.Driver, error) {
+		return rawDriver, nil
+	})
+	a.NoError(err)
+	a.NoError(driver.Close(ctx))
+
+	pool.closeIdle(ctx, time.Now().Add(-time.Minute))
+	a.False(rawDriver.closed)
+	pool.closeIdle(ctx, time.Now())
+	a.True(rawDriver.closed)
+	a.NotContains(pool.entryMap, "1/db")
+}
+
+func TestDriverPoolCloseInstance(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newDriverPool()
+	rawDriverMap := map[string]*fakeResettableDriver{}
+	for _, key := range []string{"1/db", "11/db"} {
+		rawDriver := &fakeResettableDriver{}
+		rawDriverMap[key] = rawDriver
+		driver, err := pool.get(ctx, key, func(context.Context) (db.Driver, error) {
+			return rawDriver, nil
+		})
+		a.NoError(err)
+		a.NoError(driver.Close(ctx))
+	}
+
+	// Only the idle drivers of instance 1 are closed, not the ones of instance 11.
+	pool.closeInstance(ctx, 1)
+	a.True(rawDriverMap["1/db"].closed)
+	a.NotContains(pool.entryMap, "1/db")
+	a.False(rawDriverMap["11/db"].closed)
+}
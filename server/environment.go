@@ -23,6 +23,7 @@ func (s *Server) registerEnvironmentRoutes(g *echo.Group) {
 		}
 
 		envCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		envCreate.WorkspaceID = c.Get(getWorkspaceIDContextKey()).(int)
 
 		env, err := s.store.CreateEnvironment(ctx, envCreate)
 		if err != nil {
@@ -41,7 +42,10 @@ func (s *Server) registerEnvironmentRoutes(g *echo.Group) {
 
 	g.GET("/environment", func(c echo.Context) error {
 		ctx := c.Request().Context()
-		envFind := &api.EnvironmentFind{}
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		envFind := &api.EnvironmentFind{
+			WorkspaceID: &workspaceID,
+		}
 		if rowStatusStr := c.QueryParam("rowstatus"); rowStatusStr != "" {
 			rowStatus := api.RowStatus(rowStatusStr)
 			envFind.RowStatus = &rowStatus
@@ -105,6 +109,7 @@ func (s *Server) registerEnvironmentRoutes(g *echo.Group) {
 
 	g.PATCH("/environment/reorder", func(c echo.Context) error {
 		ctx := c.Request().Context()
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
 		patchList, err := jsonapi.UnmarshalManyPayload(c.Request().Body, reflect.TypeOf(new(api.EnvironmentPatch)))
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed environment reorder request").SetInternal(err)
@@ -115,6 +120,13 @@ func (s *Server) registerEnvironmentRoutes(g *echo.Group) {
 			if !ok {
 				return echo.NewHTTPError(http.StatusBadRequest, "Malformed environment reorder request").SetInternal(errors.New("failed to convert request item to *api.EnvironmentPatch"))
 			}
+			env, err := s.store.GetEnvironmentByID(ctx, envPatch.ID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find environment ID: %v", envPatch.ID)).SetInternal(err)
+			}
+			if env == nil || env.WorkspaceID != workspaceID {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Environment ID not found: %d", envPatch.ID))
+			}
 			envPatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
 			if _, err := s.store.PatchEnvironment(ctx, envPatch); err != nil {
 				if common.ErrorCode(err) == common.NotFound {
@@ -124,7 +136,7 @@ func (s *Server) registerEnvironmentRoutes(g *echo.Group) {
 			}
 		}
 
-		envList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{})
+		envList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{WorkspaceID: &workspaceID})
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch environment list for reorder").SetInternal(err)
 		}
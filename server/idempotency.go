@@ -0,0 +1,172 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// idempotencyKeyHeader is the request header carrying the idempotency key chosen by the client.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader is the response header set on the response replayed for a retried request.
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	// idempotencyKeyRetentionPeriod is how long the response of an idempotency key is kept.
+	idempotencyKeyRetentionPeriod = 24 * time.Hour
+	maxIdempotencyKeyLength       = 255
+)
+
+// idempotentRouteSet is the set of the create routes honoring the idempotency key.
+var idempotentRouteSet = map[string]bool{
+	"POST /api/issue":    true,
+	"POST /api/instance": true,
+	"POST /api/pipeline/:pipelineID/task/:taskID/check": true,
+}
+
+// idempotencyKeyStore is the storage of the idempotency keys used by the idempotency middleware.
+type idempotencyKeyStore interface {
+	CreateIdempotencyKey(ctx context.Context, create *api.IdempotencyKeyCreate) (*api.IdempotencyKey, error)
+	GetIdempotencyKey(ctx context.Context, find *api.IdempotencyKeyFind) (*api.IdempotencyKey, error)
+	PatchIdempotencyKey(ctx context.Context, patch *api.IdempotencyKeyPatch) error
+	DeleteIdempotencyKey(ctx context.Context, delete *api.IdempotencyKeyDelete) error
+}
+
+// idempotencyMiddleware makes the create requests with the idempotency key safe to retry.
+// The response of the first successful request is kept for 24 hours, and replayed for the requests with the same key
+// instead of creating the resource again. The failed requests are not kept, so that they can be retried with the same key.
+func idempotencyMiddleware(s *Server, next echo.HandlerFunc) echo.HandlerFunc {
+	return newIdempotencyHandler(s.store, next)
+}
+
+func newIdempotencyHandler(store idempotencyKeyStore, next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(idempotencyKeyHeader)
+		if key == "" || !idempotentRouteSet[fmt.Sprintf("%s %s", c.Request().Method, c.Path())] {
+			return next(c)
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s header must not be longer than %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength))
+		}
+		// The request is identified by the actual path rather than the route, e.g. the task check requests of different tasks
+		// have the same empty body.
+		request := fmt.Sprintf("%s %s", c.Request().Method, c.Request().URL.Path)
+
+		ctx := c.Request().Context()
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body").SetInternal(err)
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(sum[:])
+
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		var idempotencyKey *api.IdempotencyKey
+		// The creation is retried once if the existing key expires in between, before it's purged.
+		for i := 0; i < 2 && idempotencyKey == nil; i++ {
+			idempotencyKey, err = store.CreateIdempotencyKey(ctx, &api.IdempotencyKeyCreate{
+				CreatorID:   principalID,
+				Key:         key,
+				Request:     request,
+				RequestHash: requestHash,
+				ExpireTs:    time.Now().Add(idempotencyKeyRetentionPeriod).Unix(),
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create idempotency key").SetInternal(err)
+			}
+			if idempotencyKey != nil {
+				break
+			}
+			existing, err := store.GetIdempotencyKey(ctx, &api.IdempotencyKeyFind{CreatorID: &principalID, Key: &key})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch idempotency key").SetInternal(err)
+			}
+			if existing == nil {
+				// The key has just been deleted because its first request failed.
+				return echo.NewHTTPError(http.StatusConflict, "The request with the same idempotency key has just failed, please retry")
+			}
+			// The expired key that hasn't been purged yet is treated as absent, and replaced by the next creation.
+			if existing.ExpireTs >= time.Now().Unix() {
+				return replayIdempotentResponse(c, existing, request, requestHash)
+			}
+		}
+		if idempotencyKey == nil {
+			return echo.NewHTTPError(http.StatusConflict, "The request with the same idempotency key is still in progress")
+		}
+
+		recorder := &idempotencyResponseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+		err = next(c)
+
+		status := c.Response().Status
+		if err != nil || status < http.StatusOK || status >= http.StatusMultipleChoices {
+			if err := store.DeleteIdempotencyKey(ctx, &api.IdempotencyKeyDelete{ID: idempotencyKey.ID}); err != nil {
+				log.Error("Failed to delete the idempotency key of the failed request",
+					zap.String("request", request),
+					zap.String("key", key),
+					zap.Error(err))
+			}
+			return err
+		}
+		if err := store.PatchIdempotencyKey(ctx, &api.IdempotencyKeyPatch{
+			ID:          idempotencyKey.ID,
+			StatusCode:  status,
+			ContentType: c.Response().Header().Get(echo.HeaderContentType),
+			Response:    recorder.body.String(),
+		}); err != nil {
+			log.Error("Failed to save the response of the idempotency key",
+				zap.String("request", request),
+				zap.String("key", key),
+				zap.Error(err))
+		}
+		return nil
+	}
+}
+
+// replayIdempotentResponse answers the retried request with the response kept for the idempotency key.
+func replayIdempotentResponse(c echo.Context, idempotencyKey *api.IdempotencyKey, request string, requestHash string) error {
+	if idempotencyKey.Request != request || idempotencyKey.RequestHash != requestHash {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "The idempotency key has been used for a different request")
+	}
+	if idempotencyKey.StatusCode == 0 {
+		return echo.NewHTTPError(http.StatusConflict, "The request with the same idempotency key is still in progress")
+	}
+	if idempotencyKey.ContentType != "" {
+		c.Response().Header().Set(echo.HeaderContentType, idempotencyKey.ContentType)
+	}
+	c.Response().Header().Set(idempotentReplayedHeader, "true")
+	c.Response().WriteHeader(idempotencyKey.StatusCode)
+	if _, err := c.Response().Write([]byte(idempotencyKey.Response)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// idempotencyResponseRecorder keeps a copy of the response body written through it.
+type idempotencyResponseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *idempotencyResponseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// purgeExpiredIdempotencyKey deletes the expired idempotency keys.
+func (s *Server) purgeExpiredIdempotencyKey(ctx context.Context) {
+	if err := s.store.DeleteIdempotencyKeyExpiredBefore(ctx, time.Now().Unix()); err != nil {
+		log.Error("Failed to delete the expired idempotency keys.", zap.Error(err))
+	}
+}
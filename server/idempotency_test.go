@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestReplayIdempotentResponse(t *testing.T) {
+	idempotencyKey := &api.IdempotencyKey{
+		Request:     "POST /api/issue",
+		RequestHash: "hash",
+		StatusCode:  http.StatusOK,
+		ContentType: echo.MIMEApplicationJSON,
+		Response:    `{"data":{"id":"1"}}`,
+	}
+	tests := []struct {
+		name        string
+		request     string
+		requestHash string
+		statusCode  int
+		wantCode    int
+	}{
+		{name: "replayed", request: "POST /api/issue", requestHash: "hash", statusCode: http.StatusOK, wantCode: http.StatusOK},
+		{name: "different request", request: "POST /api/instance", requestHash: "hash", statusCode: http.StatusOK, wantCode: http.StatusUnprocessableEntity},
+		{name: "different body", request: "POST /api/issue", requestHash: "other", statusCode: http.StatusOK, wantCode: http.StatusUnprocessableEntity},
+		{name: "in progress", request: "POST /api/issue", requestHash: "hash", statusCode: 0, wantCode: http.StatusConflict},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		key := *idempotencyKey
+		key.StatusCode = test.statusCode
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+
+		err := replayIdempotentResponse(c, &key, test.request, test.requestHash)
+		if test.wantCode != http.StatusOK {
+			httpErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok, test.name)
+			assert.Equal(t, test.wantCode, httpErr.Code, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		assert.Equal(t, http.StatusOK, rec.Code, test.name)
+		assert.Equal(t, "true", rec.Header().Get(idempotentReplayedHeader), test.name)
+		assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType), test.name)
+		assert.Equal(t, idempotencyKey.Response, rec.Body.String(), test.name)
+	}
+}
+
+// fakeIdempotencyKeyStore is the in-memory idempotencyKeyStore, which replaces the expired key like the store does.
+type fakeIdempotencyKeyStore struct {
+	nextID  int
+	keyList []*api.IdempotencyKey
+}
+
+func (s *fakeIdempotencyKeyStore) find(creatorID int, key string) (int, *api.IdempotencyKey) {
+	for i, idempotencyKey := range s.keyList {
+		if idempotencyKey.CreatorID == creatorID && idempotencyKey.Key == key {
+			return i, idempotencyKey
+		}
+	}
+	return -1, nil
+}
+
+func (s *fakeIdempotencyKeyStore) CreateIdempotencyKey(_ context.Context, create *api.IdempotencyKeyCreate) (*api.IdempotencyKey, error) {
+	i, existing := s.find(create.CreatorID, create.Key)
+	if existing != nil {
+		if existing.ExpireTs >= time.Now().Unix() {
+			return nil, nil
+		}
+		s.keyList = append(s.keyList[:i], s.keyList[i+1:]...)
+	}
+	s.nextID++
+	idempotencyKey := &api.IdempotencyKey{
+		ID:          s.nextID,
+		CreatorID:   create.CreatorID,
+		Key:         create.Key,
+		Request:     create.Request,
+		RequestHash: create.RequestHash,
+		ExpireTs:    create.ExpireTs,
+	}
+	s.keyList = append(s.keyList, idempotencyKey)
+	return idempotencyKey, nil
+}
+
+func (s *fakeIdempotencyKeyStore) GetIdempotencyKey(_ context.Context, find *api.IdempotencyKeyFind) (*api.IdempotencyKey, error) {
+	_, idempotencyKey := s.find(*find.CreatorID, *find.Key)
+	return idempotencyKey, nil
+}
+
+func (s *fakeIdempotencyKeyStore) PatchIdempotencyKey(_ context.Context, patch *api.IdempotencyKeyPatch) error {
+	for _, idempotencyKey := range s.keyList {
+		if idempotencyKey.ID == patch.ID {
+			idempotencyKey.StatusCode = patch.StatusCode
+			idempotencyKey.ContentType = patch.ContentType
+			idempotencyKey.Response = patch.Response
+		}
+	}
+	return nil
+}
+
+func (s *fakeIdempotencyKeyStore) DeleteIdempotencyKey(_ context.Context, delete *api.IdempotencyKeyDelete) error {
+	for i, idempotencyKey := range s.keyList {
+		if idempotencyKey.ID == delete.ID {
+			s.keyList = append(s.keyList[:i], s.keyList[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	store := &fakeIdempotencyKeyStore{}
+	// The task IDs the check is scheduled for.
+	var scheduledTaskIDList []string
+	e := echo.New()
+	e.POST("/api/pipeline/:pipelineID/task/:taskID/check", func(c echo.Context) error {
+		if c.Param("taskID") == "99" {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to schedule the task check")
+		}
+		scheduledTaskIDList = append(scheduledTaskIDList, c.Param("taskID"))
+		return c.String(http.StatusOK, fmt.Sprintf("task %s, run %d", c.Param("taskID"), len(scheduledTaskIDList)))
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(getPrincipalIDContextKey(), 101)
+			return newIdempotencyHandler(store, next)(c)
+		}
+	})
+	post := func(path string, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(""))
+		req.Header.Set(idempotencyKeyHeader, key)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	a := require.New(t)
+	rec := post("/api/pipeline/1/task/2/check", "key-1")
+	a.Equal(http.StatusOK, rec.Code)
+	a.Equal("task 2, run 1", rec.Body.String())
+	a.Empty(rec.Header().Get(idempotentReplayedHeader))
+
+	// The retried request is answered with the kept response without scheduling the check again.
+	rec = post("/api/pipeline/1/task/2/check", "key-1")
+	a.Equal(http.StatusOK, rec.Code)
+	a.Equal("task 2, run 1", rec.Body.String())
+	a.Equal("true", rec.Header().Get(idempotentReplayedHeader))
+	a.Equal([]string{"2"}, scheduledTaskIDList)
+
+	// The same key for another task is a different request, even though the route and the empty body are the same.
+	rec = post("/api/pipeline/1/task/3/check", "key-1")
+	a.Equal(http.StatusUnprocessableEntity, rec.Code)
+	a.Equal([]string{"2"}, scheduledTaskIDList)
+
+	// The expired key that hasn't been purged yet is replaced.
+	store.keyList[0].ExpireTs = time.Now().Add(-time.Hour).Unix()
+	rec = post("/api/pipeline/1/task/3/check", "key-1")
+	a.Equal(http.StatusOK, rec.Code)
+	a.Equal("task 3, run 2", rec.Body.String())
+	a.Empty(rec.Header().Get(idempotentReplayedHeader))
+	a.Equal([]string{"2", "3"}, scheduledTaskIDList)
+
+	// The failed request isn't kept, so that it can be retried with the same key.
+	rec = post("/api/pipeline/1/task/99/check", "key-2")
+	a.Equal(http.StatusInternalServerError, rec.Code)
+	_, idempotencyKey := store.find(101, "key-2")
+	a.Nil(idempotencyKey)
+
+	// The requests without the key are not tracked.
+	rec = post("/api/pipeline/1/task/2/check", "")
+	a.Equal(http.StatusOK, rec.Code)
+	a.Equal([]string{"2", "3", "2"}, scheduledTaskIDList)
+	a.Len(store.keyList, 1)
+}
@@ -40,9 +40,38 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create instance request").SetInternal(err)
 		}
 		instanceCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		instanceCreate.WorkspaceID = c.Get(getWorkspaceIDContextKey()).(int)
+		env, err := s.store.GetEnvironmentByID(ctx, instanceCreate.EnvironmentID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find environment ID: %d", instanceCreate.EnvironmentID)).SetInternal(err)
+		}
+		if env == nil || env.WorkspaceID != instanceCreate.WorkspaceID {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Environment ID not found: %d", instanceCreate.EnvironmentID))
+		}
 		if err := s.disallowBytebaseStore(instanceCreate.Engine, instanceCreate.Host, instanceCreate.Port); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
+		if err := s.validateInstanceBackupStorageBackend(instanceCreate.BackupStorageBackend); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		tlsConfig := db.TLSConfig{
+			SslCA:   instanceCreate.SslCa,
+			SslCert: instanceCreate.SslCert,
+			SslKey:  instanceCreate.SslKey,
+		}
+		if err := tlsConfig.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid SSL config: %v", err)).SetInternal(err)
+		}
+		sshConfig := db.SSHConfig{
+			Host:       instanceCreate.SSHHost,
+			Port:       instanceCreate.SSHPort,
+			User:       instanceCreate.SSHUser,
+			PrivateKey: instanceCreate.SSHPrivateKey,
+			HostKey:    instanceCreate.SSHHostKey,
+		}
+		if err := validateInstanceSSHConfig(instanceCreate.Engine, sshConfig); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid SSH config: %v", err)).SetInternal(err)
+		}
 
 		instance, err := s.store.CreateInstance(ctx, instanceCreate)
 		if err != nil {
@@ -80,9 +109,83 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// Imports instances in bulk from a CSV or JSON manifest. Valid rows are imported even if some other rows fail.
+	g.POST("/instance/import", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		rowList, err := parseInstanceImportManifest(c.Request().Header.Get(echo.HeaderContentType), c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed instance import manifest").SetInternal(err)
+		}
+		if len(rowList) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Instance import manifest is empty")
+		}
+		if len(rowList) > maxInstanceImportRowCount {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance import manifest can have up to %d rows, got %d", maxInstanceImportRowCount, len(rowList)))
+		}
+		validateOnly := c.QueryParam("validateOnly") == "true"
+
+		response, err := s.importInstances(ctx, rowList, c.Get(getWorkspaceIDContextKey()).(int), c.Get(getPrincipalIDContextKey()).(int), validateOnly)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to import instances").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, response)
+	})
+
+	// Archives the stale instances in one transaction, or only lists them in the dry-run mode.
+	g.POST("/instance/archive", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		instanceArchive := &api.InstanceArchive{
+			UpdaterID:   c.Get(getPrincipalIDContextKey()).(int),
+			WorkspaceID: c.Get(getWorkspaceIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, instanceArchive); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed archive instance request").SetInternal(err)
+		}
+		if instanceArchive.StaleDays <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid stale days %d, it must be positive", instanceArchive.StaleDays))
+		}
+
+		rowStatus := api.Normal
+		instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{
+			RowStatus:     &rowStatus,
+			WorkspaceID:   &instanceArchive.WorkspaceID,
+			EnvironmentID: instanceArchive.EnvironmentID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch instance list").SetInternal(err)
+		}
+		databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{WorkspaceID: &instanceArchive.WorkspaceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch database list").SetInternal(err)
+		}
+		staleList := getStaleInstanceList(instanceList, databaseList, getStaleCutoffTs(instanceArchive.StaleDays))
+
+		if !instanceArchive.DryRun && len(staleList) > 0 {
+			var idList []int
+			for _, instance := range staleList {
+				idList = append(idList, instance.ID)
+			}
+			if staleList, err = s.store.ArchiveInstanceList(ctx, idList, instanceArchive.UpdaterID); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to archive instance list").SetInternal(err)
+			}
+			for _, instance := range staleList {
+				s.closeInstanceSSHTunnel(ctx, instance)
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, staleList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal archived instance list response").SetInternal(err)
+		}
+		return nil
+	})
+
 	g.GET("/instance", func(c echo.Context) error {
 		ctx := c.Request().Context()
-		instanceFind := &api.InstanceFind{}
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		instanceFind := &api.InstanceFind{
+			WorkspaceID: &workspaceID,
+		}
 		if rowStatusStr := c.QueryParam("rowstatus"); rowStatusStr != "" {
 			rowStatus := api.RowStatus(rowStatusStr)
 			instanceFind.RowStatus = &rowStatus
@@ -154,9 +257,41 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 		if err := s.disallowBytebaseStore(instance.Engine, host, port); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
+		if v := instancePatch.BackupStorageBackend; v != nil {
+			if err := s.validateInstanceBackupStorageBackend(api.BackupStorageBackend(*v)); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+		}
+		sshChanged := instancePatch.SSHHost != nil || instancePatch.SSHPort != nil || instancePatch.SSHUser != nil || instancePatch.SSHPrivateKey != nil || instancePatch.SSHHostKey != nil
+		if sshChanged {
+			// Clearing the SSH host disables the SSH tunnel.
+			if v := instancePatch.SSHHost; v != nil && *v == "" {
+				empty := ""
+				instancePatch.SSHPort, instancePatch.SSHUser, instancePatch.SSHPrivateKey, instancePatch.SSHHostKey = &empty, &empty, &empty, &empty
+			}
+			sshConfig := api.SSHConfigFromInstance(instance)
+			if v := instancePatch.SSHHost; v != nil {
+				sshConfig.Host = *v
+			}
+			if v := instancePatch.SSHPort; v != nil {
+				sshConfig.Port = *v
+			}
+			if v := instancePatch.SSHUser; v != nil {
+				sshConfig.User = *v
+			}
+			if v := instancePatch.SSHPrivateKey; v != nil {
+				sshConfig.PrivateKey = *v
+			}
+			if v := instancePatch.SSHHostKey; v != nil {
+				sshConfig.HostKey = *v
+			}
+			if err := validateInstanceSSHConfig(instance.Engine, sshConfig); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid SSH config: %v", err)).SetInternal(err)
+			}
+		}
 
 		var instancePatched *api.Instance
-		if instancePatch.RowStatus != nil || instancePatch.Name != nil || instancePatch.ExternalLink != nil || instancePatch.Host != nil || instancePatch.Port != nil {
+		if instancePatch.RowStatus != nil || instancePatch.Name != nil || instancePatch.ExternalLink != nil || instancePatch.Host != nil || instancePatch.Port != nil || instancePatch.BackupStorageBackend != nil || sshChanged {
 			// Users can switch instance status from ARCHIVED to NORMAL.
 			// So we need to check the current instance count with NORMAL status for quota limitation.
 			if instancePatch.RowStatus != nil && *instancePatch.RowStatus == string(api.Normal) {
@@ -189,10 +324,13 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 				}
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch instance ID: %v", id)).SetInternal(err)
 			}
+			if v := instancePatch.RowStatus; sshChanged || (v != nil && *v == string(api.Archived)) {
+				s.closeInstanceSSHTunnel(ctx, instance)
+			}
 		}
 
 		// Try immediately setup the migration schema, sync the engine version and schema after updating any connection related info.
-		if instancePatch.Host != nil || instancePatch.Port != nil {
+		if instancePatch.Host != nil || instancePatch.Port != nil || sshChanged {
 			db, err := s.getAdminDatabaseDriver(ctx, instancePatched, "" /* databaseName */)
 			if err == nil {
 				defer db.Close(ctx)
@@ -219,6 +357,49 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// Moves the instance to another environment, see moveInstanceEnvironment for the safety checks.
+	g.PATCH("/instance/:instanceID/environment", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		environmentPatch := &api.InstanceEnvironmentPatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, environmentPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch instance environment request").SetInternal(err)
+		}
+
+		instance, err := s.store.GetInstanceByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get instance ID: %v", id)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", id))
+		}
+		environment, err := s.store.GetEnvironmentByID(ctx, environmentPatch.EnvironmentID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get environment ID: %v", environmentPatch.EnvironmentID)).SetInternal(err)
+		}
+		if environment == nil || environment.WorkspaceID != instance.WorkspaceID {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Environment ID not found: %d", environmentPatch.EnvironmentID))
+		}
+
+		instancePatched, err := s.moveInstanceEnvironment(ctx, instance, environment, environmentPatch.UpdaterID)
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, instancePatched); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal instance ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.GET("/instance/:instanceID/user", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		id, err := strconv.Atoi(c.Param("instanceID"))
@@ -533,3 +714,25 @@ func (s *Server) disallowBytebaseStore(engine db.Type, host, port string) error
 	}
 	return nil
 }
+
+// closeInstanceSSHTunnel closes the SSH tunnel of the instance and the idle connections through it, after the SSH config
+// is changed or the instance is archived. The other instances sharing the same SSH config reconnect the SSH server
+// on their next database connection.
+func (s *Server) closeInstanceSSHTunnel(ctx context.Context, instance *api.Instance) {
+	if instance.SSHHost == "" {
+		return
+	}
+	s.driverPool.closeInstance(ctx, instance.ID)
+	db.CloseSSHTunnel(api.SSHConfigFromInstance(instance))
+}
+
+// validateInstanceSSHConfig validates the config of the SSH tunnel through which the instance of the engine is connected.
+func validateInstanceSSHConfig(engine db.Type, config db.SSHConfig) error {
+	if config.IsEmpty() {
+		return nil
+	}
+	if !db.IsSSHTunnelSupported(engine) {
+		return errors.Errorf("SSH tunnel is not supported for %s", engine)
+	}
+	return config.Validate()
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+const (
+	// agentTokenLength is the length of the generated agent token.
+	agentTokenLength = 48
+)
+
+func (s *Server) registerInstanceAgentRoutes(g *echo.Group) {
+	g.GET("/instance/:instanceID/agent", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		instanceAgent, err := s.store.GetInstanceAgent(ctx, &api.InstanceAgentFind{InstanceID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch agent for instance ID: %d", id)).SetInternal(err)
+		}
+		if instanceAgent == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Agent not found for instance ID: %d", id))
+		}
+		instanceAgent.Connected = s.AgentManager.isConnected(id)
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, instanceAgent); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal agent response for instance ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Puts the instance in agent mode and generates the agent token, or regenerates the token if already in agent mode.
+	// The token is only returned in this response.
+	g.POST("/instance/:instanceID/agent", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		instance, err := s.store.GetInstanceByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", id)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", id))
+		}
+
+		token, err := common.RandomString(agentTokenLength)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate agent token").SetInternal(err)
+		}
+		instanceAgent, err := s.store.UpsertInstanceAgent(ctx, &api.InstanceAgentUpsert{
+			UpdaterID:  c.Get(getPrincipalIDContextKey()).(int),
+			InstanceID: id,
+			TokenHash:  hashAgentToken(token),
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create agent for instance ID: %d", id)).SetInternal(err)
+		}
+		// The agent connected with the old token is disconnected.
+		s.AgentManager.disable(id)
+		s.AgentManager.enable(id)
+		instanceAgent.Token = token
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, instanceAgent); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal agent response for instance ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Puts the instance out of agent mode, so that Bytebase connects to the instance directly.
+	g.DELETE("/instance/:instanceID/agent", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		if err := s.store.DeleteInstanceAgent(ctx, &api.InstanceAgentDelete{InstanceID: id}); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Agent not found for instance ID: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete agent for instance ID: %d", id)).SetInternal(err)
+		}
+		s.AgentManager.disable(id)
+
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
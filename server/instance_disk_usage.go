@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	defaultInstanceDiskFullDays = 7
+	// instanceDiskUsageWindow is the period of the disk usage samples to project the growth rate, the older samples are purged.
+	instanceDiskUsageWindow = 7 * 24 * time.Hour
+	// minInstanceDiskUsageSpan is the minimum period covered by the samples to project, so that a burst doesn't raise the anomaly.
+	minInstanceDiskUsageSpan = time.Hour
+)
+
+func (s *Server) registerInstanceDiskUsageRoutes(g *echo.Group) {
+	g.GET("/instance/:instanceID/disk-usage", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		createdTsAfter := time.Now().Add(-instanceDiskUsageWindow).Unix()
+		diskUsageList, err := s.store.FindInstanceDiskUsage(ctx, &api.InstanceDiskUsageFind{
+			InstanceID:     &id,
+			CreatedTsAfter: &createdTsAfter,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch disk usage for instance ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, diskUsageList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal disk usage response for instance ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// checkInstanceDiskAnomaly takes a disk usage sample of the instance, and raises the anomaly if the disk is projected to fill up soon.
+// The free space is only known if the instance agent runs on the database host with the disk path.
+func (s *AnomalyScanner) checkInstanceDiskAnomaly(ctx context.Context, instance *api.Instance) {
+	dataBytes, err := s.server.store.GetInstanceDataSize(ctx, instance.ID)
+	if err != nil {
+		log.Error("Failed to get instance data size", zap.String("instance", instance.Name), zap.Error(err))
+		return
+	}
+	totalBytes, freeBytes, ok, err := s.server.AgentManager.getDiskUsage(ctx, instance.ID)
+	if err != nil {
+		log.Debug("Failed to get instance disk usage from the agent", zap.String("instance", instance.Name), zap.Error(err))
+	}
+	if _, err := s.server.store.CreateInstanceDiskUsage(ctx, &api.InstanceDiskUsageCreate{
+		InstanceID: instance.ID,
+		TotalBytes: totalBytes,
+		FreeBytes:  freeBytes,
+		DataBytes:  dataBytes,
+	}); err != nil {
+		log.Error("Failed to create instance disk usage", zap.String("instance", instance.Name), zap.Error(err))
+		return
+	}
+	// Keep the anomaly as is while the agent is unavailable, the connection anomaly covers that.
+	if !ok {
+		return
+	}
+
+	fullDays, err := s.server.getInstanceDiskFullDays(ctx)
+	if err != nil {
+		log.Error("Failed to get instance disk full days setting", zap.Error(err))
+		return
+	}
+	daysToFull, projected := 0.0, false
+	if fullDays > 0 {
+		createdTsAfter := time.Now().Add(-instanceDiskUsageWindow).Unix()
+		diskUsageList, err := s.server.store.FindInstanceDiskUsage(ctx, &api.InstanceDiskUsageFind{
+			InstanceID:     &instance.ID,
+			CreatedTsAfter: &createdTsAfter,
+		})
+		if err != nil {
+			log.Error("Failed to find instance disk usage", zap.String("instance", instance.Name), zap.Error(err))
+			return
+		}
+		daysToFull, projected = projectDiskFullDays(diskUsageList)
+	}
+
+	if !projected || daysToFull > float64(fullDays) {
+		err := s.server.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			InstanceID: &instance.ID,
+			Type:       api.AnomalyInstanceDiskFull,
+		})
+		if err != nil && common.ErrorCode(err) != common.NotFound {
+			log.Error("Failed to close anomaly",
+				zap.String("instance", instance.Name),
+				zap.String("type", string(api.AnomalyInstanceDiskFull)),
+				zap.Error(err))
+		}
+		return
+	}
+	payload, err := json.Marshal(api.AnomalyInstanceDiskFullPayload{
+		TotalBytes: totalBytes,
+		FreeBytes:  freeBytes,
+		DaysToFull: daysToFull,
+	})
+	if err != nil {
+		log.Error("Failed to marshal anomaly payload",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(api.AnomalyInstanceDiskFull)),
+			zap.Error(err))
+		return
+	}
+	if _, err := s.server.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		Type:       api.AnomalyInstanceDiskFull,
+		Payload:    string(payload),
+	}); err != nil {
+		log.Error("Failed to create anomaly",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(api.AnomalyInstanceDiskFull)),
+			zap.Error(err))
+	}
+}
+
+// projectDiskFullDays returns the days until the free space runs out, at the rate fitted by least squares over the samples.
+// Only the samples of the same disk size as the latest are used, so that resizing the disk starts over.
+// It returns false if there are too few samples or the free space isn't shrinking.
+func projectDiskFullDays(diskUsageList []*api.InstanceDiskUsage) (float64, bool) {
+	var sampleList []*api.InstanceDiskUsage
+	for i := len(diskUsageList) - 1; i >= 0; i-- {
+		diskUsage := diskUsageList[i]
+		if diskUsage.TotalBytes == 0 {
+			continue
+		}
+		if len(sampleList) > 0 && diskUsage.TotalBytes != sampleList[0].TotalBytes {
+			break
+		}
+		sampleList = append(sampleList, diskUsage)
+	}
+	if len(sampleList) < 2 {
+		return 0, false
+	}
+	latest, earliest := sampleList[0], sampleList[len(sampleList)-1]
+	if time.Duration(latest.CreatedTs-earliest.CreatedTs)*time.Second < minInstanceDiskUsageSpan {
+		return 0, false
+	}
+
+	// The timestamps are relative to the latest to keep the precision.
+	n := float64(len(sampleList))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range sampleList {
+		x := float64(sample.CreatedTs - latest.CreatedTs)
+		y := float64(sample.FreeBytes)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	// The free bytes changed per second.
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	if slope >= 0 {
+		return 0, false
+	}
+	return float64(latest.FreeBytes) / -slope / (24 * 60 * 60), true
+}
+
+// getInstanceDiskFullDays returns the instance disk full days setting, 0 means the anomaly is disabled.
+func (s *Server) getInstanceDiskFullDays(ctx context.Context) (int, error) {
+	settingName := api.SettingInstanceDiskFullDays
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return 0, err
+	}
+	if len(settingList) == 0 {
+		return defaultInstanceDiskFullDays, nil
+	}
+	days, err := strconv.Atoi(settingList[0].Value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid setting %s %q", settingName, settingList[0].Value)
+	}
+	return days, nil
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestProjectDiskFullDays(t *testing.T) {
+	const gb = int64(1) << 30
+	const day = int64(24 * 60 * 60)
+	tests := []struct {
+		name          string
+		diskUsageList []*api.InstanceDiskUsage
+		wantDays      float64
+		wantProjected bool
+	}{
+		{
+			name: "shrinking 1GB a day",
+			diskUsageList: []*api.InstanceDiskUsage{
+				{CreatedTs: 0, TotalBytes: 100 * gb, FreeBytes: 12 * gb},
+				{CreatedTs: day, TotalBytes: 100 * gb, FreeBytes: 11 * gb},
+				{CreatedTs: 2 * day, TotalBytes: 100 * gb, FreeBytes: 10 * gb},
+			},
+			wantDays:      10,
+			wantProjected: true,
+		},
+		{
+			name: "growing free space",
+			diskUsageList: []*api.InstanceDiskUsage{
+				{CreatedTs: 0, TotalBytes: 100 * gb, FreeBytes: 10 * gb},
+				{CreatedTs: day, TotalBytes: 100 * gb, FreeBytes: 11 * gb},
+			},
+			wantProjected: false,
+		},
+		{
+			name: "the samples without the agent are skipped",
+			diskUsageList: []*api.InstanceDiskUsage{
+				{CreatedTs: 0, TotalBytes: 100 * gb, FreeBytes: 4 * gb},
+				{CreatedTs: day},
+				{CreatedTs: 2 * day, TotalBytes: 100 * gb, FreeBytes: 2 * gb},
+			},
+			wantDays:      2,
+			wantProjected: true,
+		},
+		{
+			name: "resizing the disk starts over",
+			diskUsageList: []*api.InstanceDiskUsage{
+				{CreatedTs: 0, TotalBytes: 100 * gb, FreeBytes: 10 * gb},
+				{CreatedTs: day, TotalBytes: 100 * gb, FreeBytes: 1 * gb},
+				{CreatedTs: 2 * day, TotalBytes: 200 * gb, FreeBytes: 101 * gb},
+			},
+			wantProjected: false,
+		},
+		{
+			name: "too short a span",
+			diskUsageList: []*api.InstanceDiskUsage{
+				{CreatedTs: 0, TotalBytes: 100 * gb, FreeBytes: 10 * gb},
+				{CreatedTs: 600, TotalBytes: 100 * gb, FreeBytes: 9 * gb},
+			},
+			wantProjected: false,
+		},
+		{
+			name:          "no sample",
+			diskUsageList: nil,
+			wantProjected: false,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		days, projected := projectDiskFullDays(test.diskUsageList)
+		a.Equal(test.wantProjected, projected, test.name)
+		if test.wantProjected {
+			a.InDelta(test.wantDays, days, 0.001, test.name)
+		}
+	}
+}
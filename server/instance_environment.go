@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// moveInstanceEnvironment moves the instance to another environment in the same workspace.
+// The instance cannot be moved while any pipeline is in flight against it, since the tasks have been
+// approved and scheduled under the policies of the old environment. After the move, the backup settings
+// of the databases are updated to comply with the backup plan policy of the new environment, and the
+// approval policy of the new environment applies to the tasks created afterwards.
+func (s *Server) moveInstanceEnvironment(ctx context.Context, instance *api.Instance, environment *api.Environment, updaterID int) (*api.Instance, error) {
+	if instance.RowStatus == api.Archived {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance %q is archived", instance.Name))
+	}
+	if environment.RowStatus == api.Archived {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Environment %q is archived", environment.Name))
+	}
+	if instance.EnvironmentID == environment.ID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance %q is already in environment %q", instance.Name, environment.Name))
+	}
+
+	issueNameList, err := s.findInFlightIssueNameList(ctx, instance.ID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find in-flight pipelines for instance ID: %v", instance.ID)).SetInternal(err)
+	}
+	if len(issueNameList) > 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("You should wait for or cancel these issues before moving the instance: %s.", strings.Join(issueNameList, ", ")))
+	}
+
+	approvalPolicy, err := s.store.GetPipelineApprovalPolicy(ctx, environment.ID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get approval policy for environment ID: %v", environment.ID)).SetInternal(err)
+	}
+	backupPlanPolicy, err := s.store.GetBackupPlanPolicyByEnvID(ctx, environment.ID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get backup plan policy for environment ID: %v", environment.ID)).SetInternal(err)
+	}
+
+	instancePatched, err := s.store.PatchInstance(ctx, &api.InstancePatch{
+		ID:            instance.ID,
+		UpdaterID:     updaterID,
+		EnvironmentID: &environment.ID,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch instance ID: %v", instance.ID)).SetInternal(err)
+	}
+
+	// Updates the backup settings after the move, so that the backup setting validation runs against the new environment.
+	var updatedDatabaseNameList []string
+	databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{InstanceID: &instance.ID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find databases in the instance %d", instance.ID)).SetInternal(err)
+	}
+	for _, database := range databaseList {
+		backupSetting, err := s.store.GetBackupSettingByDatabaseID(ctx, database.ID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get backup setting for database ID: %v", database.ID)).SetInternal(err)
+		}
+		backupSettingUpsert := getCompliantBackupSettingUpsert(backupSetting, backupPlanPolicy.Schedule)
+		if backupSettingUpsert == nil {
+			continue
+		}
+		backupSettingUpsert.UpdaterID = updaterID
+		backupSettingUpsert.DatabaseID = database.ID
+		backupSettingUpsert.EnvironmentID = environment.ID
+		if _, err := s.store.UpsertBackupSetting(ctx, backupSettingUpsert); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update backup setting for database ID: %v", database.ID)).SetInternal(err)
+		}
+		updatedDatabaseNameList = append(updatedDatabaseNameList, database.Name)
+	}
+
+	bytes, err := json.Marshal(api.ActivityInstanceEnvironmentUpdatePayload{
+		OldEnvironmentID:                 instance.EnvironmentID,
+		NewEnvironmentID:                 environment.ID,
+		InstanceName:                     instance.Name,
+		OldEnvironmentName:               instance.Environment.Name,
+		NewEnvironmentName:               environment.Name,
+		ApprovalPolicy:                   approvalPolicy.Value,
+		BackupPlanSchedule:               backupPlanPolicy.Schedule,
+		BackupSettingUpdatedDatabaseList: updatedDatabaseNameList,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   updaterID,
+		ContainerID: instance.ID,
+		Type:        api.ActivityInstanceEnvironmentUpdate,
+		Level:       api.ActivityInfo,
+		Comment:     fmt.Sprintf("Moved instance %q from environment %q to %q.", instance.Name, instance.Environment.Name, environment.Name),
+		Payload:     string(bytes),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after moving instance ID: %v", instance.ID)).SetInternal(err)
+	}
+
+	return instancePatched, nil
+}
+
+// findInFlightIssueNameList returns the names of the open issues having unfinished tasks against the instance.
+func (s *Server) findInFlightIssueNameList(ctx context.Context, instanceID int) ([]string, error) {
+	statusList := []api.TaskStatus{api.TaskPending, api.TaskPendingApproval, api.TaskRunning, api.TaskFailed}
+	taskList, err := s.store.FindTask(ctx, &api.TaskFind{InstanceID: &instanceID, StatusList: &statusList}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var issueNameList []string
+	visited := make(map[int]bool)
+	for _, task := range taskList {
+		if visited[task.PipelineID] {
+			continue
+		}
+		visited[task.PipelineID] = true
+		issue, err := s.store.GetIssueByPipelineID(ctx, task.PipelineID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get issue by pipeline ID %d", task.PipelineID)
+		}
+		// The tasks of the done or canceled issues won't be run anymore.
+		if issue == nil || issue.Status != api.IssueOpen {
+			continue
+		}
+		issueNameList = append(issueNameList, issue.Name)
+	}
+	return issueNameList, nil
+}
+
+// getCompliantBackupSettingUpsert returns the backup setting upsert complying with the backup plan policy schedule,
+// or nil if the backup setting already complies. The existing hour, retention period and hook URL are kept.
+func getCompliantBackupSettingUpsert(backupSetting *api.BackupSetting, schedule api.BackupPlanPolicySchedule) *api.BackupSettingUpsert {
+	if schedule == api.BackupPlanPolicyScheduleUnset {
+		return nil
+	}
+	upsert := &api.BackupSettingUpsert{
+		Enabled:           true,
+		Hour:              rand.Intn(24),
+		RetentionPeriodTs: 7 * 24 * 3600,
+	}
+	if backupSetting != nil {
+		upsert.Hour = backupSetting.Hour
+		upsert.DayOfWeek = backupSetting.DayOfWeek
+		upsert.RetentionPeriodTs = backupSetting.RetentionPeriodTs
+		upsert.HookURL = backupSetting.HookURL
+	}
+
+	compliant := backupSetting != nil && backupSetting.Enabled
+	switch schedule {
+	case api.BackupPlanPolicyScheduleDaily:
+		if backupSetting == nil || backupSetting.DayOfWeek != -1 {
+			compliant = false
+		}
+		upsert.DayOfWeek = -1
+	case api.BackupPlanPolicyScheduleWeekly:
+		if backupSetting == nil || backupSetting.DayOfWeek == -1 {
+			compliant = false
+			upsert.DayOfWeek = rand.Intn(7)
+		}
+	}
+	if compliant {
+		return nil
+	}
+	return upsert
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetCompliantBackupSettingUpsert(t *testing.T) {
+	daily := &api.BackupSetting{Enabled: true, Hour: 3, DayOfWeek: -1, RetentionPeriodTs: 3600, HookURL: "http://hook"}
+	weekly := &api.BackupSetting{Enabled: true, Hour: 4, DayOfWeek: 2, RetentionPeriodTs: 7200}
+	disabled := &api.BackupSetting{Enabled: false, Hour: 5, DayOfWeek: 1}
+
+	// No policy, nothing to update.
+	assert.Nil(t, getCompliantBackupSettingUpsert(nil, api.BackupPlanPolicyScheduleUnset))
+	assert.Nil(t, getCompliantBackupSettingUpsert(disabled, api.BackupPlanPolicyScheduleUnset))
+
+	// Already compliant.
+	assert.Nil(t, getCompliantBackupSettingUpsert(daily, api.BackupPlanPolicyScheduleDaily))
+	assert.Nil(t, getCompliantBackupSettingUpsert(weekly, api.BackupPlanPolicyScheduleWeekly))
+
+	// Weekly backup doesn't satisfy the daily policy, the other fields are kept.
+	upsert := getCompliantBackupSettingUpsert(weekly, api.BackupPlanPolicyScheduleDaily)
+	require.NotNil(t, upsert)
+	assert.Equal(t, &api.BackupSettingUpsert{Enabled: true, Hour: 4, DayOfWeek: -1, RetentionPeriodTs: 7200}, upsert)
+
+	// Daily backup is changed to a day of week for the weekly policy.
+	upsert = getCompliantBackupSettingUpsert(daily, api.BackupPlanPolicyScheduleWeekly)
+	require.NotNil(t, upsert)
+	assert.True(t, upsert.Enabled)
+	assert.Equal(t, 3, upsert.Hour)
+	assert.GreaterOrEqual(t, upsert.DayOfWeek, 0)
+	assert.Less(t, upsert.DayOfWeek, 7)
+	assert.Equal(t, "http://hook", upsert.HookURL)
+
+	// Disabled backup is enabled and keeps its schedule if it complies.
+	upsert = getCompliantBackupSettingUpsert(disabled, api.BackupPlanPolicyScheduleWeekly)
+	require.NotNil(t, upsert)
+	assert.Equal(t, &api.BackupSettingUpsert{Enabled: true, Hour: 5, DayOfWeek: 1}, upsert)
+
+	// Missing backup setting is created.
+	upsert = getCompliantBackupSettingUpsert(nil, api.BackupPlanPolicyScheduleDaily)
+	require.NotNil(t, upsert)
+	assert.True(t, upsert.Enabled)
+	assert.Equal(t, -1, upsert.DayOfWeek)
+	assert.Equal(t, 7*24*3600, upsert.RetentionPeriodTs)
+}
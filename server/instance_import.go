@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// maxInstanceImportRowCount is the maximum number of rows in a single instance import manifest.
+	maxInstanceImportRowCount = 1000
+)
+
+// parseInstanceImportManifest parses the instance import manifest.
+// The manifest is parsed as CSV if the content type is text/csv, otherwise it's parsed as a JSON array.
+func parseInstanceImportManifest(contentType string, r io.Reader) ([]*api.InstanceImportRow, error) {
+	if !strings.HasPrefix(contentType, "text/csv") {
+		var rowList []*api.InstanceImportRow
+		if err := json.NewDecoder(r).Decode(&rowList); err != nil {
+			return nil, errors.Wrap(err, "failed to decode JSON manifest")
+		}
+		return rowList, nil
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	recordList, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read CSV manifest")
+	}
+	if len(recordList) == 0 {
+		return nil, errors.Errorf("CSV manifest has no header line")
+	}
+	header := recordList[0]
+	for _, column := range header {
+		if _, ok := getInstanceImportField(&api.InstanceImportRow{}, column); !ok {
+			return nil, errors.Errorf("unknown column %q in CSV manifest", column)
+		}
+	}
+	var rowList []*api.InstanceImportRow
+	for _, record := range recordList[1:] {
+		row := &api.InstanceImportRow{}
+		for i, value := range record {
+			field, _ := getInstanceImportField(row, header[i])
+			*field = strings.TrimSpace(value)
+		}
+		rowList = append(rowList, row)
+	}
+	return rowList, nil
+}
+
+// getInstanceImportField returns the field of the row by the CSV column name.
+func getInstanceImportField(row *api.InstanceImportRow, column string) (*string, bool) {
+	switch strings.TrimSpace(column) {
+	case "name":
+		return &row.Name, true
+	case "engine":
+		return (*string)(&row.Engine), true
+	case "host":
+		return &row.Host, true
+	case "port":
+		return &row.Port, true
+	case "externalLink":
+		return &row.ExternalLink, true
+	case "environment":
+		return &row.Environment, true
+	case "username":
+		return &row.Username, true
+	case "password":
+		return &row.Password, true
+	case "credentialInstance":
+		return &row.CredentialInstance, true
+	}
+	return nil, false
+}
+
+// importInstances validates each row of the manifest and creates the instances for the valid rows.
+// A row failure doesn't stop importing the other rows. If validateOnly is true, no instance is created.
+func (s *Server) importInstances(ctx context.Context, rowList []*api.InstanceImportRow, workspaceID int, creatorID int, validateOnly bool) (*api.InstanceImportResponse, error) {
+	status := api.Normal
+	instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{RowStatus: &status, WorkspaceID: &workspaceID})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find instance list")
+	}
+	instanceByName := make(map[string]*api.Instance)
+	for _, instance := range instanceList {
+		instanceByName[instance.Name] = instance
+	}
+	environmentList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{RowStatus: &status, WorkspaceID: &workspaceID})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find environment list")
+	}
+	environmentByName := make(map[string]*api.Environment)
+	for _, environment := range environmentList {
+		environmentByName[environment.Name] = environment
+	}
+	// The instance count limit applies to the whole deployment.
+	instanceCount, err := s.store.CountInstance(ctx, &api.InstanceFind{RowStatus: &status})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count instance")
+	}
+	remainingCount := s.loadSubscription().InstanceCount - instanceCount
+
+	response := &api.InstanceImportResponse{}
+	importedNames := make(map[string]bool)
+	for i, row := range rowList {
+		result := &api.InstanceImportResult{
+			Row:  i + 1,
+			Name: row.Name,
+		}
+		response.ResultList = append(response.ResultList, result)
+
+		instanceCreate, err := s.validateInstanceImportRow(row, instanceByName, environmentByName)
+		if err == nil && importedNames[row.Name] {
+			err = errors.Errorf("duplicate instance name %q in the manifest", row.Name)
+		}
+		if err == nil && remainingCount <= 0 {
+			err = errors.Errorf("reached the maximum instance count %d", s.loadSubscription().InstanceCount)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			response.FailureCount++
+			continue
+		}
+		importedNames[row.Name] = true
+		remainingCount--
+		if validateOnly {
+			response.SuccessCount++
+			continue
+		}
+
+		instanceCreate.CreatorID = creatorID
+		instanceCreate.WorkspaceID = workspaceID
+		instance, err := s.store.CreateInstance(ctx, instanceCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				result.Error = fmt.Sprintf("instance name already exists: %s", row.Name)
+			} else {
+				result.Error = fmt.Sprintf("failed to create instance: %v", err)
+			}
+			response.FailureCount++
+			continue
+		}
+		result.InstanceID = instance.ID
+		response.SuccessCount++
+	}
+	return response, nil
+}
+
+// validateInstanceImportRow validates the row and converts it to the instance create.
+func (s *Server) validateInstanceImportRow(row *api.InstanceImportRow, instanceByName map[string]*api.Instance, environmentByName map[string]*api.Environment) (*api.InstanceCreate, error) {
+	if row.Name == "" {
+		return nil, errors.Errorf("name is required")
+	}
+	if _, ok := instanceByName[row.Name]; ok {
+		return nil, errors.Errorf("instance name already exists: %s", row.Name)
+	}
+	switch row.Engine {
+	case db.ClickHouse, db.MySQL, db.Postgres, db.Snowflake, db.TiDB:
+	default:
+		return nil, errors.Errorf("unsupported engine %q", row.Engine)
+	}
+	if row.Host == "" {
+		return nil, errors.Errorf("host is required")
+	}
+	if err := s.disallowBytebaseStore(row.Engine, row.Host, row.Port); err != nil {
+		return nil, err
+	}
+	environment, ok := environmentByName[row.Environment]
+	if !ok {
+		return nil, errors.Errorf("environment %q not found", row.Environment)
+	}
+
+	instanceCreate := &api.InstanceCreate{
+		EnvironmentID: environment.ID,
+		Name:          row.Name,
+		Engine:        row.Engine,
+		ExternalLink:  row.ExternalLink,
+		Host:          row.Host,
+		Port:          row.Port,
+		Username:      row.Username,
+		Password:      row.Password,
+	}
+	if row.CredentialInstance != "" {
+		if row.Username != "" || row.Password != "" {
+			return nil, errors.Errorf("credentialInstance cannot be specified together with username and password")
+		}
+		credentialInstance, ok := instanceByName[row.CredentialInstance]
+		if !ok {
+			return nil, errors.Errorf("credential instance %q not found", row.CredentialInstance)
+		}
+		if credentialInstance.Engine != row.Engine {
+			return nil, errors.Errorf("credential instance %q has engine %q, expect %q", row.CredentialInstance, credentialInstance.Engine, row.Engine)
+		}
+		adminDataSource := api.DataSourceFromInstanceWithType(credentialInstance, api.Admin)
+		if adminDataSource == nil {
+			return nil, errors.Errorf("admin data source not found for credential instance %q", row.CredentialInstance)
+		}
+		instanceCreate.Username = adminDataSource.Username
+		instanceCreate.Password = adminDataSource.Password
+	}
+	return instanceCreate, nil
+}
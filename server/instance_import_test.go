@@ -0,0 +1,83 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestParseInstanceImportManifest(t *testing.T) {
+	tests := []struct {
+		contentType string
+		manifest    string
+		want        []*api.InstanceImportRow
+		wantErr     bool
+	}{
+		{
+			contentType: "application/json",
+			manifest:    `[{"name":"prod-1","engine":"MYSQL","host":"10.0.0.1","port":"3306","environment":"Prod","credentialInstance":"prod-0"}]`,
+			want: []*api.InstanceImportRow{
+				{
+					Name:               "prod-1",
+					Engine:             db.MySQL,
+					Host:               "10.0.0.1",
+					Port:               "3306",
+					Environment:        "Prod",
+					CredentialInstance: "prod-0",
+				},
+			},
+		},
+		{
+			contentType: "text/csv; charset=utf-8",
+			manifest:    "name,engine,host,port,environment,username,password\nprod-1, MYSQL,10.0.0.1,3306,Prod,root,pwd\nprod-2,POSTGRES,10.0.0.2,5432,Prod,postgres,\n",
+			want: []*api.InstanceImportRow{
+				{
+					Name:        "prod-1",
+					Engine:      db.MySQL,
+					Host:        "10.0.0.1",
+					Port:        "3306",
+					Environment: "Prod",
+					Username:    "root",
+					Password:    "pwd",
+				},
+				{
+					Name:        "prod-2",
+					Engine:      db.Postgres,
+					Host:        "10.0.0.2",
+					Port:        "5432",
+					Environment: "Prod",
+					Username:    "postgres",
+				},
+			},
+		},
+		{
+			contentType: "text/csv",
+			manifest:    "name,engine,unknown\nprod-1,MYSQL,foo\n",
+			wantErr:     true,
+		},
+		{
+			contentType: "text/csv",
+			manifest:    "name,engine\nprod-1,MYSQL,foo\n",
+			wantErr:     true,
+		},
+		{
+			contentType: "application/json",
+			manifest:    `{"name":"prod-1"}`,
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		rowList, err := parseInstanceImportManifest(test.contentType, strings.NewReader(test.manifest))
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.want, rowList)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -21,6 +22,13 @@ import (
 	"github.com/bytebase/bytebase/plugin/vcs"
 )
 
+const (
+	// issueLabelMaxLength is the maximum length in characters of an issue label.
+	issueLabelMaxLength = 64
+	// issueLabelMaxCount is the maximum number of the labels of an issue.
+	issueLabelMaxCount = 20
+)
+
 func (s *Server) registerIssueRoutes(g *echo.Group) {
 	g.POST("/issue", func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -28,6 +36,7 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, issueCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create issue request").SetInternal(err)
 		}
+		issueCreate.WorkspaceID = c.Get(getWorkspaceIDContextKey()).(int)
 
 		issue, err := s.createIssue(ctx, issueCreate, c.Get(getPrincipalIDContextKey()).(int))
 		if err != nil {
@@ -43,7 +52,27 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 
 	g.GET("/issue", func(c echo.Context) error {
 		ctx := c.Request().Context()
-		issueFind := &api.IssueFind{}
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		issueFind := &api.IssueFind{
+			WorkspaceID: &workspaceID,
+		}
+		// The filter of the view is applied first, so that the other query parameters take precedence.
+		if viewIDStr := c.QueryParam("view"); viewIDStr != "" {
+			viewID, err := strconv.Atoi(viewIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("view query parameter is not a number: %s", viewIDStr)).SetInternal(err)
+			}
+			view, err := s.store.GetIssueViewByID(ctx, viewID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue view ID: %v", viewID)).SetInternal(err)
+			}
+			if view == nil || !canReadIssueView(view, c.Get(getPrincipalIDContextKey()).(int), workspaceID) {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue view ID not found: %d", viewID))
+			}
+			if err := applyIssueViewFilter(issueFind, view); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to apply issue view ID: %v", viewID)).SetInternal(err)
+			}
+		}
 		projectIDStr := c.QueryParams().Get("project")
 		if projectIDStr != "" {
 			projectID, err := strconv.Atoi(projectIDStr)
@@ -74,6 +103,35 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 			}
 			issueFind.PrincipalID = &userID
 		}
+		if assigneeIDStr := c.QueryParam("assignee"); assigneeIDStr != "" {
+			assigneeID, err := strconv.Atoi(assigneeIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("assignee query parameter is not a number: %s", assigneeIDStr)).SetInternal(err)
+			}
+			issueFind.AssigneeID = &assigneeID
+		}
+		if environmentIDStr := c.QueryParam("environment"); environmentIDStr != "" {
+			environmentID, err := strconv.Atoi(environmentIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("environment query parameter is not a number: %s", environmentIDStr)).SetInternal(err)
+			}
+			issueFind.EnvironmentID = &environmentID
+		}
+		if label := c.QueryParam("label"); label != "" {
+			issueFind.Label = &label
+		}
+		if referenceType := c.QueryParam("referenceType"); referenceType != "" {
+			reference, err := getIssueReferenceFind(referenceType, c.QueryParam("referenceId"))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			issueFind.Reference = reference
+		}
+		paged, cursor, err := getPageToken(c)
+		if err != nil {
+			return err
+		}
+		issueFind.After = cursor
 
 		issueList, err := s.store.FindIssueStripped(ctx, issueFind)
 		if err != nil {
@@ -85,6 +143,18 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if paged {
+			issueResponse := &api.IssueResponse{Issues: issueList}
+			// A full page may be followed by more issues.
+			if issueFind.Limit != nil && len(issueList) > 0 && len(issueList) == *issueFind.Limit {
+				last := issueList[len(issueList)-1]
+				issueResponse.NextToken = (&api.PageCursor{Ts: last.UpdatedTs, ID: last.ID}).Encode()
+			}
+			if err := jsonapi.MarshalPayload(c.Response().Writer, issueResponse); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue list response").SetInternal(err)
+			}
+			return nil
+		}
 		if err := jsonapi.MarshalPayload(c.Response().Writer, issueList); err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue list response").SetInternal(err)
 		}
@@ -138,6 +208,15 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Unable to find issue ID to update: %d", id))
 		}
 
+		if v := issuePatch.LabelList; v != nil {
+			labelList, err := normalizeIssueLabelList(splitCommaSeparatedList(*v))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid issue labels: %v", err)).SetInternal(err)
+			}
+			labelListStr := strings.Join(labelList, ",")
+			issuePatch.LabelList = &labelListStr
+		}
+
 		if issuePatch.AssigneeID != nil {
 			stage := getActiveStage(issue.Pipeline.StageList)
 			if stage == nil {
@@ -157,6 +236,11 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update issue with ID %d", id)).SetInternal(err)
 		}
+		if issuePatch.Description != nil && *issuePatch.Description != issue.Description {
+			if err := s.setIssueReferenceList(ctx, updatedIssue, issuePatch.UpdaterID); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to save the references in the description of issue %d", id)).SetInternal(err)
+			}
+		}
 
 		payloadList := [][]byte{}
 		if issuePatch.Name != nil && *issuePatch.Name != issue.Name {
@@ -195,6 +279,18 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 			}
 			payloadList = append(payloadList, payload)
 		}
+		if issuePatch.LabelList != nil && *issuePatch.LabelList != strings.Join(issue.LabelList, ",") {
+			payload, err := json.Marshal(api.ActivityIssueFieldUpdatePayload{
+				FieldID:   api.IssueFieldLabelList,
+				OldValue:  strings.Join(issue.LabelList, ","),
+				NewValue:  *issuePatch.LabelList,
+				IssueName: issue.Name,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal activity after changing issue labels: %v", updatedIssue.Name)).SetInternal(err)
+			}
+			payloadList = append(payloadList, payload)
+		}
 
 		for _, payload := range payloadList {
 			activityCreate := &api.ActivityCreate{
@@ -258,6 +354,47 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	g.POST("/issue/:issueID/clone", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+
+		issueClone := &api.IssueClone{
+			ID:        id,
+			CreatorID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, issueClone); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed clone issue request").SetInternal(err)
+		}
+
+		issue, err := s.store.GetIssueByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %v", id)).SetInternal(err)
+		}
+		if issue == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", id))
+		}
+
+		issueCreate, err := s.getIssueCreateForClone(ctx, issue, issueClone)
+		if err != nil {
+			return err
+		}
+		issueCreate.WorkspaceID = c.Get(getWorkspaceIDContextKey()).(int)
+
+		clonedIssue, err := s.createIssue(ctx, issueCreate, issueClone.CreatorID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to clone issue ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, clonedIssue); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal clone issue response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
 }
 
 func (s *Server) createIssue(ctx context.Context, issueCreate *api.IssueCreate, creatorID int) (*api.Issue, error) {
@@ -269,6 +406,18 @@ func (s *Server) createIssue(ctx context.Context, issueCreate *api.IssueCreate,
 	if err != nil {
 		return nil, err
 	}
+	// The IDs in the request body aren't checked by the ACL middleware.
+	if issueCreate.WorkspaceID != 0 {
+		if err := s.checkRequestWorkspaceResource(ctx, issueCreate.WorkspaceID, getPipelineWorkspaceResourceList(issueCreate.ProjectID, pipelineCreate)); err != nil {
+			return nil, err
+		}
+	}
+
+	labelList, err := normalizeIssueLabelList(issueCreate.LabelList)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid issue labels: %v", err)).SetInternal(err)
+	}
+	issueCreate.LabelList = labelList
 
 	if issueCreate.AssigneeID == api.UnknownID {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, assignee missing")
@@ -299,6 +448,11 @@ func (s *Server) createIssue(ctx context.Context, issueCreate *api.IssueCreate,
 		if err != nil {
 			return nil, err
 		}
+		referenceList, err := s.resolveIssueReferenceList(ctx, issue, creatorID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to resolve the references in the issue description").SetInternal(err)
+		}
+		issue.ReferenceList = referenceList
 		return issue, nil
 	}
 
@@ -319,6 +473,9 @@ func (s *Server) createIssue(ctx context.Context, issueCreate *api.IssueCreate,
 			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to add subscriber %d after creating issue %d", subscriberID, issue.ID)).SetInternal(err)
 		}
 	}
+	if err := s.setIssueReferenceList(ctx, issue, creatorID); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to save the references in the description after creating issue %d", issue.ID)).SetInternal(err)
+	}
 
 	if err := s.ScheduleActiveStage(ctx, issue.Pipeline); err != nil {
 		return nil, errors.Wrapf(err, "failed to schedule task after creating the issue: %v", issue.Name)
@@ -348,6 +505,140 @@ func (s *Server) createIssue(ctx context.Context, issueCreate *api.IssueCreate,
 	return issue, nil
 }
 
+// getIssueCreateForClone returns the issue create copying the statements, target databases and subscribers of the issue.
+// If the environment is specified in the issue clone, each database is replaced by the database with the same name
+// in the same project from that environment.
+func (s *Server) getIssueCreateForClone(ctx context.Context, issue *api.Issue, issueClone *api.IssueClone) (*api.IssueCreate, error) {
+	if issue.Project.TenantMode == api.TenantModeTenant {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Cloning issues in tenant mode project is not supported yet")
+	}
+
+	var environment *api.Environment
+	if issueClone.EnvironmentID != nil {
+		env, err := s.store.GetEnvironmentByID(ctx, *issueClone.EnvironmentID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch environment ID: %v", *issueClone.EnvironmentID)).SetInternal(err)
+		}
+		if env == nil {
+			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Environment ID not found: %d", *issueClone.EnvironmentID))
+		}
+		environment = env
+	}
+	getTargetDatabaseID := func(task *api.Task) (int, error) {
+		if task.Database == nil {
+			return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %q has no database", task.Name))
+		}
+		if environment == nil {
+			return task.Database.ID, nil
+		}
+		databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{
+			ProjectID: &issue.ProjectID,
+			Name:      &task.Database.Name,
+		})
+		if err != nil {
+			return 0, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database %q in project ID: %v", task.Database.Name, issue.ProjectID)).SetInternal(err)
+		}
+		var matchedList []*api.Database
+		for _, database := range databaseList {
+			if database.Instance.EnvironmentID == environment.ID {
+				matchedList = append(matchedList, database)
+			}
+		}
+		if len(matchedList) == 0 {
+			return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q not found in environment %q", task.Database.Name, environment.Name))
+		}
+		if len(matchedList) > 1 {
+			return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Found %d databases named %q in environment %q", len(matchedList), task.Database.Name, environment.Name))
+		}
+		return matchedList[0].ID, nil
+	}
+
+	var createContext interface{}
+	switch issue.Type {
+	case api.IssueDatabaseSchemaUpdate, api.IssueDatabaseDataUpdate:
+		c := api.UpdateSchemaContext{}
+		for _, stage := range issue.Pipeline.StageList {
+			for _, task := range stage.TaskList {
+				if task.Type != api.TaskDatabaseSchemaUpdate && task.Type != api.TaskDatabaseDataUpdate {
+					continue
+				}
+				payload := &api.TaskDatabaseSchemaUpdatePayload{}
+				if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal payload of task %q", task.Name)).SetInternal(err)
+				}
+				databaseID, err := getTargetDatabaseID(task)
+				if err != nil {
+					return nil, err
+				}
+				c.MigrationType = payload.MigrationType
+				if task.Type == api.TaskDatabaseDataUpdate {
+					c.MigrationType = db.Data
+				}
+				c.DetailList = append(c.DetailList, &api.UpdateSchemaDetail{
+					DatabaseID: databaseID,
+					Statement:  payload.Statement,
+				})
+			}
+		}
+		createContext = c
+	case api.IssueDatabaseSchemaUpdateGhost:
+		c := api.UpdateSchemaGhostContext{}
+		for _, stage := range issue.Pipeline.StageList {
+			for _, task := range stage.TaskList {
+				if task.Type != api.TaskDatabaseSchemaUpdateGhostSync {
+					continue
+				}
+				payload := &api.TaskDatabaseSchemaUpdateGhostSyncPayload{}
+				if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal payload of task %q", task.Name)).SetInternal(err)
+				}
+				databaseID, err := getTargetDatabaseID(task)
+				if err != nil {
+					return nil, err
+				}
+				c.DetailList = append(c.DetailList, &api.UpdateSchemaGhostDetail{
+					DatabaseID: databaseID,
+					Statement:  payload.Statement,
+				})
+			}
+		}
+		createContext = c
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot clone issue with type %q", issue.Type))
+	}
+	createContextBytes, err := json.Marshal(createContext)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue create context").SetInternal(err)
+	}
+	payloadBytes, err := json.Marshal(api.IssuePayload{ClonedFromIssueID: issue.ID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue payload").SetInternal(err)
+	}
+
+	issueCreate := &api.IssueCreate{
+		ProjectID:     issue.ProjectID,
+		Name:          issueClone.Name,
+		Type:          issue.Type,
+		Description:   issue.Description,
+		AssigneeID:    issueClone.AssigneeID,
+		Payload:       string(payloadBytes),
+		LabelList:     issue.LabelList,
+		CreateContext: string(createContextBytes),
+		ValidateOnly:  issueClone.ValidateOnly,
+	}
+	if issueCreate.Name == "" {
+		issueCreate.Name = fmt.Sprintf("%s (clone)", issue.Name)
+	}
+	// Let createIssue pick the default assignee for the target environment.
+	if issueCreate.AssigneeID == api.UnknownID {
+		issueCreate.AssigneeID = api.SystemBotID
+	}
+	for _, subscriber := range issue.SubscriberList {
+		issueCreate.SubscriberIDList = append(issueCreate.SubscriberIDList, subscriber.ID)
+	}
+	return issueCreate, nil
+}
+
 func (s *Server) createPipeline(ctx context.Context, issueCreate *api.IssueCreate, pipelineCreate *api.PipelineCreate, creatorID int) (*api.Pipeline, error) {
 	// Return an error if the issue has no task to be executed
 	hasTask := false
@@ -419,6 +710,8 @@ func (s *Server) getPipelineCreate(ctx context.Context, issueCreate *api.IssueCr
 		return s.getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx, issueCreate)
 	case api.IssueDatabaseSchemaUpdateGhost:
 		return s.getPipelineCreateForDatabaseSchemaUpdateGhost(ctx, issueCreate)
+	case api.IssueGeneral:
+		return s.getPipelineCreateForDatabaseMaintenance(ctx, issueCreate)
 	default:
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid issue type %q", issueCreate.Type))
 	}
@@ -514,8 +807,8 @@ func (s *Server) getPipelineCreateForDatabaseCreate(ctx context.Context, issueCr
 }
 
 func (s *Server) getPipelineCreateForDatabasePITR(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
-	if !s.feature(api.FeaturePITR) {
-		return nil, echo.NewHTTPError(http.StatusForbidden, api.FeaturePITR.AccessErrorMessage())
+	if err := s.checkFeature(api.FeaturePITR); err != nil {
+		return nil, featureHTTPError(err)
 	}
 	c := api.PITRContext{}
 	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
@@ -548,15 +841,61 @@ func (s *Server) getPipelineCreateForDatabasePITR(ctx context.Context, issueCrea
 	}, nil
 }
 
+// getPipelineCreateForDatabaseMaintenance returns the pipeline with a single general task on the database.
+// The task does nothing when run, and is approved by the assignee to resolve the issue after the maintenance is done.
+func (s *Server) getPipelineCreateForDatabaseMaintenance(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.DatabaseMaintenanceContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid database maintenance context").SetInternal(err)
+	}
+	if c.TaskName == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, task name missing")
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	if database.ProjectID != issueCreate.ProjectID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q is not in the project of the issue", database.Name))
+	}
+
+	return &api.PipelineCreate{
+		Name: fmt.Sprintf("Maintain database %v pipeline", database.Name),
+		StageList: []api.StageCreate{
+			{
+				Name:          database.Instance.Environment.Name,
+				EnvironmentID: database.Instance.EnvironmentID,
+				TaskList: []api.TaskCreate{
+					{
+						Name:       c.TaskName,
+						InstanceID: database.InstanceID,
+						DatabaseID: &database.ID,
+						Status:     api.TaskPendingApproval,
+						Type:       api.TaskGeneral,
+						Payload:    "{}",
+					},
+				},
+			},
+		},
+	}, nil
+}
+
 func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
 	c := api.UpdateSchemaContext{}
 	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
 		return nil, err
 	}
-	if !s.feature(api.FeatureTaskScheduleTime) {
-		for _, detail := range c.DetailList {
-			if detail.EarliestAllowedTs != 0 {
-				return nil, echo.NewHTTPError(http.StatusForbidden, api.FeatureTaskScheduleTime.AccessErrorMessage())
+	if err := expandUpdateSchemaDatabaseIDList(&c); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	for _, detail := range c.DetailList {
+		if detail.EarliestAllowedTs != 0 {
+			if err := s.checkFeature(api.FeatureTaskScheduleTime); err != nil {
+				return nil, featureHTTPError(err)
 			}
 		}
 	}
@@ -588,8 +927,8 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 	schemaVersion := common.DefaultMigrationVersion()
 	// Tenant mode project pipeline has its own generation.
 	if project.TenantMode == api.TenantModeTenant {
-		if !s.feature(api.FeatureMultiTenancy) {
-			return nil, echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
+		if err := s.checkFeature(api.FeatureMultiTenancy); err != nil {
+			return nil, featureHTTPError(err)
 		}
 		if c.MigrationType != db.Migrate && c.MigrationType != db.Data {
 			return nil, echo.NewHTTPError(http.StatusBadRequest, "Only Migrate and Data type migration can be performed on tenant mode project")
@@ -603,6 +942,9 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 		}
 
 		if d.DatabaseName == "" && d.DatabaseID > 0 {
+			if c.Canary != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Canary requires the tenant databases to be selected by the database name")
+			}
 			database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &d.DatabaseID})
 			if err != nil {
 				return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", d.DatabaseID)).SetInternal(err)
@@ -634,39 +976,36 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 			if err != nil {
 				return nil, err
 			}
+			var canaryList []*api.Database
+			if c.Canary != nil {
+				canaryList, deployments, matrix, err = getCanaryDatabaseMatrix(c.Canary, deployments, matrix)
+				if err != nil {
+					return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+				}
+				stageCreate, err := getTenantStageCreate(canaryStageName, canaryList, c.MigrationType, c.VCSPushEvent, d, schemaVersion)
+				if err != nil {
+					return nil, err
+				}
+				create.StageList = append(create.StageList, *stageCreate)
+			}
 			// Convert to pipelineCreate
 			for i, databaseList := range matrix {
-				// Since environment is required for stage, we use an internal bb system environment for tenant deployments.
-				environmentSet := make(map[string]bool)
-				var environmentID int
-				var taskCreateList []api.TaskCreate
-				for _, database := range databaseList {
-					environmentSet[database.Instance.Environment.Name] = true
-					environmentID = database.Instance.EnvironmentID
-
-					taskCreate, err := getUpdateTask(database, c.MigrationType, c.VCSPushEvent, d, schemaVersion)
-					if err != nil {
-						return nil, err
-					}
-					taskCreateList = append(taskCreateList, *taskCreate)
+				stageCreate, err := getTenantStageCreate(deployments[i].Name, databaseList, c.MigrationType, c.VCSPushEvent, d, schemaVersion)
+				if err != nil {
+					return nil, err
 				}
-				if len(environmentSet) != 1 {
-					var environments []string
-					for k := range environmentSet {
-						environments = append(environments, k)
-					}
-					err := errors.Errorf("all databases in a stage should have the same environment; got %s", strings.Join(environments, ","))
-					return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+				if i == 0 && len(canaryList) > 0 {
+					// Hold the pipeline after the canary until the soak time passes or it's resumed manually.
+					stageCreate.Paused = true
+					stageCreate.SoakSeconds = c.Canary.SoakSeconds
 				}
-
-				create.StageList = append(create.StageList, api.StageCreate{
-					Name:          deployments[i].Name,
-					EnvironmentID: environmentID,
-					TaskList:      taskCreateList,
-				})
+				create.StageList = append(create.StageList, *stageCreate)
 			}
 		}
 	} else {
+		if c.Canary != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Canary is only supported in tenant mode project")
+		}
 		maximumTaskLimit := s.getPlanLimitValue(api.PlanLimitMaximumTask)
 		if int64(len(c.DetailList)) > maximumTaskLimit {
 			return nil, echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Effective plan %s can update up to %d databases, got %d.", s.getEffectivePlan(), maximumTaskLimit, len(c.DetailList)))
@@ -717,18 +1056,72 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 	return create, nil
 }
 
+// getTenantStageCreate gets the stage updating the tenant databases.
+func getTenantStageCreate(name string, databaseList []*api.Database, migrationType db.MigrationType, vcsPushEvent *vcs.PushEvent, d *api.UpdateSchemaDetail, schemaVersion string) (*api.StageCreate, error) {
+	// Since environment is required for stage, we use an internal bb system environment for tenant deployments.
+	environmentSet := make(map[string]bool)
+	var environmentID int
+	var taskCreateList []api.TaskCreate
+	for _, database := range databaseList {
+		environmentSet[database.Instance.Environment.Name] = true
+		environmentID = database.Instance.EnvironmentID
+
+		taskCreate, err := getUpdateTask(database, migrationType, vcsPushEvent, d, schemaVersion)
+		if err != nil {
+			return nil, err
+		}
+		taskCreateList = append(taskCreateList, *taskCreate)
+	}
+	if len(environmentSet) != 1 {
+		var environments []string
+		for k := range environmentSet {
+			environments = append(environments, k)
+		}
+		err := errors.Errorf("all databases in a stage should have the same environment; got %s", strings.Join(environments, ","))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return &api.StageCreate{
+		Name:          name,
+		EnvironmentID: environmentID,
+		TaskList:      taskCreateList,
+	}, nil
+}
+
+// expandUpdateSchemaDatabaseIDList converts the database ID list and the statement in the context to the detail list.
+func expandUpdateSchemaDatabaseIDList(c *api.UpdateSchemaContext) error {
+	if len(c.DatabaseIDList) == 0 {
+		return nil
+	}
+	if len(c.DetailList) > 0 {
+		return errors.New("databaseIdList and updateSchemaDetailList are mutually exclusive")
+	}
+	databaseIDSet := make(map[int]bool)
+	for _, databaseID := range c.DatabaseIDList {
+		if databaseIDSet[databaseID] {
+			return errors.Errorf("duplicate database ID %d in databaseIdList", databaseID)
+		}
+		databaseIDSet[databaseID] = true
+		c.DetailList = append(c.DetailList, &api.UpdateSchemaDetail{
+			DatabaseID: databaseID,
+			Statement:  c.Statement,
+		})
+	}
+	return nil
+}
+
 func (s *Server) getPipelineCreateForDatabaseSchemaUpdateGhost(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
-	if !s.feature(api.FeatureGhost) {
-		return nil, echo.NewHTTPError(http.StatusForbidden, api.FeatureGhost.AccessErrorMessage())
+	if err := s.checkFeature(api.FeatureGhost); err != nil {
+		return nil, featureHTTPError(err)
 	}
 	c := api.UpdateSchemaGhostContext{}
 	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
 		return nil, err
 	}
-	if !s.feature(api.FeatureTaskScheduleTime) {
-		for _, detail := range c.DetailList {
-			if detail.EarliestAllowedTs != 0 {
-				return nil, echo.NewHTTPError(http.StatusForbidden, api.FeatureTaskScheduleTime.AccessErrorMessage())
+	for _, detail := range c.DetailList {
+		if detail.EarliestAllowedTs != 0 {
+			if err := s.checkFeature(api.FeatureTaskScheduleTime); err != nil {
+				return nil, featureHTTPError(err)
 			}
 		}
 	}
@@ -756,6 +1149,14 @@ func (s *Server) getPipelineCreateForDatabaseSchemaUpdateGhost(ctx context.Conte
 		if database == nil {
 			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("database ID not found: %d", detail.DatabaseID))
 		}
+		// gh-ost relies on the MySQL binlog, and it's unnecessary for TiDB whose DDL is online already.
+		if database.Instance.Engine != db.MySQL {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("gh-ost only supports MySQL, database %q is on %s", database.Name, database.Instance.Engine))
+		}
+		// gh-ost connects the instance and its replicas by their addresses, which are unreachable without the SSH tunnel.
+		if database.Instance.SSHHost != "" {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("gh-ost doesn't support the instance %q connected through SSH tunnel", database.Instance.Name))
+		}
 
 		taskCreateList, taskIndexDAGList, err := createGhostTaskList(database, c.VCSPushEvent, detail, schemaVersion)
 		if err != nil {
@@ -773,6 +1174,16 @@ func (s *Server) getPipelineCreateForDatabaseSchemaUpdateGhost(ctx context.Conte
 }
 
 func getUpdateTask(database *api.Database, migrationType db.MigrationType, vcsPushEvent *vcs.PushEvent, d *api.UpdateSchemaDetail, schemaVersion string) (*api.TaskCreate, error) {
+	// Validate that the statement variables can be resolved for each target database, so the task won't fail on them at execution.
+	if _, err := api.ResolveStatementVariables(d.Statement, database); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid statement: %v", err)).SetInternal(err)
+	}
+	if d.TimeoutSeconds < 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid timeout %d seconds, must not be negative", d.TimeoutSeconds))
+	}
+	if d.SchemaName != "" && database.Instance.Engine != db.Postgres {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Schema %q is only supported for Postgres, but database %q is %s", d.SchemaName, database.Name, database.Instance.Engine))
+	}
 	taskName := fmt.Sprintf("Establish %q baseline", database.Name)
 	switch migrationType {
 	case db.Migrate:
@@ -784,6 +1195,8 @@ func getUpdateTask(database *api.Database, migrationType db.MigrationType, vcsPu
 	payload.MigrationType = migrationType
 	payload.Statement = d.Statement
 	payload.SchemaVersion = schemaVersion
+	payload.TimeoutSeconds = d.TimeoutSeconds
+	payload.SchemaName = d.SchemaName
 	if vcsPushEvent != nil {
 		payload.VCSPushEvent = vcsPushEvent
 	}
@@ -815,6 +1228,26 @@ func getUpdateTask(database *api.Database, migrationType db.MigrationType, vcsPu
 
 // createDatabaseCreateTaskList returns the task list for create database.
 func (s *Server) createDatabaseCreateTaskList(ctx context.Context, c api.CreateDatabaseContext, instance api.Instance, project api.Project) ([]api.TaskCreate, error) {
+	var template *api.DatabaseTemplate
+	if c.TemplateID != 0 {
+		t, err := s.store.GetDatabaseTemplateByID(ctx, c.TemplateID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database template ID: %v", c.TemplateID)).SetInternal(err)
+		}
+		if t == nil || t.WorkspaceID != instance.WorkspaceID {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database template ID not found: %d", c.TemplateID))
+		}
+		if t.Engine != instance.Engine {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database template %q is for %s, but the instance is %s", t.Name, t.Engine, instance.Engine))
+		}
+		if c.CharacterSet == "" {
+			c.CharacterSet = t.CharacterSet
+		}
+		if c.Collation == "" {
+			c.Collation = t.Collation
+		}
+		template = t
+	}
 	if err := checkCharacterSetCollationOwner(instance.Engine, c.CharacterSet, c.Collation, c.Owner); err != nil {
 		return nil, err
 	}
@@ -832,12 +1265,24 @@ func (s *Server) createDatabaseCreateTaskList(ctx context.Context, c api.CreateD
 		}
 	}
 
-	var schemaVersion, schema string
-	// We will use schema from existing tenant databases for creating a database in a tenant mode project if possible.
 	if project.TenantMode == api.TenantModeTenant {
-		if !s.feature(api.FeatureMultiTenancy) {
-			return nil, echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
+		if err := s.checkFeature(api.FeatureMultiTenancy); err != nil {
+			return nil, featureHTTPError(err)
+		}
+	}
+	var schemaVersion, schema string
+	// The baseline schema of the template takes precedence over the schema of the peer tenant databases.
+	if template != nil && template.SheetID != nil {
+		sheet, err := s.store.GetSheet(ctx, &api.SheetFind{ID: template.SheetID}, api.SystemBotID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sheet ID: %v", *template.SheetID)).SetInternal(err)
 		}
+		if sheet == nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Baseline schema sheet ID %d of database template %q not found", *template.SheetID, template.Name))
+		}
+		schema = sheet.Statement
+	} else if project.TenantMode == api.TenantModeTenant {
+		// We will use schema from existing tenant databases for creating a database in a tenant mode project if possible.
 		baseDatabaseName, err := api.GetBaseDatabaseName(c.DatabaseName, project.DBNameTemplate, c.Labels)
 		if err != nil {
 			return nil, errors.Wrapf(err, "api.GetBaseDatabaseName(%q, %q, %q) failed", c.DatabaseName, project.DBNameTemplate, c.Labels)
@@ -864,6 +1309,11 @@ func (s *Server) createDatabaseCreateTaskList(ctx context.Context, c api.CreateD
 		Labels:        c.Labels,
 		SchemaVersion: schemaVersion,
 	}
+	if template != nil {
+		if stmt := getDatabaseTemplateStatement(instance.Engine, c.DatabaseName, template); stmt != "" {
+			schema = fmt.Sprintf("%s\n%s", stmt, schema)
+		}
+	}
 	payload.DatabaseName, payload.Statement = getDatabaseNameAndStatement(instance.Engine, c, adminDataSource.Username, schema)
 	bytes, err := json.Marshal(payload)
 	if err != nil {
@@ -1144,6 +1594,12 @@ func (s *Server) changeIssueStatus(ctx context.Context, issue *api.Issue, newSta
 						return nil, errors.Wrapf(err, "failed to cancel issue: %v, failed to cancel task: %v", issue.Name, task.Name)
 					}
 				}
+				// The running task checks are no longer needed either.
+				if hasRunningTaskCheckRun(task) {
+					if _, err := s.TaskCheckScheduler.CancelTaskCheckRunList(ctx, task.ID, updaterID); err != nil {
+						return nil, errors.Wrapf(err, "failed to cancel issue: %v, failed to cancel task checks of task: %v", issue.Name, task.Name)
+					}
+				}
 			}
 		}
 		pipelineStatus = api.PipelineCanceled
@@ -1357,6 +1813,33 @@ func (s *Server) setTaskProgressForIssue(issue *api.Issue) {
 			if progress, ok := s.TaskScheduler.taskProgress.Load(task.ID); ok {
 				task.Progress = progress.(api.Progress)
 			}
+			if position, ok := s.TaskScheduler.taskQueuePosition.Load(task.ID); ok {
+				task.QueuePosition = position.(int)
+			}
 		}
 	}
 }
+
+// normalizeIssueLabelList trims the labels, and drops the empty and the duplicate ones.
+func normalizeIssueLabelList(labelList []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, label := range labelList {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		if strings.Contains(label, ",") {
+			return nil, errors.Errorf("label %q must not contain comma", label)
+		}
+		if utf8.RuneCountInString(label) > issueLabelMaxLength {
+			return nil, errors.Errorf("label %q is longer than %d characters", label, issueLabelMaxLength)
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	if len(normalized) > issueLabelMaxCount {
+		return nil, errors.Errorf("issue can have at most %d labels", issueLabelMaxCount)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// maxIssueReferenceCount caps the references resolved in an issue description.
+const maxIssueReferenceCount = 50
+
+// issueReferenceRegexp matches #123, db:prod/orders and sheet:123.
+// The reference mustn't follow a word character or a slash, so that the anchors and the paths in the URLs are not matched.
+var issueReferenceRegexp = regexp.MustCompile(`(?:^|[^\w/#&:])(#(\d+)|db:([\w-]+)/([\w$-]+)|sheet:(\d+))\b`)
+
+// issueReferenceText is a reference parsed from the issue description before being resolved.
+type issueReferenceText struct {
+	referenceType api.IssueReferenceType
+	text          string
+	// id is the ID of the referenced issue or sheet.
+	id int
+	// environment is the environment slug and database is the database name of the database reference.
+	environment string
+	database    string
+}
+
+// parseIssueReferenceText returns the distinct references in the issue description in the order they appear.
+func parseIssueReferenceText(description string) []*issueReferenceText {
+	var textList []*issueReferenceText
+	seen := make(map[string]bool)
+	for _, match := range issueReferenceRegexp.FindAllStringSubmatch(description, -1) {
+		if len(textList) >= maxIssueReferenceCount {
+			break
+		}
+		text := match[1]
+		if seen[text] {
+			continue
+		}
+		seen[text] = true
+
+		switch {
+		case match[2] != "":
+			id, err := strconv.Atoi(match[2])
+			if err != nil {
+				continue
+			}
+			textList = append(textList, &issueReferenceText{referenceType: api.IssueReferenceIssue, text: text, id: id})
+		case match[3] != "":
+			textList = append(textList, &issueReferenceText{referenceType: api.IssueReferenceDatabase, text: text, environment: strings.ToLower(match[3]), database: match[4]})
+		case match[5] != "":
+			id, err := strconv.Atoi(match[5])
+			if err != nil {
+				continue
+			}
+			textList = append(textList, &issueReferenceText{referenceType: api.IssueReferenceSheet, text: text, id: id})
+		}
+	}
+	return textList
+}
+
+// resolveIssueReferenceList resolves the references in the issue description to the targets in the workspace of the issue project.
+// The references to the missing targets are dropped, and a database reference resolves to the databases with the name
+// on all the instances in the environment.
+func (s *Server) resolveIssueReferenceList(ctx context.Context, issue *api.Issue, principalID int) ([]*api.IssueReference, error) {
+	textList := parseIssueReferenceText(issue.Description)
+	if len(textList) == 0 {
+		return nil, nil
+	}
+	workspaceID := issue.Project.WorkspaceID
+
+	type referenceKey struct {
+		referenceType api.IssueReferenceType
+		targetID      int
+	}
+	var referenceList []*api.IssueReference
+	seen := make(map[referenceKey]bool)
+	addReference := func(text *issueReferenceText, targetID int, name string) {
+		key := referenceKey{referenceType: text.referenceType, targetID: targetID}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		referenceList = append(referenceList, &api.IssueReference{
+			IssueID:  issue.ID,
+			Type:     text.referenceType,
+			Text:     text.text,
+			TargetID: targetID,
+			Name:     name,
+		})
+	}
+
+	var envList []*api.Environment
+	for _, text := range textList {
+		switch text.referenceType {
+		case api.IssueReferenceIssue:
+			if text.id == issue.ID {
+				continue
+			}
+			issueList, err := s.store.FindIssueStripped(ctx, &api.IssueFind{ID: &text.id, WorkspaceID: &workspaceID})
+			if err != nil {
+				return nil, err
+			}
+			for _, referencedIssue := range issueList {
+				addReference(text, referencedIssue.ID, referencedIssue.Name)
+			}
+		case api.IssueReferenceDatabase:
+			if envList == nil {
+				list, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{WorkspaceID: &workspaceID})
+				if err != nil {
+					return nil, err
+				}
+				envList = list
+			}
+			envIDMap := make(map[int]bool)
+			for _, env := range envList {
+				if api.EnvSlug(env) == text.environment {
+					envIDMap[env.ID] = true
+				}
+			}
+			if len(envIDMap) == 0 {
+				continue
+			}
+			databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{Name: &text.database, WorkspaceID: &workspaceID})
+			if err != nil {
+				return nil, err
+			}
+			for _, database := range databaseList {
+				if envIDMap[database.Instance.EnvironmentID] {
+					addReference(text, database.ID, database.Name)
+				}
+			}
+		case api.IssueReferenceSheet:
+			sheet, err := s.store.GetSheet(ctx, &api.SheetFind{ID: &text.id, WorkspaceID: &workspaceID}, principalID)
+			if err != nil {
+				return nil, err
+			}
+			if sheet == nil || sheet.RowStatus != api.Normal || !canReferenceSheet(sheet, issue.ProjectID, principalID) {
+				continue
+			}
+			addReference(text, sheet.ID, sheet.Name)
+		}
+	}
+	return referenceList, nil
+}
+
+// canReferenceSheet returns true if the principal can reference the sheet in an issue of the project.
+// A private sheet can only be referenced by the creator, and a project sheet only in the issues of the same project.
+func canReferenceSheet(sheet *api.Sheet, projectID int, principalID int) bool {
+	switch sheet.Visibility {
+	case api.PublicSheet:
+		return true
+	case api.ProjectSheet:
+		return sheet.ProjectID == projectID
+	case api.PrivateSheet:
+		return sheet.CreatorID == principalID
+	}
+	return false
+}
+
+// setIssueReferenceList resolves and saves the references in the issue description.
+func (s *Server) setIssueReferenceList(ctx context.Context, issue *api.Issue, principalID int) error {
+	referenceList, err := s.resolveIssueReferenceList(ctx, issue, principalID)
+	if err != nil {
+		return err
+	}
+	if err := s.store.SetIssueReferenceList(ctx, issue.ID, referenceList); err != nil {
+		return err
+	}
+	issue.ReferenceList = referenceList
+	return nil
+}
+
+// getIssueReferenceFind returns the find for the issues referencing the target, e.g. the issues referencing a database.
+func getIssueReferenceFind(referenceType string, targetIDStr string) (*api.IssueReferenceFind, error) {
+	find := &api.IssueReferenceFind{Type: api.IssueReferenceType(referenceType)}
+	switch find.Type {
+	case api.IssueReferenceIssue, api.IssueReferenceDatabase, api.IssueReferenceSheet:
+	default:
+		return nil, errors.Errorf("invalid referenceType query parameter: %s", referenceType)
+	}
+	targetID, err := strconv.Atoi(targetIDStr)
+	if err != nil {
+		return nil, errors.Errorf("referenceId query parameter is not a number: %s", targetIDStr)
+	}
+	find.TargetID = targetID
+	return find, nil
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestParseIssueReferenceText(t *testing.T) {
+	tests := []struct {
+		description string
+		want        []*issueReferenceText
+	}{
+		{
+			description: "",
+			want:        nil,
+		},
+		{
+			description: "Follow up #12, see db:Prod/orders and sheet:3.",
+			want: []*issueReferenceText{
+				{referenceType: api.IssueReferenceIssue, text: "#12", id: 12},
+				{referenceType: api.IssueReferenceDatabase, text: "db:Prod/orders", environment: "prod", database: "orders"},
+				{referenceType: api.IssueReferenceSheet, text: "sheet:3", id: 3},
+			},
+		},
+		// The duplicate references are parsed once.
+		{
+			description: "(#12) and #12\n#13",
+			want: []*issueReferenceText{
+				{referenceType: api.IssueReferenceIssue, text: "#12", id: 12},
+				{referenceType: api.IssueReferenceIssue, text: "#13", id: 13},
+			},
+		},
+		// The anchors, the paths and the words ending with the prefixes aren't references.
+		{
+			description: "https://example.com/page#12 a/#12 a#12 &#12; #12a mydb:prod/orders worksheet:3 # 12",
+			want:        nil,
+		},
+		{
+			description: "db:prod-dr/order_2022$",
+			want: []*issueReferenceText{
+				{referenceType: api.IssueReferenceDatabase, text: "db:prod-dr/order_2022", environment: "prod-dr", database: "order_2022"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, parseIssueReferenceText(test.description), test.description)
+	}
+}
+
+func TestParseIssueReferenceTextLimit(t *testing.T) {
+	description := ""
+	for i := 0; i < maxIssueReferenceCount+10; i++ {
+		description += fmt.Sprintf(" sheet:%d", i+1)
+	}
+	require.Len(t, parseIssueReferenceText(description), maxIssueReferenceCount)
+}
+
+func TestCanReferenceSheet(t *testing.T) {
+	tests := []struct {
+		visibility  api.SheetVisibility
+		projectID   int
+		principalID int
+		want        bool
+	}{
+		{visibility: api.PublicSheet, projectID: 102, principalID: 202, want: true},
+		{visibility: api.ProjectSheet, projectID: 101, principalID: 202, want: true},
+		{visibility: api.ProjectSheet, projectID: 102, principalID: 201, want: false},
+		{visibility: api.PrivateSheet, projectID: 101, principalID: 201, want: true},
+		{visibility: api.PrivateSheet, projectID: 101, principalID: 202, want: false},
+	}
+
+	for _, test := range tests {
+		sheet := &api.Sheet{CreatorID: 201, ProjectID: 101, Visibility: test.visibility}
+		require.Equal(t, test.want, canReferenceSheet(sheet, test.projectID, test.principalID), test)
+	}
+}
+
+func TestGetIssueReferenceFind(t *testing.T) {
+	a := require.New(t)
+	find, err := getIssueReferenceFind("DATABASE", "101")
+	a.NoError(err)
+	a.Equal(&api.IssueReferenceFind{Type: api.IssueReferenceDatabase, TargetID: 101}, find)
+
+	_, err = getIssueReferenceFind("TABLE", "101")
+	a.Error(err)
+	_, err = getIssueReferenceFind("SHEET", "")
+	a.Error(err)
+}
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+const (
+	// defaultIssueSLAReportWindow is the time window of the issue SLA report if not specified.
+	defaultIssueSLAReportWindow = 30 * 24 * time.Hour
+)
+
+func (s *Server) registerIssueSLARoutes(g *echo.Group) {
+	g.GET("/issue/:issueID/sla", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+
+		issue, err := s.store.GetIssueByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %v", id)).SetInternal(err)
+		}
+		if issue == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", id))
+		}
+
+		sla, err := s.getIssueSLA(ctx, issue, make(map[int]*api.IssueSLAPolicy), time.Now().Unix())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compute SLA for issue ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, sla); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal issue SLA response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Reports the SLA of the issues created in the time window [startTs, endTs), which defaults to the last 30 days.
+	// The report can be filtered by the project.
+	g.GET("/issue-sla-report", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		now := time.Now().Unix()
+		endTs := now
+		if endTsStr := c.QueryParam("endTs"); endTsStr != "" {
+			v, err := strconv.ParseInt(endTsStr, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter endTs is not a number: %s", endTsStr)).SetInternal(err)
+			}
+			endTs = v
+		}
+		startTs := endTs - int64(defaultIssueSLAReportWindow/time.Second)
+		if startTsStr := c.QueryParam("startTs"); startTsStr != "" {
+			v, err := strconv.ParseInt(startTsStr, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter startTs is not a number: %s", startTsStr)).SetInternal(err)
+			}
+			startTs = v
+		}
+		if startTs >= endTs {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter startTs %d must be before endTs %d", startTs, endTs))
+		}
+
+		issueFind := &api.IssueFind{
+			WorkspaceID:     &workspaceID,
+			CreatedTsAfter:  &startTs,
+			CreatedTsBefore: &endTs,
+		}
+		if projectIDStr := c.QueryParam("project"); projectIDStr != "" {
+			projectID, err := strconv.Atoi(projectIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter project is not a number: %s", projectIDStr)).SetInternal(err)
+			}
+			issueFind.ProjectID = &projectID
+		}
+		issueList, err := s.store.FindIssueStripped(ctx, issueFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch issue list").SetInternal(err)
+		}
+
+		// The policies are shared by the issues, so they are only fetched once.
+		policyMap := make(map[int]*api.IssueSLAPolicy)
+		var slaList []*api.IssueSLA
+		for _, issue := range issueList {
+			pipeline, err := s.store.GetPipelineByID(ctx, issue.PipelineID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch pipeline ID: %v", issue.PipelineID)).SetInternal(err)
+			}
+			if pipeline == nil {
+				continue
+			}
+			issue.Pipeline = pipeline
+			sla, err := s.getIssueSLA(ctx, issue, policyMap, now)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compute SLA for issue ID: %v", issue.ID)).SetInternal(err)
+			}
+			slaList = append(slaList, sla)
+		}
+
+		report := buildIssueSLAReport(slaList)
+		report.StartTs = startTs
+		report.EndTs = endTs
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, report); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue SLA report response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getIssueSLA computes the SLA timers of the issue, whose pipeline must be composed.
+// The SLA policies of the environments are looked up in and added to the policyMap.
+func (s *Server) getIssueSLA(ctx context.Context, issue *api.Issue, policyMap map[int]*api.IssueSLAPolicy, now int64) (*api.IssueSLA, error) {
+	hasTarget := false
+	for _, stage := range issue.Pipeline.StageList {
+		policy, ok := policyMap[stage.EnvironmentID]
+		if !ok {
+			var err error
+			policy, err = s.store.GetIssueSLAPolicyByEnvID(ctx, stage.EnvironmentID)
+			if err != nil {
+				return nil, err
+			}
+			policyMap[stage.EnvironmentID] = policy
+		}
+		if policy.ApprovalSeconds > 0 || policy.DoneSeconds > 0 {
+			hasTarget = true
+		}
+	}
+	if !hasTarget {
+		return buildIssueSLA(issue, policyMap, nil, now)
+	}
+
+	typePrefix := string(api.ActivityPipelineTaskStatusUpdate)
+	activityList, err := s.store.FindActivity(ctx, &api.ActivityFind{
+		ContainerID: &issue.PipelineID,
+		TypePrefix:  &typePrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buildIssueSLA(issue, policyMap, activityList, now)
+}
+
+// buildIssueSLA builds the SLA timers of the issue for the stages in the environments with the SLA targets.
+// The task approval and completion times are taken from the task status update activities.
+func buildIssueSLA(issue *api.Issue, policyMap map[int]*api.IssueSLAPolicy, activityList []*api.Activity, now int64) (*api.IssueSLA, error) {
+	approvedTsMap := make(map[int]int64)
+	doneTsMap := make(map[int]int64)
+	for _, activity := range activityList {
+		if activity.Type != api.ActivityPipelineTaskStatusUpdate {
+			continue
+		}
+		payload := &api.ActivityPipelineTaskStatusUpdatePayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal task status update activity payload %q", activity.Payload)
+		}
+		// The approval may be dismissed and given again, so the latest one counts.
+		if payload.OldStatus == api.TaskPendingApproval && payload.NewStatus == api.TaskPending && activity.CreatedTs > approvedTsMap[payload.TaskID] {
+			approvedTsMap[payload.TaskID] = activity.CreatedTs
+		}
+		if payload.NewStatus == api.TaskDone && activity.CreatedTs > doneTsMap[payload.TaskID] {
+			doneTsMap[payload.TaskID] = activity.CreatedTs
+		}
+	}
+
+	sla := &api.IssueSLA{
+		IssueID:   issue.ID,
+		TimerList: []*api.IssueSLATimer{},
+	}
+	for _, stage := range issue.Pipeline.StageList {
+		policy := policyMap[stage.EnvironmentID]
+		if policy == nil || len(stage.TaskList) == 0 {
+			continue
+		}
+		if policy.ApprovalSeconds > 0 {
+			var stopTs int64
+			for _, task := range stage.TaskList {
+				if task.Status == api.TaskPendingApproval {
+					stopTs = 0
+					break
+				}
+				// The task never pending approval is approved on creation.
+				ts := task.CreatedTs
+				if v, ok := approvedTsMap[task.ID]; ok {
+					ts = v
+				}
+				if ts > stopTs {
+					stopTs = ts
+				}
+			}
+			sla.TimerList = append(sla.TimerList, newIssueSLATimer(issue, stage, api.IssueSLATimerApproval, policy.ApprovalSeconds, stopTs, now))
+		}
+		if policy.DoneSeconds > 0 {
+			var stopTs int64
+			for _, task := range stage.TaskList {
+				if task.Status != api.TaskDone {
+					stopTs = 0
+					break
+				}
+				ts := task.UpdatedTs
+				if v, ok := doneTsMap[task.ID]; ok {
+					ts = v
+				}
+				if ts > stopTs {
+					stopTs = ts
+				}
+			}
+			sla.TimerList = append(sla.TimerList, newIssueSLATimer(issue, stage, api.IssueSLATimerDone, policy.DoneSeconds, stopTs, now))
+		}
+	}
+	for _, timer := range sla.TimerList {
+		if timer.Breached {
+			sla.Breached = true
+		}
+	}
+	return sla, nil
+}
+
+// newIssueSLATimer creates the timer of the stage, which stops at the stopTs, or when the issue is closed if the stopTs is 0.
+func newIssueSLATimer(issue *api.Issue, stage *api.Stage, timerType api.IssueSLATimerType, targetSeconds int64, stopTs int64, now int64) *api.IssueSLATimer {
+	if stopTs == 0 && issue.Status != api.IssueOpen {
+		stopTs = issue.UpdatedTs
+	}
+	timer := &api.IssueSLATimer{
+		StageID:       stage.ID,
+		EnvironmentID: stage.EnvironmentID,
+		Type:          timerType,
+		TargetSeconds: targetSeconds,
+		StartTs:       issue.CreatedTs,
+		DueTs:         issue.CreatedTs + targetSeconds,
+		StopTs:        stopTs,
+	}
+	endTs := now
+	if stopTs != 0 {
+		endTs = stopTs
+	}
+	if endTs > timer.StartTs {
+		timer.ElapsedSeconds = endTs - timer.StartTs
+	}
+	timer.Breached = endTs > timer.DueTs
+	return timer
+}
+
+type issueSLAReportKey struct {
+	environmentID int
+	timerType     api.IssueSLATimerType
+}
+
+// buildIssueSLAReport aggregates the SLA timers of the issues by the environment and the timer type.
+func buildIssueSLAReport(slaList []*api.IssueSLA) *api.IssueSLAReport {
+	report := &api.IssueSLAReport{
+		BreachedIssueIDList: []int{},
+		ItemList:            []*api.IssueSLAReportItem{},
+	}
+	itemMap := make(map[issueSLAReportKey]*api.IssueSLAReportItem)
+	stoppedMap := make(map[issueSLAReportKey]int)
+	leadSecondsMap := make(map[issueSLAReportKey]int64)
+	for _, sla := range slaList {
+		if len(sla.TimerList) == 0 {
+			continue
+		}
+		report.IssueCount++
+		if sla.Breached {
+			report.BreachedIssueIDList = append(report.BreachedIssueIDList, sla.IssueID)
+		}
+		for _, timer := range sla.TimerList {
+			key := issueSLAReportKey{environmentID: timer.EnvironmentID, timerType: timer.Type}
+			item, ok := itemMap[key]
+			if !ok {
+				item = &api.IssueSLAReportItem{
+					EnvironmentID: timer.EnvironmentID,
+					Type:          timer.Type,
+				}
+				itemMap[key] = item
+				report.ItemList = append(report.ItemList, item)
+			}
+			item.TimerCount++
+			if timer.Breached {
+				item.BreachedCount++
+			}
+			if timer.StopTs != 0 {
+				stoppedMap[key]++
+				leadSecondsMap[key] += timer.ElapsedSeconds
+			}
+		}
+	}
+	for key, item := range itemMap {
+		if stoppedMap[key] > 0 {
+			item.AverageLeadSeconds = float64(leadSecondsMap[key]) / float64(stoppedMap[key])
+		}
+	}
+
+	sort.Ints(report.BreachedIssueIDList)
+	sort.Slice(report.ItemList, func(i, j int) bool {
+		if report.ItemList[i].EnvironmentID != report.ItemList[j].EnvironmentID {
+			return report.ItemList[i].EnvironmentID < report.ItemList[j].EnvironmentID
+		}
+		return report.ItemList[i].Type < report.ItemList[j].Type
+	})
+	return report
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func newTaskStatusUpdateActivity(t *testing.T, createdTs int64, taskID int, oldStatus, newStatus api.TaskStatus) *api.Activity {
+	payload, err := json.Marshal(api.ActivityPipelineTaskStatusUpdatePayload{
+		TaskID:    taskID,
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+	})
+	require.NoError(t, err)
+	return &api.Activity{
+		CreatedTs: createdTs,
+		Type:      api.ActivityPipelineTaskStatusUpdate,
+		Payload:   string(payload),
+	}
+}
+
+func TestBuildIssueSLA(t *testing.T) {
+	issue := &api.Issue{
+		ID:        1,
+		CreatedTs: 1000,
+		Status:    api.IssueOpen,
+		Pipeline: &api.Pipeline{
+			StageList: []*api.Stage{
+				{
+					ID:            11,
+					EnvironmentID: 101,
+					TaskList: []*api.Task{
+						{ID: 111, CreatedTs: 1000, UpdatedTs: 1500, Status: api.TaskDone},
+						{ID: 112, CreatedTs: 1000, UpdatedTs: 1700, Status: api.TaskDone},
+					},
+				},
+				{
+					ID:            12,
+					EnvironmentID: 102,
+					TaskList: []*api.Task{
+						{ID: 121, CreatedTs: 1000, UpdatedTs: 2000, Status: api.TaskPending},
+						{ID: 122, CreatedTs: 1000, UpdatedTs: 1000, Status: api.TaskPendingApproval},
+					},
+				},
+				{
+					ID:            13,
+					EnvironmentID: 103,
+					TaskList: []*api.Task{
+						{ID: 131, CreatedTs: 1000, UpdatedTs: 1000, Status: api.TaskPendingApproval},
+					},
+				},
+			},
+		},
+	}
+	policyMap := map[int]*api.IssueSLAPolicy{
+		101: {ApprovalSeconds: 600, DoneSeconds: 600},
+		102: {ApprovalSeconds: 3600},
+		103: {},
+	}
+	activityList := []*api.Activity{
+		// The tasks in the first environment are approved on creation.
+		newTaskStatusUpdateActivity(t, 1100, 111, api.TaskPending, api.TaskRunning),
+		newTaskStatusUpdateActivity(t, 1400, 111, api.TaskRunning, api.TaskDone),
+		newTaskStatusUpdateActivity(t, 1800, 112, api.TaskRunning, api.TaskDone),
+		newTaskStatusUpdateActivity(t, 1900, 121, api.TaskPendingApproval, api.TaskPending),
+	}
+
+	sla, err := buildIssueSLA(issue, policyMap, activityList, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sla.IssueID)
+	assert.True(t, sla.Breached)
+	assert.Equal(t, []*api.IssueSLATimer{
+		{StageID: 11, EnvironmentID: 101, Type: api.IssueSLATimerApproval, TargetSeconds: 600, StartTs: 1000, DueTs: 1600, StopTs: 1000, ElapsedSeconds: 0, Breached: false},
+		{StageID: 11, EnvironmentID: 101, Type: api.IssueSLATimerDone, TargetSeconds: 600, StartTs: 1000, DueTs: 1600, StopTs: 1800, ElapsedSeconds: 800, Breached: true},
+		{StageID: 12, EnvironmentID: 102, Type: api.IssueSLATimerApproval, TargetSeconds: 3600, StartTs: 1000, DueTs: 4600, StopTs: 0, ElapsedSeconds: 4000, Breached: true},
+	}, sla.TimerList)
+
+	// The running timers stop when the issue is canceled.
+	issue.Status = api.IssueCanceled
+	issue.UpdatedTs = 3000
+	sla, err = buildIssueSLA(issue, policyMap, activityList, 5000)
+	require.NoError(t, err)
+	timer := sla.TimerList[2]
+	assert.Equal(t, int64(3000), timer.StopTs)
+	assert.Equal(t, int64(2000), timer.ElapsedSeconds)
+	assert.False(t, timer.Breached)
+
+	sla, err = buildIssueSLA(issue, map[int]*api.IssueSLAPolicy{}, nil, 5000)
+	require.NoError(t, err)
+	assert.False(t, sla.Breached)
+	assert.Empty(t, sla.TimerList)
+}
+
+func TestBuildIssueSLAReport(t *testing.T) {
+	slaList := []*api.IssueSLA{
+		{
+			IssueID:  3,
+			Breached: true,
+			TimerList: []*api.IssueSLATimer{
+				{EnvironmentID: 101, Type: api.IssueSLATimerDone, StopTs: 1800, ElapsedSeconds: 800, Breached: true},
+				{EnvironmentID: 101, Type: api.IssueSLATimerApproval, StopTs: 1000, ElapsedSeconds: 0},
+			},
+		},
+		{
+			IssueID: 2,
+			TimerList: []*api.IssueSLATimer{
+				{EnvironmentID: 101, Type: api.IssueSLATimerApproval, StopTs: 1200, ElapsedSeconds: 200},
+				{EnvironmentID: 102, Type: api.IssueSLATimerApproval, ElapsedSeconds: 100},
+			},
+		},
+		{
+			IssueID:   1,
+			TimerList: []*api.IssueSLATimer{},
+		},
+	}
+
+	report := buildIssueSLAReport(slaList)
+	assert.Equal(t, 2, report.IssueCount)
+	assert.Equal(t, []int{3}, report.BreachedIssueIDList)
+	assert.Equal(t, []*api.IssueSLAReportItem{
+		{EnvironmentID: 101, Type: api.IssueSLATimerApproval, TimerCount: 2, BreachedCount: 0, AverageLeadSeconds: 100},
+		{EnvironmentID: 101, Type: api.IssueSLATimerDone, TimerCount: 1, BreachedCount: 1, AverageLeadSeconds: 800},
+		{EnvironmentID: 102, Type: api.IssueSLATimerApproval, TimerCount: 1, BreachedCount: 0, AverageLeadSeconds: 0},
+	}, report.ItemList)
+
+	empty := buildIssueSLAReport(nil)
+	assert.Equal(t, 0, empty.IssueCount)
+	assert.Empty(t, empty.BreachedIssueIDList)
+	assert.Empty(t, empty.ItemList)
+}
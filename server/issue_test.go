@@ -1,6 +1,8 @@
 package server
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/bytebase/bytebase/api"
@@ -165,3 +167,76 @@ func TestCheckCharacterSetCollationOwner(t *testing.T) {
 		}
 	}
 }
+
+func TestExpandUpdateSchemaDatabaseIDList(t *testing.T) {
+	c := &api.UpdateSchemaContext{
+		MigrationType:  db.Migrate,
+		DatabaseIDList: []int{3, 1},
+		Statement:      "CREATE TABLE t(id INT);",
+	}
+	require.NoError(t, expandUpdateSchemaDatabaseIDList(c))
+	assert.Equal(t, []*api.UpdateSchemaDetail{
+		{DatabaseID: 3, Statement: "CREATE TABLE t(id INT);"},
+		{DatabaseID: 1, Statement: "CREATE TABLE t(id INT);"},
+	}, c.DetailList)
+
+	// Duplicate databases.
+	c = &api.UpdateSchemaContext{
+		DatabaseIDList: []int{1, 1},
+		Statement:      "CREATE TABLE t(id INT);",
+	}
+	require.Error(t, expandUpdateSchemaDatabaseIDList(c))
+
+	// Mutually exclusive to the detail list.
+	c = &api.UpdateSchemaContext{
+		DatabaseIDList: []int{1},
+		DetailList:     []*api.UpdateSchemaDetail{{DatabaseID: 2}},
+	}
+	require.Error(t, expandUpdateSchemaDatabaseIDList(c))
+}
+
+func TestNormalizeIssueLabelList(t *testing.T) {
+	tests := []struct {
+		labelList []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			labelList: nil,
+			want:      nil,
+		},
+		{
+			labelList: []string{" backlog ", "", "urgent", "backlog"},
+			want:      []string{"backlog", "urgent"},
+		},
+		{
+			labelList: []string{"a,b"},
+			wantErr:   true,
+		},
+		{
+			labelList: []string{strings.Repeat("标", issueLabelMaxLength)},
+			want:      []string{strings.Repeat("标", issueLabelMaxLength)},
+		},
+		{
+			labelList: []string{strings.Repeat("a", issueLabelMaxLength+1)},
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := normalizeIssueLabelList(test.labelList)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, test.want, got)
+	}
+
+	var tooMany []string
+	for i := 0; i <= issueLabelMaxCount; i++ {
+		tooMany = append(tooMany, fmt.Sprintf("label%d", i))
+	}
+	_, err := normalizeIssueLabelList(tooMany)
+	assert.Error(t, err)
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerIssueViewRoutes(g *echo.Group) {
+	g.POST("/issue-view", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		viewCreate := &api.IssueViewCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, viewCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create issue view request").SetInternal(err)
+		}
+		viewCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		viewCreate.WorkspaceID = c.Get(getWorkspaceIDContextKey()).(int)
+
+		if viewCreate.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Issue view name is required")
+		}
+		filter, err := normalizeIssueViewFilter(viewCreate.Filter)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid issue view filter: %v", err)).SetInternal(err)
+		}
+		viewCreate.Filter = filter
+		if viewCreate.ProjectID != nil {
+			if err := s.checkIssueViewProject(ctx, *viewCreate.ProjectID, viewCreate.WorkspaceID); err != nil {
+				return err
+			}
+		}
+
+		view, err := s.store.CreateIssueView(ctx, viewCreate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create issue view").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, view); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create issue view response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// The views of the project are returned if the project is specified, otherwise the views created by the caller.
+	g.GET("/issue-view", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		viewFind := &api.IssueViewFind{
+			WorkspaceID: &workspaceID,
+		}
+		if projectIDStr := c.QueryParam("project"); projectIDStr != "" {
+			projectID, err := strconv.Atoi(projectIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("project query parameter is not a number: %s", projectIDStr)).SetInternal(err)
+			}
+			viewFind.ProjectID = &projectID
+		} else {
+			principalID := c.Get(getPrincipalIDContextKey()).(int)
+			viewFind.CreatorID = &principalID
+		}
+
+		viewList, err := s.store.FindIssueView(ctx, viewFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch issue view list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, viewList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue view list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/issue-view/:issueViewID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("issueViewID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("issueViewID"))).SetInternal(err)
+		}
+
+		viewPatch := &api.IssueViewPatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, viewPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch issue view request").SetInternal(err)
+		}
+		if err := s.checkIssueViewCreator(ctx, id, viewPatch.UpdaterID); err != nil {
+			return err
+		}
+		if v := viewPatch.Name; v != nil && *v == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Issue view name is required")
+		}
+		if v := viewPatch.Filter; v != nil {
+			filter, err := normalizeIssueViewFilter(*v)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid issue view filter: %v", err)).SetInternal(err)
+			}
+			viewPatch.Filter = &filter
+		}
+
+		view, err := s.store.PatchIssueView(ctx, viewPatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue view ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch issue view ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, view); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal issue view ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/issue-view/:issueViewID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("issueViewID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("issueViewID"))).SetInternal(err)
+		}
+
+		viewDelete := &api.IssueViewDelete{
+			ID:        id,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.checkIssueViewCreator(ctx, id, viewDelete.DeleterID); err != nil {
+			return err
+		}
+		if err := s.store.DeleteIssueView(ctx, viewDelete); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue view ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete issue view ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// checkIssueViewProject makes sure the project of the new issue view is in the workspace, and returns the HTTP error otherwise.
+func (s *Server) checkIssueViewProject(ctx context.Context, projectID int, workspaceID int) error {
+	project, err := s.store.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+	}
+	// The default project is shared by all workspaces.
+	if project == nil || (project.ID != api.DefaultProjectID && project.WorkspaceID != workspaceID) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID not found: %d", projectID))
+	}
+	return nil
+}
+
+// checkIssueViewCreator makes sure the issue view is changed by its creator, and returns the HTTP error otherwise.
+func (s *Server) checkIssueViewCreator(ctx context.Context, id int, principalID int) error {
+	view, err := s.store.GetIssueViewByID(ctx, id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue view ID: %v", id)).SetInternal(err)
+	}
+	if view == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue view ID not found: %d", id))
+	}
+	if view.CreatorID != principalID {
+		return echo.NewHTTPError(http.StatusForbidden, "Only the creator can change the issue view")
+	}
+	return nil
+}
+
+// normalizeIssueViewFilter validates the json-encoded issue view filter and returns it normalized.
+func normalizeIssueViewFilter(filter string) (string, error) {
+	if filter == "" {
+		return "{}", nil
+	}
+	viewFilter := &api.IssueViewFilter{}
+	if err := json.Unmarshal([]byte(filter), viewFilter); err != nil {
+		return "", errors.Wrap(err, "malformed filter")
+	}
+	for _, status := range viewFilter.StatusList {
+		switch status {
+		case api.IssueOpen, api.IssueDone, api.IssueCanceled:
+		default:
+			return "", errors.Errorf("invalid issue status %q", status)
+		}
+	}
+	viewFilter.Label = strings.TrimSpace(viewFilter.Label)
+	bytes, err := json.Marshal(viewFilter)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal filter")
+	}
+	return string(bytes), nil
+}
+
+// canReadIssueView returns true if the principal can filter the issues by the view.
+// The views of the projects are shared with the workspace, and the others are private to their creators.
+func canReadIssueView(view *api.IssueView, principalID int, workspaceID int) bool {
+	if view.WorkspaceID != workspaceID {
+		return false
+	}
+	return view.ProjectID != nil || view.CreatorID == principalID
+}
+
+// applyIssueViewFilter applies the filter of the issue view to the issue find.
+func applyIssueViewFilter(find *api.IssueFind, view *api.IssueView) error {
+	viewFilter := &api.IssueViewFilter{}
+	if err := json.Unmarshal([]byte(view.Filter), viewFilter); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal filter of issue view %d", view.ID)
+	}
+	if view.ProjectID != nil {
+		find.ProjectID = view.ProjectID
+	}
+	if len(viewFilter.StatusList) > 0 {
+		find.StatusList = viewFilter.StatusList
+	}
+	if viewFilter.AssigneeID != nil {
+		find.AssigneeID = viewFilter.AssigneeID
+	}
+	if viewFilter.EnvironmentID != nil {
+		find.EnvironmentID = viewFilter.EnvironmentID
+	}
+	if viewFilter.Label != "" {
+		label := viewFilter.Label
+		find.Label = &label
+	}
+	return nil
+}
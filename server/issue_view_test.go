@@ -0,0 +1,112 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestNormalizeIssueViewFilter(t *testing.T) {
+	tests := []struct {
+		filter  string
+		want    string
+		wantErr bool
+	}{
+		{
+			filter: "",
+			want:   "{}",
+		},
+		{
+			filter: `{"statusList":["OPEN","DONE"],"assigneeId":101,"label":" backlog "}`,
+			want:   `{"statusList":["OPEN","DONE"],"assigneeId":101,"label":"backlog"}`,
+		},
+		{
+			filter:  `{"statusList":["PENDING"]}`,
+			wantErr: true,
+		},
+		{
+			filter:  `{"statusList":`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := normalizeIssueViewFilter(test.filter)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, test.want, got)
+	}
+}
+
+func TestCanReadIssueView(t *testing.T) {
+	projectID := 102
+	tests := []struct {
+		view        *api.IssueView
+		principalID int
+		workspaceID int
+		want        bool
+	}{
+		{
+			view:        &api.IssueView{CreatorID: 101, WorkspaceID: 1},
+			principalID: 101,
+			workspaceID: 1,
+			want:        true,
+		},
+		{
+			view:        &api.IssueView{CreatorID: 101, WorkspaceID: 1},
+			principalID: 102,
+			workspaceID: 1,
+			want:        false,
+		},
+		{
+			view:        &api.IssueView{CreatorID: 101, WorkspaceID: 1, ProjectID: &projectID},
+			principalID: 102,
+			workspaceID: 1,
+			want:        true,
+		},
+		{
+			view:        &api.IssueView{CreatorID: 101, WorkspaceID: 1, ProjectID: &projectID},
+			principalID: 102,
+			workspaceID: 2,
+			want:        false,
+		},
+	}
+
+	for _, test := range tests {
+		got := canReadIssueView(test.view, test.principalID, test.workspaceID)
+		assert.Equal(t, test.want, got)
+	}
+}
+
+func TestApplyIssueViewFilter(t *testing.T) {
+	workspaceID, projectID, assigneeID, environmentID := 1, 102, 103, 104
+	find := &api.IssueFind{WorkspaceID: &workspaceID}
+	view := &api.IssueView{
+		ID:        101,
+		ProjectID: &projectID,
+		Filter:    `{"statusList":["OPEN"],"assigneeId":103,"environmentId":104,"label":"backlog"}`,
+	}
+	require.NoError(t, applyIssueViewFilter(find, view))
+	label := "backlog"
+	assert.Equal(t, &api.IssueFind{
+		WorkspaceID:   &workspaceID,
+		ProjectID:     &projectID,
+		StatusList:    []api.IssueStatus{api.IssueOpen},
+		AssigneeID:    &assigneeID,
+		EnvironmentID: &environmentID,
+		Label:         &label,
+	}, find)
+
+	// The empty filter leaves the find unchanged.
+	find = &api.IssueFind{WorkspaceID: &workspaceID}
+	require.NoError(t, applyIssueViewFilter(find, &api.IssueView{ID: 102, Filter: "{}"}))
+	assert.Equal(t, &api.IssueFind{WorkspaceID: &workspaceID}, find)
+
+	assert.Error(t, applyIssueViewFilter(find, &api.IssueView{ID: 103, Filter: "{"}))
+}
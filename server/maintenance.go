@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	defaultMaintenanceMessage = "Bytebase is under maintenance and in read-only mode, please try again later."
+)
+
+// maintenanceMiddleware rejects the mutating requests while the maintenance mode is enabled.
+// Signing in and out are still allowed, so that the admin can disable the maintenance mode.
+func maintenanceMiddleware(s *Server, next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !isMutatingMethod(c.Request().Method) {
+			return next(c)
+		}
+		if common.HasPrefixes(c.Path(), "/api/auth/login", "/api/auth/logout") {
+			return next(c)
+		}
+		if c.Path() == "/api/setting/:name" && c.Param("name") == string(api.SettingMaintenance) {
+			return next(c)
+		}
+
+		if maintenance := s.getMaintenance(); maintenance.Enabled {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, getMaintenanceMessage(maintenance))
+		}
+		return next(c)
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+func getMaintenanceMessage(maintenance api.MaintenanceSetting) string {
+	if maintenance.Message != "" {
+		return maintenance.Message
+	}
+	return defaultMaintenanceMessage
+}
+
+// getMaintenance returns the cached maintenance setting.
+func (s *Server) getMaintenance() api.MaintenanceSetting {
+	s.maintenanceMu.RLock()
+	defer s.maintenanceMu.RUnlock()
+	return s.maintenance
+}
+
+func (s *Server) setMaintenance(maintenance api.MaintenanceSetting) {
+	s.maintenanceMu.Lock()
+	defer s.maintenanceMu.Unlock()
+	s.maintenance = maintenance
+}
+
+// isInMaintenance returns whether the maintenance mode is enabled, the background runners skip their rounds if so.
+func (s *Server) isInMaintenance() bool {
+	return s.getMaintenance().Enabled
+}
+
+// loadMaintenance loads the maintenance setting from the store into the cache.
+func (s *Server) loadMaintenance(ctx context.Context) error {
+	settingName := api.SettingMaintenance
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return err
+	}
+	if len(settingList) == 0 {
+		return errors.Errorf("cannot find setting %v", settingName)
+	}
+	maintenance, err := parseMaintenanceSetting(settingList[0].Value)
+	if err != nil {
+		return err
+	}
+	s.setMaintenance(*maintenance)
+	return nil
+}
+
+func parseMaintenanceSetting(value string) (*api.MaintenanceSetting, error) {
+	maintenance := &api.MaintenanceSetting{}
+	if err := json.Unmarshal([]byte(value), maintenance); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal maintenance setting %q", value)
+	}
+	return maintenance, nil
+}
+
+// updateMaintenance applies the maintenance setting right away and records the change as an activity.
+func (s *Server) updateMaintenance(ctx context.Context, setting *api.Setting, maintenance *api.MaintenanceSetting) error {
+	oldMaintenance := s.getMaintenance()
+	s.setMaintenance(*maintenance)
+	if maintenance.Enabled {
+		log.Info("Maintenance mode enabled, the mutating requests are rejected and the background runners are paused")
+	} else if oldMaintenance.Enabled {
+		log.Info("Maintenance mode disabled")
+	}
+
+	bytes, err := json.Marshal(api.ActivitySettingMaintenanceUpdatePayload{
+		OldEnabled: oldMaintenance.Enabled,
+		NewEnabled: maintenance.Enabled,
+		OldMessage: oldMaintenance.Message,
+		NewMessage: maintenance.Message,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal maintenance activity payload")
+	}
+	comment := "Updated the maintenance message."
+	if maintenance.Enabled != oldMaintenance.Enabled {
+		if maintenance.Enabled {
+			comment = "Enabled the maintenance mode."
+		} else {
+			comment = "Disabled the maintenance mode."
+		}
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   setting.UpdaterID,
+		ContainerID: setting.ID,
+		Type:        api.ActivitySettingMaintenanceUpdate,
+		Level:       api.ActivityInfo,
+		Comment:     comment,
+		Payload:     string(bytes),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		return errors.Wrapf(err, "failed to create activity after updating setting %s", setting.Name)
+	}
+	return nil
+}
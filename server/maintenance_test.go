@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	tests := []struct {
+		method     string
+		path       string
+		param      string
+		wantPassed bool
+	}{
+		{method: http.MethodGet, path: "/api/issue", wantPassed: true},
+		{method: http.MethodPost, path: "/api/issue", wantPassed: false},
+		{method: http.MethodPatch, path: "/api/instance/:instanceID", param: "1", wantPassed: false},
+		{method: http.MethodPost, path: "/api/auth/login/:auth_provider", param: "BYTEBASE", wantPassed: true},
+		{method: http.MethodPatch, path: "/api/setting/:name", param: string(api.SettingMaintenance), wantPassed: true},
+		{method: http.MethodPatch, path: "/api/setting/:name", param: string(api.SettingBrandingLogo), wantPassed: false},
+	}
+
+	s := &Server{}
+	s.setMaintenance(api.MaintenanceSetting{Enabled: true, Message: "Upgrading"})
+	e := echo.New()
+	for _, test := range tests {
+		passed := false
+		handler := maintenanceMiddleware(s, func(c echo.Context) error {
+			passed = true
+			return nil
+		})
+		c := e.NewContext(httptest.NewRequest(test.method, "/", nil), httptest.NewRecorder())
+		c.SetPath(test.path)
+		if test.param != "" {
+			c.SetParamNames("name")
+			c.SetParamValues(test.param)
+		}
+		err := handler(c)
+		assert.Equal(t, test.wantPassed, passed, "%s %s", test.method, test.path)
+		if !test.wantPassed {
+			httpErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok)
+			assert.Equal(t, http.StatusServiceUnavailable, httpErr.Code)
+			assert.Equal(t, "Upgrading", httpErr.Message)
+		}
+	}
+
+	// Nothing is rejected once the maintenance mode is disabled.
+	s.setMaintenance(api.MaintenanceSetting{Enabled: false})
+	passed := false
+	handler := maintenanceMiddleware(s, func(c echo.Context) error {
+		passed = true
+		return nil
+	})
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	c.SetPath("/api/issue")
+	require.NoError(t, handler(c))
+	assert.True(t, passed)
+}
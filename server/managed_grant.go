@@ -0,0 +1,368 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+var (
+	// mysqlDatabasePrivilegeSet is the MySQL and TiDB privileges which can be granted on the database level.
+	mysqlDatabasePrivilegeSet = map[string]bool{
+		"ALTER":                   true,
+		"ALTER ROUTINE":           true,
+		"CREATE":                  true,
+		"CREATE ROUTINE":          true,
+		"CREATE TEMPORARY TABLES": true,
+		"CREATE VIEW":             true,
+		"DELETE":                  true,
+		"DROP":                    true,
+		"EVENT":                   true,
+		"EXECUTE":                 true,
+		"INDEX":                   true,
+		"INSERT":                  true,
+		"LOCK TABLES":             true,
+		"REFERENCES":              true,
+		"SELECT":                  true,
+		"SHOW VIEW":               true,
+		"TRIGGER":                 true,
+		"UPDATE":                  true,
+	}
+	// pgDatabasePrivilegeSet is the Postgres privileges which can be granted on the database level.
+	pgDatabasePrivilegeSet = map[string]bool{
+		"CONNECT":   true,
+		"CREATE":    true,
+		"TEMPORARY": true,
+	}
+)
+
+func (s *Server) registerManagedGrantRoutes(g *echo.Group) {
+	g.GET("/database/:id/managed-grant", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		managedGrantList, err := s.store.FindManagedGrant(ctx, &api.ManagedGrantFind{DatabaseID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch managed grant list for database ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, managedGrantList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal managed grant list response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Creates the managed grant, or replaces its privileges if the grantee is already managed.
+	g.POST("/database/:id/managed-grant", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+
+		upsert := &api.ManagedGrantUpsert{
+			UpdaterID:  c.Get(getPrincipalIDContextKey()).(int),
+			DatabaseID: id,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed upsert managed grant request").SetInternal(err)
+		}
+		if err := normalizeManagedGrant(database.Instance.Engine, upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid managed grant: %v", err))
+		}
+
+		managedGrant, err := s.store.UpsertManagedGrant(ctx, upsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to upsert managed grant %q for database ID: %d", upsert.Grantee, id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, managedGrant); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal managed grant response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Creates a data update issue granting the missing privileges and revoking the extra privileges of the managed grantees.
+	g.POST("/database/:id/managed-grant/remediation", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+		if database.Project.TenantMode == api.TenantModeTenant {
+			return echo.NewHTTPError(http.StatusBadRequest, "Remediating the grant drift of the database in tenant mode project is not supported yet")
+		}
+
+		managedGrantList, err := s.store.FindManagedGrant(ctx, &api.ManagedGrantFind{DatabaseID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch managed grant list for database ID: %d", id)).SetInternal(err)
+		}
+		if len(managedGrantList) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q has no managed grant", database.Name))
+		}
+		driftList, err := s.getManagedGrantDriftList(ctx, database.Instance, database.Name, managedGrantList)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to sync grants of database %q", database.Name)).SetInternal(err)
+		}
+		if len(driftList) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q has no grant drift", database.Name))
+		}
+
+		createContext, err := json.Marshal(&api.UpdateSchemaContext{
+			MigrationType: db.Data,
+			DetailList: []*api.UpdateSchemaDetail{
+				{
+					DatabaseID: database.ID,
+					Statement:  getManagedGrantRemediationStatement(database.Instance.Engine, database.Name, driftList),
+				},
+			},
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal update schema context").SetInternal(err)
+		}
+		issueCreate := &api.IssueCreate{
+			ProjectID:     database.ProjectID,
+			Name:          fmt.Sprintf("Remediate grant drift of database %q", database.Name),
+			Type:          api.IssueDatabaseDataUpdate,
+			Description:   "Grant the missing privileges and revoke the extra privileges of the managed grantees.",
+			AssigneeID:    api.SystemBotID,
+			CreateContext: string(createContext),
+			WorkspaceID:   c.Get(getWorkspaceIDContextKey()).(int),
+		}
+		issue, err := s.createIssue(ctx, issueCreate, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create grant remediation issue for database %q", database.Name)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, issue); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal grant remediation issue response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/database/:id/managed-grant/:grantID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		grantID, err := strconv.Atoi(c.Param("grantID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Grant ID is not a number: %s", c.Param("grantID"))).SetInternal(err)
+		}
+
+		if err := s.store.DeleteManagedGrant(ctx, &api.ManagedGrantDelete{
+			ID:         grantID,
+			DatabaseID: id,
+		}); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Managed grant ID %d not found in database ID: %d", grantID, id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete managed grant ID %d for database ID: %d", grantID, id)).SetInternal(err)
+		}
+
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// checkManagedGrantDrift compares the managed grants of the database with the actual grants,
+// and reports the difference as the grant drift anomaly, which is archived once the grants match again.
+func (s *Server) checkManagedGrantDrift(ctx context.Context, instance *api.Instance, database *api.Database) error {
+	managedGrantList, err := s.store.FindManagedGrant(ctx, &api.ManagedGrantFind{DatabaseID: &database.ID})
+	if err != nil {
+		return err
+	}
+	var driftList []*api.ManagedGrantDrift
+	if len(managedGrantList) > 0 {
+		driftList, err = s.getManagedGrantDriftList(ctx, instance, database.Name, managedGrantList)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(driftList) == 0 {
+		if err := s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			DatabaseID: &database.ID,
+			Type:       api.AnomalyDatabaseGrantDrift,
+		}); err != nil && common.ErrorCode(err) != common.NotFound {
+			return err
+		}
+		return nil
+	}
+
+	payload, err := json.Marshal(api.AnomalyDatabaseGrantDriftPayload{
+		DriftList: driftList,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal grant drift anomaly payload")
+	}
+	return s.upsertDatabaseAnomaly(ctx, database, &api.AnomalyUpsert{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		DatabaseID: &database.ID,
+		Type:       api.AnomalyDatabaseGrantDrift,
+		Payload:    string(payload),
+	})
+}
+
+// getManagedGrantDriftList syncs the actual grants of the database with the admin data source, because the read-only data source
+// may not be privileged to see the grants of the other users, and compares them with the managed grants.
+func (s *Server) getManagedGrantDriftList(ctx context.Context, instance *api.Instance, databaseName string, managedGrantList []*api.ManagedGrant) ([]*api.ManagedGrantDrift, error) {
+	driver, err := s.getAdminDatabaseDriver(ctx, instance, "")
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+
+	grantSyncer, ok := unwrapDriver(driver).(db.GrantSyncer)
+	if !ok {
+		return nil, errors.Errorf("syncing grants is not supported for %s", instance.Engine)
+	}
+	grantList, err := grantSyncer.SyncDBGrant(ctx, databaseName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to sync grants of database %q", databaseName)
+	}
+	return diffManagedGrant(managedGrantList, grantList), nil
+}
+
+// normalizeManagedGrant checks the grantee and the privileges are supported by the engine.
+// The privileges are upper cased, deduplicated and sorted, and the host of the MySQL and TiDB grantee defaults to "%",
+// so that they compare with the synced grants as is.
+func normalizeManagedGrant(dbType db.Type, upsert *api.ManagedGrantUpsert) error {
+	var privilegeSet map[string]bool
+	switch dbType {
+	case db.MySQL, db.TiDB:
+		privilegeSet = mysqlDatabasePrivilegeSet
+	case db.Postgres:
+		privilegeSet = pgDatabasePrivilegeSet
+	default:
+		return errors.Errorf("managed grants are only supported for %s, %s and %s", db.MySQL, db.TiDB, db.Postgres)
+	}
+
+	if err := checkGrantee(dbType, upsert.Grantee); err != nil {
+		return err
+	}
+	if dbType != db.Postgres {
+		user, host := splitGrantee(upsert.Grantee)
+		upsert.Grantee = fmt.Sprintf("%s@%s", user, host)
+	}
+
+	seen := make(map[string]bool)
+	var privilegeList []string
+	for _, privilege := range upsert.PrivilegeList {
+		privilege = strings.ToUpper(strings.Join(strings.Fields(privilege), " "))
+		if privilege == "TEMP" && dbType == db.Postgres {
+			privilege = "TEMPORARY"
+		}
+		if !privilegeSet[privilege] {
+			return errors.Errorf("privilege %q can not be granted on the %s database", privilege, dbType)
+		}
+		if seen[privilege] {
+			continue
+		}
+		seen[privilege] = true
+		privilegeList = append(privilegeList, privilege)
+	}
+	sort.Strings(privilegeList)
+	upsert.PrivilegeList = privilegeList
+	return nil
+}
+
+// diffManagedGrant returns the drifts of the managed grantees. The grants of the unmanaged grantees are ignored.
+func diffManagedGrant(managedGrantList []*api.ManagedGrant, grantList []db.Grant) []*api.ManagedGrantDrift {
+	grantedMap := make(map[string]map[string]bool)
+	for _, grant := range grantList {
+		if _, ok := grantedMap[grant.Grantee]; !ok {
+			grantedMap[grant.Grantee] = make(map[string]bool)
+		}
+		grantedMap[grant.Grantee][grant.Privilege] = true
+	}
+
+	var driftList []*api.ManagedGrantDrift
+	for _, managedGrant := range managedGrantList {
+		granted := grantedMap[managedGrant.Grantee]
+		managed := make(map[string]bool)
+		drift := &api.ManagedGrantDrift{
+			Grantee: managedGrant.Grantee,
+		}
+		for _, privilege := range managedGrant.PrivilegeList {
+			managed[privilege] = true
+			if !granted[privilege] {
+				drift.MissingPrivilegeList = append(drift.MissingPrivilegeList, privilege)
+			}
+		}
+		for privilege := range granted {
+			if !managed[privilege] {
+				drift.ExtraPrivilegeList = append(drift.ExtraPrivilegeList, privilege)
+			}
+		}
+		if len(drift.MissingPrivilegeList) == 0 && len(drift.ExtraPrivilegeList) == 0 {
+			continue
+		}
+		sort.Strings(drift.MissingPrivilegeList)
+		sort.Strings(drift.ExtraPrivilegeList)
+		driftList = append(driftList, drift)
+	}
+	return driftList
+}
+
+// getManagedGrantRemediationStatement returns the statement granting the missing privileges and revoking the extra privileges.
+func getManagedGrantRemediationStatement(dbType db.Type, databaseName string, driftList []*api.ManagedGrantDrift) string {
+	var stmtList []string
+	for _, drift := range driftList {
+		missing, extra := strings.Join(drift.MissingPrivilegeList, ", "), strings.Join(drift.ExtraPrivilegeList, ", ")
+		switch dbType {
+		case db.MySQL, db.TiDB:
+			user, host := splitGrantee(drift.Grantee)
+			if missing != "" {
+				stmtList = append(stmtList, fmt.Sprintf("GRANT %s ON `%s`.* TO '%s'@'%s';", missing, databaseName, user, host))
+			}
+			if extra != "" {
+				stmtList = append(stmtList, fmt.Sprintf("REVOKE %s ON `%s`.* FROM '%s'@'%s';", extra, databaseName, user, host))
+			}
+		case db.Postgres:
+			if missing != "" {
+				stmtList = append(stmtList, fmt.Sprintf("GRANT %s ON DATABASE \"%s\" TO \"%s\";", missing, databaseName, drift.Grantee))
+			}
+			if extra != "" {
+				stmtList = append(stmtList, fmt.Sprintf("REVOKE %s ON DATABASE \"%s\" FROM \"%s\";", extra, databaseName, drift.Grantee))
+			}
+		}
+	}
+	return strings.Join(stmtList, "\n")
+}
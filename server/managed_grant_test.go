@@ -0,0 +1,96 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestNormalizeManagedGrant(t *testing.T) {
+	tests := []struct {
+		dbType  db.Type
+		upsert  *api.ManagedGrantUpsert
+		want    *api.ManagedGrantUpsert
+		wantErr bool
+	}{
+		{
+			dbType: db.MySQL,
+			upsert: &api.ManagedGrantUpsert{Grantee: "app", PrivilegeList: []string{"update", "SELECT", "show  view", "select"}},
+			want:   &api.ManagedGrantUpsert{Grantee: "app@%", PrivilegeList: []string{"SELECT", "SHOW VIEW", "UPDATE"}},
+		},
+		{
+			dbType: db.Postgres,
+			upsert: &api.ManagedGrantUpsert{Grantee: "app", PrivilegeList: []string{"connect", "temp"}},
+			want:   &api.ManagedGrantUpsert{Grantee: "app", PrivilegeList: []string{"CONNECT", "TEMPORARY"}},
+		},
+		{
+			dbType:  db.MySQL,
+			upsert:  &api.ManagedGrantUpsert{Grantee: "app@%", PrivilegeList: []string{"ALL PRIVILEGES"}},
+			wantErr: true,
+		},
+		{
+			dbType:  db.Postgres,
+			upsert:  &api.ManagedGrantUpsert{Grantee: "app", PrivilegeList: []string{"SELECT"}},
+			wantErr: true,
+		},
+		{
+			dbType:  db.TiDB,
+			upsert:  &api.ManagedGrantUpsert{Grantee: "app'@'%", PrivilegeList: []string{"SELECT"}},
+			wantErr: true,
+		},
+		{
+			dbType:  db.ClickHouse,
+			upsert:  &api.ManagedGrantUpsert{Grantee: "app", PrivilegeList: []string{"SELECT"}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := normalizeManagedGrant(test.dbType, test.upsert)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, test.want, test.upsert)
+	}
+}
+
+func TestManagedGrantRemediation(t *testing.T) {
+	managedGrantList := []*api.ManagedGrant{
+		{Grantee: "app@%", PrivilegeList: []string{"DELETE", "INSERT", "SELECT", "UPDATE"}},
+		{Grantee: "reader@10.0.0.%", PrivilegeList: []string{"SELECT"}},
+		{Grantee: "report@%", PrivilegeList: []string{"SELECT", "SHOW VIEW"}},
+	}
+	grantList := []db.Grant{
+		{Grantee: "app@%", Privilege: "DROP"},
+		{Grantee: "app@%", Privilege: "INSERT"},
+		{Grantee: "app@%", Privilege: "SELECT"},
+		{Grantee: "reader@10.0.0.%", Privilege: "SELECT"},
+		{Grantee: "admin@%", Privilege: "ALTER"},
+	}
+
+	driftList := diffManagedGrant(managedGrantList, grantList)
+	assert.Equal(t, []*api.ManagedGrantDrift{
+		{Grantee: "app@%", MissingPrivilegeList: []string{"DELETE", "UPDATE"}, ExtraPrivilegeList: []string{"DROP"}},
+		{Grantee: "report@%", MissingPrivilegeList: []string{"SELECT", "SHOW VIEW"}},
+	}, driftList)
+
+	assert.Equal(t,
+		"GRANT DELETE, UPDATE ON `db1`.* TO 'app'@'%';\n"+
+			"REVOKE DROP ON `db1`.* FROM 'app'@'%';\n"+
+			"GRANT SELECT, SHOW VIEW ON `db1`.* TO 'report'@'%';",
+		getManagedGrantRemediationStatement(db.MySQL, "db1", driftList))
+
+	pgDriftList := []*api.ManagedGrantDrift{
+		{Grantee: "app", MissingPrivilegeList: []string{"CONNECT"}, ExtraPrivilegeList: []string{"CREATE", "TEMPORARY"}},
+	}
+	assert.Equal(t,
+		"GRANT CONNECT ON DATABASE \"db1\" TO \"app\";\n"+
+			"REVOKE CREATE, TEMPORARY ON DATABASE \"db1\" FROM \"app\";",
+		getManagedGrantRemediationStatement(db.Postgres, "db1", pgDriftList))
+}
@@ -24,6 +24,11 @@ func (s *Server) registerMemberRoutes(g *echo.Group) {
 		}
 
 		memberCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		memberCreate.WorkspaceID = c.Get(getWorkspaceIDContextKey()).(int)
+
+		if err := s.seatCountGuard(ctx); err != nil {
+			return err
+		}
 
 		member, err := s.store.CreateMember(ctx, memberCreate)
 		if err != nil {
@@ -75,7 +80,10 @@ func (s *Server) registerMemberRoutes(g *echo.Group) {
 
 	g.GET("/member", func(c echo.Context) error {
 		ctx := c.Request().Context()
-		memberFind := &api.MemberFind{}
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		memberFind := &api.MemberFind{
+			WorkspaceID: &workspaceID,
+		}
 		memberList, err := s.store.FindMember(ctx, memberFind)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch member list").SetInternal(err)
@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 	enterpriseAPI "github.com/bytebase/bytebase/enterprise/api"
 	"github.com/bytebase/bytebase/plugin/metric"
+	"github.com/bytebase/bytebase/plugin/metric/endpoint"
 	"github.com/bytebase/bytebase/plugin/metric/segment"
 	"github.com/bytebase/bytebase/store"
 	"github.com/pkg/errors"
@@ -27,9 +29,13 @@ const (
 	identifyTraitForVersion = "version"
 	// principalIDForFirstUser is the principal id for the first user in workspace.
 	principalIDForFirstUser = 101
+	// usageMetricRetentionPeriod is how long the usage metrics are kept locally.
+	usageMetricRetentionPeriod = time.Duration(90*24) * time.Hour
 )
 
 // MetricReporter is the metric reporter.
+// The collected metrics are always persisted locally for the usage dashboard,
+// and sent to the telemetry and the collector endpoint unless the workspace opts out.
 type MetricReporter struct {
 	// subscription is the pointer to the server.subscription.
 	// the subscription can be updated by users so we need the pointer to get the latest value.
@@ -37,14 +43,27 @@ type MetricReporter struct {
 	// Version is the bytebase's version
 	version     string
 	workspaceID string
-	reporter    metric.Reporter
-	collectors  map[string]metric.Collector
-	store       *store.Store
+	// reporter is the telemetry reporter, nil if the telemetry is disabled by the profile.
+	reporter   metric.Reporter
+	collectors map[string]metric.Collector
+	store      *store.Store
+
+	// mu protects the fields loaded from the workspace settings below.
+	mu sync.RWMutex
+	// optOut is true if the workspace opts out of sending the metrics outside the workspace.
+	optOut bool
+	// endpointURL is the collector endpoint, empty if not configured.
+	endpointURL string
+	// endpointReporter is the reporter for the collector endpoint, nil if not configured.
+	endpointReporter metric.Reporter
 }
 
 // NewMetricReporter creates a new metric scheduler.
 func NewMetricReporter(server *Server, workspaceID string) *MetricReporter {
-	r := segment.NewReporter(server.profile.MetricConnectionKey, workspaceID)
+	var r metric.Reporter
+	if server.profile.Mode == common.ReleaseModeProd && !server.profile.Demo && !server.profile.DisableMetric {
+		r = segment.NewReporter(server.profile.MetricConnectionKey, workspaceID)
+	}
 
 	return &MetricReporter{
 		subscription: &server.subscription,
@@ -79,8 +98,10 @@ func (m *MetricReporter) Run(ctx context.Context, wg *sync.WaitGroup) {
 				}()
 
 				ctx := context.Background()
+				m.loadSetting(ctx)
 				// identify will be triggered in every schedule loop so that we can update the latest workspace profile such as subscription plan.
 				m.identify(ctx)
+				var collectedList []*metric.Metric
 				for name, collector := range m.collectors {
 					log.Debug("Run metric collector", zap.String("collector", name))
 
@@ -97,7 +118,9 @@ func (m *MetricReporter) Run(ctx context.Context, wg *sync.WaitGroup) {
 					for _, metric := range metricList {
 						m.report(metric)
 					}
+					collectedList = append(collectedList, metricList...)
 				}
+				m.persist(ctx, collectedList)
 			}()
 		case <-ctx.Done(): // if cancel() execute
 			return
@@ -107,7 +130,15 @@ func (m *MetricReporter) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 // Close will close the metric reporter.
 func (m *MetricReporter) Close() {
-	m.reporter.Close()
+	if m.reporter != nil {
+		m.reporter.Close()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.endpointReporter != nil {
+		m.endpointReporter.Close()
+		m.endpointReporter = nil
+	}
 }
 
 // Register will register a metric collector.
@@ -136,7 +167,7 @@ func (m *MetricReporter) identify(ctx context.Context) {
 		email = principal.Email
 	}
 
-	if err := m.reporter.Identify(&metric.Identifier{
+	identifier := &metric.Identifier{
 		ID:    m.workspaceID,
 		Email: email,
 		Name:  orgName,
@@ -145,17 +176,98 @@ func (m *MetricReporter) identify(ctx context.Context) {
 			identifyTraitForVersion: m.version,
 			identifyTraitForOrgID:   orgID,
 		},
-	}); err != nil {
-		log.Debug("reporter identify failed", zap.Error(err))
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, reporter := range m.getReporterList() {
+		if err := reporter.Identify(identifier); err != nil {
+			log.Debug("reporter identify failed", zap.Error(err))
+		}
 	}
 }
 
 func (m *MetricReporter) report(metric *metric.Metric) {
-	if err := m.reporter.Report(metric); err != nil {
-		log.Error(
-			"Failed to report metric",
-			zap.String("metric", string(metric.Name)),
-			zap.Error(err),
-		)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, reporter := range m.getReporterList() {
+		if err := reporter.Report(metric); err != nil {
+			log.Error(
+				"Failed to report metric",
+				zap.String("metric", string(metric.Name)),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// getReporterList returns the reporters to send the metrics, and nothing if the workspace opts out.
+// The caller must hold the read lock.
+func (m *MetricReporter) getReporterList() []metric.Reporter {
+	if m.optOut {
+		return nil
+	}
+	var reporterList []metric.Reporter
+	if m.reporter != nil {
+		reporterList = append(reporterList, m.reporter)
+	}
+	if m.endpointReporter != nil {
+		reporterList = append(reporterList, m.endpointReporter)
+	}
+	return reporterList
+}
+
+// loadSetting loads the opt-out flag and the collector endpoint from the workspace settings.
+// It's called in every schedule loop and whenever the settings are updated.
+func (m *MetricReporter) loadSetting(ctx context.Context) {
+	optOut := false
+	endpointURL := ""
+	settingList, err := m.store.FindSetting(ctx, &api.SettingFind{})
+	if err != nil {
+		log.Error("Failed to find the metric settings", zap.Error(err))
+		return
+	}
+	for _, setting := range settingList {
+		switch setting.Name {
+		case api.SettingWorkspaceMetricOptOut:
+			optOut = setting.Value == "true"
+		case api.SettingWorkspaceMetricCollectorURL:
+			endpointURL = setting.Value
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.optOut = optOut
+	if endpointURL == m.endpointURL {
+		return
+	}
+	if m.endpointReporter != nil {
+		m.endpointReporter.Close()
+		m.endpointReporter = nil
+	}
+	m.endpointURL = endpointURL
+	if endpointURL != "" {
+		m.endpointReporter = endpoint.NewReporter(endpointURL, m.workspaceID)
+	}
+}
+
+// persist saves the metrics collected in the round locally for the usage dashboard, and purges the expired ones.
+func (m *MetricReporter) persist(ctx context.Context, metricList []*metric.Metric) {
+	now := time.Now()
+	var createList []*api.UsageMetricCreate
+	for _, metric := range metricList {
+		createList = append(createList, &api.UsageMetricCreate{
+			CreatedTs: now.Unix(),
+			Name:      string(metric.Name),
+			Value:     metric.Value,
+			Labels:    metric.Labels,
+		})
+	}
+	if err := m.store.CreateUsageMetricList(ctx, createList); err != nil {
+		log.Error("Failed to persist the usage metrics", zap.Error(err))
+		return
+	}
+	if err := m.store.DeleteUsageMetricBefore(ctx, now.Add(-usageMetricRetentionPeriod).Unix()); err != nil {
+		log.Error("Failed to purge the expired usage metrics", zap.Error(err))
 	}
 }
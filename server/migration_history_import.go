@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+const (
+	defaultFlywayHistoryTable    = "flyway_schema_history"
+	defaultLiquibaseHistoryTable = "DATABASECHANGELOG"
+)
+
+// importMigrationHistory reads the migration history recorded by Flyway or Liquibase in the database,
+// and records each applied migration as a baseline in the migration history, so that the database can be migrated by Bytebase afterwards.
+// Importing again skips the migrations imported before.
+func (s *Server) importMigrationHistory(ctx context.Context, database *api.Database, historyImport *api.MigrationHistoryImport) ([]*db.MigrationHistory, error) {
+	creator, err := s.store.GetPrincipalByID(ctx, historyImport.CreatorID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch principal ID: %v", historyImport.CreatorID)).SetInternal(err)
+	}
+	if creator == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Principal ID not found: %d", historyImport.CreatorID))
+	}
+
+	driver, err := s.getAdminDatabaseDriver(ctx, database.Instance, database.Name)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to connect database %q", database.Name)).SetInternal(err)
+	}
+	defer driver.Close(ctx)
+	setup, err := driver.NeedsSetupMigration(ctx)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check migration setup for instance %q", database.Instance.Name)).SetInternal(err)
+	}
+	if setup {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Missing migration schema for instance %q", database.Instance.Name))
+	}
+	sqldb, err := driver.GetDBConnection(ctx, database.Name)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to connect database %q", database.Name)).SetInternal(err)
+	}
+
+	var toolName string
+	var migrationList []*util.ExternalMigration
+	switch historyImport.Format {
+	case api.MigrationHistoryImportFlyway:
+		toolName = "Flyway"
+		if historyImport.Table == "" {
+			historyImport.Table = defaultFlywayHistoryTable
+		}
+		migrationList, err = util.FindFlywayMigrationList(ctx, sqldb, database.Instance.Engine, historyImport.Table)
+	case api.MigrationHistoryImportLiquibase:
+		toolName = "Liquibase"
+		if historyImport.Table == "" {
+			historyImport.Table = defaultLiquibaseHistoryTable
+		}
+		migrationList, err = util.FindLiquibaseMigrationList(ctx, sqldb, database.Instance.Engine, historyImport.Table)
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid import format %q", historyImport.Format))
+	}
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to read %s history table %q in database %q", toolName, historyImport.Table, database.Name)).SetInternal(err)
+	}
+
+	var historyList []*db.MigrationHistory
+	var prevAppliedTs int64
+	for _, migration := range migrationList {
+		// The versions must be increasing to be applied in order, so the applied time never goes backwards.
+		if migration.AppliedTs < prevAppliedTs {
+			migration.AppliedTs = prevAppliedTs
+		}
+		prevAppliedTs = migration.AppliedTs
+
+		m := &db.MigrationInfo{
+			ReleaseVersion: s.profile.Version,
+			Version:        getImportedMigrationVersion(migration),
+			Namespace:      database.Name,
+			Database:       database.Name,
+			Environment:    database.Instance.Environment.Name,
+			Source:         db.LIBRARY,
+			Type:           db.Baseline,
+			Description:    getImportedMigrationDescription(toolName, migration),
+			Creator:        creator.Name,
+		}
+		migrationID, _, err := driver.ExecuteMigration(ctx, m, "")
+		if err != nil {
+			if common.ErrorCode(err) == common.MigrationOutOfOrder {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot import %s version %s since database %q has applied later migrations", toolName, migration.Version, database.Name)).SetInternal(err)
+			}
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to import %s version %s for database %q", toolName, migration.Version, database.Name)).SetInternal(err)
+		}
+		id := int(migrationID)
+		list, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{ID: &id})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch migration history list").SetInternal(err)
+		}
+		historyList = append(historyList, list...)
+	}
+	return historyList, nil
+}
+
+// getImportedMigrationVersion returns the version of the imported migration.
+// The version is based on the applied time like the default migration version, so that the later migrations
+// applied by Bytebase continue the version ordering. The rank tells apart the migrations applied in the same second.
+func getImportedMigrationVersion(migration *util.ExternalMigration) string {
+	return fmt.Sprintf("%s.%06d", time.Unix(migration.AppliedTs, 0).Format("20060102150405"), migration.Rank)
+}
+
+func getImportedMigrationDescription(toolName string, migration *util.ExternalMigration) string {
+	description := fmt.Sprintf("Imported %s version %s from %s", toolName, migration.Version, migration.Script)
+	if migration.Creator != "" {
+		description += fmt.Sprintf(" applied by %s", migration.Creator)
+	}
+	if migration.Description != "" {
+		description += fmt.Sprintf(": %s", migration.Description)
+	}
+	return description
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+func TestGetImportedMigrationVersion(t *testing.T) {
+	appliedTs := time.Date(2022, 9, 1, 12, 0, 0, 0, time.Local).Unix()
+	first := getImportedMigrationVersion(&util.ExternalMigration{Rank: 9, AppliedTs: appliedTs})
+	second := getImportedMigrationVersion(&util.ExternalMigration{Rank: 10, AppliedTs: appliedTs})
+	assert.Equal(t, "20220901120000.000009", first)
+	assert.Equal(t, "20220901120000.000010", second)
+	// The migrations applied in the same second are ordered by rank.
+	assert.Less(t, first, second)
+	// The migrations applied by Bytebase afterwards are ordered after the imported ones.
+	assert.Less(t, second, common.DefaultMigrationVersion())
+}
+
+func TestGetImportedMigrationDescription(t *testing.T) {
+	assert.Equal(t,
+		"Imported Flyway version 1.1 from V1_1__create_user.sql applied by alice: create user",
+		getImportedMigrationDescription("Flyway", &util.ExternalMigration{Version: "1.1", Script: "V1_1__create_user.sql", Creator: "alice", Description: "create user"}),
+	)
+	assert.Equal(t,
+		"Imported Liquibase version 1 from changelog.xml",
+		getImportedMigrationDescription("Liquibase", &util.ExternalMigration{Version: "1", Script: "changelog.xml"}),
+	)
+}
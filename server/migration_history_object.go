@@ -0,0 +1,281 @@
+package server
+
+import (
+	"context"
+	"sort"
+
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+// migrationObject is a table or column referenced by a migration statement.
+// The schema is empty if the table isn't qualified with a schema, and the column is empty if the whole table is referenced.
+type migrationObject struct {
+	schema string
+	table  string
+	column string
+}
+
+// recordMigrationHistoryObjectList extracts the tables and columns referenced by the statement of the migration history,
+// and stores them for looking up the change history of a column.
+// The migration has been executed, so the failure is logged instead of failing the task.
+func (s *Server) recordMigrationHistoryObjectList(ctx context.Context, task *api.Task, migrationID int, statement string) {
+	objectList, err := extractMigrationObjectList(task.Instance.Engine, statement)
+	if err != nil {
+		log.Warn("Failed to extract the objects referenced by the migration statement",
+			zap.Int("task_id", task.ID),
+			zap.Int("migration_id", migrationID),
+			zap.Error(err))
+		return
+	}
+	if len(objectList) == 0 {
+		return
+	}
+
+	var createList []*api.MigrationHistoryObjectCreate
+	for _, object := range objectList {
+		createList = append(createList, &api.MigrationHistoryObjectCreate{
+			DatabaseID:         task.Database.ID,
+			MigrationHistoryID: migrationID,
+			SchemaName:         object.schema,
+			TableName:          object.table,
+			ColumnName:         object.column,
+		})
+	}
+	if err := s.store.CreateMigrationHistoryObjectList(ctx, createList); err != nil {
+		log.Error("Failed to create the objects referenced by the migration statement",
+			zap.Int("task_id", task.ID),
+			zap.Int("migration_id", migrationID),
+			zap.Error(err))
+	}
+}
+
+// extractMigrationObjectList extracts the tables and columns referenced by the statement.
+// Returns nil for the engines without parser support. The result is deduplicated and sorted.
+func extractMigrationObjectList(engine db.Type, statement string) ([]*migrationObject, error) {
+	var objectList []*migrationObject
+	switch engine {
+	case db.MySQL, db.TiDB:
+		p := tidbparser.New()
+		// To support MySQL8 window function syntax.
+		// See https://github.com/bytebase/bytebase/issues/175.
+		p.EnableWindowFunc(true)
+		nodeList, _, err := p.Parse(statement, "", "")
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodeList {
+			objectList = append(objectList, extractMySQLMigrationObjectList(node)...)
+		}
+	case db.Postgres:
+		nodeList, err := parser.Parse(parser.Postgres, parser.Context{}, statement)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodeList {
+			objectList = append(objectList, extractPostgresMigrationObjectList(node)...)
+		}
+	default:
+		return nil, nil
+	}
+
+	visited := make(map[migrationObject]bool)
+	var result []*migrationObject
+	for _, object := range objectList {
+		if object.table == "" || visited[*object] {
+			continue
+		}
+		visited[*object] = true
+		result = append(result, object)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].schema != result[j].schema {
+			return result[i].schema < result[j].schema
+		}
+		if result[i].table != result[j].table {
+			return result[i].table < result[j].table
+		}
+		return result[i].column < result[j].column
+	})
+	return result, nil
+}
+
+func extractMySQLMigrationObjectList(node tidbast.StmtNode) []*migrationObject {
+	var objectList []*migrationObject
+	switch node := node.(type) {
+	case *tidbast.CreateTableStmt:
+		schema, table := node.Table.Schema.O, node.Table.Name.O
+		objectList = append(objectList, &migrationObject{schema: schema, table: table})
+		for _, column := range node.Cols {
+			objectList = append(objectList, &migrationObject{schema: schema, table: table, column: column.Name.Name.O})
+		}
+	case *tidbast.AlterTableStmt:
+		schema, table := node.Table.Schema.O, node.Table.Name.O
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			case tidbast.AlterTableAddColumns, tidbast.AlterTableModifyColumn, tidbast.AlterTableAlterColumn:
+				for _, column := range spec.NewColumns {
+					objectList = append(objectList, &migrationObject{schema: schema, table: table, column: column.Name.Name.O})
+				}
+			case tidbast.AlterTableChangeColumn:
+				objectList = append(objectList, &migrationObject{schema: schema, table: table, column: spec.OldColumnName.Name.O})
+				for _, column := range spec.NewColumns {
+					objectList = append(objectList, &migrationObject{schema: schema, table: table, column: column.Name.Name.O})
+				}
+			case tidbast.AlterTableDropColumn:
+				objectList = append(objectList, &migrationObject{schema: schema, table: table, column: spec.OldColumnName.Name.O})
+			case tidbast.AlterTableRenameColumn:
+				objectList = append(objectList,
+					&migrationObject{schema: schema, table: table, column: spec.OldColumnName.Name.O},
+					&migrationObject{schema: schema, table: table, column: spec.NewColumnName.Name.O},
+				)
+			case tidbast.AlterTableRenameTable:
+				objectList = append(objectList,
+					&migrationObject{schema: schema, table: table},
+					&migrationObject{schema: spec.NewTable.Schema.O, table: spec.NewTable.Name.O},
+				)
+			}
+		}
+	case *tidbast.DropTableStmt:
+		for _, table := range node.Tables {
+			objectList = append(objectList, &migrationObject{schema: table.Schema.O, table: table.Name.O})
+		}
+	case *tidbast.RenameTableStmt:
+		for _, tableToTable := range node.TableToTables {
+			objectList = append(objectList,
+				&migrationObject{schema: tableToTable.OldTable.Schema.O, table: tableToTable.OldTable.Name.O},
+				&migrationObject{schema: tableToTable.NewTable.Schema.O, table: tableToTable.NewTable.Name.O},
+			)
+		}
+	case *tidbast.InsertStmt:
+		tableList, _ := extractMySQLTableList(node.Table)
+		if len(tableList) != 1 {
+			break
+		}
+		table := tableList[0]
+		columnList := node.Columns
+		for _, assignment := range node.Setlist {
+			columnList = append(columnList, assignment.Column)
+		}
+		if len(columnList) == 0 {
+			// INSERT INTO t VALUES (...) writes all columns.
+			objectList = append(objectList, table)
+		}
+		for _, column := range columnList {
+			objectList = append(objectList, &migrationObject{schema: table.schema, table: table.table, column: column.Name.O})
+		}
+	case *tidbast.UpdateStmt:
+		tableList, tableByAlias := extractMySQLTableList(node.TableRefs)
+		for _, assignment := range node.List {
+			var table *migrationObject
+			if v := assignment.Column.Table.O; v != "" {
+				table = tableByAlias[v]
+			} else if len(tableList) == 1 {
+				table = tableList[0]
+			}
+			if table == nil {
+				// Cannot tell which table the column belongs to, so the whole tables are referenced.
+				objectList = append(objectList, tableList...)
+				continue
+			}
+			objectList = append(objectList, &migrationObject{schema: table.schema, table: table.table, column: assignment.Column.Name.O})
+		}
+	case *tidbast.DeleteStmt:
+		tableList, _ := extractMySQLTableList(node.TableRefs)
+		objectList = append(objectList, tableList...)
+	}
+	return objectList
+}
+
+// extractMySQLTableList returns the tables in the table references and the map from the table alias to the table.
+func extractMySQLTableList(tableRefs *tidbast.TableRefsClause) ([]*migrationObject, map[string]*migrationObject) {
+	var tableList []*migrationObject
+	tableByAlias := make(map[string]*migrationObject)
+	if tableRefs == nil || tableRefs.TableRefs == nil {
+		return tableList, tableByAlias
+	}
+	var visit func(node tidbast.ResultSetNode)
+	visit = func(node tidbast.ResultSetNode) {
+		switch node := node.(type) {
+		case *tidbast.Join:
+			visit(node.Left)
+			visit(node.Right)
+		case *tidbast.TableSource:
+			if tableName, ok := node.Source.(*tidbast.TableName); ok {
+				table := &migrationObject{schema: tableName.Schema.O, table: tableName.Name.O}
+				tableList = append(tableList, table)
+				tableByAlias[tableName.Name.O] = table
+				if node.AsName.O != "" {
+					tableByAlias[node.AsName.O] = table
+				}
+			}
+		}
+	}
+	visit(tableRefs.TableRefs)
+	return tableList, tableByAlias
+}
+
+func extractPostgresMigrationObjectList(node ast.Node) []*migrationObject {
+	var objectList []*migrationObject
+	switch node := node.(type) {
+	case *ast.CreateTableStmt:
+		schema, table := node.Name.Schema, node.Name.Name
+		objectList = append(objectList, &migrationObject{schema: schema, table: table})
+		for _, column := range node.ColumnList {
+			objectList = append(objectList, &migrationObject{schema: schema, table: table, column: column.ColumnName})
+		}
+	case *ast.AlterTableStmt:
+		schema, table := node.Table.Schema, node.Table.Name
+		for _, item := range node.AlterItemList {
+			switch item := item.(type) {
+			case *ast.AddColumnListStmt:
+				for _, column := range item.ColumnList {
+					objectList = append(objectList, &migrationObject{schema: schema, table: table, column: column.ColumnName})
+				}
+			case *ast.DropColumnStmt:
+				objectList = append(objectList, &migrationObject{schema: schema, table: table, column: item.ColumnName})
+			case *ast.RenameColumnStmt:
+				objectList = append(objectList,
+					&migrationObject{schema: schema, table: table, column: item.ColumnName},
+					&migrationObject{schema: schema, table: table, column: item.NewName},
+				)
+			case *ast.AlterColumnTypeStmt:
+				objectList = append(objectList, &migrationObject{schema: schema, table: table, column: item.ColumnName})
+			case *ast.SetNotNullStmt:
+				objectList = append(objectList, &migrationObject{schema: schema, table: table, column: item.ColumnName})
+			case *ast.DropNotNullStmt:
+				objectList = append(objectList, &migrationObject{schema: schema, table: table, column: item.ColumnName})
+			case *ast.RenameTableStmt:
+				// The renamed table stays in the same schema.
+				objectList = append(objectList,
+					&migrationObject{schema: schema, table: table},
+					&migrationObject{schema: schema, table: item.NewName},
+				)
+			case *ast.SetSchemaStmt:
+				objectList = append(objectList,
+					&migrationObject{schema: schema, table: table},
+					&migrationObject{schema: item.NewSchema, table: table},
+				)
+			}
+		}
+	case *ast.DropTableStmt:
+		for _, table := range node.TableList {
+			objectList = append(objectList, &migrationObject{schema: table.Schema, table: table.Name})
+		}
+	// The parser doesn't convert the column list of INSERT and UPDATE yet, so the whole table is referenced.
+	case *ast.InsertStmt:
+		objectList = append(objectList, &migrationObject{schema: node.Table.Schema, table: node.Table.Name})
+	case *ast.UpdateStmt:
+		objectList = append(objectList, &migrationObject{schema: node.Table.Schema, table: node.Table.Name})
+	case *ast.DeleteStmt:
+		objectList = append(objectList, &migrationObject{schema: node.Table.Schema, table: node.Table.Name})
+	}
+	return objectList
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"testing"
+
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+
+	// Register postgresql parser engine.
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
+)
+
+func TestExtractMigrationObjectList(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		want      []*migrationObject
+	}{
+		{
+			engine:    db.MySQL,
+			statement: "CREATE TABLE t (id INT, name VARCHAR(10));",
+			want: []*migrationObject{
+				{table: "t"},
+				{table: "t", column: "id"},
+				{table: "t", column: "name"},
+			},
+		},
+		{
+			engine: db.MySQL,
+			statement: `ALTER TABLE t ADD COLUMN age INT, DROP COLUMN name, CHANGE COLUMN a b INT, RENAME COLUMN c TO d;
+				RENAME TABLE u TO v;
+				DROP TABLE w;`,
+			want: []*migrationObject{
+				{table: "t", column: "a"},
+				{table: "t", column: "age"},
+				{table: "t", column: "b"},
+				{table: "t", column: "c"},
+				{table: "t", column: "d"},
+				{table: "t", column: "name"},
+				{table: "u"},
+				{table: "v"},
+				{table: "w"},
+			},
+		},
+		{
+			engine: db.MySQL,
+			statement: `UPDATE t SET name = 'a' WHERE id = 1;
+				UPDATE t1 AS x JOIN t2 ON x.id = t2.id SET x.age = 1;
+				INSERT INTO t3 (id, name) VALUES (1, 'a');
+				INSERT INTO t4 VALUES (1);
+				DELETE FROM t5;`,
+			want: []*migrationObject{
+				{table: "t", column: "name"},
+				{table: "t1", column: "age"},
+				{table: "t3", column: "id"},
+				{table: "t3", column: "name"},
+				{table: "t4"},
+				{table: "t5"},
+			},
+		},
+		{
+			engine: db.Postgres,
+			statement: `CREATE TABLE t (id INT);
+				ALTER TABLE t ADD COLUMN name TEXT;
+				ALTER TABLE t RENAME COLUMN name TO title;
+				ALTER TABLE t ALTER COLUMN id SET NOT NULL;
+				UPDATE u SET a = 1;`,
+			want: []*migrationObject{
+				{table: "t"},
+				{table: "t", column: "id"},
+				{table: "t", column: "name"},
+				{table: "t", column: "title"},
+				{table: "u"},
+			},
+		},
+		{
+			engine: db.MySQL,
+			statement: `CREATE TABLE db1.t (id INT);
+				RENAME TABLE db1.t TO db2.t;
+				UPDATE db2.t AS x SET x.id = 1;`,
+			want: []*migrationObject{
+				{schema: "db1", table: "t"},
+				{schema: "db1", table: "t", column: "id"},
+				{schema: "db2", table: "t"},
+				{schema: "db2", table: "t", column: "id"},
+			},
+		},
+		{
+			engine: db.Postgres,
+			statement: `CREATE TABLE s1.t (id INT);
+				ALTER TABLE s1.t SET SCHEMA s2;
+				DELETE FROM s2.t;`,
+			want: []*migrationObject{
+				{schema: "s1", table: "t"},
+				{schema: "s1", table: "t", column: "id"},
+				{schema: "s2", table: "t"},
+			},
+		},
+		{
+			engine:    db.Snowflake,
+			statement: "CREATE TABLE t (id INT);",
+			want:      nil,
+		},
+	}
+
+	for _, test := range tests {
+		objectList, err := extractMigrationObjectList(test.engine, test.statement)
+		require.NoError(t, err)
+		require.Equal(t, test.want, objectList, test.statement)
+	}
+}
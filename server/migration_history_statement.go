@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// recordMigrationHistoryStatementList stores the execution duration of each statement of the migration history,
+// so that the slow migrations can be analyzed later and compared across the environments.
+// The migration has been executed, so the failure is logged instead of failing the task.
+func (s *Server) recordMigrationHistoryStatementList(ctx context.Context, task *api.Task, migrationID int, statementLogList []*db.StatementLog) {
+	if len(statementLogList) == 0 {
+		return
+	}
+
+	var createList []*api.MigrationHistoryStatementCreate
+	for _, stmtLog := range statementLogList {
+		createList = append(createList, &api.MigrationHistoryStatementCreate{
+			DatabaseID:         task.Database.ID,
+			MigrationHistoryID: migrationID,
+			StatementIndex:     stmtLog.Index,
+			Statement:          stmtLog.Statement,
+			DurationNs:         stmtLog.DurationNs,
+			RowsAffected:       stmtLog.RowsAffected,
+		})
+	}
+	if err := s.store.CreateMigrationHistoryStatementList(ctx, createList); err != nil {
+		log.Error("Failed to create the statement executions of the migration",
+			zap.Int("task_id", task.ID),
+			zap.Int("migration_id", migrationID),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+const (
+	// defaultMigrationStatWindow is the time window of the migration statistics if not specified.
+	defaultMigrationStatWindow = 30 * 24 * time.Hour
+)
+
+func (s *Server) registerMigrationStatRoutes(g *echo.Group) {
+	// Aggregates the migrations in the time window [startTs, endTs), which defaults to the last 30 days.
+	// The statistics can be filtered by the environment and the project.
+	g.GET("/migration-stat", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		find := &api.MigrationStatFind{
+			WorkspaceID: &workspaceID,
+			EndTs:       time.Now().Unix(),
+		}
+		if endTsStr := c.QueryParam("endTs"); endTsStr != "" {
+			endTs, err := strconv.ParseInt(endTsStr, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter endTs is not a number: %s", endTsStr)).SetInternal(err)
+			}
+			find.EndTs = endTs
+		}
+		find.StartTs = find.EndTs - int64(defaultMigrationStatWindow/time.Second)
+		if startTsStr := c.QueryParam("startTs"); startTsStr != "" {
+			startTs, err := strconv.ParseInt(startTsStr, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter startTs is not a number: %s", startTsStr)).SetInternal(err)
+			}
+			find.StartTs = startTs
+		}
+		if find.StartTs >= find.EndTs {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter startTs %d must be before endTs %d", find.StartTs, find.EndTs))
+		}
+		if environmentIDStr := c.QueryParam("environment"); environmentIDStr != "" {
+			environmentID, err := strconv.Atoi(environmentIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter environment is not a number: %s", environmentIDStr)).SetInternal(err)
+			}
+			find.EnvironmentID = &environmentID
+		}
+		if projectIDStr := c.QueryParam("project"); projectIDStr != "" {
+			projectID, err := strconv.Atoi(projectIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter project is not a number: %s", projectIDStr)).SetInternal(err)
+			}
+			find.ProjectID = &projectID
+		}
+
+		runCountList, err := s.store.FindMigrationRunCount(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to count migration runs").SetInternal(err)
+		}
+		checkFailureCountList, err := s.store.FindMigrationCheckFailureCount(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to count migration check failures").SetInternal(err)
+		}
+
+		stat := buildMigrationStat(runCountList, checkFailureCountList)
+		stat.StartTs = find.StartTs
+		stat.EndTs = find.EndTs
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, stat); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal migration stat response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// migrationStatAccumulator accumulates the counts of a migration statistics summary.
+type migrationStatAccumulator struct {
+	doneCount           int
+	failedCount         int
+	doneDurationSeconds int64
+	checkFailureMap     map[api.TaskCheckType]int
+}
+
+func (acc *migrationStatAccumulator) addRunCount(count *api.MigrationRunCount) {
+	acc.doneCount += count.DoneCount
+	acc.failedCount += count.FailedCount
+	acc.doneDurationSeconds += count.DoneDurationSeconds
+}
+
+func (acc *migrationStatAccumulator) addCheckFailureCount(count *api.MigrationCheckFailureCount) {
+	if acc.checkFailureMap == nil {
+		acc.checkFailureMap = make(map[api.TaskCheckType]int)
+	}
+	acc.checkFailureMap[count.Type] += count.Count
+}
+
+func (acc *migrationStatAccumulator) summary(id int) *api.MigrationStatSummary {
+	summary := &api.MigrationStatSummary{
+		ID:               id,
+		MigrationCount:   acc.doneCount + acc.failedCount,
+		FailedCount:      acc.failedCount,
+		CheckFailureList: []*api.MigrationCheckFailureCount{},
+	}
+	if summary.MigrationCount > 0 {
+		summary.FailureRate = float64(acc.failedCount) / float64(summary.MigrationCount)
+	}
+	if acc.doneCount > 0 {
+		summary.AverageDurationSeconds = float64(acc.doneDurationSeconds) / float64(acc.doneCount)
+	}
+	for checkType, count := range acc.checkFailureMap {
+		summary.CheckFailureList = append(summary.CheckFailureList, &api.MigrationCheckFailureCount{
+			Type:  checkType,
+			Count: count,
+		})
+	}
+	sort.Slice(summary.CheckFailureList, func(i, j int) bool {
+		if summary.CheckFailureList[i].Count != summary.CheckFailureList[j].Count {
+			return summary.CheckFailureList[i].Count > summary.CheckFailureList[j].Count
+		}
+		return summary.CheckFailureList[i].Type < summary.CheckFailureList[j].Type
+	})
+	return summary
+}
+
+// buildMigrationStat builds the migration statistics dashboard from the counts grouped by the environment and the project.
+func buildMigrationStat(runCountList []*api.MigrationRunCount, checkFailureCountList []*api.MigrationCheckFailureCount) *api.MigrationStat {
+	total := &migrationStatAccumulator{}
+	environmentMap := make(map[int]*migrationStatAccumulator)
+	projectMap := make(map[int]*migrationStatAccumulator)
+	get := func(m map[int]*migrationStatAccumulator, id int) *migrationStatAccumulator {
+		if _, ok := m[id]; !ok {
+			m[id] = &migrationStatAccumulator{}
+		}
+		return m[id]
+	}
+
+	for _, count := range runCountList {
+		total.addRunCount(count)
+		get(environmentMap, count.EnvironmentID).addRunCount(count)
+		get(projectMap, count.ProjectID).addRunCount(count)
+	}
+	for _, count := range checkFailureCountList {
+		total.addCheckFailureCount(count)
+		get(environmentMap, count.EnvironmentID).addCheckFailureCount(count)
+		get(projectMap, count.ProjectID).addCheckFailureCount(count)
+	}
+
+	stat := &api.MigrationStat{
+		Total:           total.summary(0),
+		EnvironmentList: []*api.MigrationStatSummary{},
+		ProjectList:     []*api.MigrationStatSummary{},
+	}
+	for id, acc := range environmentMap {
+		stat.EnvironmentList = append(stat.EnvironmentList, acc.summary(id))
+	}
+	for id, acc := range projectMap {
+		stat.ProjectList = append(stat.ProjectList, acc.summary(id))
+	}
+	sort.Slice(stat.EnvironmentList, func(i, j int) bool {
+		return stat.EnvironmentList[i].ID < stat.EnvironmentList[j].ID
+	})
+	sort.Slice(stat.ProjectList, func(i, j int) bool {
+		return stat.ProjectList[i].ID < stat.ProjectList[j].ID
+	})
+	return stat
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestBuildMigrationStat(t *testing.T) {
+	runCountList := []*api.MigrationRunCount{
+		{EnvironmentID: 101, ProjectID: 201, DoneCount: 3, FailedCount: 1, DoneDurationSeconds: 30},
+		{EnvironmentID: 102, ProjectID: 201, DoneCount: 1, FailedCount: 0, DoneDurationSeconds: 6},
+		{EnvironmentID: 102, ProjectID: 202, DoneCount: 0, FailedCount: 2, DoneDurationSeconds: 0},
+	}
+	checkFailureCountList := []*api.MigrationCheckFailureCount{
+		{EnvironmentID: 101, ProjectID: 201, Type: api.TaskCheckDatabaseStatementAdvise, Count: 2},
+		{EnvironmentID: 102, ProjectID: 202, Type: api.TaskCheckDatabaseStatementAdvise, Count: 1},
+		{EnvironmentID: 102, ProjectID: 202, Type: api.TaskCheckDatabaseStatementSyntax, Count: 4},
+		// The project has no finished migrations in the window.
+		{EnvironmentID: 101, ProjectID: 203, Type: api.TaskCheckDatabaseConnect, Count: 1},
+	}
+
+	stat := buildMigrationStat(runCountList, checkFailureCountList)
+	assert.Equal(t, &api.MigrationStatSummary{
+		ID:                     0,
+		MigrationCount:         7,
+		FailedCount:            3,
+		FailureRate:            3.0 / 7,
+		AverageDurationSeconds: 9,
+		CheckFailureList: []*api.MigrationCheckFailureCount{
+			{Type: api.TaskCheckDatabaseStatementSyntax, Count: 4},
+			{Type: api.TaskCheckDatabaseStatementAdvise, Count: 3},
+			{Type: api.TaskCheckDatabaseConnect, Count: 1},
+		},
+	}, stat.Total)
+
+	assert.Equal(t, []*api.MigrationStatSummary{
+		{
+			ID:                     101,
+			MigrationCount:         4,
+			FailedCount:            1,
+			FailureRate:            0.25,
+			AverageDurationSeconds: 10,
+			CheckFailureList: []*api.MigrationCheckFailureCount{
+				{Type: api.TaskCheckDatabaseStatementAdvise, Count: 2},
+				{Type: api.TaskCheckDatabaseConnect, Count: 1},
+			},
+		},
+		{
+			ID:                     102,
+			MigrationCount:         3,
+			FailedCount:            2,
+			FailureRate:            2.0 / 3,
+			AverageDurationSeconds: 6,
+			CheckFailureList: []*api.MigrationCheckFailureCount{
+				{Type: api.TaskCheckDatabaseStatementSyntax, Count: 4},
+				{Type: api.TaskCheckDatabaseStatementAdvise, Count: 1},
+			},
+		},
+	}, stat.EnvironmentList)
+
+	assert.Len(t, stat.ProjectList, 3)
+	project := stat.ProjectList[1]
+	assert.Equal(t, 202, project.ID)
+	assert.Equal(t, 2, project.MigrationCount)
+	assert.Equal(t, 1.0, project.FailureRate)
+	assert.Equal(t, 0.0, project.AverageDurationSeconds)
+	project = stat.ProjectList[2]
+	assert.Equal(t, 203, project.ID)
+	assert.Equal(t, 0, project.MigrationCount)
+	assert.Equal(t, 0.0, project.FailureRate)
+	assert.Equal(t, []*api.MigrationCheckFailureCount{
+		{Type: api.TaskCheckDatabaseConnect, Count: 1},
+	}, project.CheckFailureList)
+
+	empty := buildMigrationStat(nil, nil)
+	assert.Equal(t, 0, empty.Total.MigrationCount)
+	assert.Empty(t, empty.Total.CheckFailureList)
+	assert.Empty(t, empty.EnvironmentList)
+	assert.Empty(t, empty.ProjectList)
+}
@@ -8,12 +8,14 @@ import (
 	"net/http"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
 	metricAPI "github.com/bytebase/bytebase/metric"
 	"github.com/bytebase/bytebase/plugin/advisor/catalog"
 	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
 	"github.com/bytebase/bytebase/plugin/metric"
 	"github.com/bytebase/bytebase/store"
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 )
 
 var (
@@ -36,6 +38,11 @@ type sqlCheckRequestBody struct {
 	EnvironmentName string `json:"environmentName"`
 	Host            string `json:"host"`
 	Port            string `json:"port"`
+	// The GitLab merge request to post the findings as discussions, optional.
+	RepositoryID   string `json:"repositoryId"`
+	MergeRequestID int    `json:"mergeRequestId"`
+	FilePath       string `json:"filePath"`
+	SecretToken    string `json:"secretToken"`
 }
 
 func (s *Server) registerOpenAPIRoutes(g *echo.Group) {
@@ -54,6 +61,10 @@ func (s *Server) registerOpenAPIRoutes(g *echo.Group) {
 // @Param  host             body  string  false  "The instance host."
 // @Param  port             body  string  false  "The instance port."
 // @Param  databaseName     body  string  false  "The database name in the instance."
+// @Param  repositoryId     body  string  false  "The GitLab project ID. Post the findings to the merge request if specified."
+// @Param  mergeRequestId   body  int     false  "The GitLab merge request IID. Required if the repository ID is specified."
+// @Param  filePath         body  string  false  "The path of the SQL file in the repository. Required if the repository ID is specified."
+// @Param  secretToken      body  string  false  "The webhook secret token of the repository linked to the project. Required if the repository ID is specified."
 // @Success  200  {array}   advisor.Advice
 // @Failure  400  {object}  echo.HTTPError
 // @Failure  500  {object}  echo.HTTPError
@@ -77,6 +88,17 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 	}
 
 	ctx := c.Request().Context()
+	var repo *api.Repository
+	if request.RepositoryID != "" {
+		if request.MergeRequestID <= 0 || request.FilePath == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing required merge request ID or file path")
+		}
+		repo, err = s.findSQLReviewRepository(ctx, request.RepositoryID, request.SecretToken)
+		if err != nil {
+			return err
+		}
+	}
+
 	var databaseType string
 	var catalog catalog.Catalog = &catalogService{}
 
@@ -113,6 +135,7 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 	_, adviceList, err := s.sqlCheck(
 		ctx,
 		advisorDBType,
+		"", /* engineVersion */
 		"utf8mb4",
 		"utf8mb4_general_ci",
 		envList[0].ID,
@@ -123,6 +146,17 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to run sql check").SetInternal(err)
 	}
 
+	if repo != nil {
+		// Failing to post the discussions shouldn't fail the check, the findings are still returned to the CI.
+		if err := s.syncSQLReviewMergeRequestDiscussion(ctx, repo, request.MergeRequestID, request.FilePath, adviceList); err != nil {
+			log.Warn("Failed to post SQL review findings to the merge request",
+				zap.String("repository", repo.FullPath),
+				zap.Int("merge_request", request.MergeRequestID),
+				zap.Error(err),
+			)
+		}
+	}
+
 	if s.MetricReporter != nil {
 		s.MetricReporter.report(&metric.Metric{
 			Name:  metricAPI.SQLAdviseAPIMetricName,
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// loadProxy applies the workspace proxy setting to the outbound HTTP clients.
+func (s *Server) loadProxy(ctx context.Context) error {
+	settingName := api.SettingWorkspaceProxy
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return err
+	}
+	if len(settingList) == 0 {
+		return errors.Errorf("cannot find setting %v", settingName)
+	}
+	proxy, err := parseProxySetting(settingList[0].Value)
+	if err != nil {
+		return err
+	}
+	return common.SetProxyConfig(proxy)
+}
+
+// loadCACertificates makes the outbound connections trust the workspace CA certificates.
+func (s *Server) loadCACertificates(ctx context.Context) error {
+	settingName := api.SettingWorkspaceCACertificates
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return err
+	}
+	if len(settingList) == 0 {
+		return errors.Errorf("cannot find setting %v", settingName)
+	}
+	return common.SetCACertificates(settingList[0].Value)
+}
+
+// parseProxySetting parses and validates the proxy setting value.
+func parseProxySetting(value string) (*common.ProxyConfig, error) {
+	setting := &api.ProxySetting{}
+	if err := json.Unmarshal([]byte(value), setting); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal proxy setting %q", value)
+	}
+	proxy := &common.ProxyConfig{
+		HTTPProxy:  setting.HTTPProxy,
+		HTTPSProxy: setting.HTTPSProxy,
+		NoProxy:    setting.NoProxy,
+	}
+	if err := common.ValidateProxyConfig(proxy); err != nil {
+		return nil, err
+	}
+	return proxy, nil
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// getPageToken returns whether the list is requested page by page with the "token" query parameter,
+// and the cursor decoded from the token. The first page is requested with an empty token.
+func getPageToken(c echo.Context) (bool, *api.PageCursor, error) {
+	if !c.QueryParams().Has("token") {
+		return false, nil, nil
+	}
+	token := c.QueryParam("token")
+	if token == "" {
+		return true, nil, nil
+	}
+	cursor, err := api.DecodePageCursor(token)
+	if err != nil {
+		return false, nil, echo.NewHTTPError(http.StatusBadRequest, "Query parameter token is invalid").SetInternal(err)
+	}
+	return true, cursor, nil
+}
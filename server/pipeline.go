@@ -2,6 +2,9 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/pkg/errors"
@@ -13,6 +16,16 @@ func (s *Server) ScheduleActiveStage(ctx context.Context, pipeline *api.Pipeline
 	if stage == nil {
 		return nil
 	}
+	// The pipeline is held before the stage until it's resumed.
+	if stage.Paused {
+		resumeTs := getStageSoakResumeTs(pipeline.StageList, stage)
+		if resumeTs == 0 || time.Now().Unix() < resumeTs {
+			return nil
+		}
+		if err := s.resumeSoakedStage(ctx, pipeline, stage); err != nil {
+			return errors.Wrapf(err, "failed to resume stage %d after soak time", stage.ID)
+		}
+	}
 	for _, task := range stage.TaskList {
 		switch task.Status {
 		case api.TaskPendingApproval:
@@ -52,3 +65,73 @@ func (s *Server) ScheduleActiveStage(ctx context.Context, pipeline *api.Pipeline
 	}
 	return nil
 }
+
+// resumeSoakedStage resumes the paused stage whose soak time has passed.
+func (s *Server) resumeSoakedStage(ctx context.Context, pipeline *api.Pipeline, stage *api.Stage) error {
+	paused := false
+	soakSeconds := int64(0)
+	if _, err := s.store.PatchStage(ctx, &api.StagePatch{
+		ID:          stage.ID,
+		UpdaterID:   api.SystemBotID,
+		Paused:      &paused,
+		SoakSeconds: &soakSeconds,
+	}); err != nil {
+		return err
+	}
+	stage.Paused = false
+
+	issue, err := s.store.GetIssueByPipelineID(ctx, pipeline.ID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch issue with pipeline ID %d", pipeline.ID)
+	}
+	if issue == nil {
+		return nil
+	}
+	payload, err := json.Marshal(api.ActivityPipelineStagePauseResumePayload{
+		StageID:   stage.ID,
+		IssueName: issue.Name,
+		StageName: stage.Name,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal stage resume activity payload")
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: pipeline.ID,
+		Type:        api.ActivityPipelineStageResume,
+		Level:       api.ActivityInfo,
+		Comment:     fmt.Sprintf("Resumed automatically after soaking for %s.", time.Duration(stage.SoakSeconds)*time.Second),
+		Payload:     string(payload),
+	}, &ActivityMeta{
+		issue: issue,
+	}); err != nil {
+		return errors.Wrap(err, "failed to create stage resume activity")
+	}
+	return nil
+}
+
+// getStageSoakResumeTs returns the time the paused stage is resumed automatically, which is the soak time after the previous stage finishes.
+// Returns 0 if the stage is only resumed manually.
+// The stage must be the active stage, so that all the tasks of the previous stages have finished.
+func getStageSoakResumeTs(stageList []*api.Stage, stage *api.Stage) int64 {
+	if stage.SoakSeconds <= 0 {
+		return 0
+	}
+	var prevStage *api.Stage
+	for _, item := range stageList {
+		if item.ID == stage.ID {
+			break
+		}
+		prevStage = item
+	}
+	if prevStage == nil {
+		return 0
+	}
+	var finishedTs int64
+	for _, task := range prevStage.TaskList {
+		if task.UpdatedTs > finishedTs {
+			finishedTs = task.UpdatedTs
+		}
+	}
+	return finishedTs + stage.SoakSeconds
+}
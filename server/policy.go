@@ -31,21 +31,13 @@ func (s *Server) hasAccessToUpsertPolicy(policyUpsert *api.PolicyUpsert) error {
 	}
 	switch policyUpsert.Type {
 	case api.PolicyTypePipelineApproval:
-		if !s.feature(api.FeatureApprovalPolicy) {
-			return errors.Errorf(api.FeatureApprovalPolicy.AccessErrorMessage())
-		}
+		return s.checkFeature(api.FeatureApprovalPolicy)
 	case api.PolicyTypeBackupPlan:
-		if !s.feature(api.FeatureBackupPolicy) {
-			return errors.Errorf(api.FeatureBackupPolicy.AccessErrorMessage())
-		}
+		return s.checkFeature(api.FeatureBackupPolicy)
 	case api.PolicyTypeSQLReview:
-		if !s.feature(api.FeatureSQLReviewPolicy) {
-			return errors.Errorf(api.FeatureSQLReviewPolicy.AccessErrorMessage())
-		}
+		return s.checkFeature(api.FeatureSQLReviewPolicy)
 	case api.PolicyTypeEnvironmentTier:
-		if !s.feature(api.FeatureEnvironmentTierPolicy) {
-			return errors.Errorf(api.FeatureEnvironmentTierPolicy.AccessErrorMessage())
-		}
+		return s.checkFeature(api.FeatureEnvironmentTierPolicy)
 	}
 	return nil
 }
@@ -72,6 +64,10 @@ func (s *Server) registerPolicyRoutes(g *echo.Group) {
 		policyUpsert.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
 
 		if err := s.hasAccessToUpsertPolicy(policyUpsert); err != nil {
+			var featureErr *api.FeatureError
+			if errors.As(err, &featureErr) {
+				return featureHTTPError(err)
+			}
 			return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
 		}
 
@@ -147,8 +143,10 @@ func (s *Server) registerPolicyRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid policy type: %q", pType)).SetInternal(err)
 		}
 
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
 		policyFind := &api.PolicyFind{
-			Type: &pType,
+			Type:        &pType,
+			WorkspaceID: &workspaceID,
 		}
 
 		ctx := c.Request().Context()
@@ -53,8 +53,25 @@ func (s *Server) registerPrincipalRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch principal list").SetInternal(err)
 		}
 
+		// Only returns the principals who are members of the caller's workspace, plus the system bot.
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		memberList, err := s.store.FindMember(ctx, &api.MemberFind{WorkspaceID: &workspaceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch member list").SetInternal(err)
+		}
+		memberPrincipalIDs := make(map[int]bool)
+		for _, member := range memberList {
+			memberPrincipalIDs[member.PrincipalID] = true
+		}
+		var filteredList []*api.Principal
+		for _, principal := range principalList {
+			if principal.ID == api.SystemBotID || memberPrincipalIDs[principal.ID] {
+				filteredList = append(filteredList, principal)
+			}
+		}
+
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
-		if err := jsonapi.MarshalPayload(c.Response().Writer, principalList); err != nil {
+		if err := jsonapi.MarshalPayload(c.Response().Writer, filteredList); err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal principal list response").SetInternal(err)
 		}
 		return nil
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 	"strconv"
 	"strings"
 
@@ -29,10 +30,13 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, projectCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create project request").SetInternal(err)
 		}
-		if projectCreate.TenantMode == api.TenantModeTenant && !s.feature(api.FeatureMultiTenancy) {
-			return echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
+		if projectCreate.TenantMode == api.TenantModeTenant {
+			if err := s.checkFeature(api.FeatureMultiTenancy); err != nil {
+				return featureHTTPError(err)
+			}
 		}
 		projectCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		projectCreate.WorkspaceID = c.Get(getWorkspaceIDContextKey()).(int)
 		if projectCreate.TenantMode == "" {
 			projectCreate.TenantMode = api.TenantModeDisabled
 		}
@@ -70,7 +74,10 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 
 	g.GET("/project", func(c echo.Context) error {
 		ctx := c.Request().Context()
-		projectFind := &api.ProjectFind{}
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		projectFind := &api.ProjectFind{
+			WorkspaceID: &workspaceID,
+		}
 		if userIDStr := c.QueryParam("user"); userIDStr != "" {
 			userID, err := strconv.Atoi(userIDStr)
 			if err != nil {
@@ -79,10 +86,10 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			projectFind.PrincipalID = &userID
 		}
 
-		// Only Owner and DBA can fetch all projects from all users.
+		// Only Owner, DBA and Auditor can fetch all projects from all users.
 		if projectFind.PrincipalID == nil {
 			role := c.Get(getRoleContextKey()).(api.Role)
-			if role != api.Owner && role != api.DBA {
+			if role != api.Owner && role != api.DBA && role != api.Auditor {
 				return echo.NewHTTPError(http.StatusForbidden, "Not allowed to fetch all project list")
 			}
 		}
@@ -233,7 +240,8 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find VCS for creating repository: %d", repositoryCreate.VCSID)).SetInternal(err)
 		}
-		if vcs == nil {
+		// The VCS of another workspace is reported as not found, like the resources addressed by the route.
+		if vcs == nil || vcs.WorkspaceID != project.WorkspaceID {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("VCS not found with ID: %d", repositoryCreate.VCSID))
 		}
 
@@ -250,7 +258,7 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		switch vcs.Type {
 		case vcsPlugin.GitLabSelfHost:
 			webhookCreate := gitlab.WebhookCreate{
-				URL:                    fmt.Sprintf("%s:%d/%s/%s", s.profile.BackendHost, s.profile.BackendPort, gitlabWebhookPath, repositoryCreate.WebhookEndpointID),
+				URL:                    s.getRepositoryWebhookURL(vcs.Type, repositoryCreate.WebhookEndpointID),
 				SecretToken:            repositoryCreate.WebhookSecretToken,
 				PushEvents:             true,
 				PushEventsBranchFilter: repositoryCreate.BranchFilter,
@@ -261,13 +269,9 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal request body for creating webhook for project ID: %d", repositoryCreate.ProjectID)).SetInternal(err)
 			}
 		case vcsPlugin.GitHubCom:
-			webhookHost := s.profile.BackendHost
-			if s.profile.BackendHost == "http://localhost" {
-				webhookHost = fmt.Sprintf("%s:%d", s.profile.BackendHost, s.profile.BackendPort)
-			}
 			webhookPost := github.WebhookCreateOrUpdate{
 				Config: github.WebhookConfig{
-					URL:         fmt.Sprintf("%s/%s/%s", webhookHost, githubWebhookPath, repositoryCreate.WebhookEndpointID),
+					URL:         s.getRepositoryWebhookURL(vcs.Type, repositoryCreate.WebhookEndpointID),
 					ContentType: "json",
 					Secret:      repositoryCreate.WebhookSecretToken,
 					InsecureSSL: 1, // TODO: Allow user to specify this value through api.RepositoryCreate
@@ -543,6 +547,166 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// Check the GitOps configuration of the project end-to-end and return a checklist.
+	g.GET("/project/:projectID/repository/check", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project not found with ID %d", projectID))
+		}
+		repo, err := s.store.GetRepository(ctx, &api.RepositoryFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find repository for project ID: %d", projectID)).SetInternal(err)
+		}
+		if repo == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Repository not found for project ID: %d", projectID))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &api.RepositoryCheckResult{
+			ProjectID: projectID,
+			ItemList:  s.checkRepository(ctx, project, repo),
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal repository check result for project ID: %d", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// List the migration files in the repository linked to the project, so that users can pick one when creating the issue manually.
+	// The "path" query is the directory to list, defaults to the base directory of the repository.
+	// The "ref" query is the branch, tag or commit to list, defaults to the branch filter of the repository.
+	g.GET("/project/:projectID/repository/file", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		repo, err := s.store.GetRepository(ctx, &api.RepositoryFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find repository for project ID: %d", projectID)).SetInternal(err)
+		}
+		if repo == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Repository not found for project ID: %d", projectID))
+		}
+
+		dir, err := getRepositoryFilePath(repo, c.QueryParam("path"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		ref := c.QueryParam("ref")
+		if ref == "" {
+			ref = repo.BranchFilter
+		}
+
+		nodeList, err := vcsPlugin.Get(repo.VCS.Type, vcsPlugin.ProviderConfig{}).FetchRepositoryFileList(ctx,
+			common.OauthContext{
+				ClientID:     repo.VCS.ApplicationID,
+				ClientSecret: repo.VCS.Secret,
+				AccessToken:  repo.AccessToken,
+				RefreshToken: repo.RefreshToken,
+				Refresher:    s.refreshToken(ctx, repo.ID),
+			},
+			repo.VCS.InstanceURL,
+			repo.ExternalID,
+			ref,
+			dir,
+		)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Directory %q not found on ref %q", dir, ref)).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch repository file list from VCS, instance URL: %s", repo.VCS.InstanceURL)).SetInternal(err)
+		}
+
+		fileList := []*api.RepositoryFile{}
+		for _, node := range nodeList {
+			// Only the SQL files can be the migration files.
+			if !strings.HasSuffix(strings.ToLower(node.Path), ".sql") {
+				continue
+			}
+			fileList = append(fileList, &api.RepositoryFile{
+				Path: node.Path,
+				Name: path.Base(node.Path),
+			})
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, fileList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal repository file list response for project ID: %d", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Get the content of the file in the repository linked to the project.
+	// The "path" query is required, and the "ref" query defaults to the branch filter of the repository.
+	g.GET("/project/:projectID/repository/file/content", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		if c.QueryParam("path") == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing required file path")
+		}
+
+		repo, err := s.store.GetRepository(ctx, &api.RepositoryFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find repository for project ID: %d", projectID)).SetInternal(err)
+		}
+		if repo == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Repository not found for project ID: %d", projectID))
+		}
+
+		filePath, err := getRepositoryFilePath(repo, c.QueryParam("path"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		ref := c.QueryParam("ref")
+		if ref == "" {
+			ref = repo.BranchFilter
+		}
+
+		oauthCtx := common.OauthContext{
+			ClientID:     repo.VCS.ApplicationID,
+			ClientSecret: repo.VCS.Secret,
+			AccessToken:  repo.AccessToken,
+			RefreshToken: repo.RefreshToken,
+			Refresher:    s.refreshToken(ctx, repo.ID),
+		}
+		fileMeta, err := vcsPlugin.Get(repo.VCS.Type, vcsPlugin.ProviderConfig{}).ReadFileMeta(ctx, oauthCtx, repo.VCS.InstanceURL, repo.ExternalID, filePath, ref)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("File %q not found on ref %q", filePath, ref)).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch file meta from VCS, instance URL: %s, repo ID: %s, file path: %s, ref: %s", repo.VCS.InstanceURL, repo.ExternalID, filePath, ref)).SetInternal(err)
+		}
+		content, err := vcsPlugin.Get(repo.VCS.Type, vcsPlugin.ProviderConfig{}).ReadFileContent(ctx, oauthCtx, repo.VCS.InstanceURL, repo.ExternalID, filePath, ref)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch file content from VCS, instance URL: %s, repo ID: %s, file path: %s, ref: %s", repo.VCS.InstanceURL, repo.ExternalID, filePath, ref)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &api.RepositoryFile{
+			Path:         fileMeta.Path,
+			Name:         fileMeta.Name,
+			Size:         fileMeta.Size,
+			LastCommitID: fileMeta.LastCommitID,
+			Content:      content,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal repository file response for project ID: %d", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.PATCH("/project/:id/deployment", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		id, err := strconv.Atoi(c.Param("id"))
@@ -632,3 +796,29 @@ func refreshTokenNoop() common.TokenRefresher {
 		return nil
 	}
 }
+
+// getRepositoryWebhookURL returns the URL to which the VCS delivers the events of the repository.
+func (s *Server) getRepositoryWebhookURL(vcsType vcsPlugin.Type, webhookEndpointID string) string {
+	if vcsType == vcsPlugin.GitHubCom {
+		webhookHost := s.profile.BackendHost
+		if s.profile.BackendHost == "http://localhost" {
+			webhookHost = fmt.Sprintf("%s:%d", s.profile.BackendHost, s.profile.BackendPort)
+		}
+		return fmt.Sprintf("%s/%s/%s", webhookHost, githubWebhookPath, webhookEndpointID)
+	}
+	return fmt.Sprintf("%s:%d/%s/%s", s.profile.BackendHost, s.profile.BackendPort, gitlabWebhookPath, webhookEndpointID)
+}
+
+// getRepositoryFilePath cleans the path in the repository, and returns an error if it's outside the base directory of the repository.
+// The empty path refers to the base directory.
+func getRepositoryFilePath(repo *api.Repository, filePath string) (string, error) {
+	baseDirectory := strings.Trim(path.Clean("/"+repo.BaseDirectory), "/")
+	if filePath == "" {
+		return baseDirectory, nil
+	}
+	cleaned := strings.Trim(path.Clean("/"+filePath), "/")
+	if baseDirectory != "" && cleaned != baseDirectory && !strings.HasPrefix(cleaned, baseDirectory+"/") {
+		return "", errors.Errorf("path %q is outside the base directory %q of the repository", filePath, repo.BaseDirectory)
+	}
+	return cleaned, nil
+}
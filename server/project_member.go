@@ -94,7 +94,7 @@ func (s *Server) registerProjectMemberRoutes(g *echo.Group) {
 					// if the principal uses external auth provider
 					Password: password,
 				}
-				createdPrincipal, httpErr := trySignUp(ctx, s, signUpInfo, c.Get(getPrincipalIDContextKey()).(int))
+				createdPrincipal, httpErr := trySignUp(ctx, s, signUpInfo, c.Get(getWorkspaceIDContextKey()).(int), c.Get(getPrincipalIDContextKey()).(int))
 				if httpErr != nil {
 					return httpErr
 				}
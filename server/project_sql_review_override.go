@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerProjectSQLReviewOverrideRoutes(g *echo.Group) {
+	g.GET("/project/:projectID/sql-review-override", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		override, err := s.store.GetProjectSQLReviewOverrideByProjectID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get SQL review override for project ID: %d", projectID)).SetInternal(err)
+		}
+		// Return an empty override if the project has not configured any.
+		if override == nil {
+			override = &api.ProjectSQLReviewOverride{
+				ProjectID: projectID,
+				Payload:   "{}",
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, override); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal project SQL review override response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/sql-review-override", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if err := s.checkFeature(api.FeatureSQLReviewPolicy); err != nil {
+			return featureHTTPError(err)
+		}
+
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		// The override loosens the SQL review policy, so only the workspace Owner, DBA and the project owner can change it.
+		if role := c.Get(getRoleContextKey()).(api.Role); role != api.Owner && role != api.DBA {
+			ownerRole := api.Owner
+			memberList, err := s.store.FindProjectMember(ctx, &api.ProjectMemberFind{
+				ProjectID: &projectID,
+				Role:      &ownerRole,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch owners for project ID: %d", projectID)).SetInternal(err)
+			}
+			isProjectOwner := false
+			for _, member := range memberList {
+				if member.PrincipalID == principalID {
+					isProjectOwner = true
+					break
+				}
+			}
+			if !isProjectOwner {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the project owner can change the SQL review override")
+			}
+		}
+
+		upsert := &api.ProjectSQLReviewOverrideUpsert{
+			UpdaterID: principalID,
+			ProjectID: projectID,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed upsert project SQL review override request").SetInternal(err)
+		}
+
+		override, err := s.store.UpsertProjectSQLReviewOverride(ctx, upsert)
+		if err != nil {
+			if common.ErrorCode(err) == common.Invalid {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to upsert SQL review override for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, override); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal project SQL review override response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Get the effective SQL review policy of the project in the environment.
+	g.GET("/project/:projectID/environment/:environmentID/sql-review", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		environmentID, err := strconv.Atoi(c.Param("environmentID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Environment ID is not a number: %s", c.Param("environmentID"))).SetInternal(err)
+		}
+
+		policyID, err := s.store.GetSQLReviewPolicyIDByEnvID(ctx, environmentID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get SQL review policy for environment ID: %d", environmentID)).SetInternal(err)
+		}
+		policy, err := s.store.GetNormalSQLReviewPolicy(ctx, &api.PolicyFind{ID: &policyID})
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("SQL review policy not found for environment ID: %d", environmentID)).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get SQL review policy for environment ID: %d", environmentID)).SetInternal(err)
+		}
+		if err := s.store.MergeProjectSQLReviewOverride(ctx, projectID, policy); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to merge SQL review override for project ID: %d", projectID)).SetInternal(err)
+		}
+		payload, err := json.Marshal(policy)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal SQL review policy").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &api.ProjectSQLReviewPolicy{
+			ID:            policyID,
+			ProjectID:     projectID,
+			EnvironmentID: environmentID,
+			Payload:       string(payload),
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal project SQL review policy response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+}
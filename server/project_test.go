@@ -0,0 +1,54 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetRepositoryFilePath(t *testing.T) {
+	tests := []struct {
+		baseDirectory string
+		filePath      string
+		want          string
+		wantErr       bool
+	}{
+		{
+			baseDirectory: "bytebase",
+			filePath:      "",
+			want:          "bytebase",
+		},
+		{
+			baseDirectory: "bytebase",
+			filePath:      "/bytebase/prod/v1__init.sql",
+			want:          "bytebase/prod/v1__init.sql",
+		},
+		{
+			baseDirectory: "bytebase",
+			filePath:      "bytebase/../secret.sql",
+			wantErr:       true,
+		},
+		{
+			baseDirectory: "bytebase",
+			filePath:      "bytebase-other/v1__init.sql",
+			wantErr:       true,
+		},
+		{
+			baseDirectory: "",
+			filePath:      "prod/v1__init.sql",
+			want:          "prod/v1__init.sql",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := getRepositoryFilePath(&api.Repository{BaseDirectory: test.baseDirectory}, test.filePath)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.want, got)
+	}
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	defaultQueryAuditLogRetentionDays = 90
+	// maxQueryAuditLogExportCount caps the rows of a single CSV export.
+	maxQueryAuditLogExportCount = 100000
+)
+
+var (
+	fingerprintStringRegexp     = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'`)
+	fingerprintNumberRegexp     = regexp.MustCompile(`\b(?:0[xX][0-9a-fA-F]+|\d+(?:\.\d+)?(?:[eE][-+]?\d+)?)\b`)
+	fingerprintValueListRegexp  = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)+\s*\)`)
+	fingerprintWhitespaceRegexp = regexp.MustCompile(`\s+`)
+)
+
+func (s *Server) registerQueryAuditLogRoutes(g *echo.Group) {
+	g.GET("/query-audit-log", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find, err := getQueryAuditLogFind(c)
+		if err != nil {
+			return err
+		}
+		queryAuditLogList, err := s.store.FindQueryAuditLog(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch query audit log list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, queryAuditLogList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal query audit log list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// The CSV export accepts the same filters as the list.
+	g.GET("/query-audit-log/export", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find, err := getQueryAuditLogFind(c)
+		if err != nil {
+			return err
+		}
+		if find.Limit == nil || *find.Limit > maxQueryAuditLogExportCount {
+			limit := maxQueryAuditLogExportCount
+			find.Limit = &limit
+		}
+		queryAuditLogList, err := s.store.FindQueryAuditLog(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch query audit log list").SetInternal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := writeQueryAuditLogCSV(&buf, queryAuditLogList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export query audit log list").SetInternal(err)
+		}
+		filename := fmt.Sprintf("query-audit-log-%s.csv", time.Now().Format("20060102150405"))
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+		return c.Blob(http.StatusOK, "text/csv", buf.Bytes())
+	})
+
+	// The query results are exported in the browser, so the client reports the exports to be audited.
+	g.POST("/query-audit-log/export", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		create := &api.QueryAuditLogCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create query audit log request").SetInternal(err)
+		}
+		if create.InstanceID == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create query audit log request, missing instanceId")
+		}
+		if create.Statement == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create query audit log request, missing statement")
+		}
+		instance, err := s.store.GetInstanceByID(ctx, create.InstanceID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", create.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", create.InstanceID))
+		}
+		create.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		create.Type = api.QueryAuditLogExport
+		create.Fingerprint = getStatementFingerprint(create.Statement)
+
+		queryAuditLog, err := s.store.CreateQueryAuditLog(ctx, create)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create query audit log").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, queryAuditLog); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal query audit log response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+func getQueryAuditLogFind(c echo.Context) (*api.QueryAuditLogFind, error) {
+	workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+	find := &api.QueryAuditLogFind{
+		WorkspaceID: &workspaceID,
+	}
+	if creatorIDStr := c.QueryParams().Get("user"); creatorIDStr != "" {
+		creatorID, err := strconv.Atoi(creatorIDStr)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter user is not a number: %s", creatorIDStr)).SetInternal(err)
+		}
+		find.CreatorID = &creatorID
+	}
+	if instanceIDStr := c.QueryParams().Get("instance"); instanceIDStr != "" {
+		instanceID, err := strconv.Atoi(instanceIDStr)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter instance is not a number: %s", instanceIDStr)).SetInternal(err)
+		}
+		find.InstanceID = &instanceID
+	}
+	if databaseName := c.QueryParams().Get("database"); databaseName != "" {
+		find.DatabaseName = &databaseName
+	}
+	if typeStr := c.QueryParams().Get("type"); typeStr != "" {
+		queryAuditLogType := api.QueryAuditLogType(typeStr)
+		if queryAuditLogType != api.QueryAuditLogQuery && queryAuditLogType != api.QueryAuditLogExport && queryAuditLogType != api.QueryAuditLogExecute {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter type is invalid: %s", typeStr))
+		}
+		find.Type = &queryAuditLogType
+	}
+	if fingerprint := c.QueryParams().Get("fingerprint"); fingerprint != "" {
+		find.Fingerprint = &fingerprint
+	}
+	if createdTsAfterStr := c.QueryParams().Get("createdTsAfter"); createdTsAfterStr != "" {
+		createdTsAfter, err := strconv.ParseInt(createdTsAfterStr, 10, 64)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter createdTsAfter is not a number: %s", createdTsAfterStr)).SetInternal(err)
+		}
+		find.CreatedTsAfter = &createdTsAfter
+	}
+	if createdTsBeforeStr := c.QueryParams().Get("createdTsBefore"); createdTsBeforeStr != "" {
+		createdTsBefore, err := strconv.ParseInt(createdTsBeforeStr, 10, 64)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter createdTsBefore is not a number: %s", createdTsBeforeStr)).SetInternal(err)
+		}
+		find.CreatedTsBefore = &createdTsBefore
+	}
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a number: %s", limitStr)).SetInternal(err)
+		}
+		find.Limit = &limit
+	}
+	return find, nil
+}
+
+// createQueryAuditLog records the statement executed or the result exported in the SQL editor.
+func (s *Server) createQueryAuditLog(ctx context.Context, c echo.Context, create *api.QueryAuditLogCreate) error {
+	create.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+	create.Fingerprint = getStatementFingerprint(create.Statement)
+	if _, err := s.store.CreateQueryAuditLog(ctx, create); err != nil {
+		log.Warn("Failed to create query audit log after executing sql statement",
+			zap.String("database_name", create.DatabaseName),
+			zap.Int("instance_id", create.InstanceID),
+			zap.String("statement", create.Statement),
+			zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create query audit log").SetInternal(err)
+	}
+	return nil
+}
+
+// purgeExpiredQueryAuditLog deletes the query audit logs older than the retention days.
+func (s *Server) purgeExpiredQueryAuditLog(ctx context.Context) {
+	settingName := api.SettingQueryAuditLogRetentionDays
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		log.Error("Failed to find the query audit log retention setting.", zap.Error(err))
+		return
+	}
+	retentionDays := defaultQueryAuditLogRetentionDays
+	if len(settingList) == 1 {
+		if retentionDays, err = strconv.Atoi(settingList[0].Value); err != nil {
+			log.Error("Invalid query audit log retention setting.", zap.String("value", settingList[0].Value), zap.Error(err))
+			return
+		}
+	}
+	if retentionDays <= 0 {
+		return
+	}
+
+	if err := s.store.DeleteQueryAuditLogBefore(ctx, time.Now().AddDate(0, 0, -retentionDays).Unix()); err != nil {
+		log.Error("Failed to delete the expired query audit logs.", zap.Int("retention_days", retentionDays), zap.Error(err))
+	}
+}
+
+// getStatementFingerprint returns the statement with the literals replaced by placeholders and the whitespaces collapsed,
+// so that the statements differing only in the values share the same fingerprint.
+func getStatementFingerprint(statement string) string {
+	fingerprint := fingerprintStringRegexp.ReplaceAllString(statement, "?")
+	fingerprint = fingerprintNumberRegexp.ReplaceAllString(fingerprint, "?")
+	fingerprint = fingerprintWhitespaceRegexp.ReplaceAllString(fingerprint, " ")
+	fingerprint = fingerprintValueListRegexp.ReplaceAllString(fingerprint, "(?)")
+	return strings.TrimRight(strings.TrimSpace(fingerprint), "; ")
+}
+
+func writeQueryAuditLogCSV(buf *bytes.Buffer, queryAuditLogList []*api.QueryAuditLog) error {
+	writer := csv.NewWriter(buf)
+	if err := writer.Write([]string{"id", "created_time", "user", "email", "type", "instance_id", "database", "statement", "fingerprint", "row_count", "duration_ms", "error"}); err != nil {
+		return err
+	}
+	for _, queryAuditLog := range queryAuditLogList {
+		var name, email string
+		if queryAuditLog.Creator != nil {
+			name, email = queryAuditLog.Creator.Name, queryAuditLog.Creator.Email
+		}
+		if err := writer.Write([]string{
+			strconv.Itoa(queryAuditLog.ID),
+			time.Unix(queryAuditLog.CreatedTs, 0).UTC().Format(time.RFC3339),
+			name,
+			email,
+			string(queryAuditLog.Type),
+			strconv.Itoa(queryAuditLog.InstanceID),
+			queryAuditLog.DatabaseName,
+			queryAuditLog.Statement,
+			queryAuditLog.Fingerprint,
+			strconv.FormatInt(queryAuditLog.RowCount, 10),
+			strconv.FormatInt(queryAuditLog.DurationNs/int64(time.Millisecond), 10),
+			queryAuditLog.Error,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
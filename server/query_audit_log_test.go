@@ -0,0 +1,35 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStatementFingerprint(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{
+			statement: "SELECT * FROM t1 WHERE id = 10",
+			want:      "SELECT * FROM t1 WHERE id = ?",
+		},
+		{
+			statement: "SELECT name FROM user\n  WHERE email = 'alice@example.com'\n  AND score > 3.5;",
+			want:      "SELECT name FROM user WHERE email = ? AND score > ?",
+		},
+		{
+			statement: "SELECT * FROM t WHERE id IN (1, 2, 3) AND note = 'it''s'",
+			want:      "SELECT * FROM t WHERE id IN (?) AND note = ?",
+		},
+		{
+			statement: "SELECT * FROM t WHERE a = 0x1F AND b = 'x\\'y' LIMIT 100",
+			want:      "SELECT * FROM t WHERE a = ? AND b = ? LIMIT ?",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, getStatementFingerprint(test.statement), test.statement)
+	}
+}
@@ -0,0 +1,236 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/store"
+)
+
+func (s *Server) registerRecurringTaskRoutes(g *echo.Group) {
+	g.POST("/recurring-task", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		recurringTaskCreate := &api.RecurringTaskCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, recurringTaskCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create recurring task request").SetInternal(err)
+		}
+
+		recurringTaskCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		recurringTaskCreate.Name = strings.TrimSpace(recurringTaskCreate.Name)
+		if recurringTaskCreate.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Recurring task name must not be empty")
+		}
+		if recurringTaskCreate.MaxAffectedRows < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Max affected rows must not be negative, got %d", recurringTaskCreate.MaxAffectedRows))
+		}
+		nextRunTs, err := getRecurringTaskNextRunTs(recurringTaskCreate.Schedule, time.Now())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		recurringTaskCreate.NextRunTs = nextRunTs
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &recurringTaskCreate.DatabaseID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", recurringTaskCreate.DatabaseID)).SetInternal(err)
+		}
+		if database == nil || database.Instance.WorkspaceID != c.Get(getWorkspaceIDContextKey()).(int) {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", recurringTaskCreate.DatabaseID))
+		}
+		if err := s.checkRecurringTaskStatement(ctx, database, recurringTaskCreate.Statement); err != nil {
+			return err
+		}
+
+		recurringTask, err := s.store.CreateRecurringTask(ctx, recurringTaskCreate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create recurring task").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, recurringTask); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create recurring task response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/recurring-task", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		recurringTaskFind := &api.RecurringTaskFind{WorkspaceID: &workspaceID}
+		if databaseIDStr := c.QueryParams().Get("database"); databaseIDStr != "" {
+			databaseID, err := strconv.Atoi(databaseIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter database is not a number: %s", databaseIDStr)).SetInternal(err)
+			}
+			recurringTaskFind.DatabaseID = &databaseID
+		}
+		if rowStatusStr := c.QueryParams().Get("rowstatus"); rowStatusStr != "" {
+			rowStatus := api.RowStatus(rowStatusStr)
+			recurringTaskFind.RowStatus = &rowStatus
+		}
+		recurringTaskList, err := s.store.FindRecurringTask(ctx, recurringTaskFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch recurring task list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, recurringTaskList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal recurring task list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/recurring-task/:recurringTaskID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("recurringTaskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("recurringTaskID"))).SetInternal(err)
+		}
+
+		recurringTask, err := s.store.GetRecurringTaskByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch recurring task ID: %v", id)).SetInternal(err)
+		}
+		if recurringTask == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Recurring task ID not found: %d", id))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, recurringTask); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal recurring task ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/recurring-task/:recurringTaskID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("recurringTaskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("recurringTaskID"))).SetInternal(err)
+		}
+
+		recurringTaskPatch := &api.RecurringTaskPatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, recurringTaskPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch recurring task request").SetInternal(err)
+		}
+
+		recurringTask, err := s.store.GetRecurringTaskByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch recurring task ID: %v", id)).SetInternal(err)
+		}
+		if recurringTask == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Recurring task ID not found: %d", id))
+		}
+
+		if v := recurringTaskPatch.Name; v != nil {
+			name := strings.TrimSpace(*v)
+			if name == "" {
+				return echo.NewHTTPError(http.StatusBadRequest, "Recurring task name must not be empty")
+			}
+			recurringTaskPatch.Name = &name
+		}
+		if v := recurringTaskPatch.MaxAffectedRows; v != nil && *v < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Max affected rows must not be negative, got %d", *v))
+		}
+		if v := recurringTaskPatch.Schedule; v != nil {
+			nextRunTs, err := getRecurringTaskNextRunTs(*v, time.Now())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			recurringTaskPatch.NextRunTs = &nextRunTs
+		}
+		// Re-calculate the next run when the recurring task is restored, so that the runs missed during archival are not caught up.
+		if v := recurringTaskPatch.RowStatus; v != nil && api.RowStatus(*v) == api.Normal && recurringTask.RowStatus != api.Normal && recurringTaskPatch.NextRunTs == nil {
+			nextRunTs, err := getRecurringTaskNextRunTs(recurringTask.Schedule, time.Now())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			recurringTaskPatch.NextRunTs = &nextRunTs
+		}
+		if v := recurringTaskPatch.Statement; v != nil {
+			if err := s.checkRecurringTaskStatement(ctx, recurringTask.Database, *v); err != nil {
+				return err
+			}
+		}
+
+		recurringTask, err = s.store.PatchRecurringTask(ctx, recurringTaskPatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Recurring task ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch recurring task ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, recurringTask); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal recurring task ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getRecurringTaskNextRunTs returns the UNIX timestamp of the first run of the schedule after now.
+func getRecurringTaskNextRunTs(schedule string, now time.Time) (int64, error) {
+	cronSchedule, err := common.ParseCronSchedule(schedule)
+	if err != nil {
+		return 0, err
+	}
+	next := cronSchedule.Next(now)
+	if next.IsZero() {
+		return 0, errors.Errorf("cron expression %q never runs", schedule)
+	}
+	return next.Unix(), nil
+}
+
+// checkRecurringTaskStatement runs the SQL review against the statement of the recurring task at definition time,
+// because the scheduled runs are approved automatically without anyone looking at the statement.
+func (s *Server) checkRecurringTaskStatement(ctx context.Context, database *api.Database, statement string) error {
+	if strings.TrimSpace(statement) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Recurring task statement must not be empty")
+	}
+	instance := database.Instance
+	if !s.feature(api.FeatureSQLReviewPolicy) || !api.IsSQLReviewSupported(instance.Engine, s.profile.Mode) {
+		return nil
+	}
+	dbType, err := advisorDB.ConvertToAdvisorDBType(string(instance.Engine))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to convert db type %v into advisor db type", instance.Engine))
+	}
+	adviceLevel, adviceList, err := s.sqlCheck(
+		ctx,
+		dbType,
+		instance.EngineVersion,
+		database.CharacterSet,
+		database.Collation,
+		instance.EnvironmentID,
+		statement,
+		store.NewCatalog(&database.ID, s.store, instance.Engine),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check SQL review policy").SetInternal(err)
+	}
+	if adviceLevel == advisor.Error {
+		var messageList []string
+		for _, advice := range adviceList {
+			if advice.Status == advisor.Error {
+				messageList = append(messageList, fmt.Sprintf("%s: %s", advice.Title, advice.Content))
+			}
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Recurring task statement violates the SQL review policy: %s", strings.Join(messageList, "; ")))
+	}
+	return nil
+}
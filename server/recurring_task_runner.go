@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// Cron schedules have the minute granularity.
+	recurringTaskRunnerInterval = time.Duration(1) * time.Minute
+)
+
+// NewRecurringTaskRunner creates a recurring task runner.
+func NewRecurringTaskRunner(server *Server) *RecurringTaskRunner {
+	return &RecurringTaskRunner{
+		server: server,
+	}
+}
+
+// RecurringTaskRunner is the runner creating data update issues for the due recurring tasks.
+type RecurringTaskRunner struct {
+	server *Server
+}
+
+// Run will run the recurring task runner.
+func (s *RecurringTaskRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(recurringTaskRunnerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Recurring task runner started and will run every %v", recurringTaskRunnerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			if s.server.isInMaintenance() {
+				log.Debug("Recurring task runner paused in maintenance mode")
+				continue
+			}
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = errors.Errorf("%v", r)
+						}
+						log.Error("Recurring task runner PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+					}
+				}()
+
+				now := time.Now()
+				nowTs := now.Unix()
+				rowStatus := api.Normal
+				recurringTaskList, err := s.server.store.FindRecurringTask(ctx, &api.RecurringTaskFind{
+					RowStatus:       &rowStatus,
+					NextRunTsBefore: &nowTs,
+				})
+				if err != nil {
+					log.Error("Failed to retrieve due recurring tasks", zap.Error(err))
+					return
+				}
+				for _, recurringTask := range recurringTaskList {
+					if err := s.runRecurringTask(ctx, recurringTask, now); err != nil {
+						log.Error("Failed to run recurring task",
+							zap.Int("id", recurringTask.ID),
+							zap.String("name", recurringTask.Name),
+							zap.Error(err))
+					}
+				}
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+// runRecurringTask creates a data update issue for the recurring task and moves the recurring task to its next run.
+// The run is skipped if the issue of the last run is still open, so that the runs don't pile up on a stuck database.
+func (s *RecurringTaskRunner) runRecurringTask(ctx context.Context, recurringTask *api.RecurringTask, now time.Time) error {
+	nextRunTs, err := getRecurringTaskNextRunTs(recurringTask.Schedule, now)
+	if err != nil {
+		return err
+	}
+	recurringTaskPatch := &api.RecurringTaskPatch{
+		ID:        recurringTask.ID,
+		UpdaterID: api.SystemBotID,
+		NextRunTs: &nextRunTs,
+	}
+
+	skip := false
+	if recurringTask.LastIssueID != 0 {
+		lastIssue, err := s.server.store.GetIssueByID(ctx, recurringTask.LastIssueID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the last issue %d", recurringTask.LastIssueID)
+		}
+		if lastIssue != nil && lastIssue.Status == api.IssueOpen {
+			log.Warn("Skip the recurring task run since the last issue is still open",
+				zap.Int("id", recurringTask.ID),
+				zap.Int("issue", lastIssue.ID))
+			skip = true
+		}
+	}
+
+	if !skip {
+		issue, err := s.createRecurringTaskIssue(ctx, recurringTask, now)
+		if err != nil {
+			return err
+		}
+		recurringTaskPatch.LastIssueID = &issue.ID
+	}
+
+	if _, err := s.server.store.PatchRecurringTask(ctx, recurringTaskPatch); err != nil {
+		return errors.Wrapf(err, "failed to patch the next run of recurring task %d", recurringTask.ID)
+	}
+	return nil
+}
+
+func (s *RecurringTaskRunner) createRecurringTaskIssue(ctx context.Context, recurringTask *api.RecurringTask, now time.Time) (*api.Issue, error) {
+	createContext, err := json.Marshal(&api.UpdateSchemaContext{
+		MigrationType: db.Data,
+		DetailList: []*api.UpdateSchemaDetail{
+			{
+				DatabaseID: recurringTask.DatabaseID,
+				Statement:  recurringTask.Statement,
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal issue create context")
+	}
+	payload, err := json.Marshal(&api.IssuePayload{RecurringTaskID: recurringTask.ID})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal issue payload")
+	}
+
+	issueCreate := &api.IssueCreate{
+		ProjectID:     recurringTask.Database.ProjectID,
+		Name:          fmt.Sprintf("[Recurring] %s @%s", recurringTask.Name, now.Format("2006-01-02 15:04")),
+		Type:          api.IssueDatabaseDataUpdate,
+		Description:   fmt.Sprintf("Run recurring task %q on schedule %q.", recurringTask.Name, recurringTask.Schedule),
+		AssigneeID:    api.SystemBotID,
+		Payload:       string(payload),
+		CreateContext: string(createContext),
+	}
+	issue, err := s.server.createIssue(ctx, issueCreate, recurringTask.CreatorID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create issue for recurring task %d", recurringTask.ID)
+	}
+
+	// The statement has been reviewed when the recurring task is defined, so the scheduled runs are approved by the system bot.
+	for _, stage := range issue.Pipeline.StageList {
+		for _, stageTask := range stage.TaskList {
+			task, err := s.server.store.GetTaskByID(ctx, stageTask.ID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get task %d", stageTask.ID)
+			}
+			if task == nil || task.Status != api.TaskPendingApproval {
+				continue
+			}
+			if _, err := s.server.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
+				ID:        task.ID,
+				UpdaterID: api.SystemBotID,
+				Status:    api.TaskPending,
+			}); err != nil {
+				return nil, errors.Wrapf(err, "failed to approve task %d", task.ID)
+			}
+		}
+	}
+	return issue, nil
+}
@@ -0,0 +1,416 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/mail"
+)
+
+const (
+	// The weekly report is sent in the first round of the week, the interval only bounds how late in the week it can be.
+	reportRunnerInterval = time.Duration(1) * time.Hour
+	// reportPeriod is the period summarized by the weekly report, and how far ahead the upcoming scheduled tasks are listed.
+	reportPeriod = time.Duration(7*24) * time.Hour
+	// reportClosedIssueLimit is the maximum number of the most recently updated closed issues looked into for the weekly report.
+	reportClosedIssueLimit = 500
+	// reportTimeLayout is the layout of the times in the weekly report.
+	reportTimeLayout = "2006-01-02 15:04 MST"
+)
+
+var (
+	//go:embed report_weekly.html
+	reportWeeklyTemplateText string
+	reportWeeklyTemplate     = template.Must(template.New("report_weekly").Parse(reportWeeklyTemplateText))
+)
+
+// NewReportRunner creates a report runner.
+func NewReportRunner(server *Server) *ReportRunner {
+	return &ReportRunner{
+		server: server,
+	}
+}
+
+// ReportRunner is the report runner sending the weekly project reports to the subscribers by email.
+type ReportRunner struct {
+	server *Server
+}
+
+// Run will run the report runner once.
+func (r *ReportRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(reportRunnerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Report runner started and will run every %v", reportRunnerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			if r.server.isInMaintenance() {
+				log.Debug("Report runner paused in maintenance mode")
+				continue
+			}
+			log.Debug("New report round started...")
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = errors.Errorf("%v", r)
+						}
+						log.Error("Report runner PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+					}
+				}()
+
+				r.sendWeeklyReport(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+// sendWeeklyReport sends the weekly report to the subscribers not having received one since the start of the week.
+// The failed subscribers are retried in the next round.
+func (r *ReportRunner) sendWeeklyReport(ctx context.Context, now time.Time) {
+	smtpSetting, err := r.server.getSMTPSetting(ctx)
+	if err != nil {
+		log.Error("Failed to get SMTP setting", zap.Error(err))
+		return
+	}
+	if smtpSetting == nil {
+		return
+	}
+
+	enabled := true
+	subscriptionList, err := r.server.store.FindReportSubscription(ctx, &api.ReportSubscriptionFind{Enabled: &enabled})
+	if err != nil {
+		log.Error("Failed to retrieve report subscriptions", zap.Error(err))
+		return
+	}
+	startOfWeekTs := getStartOfWeekTs(now)
+	projectSubscriptionMap := make(map[int][]*api.ReportSubscription)
+	var projectIDList []int
+	for _, subscription := range subscriptionList {
+		if subscription.LastSentTs >= startOfWeekTs {
+			continue
+		}
+		if _, ok := projectSubscriptionMap[subscription.ProjectID]; !ok {
+			projectIDList = append(projectIDList, subscription.ProjectID)
+		}
+		projectSubscriptionMap[subscription.ProjectID] = append(projectSubscriptionMap[subscription.ProjectID], subscription)
+	}
+
+	for _, projectID := range projectIDList {
+		if err := r.sendProjectReport(ctx, smtpSetting, projectID, projectSubscriptionMap[projectID], now); err != nil {
+			log.Error("Failed to send weekly report",
+				zap.Int("project_id", projectID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// sendProjectReport sends the weekly report of the project to the subscribers who can still see the project.
+func (r *ReportRunner) sendProjectReport(ctx context.Context, smtpSetting *api.SMTPSetting, projectID int, subscriptionList []*api.ReportSubscription, now time.Time) error {
+	project, err := r.server.store.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get project %d", projectID)
+	}
+	if project == nil || project.RowStatus == api.Archived {
+		return nil
+	}
+
+	var issueList []*api.Issue
+	openIssueList, err := r.server.store.FindIssueStripped(ctx, &api.IssueFind{
+		ProjectID:  &projectID,
+		StatusList: []api.IssueStatus{api.IssueOpen},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find open issues of project %d", projectID)
+	}
+	issueList = append(issueList, openIssueList...)
+	limit := reportClosedIssueLimit
+	closedIssueList, err := r.server.store.FindIssueStripped(ctx, &api.IssueFind{
+		ProjectID:  &projectID,
+		StatusList: []api.IssueStatus{api.IssueDone, api.IssueCanceled},
+		Limit:      &limit,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find closed issues of project %d", projectID)
+	}
+	issueList = append(issueList, closedIssueList...)
+	for _, issue := range issueList {
+		pipeline, err := r.server.store.GetPipelineByID(ctx, issue.PipelineID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get pipeline of issue %d", issue.ID)
+		}
+		issue.Pipeline = pipeline
+	}
+
+	rowStatus := api.Normal
+	databaseList, err := r.server.store.FindDatabase(ctx, &api.DatabaseFind{ProjectID: &projectID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find databases of project %d", projectID)
+	}
+	var anomalyList []*api.Anomaly
+	for _, database := range databaseList {
+		list, err := r.server.store.FindAnomaly(ctx, &api.AnomalyFind{
+			RowStatus:  &rowStatus,
+			DatabaseID: &database.ID,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to find anomalies of database %d", database.ID)
+		}
+		anomalyList = append(anomalyList, list...)
+	}
+
+	baseURL := fmt.Sprintf("%s:%d", r.server.profile.FrontendHost, r.server.profile.FrontendPort)
+	report := buildProjectReport(baseURL, project, issueList, databaseList, anomalyList, now)
+	body, err := renderProjectReport(report)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptionList {
+		member, err := r.server.store.GetMemberByPrincipalID(ctx, subscription.PrincipalID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get member of principal %d", subscription.PrincipalID)
+		}
+		// The subscribers who left the workspace or the project no longer receive the report.
+		if member == nil || member.RowStatus == api.Archived || !canSubscribeProjectReport(project, subscription.PrincipalID, member.Role) {
+			continue
+		}
+		principal, err := r.server.store.GetPrincipalByID(ctx, subscription.PrincipalID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get principal %d", subscription.PrincipalID)
+		}
+		if principal == nil || principal.Email == "" {
+			continue
+		}
+
+		if err := mail.Send(&mail.Config{
+			Host:     smtpSetting.Host,
+			Port:     smtpSetting.Port,
+			Username: smtpSetting.Username,
+			Password: smtpSetting.Password,
+			From:     smtpSetting.From,
+		}, &mail.Message{
+			To:       []string{principal.Email},
+			Subject:  fmt.Sprintf("[Bytebase] Weekly report of %s", project.Name),
+			HTMLBody: body,
+		}); err != nil {
+			log.Warn("Failed to send weekly report email",
+				zap.Int("project_id", projectID),
+				zap.String("email", principal.Email),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		lastSentTs := now.Unix()
+		if _, err := r.server.store.PatchReportSubscription(ctx, &api.ReportSubscriptionPatch{
+			ID:         subscription.ID,
+			UpdaterID:  api.SystemBotID,
+			LastSentTs: &lastSentTs,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to patch report subscription %d", subscription.ID)
+		}
+	}
+	return nil
+}
+
+// getSMTPSetting returns the SMTP setting, or nil if the SMTP server isn't configured.
+func (s *Server) getSMTPSetting(ctx context.Context) (*api.SMTPSetting, error) {
+	settingName := api.SettingMailSMTP
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return nil, err
+	}
+	if len(settingList) == 0 {
+		return nil, nil
+	}
+	smtpSetting, err := parseSMTPSetting(settingList[0].Value)
+	if err != nil {
+		return nil, err
+	}
+	if smtpSetting.Host == "" {
+		return nil, nil
+	}
+	if s.profile.SMTPPassword != "" {
+		smtpSetting.Password = s.profile.SMTPPassword
+	}
+	return smtpSetting, nil
+}
+
+// parseSMTPSetting parses and validates the SMTP setting, the setting with empty host isn't validated.
+func parseSMTPSetting(value string) (*api.SMTPSetting, error) {
+	smtpSetting := &api.SMTPSetting{}
+	if err := json.Unmarshal([]byte(value), smtpSetting); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal SMTP setting")
+	}
+	if smtpSetting.Host == "" {
+		return smtpSetting, nil
+	}
+	config := &mail.Config{
+		Host: smtpSetting.Host,
+		Port: smtpSetting.Port,
+		From: smtpSetting.From,
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return smtpSetting, nil
+}
+
+// getStartOfWeekTs returns the timestamp of Monday 00:00 of the week in the location of the time.
+func getStartOfWeekTs(t time.Time) int64 {
+	year, month, day := t.Date()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, t.Location()).Unix()
+}
+
+// projectReport is the weekly report of a project.
+type projectReport struct {
+	ProjectName string
+	ProjectLink string
+	StartDate   string
+	EndDate     string
+	// MigrationList is the schema and data migrations applied in the period.
+	MigrationList []*projectReportTask
+	// FailureList is the tasks failed in the period.
+	FailureList []*projectReportTask
+	// AnomalyList is the open anomalies of the databases in the project.
+	AnomalyList []*projectReportAnomaly
+	// UpcomingList is the pending tasks scheduled to run in the next period.
+	UpcomingList []*projectReportTask
+}
+
+type projectReportTask struct {
+	IssueName       string
+	IssueLink       string
+	TaskName        string
+	EnvironmentName string
+	Time            string
+	ts              int64
+}
+
+type projectReportAnomaly struct {
+	DatabaseName    string
+	DatabaseLink    string
+	EnvironmentName string
+	Type            api.AnomalyType
+	Severity        api.AnomalySeverity
+}
+
+// buildProjectReport summarizes the tasks of the issues and the anomalies of the databases in the project
+// into the report of the period ending now.
+func buildProjectReport(baseURL string, project *api.Project, issueList []*api.Issue, databaseList []*api.Database, anomalyList []*api.Anomaly, now time.Time) *projectReport {
+	startTs, nowTs, endTs := now.Add(-reportPeriod).Unix(), now.Unix(), now.Add(reportPeriod).Unix()
+	report := &projectReport{
+		ProjectName: project.Name,
+		ProjectLink: fmt.Sprintf("%s/project/%s", baseURL, api.ProjectSlug(project)),
+		StartDate:   now.Add(-reportPeriod).Format("2006-01-02"),
+		EndDate:     now.Format("2006-01-02"),
+	}
+
+	for _, issue := range issueList {
+		if issue.Pipeline == nil {
+			continue
+		}
+		for _, stage := range issue.Pipeline.StageList {
+			environmentName := ""
+			if stage.Environment != nil {
+				environmentName = stage.Environment.Name
+			}
+			for _, task := range stage.TaskList {
+				newReportTask := func(ts int64) *projectReportTask {
+					return &projectReportTask{
+						IssueName:       issue.Name,
+						IssueLink:       fmt.Sprintf("%s/issue/%s", baseURL, api.IssueSlug(issue)),
+						TaskName:        task.Name,
+						EnvironmentName: environmentName,
+						Time:            time.Unix(ts, 0).In(now.Location()).Format(reportTimeLayout),
+						ts:              ts,
+					}
+				}
+				switch task.Status {
+				case api.TaskDone:
+					if isMigrationTask(task.Type) && task.UpdatedTs >= startTs && task.UpdatedTs < nowTs {
+						report.MigrationList = append(report.MigrationList, newReportTask(task.UpdatedTs))
+					}
+				case api.TaskFailed:
+					if task.UpdatedTs >= startTs && task.UpdatedTs < nowTs {
+						report.FailureList = append(report.FailureList, newReportTask(task.UpdatedTs))
+					}
+				case api.TaskPending, api.TaskPendingApproval:
+					if task.EarliestAllowedTs > nowTs && task.EarliestAllowedTs <= endTs {
+						report.UpcomingList = append(report.UpcomingList, newReportTask(task.EarliestAllowedTs))
+					}
+				}
+			}
+		}
+	}
+	for _, list := range [][]*projectReportTask{report.MigrationList, report.FailureList, report.UpcomingList} {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].ts < list[j].ts
+		})
+	}
+
+	databaseMap := make(map[int]*api.Database)
+	for _, database := range databaseList {
+		databaseMap[database.ID] = database
+	}
+	for _, anomaly := range anomalyList {
+		if anomaly.DatabaseID == nil {
+			continue
+		}
+		database, ok := databaseMap[*anomaly.DatabaseID]
+		if !ok {
+			continue
+		}
+		environmentName := ""
+		if database.Instance != nil && database.Instance.Environment != nil {
+			environmentName = database.Instance.Environment.Name
+		}
+		report.AnomalyList = append(report.AnomalyList, &projectReportAnomaly{
+			DatabaseName:    database.Name,
+			DatabaseLink:    fmt.Sprintf("%s/db/%s", baseURL, api.DatabaseSlug(database)),
+			EnvironmentName: environmentName,
+			Type:            anomaly.Type,
+			Severity:        anomaly.Severity,
+		})
+	}
+	return report
+}
+
+// isMigrationTask returns true if the task type applies the schema or data migration.
+// The gh-ost migration is applied by the cutover task.
+func isMigrationTask(taskType api.TaskType) bool {
+	switch taskType {
+	case api.TaskDatabaseSchemaUpdate, api.TaskDatabaseDataUpdate, api.TaskDatabaseSchemaUpdateGhostCutover:
+		return true
+	default:
+		return false
+	}
+}
+
+// renderProjectReport renders the weekly report as HTML.
+func renderProjectReport(report *projectReport) (string, error) {
+	var buf bytes.Buffer
+	if err := reportWeeklyTemplate.Execute(&buf, report); err != nil {
+		return "", errors.Wrapf(err, "failed to render weekly report of project %q", report.ProjectName)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetStartOfWeekTs(t *testing.T) {
+	location := time.FixedZone("UTC+8", 8*60*60)
+	monday := time.Date(2022, 9, 26, 0, 0, 0, 0, location)
+	tests := []struct {
+		t    time.Time
+		want time.Time
+	}{
+		{
+			t:    monday,
+			want: monday,
+		},
+		{
+			t:    time.Date(2022, 9, 28, 15, 4, 5, 0, location),
+			want: monday,
+		},
+		{
+			t:    time.Date(2022, 10, 2, 23, 59, 59, 0, location),
+			want: monday,
+		},
+		{
+			// Sunday in UTC is Monday in UTC+8.
+			t:    time.Date(2022, 10, 2, 16, 0, 0, 0, time.UTC).In(location),
+			want: monday.AddDate(0, 0, 7),
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want.Unix(), getStartOfWeekTs(test.t), test.t.String())
+	}
+}
+
+func TestParseSMTPSetting(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{
+			value: `{"host":"","port":587,"username":"","password":"","from":""}`,
+		},
+		{
+			value: `{"host":"smtp.example.com","port":587,"username":"bytebase","password":"secret","from":"Bytebase <bytebase@example.com>"}`,
+		},
+		{
+			value:   `{"host":"smtp.example.com","port":0,"from":"bytebase@example.com"}`,
+			wantErr: true,
+		},
+		{
+			value:   `{"host":"smtp.example.com","port":587,"from":""}`,
+			wantErr: true,
+		},
+		{
+			value:   `{"host":`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := parseSMTPSetting(test.value)
+		if test.wantErr {
+			assert.Error(t, err, test.value)
+		} else {
+			assert.NoError(t, err, test.value)
+		}
+	}
+}
+
+func TestCanSubscribeProjectReport(t *testing.T) {
+	project := &api.Project{
+		ProjectMemberList: []*api.ProjectMember{
+			{PrincipalID: 101},
+		},
+	}
+	assert.True(t, canSubscribeProjectReport(project, 101, api.Developer))
+	assert.False(t, canSubscribeProjectReport(project, 102, api.Developer))
+	assert.True(t, canSubscribeProjectReport(project, 102, api.DBA))
+	assert.True(t, canSubscribeProjectReport(project, 102, api.Owner))
+}
+
+func TestBuildProjectReport(t *testing.T) {
+	now := time.Date(2022, 9, 26, 8, 0, 0, 0, time.UTC)
+	hoursAgo := func(hours int) int64 {
+		return now.Add(-time.Duration(hours) * time.Hour).Unix()
+	}
+	project := &api.Project{ID: 101, Name: "Shop"}
+	issue := &api.Issue{
+		ID:   102,
+		Name: "Add column",
+		Pipeline: &api.Pipeline{
+			StageList: []*api.Stage{
+				{
+					Environment: &api.Environment{Name: "Prod"},
+					TaskList: []*api.Task{
+						{Name: "Migrate order", Type: api.TaskDatabaseSchemaUpdate, Status: api.TaskDone, UpdatedTs: hoursAgo(2)},
+						{Name: "Migrate user", Type: api.TaskDatabaseDataUpdate, Status: api.TaskDone, UpdatedTs: hoursAgo(1)},
+						// Applied before the period.
+						{Name: "Migrate item", Type: api.TaskDatabaseSchemaUpdate, Status: api.TaskDone, UpdatedTs: hoursAgo(24 * 8)},
+						// Not a migration.
+						{Name: "Backup", Type: api.TaskDatabaseBackup, Status: api.TaskDone, UpdatedTs: hoursAgo(1)},
+						{Name: "Migrate cart", Type: api.TaskDatabaseSchemaUpdate, Status: api.TaskFailed, UpdatedTs: hoursAgo(3)},
+						{Name: "Migrate stock", Type: api.TaskDatabaseSchemaUpdate, Status: api.TaskPendingApproval, EarliestAllowedTs: hoursAgo(-24)},
+						// Scheduled beyond the next period.
+						{Name: "Migrate price", Type: api.TaskDatabaseSchemaUpdate, Status: api.TaskPending, EarliestAllowedTs: hoursAgo(-24 * 8)},
+						// Not scheduled.
+						{Name: "Migrate tax", Type: api.TaskDatabaseSchemaUpdate, Status: api.TaskPending},
+					},
+				},
+			},
+		},
+	}
+	databaseID := 103
+	databaseList := []*api.Database{
+		{
+			ID:       databaseID,
+			Name:     "shop",
+			Instance: &api.Instance{Environment: &api.Environment{Name: "Prod"}},
+		},
+	}
+	anomalyList := []*api.Anomaly{
+		{DatabaseID: &databaseID, Type: api.AnomalyDatabaseSchemaDrift, Severity: api.AnomalySeverityCritical},
+		// Instance anomaly.
+		{InstanceID: 104, Type: api.AnomalyInstanceConnection, Severity: api.AnomalySeverityCritical},
+	}
+
+	report := buildProjectReport("http://localhost:8080", project, []*api.Issue{issue}, databaseList, anomalyList, now)
+	taskNameList := func(list []*projectReportTask) []string {
+		var nameList []string
+		for _, task := range list {
+			nameList = append(nameList, task.TaskName)
+		}
+		return nameList
+	}
+	assert.Equal(t, "http://localhost:8080/project/shop-101", report.ProjectLink)
+	assert.Equal(t, "2022-09-19", report.StartDate)
+	assert.Equal(t, "2022-09-26", report.EndDate)
+	assert.Equal(t, []string{"Migrate order", "Migrate user"}, taskNameList(report.MigrationList))
+	assert.Equal(t, "http://localhost:8080/issue/add-column-102", report.MigrationList[0].IssueLink)
+	assert.Equal(t, "Prod", report.MigrationList[0].EnvironmentName)
+	assert.Equal(t, "2022-09-26 06:00 UTC", report.MigrationList[0].Time)
+	assert.Equal(t, []string{"Migrate cart"}, taskNameList(report.FailureList))
+	assert.Equal(t, []string{"Migrate stock"}, taskNameList(report.UpcomingList))
+	require.Len(t, report.AnomalyList, 1)
+	assert.Equal(t, &projectReportAnomaly{
+		DatabaseName:    "shop",
+		DatabaseLink:    "http://localhost:8080/db/shop-103",
+		EnvironmentName: "Prod",
+		Type:            api.AnomalyDatabaseSchemaDrift,
+		Severity:        api.AnomalySeverityCritical,
+	}, report.AnomalyList[0])
+
+	body, err := renderProjectReport(report)
+	require.NoError(t, err)
+	assert.Contains(t, body, "Weekly report of <a href=\"http://localhost:8080/project/shop-101\">Shop</a>")
+	assert.Contains(t, body, "Migrations applied (2)")
+	assert.Contains(t, body, "Failures (1)")
+	assert.Contains(t, body, "Open anomalies (1)")
+	assert.Contains(t, body, "Upcoming scheduled tasks (1)")
+	assert.Contains(t, body, "Migrate cart")
+
+	// The names are escaped.
+	project.Name = "<script>"
+	body, err = renderProjectReport(buildProjectReport("http://localhost:8080", project, nil, nil, nil, now))
+	require.NoError(t, err)
+	assert.False(t, strings.Contains(body, "<script>"))
+	assert.Equal(t, 4, strings.Count(body, "<p>None</p>"))
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerReportSubscriptionRoutes(g *echo.Group) {
+	// The subscription of the caller is returned, which is disabled if the caller hasn't subscribed yet.
+	g.GET("/project/:projectID/report-subscription", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+
+		subscription, err := s.store.GetReportSubscription(ctx, &api.ReportSubscriptionFind{
+			ProjectID:   &projectID,
+			PrincipalID: &principalID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch report subscription of project ID: %d", projectID)).SetInternal(err)
+		}
+		if subscription == nil {
+			subscription = &api.ReportSubscription{
+				ProjectID:   projectID,
+				PrincipalID: principalID,
+				Enabled:     false,
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, subscription); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal report subscription response of project ID: %d", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/report-subscription", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		upsert := &api.ReportSubscriptionUpsert{
+			UpdaterID:   principalID,
+			ProjectID:   projectID,
+			PrincipalID: principalID,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch report subscription request").SetInternal(err)
+		}
+
+		if upsert.Enabled {
+			project, err := s.store.GetProjectByID(ctx, projectID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %d", projectID)).SetInternal(err)
+			}
+			if project == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+			}
+			role := c.Get(getRoleContextKey()).(api.Role)
+			if !canSubscribeProjectReport(project, principalID, role) {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the project members, the workspace owners and DBAs can subscribe to the project report")
+			}
+		}
+
+		subscription, err := s.store.UpsertReportSubscription(ctx, upsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch report subscription of project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, subscription); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal report subscription response of project ID: %d", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// canSubscribeProjectReport returns true if the principal can receive the report of the project.
+// The workspace owners and DBAs can see every project, the developers only see the projects they are members of.
+func canSubscribeProjectReport(project *api.Project, principalID int, role api.Role) bool {
+	if role == api.Owner || role == api.DBA {
+		return true
+	}
+	return isProjectMember(project, principalID)
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+)
+
+// checkRepository checks the GitOps configuration of the project end-to-end, including the VCS access token, the webhook,
+// the base directory and the path templates, and returns one check item per aspect.
+func (s *Server) checkRepository(ctx context.Context, project *api.Project, repo *api.Repository) []*api.RepositoryCheckItem {
+	provider := vcsPlugin.Get(repo.VCS.Type, vcsPlugin.ProviderConfig{})
+	oauthCtx := common.OauthContext{
+		ClientID:     repo.VCS.ApplicationID,
+		ClientSecret: repo.VCS.Secret,
+		AccessToken:  repo.AccessToken,
+		RefreshToken: repo.RefreshToken,
+		Refresher:    s.refreshToken(ctx, repo.ID),
+	}
+	var itemList []*api.RepositoryCheckItem
+	addItem := func(checkType api.RepositoryCheckType, status api.RepositoryCheckStatus, message string) {
+		itemList = append(itemList, &api.RepositoryCheckItem{
+			Type:    checkType,
+			Status:  status,
+			Message: message,
+		})
+	}
+
+	if _, err := provider.TryLogin(ctx, oauthCtx, repo.VCS.InstanceURL); err != nil {
+		addItem(api.RepositoryCheckAccessToken, api.RepositoryCheckStatusError, fmt.Sprintf("The access token is invalid or expired, please relink the repository: %v", err))
+	} else {
+		addItem(api.RepositoryCheckAccessToken, api.RepositoryCheckStatusSuccess, "")
+	}
+
+	// Fetching the webhook requires the same permission as managing it, so it also tells whether the token scope is sufficient.
+	expectedURL := s.getRepositoryWebhookURL(repo.VCS.Type, repo.WebhookEndpointID)
+	webhook, err := provider.FetchWebhook(ctx, oauthCtx, repo.VCS.InstanceURL, repo.ExternalID, repo.ExternalWebhookID)
+	switch {
+	case err == nil:
+		addItem(api.RepositoryCheckTokenScope, api.RepositoryCheckStatusSuccess, "")
+		if webhook.URL != expectedURL {
+			addItem(api.RepositoryCheckWebhook, api.RepositoryCheckStatusError, fmt.Sprintf("The webhook delivers the events to %q, expect %q", webhook.URL, expectedURL))
+		} else {
+			addItem(api.RepositoryCheckWebhook, api.RepositoryCheckStatusSuccess, "")
+		}
+	case common.ErrorCode(err) == common.NotAuthorized:
+		addItem(api.RepositoryCheckTokenScope, api.RepositoryCheckStatusError, "The access token isn't allowed to manage the webhook of the repository, the maintainer role and the api scope are required")
+		addItem(api.RepositoryCheckWebhook, api.RepositoryCheckStatusError, "Cannot fetch the webhook due to insufficient token scope")
+	case common.ErrorCode(err) == common.NotFound:
+		addItem(api.RepositoryCheckTokenScope, api.RepositoryCheckStatusSuccess, "")
+		addItem(api.RepositoryCheckWebhook, api.RepositoryCheckStatusError, fmt.Sprintf("The webhook %s is not found in the repository, please relink the repository", repo.ExternalWebhookID))
+	default:
+		addItem(api.RepositoryCheckTokenScope, api.RepositoryCheckStatusError, fmt.Sprintf("Failed to fetch the webhook: %v", err))
+		addItem(api.RepositoryCheckWebhook, api.RepositoryCheckStatusError, fmt.Sprintf("Failed to fetch the webhook: %v", err))
+	}
+
+	if isLoopbackURL(expectedURL) {
+		addItem(api.RepositoryCheckWebhookReachable, api.RepositoryCheckStatusWarn, fmt.Sprintf("The webhook URL %q is a loopback address, which is not reachable from the VCS unless it runs on the same host, please configure the external URL of Bytebase", expectedURL))
+	} else {
+		addItem(api.RepositoryCheckWebhookReachable, api.RepositoryCheckStatusSuccess, "")
+	}
+
+	if _, err := provider.FetchRepositoryFileList(ctx, oauthCtx, repo.VCS.InstanceURL, repo.ExternalID, repo.BranchFilter, repo.BaseDirectory); err != nil {
+		if common.ErrorCode(err) == common.NotFound {
+			addItem(api.RepositoryCheckBaseDirectory, api.RepositoryCheckStatusError, fmt.Sprintf("The base directory %q is not found on branch %q", repo.BaseDirectory, repo.BranchFilter))
+		} else {
+			addItem(api.RepositoryCheckBaseDirectory, api.RepositoryCheckStatusError, fmt.Sprintf("Failed to list the base directory %q on branch %q: %v", repo.BaseDirectory, repo.BranchFilter, err))
+		}
+	} else {
+		addItem(api.RepositoryCheckBaseDirectory, api.RepositoryCheckStatusSuccess, "")
+	}
+
+	if err := api.ValidateRepositoryFilePathTemplate(repo.FilePathTemplate, project.TenantMode); err != nil {
+		addItem(api.RepositoryCheckFilePathTemplate, api.RepositoryCheckStatusError, err.Error())
+	} else if err := api.ValidateRepositorySchemaPathTemplate(repo.SchemaPathTemplate, project.TenantMode); err != nil {
+		addItem(api.RepositoryCheckFilePathTemplate, api.RepositoryCheckStatusError, err.Error())
+	} else {
+		addItem(api.RepositoryCheckFilePathTemplate, api.RepositoryCheckStatusSuccess, "")
+	}
+
+	return itemList
+}
+
+// isLoopbackURL returns true if the host of the URL is the localhost or a loopback IP.
+func isLoopbackURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
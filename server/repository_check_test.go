@@ -0,0 +1,15 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsLoopbackURL(t *testing.T) {
+	assert.True(t, isLoopbackURL("http://localhost:8080/hook/gitlab/1"))
+	assert.True(t, isLoopbackURL("http://127.0.0.1:8080/hook/gitlab/1"))
+	assert.True(t, isLoopbackURL("http://[::1]:8080/hook/gitlab/1"))
+	assert.False(t, isLoopbackURL("https://bytebase.example.com/hook/github/1"))
+	assert.False(t, isLoopbackURL("http://10.0.0.1:8080/hook/gitlab/1"))
+}
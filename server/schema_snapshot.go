@@ -0,0 +1,153 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerSchemaSnapshotRoutes(g *echo.Group) {
+	// Lists the schema snapshots of the database from the newest, without the schema.
+	g.GET("/database/:id/schema-snapshot", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		find := &api.SchemaSnapshotFind{DatabaseID: &id}
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a positive number: %s", limitStr)).SetInternal(err)
+			}
+			find.Limit = &limit
+		}
+		schemaSnapshotList, err := s.store.FindSchemaSnapshot(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot list for database ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, schemaSnapshotList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema snapshot list response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Gets the schema of the database as of the timestamp, which is the one of the newest snapshot taken at or before it.
+	g.GET("/database/:id/schema-snapshot/as-of", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		ts, err := strconv.ParseInt(c.QueryParam("ts"), 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter ts is not a timestamp: %s", c.QueryParam("ts"))).SetInternal(err)
+		}
+
+		schemaSnapshot, err := s.store.GetSchemaSnapshot(ctx, &api.SchemaSnapshotFind{
+			DatabaseID:      &id,
+			CreatedTsBefore: &ts,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot as of %d for database ID: %d", ts, id)).SetInternal(err)
+		}
+		if schemaSnapshot == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("No schema snapshot as of %d for database ID: %d", ts, id))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, schemaSnapshot); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema snapshot response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/schema-snapshot/:snapshotID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		snapshotID, err := strconv.Atoi(c.Param("snapshotID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Snapshot ID is not a number: %s", c.Param("snapshotID"))).SetInternal(err)
+		}
+
+		schemaSnapshot, err := s.store.GetSchemaSnapshot(ctx, &api.SchemaSnapshotFind{
+			ID:         &snapshotID,
+			DatabaseID: &id,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot ID: %d", snapshotID)).SetInternal(err)
+		}
+		if schemaSnapshot == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema snapshot ID not found: %d", snapshotID))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, schemaSnapshot); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema snapshot ID response: %d", snapshotID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Diffs the schemas of any two snapshots, which may belong to different databases.
+	g.GET("/schema-snapshot/diff", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var snapshotList []*api.SchemaSnapshot
+		for _, param := range []string{"from", "to"} {
+			snapshotID, err := strconv.Atoi(c.QueryParam(param))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter %s is not a number: %s", param, c.QueryParam(param))).SetInternal(err)
+			}
+			schemaSnapshot, err := s.store.GetSchemaSnapshot(ctx, &api.SchemaSnapshotFind{ID: &snapshotID})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot ID: %d", snapshotID)).SetInternal(err)
+			}
+			if schemaSnapshot == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema snapshot ID not found: %d", snapshotID))
+			}
+			snapshotList = append(snapshotList, schemaSnapshot)
+		}
+
+		diff, err := getSchemaSnapshotDiff(snapshotList[0], snapshotList[1])
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to diff schema snapshots").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, &api.SchemaSnapshotDiff{
+			FromSnapshotID: snapshotList[0].ID,
+			ToSnapshotID:   snapshotList[1].ID,
+			Diff:           diff,
+		})
+	})
+}
+
+// getSchemaSnapshotDiff returns the unified diff from the schema of the from snapshot to the one of the to snapshot.
+func getSchemaSnapshotDiff(from, to *api.SchemaSnapshot) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitSchemaLines(from.Schema),
+		B:        splitSchemaLines(to.Schema),
+		FromFile: fmt.Sprintf("snapshot-%d", from.ID),
+		ToFile:   fmt.Sprintf("snapshot-%d", to.ID),
+		Context:  3,
+	})
+}
+
+// splitSchemaLines splits the schema into lines keeping the line endings, as difflib.SplitLines adds a trailing empty line.
+func splitSchemaLines(schema string) []string {
+	lines := strings.SplitAfter(schema, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
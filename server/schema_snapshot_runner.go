@@ -0,0 +1,139 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// The daily snapshot is taken in the first round of the day, the interval only bounds how late in the day it can be.
+	schemaSnapshotInterval = time.Duration(1) * time.Hour
+)
+
+// NewSchemaSnapshotRunner creates a schema snapshot runner.
+func NewSchemaSnapshotRunner(server *Server) *SchemaSnapshotRunner {
+	return &SchemaSnapshotRunner{
+		server: server,
+	}
+}
+
+// SchemaSnapshotRunner is the schema snapshot runner taking the daily schema snapshots of the databases.
+type SchemaSnapshotRunner struct {
+	server *Server
+}
+
+// Run will run the schema snapshot runner once.
+func (r *SchemaSnapshotRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(schemaSnapshotInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Schema snapshot runner started and will run every %v", schemaSnapshotInterval))
+	for {
+		select {
+		case <-ticker.C:
+			if r.server.isInMaintenance() {
+				log.Debug("Schema snapshot runner paused in maintenance mode")
+				continue
+			}
+			log.Debug("New schema snapshot round started...")
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = errors.Errorf("%v", r)
+						}
+						log.Error("Schema snapshot runner PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+					}
+				}()
+
+				r.takeDailySchemaSnapshot(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+// takeDailySchemaSnapshot takes the schema snapshot of the databases not having one since the start of the day.
+func (r *SchemaSnapshotRunner) takeDailySchemaSnapshot(ctx context.Context, now time.Time) {
+	rowStatus := api.Normal
+	syncStatus := api.OK
+	databaseList, err := r.server.store.FindDatabase(ctx, &api.DatabaseFind{
+		RowStatus:  &rowStatus,
+		SyncStatus: &syncStatus,
+	})
+	if err != nil {
+		log.Error("Failed to retrieve databases", zap.Error(err))
+		return
+	}
+
+	startOfDayTs := getStartOfDayTs(now)
+	for _, database := range databaseList {
+		if ctx.Err() != nil {
+			return
+		}
+		if database.Instance.RowStatus != api.Normal {
+			continue
+		}
+		snapshot, err := r.server.store.GetSchemaSnapshot(ctx, &api.SchemaSnapshotFind{
+			DatabaseID:     &database.ID,
+			CreatedTsAfter: &startOfDayTs,
+		})
+		if err != nil {
+			log.Error("Failed to find schema snapshot",
+				zap.String("instance", database.Instance.Name),
+				zap.String("database", database.Name),
+				zap.Error(err))
+			continue
+		}
+		if snapshot != nil {
+			continue
+		}
+		if err := r.server.createSchemaSnapshot(ctx, database, api.SchemaSnapshotDaily, ""); err != nil {
+			log.Debug("Failed to take daily schema snapshot",
+				zap.String("instance", database.Instance.Name),
+				zap.String("database", database.Name),
+				zap.Error(err))
+		}
+	}
+}
+
+// createSchemaSnapshot dumps the current schema of the database and saves it as a snapshot.
+func (s *Server) createSchemaSnapshot(ctx context.Context, database *api.Database, snapshotType api.SchemaSnapshotType, migrationVersion string) error {
+	driver, err := s.getAdminDatabaseDriver(ctx, database.Instance, database.Name)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	var schemaBuf bytes.Buffer
+	if _, err := driver.Dump(ctx, database.Name, &schemaBuf, true /* schemaOnly */); err != nil {
+		return errors.Wrapf(err, "failed to dump schema of database %q", database.Name)
+	}
+	if _, err := s.store.CreateSchemaSnapshot(ctx, &api.SchemaSnapshotCreate{
+		CreatorID:        api.SystemBotID,
+		DatabaseID:       database.ID,
+		Type:             snapshotType,
+		MigrationVersion: migrationVersion,
+		Schema:           schemaBuf.String(),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// getStartOfDayTs returns the timestamp of the start of the day in the local time zone.
+func getStartOfDayTs(t time.Time) int64 {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location()).Unix()
+}
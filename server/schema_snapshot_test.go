@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetSchemaSnapshotDiff(t *testing.T) {
+	from := &api.SchemaSnapshot{
+		ID:     101,
+		Schema: "CREATE TABLE t (\n  id INT\n);\n",
+	}
+	to := &api.SchemaSnapshot{
+		ID:     102,
+		Schema: "CREATE TABLE t (\n  id INT,\n  name TEXT\n);\n",
+	}
+
+	diff, err := getSchemaSnapshotDiff(from, to)
+	require.NoError(t, err)
+	want := "--- snapshot-101\n" +
+		"+++ snapshot-102\n" +
+		"@@ -1,3 +1,4 @@\n" +
+		" CREATE TABLE t (\n" +
+		"-  id INT\n" +
+		"+  id INT,\n" +
+		"+  name TEXT\n" +
+		" );\n"
+	require.Equal(t, want, diff)
+
+	diff, err = getSchemaSnapshotDiff(from, from)
+	require.NoError(t, err)
+	require.Equal(t, "", diff)
+}
+
+func TestGetStartOfDayTs(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	got := getStartOfDayTs(time.Date(2022, 9, 12, 23, 59, 59, 0, loc))
+	require.Equal(t, time.Date(2022, 9, 12, 0, 0, 0, 0, loc).Unix(), got)
+}
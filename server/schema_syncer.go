@@ -39,6 +39,10 @@ func (s *SchemaSyncer) Run(ctx context.Context, wg *sync.WaitGroup) {
 	for {
 		select {
 		case <-ticker.C:
+			if s.server.isInMaintenance() {
+				log.Debug("Schema syncer paused in maintenance mode")
+				continue
+			}
 			log.Debug("New schema syncer round started...")
 			func() {
 				defer func() {
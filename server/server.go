@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -32,7 +33,7 @@ import (
 	enterpriseService "github.com/bytebase/bytebase/enterprise/service"
 	"github.com/bytebase/bytebase/metric"
 	metricCollector "github.com/bytebase/bytebase/metric/collector"
-	s3bb "github.com/bytebase/bytebase/plugin/storage/s3"
+	"github.com/bytebase/bytebase/plugin/storage"
 	"github.com/bytebase/bytebase/resources/mysqlutil"
 	"github.com/bytebase/bytebase/resources/postgres"
 	"github.com/bytebase/bytebase/store"
@@ -44,15 +45,28 @@ const openAPIPrefix = "/v1"
 // Server is the Bytebase server.
 type Server struct {
 	// Asynchronous runners.
-	TaskScheduler      *TaskScheduler
-	TaskCheckScheduler *TaskCheckScheduler
-	MetricReporter     *MetricReporter
-	SchemaSyncer       *SchemaSyncer
-	BackupRunner       *BackupRunner
-	AnomalyScanner     *AnomalyScanner
-	runnerWG           sync.WaitGroup
+	TaskScheduler        *TaskScheduler
+	TaskCheckScheduler   *TaskCheckScheduler
+	MetricReporter       *MetricReporter
+	SchemaSyncer         *SchemaSyncer
+	BackupRunner         *BackupRunner
+	AnomalyScanner       *AnomalyScanner
+	SchemaSnapshotRunner *SchemaSnapshotRunner
+	RecurringTaskRunner  *RecurringTaskRunner
+	ReportRunner         *ReportRunner
+	runnerWG             sync.WaitGroup
+
+	// AgentManager relays the connections of the instances in agent mode through their agents.
+	AgentManager *AgentManager
+	// driverPool keeps the opened database drivers for reuse.
+	driverPool *driverPool
+
+	// maintenance is the cached maintenance setting, see maintenanceMiddleware.
+	maintenance   api.MaintenanceSetting
+	maintenanceMu sync.RWMutex
 
 	ActivityManager *ActivityManager
+	DataDiffManager *DataDiffManager
 
 	LicenseService enterpriseAPI.LicenseService
 	subscription   enterpriseAPI.Subscription
@@ -65,7 +79,8 @@ type Server struct {
 	startedTs  int64
 	secret     string
 
-	s3Client *s3bb.Client
+	// storageBackends are the storage backends configured for the server, keyed by the type.
+	storageBackends map[api.BackupStorageBackend]storage.Backend
 
 	// boot specifies that whether the server boot correctly
 	cancel context.CancelFunc
@@ -105,8 +120,9 @@ var casbinDeveloperPolicy string
 // NewServer creates a server.
 func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	s := &Server{
-		profile:   prof,
-		startedTs: time.Now().Unix(),
+		profile:    prof,
+		startedTs:  time.Now().Unix(),
+		driverPool: newDriverPool(),
 	}
 
 	// Display config
@@ -191,6 +207,21 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 		return nil, errors.Wrap(err, "failed to init config")
 	}
 	s.secret = config.secret
+	if prof.SecretKey != "" {
+		s.secret = prof.SecretKey
+	}
+	if err := s.loadMaintenance(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to load maintenance setting")
+	}
+	if err := s.loadProxy(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to load proxy setting")
+	}
+	if err := s.loadCACertificates(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to load CA certificates setting")
+	}
+	if s.AgentManager, err = NewAgentManager(ctx, s); err != nil {
+		return nil, errors.Wrap(err, "failed to create agent manager")
+	}
 
 	e := echo.New()
 	e.Debug = prof.Debug
@@ -205,16 +236,8 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	embedFrontend(e)
 	s.e = e
 
-	if prof.BackupBucket != "" {
-		credentials, err := s3bb.GetCredentialsFromFile(ctx, prof.BackupCredentialFile)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to get credentials from file")
-		}
-		s3Client, err := s3bb.NewClient(ctx, prof.BackupRegion, prof.BackupBucket, credentials)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to create AWS S3 client")
-		}
-		s.s3Client = s3Client
+	if err := s.initStorageBackends(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to init storage backends")
 	}
 
 	if !prof.Readonly {
@@ -241,6 +264,11 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 
 		taskScheduler.Register(api.TaskDatabaseRestorePITRCutover, NewPITRCutoverTaskExecutor)
 
+		// The executors registered by RegisterTaskExecutor panic on colliding with the built-in task types above.
+		for taskType, factory := range registeredTaskExecutors() {
+			taskScheduler.Register(taskType, factory)
+		}
+
 		s.TaskScheduler = taskScheduler
 
 		// Task check scheduler
@@ -256,6 +284,9 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 		statementTypeExecutor := NewTaskCheckStatementTypeExecutor()
 		taskCheckScheduler.Register(api.TaskCheckDatabaseStatementType, statementTypeExecutor)
 
+		statementDryRunExecutor := NewTaskCheckStatementDryRunExecutor()
+		taskCheckScheduler.Register(api.TaskCheckDatabaseStatementDryRun, statementDryRunExecutor)
+
 		databaseConnectExecutor := NewTaskCheckDatabaseConnectExecutor()
 		taskCheckScheduler.Register(api.TaskCheckDatabaseConnect, databaseConnectExecutor)
 
@@ -268,6 +299,9 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 		timingExecutor := NewTaskCheckTimingExecutor()
 		taskCheckScheduler.Register(api.TaskCheckGeneralEarliestAllowedTime, timingExecutor)
 
+		customExecutor := NewTaskCheckCustomExecutor()
+		taskCheckScheduler.Register(api.TaskCheckCustom, customExecutor)
+
 		s.TaskCheckScheduler = taskCheckScheduler
 
 		// Schema syncer
@@ -279,6 +313,15 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 		// Anomaly scanner
 		s.AnomalyScanner = NewAnomalyScanner(s)
 
+		// Schema snapshot runner
+		s.SchemaSnapshotRunner = NewSchemaSnapshotRunner(s)
+
+		// Recurring task runner
+		s.RecurringTaskRunner = NewRecurringTaskRunner(s)
+
+		// Report runner
+		s.ReportRunner = NewReportRunner(s)
+
 		// Metric reporter
 		s.initMetricReporter(config.workspaceID)
 	}
@@ -298,17 +341,28 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	e.Use(recoverMiddleware)
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
+	s.registerAgentRoutes(e)
+
 	webhookGroup := e.Group("/hook")
+	webhookGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return maintenanceMiddleware(s, next)
+	})
 	s.registerWebhookRoutes(webhookGroup)
 
 	openAPIGroup := e.Group(openAPIPrefix)
 	openAPIGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return openAPIMetricMiddleware(s, next)
 	})
+	openAPIGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return maintenanceMiddleware(s, next)
+	})
 
 	apiGroup := e.Group("/api")
 	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
-		return JWTMiddleware(s.store, next, prof.Mode, config.secret)
+		return JWTMiddleware(s.store, next, prof.Mode, s.secret)
+	})
+	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return maintenanceMiddleware(s, next)
 	})
 
 	m, err := model.NewModelFromString(casbinModel)
@@ -323,6 +377,9 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return aclMiddleware(s, ce, next, prof.Readonly)
 	})
+	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return idempotencyMiddleware(s, next)
+	})
 	s.registerDebugRoutes(apiGroup)
 	s.registerSettingRoutes(apiGroup)
 	s.registerActuatorRoutes(apiGroup)
@@ -333,21 +390,42 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	s.registerPolicyRoutes(apiGroup)
 	s.registerProjectRoutes(apiGroup)
 	s.registerProjectWebhookRoutes(apiGroup)
+	s.registerProjectSQLReviewOverrideRoutes(apiGroup)
+	s.registerReportSubscriptionRoutes(apiGroup)
 	s.registerProjectMemberRoutes(apiGroup)
 	s.registerEnvironmentRoutes(apiGroup)
 	s.registerInstanceRoutes(apiGroup)
+	s.registerInstanceAgentRoutes(apiGroup)
+	s.registerInstanceDiskUsageRoutes(apiGroup)
 	s.registerDatabaseRoutes(apiGroup)
+	s.registerDatabaseSecretRoutes(apiGroup)
+	s.registerDatabaseTemplateRoutes(apiGroup)
+	s.registerManagedGrantRoutes(apiGroup)
+	s.registerDBQueryGrantRoutes(apiGroup)
+	s.registerSchemaSnapshotRoutes(apiGroup)
 	s.registerIssueRoutes(apiGroup)
+	s.registerIssueViewRoutes(apiGroup)
 	s.registerIssueSubscriberRoutes(apiGroup)
+	s.registerStatementCommentRoutes(apiGroup)
+	s.registerIssueSLARoutes(apiGroup)
 	s.registerTaskRoutes(apiGroup)
+	s.registerTaskRunArtifactRoutes(apiGroup)
+	s.registerTaskRunProgressRoutes(apiGroup)
 	s.registerStageRoutes(apiGroup)
 	s.registerActivityRoutes(apiGroup)
 	s.registerInboxRoutes(apiGroup)
 	s.registerBookmarkRoutes(apiGroup)
+	s.registerRecurringTaskRoutes(apiGroup)
+	s.registerDataDiffRoutes(apiGroup)
 	s.registerSQLRoutes(apiGroup)
+	s.registerQueryAuditLogRoutes(apiGroup)
 	s.registerVCSRoutes(apiGroup)
 	s.registerLabelRoutes(apiGroup)
 	s.registerSubscriptionRoutes(apiGroup)
+	s.registerUsageRoutes(apiGroup)
+	s.registerMigrationStatRoutes(apiGroup)
+	s.registerWorkspaceRoutes(apiGroup)
+	s.registerWorkspaceConfigRoutes(apiGroup)
 	s.registerSheetRoutes(apiGroup)
 	s.registerSheetOrganizerRoutes(apiGroup)
 	s.registerOpenAPIRoutes(openAPIGroup)
@@ -363,6 +441,7 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	p.Use(e)
 
 	s.ActivityManager = NewActivityManager(s, storeInstance)
+	s.DataDiffManager = NewDataDiffManager(s)
 	s.LicenseService, err = enterpriseService.NewLicenseService(prof.Mode, s.store)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create license service")
@@ -380,20 +459,19 @@ func (s *Server) initSubscription() {
 }
 
 // initMetricReporter will initial the metric scheduler.
+// The metrics are always collected for the usage dashboard, the telemetry is only enabled in the release mode.
 func (s *Server) initMetricReporter(workspaceID string) {
-	enabled := s.profile.Mode == common.ReleaseModeProd && !s.profile.Demo && !s.profile.DisableMetric
-	if enabled {
-		metricReporter := NewMetricReporter(s, workspaceID)
-		metricReporter.Register(metric.InstanceCountMetricName, metricCollector.NewInstanceCountCollector(s.store))
-		metricReporter.Register(metric.IssueCountMetricName, metricCollector.NewIssueCountCollector(s.store))
-		metricReporter.Register(metric.ProjectCountMetricName, metricCollector.NewProjectCountCollector(s.store))
-		metricReporter.Register(metric.PolicyCountMetricName, metricCollector.NewPolicyCountCollector(s.store))
-		metricReporter.Register(metric.TaskCountMetricName, metricCollector.NewTaskCountCollector(s.store))
-		metricReporter.Register(metric.DatabaseCountMetricName, metricCollector.NewDatabaseCountCollector(s.store))
-		metricReporter.Register(metric.SheetCountMetricName, metricCollector.NewSheetCountCollector(s.store))
-		metricReporter.Register(metric.MemberCountMetricName, metricCollector.NewMemberCountCollector(s.store))
-		s.MetricReporter = metricReporter
-	}
+	metricReporter := NewMetricReporter(s, workspaceID)
+	metricReporter.Register(metric.InstanceCountMetricName, metricCollector.NewInstanceCountCollector(s.store))
+	metricReporter.Register(metric.IssueCountMetricName, metricCollector.NewIssueCountCollector(s.store))
+	metricReporter.Register(metric.WeeklyIssueCountMetricName, metricCollector.NewWeeklyIssueCountCollector(s.store))
+	metricReporter.Register(metric.ProjectCountMetricName, metricCollector.NewProjectCountCollector(s.store))
+	metricReporter.Register(metric.PolicyCountMetricName, metricCollector.NewPolicyCountCollector(s.store))
+	metricReporter.Register(metric.TaskCountMetricName, metricCollector.NewTaskCountCollector(s.store))
+	metricReporter.Register(metric.DatabaseCountMetricName, metricCollector.NewDatabaseCountCollector(s.store))
+	metricReporter.Register(metric.SheetCountMetricName, metricCollector.NewSheetCountCollector(s.store))
+	metricReporter.Register(metric.MemberCountMetricName, metricCollector.NewMemberCountCollector(s.store))
+	s.MetricReporter = metricReporter
 }
 
 func getInitSetting(ctx context.Context, store *store.Store) (*config, error) {
@@ -448,6 +526,132 @@ func getInitSetting(ctx context.Context, store *store.Store) (*config, error) {
 		return nil, err
 	}
 
+	// initial task concurrency limits
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingTaskConcurrencyGlobal,
+		Value:       "0",
+		Description: "The maximum number of tasks running simultaneously in the workspace, 0 means unlimited.",
+	}); err != nil {
+		return nil, err
+	}
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingTaskConcurrencyInstance,
+		Value:       "0",
+		Description: "The maximum number of tasks running simultaneously on a single instance, 0 means unlimited.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial task heartbeat timeout
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingTaskHeartbeatTimeoutSeconds,
+		Value:       strconv.Itoa(defaultTaskHeartbeatTimeoutSeconds),
+		Description: "The number of seconds without heartbeat after which the running task is considered orphaned, 0 means re-running the orphaned tasks right away.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial workspace metric settings
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceMetricOptOut,
+		Value:       "false",
+		Description: "Whether the workspace opts out of sending the usage metrics outside the workspace.",
+	}); err != nil {
+		return nil, err
+	}
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceMetricCollectorURL,
+		Value:       "",
+		Description: "The endpoint to receive the usage metrics as JSON, empty means not configured.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial maintenance mode
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingMaintenance,
+		Value:       `{"enabled":false,"message":""}`,
+		Description: "The read-only maintenance mode rejecting the mutating requests and pausing the background runners.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial query audit log retention
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingQueryAuditLogRetentionDays,
+		Value:       strconv.Itoa(defaultQueryAuditLogRetentionDays),
+		Description: "The number of days to keep the query audit logs of the SQL editor, 0 means keeping forever.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial instance disk full anomaly days
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingInstanceDiskFullDays,
+		Value:       strconv.Itoa(defaultInstanceDiskFullDays),
+		Description: "The anomaly is raised if the instance disk is projected to fill up within the number of days, 0 means disabled.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial SQL editor export max rows
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingSQLEditorExportMaxRows,
+		Value:       strconv.Itoa(defaultSQLExportMaxRows),
+		Description: "The maximum number of rows exported from the SQL editor at once, 0 means unlimited.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial workspace proxy, falling back to the proxy environment variables
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceProxy,
+		Value:       `{"httpProxy":"","httpsProxy":"","noProxy":[]}`,
+		Description: "The outbound HTTP proxy for the VCS providers and the webhooks, empty means using the proxy environment variables.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial workspace CA certificates
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceCACertificates,
+		Value:       "",
+		Description: "The PEM encoded CA certificates trusted besides the system ones for the VCS providers, the webhooks and the databases with SSL.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial SMTP server, empty host means not sending any email
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingMailSMTP,
+		Value:       `{"host":"","port":587,"username":"","password":"","from":""}`,
+		Description: "The SMTP server sending the emails, e.g. the weekly project reports, empty host means not configured.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial custom task check plugins, none by default
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingTaskCheckCustom,
+		Value:       `{"pluginList":[]}`,
+		Description: "The custom task check plugins run against the schema and data update tasks.",
+	}); err != nil {
+		return nil, err
+	}
+
 	return conf, nil
 }
 
@@ -455,6 +659,8 @@ func getInitSetting(ctx context.Context, store *store.Store) (*config, error) {
 func (s *Server) Run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	s.cancel = cancel
+	s.runnerWG.Add(1)
+	go s.driverPool.Run(ctx, &s.runnerWG)
 	if !s.profile.Readonly {
 		// runnerWG waits for all goroutines to complete.
 		s.runnerWG.Add(1)
@@ -467,6 +673,12 @@ func (s *Server) Run(ctx context.Context) error {
 		go s.BackupRunner.Run(ctx, &s.runnerWG)
 		s.runnerWG.Add(1)
 		go s.AnomalyScanner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.SchemaSnapshotRunner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.RecurringTaskRunner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.ReportRunner.Run(ctx, &s.runnerWG)
 
 		if s.MetricReporter != nil {
 			s.runnerWG.Add(1)
@@ -3,6 +3,8 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -15,6 +17,16 @@ var (
 	// Some settings contain secret info so we only return settings that are needed by the client.
 	whitelistSettings = []api.SettingName{
 		api.SettingBrandingLogo,
+		api.SettingTaskConcurrencyGlobal,
+		api.SettingTaskConcurrencyInstance,
+		api.SettingTaskHeartbeatTimeoutSeconds,
+		api.SettingWorkspaceMetricOptOut,
+		api.SettingWorkspaceMetricCollectorURL,
+		api.SettingMaintenance,
+		api.SettingQueryAuditLogRetentionDays,
+		api.SettingSQLEditorExportMaxRows,
+		api.SettingWorkspaceCACertificates,
+		api.SettingInstanceDiskFullDays,
 	}
 )
 
@@ -50,15 +62,73 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 			Name:      api.SettingName(c.Param("name")),
 			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
 		}
+		// The settings apply to the whole deployment, e.g. the SMTP server and the custom task check plugins,
+		// so only the owners of the default workspace can change them.
+		if c.Get(getWorkspaceIDContextKey()).(int) != api.DefaultWorkspaceID {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the owner of the default workspace can update the settings")
+		}
 
-		if settingPatch.Name == api.SettingBrandingLogo && !s.feature(api.FeatureBranding) {
-			return echo.NewHTTPError(http.StatusForbidden, api.FeatureBranding.AccessErrorMessage())
+		if settingPatch.Name == api.SettingBrandingLogo {
+			if err := s.checkFeature(api.FeatureBranding); err != nil {
+				return featureHTTPError(err)
+			}
 		}
 
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, settingPatch); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed update setting request").SetInternal(err)
 		}
 
+		if settingPatch.Name == api.SettingTaskConcurrencyGlobal || settingPatch.Name == api.SettingTaskConcurrencyInstance || settingPatch.Name == api.SettingQueryAuditLogRetentionDays || settingPatch.Name == api.SettingSQLEditorExportMaxRows || settingPatch.Name == api.SettingInstanceDiskFullDays {
+			if limit, err := strconv.Atoi(settingPatch.Value); err != nil || limit < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Setting %s must be a non-negative integer, got %q", settingPatch.Name, settingPatch.Value))
+			}
+		}
+		if settingPatch.Name == api.SettingTaskHeartbeatTimeoutSeconds {
+			if err := validateTaskHeartbeatTimeout(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid setting %s: %v", settingPatch.Name, err)).SetInternal(err)
+			}
+		}
+		if settingPatch.Name == api.SettingWorkspaceMetricOptOut {
+			if settingPatch.Value != "true" && settingPatch.Value != "false" {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Setting %s must be true or false, got %q", settingPatch.Name, settingPatch.Value))
+			}
+		}
+		if settingPatch.Name == api.SettingWorkspaceMetricCollectorURL && settingPatch.Value != "" {
+			if u, err := url.ParseRequestURI(settingPatch.Value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Setting %s must be an http or https URL, got %q", settingPatch.Name, settingPatch.Value))
+			}
+		}
+
+		var proxy *common.ProxyConfig
+		if settingPatch.Name == api.SettingWorkspaceProxy {
+			var err error
+			if proxy, err = parseProxySetting(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid setting %s: %v", settingPatch.Name, err)).SetInternal(err)
+			}
+		}
+		if settingPatch.Name == api.SettingWorkspaceCACertificates {
+			if _, err := common.ParseCACertificates(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid setting %s: %v", settingPatch.Name, err)).SetInternal(err)
+			}
+		}
+		if settingPatch.Name == api.SettingMailSMTP {
+			if _, err := parseSMTPSetting(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid setting %s: %v", settingPatch.Name, err)).SetInternal(err)
+			}
+		}
+		if settingPatch.Name == api.SettingTaskCheckCustom {
+			if _, err := parseTaskCheckCustomSetting(settingPatch.Value, s.profile.EnableTaskCheckCommand); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid setting %s: %v", settingPatch.Name, err)).SetInternal(err)
+			}
+		}
+		var maintenance *api.MaintenanceSetting
+		if settingPatch.Name == api.SettingMaintenance {
+			var err error
+			if maintenance, err = parseMaintenanceSetting(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Setting %s must be a JSON object with enabled and message, got %q", settingPatch.Name, settingPatch.Value)).SetInternal(err)
+			}
+		}
+
 		setting, err := s.store.PatchSetting(ctx, settingPatch)
 		if err != nil {
 			if common.ErrorCode(err) == common.NotFound {
@@ -67,6 +137,27 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update setting: %v", settingPatch.Name)).SetInternal(err)
 		}
 
+		// Apply the metric settings right away instead of waiting for the next report round.
+		if s.MetricReporter != nil && (settingPatch.Name == api.SettingWorkspaceMetricOptOut || settingPatch.Name == api.SettingWorkspaceMetricCollectorURL) {
+			s.MetricReporter.loadSetting(ctx)
+		}
+		if proxy != nil {
+			if err := common.SetProxyConfig(proxy); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update setting: %v", settingPatch.Name)).SetInternal(err)
+			}
+		}
+		// Hot-reload the CA certificates, the existing connections are kept until closed.
+		if settingPatch.Name == api.SettingWorkspaceCACertificates {
+			if err := common.SetCACertificates(setting.Value); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update setting: %v", settingPatch.Name)).SetInternal(err)
+			}
+		}
+		if maintenance != nil {
+			if err := s.updateMaintenance(ctx, setting, maintenance); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update setting: %v", settingPatch.Name)).SetInternal(err)
+			}
+		}
+
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal setting response").SetInternal(err)
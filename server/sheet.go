@@ -317,6 +317,9 @@ func (s *Server) registerSheetRoutes(g *echo.Group) {
 		}
 
 		sheetFind.PrincipalID = &currentPrincipalID
+		// The public sheets are only shared within the workspace.
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		sheetFind.WorkspaceID = &workspaceID
 
 		var sheetList []*api.Sheet
 		projectSheetVisibility := api.ProjectSheet
@@ -353,6 +356,8 @@ func (s *Server) registerSheetRoutes(g *echo.Group) {
 		}
 
 		sheetFind.OrganizerID = &currentPrincipalID
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		sheetFind.WorkspaceID = &workspaceID
 
 		starredSheetList, err := s.store.FindSheet(ctx, sheetFind, currentPrincipalID)
 		if err != nil {
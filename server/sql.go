@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
@@ -27,6 +31,13 @@ import (
 	"github.com/bytebase/bytebase/store"
 )
 
+const (
+	// defaultQueryTimeout is the timeout of the SQL editor query if the client doesn't request one.
+	defaultQueryTimeout = time.Minute
+	// maxQueryTimeout is the max timeout of the SQL editor query, so that the forgotten queries don't hold the connections.
+	maxQueryTimeout = 10 * time.Minute
+)
+
 func (s *Server) registerSQLRoutes(g *echo.Group) {
 	g.POST("/sql/ping", func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -37,6 +48,13 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		if err := s.disallowBytebaseStore(connectionInfo.Engine, connectionInfo.Host, connectionInfo.Port); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
+		// The saved password, SSL suite and SSH key are only lent to the instances of the workspace.
+		if connectionInfo.InstanceID != nil {
+			resourceList := []workspaceResource{{resourceType: workspaceResourceInstance, id: strconv.Itoa(*connectionInfo.InstanceID)}}
+			if err := s.checkRequestWorkspaceResource(ctx, c.Get(getWorkspaceIDContextKey()).(int), resourceList); err != nil {
+				return err
+			}
+		}
 
 		password := connectionInfo.Password
 		// Instance detail page has a Test Connection button, if user doesn't input new password and doesn't specify
@@ -72,17 +90,45 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusBadRequest, "TLS/SSL suite must all be set or not be set")
 			}
 		}
+		sshConfig := db.SSHConfig{
+			Host:       connectionInfo.SSHHost,
+			Port:       connectionInfo.SSHPort,
+			User:       connectionInfo.SSHUser,
+			PrivateKey: connectionInfo.SSHPrivateKey,
+			HostKey:    connectionInfo.SSHHostKey,
+		}
+		// Similar to the password, the existing private key is used if the user doesn't input a new one.
+		if sshConfig.Host != "" && sshConfig.PrivateKey == "" && connectionInfo.InstanceID != nil {
+			instance, err := s.store.GetInstanceByID(ctx, *connectionInfo.InstanceID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve SSH private key for instance: %d", *connectionInfo.InstanceID)).SetInternal(err)
+			}
+			if instance != nil {
+				sshConfig.PrivateKey = instance.SSHPrivateKey
+			}
+		}
+		if err := validateInstanceSSHConfig(connectionInfo.Engine, sshConfig); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid SSH config: %v", err)).SetInternal(err)
+		}
+		connCfg := db.ConnectionConfig{
+			Username:  connectionInfo.Username,
+			Password:  password,
+			Host:      connectionInfo.Host,
+			Port:      connectionInfo.Port,
+			TLSConfig: tlsConfig,
+			SSHConfig: sshConfig,
+		}
+		// The existing instance in agent mode is only reachable through its agent, which dials the saved instance address.
+		if connectionInfo.InstanceID != nil {
+			if err := s.AgentManager.applyAgent(*connectionInfo.InstanceID, &connCfg); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+		}
 		db, err := db.Open(
 			ctx,
 			connectionInfo.Engine,
 			db.DriverConfig{},
-			db.ConnectionConfig{
-				Username:  connectionInfo.Username,
-				Password:  password,
-				Host:      connectionInfo.Host,
-				Port:      connectionInfo.Port,
-				TLSConfig: tlsConfig,
-			},
+			connCfg,
 			db.ConnectionContext{},
 		)
 
@@ -123,7 +169,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %d", *sync.InstanceID)).SetInternal(err)
 			}
-			if instance == nil {
+			if instance == nil || instance.WorkspaceID != c.Get(getWorkspaceIDContextKey()).(int) {
 				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", *sync.InstanceID))
 			}
 			if err := s.syncEngineVersionAndSchema(ctx, instance); err != nil {
@@ -135,7 +181,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to database instance ID: %d", *sync.DatabaseID)).SetInternal(err)
 			}
-			if database == nil {
+			if database == nil || database.Instance.WorkspaceID != c.Get(getWorkspaceIDContextKey()).(int) {
 				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", *sync.DatabaseID))
 			}
 			if err := s.syncDatabaseSchema(ctx, database.Instance, database.Name); err != nil {
@@ -150,6 +196,41 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		return nil
 	})
 
+	g.POST("/sql/format", func(c echo.Context) error {
+		format := &api.SQLFormat{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, format); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql format request").SetInternal(err)
+		}
+
+		var engineType parser.EngineType
+		switch format.Engine {
+		case db.MySQL:
+			engineType = parser.MySQL
+		case db.TiDB:
+			engineType = parser.TiDB
+		case db.Postgres:
+			engineType = parser.Postgres
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Formatting is not supported for engine %s", format.Engine))
+		}
+
+		result := &api.SQLFormatResult{}
+		statement, err := parser.Format(engineType, format.Statement)
+		if err != nil {
+			// Return the statement as is, so that the caller can still display it.
+			result.Statement = format.Statement
+			result.Error = err.Error()
+		} else {
+			result.Statement = statement
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, result); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql format response").SetInternal(err)
+		}
+		return nil
+	})
+
 	g.POST("/sql/execute", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		exec := &api.SQLExecute{}
@@ -163,96 +244,99 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		if len(exec.Statement) == 0 {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, missing sql statement")
 		}
-		if !exec.Readonly {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support readonly sql statement")
+		if !exec.Readonly && exec.DatabaseName == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, missing databaseName for the write statement")
 		}
-		if !validateSQLSelectStatement(exec.Statement) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support SELECT sql statement")
+		if exec.Readonly && !validateSQLSelectStatement(exec.Statement) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support SELECT, SHOW and EXPLAIN sql statement")
 		}
 
 		instance, err := s.store.GetInstanceByID(ctx, exec.InstanceID)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", exec.InstanceID)).SetInternal(err)
 		}
-		if instance == nil {
+		// The instance of another workspace is reported as not found, like the resources addressed by the route.
+		if instance == nil || instance.WorkspaceID != c.Get(getWorkspaceIDContextKey()).(int) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exec.InstanceID))
 		}
+		if err := s.checkSQLEditorDatabasePermission(ctx, c, instance, exec); err != nil {
+			return err
+		}
+		if !exec.Readonly {
+			return s.executeSQLEditorWriteStatement(ctx, c, instance, exec)
+		}
 
-		adviceLevel := advisor.Success
-		adviceList := []advisor.Advice{}
-
-		if s.feature(api.FeatureSQLReviewPolicy) && api.IsSQLReviewSupported(instance.Engine, s.profile.Mode) {
-			dbType, err := advisorDB.ConvertToAdvisorDBType(string(instance.Engine))
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to convert db type %v into advisor db type", instance.Engine))
-			}
-
-			databaseFind := &api.DatabaseFind{
-				InstanceID: &instance.ID,
-				Name:       &exec.DatabaseName,
-			}
-			dbList, err := s.store.FindDatabase(ctx, databaseFind)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database `%s` for instance ID: %d", exec.DatabaseName, instance.ID)).SetInternal(err)
-			}
-			if len(dbList) == 0 {
-				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database `%s` for instance ID: %d not found", exec.DatabaseName, instance.ID))
+		adviceLevel, adviceList, err := s.reviewSQLEditorStatement(ctx, instance, exec)
+		if err != nil {
+			return err
+		}
+		if adviceLevel == advisor.Error {
+			if err := s.createSQLEditorQueryActivity(ctx, c, api.ActivityError, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
+				Statement:    exec.Statement,
+				DurationNs:   0,
+				InstanceName: instance.Name,
+				DatabaseName: exec.DatabaseName,
+				Error:        "",
+				AdviceList:   adviceList,
+			}); err != nil {
+				return err
 			}
-			if len(dbList) > 1 {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("There are multiple database `%s` for instance ID: %d", exec.DatabaseName, instance.ID))
+			if err := s.createQueryAuditLog(ctx, c, &api.QueryAuditLogCreate{
+				InstanceID:   exec.InstanceID,
+				Type:         api.QueryAuditLogQuery,
+				DatabaseName: exec.DatabaseName,
+				Statement:    exec.Statement,
+				Error:        "Rejected by the SQL review policy",
+			}); err != nil {
+				return err
 			}
-			db := dbList[0]
-
-			adviceLevel, adviceList, err = s.sqlCheck(
-				ctx,
-				dbType,
-				db.CharacterSet,
-				db.Collation,
-				instance.EnvironmentID,
-				exec.Statement,
-				store.NewCatalog(&db.ID, s.store, instance.Engine),
-			)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check SQL review policy").SetInternal(err)
-			}
-
-			if adviceLevel == advisor.Error {
-				if err := s.createSQLEditorQueryActivity(ctx, c, api.ActivityError, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
-					Statement:    exec.Statement,
-					DurationNs:   0,
-					InstanceName: instance.Name,
-					DatabaseName: exec.DatabaseName,
-					Error:        "",
-					AdviceList:   adviceList,
-				}); err != nil {
-					return err
-				}
 
-				resultSet := &api.SQLResultSet{
-					AdviceList: adviceList,
-				}
+			resultSet := &api.SQLResultSet{
+				AdviceList: adviceList,
+			}
 
-				c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
-				if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
-					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
-				}
-				return nil
+			c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+			if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
 			}
+			return nil
+		}
+
+		queryContext, err := s.getQueryContext(ctx, c.Get(getRoleContextKey()).(api.Role), instance.EnvironmentID, exec.Limit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the query limit for environment ID: %d", instance.EnvironmentID)).SetInternal(err)
 		}
 
+		queryTimeout := getQueryTimeout(exec.Timeout)
 		start := time.Now().UnixNano()
 
+		var rowCount int64
+		var columnNameList []string
 		bytes, queryErr := func() ([]byte, error) {
-			driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName)
+			driver, err := s.tryGetReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName)
 			if err != nil {
 				return nil, err
 			}
 			defer driver.Close(ctx)
 
-			rowSet, err := driver.Query(ctx, exec.Statement, exec.Limit)
+			queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+			defer cancel()
+			rowSet, err := driver.Query(queryCtx, exec.Statement, queryContext)
 			if err != nil {
+				if queryCtx.Err() == context.DeadlineExceeded {
+					return nil, errors.Errorf("the query is canceled after exceeding the timeout of %v", queryTimeout)
+				}
 				return nil, err
 			}
+			// The row set consists of the column names, the column types and the rows.
+			if len(rowSet) == 3 {
+				if rows, ok := rowSet[2].([]interface{}); ok {
+					rowCount = int64(len(rows))
+				}
+				if names, ok := rowSet[0].([]string); ok {
+					columnNameList = names
+				}
+			}
 
 			return json.Marshal(rowSet)
 		}()
@@ -295,9 +379,10 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			level = api.ActivityError
 			errMessage = queryErr.Error()
 		}
+		durationNs := time.Now().UnixNano() - start
 		if err := s.createSQLEditorQueryActivity(ctx, c, level, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
 			Statement:    exec.Statement,
-			DurationNs:   time.Now().UnixNano() - start,
+			DurationNs:   durationNs,
 			InstanceName: instance.Name,
 			DatabaseName: exec.DatabaseName,
 			Error:        errMessage,
@@ -305,10 +390,31 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		}); err != nil {
 			return err
 		}
+		if err := s.createQueryAuditLog(ctx, c, &api.QueryAuditLogCreate{
+			InstanceID:   exec.InstanceID,
+			Type:         api.QueryAuditLogQuery,
+			DatabaseName: exec.DatabaseName,
+			Statement:    exec.Statement,
+			RowCount:     rowCount,
+			DurationNs:   durationNs,
+			Error:        errMessage,
+		}); err != nil {
+			return err
+		}
 
 		resultSet := &api.SQLResultSet{AdviceList: adviceList}
 		if queryErr == nil {
 			resultSet.Data = string(bytes)
+			// The column metadata is informational, so the failure doesn't fail the query.
+			columnList, err := s.getSQLResultColumnList(ctx, instance, exec, columnNameList)
+			if err != nil {
+				log.Warn("Failed to get the column metadata of the query result",
+					zap.Int("instance_id", instance.ID),
+					zap.String("database_name", exec.DatabaseName),
+					zap.Error(err),
+				)
+			}
+			resultSet.ColumnList = columnList
 			log.Debug("Query result",
 				zap.String("statement", exec.Statement),
 				zap.String("data", resultSet.Data),
@@ -337,10 +443,141 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	// The export reads the rows of the query result and writes them as a CSV or JSON attachment as they are read,
+	// so that the large results aren't kept in memory. The failure after the first row can't change the response status,
+	// the attachment is truncated and the error is recorded in the query audit log.
+	g.POST("/sql/export", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		export := &api.SQLExport{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, export); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql export request").SetInternal(err)
+		}
+
+		if err := validateSQLExport(export); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql export request, %v", err))
+		}
+		resultWriter, err := newSQLResultWriter(export.Format, c.Response())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql export request, %v", err))
+		}
+
+		instance, err := s.store.GetInstanceByID(ctx, export.InstanceID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", export.InstanceID)).SetInternal(err)
+		}
+		// The instance of another workspace is reported as not found, like the resources addressed by the route.
+		if instance == nil || instance.WorkspaceID != c.Get(getWorkspaceIDContextKey()).(int) {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", export.InstanceID))
+		}
+		exec := &api.SQLExecute{
+			InstanceID:   export.InstanceID,
+			DatabaseName: export.DatabaseName,
+			Statement:    export.Statement,
+			Readonly:     true,
+			Limit:        export.Limit,
+			Timeout:      export.Timeout,
+		}
+		if err := s.checkSQLEditorDatabasePermission(ctx, c, instance, exec); err != nil {
+			return err
+		}
+
+		adviceLevel, _, err := s.reviewSQLEditorStatement(ctx, instance, exec)
+		if err != nil {
+			return err
+		}
+		if adviceLevel == advisor.Error {
+			if err := s.createQueryAuditLog(ctx, c, &api.QueryAuditLogCreate{
+				InstanceID:   export.InstanceID,
+				Type:         api.QueryAuditLogExport,
+				DatabaseName: export.DatabaseName,
+				Statement:    export.Statement,
+				Error:        "Rejected by the SQL review policy",
+			}); err != nil {
+				return err
+			}
+			return echo.NewHTTPError(http.StatusBadRequest, "The statement is rejected by the SQL review policy, please check it in the SQL editor")
+		}
+
+		queryContext, err := s.getQueryContext(ctx, c.Get(getRoleContextKey()).(api.Role), instance.EnvironmentID, export.Limit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the query limit for environment ID: %d", instance.EnvironmentID)).SetInternal(err)
+		}
+		maxRows, err := s.getSQLExportMaxRows(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the export max rows setting").SetInternal(err)
+		}
+		limit := getSQLExportLimit(queryContext.Limit, maxRows)
+
+		queryTimeout := getQueryTimeout(export.Timeout)
+		start := time.Now()
+
+		rowCount, queryErr := func() (int64, error) {
+			driver, err := s.tryGetReadOnlyDatabaseDriver(ctx, instance, export.DatabaseName)
+			if err != nil {
+				return 0, err
+			}
+			defer driver.Close(ctx)
+			sqldb, err := driver.GetDBConnection(ctx, export.DatabaseName)
+			if err != nil {
+				return 0, err
+			}
+
+			queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+			defer cancel()
+			rowCount, err := util.StreamQuery(queryCtx, sqldb, export.Statement, limit, func(columnNames []string) error {
+				c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", getSQLExportFilename(export.DatabaseName, export.Format, start)))
+				if export.Format == api.SQLExportFormatJSON {
+					c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+				} else {
+					c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=UTF-8")
+				}
+				c.Response().WriteHeader(http.StatusOK)
+				return resultWriter.writeHeader(columnNames)
+			}, resultWriter.writeRow)
+			if err != nil {
+				if queryCtx.Err() == context.DeadlineExceeded {
+					return rowCount, errors.Errorf("the export is canceled after exceeding the timeout of %v", queryTimeout)
+				}
+				return rowCount, err
+			}
+			return rowCount, resultWriter.close()
+		}()
+
+		errMessage := ""
+		if queryErr != nil {
+			errMessage = queryErr.Error()
+		}
+		auditErr := s.createQueryAuditLog(ctx, c, &api.QueryAuditLogCreate{
+			InstanceID:   export.InstanceID,
+			Type:         api.QueryAuditLogExport,
+			DatabaseName: export.DatabaseName,
+			Statement:    export.Statement,
+			RowCount:     rowCount,
+			DurationNs:   time.Since(start).Nanoseconds(),
+			Error:        errMessage,
+		})
+		if c.Response().Committed {
+			// The attachment has been sent, the failures are logged and can't be reported in the response any more.
+			if queryErr != nil {
+				log.Warn("Failed to export the query result",
+					zap.Int("instance_id", instance.ID),
+					zap.String("database_name", export.DatabaseName),
+					zap.Int64("row_count", rowCount),
+					zap.Error(queryErr),
+				)
+			}
+			return nil
+		}
+		if queryErr != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to export the query result: %v", queryErr)).SetInternal(queryErr)
+		}
+		return auditErr
+	})
 }
 
 func (s *Server) syncEngineVersionAndSchema(ctx context.Context, instance *api.Instance) error {
-	driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, "")
+	driver, err := s.tryGetReadOnlyDatabaseDriver(ctx, instance, "")
 	if err != nil {
 		return err
 	}
@@ -512,7 +749,7 @@ func (s *Server) syncInstanceSchema(ctx context.Context, instance *api.Instance,
 }
 
 func (s *Server) syncDatabaseSchema(ctx context.Context, instance *api.Instance, databaseName string) error {
-	driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, "")
+	driver, err := s.tryGetReadOnlyDatabaseDriver(ctx, instance, "")
 	if err != nil {
 		return err
 	}
@@ -585,7 +822,20 @@ func (s *Server) syncDatabaseSchema(ctx context.Context, instance *api.Instance,
 	if err := syncViewSchema(ctx, s.store, database, schema); err != nil {
 		return err
 	}
-	return syncDBExtensionSchema(ctx, s.store, database, schema)
+	if err := syncDBExtensionSchema(ctx, s.store, database, schema); err != nil {
+		return err
+	}
+	if err := syncDBSchemaSchema(ctx, s.store, database, schema); err != nil {
+		return err
+	}
+	// The grant drift doesn't fail the sync, because the schema has been synced already.
+	if err := s.checkManagedGrantDrift(ctx, instance, database); err != nil {
+		log.Warn("Failed to check managed grant drift",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.Error(err))
+	}
+	return nil
 }
 
 func syncTableSchema(ctx context.Context, store *store.Store, database *api.Database, schema *db.Schema) error {
@@ -600,6 +850,10 @@ func syncDBExtensionSchema(ctx context.Context, store *store.Store, database *ap
 	return store.SetDBExtensionList(ctx, schema, database.ID)
 }
 
+func syncDBSchemaSchema(ctx context.Context, store *store.Store, database *api.Database, schema *db.Schema) error {
+	return store.SetDBSchemaList(ctx, schema, database.ID)
+}
+
 func getLatestSchemaVersion(ctx context.Context, driver db.Driver, databaseName string) (string, error) {
 	// TODO(d): support semantic versioning.
 	limit := 1
@@ -617,21 +871,284 @@ func getLatestSchemaVersion(ctx context.Context, driver db.Driver, databaseName
 	return schemaVersion, nil
 }
 
-func validateSQLSelectStatement(sqlStatement string) bool {
-	// Check if the query has only one statement.
-	count := 0
-	if err := util.ApplyMultiStatements(strings.NewReader(sqlStatement), func(_ string) error {
-		count++
+// reviewSQLEditorStatement checks the statement against the SQL review policy of the environment,
+// and returns the advice level and the advice list. It's a success without advice if the SQL review isn't enabled.
+func (s *Server) reviewSQLEditorStatement(ctx context.Context, instance *api.Instance, exec *api.SQLExecute) (advisor.Status, []advisor.Advice, error) {
+	if !s.feature(api.FeatureSQLReviewPolicy) || !api.IsSQLReviewSupported(instance.Engine, s.profile.Mode) {
+		return advisor.Success, []advisor.Advice{}, nil
+	}
+
+	dbType, err := advisorDB.ConvertToAdvisorDBType(string(instance.Engine))
+	if err != nil {
+		return advisor.Error, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to convert db type %v into advisor db type", instance.Engine))
+	}
+
+	databaseFind := &api.DatabaseFind{
+		InstanceID: &instance.ID,
+		Name:       &exec.DatabaseName,
+	}
+	dbList, err := s.store.FindDatabase(ctx, databaseFind)
+	if err != nil {
+		return advisor.Error, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database `%s` for instance ID: %d", exec.DatabaseName, instance.ID)).SetInternal(err)
+	}
+	if len(dbList) == 0 {
+		return advisor.Error, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database `%s` for instance ID: %d not found", exec.DatabaseName, instance.ID))
+	}
+	if len(dbList) > 1 {
+		return advisor.Error, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("There are multiple database `%s` for instance ID: %d", exec.DatabaseName, instance.ID))
+	}
+	db := dbList[0]
+
+	adviceLevel, adviceList, err := s.sqlCheck(
+		ctx,
+		dbType,
+		instance.EngineVersion,
+		db.CharacterSet,
+		db.Collation,
+		instance.EnvironmentID,
+		exec.Statement,
+		store.NewCatalog(&db.ID, s.store, instance.Engine),
+	)
+	if err != nil {
+		return advisor.Error, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check SQL review policy").SetInternal(err)
+	}
+	return adviceLevel, adviceList, nil
+}
+
+// checkSQLEditorDatabasePermission checks the permission of the caller on the current database and every database
+// referenced by the statement in the same instance, e.g. db1.t JOIN db2.t, and returns the HTTP error otherwise.
+// Developers can only query the databases with an active query grant of themselves or their role.
+// The write statement requires an active elevated write access of the caller on every database, regardless of the role.
+// The current database is required whenever the grant is checked.
+func (s *Server) checkSQLEditorDatabasePermission(ctx context.Context, c echo.Context, instance *api.Instance, exec *api.SQLExecute) error {
+	role := c.Get(getRoleContextKey()).(api.Role)
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	// Without the database name, the connection falls back to the default database of the login user, e.g. on Postgres,
+	// which isn't checked against the grants.
+	if exec.DatabaseName == "" && (role == api.Developer || !exec.Readonly) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Database name is required to check the grant of the database")
+	}
+
+	var databaseNameList []string
+	if exec.DatabaseName != "" {
+		databaseNameList = append(databaseNameList, exec.DatabaseName)
+	}
+	if instance.Engine == db.MySQL || instance.Engine == db.TiDB {
+		referencedList, err := extractMySQLReferencedDatabaseList(exec.Statement)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to parse the statement: %v", err)).SetInternal(err)
+		}
+		for _, name := range referencedList {
+			if name != exec.DatabaseName {
+				databaseNameList = append(databaseNameList, name)
+			}
+		}
+	}
+
+	for _, name := range databaseNameList {
+		databaseName := name
+		dbList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{
+			InstanceID: &instance.ID,
+			Name:       &databaseName,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database `%s` for instance ID: %d", databaseName, instance.ID)).SetInternal(err)
+		}
+		if len(dbList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database `%s` for instance ID: %d not found", databaseName, instance.ID))
+		}
+		if !exec.Readonly {
+			granted, err := s.checkDBQueryGrant(ctx, dbList[0].ID, principalID, role, api.DBQueryGrantAccessWrite)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check elevated write access for database `%s`", databaseName)).SetInternal(err)
+			}
+			if !granted {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Not allowed to write database `%s`, request the elevated write access of the database first", databaseName))
+			}
+			continue
+		}
+		if role != api.Developer {
+			continue
+		}
+		granted, err := s.checkDBQueryGrant(ctx, dbList[0].ID, principalID, role, api.DBQueryGrantAccessQuery)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check query grant for database `%s`", databaseName)).SetInternal(err)
+		}
+		if !granted {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Not allowed to query database `%s`, request the query grant of the database first", databaseName))
+		}
+	}
+	return nil
+}
+
+// executeSQLEditorWriteStatement executes the write statement with the admin data source, which requires the elevated write access.
+// The execution is recorded as a warning activity and an EXECUTE query audit log.
+func (s *Server) executeSQLEditorWriteStatement(ctx context.Context, c echo.Context, instance *api.Instance, exec *api.SQLExecute) error {
+	queryTimeout := getQueryTimeout(exec.Timeout)
+	start := time.Now().UnixNano()
+	execErr := func() error {
+		driver, err := s.getAdminDatabaseDriver(ctx, instance, exec.DatabaseName)
+		if err != nil {
+			return err
+		}
+		defer driver.Close(ctx)
+
+		execCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+		defer cancel()
+		if err := driver.Execute(execCtx, exec.Statement); err != nil {
+			if execCtx.Err() == context.DeadlineExceeded {
+				return errors.Errorf("the statement is canceled after exceeding the timeout of %v", queryTimeout)
+			}
+			return err
+		}
 		return nil
+	}()
+	durationNs := time.Now().UnixNano() - start
+
+	level := api.ActivityWarn
+	errMessage := ""
+	if execErr != nil {
+		level = api.ActivityError
+		errMessage = execErr.Error()
+	}
+	if err := s.createSQLEditorQueryActivity(ctx, c, level, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
+		Statement:    exec.Statement,
+		DurationNs:   durationNs,
+		InstanceName: instance.Name,
+		DatabaseName: exec.DatabaseName,
+		Error:        errMessage,
+	}); err != nil {
+		return err
+	}
+	if err := s.createQueryAuditLog(ctx, c, &api.QueryAuditLogCreate{
+		InstanceID:   exec.InstanceID,
+		Type:         api.QueryAuditLogExecute,
+		DatabaseName: exec.DatabaseName,
+		Statement:    exec.Statement,
+		DurationNs:   durationNs,
+		Error:        errMessage,
 	}); err != nil {
+		return err
+	}
+
+	resultSet := &api.SQLResultSet{}
+	if execErr != nil {
+		resultSet.Error = execErr.Error()
+	}
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
+	}
+	return nil
+}
+
+// isProjectMember returns true if the principal is a member of the project.
+func isProjectMember(project *api.Project, principalID int) bool {
+	if project == nil {
 		return false
 	}
-	if count != 1 {
+	for _, member := range project.ProjectMemberList {
+		if member.PrincipalID == principalID {
+			return true
+		}
+	}
+	return false
+}
+
+// mysqlSystemDatabases are the databases of MySQL and TiDB which are not synced, and are queryable by everyone.
+var mysqlSystemDatabases = map[string]bool{
+	"information_schema": true,
+	"metrics_schema":     true,
+	"mysql":              true,
+	"performance_schema": true,
+	"sys":                true,
+}
+
+// mysqlTableNameVisitor collects the table names referenced by the statement.
+type mysqlTableNameVisitor struct {
+	tableNameList []*tidbast.TableName
+	// showDatabaseList is the databases referenced by the SHOW statements without a table, e.g. SHOW TABLES FROM db.
+	showDatabaseList []string
+}
+
+func (v *mysqlTableNameVisitor) Enter(in tidbast.Node) (tidbast.Node, bool) {
+	switch node := in.(type) {
+	case *tidbast.TableName:
+		v.tableNameList = append(v.tableNameList, node)
+	case *tidbast.ShowStmt:
+		if node.DBName != "" {
+			v.showDatabaseList = append(v.showDatabaseList, node.DBName)
+		}
+	}
+	return in, false
+}
+
+func (*mysqlTableNameVisitor) Leave(in tidbast.Node) (tidbast.Node, bool) {
+	return in, true
+}
+
+// parseMySQLTableNameList parses the MySQL statement and returns the table names referenced by it.
+func parseMySQLTableNameList(statement string) ([]*tidbast.TableName, error) {
+	visitor, err := visitMySQLTableNameList(statement)
+	if err != nil {
+		return nil, err
+	}
+	return visitor.tableNameList, nil
+}
+
+func visitMySQLTableNameList(statement string) (*mysqlTableNameVisitor, error) {
+	p := tidbparser.New()
+	// To support MySQL8 window function syntax.
+	// See https://github.com/bytebase/bytebase/issues/175.
+	p.EnableWindowFunc(true)
+	nodeList, _, err := p.Parse(statement, "", "")
+	if err != nil {
+		return nil, err
+	}
+	visitor := &mysqlTableNameVisitor{}
+	for _, node := range nodeList {
+		node.Accept(visitor)
+	}
+	return visitor, nil
+}
+
+// extractMySQLReferencedDatabaseList extracts the databases explicitly referenced by the MySQL statement, excluding the system databases.
+// The result is deduplicated and sorted.
+func extractMySQLReferencedDatabaseList(statement string) ([]string, error) {
+	visitor, err := visitMySQLTableNameList(statement)
+	if err != nil {
+		return nil, err
+	}
+	databaseMap := make(map[string]bool)
+	for _, table := range visitor.tableNameList {
+		if table.Schema.O != "" && !mysqlSystemDatabases[table.Schema.L] {
+			databaseMap[table.Schema.O] = true
+		}
+	}
+	for _, database := range visitor.showDatabaseList {
+		if !mysqlSystemDatabases[strings.ToLower(database)] {
+			databaseMap[database] = true
+		}
+	}
+	var databaseList []string
+	for database := range databaseMap {
+		databaseList = append(databaseList, database)
+	}
+	sort.Strings(databaseList)
+	return databaseList, nil
+}
+
+// validateSQLSelectStatement returns whether the statement is a single SELECT, SHOW or EXPLAIN statement.
+func validateSQLSelectStatement(sqlStatement string) bool {
+	// Check if the query has only one statement. Any `;` other than the trailing ones is rejected, even in a string
+	// literal or a comment, because the quoting and comment syntax differs across the engines and a statement smuggled
+	// after the `;` would run with the readonly check bypassed.
+	if strings.Contains(strings.TrimRight(strings.TrimSpace(sqlStatement), "; \t\r\n"), ";") {
 		return false
 	}
 
-	// Allow SELECT and EXPLAIN queries only.
-	whiteListRegs := []string{`^SELECT\s+?`, `^EXPLAIN\s+?`}
+	// Allow SELECT, SHOW and EXPLAIN queries only.
+	whiteListRegs := []string{`^SELECT\s+?`, `^SHOW\s+?`, `^EXPLAIN\s+?`}
 	formattedStr := strings.ToUpper(strings.TrimSpace(sqlStatement))
 	for _, reg := range whiteListRegs {
 		matchResult, _ := regexp.MatchString(reg, formattedStr)
@@ -642,6 +1159,38 @@ func validateSQLSelectStatement(sqlStatement string) bool {
 	return false
 }
 
+// getQueryTimeout returns the timeout of the SQL editor query with the timeout in seconds requested by the client.
+func getQueryTimeout(requestTimeout int) time.Duration {
+	if requestTimeout <= 0 {
+		return defaultQueryTimeout
+	}
+	if timeout := time.Duration(requestTimeout) * time.Second; timeout < maxQueryTimeout {
+		return timeout
+	}
+	return maxQueryTimeout
+}
+
+// getQueryContext returns the query context limiting the result by the query limit policy of the environment for the role.
+// The row limit requested by the client applies if it's stricter.
+func (s *Server) getQueryContext(ctx context.Context, role api.Role, environmentID int, requestLimit int) (*db.QueryContext, error) {
+	policy, err := s.store.GetQueryLimitPolicyByEnvID(ctx, environmentID)
+	if err != nil {
+		return nil, err
+	}
+	return getQueryContextByLimit(policy.GetQueryLimit(role), requestLimit), nil
+}
+
+func getQueryContextByLimit(limit api.QueryLimit, requestLimit int) *db.QueryContext {
+	queryContext := &db.QueryContext{
+		Limit:    requestLimit,
+		MaxBytes: limit.MaxBytes,
+	}
+	if limit.MaxRows > 0 && (queryContext.Limit <= 0 || queryContext.Limit > limit.MaxRows) {
+		queryContext.Limit = limit.MaxRows
+	}
+	return queryContext
+}
+
 func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Context, level api.ActivityLevel, containerID int, payload api.ActivitySQLEditorQueryPayload) error {
 	activityBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -677,6 +1226,7 @@ func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Contex
 func (s *Server) sqlCheck(
 	ctx context.Context,
 	dbType advisorDB.Type,
+	engineVersion string,
 	dbCharacterSet string,
 	dbCollation string,
 	environmentID int,
@@ -701,10 +1251,11 @@ func (s *Server) sqlCheck(
 	}
 
 	res, err := advisor.SQLReviewCheck(statement, policy.RuleList, advisor.SQLReviewCheckContext{
-		Charset:   dbCharacterSet,
-		Collation: dbCollation,
-		DbType:    dbType,
-		Catalog:   catalog,
+		Charset:       dbCharacterSet,
+		Collation:     dbCollation,
+		DbType:        dbType,
+		EngineVersion: engineVersion,
+		Catalog:       catalog,
 	})
 	if err != nil {
 		return advisor.Error, nil, err
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+const (
+	// defaultSQLExportMaxRows is the default max rows of a single SQL editor export.
+	defaultSQLExportMaxRows = 100000
+)
+
+var (
+	sqlExportFilenameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_\-]+`)
+)
+
+// sqlResultWriter writes the query result rows in the export format while they are read from the database.
+type sqlResultWriter interface {
+	writeHeader(columnNames []string) error
+	writeRow(rowData []interface{}) error
+	// close writes the remaining output, it's not called if the query fails.
+	close() error
+}
+
+func newSQLResultWriter(format api.SQLExportFormat, w io.Writer) (sqlResultWriter, error) {
+	switch format {
+	case api.SQLExportFormatCSV:
+		return &csvResultWriter{writer: csv.NewWriter(w)}, nil
+	case api.SQLExportFormatJSON:
+		return &jsonResultWriter{writer: w}, nil
+	}
+	return nil, errors.Errorf("unsupported export format %q", format)
+}
+
+type csvResultWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvResultWriter) writeHeader(columnNames []string) error {
+	return w.writer.Write(columnNames)
+}
+
+func (w *csvResultWriter) writeRow(rowData []interface{}) error {
+	record := make([]string, len(rowData))
+	for i, v := range rowData {
+		record[i] = formatSQLExportValue(v)
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvResultWriter) close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// jsonResultWriter writes the rows as the objects keyed by the column names, in the column order.
+type jsonResultWriter struct {
+	writer      io.Writer
+	columnNames []string
+	rowCount    int
+}
+
+func (w *jsonResultWriter) writeHeader(columnNames []string) error {
+	w.columnNames = columnNames
+	_, err := io.WriteString(w.writer, "[")
+	return err
+}
+
+func (w *jsonResultWriter) writeRow(rowData []interface{}) error {
+	buf := []byte("{")
+	if w.rowCount > 0 {
+		buf = []byte(",\n{")
+	}
+	for i, v := range rowData {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		name, err := json.Marshal(w.columnNames[i])
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, name...)
+		buf = append(buf, ':')
+		buf = append(buf, value...)
+	}
+	buf = append(buf, '}')
+	w.rowCount++
+	_, err := w.writer.Write(buf)
+	return err
+}
+
+func (w *jsonResultWriter) close() error {
+	_, err := io.WriteString(w.writer, "]\n")
+	return err
+}
+
+// validateSQLExport validates the export request, the statement must be a single readonly statement like the SQL editor query.
+func validateSQLExport(export *api.SQLExport) error {
+	if export.InstanceID == 0 {
+		return errors.New("missing instanceId")
+	}
+	if len(export.Statement) == 0 {
+		return errors.New("missing sql statement")
+	}
+	if !validateSQLSelectStatement(export.Statement) {
+		return errors.New("only support SELECT, SHOW and EXPLAIN sql statement")
+	}
+	if export.Format != api.SQLExportFormatCSV && export.Format != api.SQLExportFormatJSON {
+		return errors.Errorf("unsupported export format %q", export.Format)
+	}
+	return nil
+}
+
+// formatSQLExportValue formats the value of a CSV cell, NULL is an empty cell.
+func formatSQLExportValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// getSQLExportLimit returns the max rows of the export, the smaller of the query limit and the export max rows.
+// A limit <= 0 is not enforced, and it returns 0 if neither is enforced.
+func getSQLExportLimit(queryLimit int, maxRows int) int {
+	if maxRows > 0 && (queryLimit <= 0 || queryLimit > maxRows) {
+		return maxRows
+	}
+	if queryLimit < 0 {
+		return 0
+	}
+	return queryLimit
+}
+
+// getSQLExportFilename returns the attachment filename of the export, e.g. employee-20220928T150405.csv.
+func getSQLExportFilename(databaseName string, format api.SQLExportFormat, exportTime time.Time) string {
+	name := sqlExportFilenameRegexp.ReplaceAllString(databaseName, "_")
+	if name == "" {
+		name = "query"
+	}
+	extension := "csv"
+	if format == api.SQLExportFormatJSON {
+		extension = "json"
+	}
+	return fmt.Sprintf("%s-%s.%s", name, exportTime.Format("20060102T150405"), extension)
+}
+
+// getSQLExportMaxRows returns the export max rows setting, 0 means unlimited.
+func (s *Server) getSQLExportMaxRows(ctx context.Context) (int, error) {
+	settingName := api.SettingSQLEditorExportMaxRows
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return 0, err
+	}
+	if len(settingList) == 0 {
+		return defaultSQLExportMaxRows, nil
+	}
+	maxRows, err := strconv.Atoi(settingList[0].Value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid setting %s %q", settingName, settingList[0].Value)
+	}
+	return maxRows, nil
+}
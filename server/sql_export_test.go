@@ -0,0 +1,139 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestSQLResultWriter(t *testing.T) {
+	columnNames := []string{"id", "name", "score", "active", "note"}
+	rowList := [][]interface{}{
+		{int64(1), "Alice, Jr.", 9.5, true, nil},
+		{int64(2), `Bob "B"`, float64(10), false, "line1\nline2"},
+	}
+	tests := []struct {
+		format api.SQLExportFormat
+		want   string
+	}{
+		{
+			format: api.SQLExportFormatCSV,
+			want: "id,name,score,active,note\n" +
+				"1,\"Alice, Jr.\",9.5,true,\n" +
+				"2,\"Bob \"\"B\"\"\",10,false,\"line1\nline2\"\n",
+		},
+		{
+			format: api.SQLExportFormatJSON,
+			want: `[{"id":1,"name":"Alice, Jr.","score":9.5,"active":true,"note":null},` + "\n" +
+				`{"id":2,"name":"Bob \"B\"","score":10,"active":false,"note":"line1\nline2"}]` + "\n",
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		var buf bytes.Buffer
+		writer, err := newSQLResultWriter(test.format, &buf)
+		a.NoError(err)
+		a.NoError(writer.writeHeader(columnNames))
+		for _, row := range rowList {
+			a.NoError(writer.writeRow(row))
+		}
+		a.NoError(writer.close())
+		a.Equal(test.want, buf.String(), test.format)
+	}
+
+	var buf bytes.Buffer
+	writer, err := newSQLResultWriter(api.SQLExportFormatJSON, &buf)
+	a.NoError(err)
+	a.NoError(writer.writeHeader(columnNames))
+	a.NoError(writer.close())
+	a.Equal("[]\n", buf.String())
+
+	_, err = newSQLResultWriter("XML", &buf)
+	a.Error(err)
+}
+
+func TestValidateSQLExport(t *testing.T) {
+	tests := []struct {
+		export  *api.SQLExport
+		wantErr bool
+	}{
+		{
+			export:  &api.SQLExport{InstanceID: 1, Statement: "SELECT * FROM t;", Format: api.SQLExportFormatCSV},
+			wantErr: false,
+		},
+		{
+			export:  &api.SQLExport{InstanceID: 1, Statement: "SHOW TABLES", Format: api.SQLExportFormatJSON},
+			wantErr: false,
+		},
+		{
+			export:  &api.SQLExport{InstanceID: 1, Statement: "SELECT 1; DELETE FROM t", Format: api.SQLExportFormatCSV},
+			wantErr: true,
+		},
+		{
+			export:  &api.SQLExport{InstanceID: 1, Statement: "DELETE FROM t", Format: api.SQLExportFormatCSV},
+			wantErr: true,
+		},
+		{
+			export:  &api.SQLExport{InstanceID: 1, Statement: "SELECT * FROM t", Format: "XML"},
+			wantErr: true,
+		},
+		{
+			export:  &api.SQLExport{Statement: "SELECT * FROM t", Format: api.SQLExportFormatCSV},
+			wantErr: true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		err := validateSQLExport(test.export)
+		if test.wantErr {
+			a.Error(err, test.export.Statement)
+		} else {
+			a.NoError(err, test.export.Statement)
+		}
+	}
+}
+
+func TestGetSQLExportLimit(t *testing.T) {
+	tests := []struct {
+		queryLimit int
+		maxRows    int
+		want       int
+	}{
+		{queryLimit: 0, maxRows: 0, want: 0},
+		{queryLimit: 0, maxRows: 1000, want: 1000},
+		{queryLimit: 10, maxRows: 1000, want: 10},
+		{queryLimit: 5000, maxRows: 1000, want: 1000},
+		{queryLimit: 5000, maxRows: 0, want: 5000},
+		{queryLimit: -1, maxRows: 0, want: 0},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, getSQLExportLimit(test.queryLimit, test.maxRows), "%+v", test)
+	}
+}
+
+func TestGetSQLExportFilename(t *testing.T) {
+	exportTime := time.Date(2022, 9, 28, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		databaseName string
+		format       api.SQLExportFormat
+		want         string
+	}{
+		{databaseName: "employee", format: api.SQLExportFormatCSV, want: "employee-20220928T150405.csv"},
+		{databaseName: "employee", format: api.SQLExportFormatJSON, want: "employee-20220928T150405.json"},
+		{databaseName: "", format: api.SQLExportFormatCSV, want: "query-20220928T150405.csv"},
+		{databaseName: `my db/"x"`, format: api.SQLExportFormatCSV, want: "my_db_x_-20220928T150405.csv"},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, getSQLExportFilename(test.databaseName, test.format, exportTime))
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// sqlResultColumnCandidate is a synced column which a column in the SQL results may come from.
+type sqlResultColumnCandidate struct {
+	database string
+	table    string
+	name     string
+	comment  string
+}
+
+// getSQLResultColumnList returns the metadata of the columns in the SQL results joined from the synced schema.
+// The result columns are matched by name against the columns of the tables referenced by the statement for MySQL and TiDB,
+// or the columns of all the tables in the current database for the other engines.
+func (s *Server) getSQLResultColumnList(ctx context.Context, instance *api.Instance, exec *api.SQLExecute, columnNameList []string) ([]api.SQLResultColumn, error) {
+	// The referenced tables keyed by the database, nil means all the tables in the database.
+	tableMap := make(map[string]map[string]bool)
+	if instance.Engine == db.MySQL || instance.Engine == db.TiDB {
+		tableNameList, err := parseMySQLTableNameList(exec.Statement)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range tableNameList {
+			databaseName := table.Schema.O
+			if databaseName == "" {
+				databaseName = exec.DatabaseName
+			}
+			if databaseName == "" || mysqlSystemDatabases[strings.ToLower(databaseName)] {
+				continue
+			}
+			if tableMap[databaseName] == nil {
+				tableMap[databaseName] = make(map[string]bool)
+			}
+			tableMap[databaseName][table.Name.O] = true
+		}
+	} else if exec.DatabaseName != "" {
+		tableMap[exec.DatabaseName] = nil
+	}
+
+	var candidateList []*sqlResultColumnCandidate
+	for databaseName, tableSet := range tableMap {
+		list, err := s.findSQLResultColumnCandidateList(ctx, instance.ID, databaseName, tableSet)
+		if err != nil {
+			return nil, err
+		}
+		candidateList = append(candidateList, list...)
+	}
+	return matchSQLResultColumnList(columnNameList, candidateList), nil
+}
+
+// findSQLResultColumnCandidateList returns the synced columns of the tables in the database, or all the tables if tableSet is nil.
+func (s *Server) findSQLResultColumnCandidateList(ctx context.Context, instanceID int, databaseName string, tableSet map[string]bool) ([]*sqlResultColumnCandidate, error) {
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{
+		InstanceID: &instanceID,
+		Name:       &databaseName,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find database %q", databaseName)
+	}
+	if database == nil {
+		return nil, nil
+	}
+
+	tableList, err := s.store.FindTable(ctx, &api.TableFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find tables of database %q", databaseName)
+	}
+	tableNameMap := make(map[int]string)
+	for _, table := range tableList {
+		if tableSet == nil || tableSet[table.Name] {
+			tableNameMap[table.ID] = table.Name
+		}
+	}
+	if len(tableNameMap) == 0 {
+		return nil, nil
+	}
+
+	columnList, err := s.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find columns of database %q", databaseName)
+	}
+	var candidateList []*sqlResultColumnCandidate
+	for _, column := range columnList {
+		tableName, ok := tableNameMap[column.TableID]
+		if !ok {
+			continue
+		}
+		candidateList = append(candidateList, &sqlResultColumnCandidate{
+			database: databaseName,
+			table:    tableName,
+			name:     column.Name,
+			comment:  column.Comment,
+		})
+	}
+	return candidateList, nil
+}
+
+// matchSQLResultColumnList matches the result columns against the candidates by the case-insensitive name.
+// The metadata is left empty for the result columns matching none or more than one candidate.
+func matchSQLResultColumnList(columnNameList []string, candidateList []*sqlResultColumnCandidate) []api.SQLResultColumn {
+	candidateMap := make(map[string][]*sqlResultColumnCandidate)
+	for _, candidate := range candidateList {
+		key := strings.ToLower(candidate.name)
+		candidateMap[key] = append(candidateMap[key], candidate)
+	}
+
+	var columnList []api.SQLResultColumn
+	for _, name := range columnNameList {
+		column := api.SQLResultColumn{Name: name}
+		if list := candidateMap[strings.ToLower(name)]; len(list) == 1 {
+			column.Database = list[0].database
+			column.Table = list[0].table
+			column.Comment = list[0].comment
+		}
+		columnList = append(columnList, column)
+	}
+	return columnList
+}
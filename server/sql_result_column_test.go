@@ -0,0 +1,28 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestMatchSQLResultColumnList(t *testing.T) {
+	candidateList := []*sqlResultColumnCandidate{
+		{database: "db1", table: "user", name: "id", comment: "The user ID"},
+		{database: "db1", table: "user", name: "Email", comment: "The login email"},
+		{database: "db2", table: "order", name: "id", comment: "The order ID"},
+		{database: "db2", table: "order", name: "user_id", comment: ""},
+	}
+	got := matchSQLResultColumnList([]string{"id", "email", "user_id", "COUNT(*)"}, candidateList)
+	assert.Equal(t, []api.SQLResultColumn{
+		// Ambiguous between db1.user and db2.order.
+		{Name: "id"},
+		{Name: "email", Database: "db1", Table: "user", Comment: "The login email"},
+		{Name: "user_id", Database: "db2", Table: "order"},
+		{Name: "COUNT(*)"},
+	}, got)
+
+	assert.Nil(t, matchSQLResultColumnList(nil, candidateList))
+}
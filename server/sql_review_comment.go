@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
+)
+
+// sqlReviewDiscussionMarkerPrefix is the prefix of the hidden marker at the beginning of the discussion posted by the SQL review bot.
+// The marker identifies the finding the discussion is about, so that we can update or resolve it on the subsequent pushes.
+const sqlReviewDiscussionMarkerPrefix = "<!-- bytebase-sql-review: "
+
+// sqlReviewDiscussion is the merge request discussion for a SQL review finding.
+type sqlReviewDiscussion struct {
+	key  string
+	line int
+	body string
+}
+
+// buildSQLReviewDiscussionList builds the discussions for the findings in the file, the passed advices are skipped.
+func buildSQLReviewDiscussionList(filePath string, adviceList []advisor.Advice) []*sqlReviewDiscussion {
+	var discussionList []*sqlReviewDiscussion
+	for _, advice := range adviceList {
+		if advice.Status == advisor.Success {
+			continue
+		}
+		key := fmt.Sprintf("%s:%d:%s", filePath, advice.Line, advice.Title)
+		body := fmt.Sprintf("%s%s -->\n**[%s] %s**\n\n%s", sqlReviewDiscussionMarkerPrefix, key, advice.Status, advice.Title, advice.Content)
+		// Also mention the location in the body, because the discussion falls back to a general one if the line isn't a part of the diff.
+		if advice.Line > 0 {
+			body = fmt.Sprintf("%s\n\n`%s` line %d", body, filePath, advice.Line)
+		}
+		discussionList = append(discussionList, &sqlReviewDiscussion{
+			key:  key,
+			line: advice.Line,
+			body: body,
+		})
+	}
+	return discussionList
+}
+
+// getSQLReviewDiscussionKey returns the key in the marker of the discussion body, and false if the discussion isn't posted by the SQL review bot.
+func getSQLReviewDiscussionKey(body string) (string, bool) {
+	if !strings.HasPrefix(body, sqlReviewDiscussionMarkerPrefix) {
+		return "", false
+	}
+	end := strings.Index(body, " -->")
+	if end < 0 {
+		return "", false
+	}
+	return body[len(sqlReviewDiscussionMarkerPrefix):end], true
+}
+
+// findSQLReviewRepository finds the GitLab repository linked to the project by its external ID, and verifies the secret token.
+func (s *Server) findSQLReviewRepository(ctx context.Context, externalID, secretToken string) (*api.Repository, error) {
+	repoList, err := s.store.FindRepository(ctx, &api.RepositoryFind{ExternalID: &externalID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find repository with ID: %s", externalID)).SetInternal(err)
+	}
+	for _, repo := range repoList {
+		if repo.VCS == nil || repo.VCS.Type != vcs.GitLabSelfHost {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(repo.WebhookSecretToken), []byte(secretToken)) == 1 {
+			return repo, nil
+		}
+	}
+	if len(repoList) == 0 {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Cannot find GitLab repository with ID: %s", externalID))
+	}
+	return nil, echo.NewHTTPError(http.StatusForbidden, "Secret token mismatch")
+}
+
+// syncSQLReviewMergeRequestDiscussion posts the SQL review findings in the file as the merge request discussions anchored to the offending lines.
+// The discussions posted by the previous runs are updated if the finding changes, and resolved if the finding is gone.
+func (s *Server) syncSQLReviewMergeRequestDiscussion(ctx context.Context, repo *api.Repository, mergeRequestIID int, filePath string, adviceList []advisor.Advice) error {
+	provider, ok := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{}).(*gitlab.Provider)
+	if !ok {
+		return errors.Errorf("VCS type %s doesn't support merge request discussion", repo.VCS.Type)
+	}
+	oauthCtx := common.OauthContext{
+		ClientID:     repo.VCS.ApplicationID,
+		ClientSecret: repo.VCS.Secret,
+		AccessToken:  repo.AccessToken,
+		RefreshToken: repo.RefreshToken,
+		Refresher:    s.refreshToken(ctx, repo.ID),
+	}
+	instanceURL := repo.VCS.InstanceURL
+
+	version, err := provider.FetchMergeRequestLatestVersion(ctx, oauthCtx, instanceURL, repo.ExternalID, mergeRequestIID)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch merge request version")
+	}
+	discussionList, err := provider.FetchMergeRequestDiscussionList(ctx, oauthCtx, instanceURL, repo.ExternalID, mergeRequestIID)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch merge request discussions")
+	}
+
+	// Collect the unresolved discussions posted by the previous runs for the same file.
+	existingMap := make(map[string]*gitlab.Discussion)
+	var existingKeyList []string
+	for i := range discussionList {
+		discussion := &discussionList[i]
+		if len(discussion.Notes) == 0 || discussion.Notes[0].Resolved {
+			continue
+		}
+		key, ok := getSQLReviewDiscussionKey(discussion.Notes[0].Body)
+		if !ok || !strings.HasPrefix(key, filePath+":") {
+			continue
+		}
+		if _, ok := existingMap[key]; !ok {
+			existingKeyList = append(existingKeyList, key)
+		}
+		existingMap[key] = discussion
+	}
+
+	for _, discussion := range buildSQLReviewDiscussionList(filePath, adviceList) {
+		if existing, ok := existingMap[discussion.key]; ok {
+			delete(existingMap, discussion.key)
+			note := existing.Notes[0]
+			if note.Body == discussion.body {
+				continue
+			}
+			if err := provider.UpdateMergeRequestDiscussionNote(ctx, oauthCtx, instanceURL, repo.ExternalID, mergeRequestIID, existing.ID, note.ID, discussion.body); err != nil {
+				return errors.Wrapf(err, "failed to update merge request discussion %s", existing.ID)
+			}
+			continue
+		}
+
+		discussionCreate := &gitlab.DiscussionCreate{
+			Body: discussion.body,
+		}
+		// The advice without line, e.g. the policy not found error, is posted as a general discussion.
+		if discussion.line > 0 {
+			discussionCreate.Position = &gitlab.DiscussionPosition{
+				PositionType: "text",
+				BaseSHA:      version.BaseCommitSHA,
+				StartSHA:     version.StartCommitSHA,
+				HeadSHA:      version.HeadCommitSHA,
+				OldPath:      filePath,
+				NewPath:      filePath,
+				NewLine:      discussion.line,
+			}
+		}
+		if err := provider.CreateMergeRequestDiscussion(ctx, oauthCtx, instanceURL, repo.ExternalID, mergeRequestIID, discussionCreate); err != nil {
+			if discussionCreate.Position == nil {
+				return errors.Wrap(err, "failed to create merge request discussion")
+			}
+			// GitLab rejects the position if the line isn't a part of the diff, fall back to a general discussion in that case.
+			discussionCreate.Position = nil
+			if err := provider.CreateMergeRequestDiscussion(ctx, oauthCtx, instanceURL, repo.ExternalID, mergeRequestIID, discussionCreate); err != nil {
+				return errors.Wrap(err, "failed to create merge request discussion")
+			}
+		}
+	}
+
+	// The remaining discussions are about the findings which have been fixed.
+	for _, key := range existingKeyList {
+		existing, ok := existingMap[key]
+		if !ok {
+			continue
+		}
+		if err := provider.ResolveMergeRequestDiscussion(ctx, oauthCtx, instanceURL, repo.ExternalID, mergeRequestIID, existing.ID); err != nil {
+			return errors.Wrapf(err, "failed to resolve merge request discussion %s", existing.ID)
+		}
+	}
+
+	log.Debug("Synced SQL review merge request discussions",
+		zap.String("repository", repo.FullPath),
+		zap.Int("merge_request", mergeRequestIID),
+		zap.String("file", filePath),
+	)
+	return nil
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestBuildSQLReviewDiscussionList(t *testing.T) {
+	adviceList := []advisor.Advice{
+		{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		},
+		{
+			Status:  advisor.Warn,
+			Code:    advisor.StatementNoWhere,
+			Title:   "statement.where.require",
+			Content: "\"DELETE FROM t\" requires WHERE clause",
+			Line:    3,
+		},
+	}
+
+	discussionList := buildSQLReviewDiscussionList("migration/1__init.sql", adviceList)
+	assert.Equal(t, []*sqlReviewDiscussion{
+		{
+			key:  "migration/1__init.sql:3:statement.where.require",
+			line: 3,
+			body: "<!-- bytebase-sql-review: migration/1__init.sql:3:statement.where.require -->\n**[WARN] statement.where.require**\n\n\"DELETE FROM t\" requires WHERE clause\n\n`migration/1__init.sql` line 3",
+		},
+	}, discussionList)
+
+	key, ok := getSQLReviewDiscussionKey(discussionList[0].body)
+	assert.True(t, ok)
+	assert.Equal(t, discussionList[0].key, key)
+
+	_, ok = getSQLReviewDiscussionKey("LGTM")
+	assert.False(t, ok)
+}
@@ -1,7 +1,19 @@
 package server
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func TestValidateSQLSelectStatement(t *testing.T) {
@@ -85,6 +97,38 @@ func TestValidateSQLSelectStatement(t *testing.T) {
 			sqlStatement: "SETEST 1; INSERT INTO tbl(num) VALUES(113);",
 			want:         false,
 		},
+		{
+			sqlStatement: "show tables",
+			want:         true,
+		},
+		{
+			sqlStatement: "SHOW CREATE TABLE t",
+			want:         true,
+		},
+		{
+			sqlStatement: "SHOWTABLES",
+			want:         false,
+		},
+		{
+			sqlStatement: "SHOW TABLES; DROP TABLE t;",
+			want:         false,
+		},
+		{
+			sqlStatement: "SELECT 1;\nDROP TABLE t",
+			want:         false,
+		},
+		{
+			sqlStatement: "SELECT * FROM t -- it's\n; DELETE FROM t; -- '",
+			want:         false,
+		},
+		{
+			sqlStatement: "SELECT * FROM t;",
+			want:         true,
+		},
+		{
+			sqlStatement: "SHOW TABLES; \n",
+			want:         true,
+		},
 	}
 
 	for _, test := range tests {
@@ -94,3 +138,148 @@ func TestValidateSQLSelectStatement(t *testing.T) {
 		}
 	}
 }
+
+func TestGetQueryContextByLimit(t *testing.T) {
+	tests := []struct {
+		limit        api.QueryLimit
+		requestLimit int
+		want         *db.QueryContext
+	}{
+		{
+			limit:        api.QueryLimit{MaxBytes: api.DefaultQueryMaxBytes},
+			requestLimit: 10000,
+			want:         &db.QueryContext{Limit: 10000, MaxBytes: api.DefaultQueryMaxBytes},
+		},
+		{
+			limit:        api.QueryLimit{MaxRows: 100, MaxBytes: 1024},
+			requestLimit: 10000,
+			want:         &db.QueryContext{Limit: 100, MaxBytes: 1024},
+		},
+		{
+			limit:        api.QueryLimit{MaxRows: 100},
+			requestLimit: 10,
+			want:         &db.QueryContext{Limit: 10},
+		},
+		{
+			limit:        api.QueryLimit{MaxRows: 100},
+			requestLimit: 0,
+			want:         &db.QueryContext{Limit: 100},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, getQueryContextByLimit(test.limit, test.requestLimit))
+	}
+}
+
+func TestGetQueryTimeout(t *testing.T) {
+	assert.Equal(t, defaultQueryTimeout, getQueryTimeout(0))
+	assert.Equal(t, defaultQueryTimeout, getQueryTimeout(-1))
+	assert.Equal(t, 5*time.Second, getQueryTimeout(5))
+	assert.Equal(t, maxQueryTimeout, getQueryTimeout(3600))
+}
+
+func TestExtractMySQLReferencedDatabaseList(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			statement: "SELECT * FROM t",
+			want:      nil,
+		},
+		{
+			statement: "SELECT * FROM db2.t2 JOIN db1.t1 ON t1.id = t2.id JOIN t3 ON t3.id = t1.id",
+			want:      []string{"db1", "db2"},
+		},
+		{
+			statement: "SELECT * FROM db1.t1 WHERE id IN (SELECT id FROM db1.t2 UNION SELECT id FROM `Db3`.t3)",
+			want:      []string{"Db3", "db1"},
+		},
+		{
+			statement: "SELECT * FROM information_schema.TABLES JOIN MySQL.user",
+			want:      nil,
+		},
+		{
+			statement: "EXPLAIN SELECT * FROM db1.t1",
+			want:      []string{"db1"},
+		},
+		{
+			statement: "SHOW TABLES FROM db1",
+			want:      []string{"db1"},
+		},
+		{
+			statement: "SHOW COLUMNS FROM t1 FROM db1",
+			want:      []string{"db1"},
+		},
+		{
+			statement: "SHOW TABLES FROM INFORMATION_SCHEMA",
+			want:      nil,
+		},
+		{
+			statement: "SELECT * FROM",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := extractMySQLReferencedDatabaseList(test.statement)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, got, test.statement)
+	}
+}
+
+func TestIsProjectMember(t *testing.T) {
+	project := &api.Project{
+		ProjectMemberList: []*api.ProjectMember{
+			{PrincipalID: 101},
+		},
+	}
+	assert.True(t, isProjectMember(project, 101))
+	assert.False(t, isProjectMember(project, 102))
+	assert.False(t, isProjectMember(nil, 101))
+}
+
+func TestCheckSQLEditorDatabasePermissionWithoutDatabase(t *testing.T) {
+	tests := []struct {
+		role     api.Role
+		engine   db.Type
+		readonly bool
+		wantCode int
+	}{
+		// The Postgres connection without the database name falls back to the default database of the login user.
+		{api.Developer, db.Postgres, true, http.StatusBadRequest},
+		{api.Developer, db.MySQL, true, http.StatusBadRequest},
+		{api.Owner, db.Postgres, false, http.StatusBadRequest},
+		{api.DBA, db.Postgres, false, http.StatusBadRequest},
+		// The DBAs and the owners can query without any grant.
+		{api.DBA, db.Postgres, true, 0},
+		{api.Owner, db.Postgres, true, 0},
+	}
+
+	a := require.New(t)
+	e := echo.New()
+	s := &Server{}
+	for _, test := range tests {
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/sql/execute", nil), httptest.NewRecorder())
+		c.Set(getRoleContextKey(), test.role)
+		c.Set(getPrincipalIDContextKey(), 101)
+		err := s.checkSQLEditorDatabasePermission(context.Background(), c, &api.Instance{ID: 1, Engine: test.engine}, &api.SQLExecute{
+			InstanceID: 1,
+			Statement:  "SELECT 1",
+			Readonly:   test.readonly,
+		})
+		if test.wantCode == 0 {
+			a.NoError(err, "%s %s readonly %v", test.role, test.engine, test.readonly)
+			continue
+		}
+		httpErr, ok := err.(*echo.HTTPError)
+		a.True(ok, "%s %s readonly %v", test.role, test.engine, test.readonly)
+		a.Equal(test.wantCode, httpErr.Code, "%s %s readonly %v", test.role, test.engine, test.readonly)
+	}
+}
@@ -1,12 +1,15 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
@@ -73,4 +76,215 @@ func (s *Server) registerStageRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+	// This function pauses the pipeline before the stage, or resumes it.
+	// Anyone who can change the task status can pause the stage, but only the workspace Owner and DBA can resume it.
+	g.PATCH("/pipeline/:pipelineID/stage/:stageID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		stageID, err := strconv.Atoi(c.Param("stageID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Stage ID is not a number: %s", c.Param("stageID"))).SetInternal(err)
+		}
+		pipelineID, err := strconv.Atoi(c.Param("pipelineID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Pipeline ID is not a number: %s", c.Param("pipelineID"))).SetInternal(err)
+		}
+
+		currentPrincipalID := c.Get(getPrincipalIDContextKey()).(int)
+		stagePatch := &api.StagePatch{
+			ID:        stageID,
+			UpdaterID: currentPrincipalID,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, stagePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed update stage request").SetInternal(err)
+		}
+		if stagePatch.Paused == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing paused in update stage request")
+		}
+
+		stageList, err := s.store.FindStage(ctx, &api.StageFind{ID: &stageID, PipelineID: &pipelineID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch stage ID: %d", stageID)).SetInternal(err)
+		}
+		if len(stageList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Stage not found with ID %d", stageID))
+		}
+		stage := stageList[0]
+		if stage.Paused == *stagePatch.Paused {
+			c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+			if err := jsonapi.MarshalPayload(c.Response().Writer, stage); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal stage ID response: %d", stageID)).SetInternal(err)
+			}
+			return nil
+		}
+		if len(stage.TaskList) == 0 {
+			// which is impossible, because we make sure at least there is one task in each stage.
+			return echo.NewHTTPError(http.StatusInternalServerError, "No task in the stage")
+		}
+
+		activityType := api.ActivityPipelineStageResume
+		if *stagePatch.Paused {
+			activityType = api.ActivityPipelineStagePause
+			if !canPauseStage(stage) {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Can not pause stage %q because all its tasks have finished", stage.Name))
+			}
+			// pick any task in the stage to validate
+			// because all tasks in the same stage share the issue & environment.
+			ok, err := s.canPrincipalChangeTaskStatus(ctx, currentPrincipalID, stage.TaskList[0])
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change task status").SetInternal(err)
+			}
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to pause the stage")
+			}
+		} else {
+			if role := c.Get(getRoleContextKey()).(api.Role); role != api.Owner && role != api.DBA {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Only the workspace Owner and DBA can resume the stage")
+			}
+			// Resuming manually confirms the remaining tasks, so the stage no longer resumes automatically if paused again.
+			soakSeconds := int64(0)
+			stagePatch.SoakSeconds = &soakSeconds
+		}
+
+		stagePatched, err := s.store.PatchStage(ctx, stagePatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update stage ID: %d", stageID)).SetInternal(err)
+		}
+
+		issue, err := s.store.GetIssueByPipelineID(ctx, pipelineID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue with pipeline ID: %d", pipelineID)).SetInternal(err)
+		}
+		if issue != nil {
+			payload, err := json.Marshal(api.ActivityPipelineStagePauseResumePayload{
+				StageID:   stage.ID,
+				IssueName: issue.Name,
+				StageName: stage.Name,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal stage pause activity payload").SetInternal(err)
+			}
+			if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+				CreatorID:   currentPrincipalID,
+				ContainerID: pipelineID,
+				Type:        activityType,
+				Level:       api.ActivityInfo,
+				Comment:     stagePatch.Comment,
+				Payload:     string(payload),
+			}, &ActivityMeta{
+				issue: issue,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after updating stage ID: %d", stageID)).SetInternal(err)
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, stagePatched); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal stage ID response: %d", stageID)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// canPauseStage returns true if the stage has any task not finished yet, it makes no sense to hold the pipeline before a finished stage.
+func canPauseStage(stage *api.Stage) bool {
+	for _, task := range stage.TaskList {
+		if task.Status != api.TaskDone && task.Status != api.TaskCanceled {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUnstartedTask returns true if the stage has any task waiting to be scheduled.
+func hasUnstartedTask(stage *api.Stage) bool {
+	for _, task := range stage.TaskList {
+		if task.Status == api.TaskPendingApproval || task.Status == api.TaskPending {
+			return true
+		}
+	}
+	return false
+}
+
+// pauseStageOnTaskFailure pauses the stage of the failed task if the task failure policy of the stage environment requires,
+// so that a bad change doesn't keep rolling out to the remaining tenant databases. The running tasks are not affected.
+func (s *Server) pauseStageOnTaskFailure(ctx context.Context, issue *api.Issue, task *api.Task) error {
+	if issue.Project.TenantMode != api.TenantModeTenant {
+		return nil
+	}
+	stageList, err := s.store.FindStage(ctx, &api.StageFind{ID: &task.StageID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch stage ID %d", task.StageID)
+	}
+	if len(stageList) == 0 {
+		return errors.Errorf("stage ID not found: %d", task.StageID)
+	}
+	stage := stageList[0]
+	if stage.Paused || !hasUnstartedTask(stage) {
+		return nil
+	}
+	policy, err := s.store.GetTaskFailurePolicyByEnvID(ctx, stage.EnvironmentID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get task failure policy for environment ID %d", stage.EnvironmentID)
+	}
+	if policy.Action != api.TaskFailureActionPauseStage {
+		return nil
+	}
+
+	paused := true
+	if _, err := s.store.PatchStage(ctx, &api.StagePatch{
+		ID:        stage.ID,
+		UpdaterID: api.SystemBotID,
+		Paused:    &paused,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to pause stage ID %d", stage.ID)
+	}
+
+	payload, err := json.Marshal(api.ActivityPipelineStagePauseResumePayload{
+		StageID:   stage.ID,
+		IssueName: issue.Name,
+		StageName: stage.Name,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal stage pause activity payload")
+	}
+	activity, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: task.PipelineID,
+		Type:        api.ActivityPipelineStagePause,
+		Level:       api.ActivityWarn,
+		Comment:     fmt.Sprintf("Paused automatically by the task failure policy because task %q failed.", task.Name),
+		Payload:     string(payload),
+	}, &ActivityMeta{
+		issue: issue,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create stage pause activity")
+	}
+	return s.postInboxProjectOwnerActivity(ctx, issue, activity.ID)
+}
+
+// postInboxProjectOwnerActivity posts the activity to the inbox of the project owners,
+// skipping the ones notified as the issue creator, assignee or subscriber.
+func (s *Server) postInboxProjectOwnerActivity(ctx context.Context, issue *api.Issue, activityID int) error {
+	notifiedSet := map[int]bool{
+		api.SystemBotID:  true,
+		issue.CreatorID:  true,
+		issue.AssigneeID: true,
+	}
+	for _, subscriber := range issue.SubscriberList {
+		notifiedSet[subscriber.ID] = true
+	}
+	for _, member := range issue.Project.ProjectMemberList {
+		if member.Role != string(common.ProjectOwner) || notifiedSet[member.PrincipalID] {
+			continue
+		}
+		notifiedSet[member.PrincipalID] = true
+		if _, err := s.store.CreateInbox(ctx, &api.InboxCreate{
+			ReceiverID: member.PrincipalID,
+			ActivityID: activityID,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to post activity to project owner inbox: %d", member.PrincipalID)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestCanPauseStage(t *testing.T) {
+	tests := []struct {
+		statusList []api.TaskStatus
+		want       bool
+	}{
+		{
+			statusList: []api.TaskStatus{api.TaskPendingApproval, api.TaskDone},
+			want:       true,
+		},
+		{
+			statusList: []api.TaskStatus{api.TaskFailed},
+			want:       true,
+		},
+		{
+			statusList: []api.TaskStatus{api.TaskDone, api.TaskCanceled},
+			want:       false,
+		},
+	}
+
+	for _, test := range tests {
+		stage := &api.Stage{}
+		for _, status := range test.statusList {
+			stage.TaskList = append(stage.TaskList, &api.Task{Status: status})
+		}
+		assert.Equal(t, test.want, canPauseStage(stage), test.statusList)
+	}
+}
+
+func TestGetStageSoakResumeTs(t *testing.T) {
+	canary := &api.Stage{ID: 1, TaskList: []*api.Task{{UpdatedTs: 100}, {UpdatedTs: 200}}}
+	soaked := &api.Stage{ID: 2, Paused: true, SoakSeconds: 3600}
+	manual := &api.Stage{ID: 3, Paused: true}
+	stageList := []*api.Stage{canary, soaked, manual}
+
+	assert.Equal(t, int64(3800), getStageSoakResumeTs(stageList, soaked))
+	assert.Equal(t, int64(0), getStageSoakResumeTs(stageList, manual))
+	// The first stage has no previous stage to soak after.
+	assert.Equal(t, int64(0), getStageSoakResumeTs([]*api.Stage{soaked}, soaked))
+}
+
+func TestHasUnstartedTask(t *testing.T) {
+	tests := []struct {
+		statusList []api.TaskStatus
+		want       bool
+	}{
+		{
+			statusList: []api.TaskStatus{api.TaskFailed, api.TaskPending},
+			want:       true,
+		},
+		{
+			statusList: []api.TaskStatus{api.TaskFailed, api.TaskPendingApproval},
+			want:       true,
+		},
+		{
+			statusList: []api.TaskStatus{api.TaskFailed, api.TaskRunning, api.TaskDone},
+			want:       false,
+		},
+	}
+
+	for _, test := range tests {
+		stage := &api.Stage{}
+		for _, status := range test.statusList {
+			stage.TaskList = append(stage.TaskList, &api.Task{Status: status})
+		}
+		assert.Equal(t, test.want, hasUnstartedTask(stage), test.statusList)
+	}
+}
@@ -0,0 +1,296 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// registerStatementCommentRoutes registers the routes of the review comments on the lines of the task statements in the issue.
+// The approvers comment on the statement like a code review, and the task can't be approved until all its comments are resolved.
+func (s *Server) registerStatementCommentRoutes(g *echo.Group) {
+	g.POST("/issue/:issueID/statement-comment", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issueID, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+
+		commentCreate := &api.StatementCommentCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, commentCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create statement comment request").SetInternal(err)
+		}
+		commentCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		commentCreate.IssueID = issueID
+		commentCreate.Content = strings.TrimSpace(commentCreate.Content)
+		if commentCreate.Content == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Statement comment content is required")
+		}
+
+		issue, httpErr := s.getStatementCommentIssue(ctx, issueID)
+		if httpErr != nil {
+			return httpErr
+		}
+		if issue.Status != api.IssueOpen {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Can not comment on the statement of issue %q in %q status", issue.Name, issue.Status))
+		}
+		task, statement, httpErr := s.getStatementCommentTask(ctx, issue, commentCreate.TaskID)
+		if httpErr != nil {
+			return httpErr
+		}
+		ok, err := s.canPrincipalChangeTaskStatus(ctx, commentCreate.CreatorID, task)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change task status").SetInternal(err)
+		}
+		if !ok {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the approvers of the task can comment on its statement")
+		}
+		if !isStatementLineValid(statement, commentCreate.Line) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Line %d is out of the statement of task %q", commentCreate.Line, task.Name))
+		}
+		commentCreate.StatementHash = api.GetStatementHash(statement)
+
+		comment, err := s.store.CreateStatementComment(ctx, commentCreate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create statement comment for issue %d", issueID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, comment); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create statement comment response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// The comments are returned with the outdated flag if the statement has changed since they were created.
+	g.GET("/issue/:issueID/statement-comment", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issueID, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+
+		commentFind := &api.StatementCommentFind{
+			IssueID: &issueID,
+		}
+		if taskIDStr := c.QueryParam("task"); taskIDStr != "" {
+			taskID, err := strconv.Atoi(taskIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("task query parameter is not a number: %s", taskIDStr)).SetInternal(err)
+			}
+			commentFind.TaskID = &taskID
+		}
+		if resolvedStr := c.QueryParam("resolved"); resolvedStr != "" {
+			resolved, err := strconv.ParseBool(resolvedStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("resolved query parameter is not a boolean: %s", resolvedStr)).SetInternal(err)
+			}
+			commentFind.Resolved = &resolved
+		}
+
+		commentList, err := s.store.FindStatementComment(ctx, commentFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch statement comment list for issue %d", issueID)).SetInternal(err)
+		}
+		if err := s.setStatementCommentOutdated(ctx, commentList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check the outdated statement comments for issue %d", issueID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, commentList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal statement comment list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// Only the creator can edit the content of the comment, while the creator and the approvers of the task can resolve it.
+	g.PATCH("/issue/:issueID/statement-comment/:commentID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issueID, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+		id, err := strconv.Atoi(c.Param("commentID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Comment ID is not a number: %s", c.Param("commentID"))).SetInternal(err)
+		}
+
+		commentPatch := &api.StatementCommentPatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, commentPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch statement comment request").SetInternal(err)
+		}
+
+		comment, httpErr := s.getStatementComment(ctx, issueID, id)
+		if httpErr != nil {
+			return httpErr
+		}
+		if v := commentPatch.Content; v != nil {
+			if comment.CreatorID != commentPatch.UpdaterID {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the creator can edit the statement comment")
+			}
+			content := strings.TrimSpace(*v)
+			if content == "" {
+				return echo.NewHTTPError(http.StatusBadRequest, "Statement comment content is required")
+			}
+			commentPatch.Content = &content
+		}
+		if commentPatch.Resolved != nil && comment.CreatorID != commentPatch.UpdaterID {
+			task, err := s.store.GetTaskByID(ctx, comment.TaskID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task ID: %d", comment.TaskID)).SetInternal(err)
+			}
+			if task == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", comment.TaskID))
+			}
+			ok, err := s.canPrincipalChangeTaskStatus(ctx, commentPatch.UpdaterID, task)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change task status").SetInternal(err)
+			}
+			if !ok {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the creator and the approvers of the task can resolve the statement comment")
+			}
+		}
+
+		commentPatched, err := s.store.PatchStatementComment(ctx, commentPatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Statement comment ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch statement comment ID: %v", id)).SetInternal(err)
+		}
+		if err := s.setStatementCommentOutdated(ctx, []*api.StatementComment{commentPatched}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check if statement comment ID %v is outdated", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, commentPatched); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal statement comment ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/issue/:issueID/statement-comment/:commentID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issueID, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+		id, err := strconv.Atoi(c.Param("commentID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Comment ID is not a number: %s", c.Param("commentID"))).SetInternal(err)
+		}
+
+		commentDelete := &api.StatementCommentDelete{
+			ID:        id,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		comment, httpErr := s.getStatementComment(ctx, issueID, id)
+		if httpErr != nil {
+			return httpErr
+		}
+		if comment.CreatorID != commentDelete.DeleterID {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the creator can delete the statement comment")
+		}
+		if err := s.store.DeleteStatementComment(ctx, commentDelete); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Statement comment ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete statement comment ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// getStatementCommentIssue returns the issue of the statement comments, and returns the HTTP error if it's not found.
+func (s *Server) getStatementCommentIssue(ctx context.Context, issueID int) (*api.Issue, *echo.HTTPError) {
+	issue, err := s.store.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %d", issueID)).SetInternal(err)
+	}
+	if issue == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", issueID))
+	}
+	return issue, nil
+}
+
+// getStatementCommentTask returns the task of the issue to comment on and its current statement,
+// and returns the HTTP error if the task is not in the issue or has no statement.
+func (s *Server) getStatementCommentTask(ctx context.Context, issue *api.Issue, taskID int) (*api.Task, string, *echo.HTTPError) {
+	task, err := s.store.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task ID: %d", taskID)).SetInternal(err)
+	}
+	if task == nil || task.PipelineID != issue.PipelineID {
+		return nil, "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID %d not found in issue %q", taskID, issue.Name))
+	}
+	statement, err := s.TaskCheckScheduler.getStatement(task)
+	if err != nil {
+		return nil, "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %q has no statement to comment on", task.Name)).SetInternal(err)
+	}
+	return task, statement, nil
+}
+
+// getStatementComment returns the statement comment of the issue, and returns the HTTP error if it's not found.
+func (s *Server) getStatementComment(ctx context.Context, issueID int, id int) (*api.StatementComment, *echo.HTTPError) {
+	comment, err := s.store.GetStatementComment(ctx, &api.StatementCommentFind{
+		ID:      &id,
+		IssueID: &issueID,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch statement comment ID: %v", id)).SetInternal(err)
+	}
+	if comment == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Statement comment ID not found: %d", id))
+	}
+	return comment, nil
+}
+
+// setStatementCommentOutdated marks the comments whose task statement has changed since they were created.
+func (s *Server) setStatementCommentOutdated(ctx context.Context, commentList []*api.StatementComment) error {
+	statementHashMap := make(map[int]string)
+	for _, comment := range commentList {
+		hash, ok := statementHashMap[comment.TaskID]
+		if !ok {
+			task, err := s.store.GetTaskByID(ctx, comment.TaskID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to fetch task ID %d", comment.TaskID)
+			}
+			if task != nil {
+				// The task type doesn't change, so the statement is always found for the task commented on.
+				statement, err := s.TaskCheckScheduler.getStatement(task)
+				if err != nil {
+					return errors.Wrapf(err, "failed to get the statement of task ID %d", comment.TaskID)
+				}
+				hash = api.GetStatementHash(statement)
+			}
+			statementHashMap[comment.TaskID] = hash
+		}
+		comment.Outdated = isStatementCommentOutdated(comment, hash)
+	}
+	return nil
+}
+
+// isStatementCommentOutdated returns true if the statement has changed since the comment was created.
+func isStatementCommentOutdated(comment *api.StatementComment, statementHash string) bool {
+	return comment.StatementHash != statementHash
+}
+
+// isStatementLineValid returns true if the 1-based line is in the statement.
+func isStatementLineValid(statement string, line int) bool {
+	return line >= 1 && line <= strings.Count(statement, "\n")+1
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestIsStatementLineValid(t *testing.T) {
+	tests := []struct {
+		statement string
+		line      int
+		want      bool
+	}{
+		{
+			statement: "SELECT 1;",
+			line:      1,
+			want:      true,
+		},
+		{
+			statement: "SELECT 1;",
+			line:      0,
+			want:      false,
+		},
+		{
+			statement: "CREATE TABLE t(a int);\nALTER TABLE t ADD COLUMN b int;\n",
+			line:      3,
+			want:      true,
+		},
+		{
+			statement: "CREATE TABLE t(a int);\nALTER TABLE t ADD COLUMN b int;\n",
+			line:      4,
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, isStatementLineValid(test.statement, test.line), test.statement)
+	}
+}
+
+func TestIsStatementCommentOutdated(t *testing.T) {
+	comment := &api.StatementComment{
+		StatementHash: api.GetStatementHash("ALTER TABLE t ADD COLUMN b int;"),
+	}
+	assert.False(t, isStatementCommentOutdated(comment, api.GetStatementHash("ALTER TABLE t ADD COLUMN b int;")))
+	assert.True(t, isStatementCommentOutdated(comment, api.GetStatementHash("ALTER TABLE t ADD COLUMN b int NOT NULL DEFAULT 0;")))
+	// The comment on a deleted task is outdated.
+	assert.True(t, isStatementCommentOutdated(comment, ""))
+}
@@ -1,7 +1,11 @@
 package server
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/jsonapi"
@@ -14,6 +18,9 @@ import (
 	enterpriseAPI "github.com/bytebase/bytebase/enterprise/api"
 )
 
+// maxLicenseFileSize is the maximum size of the uploaded license file, the license is a signed JWT token which is far smaller.
+const maxLicenseFileSize = 64 * 1024
+
 func (s *Server) registerSubscriptionRoutes(g *echo.Group) {
 	g.GET("/subscription", func(c echo.Context) error {
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
@@ -45,6 +52,49 @@ func (s *Server) registerSubscriptionRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	// The signed license file is uploaded as the "file" field of the multipart form.
+	g.POST("/subscription/license", func(c echo.Context) error {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed upload license request, expect the license file in the \"file\" field").SetInternal(err)
+		}
+		if fileHeader.Size > maxLicenseFileSize {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The license file is too large, the maximum size is %d bytes", maxLicenseFileSize))
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to open the license file").SetInternal(err)
+		}
+		defer file.Close()
+		content, err := io.ReadAll(io.LimitReader(file, maxLicenseFileSize))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to read the license file").SetInternal(err)
+		}
+		license := strings.TrimSpace(string(content))
+		if license == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "The license file is empty")
+		}
+
+		patch := &enterpriseAPI.SubscriptionPatch{
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+			License:   license,
+		}
+		if err := s.LicenseService.StoreLicense(patch); err != nil {
+			if common.ErrorCode(err) == common.Invalid {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to store license").SetInternal(err)
+		}
+
+		s.subscription = s.loadSubscription()
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &s.subscription); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal subscription response").SetInternal(err)
+		}
+		return nil
+	})
 }
 
 // loadLicense will load current subscription by license.
@@ -66,6 +116,8 @@ func (s *Server) loadSubscription() enterpriseAPI.Subscription {
 			Trialing:      license.Trialing,
 			OrgID:         license.OrgID(),
 			OrgName:       license.OrgName,
+			Seat:          license.Seat,
+			Features:      license.Features,
 		}
 	}
 
@@ -94,8 +146,57 @@ func (s *Server) loadLicense() (*enterpriseAPI.License, error) {
 	return license, nil
 }
 
+// feature returns true if the feature is available in the current plan, or granted by the license in addition to the plan.
 func (s *Server) feature(feature api.FeatureType) bool {
-	return api.FeatureMatrix[feature][s.getEffectivePlan()]
+	if api.FeatureMatrix[feature][s.getEffectivePlan()] {
+		return true
+	}
+	if s.isSubscriptionExpired() {
+		return false
+	}
+	for _, f := range s.subscription.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFeature is the central feature check consulted by the API handlers and the schedulers.
+// It returns the structured api.FeatureError if the feature isn't available.
+func (s *Server) checkFeature(feature api.FeatureType) error {
+	if s.feature(feature) {
+		return nil
+	}
+	return api.NewFeatureError(feature, s.getEffectivePlan())
+}
+
+// featureHTTPError converts the error returned by checkFeature to the HTTP error, whose response body is the structured error.
+func featureHTTPError(err error) *echo.HTTPError {
+	return echo.NewHTTPError(http.StatusForbidden, err).SetInternal(err)
+}
+
+// seatCountGuard is a feature guard for the member seats of the license.
+// We only count members with NORMAL status since the ARCHIVED ones cannot sign in.
+func (s *Server) seatCountGuard(ctx context.Context) *echo.HTTPError {
+	seat := s.loadSubscription().Seat
+	if seat <= 0 {
+		return nil
+	}
+	memberList, err := s.store.FindMember(ctx, &api.MemberFind{})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find member list").SetInternal(err)
+	}
+	count := 0
+	for _, member := range memberList {
+		if member.RowStatus == api.Normal {
+			count++
+		}
+	}
+	if count >= seat {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("You have reached the maximum seat count %d.", seat))
+	}
+	return nil
 }
 
 func (s *Server) getPlanLimitValue(name api.PlanLimit) int64 {
@@ -107,8 +208,12 @@ func (s *Server) getPlanLimitValue(name api.PlanLimit) int64 {
 }
 
 func (s *Server) getEffectivePlan() api.PlanType {
-	if expireTime := time.Unix(s.subscription.ExpiresTs, 0); expireTime.Before(time.Now()) {
+	if s.isSubscriptionExpired() {
 		return api.FREE
 	}
 	return s.subscription.Plan
 }
+
+func (s *Server) isSubscriptionExpired() bool {
+	return time.Unix(s.subscription.ExpiresTs, 0).Before(time.Now())
+}
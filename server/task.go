@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -15,16 +16,18 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/advisor"
 )
 
 var (
 	applicableTaskStatusTransition = map[api.TaskStatus][]api.TaskStatus{
 		api.TaskPendingApproval: {api.TaskPending},
 		api.TaskPending:         {api.TaskRunning, api.TaskPendingApproval},
-		api.TaskRunning:         {api.TaskDone, api.TaskFailed, api.TaskCanceled},
+		api.TaskRunning:         {api.TaskDone, api.TaskFailed, api.TaskCanceled, api.TaskPaused},
 		api.TaskDone:            {},
 		api.TaskFailed:          {api.TaskRunning, api.TaskPendingApproval},
 		api.TaskCanceled:        {api.TaskRunning},
+		api.TaskPaused:          {api.TaskRunning, api.TaskCanceled},
 	}
 )
 
@@ -72,8 +75,10 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed update task request").SetInternal(err)
 		}
 
-		if taskPatch.EarliestAllowedTs != nil && !s.feature(api.FeatureTaskScheduleTime) {
-			return echo.NewHTTPError(http.StatusForbidden, api.FeatureTaskScheduleTime.AccessErrorMessage())
+		if taskPatch.EarliestAllowedTs != nil {
+			if err := s.checkFeature(api.FeatureTaskScheduleTime); err != nil {
+				return featureHTTPError(err)
+			}
 		}
 
 		issue, err := s.store.GetIssueByPipelineID(ctx, pipelineID)
@@ -129,8 +134,10 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed update task request").SetInternal(err)
 		}
 
-		if taskPatch.EarliestAllowedTs != nil && !s.feature(api.FeatureTaskScheduleTime) {
-			return echo.NewHTTPError(http.StatusForbidden, api.FeatureTaskScheduleTime.AccessErrorMessage())
+		if taskPatch.EarliestAllowedTs != nil {
+			if err := s.checkFeature(api.FeatureTaskScheduleTime); err != nil {
+				return featureHTTPError(err)
+			}
 		}
 
 		task, err := s.store.GetTaskByID(ctx, taskID)
@@ -187,6 +194,10 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, taskStatusPatch); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed update task status request").SetInternal(err)
 		}
+		// The running task is paused at the next statement boundary, so it can't be set to PAUSED directly.
+		if taskStatusPatch.Status == api.TaskPaused {
+			return echo.NewHTTPError(http.StatusBadRequest, "Use the pause request to pause the running task")
+		}
 
 		task, err := s.store.GetTaskByID(ctx, taskID)
 		if err != nil {
@@ -219,6 +230,46 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// Requests the running task to pause after its current statement, instead of killing it in the middle of a statement.
+	// The task stays RUNNING until the current statement finishes, and then becomes PAUSED. It's resumed by changing its status to RUNNING.
+	g.POST("/pipeline/:pipelineID/task/:taskID/pause", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+
+		task, err := s.store.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to pause task").SetInternal(err)
+		}
+		if task == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
+		}
+
+		currentPrincipalID := c.Get(getPrincipalIDContextKey()).(int)
+		ok, err := s.canPrincipalChangeTaskStatus(ctx, currentPrincipalID, task)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change task status").SetInternal(err)
+		}
+		if !ok {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to pause task")
+		}
+		if task.Status != api.TaskRunning {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Can not pause task in %q state", task.Status))
+		}
+		if !isTaskPausable(task) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task \"%v\" can not be paused", task.Name))
+		}
+		s.TaskScheduler.requestTaskPause(task.ID)
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, task); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal pause task \"%v\" response", task.Name)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.POST("/pipeline/:pipelineID/task/:taskID/check", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		taskID, err := strconv.Atoi(c.Param("taskID"))
@@ -246,6 +297,247 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	// Suppresses a finding in the task check result with the justification, so that it no longer blocks the task.
+	// Only the principal who can approve the task is allowed to sign off the suppression.
+	g.POST("/pipeline/:pipelineID/task/:taskID/check/:taskCheckRunID/suppress", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+		taskCheckRunID, err := strconv.Atoi(c.Param("taskCheckRunID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task check run ID is not a number: %s", c.Param("taskCheckRunID"))).SetInternal(err)
+		}
+
+		currentPrincipalID := c.Get(getPrincipalIDContextKey()).(int)
+		suppress := &api.TaskCheckResultSuppress{
+			ApproverID: currentPrincipalID,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, suppress); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed suppress task check result request").SetInternal(err)
+		}
+
+		task, err := s.store.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task ID: %d", taskID)).SetInternal(err)
+		}
+		if task == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
+		}
+
+		ok, err := s.canPrincipalChangeTaskStatus(ctx, currentPrincipalID, task)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can approve the task").SetInternal(err)
+		}
+		if !ok {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to suppress task check result")
+		}
+
+		taskCheckRunList, err := s.store.FindTaskCheckRun(ctx, &api.TaskCheckRunFind{ID: &taskCheckRunID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task check run ID: %d", taskCheckRunID)).SetInternal(err)
+		}
+		if len(taskCheckRunList) == 0 || taskCheckRunList[0].TaskID != taskID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task check run not found with ID %d", taskCheckRunID))
+		}
+		taskCheckRun := taskCheckRunList[0]
+		if taskCheckRun.Status != api.TaskCheckRunDone {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Can not suppress the result of task check run in %q state", taskCheckRun.Status))
+		}
+
+		checkResult := &api.TaskCheckRunResultPayload{}
+		if err := json.Unmarshal([]byte(taskCheckRun.Result), checkResult); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal result of task check run ID: %d", taskCheckRunID)).SetInternal(err)
+		}
+		if err := suppressTaskCheckResult(checkResult, suppress, time.Now().Unix()); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		result, err := json.Marshal(checkResult)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal result of task check run ID: %d", taskCheckRunID)).SetInternal(err)
+		}
+		taskCheckRunPatched, err := s.store.PatchTaskCheckRunStatus(ctx, &api.TaskCheckRunStatusPatch{
+			ID:        &taskCheckRun.ID,
+			UpdaterID: currentPrincipalID,
+			Status:    taskCheckRun.Status,
+			Code:      taskCheckRun.Code,
+			Result:    string(result),
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to suppress result of task check run ID: %d", taskCheckRunID)).SetInternal(err)
+		}
+
+		issue, err := s.store.GetIssueByPipelineID(ctx, task.PipelineID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue with pipeline ID: %d", task.PipelineID)).SetInternal(err)
+		}
+		if issue != nil {
+			suppressedResult := checkResult.ResultList[suppress.Index]
+			payload, err := json.Marshal(api.ActivityPipelineTaskCheckResultSuppressPayload{
+				TaskID:         task.ID,
+				TaskCheckRunID: taskCheckRun.ID,
+				Status:         suppressedResult.Status,
+				Title:          suppressedResult.Title,
+				Content:        suppressedResult.Content,
+				IssueName:      issue.Name,
+				TaskName:       task.Name,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal task check result suppression activity payload").SetInternal(err)
+			}
+			if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+				CreatorID:   currentPrincipalID,
+				ContainerID: task.PipelineID,
+				Type:        api.ActivityPipelineTaskCheckResultSuppress,
+				Level:       api.ActivityInfo,
+				Comment:     suppress.Justification,
+				Payload:     string(payload),
+			}, &ActivityMeta{
+				issue: issue,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after suppressing result of task check run ID: %d", taskCheckRunID)).SetInternal(err)
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, taskCheckRunPatched); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal task check run ID response: %d", taskCheckRunID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Cancels a running task check run, e.g. one stuck on an unreachable instance, so that the check can be rerun.
+	g.PATCH("/pipeline/:pipelineID/task/:taskID/check-run/:taskCheckRunID/cancel", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+		taskCheckRunID, err := strconv.Atoi(c.Param("taskCheckRunID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task check run ID is not a number: %s", c.Param("taskCheckRunID"))).SetInternal(err)
+		}
+
+		task, err := s.store.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task ID: %d", taskID)).SetInternal(err)
+		}
+		if task == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
+		}
+
+		currentPrincipalID := c.Get(getPrincipalIDContextKey()).(int)
+		ok, err := s.canPrincipalChangeTaskStatus(ctx, currentPrincipalID, task)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change the task status").SetInternal(err)
+		}
+		if !ok {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to cancel task check run")
+		}
+
+		taskCheckRunList, err := s.store.FindTaskCheckRun(ctx, &api.TaskCheckRunFind{ID: &taskCheckRunID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task check run ID: %d", taskCheckRunID)).SetInternal(err)
+		}
+		if len(taskCheckRunList) == 0 || taskCheckRunList[0].TaskID != taskID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task check run not found with ID %d", taskCheckRunID))
+		}
+		if status := taskCheckRunList[0].Status; status != api.TaskCheckRunRunning {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Can not cancel task check run in %q state", status))
+		}
+
+		taskCheckRunCanceled, err := s.TaskCheckScheduler.CancelTaskCheckRun(ctx, taskCheckRunID, currentPrincipalID)
+		if err != nil {
+			// The task check run may complete in the meantime.
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task check run ID %d is no longer running", taskCheckRunID))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to cancel task check run ID: %d", taskCheckRunID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, taskCheckRunCanceled); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal task check run ID response: %d", taskCheckRunID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Streams the execution log of the task as server-sent events.
+	// If the task is running, the log is streamed until the task run completes.
+	// Otherwise, the persisted log of the latest task run is sent.
+	g.GET("/pipeline/:pipelineID/task/:taskID/log", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+
+		task, err := s.store.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task ID: %d", taskID)).SetInternal(err)
+		}
+		if task == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
+		}
+
+		var buffer *taskRunLogBuffer
+		if s.TaskScheduler != nil {
+			if v, ok := s.TaskScheduler.taskRunLog.Load(taskID); ok {
+				buffer = v.(*taskRunLogBuffer)
+			}
+		}
+		if buffer == nil {
+			// Fallback to the log persisted in the latest task run.
+			buffer = &taskRunLogBuffer{}
+			var latestTaskRun *api.TaskRun
+			for _, taskRun := range task.TaskRunList {
+				if latestTaskRun == nil || taskRun.ID > latestTaskRun.ID {
+					latestTaskRun = taskRun
+				}
+			}
+			if latestTaskRun != nil && latestTaskRun.Result != "" {
+				result := &api.TaskRunResultPayload{}
+				if err := json.Unmarshal([]byte(latestTaskRun.Result), result); err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal result of task run ID: %d", latestTaskRun.ID)).SetInternal(err)
+				}
+				buffer.logs = result.ExecutionLog
+			}
+			buffer.finish()
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+		c.Response().WriteHeader(http.StatusOK)
+
+		ticker := time.NewTicker(taskRunLogStreamInterval)
+		defer ticker.Stop()
+		offset := 0
+		for {
+			logList, done := buffer.list(offset)
+			for _, taskRunLog := range logList {
+				bytes, err := json.Marshal(taskRunLog)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal task run log").SetInternal(err)
+				}
+				if _, err := fmt.Fprintf(c.Response().Writer, "data: %s\n\n", bytes); err != nil {
+					return err
+				}
+			}
+			offset += len(logList)
+			c.Response().Flush()
+			if done && len(logList) == 0 {
+				return nil
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
 }
 
 func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.TaskPatch, issue *api.Issue) (*api.Task, *echo.HTTPError) {
@@ -256,6 +548,11 @@ func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.T
 			return nil, httpErr
 		}
 		newStatement = *taskPatch.Statement
+		if task.Database != nil && (task.Type == api.TaskDatabaseSchemaUpdate || task.Type == api.TaskDatabaseDataUpdate) {
+			if _, err := api.ResolveStatementVariables(newStatement, task.Database); err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid statement: %v", err)).SetInternal(err)
+			}
+		}
 
 		switch task.Type {
 		case api.TaskDatabaseSchemaUpdate:
@@ -265,6 +562,9 @@ func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.T
 			}
 			oldStatement = payload.Statement
 			payload.Statement = *taskPatch.Statement
+			// The rollback statement is generated again once the new statement is executed.
+			payload.RollbackStatement = ""
+			payload.ExecutedStatementCount = 0
 			// We should update the schema version if we've updated the SQL, otherwise we will
 			// get migration history version conflict if the previous task has been attempted.
 			payload.SchemaVersion = common.DefaultMigrationVersion()
@@ -281,6 +581,7 @@ func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.T
 			}
 			oldStatement = payload.Statement
 			payload.Statement = *taskPatch.Statement
+			payload.ExecutedStatementCount = 0
 			// We should update the schema version if we've updated the SQL, otherwise we will
 			// get migration history version conflict if the previous task has been attempted.
 			payload.SchemaVersion = common.DefaultMigrationVersion()
@@ -498,6 +799,36 @@ func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.T
 	return taskPatched, nil
 }
 
+// suppressTaskCheckResult marks the result at the index as suppressed by the approver.
+// Only the findings from the SQL advisor can be suppressed, because the other check failures such as connection errors can not be waived.
+func suppressTaskCheckResult(checkResult *api.TaskCheckRunResultPayload, suppress *api.TaskCheckResultSuppress, suppressedTs int64) error {
+	if suppress.Justification == "" {
+		return errors.Errorf("justification is required to suppress the task check result")
+	}
+	if suppress.Index < 0 || suppress.Index >= len(checkResult.ResultList) {
+		return errors.Errorf("task check result index %d is out of range", suppress.Index)
+	}
+	result := &checkResult.ResultList[suppress.Index]
+	if result.Namespace != api.AdvisorNamespace {
+		return errors.Errorf("only the SQL advisor result can be suppressed, but got namespace %q", result.Namespace)
+	}
+	if result.Code == advisor.StatementSyntaxError.Int() {
+		return errors.Errorf("the syntax error can not be suppressed")
+	}
+	if result.Status == api.TaskCheckStatusSuccess {
+		return errors.Errorf("the task check result %q is already successful", result.Title)
+	}
+	if result.Suppression != nil {
+		return errors.Errorf("the task check result %q is already suppressed", result.Title)
+	}
+	result.Suppression = &api.TaskCheckResultSuppression{
+		Justification: suppress.Justification,
+		ApproverID:    suppress.ApproverID,
+		SuppressedTs:  suppressedTs,
+	}
+	return nil
+}
+
 // canPrincipalBeAssignee checks if a principal could be the assignee of an issue, judging by the principal role and the environment policy.
 func (s *Server) canPrincipalBeAssignee(ctx context.Context, principalID int, environmentID int, projectID int, issueType api.IssueType) (bool, error) {
 	policy, err := s.store.GetPipelineApprovalPolicy(ctx, environmentID)
@@ -629,11 +960,26 @@ func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatus
 			Code: common.Invalid,
 			Err:  errors.Errorf("invalid task status transition from %v to %v. Applicable transition(s) %v", task.Status, taskStatusPatch.Status, applicableTaskStatusTransition[task.Status])}
 	}
+	// The review comments on the statement must be resolved before the task is approved.
+	if task.Status == api.TaskPendingApproval && taskStatusPatch.Status == api.TaskPending {
+		count, err := s.store.CountUnresolvedStatementComment(ctx, task.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to count the unresolved statement comments of task %v(%v)", task.ID, task.Name)
+		}
+		if count > 0 {
+			return nil, &common.Error{
+				Code: common.Invalid,
+				Err:  errors.Errorf("task %q has %d unresolved statement comment(s), resolve them before approving the task", task.Name, count)}
+		}
+	}
 
 	taskPatched, err := s.store.PatchTaskStatus(ctx, taskStatusPatch)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to change task %v(%v) status", task.ID, task.Name)
 	}
+	if task.Status == api.TaskRunning && taskPatched.Status == api.TaskCanceled {
+		s.TaskScheduler.CancelTaskRun(task.ID)
+	}
 
 	// Most tasks belong to a pipeline which in turns belongs to an issue. The followup code
 	// behaves differently depending on whether the task is wrapped in an issue.
@@ -689,6 +1035,17 @@ func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatus
 		return nil, err
 	}
 
+	// Hold the remaining tasks of the stage if required by the task failure policy.
+	if taskPatched.Status == api.TaskFailed && issue != nil {
+		if err := s.pauseStageOnTaskFailure(ctx, issue, taskPatched); err != nil {
+			log.Error("Failed to pause stage after task failure",
+				zap.Int("task_id", taskPatched.ID),
+				zap.String("task_name", taskPatched.Name),
+				zap.Error(err),
+			)
+		}
+	}
+
 	// If create database, schema update and gh-ost cutover task completes, we sync the corresponding instance schema immediately.
 	if (taskPatched.Type == api.TaskDatabaseCreate || taskPatched.Type == api.TaskDatabaseSchemaUpdate || taskPatched.Type == api.TaskDatabaseSchemaUpdateGhostCutover) && taskPatched.Status == api.TaskDone {
 		instance, err := s.store.GetInstanceByID(ctx, task.InstanceID)
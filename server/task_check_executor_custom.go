@@ -0,0 +1,251 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+const (
+	defaultTaskCheckPluginTimeout = 60 * time.Second
+	// maxTaskCheckPluginOutputSize is the max size of the output read from a plugin, the results are persisted with the task check run.
+	maxTaskCheckPluginOutputSize = 1 << 20
+)
+
+// NewTaskCheckCustomExecutor creates a task check custom executor.
+func NewTaskCheckCustomExecutor() TaskCheckExecutor {
+	return &TaskCheckCustomExecutor{}
+}
+
+// TaskCheckCustomExecutor is the task check custom executor.
+// It sends the task check payload to every custom task check plugin and collects their results.
+type TaskCheckCustomExecutor struct {
+}
+
+// Run will run the task check custom executor once.
+func (*TaskCheckCustomExecutor) Run(ctx context.Context, server *Server, taskCheckRun *api.TaskCheckRun) (result []api.TaskCheckResult, err error) {
+	setting, err := server.getTaskCheckCustomSetting(ctx)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.Wrap(err, common.Internal)
+	}
+	for _, plugin := range setting.PluginList {
+		result = append(result, runTaskCheckPlugin(ctx, plugin, []byte(taskCheckRun.Payload), server.profile.EnableTaskCheckCommand)...)
+	}
+	// The plugins may be removed after the check is scheduled.
+	if len(result) == 0 {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.CustomNamespace,
+				Code:      common.Ok.Int(),
+				Title:     "OK",
+				Content:   "No custom task check plugin is configured",
+			},
+		}, nil
+	}
+	return result, nil
+}
+
+// runTaskCheckPlugin runs the plugin with the payload, the failure to run it is reported as an error result.
+func runTaskCheckPlugin(ctx context.Context, plugin api.TaskCheckCustomPlugin, payload []byte, enableCommand bool) []api.TaskCheckResult {
+	output, err := callTaskCheckPlugin(ctx, plugin, payload, enableCommand)
+	if err != nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.CustomNamespace,
+				Code:      common.Internal.Int(),
+				Title:     fmt.Sprintf("Failed to run task check plugin %q", plugin.Name),
+				Content:   err.Error(),
+			},
+		}
+	}
+	result, err := parseTaskCheckPluginOutput(plugin.Name, output)
+	if err != nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.CustomNamespace,
+				Code:      common.Invalid.Int(),
+				Title:     fmt.Sprintf("Invalid output of task check plugin %q", plugin.Name),
+				Content:   err.Error(),
+			},
+		}
+	}
+	return result
+}
+
+// callTaskCheckPlugin sends the payload to the HTTP handler or the command of the plugin, and returns its output.
+func callTaskCheckPlugin(ctx context.Context, plugin api.TaskCheckCustomPlugin, payload []byte, enableCommand bool) ([]byte, error) {
+	timeout := defaultTaskCheckPluginTimeout
+	if plugin.TimeoutSeconds > 0 {
+		timeout = time.Duration(plugin.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if plugin.URL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, plugin.URL, bytes.NewReader(payload))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to construct POST request to %s", plugin.URL)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		client := &http.Client{
+			Transport: common.OutboundTransport,
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to POST %s", plugin.URL)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(io.LimitReader(resp.Body, maxTaskCheckPluginOutputSize+1))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read response from %s", plugin.URL)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("failed to POST %s, status code: %d, response body: %s", plugin.URL, resp.StatusCode, truncateTaskCheckPluginOutput(b))
+		}
+		if len(b) > maxTaskCheckPluginOutputSize {
+			return nil, errors.Errorf("response from %s exceeds %d bytes", plugin.URL, maxTaskCheckPluginOutputSize)
+		}
+		return b, nil
+	}
+
+	if !enableCommand {
+		return nil, errors.Errorf("running commands is disabled, start Bytebase with --enable-task-check-command to allow it")
+	}
+	if len(plugin.Command) == 0 {
+		return nil, errors.Errorf("neither URL nor command is set")
+	}
+	cmd := exec.CommandContext(ctx, plugin.Command[0], plugin.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, errors.Errorf("command %q timed out after %v", plugin.Command[0], timeout)
+		}
+		return nil, errors.Wrapf(err, "command %q failed, stderr: %s", plugin.Command[0], truncateTaskCheckPluginOutput(stderr.Bytes()))
+	}
+	if stdout.Len() > maxTaskCheckPluginOutputSize {
+		return nil, errors.Errorf("output of command %q exceeds %d bytes", plugin.Command[0], maxTaskCheckPluginOutputSize)
+	}
+	return stdout.Bytes(), nil
+}
+
+// truncateTaskCheckPluginOutput truncates the output quoted in the error, which is persisted with the task check run.
+func truncateTaskCheckPluginOutput(output []byte) string {
+	const maxLen = 1024
+	s := strings.TrimSpace(string(output))
+	if len(s) > maxLen {
+		return s[:maxLen] + "..."
+	}
+	return s
+}
+
+// parseTaskCheckPluginOutput maps the JSON output of the plugin to the task check results, the titles are prefixed with the plugin name.
+// The output without any result means the check passed.
+func parseTaskCheckPluginOutput(name string, output []byte) ([]api.TaskCheckResult, error) {
+	response := &api.TaskCheckCustomResponse{}
+	if err := json.Unmarshal(output, response); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %q", truncateTaskCheckPluginOutput(output))
+	}
+	if len(response.ResultList) == 0 {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.CustomNamespace,
+				Code:      common.Ok.Int(),
+				Title:     fmt.Sprintf("[%s] OK", name),
+			},
+		}, nil
+	}
+	var result []api.TaskCheckResult
+	for i, r := range response.ResultList {
+		switch r.Status {
+		case api.TaskCheckStatusSuccess, api.TaskCheckStatusWarn, api.TaskCheckStatusError:
+		default:
+			return nil, errors.Errorf("result %d has invalid status %q, must be one of SUCCESS, WARN and ERROR", i, r.Status)
+		}
+		if r.Title == "" {
+			return nil, errors.Errorf("result %d has empty title", i)
+		}
+		result = append(result, api.TaskCheckResult{
+			Status:    r.Status,
+			Namespace: api.CustomNamespace,
+			Code:      r.Code,
+			Title:     fmt.Sprintf("[%s] %s", name, r.Title),
+			Content:   r.Content,
+			Line:      r.Line,
+		})
+	}
+	return result, nil
+}
+
+// getTaskCheckCustomSetting returns the custom task check setting, which is empty if not configured.
+func (s *Server) getTaskCheckCustomSetting(ctx context.Context) (*api.TaskCheckCustomSetting, error) {
+	settingName := api.SettingTaskCheckCustom
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return nil, err
+	}
+	if len(settingList) == 0 {
+		return &api.TaskCheckCustomSetting{}, nil
+	}
+	setting := &api.TaskCheckCustomSetting{}
+	if err := json.Unmarshal([]byte(settingList[0].Value), setting); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting %s", settingName)
+	}
+	return setting, nil
+}
+
+// parseTaskCheckCustomSetting parses and validates the custom task check setting.
+func parseTaskCheckCustomSetting(value string, enableCommand bool) (*api.TaskCheckCustomSetting, error) {
+	setting := &api.TaskCheckCustomSetting{}
+	if err := json.Unmarshal([]byte(value), setting); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal custom task check setting")
+	}
+	nameSet := make(map[string]bool)
+	for _, plugin := range setting.PluginList {
+		if plugin.Name == "" {
+			return nil, errors.Errorf("plugin name must not be empty")
+		}
+		if nameSet[plugin.Name] {
+			return nil, errors.Errorf("duplicate plugin name %q", plugin.Name)
+		}
+		nameSet[plugin.Name] = true
+		if (plugin.URL == "") == (len(plugin.Command) == 0) {
+			return nil, errors.Errorf("plugin %q must set exactly one of url and command", plugin.Name)
+		}
+		if plugin.URL != "" {
+			if u, err := url.ParseRequestURI(plugin.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, errors.Errorf("plugin %q url must be an http or https URL, got %q", plugin.Name, plugin.URL)
+			}
+		}
+		if len(plugin.Command) > 0 {
+			if !enableCommand {
+				return nil, errors.Errorf("plugin %q runs a command, which requires starting Bytebase with --enable-task-check-command", plugin.Name)
+			}
+			if plugin.Command[0] == "" {
+				return nil, errors.Errorf("plugin %q command must not be empty", plugin.Name)
+			}
+		}
+		if plugin.TimeoutSeconds < 0 {
+			return nil, errors.Errorf("plugin %q timeoutSeconds must be non-negative, got %d", plugin.Name, plugin.TimeoutSeconds)
+		}
+	}
+	return setting, nil
+}
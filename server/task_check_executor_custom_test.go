@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestParseTaskCheckPluginOutput(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    []api.TaskCheckResult
+		wantErr bool
+	}{
+		{
+			output: `{"resultList":[]}`,
+			want: []api.TaskCheckResult{
+				{Status: api.TaskCheckStatusSuccess, Namespace: api.CustomNamespace, Code: common.Ok.Int(), Title: "[ticket] OK"},
+			},
+		},
+		{
+			output: `{"resultList":[{"status":"ERROR","code":101,"title":"Missing ticket","content":"Link a change ticket.","line":3},{"status":"WARN","title":"Large table"}]}`,
+			want: []api.TaskCheckResult{
+				{Status: api.TaskCheckStatusError, Namespace: api.CustomNamespace, Code: 101, Title: "[ticket] Missing ticket", Content: "Link a change ticket.", Line: 3},
+				{Status: api.TaskCheckStatusWarn, Namespace: api.CustomNamespace, Title: "[ticket] Large table"},
+			},
+		},
+		{
+			output:  `{"resultList":[{"status":"FATAL","title":"Missing ticket"}]}`,
+			wantErr: true,
+		},
+		{
+			output:  `{"resultList":[{"status":"ERROR"}]}`,
+			wantErr: true,
+		},
+		{
+			output:  `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := parseTaskCheckPluginOutput("ticket", []byte(test.output))
+		if test.wantErr {
+			require.Error(t, err, test.output)
+			continue
+		}
+		require.NoError(t, err, test.output)
+		require.Equal(t, test.want, got, test.output)
+	}
+}
+
+func TestParseTaskCheckCustomSetting(t *testing.T) {
+	tests := []struct {
+		value         string
+		enableCommand bool
+		wantErr       bool
+	}{
+		{value: `{"pluginList":[]}`},
+		{value: `{"pluginList":[{"name":"ticket","url":"https://gate.example.com/check","timeoutSeconds":10}]}`},
+		{value: `{"pluginList":[{"name":"ticket","command":["/usr/local/bin/check"]}]}`, enableCommand: true},
+		// The command is rejected unless enabled by the flag.
+		{value: `{"pluginList":[{"name":"ticket","command":["/usr/local/bin/check"]}]}`, wantErr: true},
+		{value: `{"pluginList":[{"name":"ticket"}]}`, wantErr: true},
+		{value: `{"pluginList":[{"name":"ticket","url":"https://gate.example.com","command":["check"]}]}`, enableCommand: true, wantErr: true},
+		{value: `{"pluginList":[{"name":"ticket","url":"ftp://gate.example.com"}]}`, wantErr: true},
+		{value: `{"pluginList":[{"name":"","url":"https://gate.example.com"}]}`, wantErr: true},
+		{value: `{"pluginList":[{"name":"ticket","url":"https://a.example.com"},{"name":"ticket","url":"https://b.example.com"}]}`, wantErr: true},
+		{value: `{"pluginList":[{"name":"ticket","url":"https://gate.example.com","timeoutSeconds":-1}]}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		_, err := parseTaskCheckCustomSetting(test.value, test.enableCommand)
+		if test.wantErr {
+			require.Error(t, err, test.value)
+		} else {
+			require.NoError(t, err, test.value)
+		}
+	}
+}
+
+func TestRunTaskCheckPlugin(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	payload := []byte(`{"taskId":1,"statement":"DROP TABLE t"}`)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || string(body) != string(payload) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"resultList":[{"status":"ERROR","title":"DROP is not allowed"}]}`))
+	}))
+	defer srv.Close()
+
+	result := runTaskCheckPlugin(ctx, api.TaskCheckCustomPlugin{Name: "http", URL: srv.URL}, payload, false)
+	a.Equal([]api.TaskCheckResult{
+		{Status: api.TaskCheckStatusError, Namespace: api.CustomNamespace, Title: "[http] DROP is not allowed"},
+	}, result)
+
+	// The command echoes the payload back as the content.
+	command := []string{"sh", "-c", `printf '{"resultList":[{"status":"WARN","title":"Echo","content":%s}]}' "$(cat | sed 's/"/\\"/g; s/.*/"&"/')"`}
+	result = runTaskCheckPlugin(ctx, api.TaskCheckCustomPlugin{Name: "cmd", Command: command}, payload, true)
+	a.Equal([]api.TaskCheckResult{
+		{Status: api.TaskCheckStatusWarn, Namespace: api.CustomNamespace, Title: "[cmd] Echo", Content: string(payload)},
+	}, result)
+
+	// The command isn't run unless enabled.
+	result = runTaskCheckPlugin(ctx, api.TaskCheckCustomPlugin{Name: "cmd", Command: command}, payload, false)
+	a.Len(result, 1)
+	a.Equal(api.TaskCheckStatusError, result[0].Status)
+	a.Equal(`Failed to run task check plugin "cmd"`, result[0].Title)
+
+	result = runTaskCheckPlugin(ctx, api.TaskCheckCustomPlugin{Name: "cmd", Command: []string{"sh", "-c", "echo denied >&2; exit 1"}}, payload, true)
+	a.Len(result, 1)
+	a.Equal(api.TaskCheckStatusError, result[0].Status)
+	a.Contains(result[0].Content, "denied")
+}
@@ -31,8 +31,8 @@ func (*TaskCheckStatementAdvisorCompositeExecutor) Run(ctx context.Context, serv
 	if taskCheckRun.Type != api.TaskCheckDatabaseStatementAdvise {
 		return nil, common.Errorf(common.Invalid, "invalid check statement advisor composite type: %v", taskCheckRun.Type)
 	}
-	if !server.feature(api.FeatureSQLReviewPolicy) {
-		return nil, common.Errorf(common.NotAuthorized, api.FeatureSQLReviewPolicy.AccessErrorMessage())
+	if err := server.checkFeature(api.FeatureSQLReviewPolicy); err != nil {
+		return nil, common.Wrap(err, common.NotAuthorized)
 	}
 
 	payload := &api.TaskCheckDatabaseStatementAdvisePayload{}
@@ -61,7 +61,19 @@ func (*TaskCheckStatementAdvisorCompositeExecutor) Run(ctx context.Context, serv
 		return nil, common.Wrapf(err, common.Internal, "failed to get task by id")
 	}
 
+	// The project may layer overrides on top of the environment SQL review policy.
+	if task.Database != nil {
+		if err := server.store.MergeProjectSQLReviewOverride(ctx, task.Database.ProjectID, policy); err != nil {
+			return nil, common.Wrapf(err, common.Internal, "failed to merge project SQL review override")
+		}
+	}
+
 	catalog := store.NewCatalog(task.DatabaseID, server.store, payload.DbType)
+	// The advice is tailored to the engine version, e.g. the columns are added without rebuilding the table on the recent versions.
+	engineVersion := ""
+	if task.Instance != nil {
+		engineVersion = task.Instance.EngineVersion
+	}
 
 	dbType, err := advisorDB.ConvertToAdvisorDBType(string(payload.DbType))
 	if err != nil {
@@ -69,10 +81,11 @@ func (*TaskCheckStatementAdvisorCompositeExecutor) Run(ctx context.Context, serv
 	}
 
 	adviceList, err := advisor.SQLReviewCheck(payload.Statement, policy.RuleList, advisor.SQLReviewCheckContext{
-		Charset:   payload.Charset,
-		Collation: payload.Collation,
-		DbType:    dbType,
-		Catalog:   catalog,
+		Charset:       payload.Charset,
+		Collation:     payload.Collation,
+		DbType:        dbType,
+		EngineVersion: engineVersion,
+		Catalog:       catalog,
 	})
 	if err != nil {
 		return nil, err
@@ -96,6 +109,7 @@ func (*TaskCheckStatementAdvisorCompositeExecutor) Run(ctx context.Context, serv
 			Code:      advice.Code.Int(),
 			Title:     advice.Title,
 			Content:   advice.Content,
+			Line:      advice.Line,
 		})
 	}
 
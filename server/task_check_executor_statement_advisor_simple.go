@@ -78,6 +78,7 @@ func (*TaskCheckStatementAdvisorSimpleExecutor) Run(_ context.Context, _ *Server
 			Code:      advice.Code.Int(),
 			Title:     advice.Title,
 			Content:   advice.Content,
+			Line:      advice.Line,
 		})
 	}
 
@@ -0,0 +1,390 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+)
+
+// NewTaskCheckStatementDryRunExecutor creates a task check statement dry run executor.
+func NewTaskCheckStatementDryRunExecutor() TaskCheckExecutor {
+	return &TaskCheckStatementDryRunExecutor{}
+}
+
+// TaskCheckStatementDryRunExecutor is the task check statement dry run executor.
+// It validates the statement against the target database without modifying data:
+// every statement is parsed, and the DML statements are explained in a read-only transaction which is always rolled back.
+type TaskCheckStatementDryRunExecutor struct {
+}
+
+// dryRunStatement is a single statement to dry run, explain is set if the statement should be explained.
+type dryRunStatement struct {
+	text    string
+	explain bool
+}
+
+// Run will run the task check statement dry run executor once.
+func (*TaskCheckStatementDryRunExecutor) Run(ctx context.Context, server *Server, taskCheckRun *api.TaskCheckRun) (result []api.TaskCheckResult, err error) {
+	task, err := server.store.GetTaskByID(ctx, taskCheckRun.TaskID)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.Wrap(err, common.Internal)
+	}
+	if task == nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.Internal.Int(),
+				Title:     fmt.Sprintf("Failed to find task %v", taskCheckRun.TaskID),
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	payload := &api.TaskCheckDatabaseStatementDryRunPayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return nil, common.Wrapf(err, common.Invalid, "invalid check statement dry run payload")
+	}
+
+	database, err := server.store.GetDatabase(ctx, &api.DatabaseFind{ID: task.DatabaseID})
+	if err != nil {
+		return []api.TaskCheckResult{}, common.Wrap(err, common.Internal)
+	}
+	if database == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "database ID not found %v", task.DatabaseID)
+	}
+
+	// The secrets are resolved so that the statement matches the one to be executed,
+	// and redacted from the results since the results are persisted.
+	secretMap, err := server.store.GetDatabaseSecretMap(ctx, database.ID)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.Wrap(err, common.Internal)
+	}
+	defer func() {
+		for i := range result {
+			result[i].Title = redactStatementSecrets(result[i].Title, secretMap)
+			result[i].Content = redactStatementSecrets(result[i].Content, secretMap)
+		}
+	}()
+	statement, err := api.ResolveStatementSecrets(payload.Statement, secretMap)
+	if err != nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.Invalid.Int(),
+				Title:     "Failed to resolve secrets",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	var stmtList []dryRunStatement
+	switch payload.DbType {
+	case db.Postgres:
+		stmtList, err = getPostgreSQLDryRunStatementList(statement)
+	case db.MySQL, db.TiDB:
+		stmtList, err = getMySQLDryRunStatementList(statement, payload.Charset, payload.Collation)
+	default:
+		return nil, common.Errorf(common.Invalid, "invalid check statement dry run database type: %s", payload.DbType)
+	}
+	if err != nil {
+		//nolint:nilerr
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.AdvisorNamespace,
+				Code:      advisor.StatementSyntaxError.Int(),
+				Title:     "Syntax error",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	if payload.DbType == db.MySQL || payload.DbType == db.TiDB {
+		problemList, err := getMySQLIncompatibilityList(payload.DbType, statement, payload.Charset, payload.Collation, database.Instance.EngineVersion)
+		if err != nil {
+			return []api.TaskCheckResult{}, common.Wrap(err, common.Internal)
+		}
+		for _, problem := range problemList {
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.Invalid.Int(),
+				Title:     fmt.Sprintf("Incompatible with %s", getMySQLEngineName(payload.DbType)),
+				Content:   problem,
+			})
+		}
+	}
+
+	driver, err := server.getAdminDatabaseDriver(ctx, database.Instance, database.Name)
+	if err != nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.DbConnectionFailure.Int(),
+				Title:     fmt.Sprintf("Failed to connect %q", database.Name),
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+	defer driver.Close(ctx)
+
+	sqlDB, err := driver.GetDBConnection(ctx, database.Name)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.Wrap(err, common.Internal)
+	}
+	tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return []api.TaskCheckResult{}, common.Wrap(err, common.DbExecutionError)
+	}
+	// The transaction is always rolled back, the dry run never modifies data.
+	defer tx.Rollback()
+
+	explainCount := 0
+	for _, stmt := range stmtList {
+		if !stmt.explain {
+			continue
+		}
+		explainCount++
+		plan, err := explainStatement(ctx, tx, stmt.text)
+		if err != nil {
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.DbExecutionError.Int(),
+				Title:     "Failed to explain statement",
+				Content:   fmt.Sprintf("%q failed with: %s", stmt.text, err.Error()),
+			})
+			continue
+		}
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "Query plan",
+			Content:   fmt.Sprintf("%s\n\n%s", stmt.text, plan),
+		})
+	}
+
+	if len(result) == 0 {
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   fmt.Sprintf("Validated %d statement(s), %d explained", len(stmtList), explainCount),
+		})
+	}
+
+	return result, nil
+}
+
+func getMySQLDryRunStatementList(statement string, charset string, collation string) ([]dryRunStatement, error) {
+	p := tidbparser.New()
+	// To support MySQL8 window function syntax.
+	// See https://github.com/bytebase/bytebase/issues/175.
+	p.EnableWindowFunc(true)
+
+	stmts, _, err := p.Parse(statement, charset, collation)
+	if err != nil {
+		return nil, err
+	}
+	var stmtList []dryRunStatement
+	for _, node := range stmts {
+		explain := false
+		switch node.(type) {
+		case *tidbast.SelectStmt, *tidbast.SetOprStmt, *tidbast.InsertStmt, *tidbast.UpdateStmt, *tidbast.DeleteStmt:
+			explain = true
+		}
+		stmtList = append(stmtList, dryRunStatement{text: strings.TrimSpace(node.Text()), explain: explain})
+	}
+	return stmtList, nil
+}
+
+// getMySQLIncompatibilityList returns the problems of the statements which the MySQL or TiDB version rejects.
+// TiDB runs adding the indexes and constraints in place and the other changes instantly, and rejects the other algorithms.
+// The features depending on the engine version, e.g. ALGORITHM=INSTANT, invisible indexes and different changes in a single
+// ALTER TABLE, are checked against the capabilities of the version, which is skipped if the version is unknown.
+func getMySQLIncompatibilityList(engine db.Type, statement string, charset string, collation string, engineVersion string) ([]string, error) {
+	p := tidbparser.New()
+	p.EnableWindowFunc(true)
+
+	stmts, _, err := p.Parse(statement, charset, collation)
+	if err != nil {
+		return nil, err
+	}
+	engineName := getMySQLEngineName(engine)
+	version, versionErr := db.ParseEngineVersion(engine, engineVersion)
+	// lacks returns true if the engine version is known and doesn't support the capability.
+	lacks := func(capability db.Capability) bool {
+		if versionErr != nil {
+			return false
+		}
+		supported, err := db.HasCapability(engine, engineVersion, capability)
+		return err == nil && !supported
+	}
+	// upgradeHint returns the hint of upgrading to the first version supporting the capability.
+	upgradeHint := func(capability db.Capability) string {
+		minVersion, ok := db.GetCapabilityMinVersion(engine, capability)
+		if !ok {
+			return ""
+		}
+		return fmt.Sprintf(" or upgrade to %s", minVersion)
+	}
+
+	var problemList []string
+	for _, node := range stmts {
+		text := strings.TrimSpace(node.Text())
+		invisibleIndex := false
+		switch node := node.(type) {
+		case *tidbast.CreateIndexStmt:
+			invisibleIndex = isInvisibleIndexOption(node.IndexOption)
+		case *tidbast.CreateTableStmt:
+			for _, constraint := range node.Constraints {
+				invisibleIndex = invisibleIndex || isInvisibleIndexOption(constraint.Option)
+			}
+		case *tidbast.AlterTableStmt:
+			algorithm := tidbast.AlgorithmTypeDefault
+			var changeList []*tidbast.AlterTableSpec
+			for _, spec := range node.Specs {
+				switch spec.Tp {
+				case tidbast.AlterTableAlgorithm:
+					algorithm = spec.Algorithm
+				case tidbast.AlterTableLock:
+				default:
+					changeList = append(changeList, spec)
+				}
+				switch spec.Tp {
+				case tidbast.AlterTableAddConstraint:
+					invisibleIndex = invisibleIndex || isInvisibleIndexOption(spec.Constraint.Option)
+				case tidbast.AlterTableIndexInvisible:
+					invisibleIndex = invisibleIndex || spec.Visibility == tidbast.IndexVisibilityInvisible
+				}
+			}
+
+			if engine == db.TiDB && algorithm != tidbast.AlgorithmTypeDefault {
+				for _, change := range changeList {
+					supported := tidbast.AlgorithmTypeInstant
+					if change.Tp == tidbast.AlterTableAddConstraint {
+						supported = tidbast.AlgorithmTypeInplace
+					}
+					if algorithm != supported {
+						problemList = append(problemList, fmt.Sprintf("%q: TiDB only supports ALGORITHM=%s for the change, but got ALGORITHM=%s.", text, supported, algorithm))
+						break
+					}
+				}
+			}
+			if engine == db.MySQL && algorithm == tidbast.AlgorithmTypeInstant && lacks(db.CapabilityInstantDDL) {
+				problemList = append(problemList, fmt.Sprintf("%q: %s %s doesn't support ALGORITHM=INSTANT, use ALGORITHM=INPLACE%s.", text, engineName, version, upgradeHint(db.CapabilityInstantDDL)))
+			}
+
+			if len(changeList) > 1 && lacks(db.CapabilityMultiSchemaChange) {
+				for _, change := range changeList {
+					if change.Tp != changeList[0].Tp || (change.Tp != tidbast.AlterTableAddColumns && change.Tp != tidbast.AlterTableDropColumn) {
+						problemList = append(problemList, fmt.Sprintf("%q: %s %s only supports adding or dropping multiple columns in a single ALTER TABLE, split the changes into separate statements%s.", text, engineName, version, upgradeHint(db.CapabilityMultiSchemaChange)))
+						break
+					}
+				}
+			}
+		}
+		if invisibleIndex && lacks(db.CapabilityInvisibleIndex) {
+			problemList = append(problemList, fmt.Sprintf("%q: %s %s doesn't support invisible indexes, remove the INVISIBLE option%s.", text, engineName, version, upgradeHint(db.CapabilityInvisibleIndex)))
+		}
+	}
+	return problemList, nil
+}
+
+// getMySQLEngineName returns the display name of the MySQL compatible engine.
+func getMySQLEngineName(engine db.Type) string {
+	if engine == db.TiDB {
+		return "TiDB"
+	}
+	return "MySQL"
+}
+
+// isInvisibleIndexOption returns true if the index option makes the index invisible.
+func isInvisibleIndexOption(option *tidbast.IndexOption) bool {
+	return option != nil && option.Visibility == tidbast.IndexVisibilityInvisible
+}
+
+func getPostgreSQLDryRunStatementList(statement string) ([]dryRunStatement, error) {
+	stmts, err := parser.Parse(parser.Postgres, parser.Context{}, statement)
+	if err != nil {
+		return nil, err
+	}
+	var stmtList []dryRunStatement
+	for _, node := range stmts {
+		explain := false
+		switch node.(type) {
+		case *ast.SelectStmt, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+			explain = true
+		}
+		stmtList = append(stmtList, dryRunStatement{text: strings.TrimSpace(node.Text()), explain: explain})
+	}
+	return stmtList, nil
+}
+
+// explainStatement returns the query plan of the statement, EXPLAIN without ANALYZE never executes the statement.
+func explainStatement(ctx context.Context, tx *sql.Tx, statement string) (string, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("EXPLAIN %s", strings.TrimSuffix(statement, ";")))
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columnList, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	var lineList []string
+	if len(columnList) > 1 {
+		lineList = append(lineList, strings.Join(columnList, " | "))
+	}
+	for rows.Next() {
+		valueList := make([]sql.NullString, len(columnList))
+		dest := make([]interface{}, len(columnList))
+		for i := range valueList {
+			dest[i] = &valueList[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return "", err
+		}
+		var fieldList []string
+		for _, value := range valueList {
+			if value.Valid {
+				fieldList = append(fieldList, value.String)
+			} else {
+				fieldList = append(fieldList, "NULL")
+			}
+		}
+		lineList = append(lineList, strings.Join(fieldList, " | "))
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(lineList, "\n"), nil
+}
+
+// redactStatementSecrets replaces the secret values in the text with their {{secret.NAME}} references.
+func redactStatementSecrets(text string, secrets map[string]string) string {
+	for name, value := range secrets {
+		if value == "" {
+			continue
+		}
+		text = strings.ReplaceAll(text, value, fmt.Sprintf("{{secret.%s}}", name))
+	}
+	return text
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"testing"
+
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetMySQLIncompatibilityList(t *testing.T) {
+	a := require.New(t)
+	tests := []struct {
+		engine        db.Type
+		statement     string
+		engineVersion string
+		wantCount     int
+	}{
+		{
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ALGORITHM=INSTANT;",
+			engineVersion: "5.7.25-TiDB-v6.1.0",
+			wantCount:     0,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ADD INDEX idx_a (a), ALGORITHM=INPLACE;",
+			engineVersion: "5.7.25-TiDB-v6.1.0",
+			wantCount:     0,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ALGORITHM=INPLACE;",
+			engineVersion: "5.7.25-TiDB-v6.1.0",
+			wantCount:     1,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ADD INDEX idx_a (a), ALGORITHM=INSTANT;",
+			engineVersion: "5.7.25-TiDB-v6.1.0",
+			wantCount:     1,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ALGORITHM=COPY; ALTER TABLE t DROP COLUMN b, ALGORITHM=COPY;",
+			engineVersion: "5.7.25-TiDB-v6.1.0",
+			wantCount:     2,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ADD COLUMN b INT; ALTER TABLE t DROP COLUMN c, DROP COLUMN d;",
+			engineVersion: "5.7.25-TiDB-v6.1.0",
+			wantCount:     0,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ADD INDEX idx_a (a);",
+			engineVersion: "5.7.25-TiDB-v6.1.0",
+			wantCount:     1,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ADD INDEX idx_a (a);",
+			engineVersion: "5.7.25-TiDB-v6.2.0",
+			wantCount:     0,
+		},
+		{
+			// The version gated checks are skipped for the unknown version.
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ADD INDEX idx_a (a);",
+			engineVersion: "",
+			wantCount:     0,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "CREATE TABLE t (a INT); INSERT INTO t VALUES (1);",
+			engineVersion: "5.7.25-TiDB-v6.1.0",
+			wantCount:     0,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "CREATE INDEX idx_a ON t (a) INVISIBLE;",
+			engineVersion: "5.7.25-TiDB-v4.0.16",
+			wantCount:     1,
+		},
+		{
+			engine:        db.TiDB,
+			statement:     "ALTER TABLE t ALTER INDEX idx_a INVISIBLE;",
+			engineVersion: "5.7.25-TiDB-v6.1.0",
+			wantCount:     0,
+		},
+		{
+			// Different changes in a single ALTER TABLE are always supported by MySQL.
+			engine:        db.MySQL,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ADD INDEX idx_a (a), ALGORITHM=INPLACE;",
+			engineVersion: "5.7.38-log",
+			wantCount:     0,
+		},
+		{
+			engine:        db.MySQL,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ALGORITHM=INSTANT;",
+			engineVersion: "5.7.38-log",
+			wantCount:     1,
+		},
+		{
+			engine:        db.MySQL,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ALGORITHM=INSTANT;",
+			engineVersion: "8.0.28",
+			wantCount:     0,
+		},
+		{
+			engine:        db.MySQL,
+			statement:     "CREATE TABLE t (a INT, INDEX idx_a (a) INVISIBLE); ALTER TABLE t ADD INDEX idx_b (b) INVISIBLE;",
+			engineVersion: "5.7.38-log",
+			wantCount:     2,
+		},
+		{
+			engine:        db.MySQL,
+			statement:     "ALTER TABLE t ADD COLUMN a INT, ALGORITHM=INSTANT;",
+			engineVersion: "",
+			wantCount:     0,
+		},
+	}
+
+	for _, test := range tests {
+		problemList, err := getMySQLIncompatibilityList(test.engine, test.statement, "", "", test.engineVersion)
+		a.NoError(err)
+		a.Len(problemList, test.wantCount, test.statement)
+	}
+}
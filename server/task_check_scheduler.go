@@ -26,6 +26,8 @@ func NewTaskCheckScheduler(server *Server) *TaskCheckScheduler {
 // TaskCheckScheduler is the task check scheduler.
 type TaskCheckScheduler struct {
 	executors map[api.TaskCheckType]TaskCheckExecutor
+	// runningCancels interrupts the executors of the running task checks.
+	runningCancels sync.Map // map[taskCheckRunID]context.CancelFunc
 
 	server *Server
 }
@@ -41,6 +43,10 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 	for {
 		select {
 		case <-ticker.C:
+			if s.server.isInMaintenance() {
+				log.Debug("Task check scheduler paused in maintenance mode")
+				continue
+			}
 			func() {
 				defer func() {
 					if r := recover(); r != nil {
@@ -83,13 +89,26 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					runningTaskChecks[taskCheckRun.ID] = true
 					mu.Unlock()
 
+					runCtx, cancel := context.WithCancel(ctx)
+					s.runningCancels.Store(taskCheckRun.ID, cancel)
 					go func(taskCheckRun *api.TaskCheckRun) {
 						defer func() {
+							s.runningCancels.Delete(taskCheckRun.ID)
+							cancel()
 							mu.Lock()
 							delete(runningTaskChecks, taskCheckRun.ID)
 							mu.Unlock()
 						}()
-						checkResultList, err := executor.Run(ctx, s.server, taskCheckRun)
+						checkResultList, err := executor.Run(runCtx, s.server, taskCheckRun)
+						// The task check run has been marked as CANCELED by the canceler.
+						if runCtx.Err() != nil {
+							log.Debug("Task check run canceled",
+								zap.Int("id", taskCheckRun.ID),
+								zap.Int("task_id", taskCheckRun.TaskID),
+								zap.String("type", string(taskCheckRun.Type)),
+							)
+							return
+						}
 
 						if err == nil {
 							bytes, err := json.Marshal(api.TaskCheckRunResultPayload{
@@ -167,6 +186,47 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// CancelTaskCheckRun marks the running task check run as CANCELED and interrupts its executor.
+func (s *TaskCheckScheduler) CancelTaskCheckRun(ctx context.Context, taskCheckRunID int, updaterID int) (*api.TaskCheckRun, error) {
+	taskCheckRun, err := s.server.store.CancelTaskCheckRun(ctx, taskCheckRunID, updaterID)
+	if err != nil {
+		return nil, err
+	}
+	if cancel, ok := s.runningCancels.Load(taskCheckRunID); ok {
+		cancel.(context.CancelFunc)()
+	}
+	return taskCheckRun, nil
+}
+
+// CancelTaskCheckRunList marks all the running task check runs of the task as CANCELED in batch and interrupts their executors.
+func (s *TaskCheckScheduler) CancelTaskCheckRunList(ctx context.Context, taskID int, updaterID int) ([]*api.TaskCheckRun, error) {
+	taskCheckRunList, err := s.server.store.BatchPatchTaskCheckRunStatus(ctx, &api.TaskCheckRunStatusPatch{
+		UpdaterID: updaterID,
+		TaskID:    &taskID,
+		Status:    api.TaskCheckRunCanceled,
+		Code:      common.Ok,
+		Result:    `{"detail":"Canceled"}`,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, taskCheckRun := range taskCheckRunList {
+		if cancel, ok := s.runningCancels.Load(taskCheckRun.ID); ok {
+			cancel.(context.CancelFunc)()
+		}
+	}
+	return taskCheckRunList, nil
+}
+
+func hasRunningTaskCheckRun(task *api.Task) bool {
+	for _, taskCheckRun := range task.TaskCheckRunList {
+		if taskCheckRun.Status == api.TaskCheckRunRunning {
+			return true
+		}
+	}
+	return false
+}
+
 // Register will register the task check executor.
 func (s *TaskCheckScheduler) Register(taskType api.TaskCheckType, executor TaskCheckExecutor) {
 	if executor == nil {
@@ -249,6 +309,12 @@ func (s *TaskCheckScheduler) ScheduleCheckIfNeeded(ctx context.Context, task *ap
 	if database == nil {
 		return nil, errors.Errorf("database ID not found %v", task.DatabaseID)
 	}
+	// Check the statement with the variables resolved, which is the one to be executed.
+	if task.Type == api.TaskDatabaseSchemaUpdate || task.Type == api.TaskDatabaseDataUpdate {
+		if statement, err = api.ResolveStatementVariables(statement, database); err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve statement variables for task: %v", task.Name)
+		}
+	}
 
 	if err := s.scheduleSyntaxCheckTaskCheck(ctx, task, creatorID, skipIfAlreadyTerminated, database, statement); err != nil {
 		return nil, errors.Wrap(err, "failed to schedule syntax check task check")
@@ -262,6 +328,14 @@ func (s *TaskCheckScheduler) ScheduleCheckIfNeeded(ctx context.Context, task *ap
 		return nil, errors.Wrap(err, "failed to schedule statement type task check")
 	}
 
+	if err := s.scheduleDryRunTaskCheck(ctx, task, creatorID, skipIfAlreadyTerminated, database, statement); err != nil {
+		return nil, errors.Wrap(err, "failed to schedule statement dry run task check")
+	}
+
+	if err := s.scheduleCustomTaskCheck(ctx, task, creatorID, skipIfAlreadyTerminated, database, statement); err != nil {
+		return nil, errors.Wrap(err, "failed to schedule custom task check")
+	}
+
 	taskCheckRunFind := &api.TaskCheckRunFind{
 		TaskID: &task.ID,
 	}
@@ -325,6 +399,37 @@ func (s *TaskCheckScheduler) scheduleStmtTypeTaskCheck(ctx context.Context, task
 	}
 	return nil
 }
+
+func (s *TaskCheckScheduler) scheduleDryRunTaskCheck(ctx context.Context, task *api.Task, creatorID int, skipIfAlreadyTerminated bool, database *api.Database, statement string) error {
+	if task.Type != api.TaskDatabaseSchemaUpdate {
+		return nil
+	}
+	switch database.Instance.Engine {
+	case db.Postgres:
+	case db.MySQL, db.TiDB:
+	default:
+		return nil
+	}
+	payload, err := json.Marshal(api.TaskCheckDatabaseStatementDryRunPayload{
+		Statement: statement,
+		DbType:    database.Instance.Engine,
+		Charset:   database.CharacterSet,
+		Collation: database.Collation,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal statement dry run payload: %v", task.Name)
+	}
+	if _, err := s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+		CreatorID:               creatorID,
+		TaskID:                  task.ID,
+		Type:                    api.TaskCheckDatabaseStatementDryRun,
+		Payload:                 string(payload),
+		SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
 func (s *TaskCheckScheduler) scheduleSQLReviewTaskCheck(ctx context.Context, task *api.Task, creatorID int, skipIfAlreadyTerminated bool, database *api.Database, statement string) error {
 	if !s.server.feature(api.FeatureSQLReviewPolicy) && api.IsSQLReviewSupported(database.Instance.Engine, s.server.profile.Mode) {
 		return nil
@@ -379,6 +484,45 @@ func (s *TaskCheckScheduler) scheduleSyntaxCheckTaskCheck(ctx context.Context, t
 	return nil
 }
 
+// scheduleCustomTaskCheck schedules the custom task check if any custom task check plugin is configured.
+func (s *TaskCheckScheduler) scheduleCustomTaskCheck(ctx context.Context, task *api.Task, creatorID int, skipIfAlreadyTerminated bool, database *api.Database, statement string) error {
+	setting, err := s.server.getTaskCheckCustomSetting(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get custom task check setting")
+	}
+	if len(setting.PluginList) == 0 {
+		return nil
+	}
+	environmentName := ""
+	if database.Instance.Environment != nil {
+		environmentName = database.Instance.Environment.Name
+	}
+	payload, err := json.Marshal(api.TaskCheckCustomPayload{
+		TaskID:          task.ID,
+		TaskName:        task.Name,
+		TaskType:        task.Type,
+		PipelineID:      task.PipelineID,
+		EnvironmentName: environmentName,
+		InstanceName:    database.Instance.Name,
+		DatabaseName:    database.Name,
+		DbType:          database.Instance.Engine,
+		Statement:       statement,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal custom task check payload: %v", task.Name)
+	}
+	if _, err := s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+		CreatorID:               creatorID,
+		TaskID:                  task.ID,
+		Type:                    api.TaskCheckCustom,
+		Payload:                 string(payload),
+		SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *TaskCheckScheduler) scheduleGeneralTaskCheck(ctx context.Context, task *api.Task, creatorID int, skipIfAlreadyTerminated bool) error {
 	if _, err := s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
 		CreatorID:               creatorID,
@@ -476,6 +620,10 @@ func (s *Server) passCheck(ctx context.Context, task *api.Task, checkType api.Ta
 		return false, err
 	}
 	for _, result := range checkResult.ResultList {
+		// The suppressed result has been signed off by the approver.
+		if result.Suppression != nil {
+			continue
+		}
 		if result.Status.LessThan(allowedStatus) {
 			log.Debug("Task is waiting for check to pass",
 				zap.Int("task_id", task.ID),
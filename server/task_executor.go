@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/mysql"
 	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
 	"github.com/pkg/errors"
 )
@@ -52,6 +55,45 @@ func RunTaskExecutorOnce(ctx context.Context, exec TaskExecutor, server *Server,
 	return exec.RunOnce(ctx, server, task)
 }
 
+// TaskExecutorFactory creates a task executor for a single task run.
+type TaskExecutorFactory func() TaskExecutor
+
+var (
+	taskExecutorFactoriesMu sync.RWMutex
+	taskExecutorFactories   = make(map[api.TaskType]TaskExecutorFactory)
+)
+
+// RegisterTaskExecutor makes a task executor available for the provided task type, so that the builds
+// embedding the server can run custom task types without touching the scheduler.
+// It's intended to be called from the init functions, the executors registered after NewServer are not picked up.
+// The task type must start with "bb.task." as required by the task table, and must not be a built-in task type.
+// If RegisterTaskExecutor is called twice with the same task type or if factory is nil, it panics.
+func RegisterTaskExecutor(taskType api.TaskType, factory TaskExecutorFactory) {
+	taskExecutorFactoriesMu.Lock()
+	defer taskExecutorFactoriesMu.Unlock()
+	if factory == nil {
+		panic("server: RegisterTaskExecutor factory is nil for task type: " + taskType)
+	}
+	if !strings.HasPrefix(string(taskType), "bb.task.") {
+		panic("server: RegisterTaskExecutor task type must start with bb.task.: " + taskType)
+	}
+	if _, dup := taskExecutorFactories[taskType]; dup {
+		panic("server: RegisterTaskExecutor called twice for task type: " + taskType)
+	}
+	taskExecutorFactories[taskType] = factory
+}
+
+// registeredTaskExecutors returns a copy of the task executor factories registered by RegisterTaskExecutor.
+func registeredTaskExecutors() map[api.TaskType]TaskExecutorFactory {
+	taskExecutorFactoriesMu.RLock()
+	defer taskExecutorFactoriesMu.RUnlock()
+	factories := make(map[api.TaskType]TaskExecutorFactory, len(taskExecutorFactories))
+	for taskType, factory := range taskExecutorFactories {
+		factories[taskType] = factory
+	}
+	return factories
+}
+
 func preMigration(ctx context.Context, server *Server, task *api.Task, migrationType db.MigrationType, statement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent) (*db.MigrationInfo, error) {
 	if task.Database == nil {
 		msg := "missing database when updating schema"
@@ -116,6 +158,13 @@ func preMigration(ctx context.Context, server *Server, task *api.Task, migration
 	}
 	if issue != nil {
 		mi.IssueID = strconv.Itoa(issue.ID)
+		if mi.Type == db.Data && issue.Payload != "" {
+			maxAffectedRows, err := getRecurringTaskMaxAffectedRows(ctx, server, issue)
+			if err != nil {
+				return nil, err
+			}
+			mi.MaxAffectedRows = maxAffectedRows
+		}
 	}
 
 	statement = strings.TrimSpace(statement)
@@ -132,13 +181,24 @@ func preMigration(ctx context.Context, server *Server, task *api.Task, migration
 	return mi, nil
 }
 
-func executeMigration(ctx context.Context, server *Server, task *api.Task, statement string, mi *db.MigrationInfo) (migrationID int64, schema string, err error) {
+// migrationExecution is the details of the migration execution recorded in the task run result.
+type migrationExecution struct {
+	// durationNs is the total execution time of the migration.
+	durationNs int64
+	// statementLogList is the statements executed successfully, if the statements are executed one by one.
+	statementLogList []*db.StatementLog
+	ddlJobIDList     []int64
+	ddlAlgorithmList []*api.TaskRunDDLAlgorithm
+}
+
+// schemaName is the Postgres schema resolving the unqualified objects of the statement, empty for the default search_path.
+func executeMigration(ctx context.Context, server *Server, task *api.Task, statement string, schemaName string, mi *db.MigrationInfo) (migrationID int64, schema string, execution *migrationExecution, err error) {
 	statement = strings.TrimSpace(statement)
 	databaseName := task.Database.Name
 
-	driver, err := server.getAdminDatabaseDriver(ctx, task.Instance, databaseName)
+	driver, err := server.getAdminDatabaseDriverWithSchema(ctx, task.Instance, databaseName, schemaName)
 	if err != nil {
-		return 0, "", err
+		return 0, "", nil, err
 	}
 	defer driver.Close(ctx)
 
@@ -152,17 +212,116 @@ func executeMigration(ctx context.Context, server *Server, task *api.Task, state
 
 	setup, err := driver.NeedsSetupMigration(ctx)
 	if err != nil {
-		return 0, "", errors.Wrapf(err, "failed to check migration setup for instance %q", task.Instance.Name)
+		return 0, "", nil, errors.Wrapf(err, "failed to check migration setup for instance %q", task.Instance.Name)
 	}
 	if setup {
-		return 0, "", common.Errorf(common.MigrationSchemaMissing, "missing migration schema for instance %q", task.Instance.Name)
+		return 0, "", nil, common.Errorf(common.MigrationSchemaMissing, "missing migration schema for instance %q", task.Instance.Name)
+	}
+
+	taskRunLog := server.getTaskRunLogBuffer(task.ID)
+	logStatement := taskRunLog.statementLogger()
+	reportStatement := newTaskRunProgressReporter(server, task.ID).statementLogger(ctx)
+	execution = &migrationExecution{}
+	mi.StatementLogger = func(stmtLog *db.StatementLog) {
+		logStatement(stmtLog)
+		reportStatement(stmtLog)
+		if stmtLog.Error == "" {
+			execution.statementLogList = append(execution.statementLogList, stmtLog)
+		}
 	}
-
+	executedStatementCount := mi.ExecutedStatementCount
+	if isTaskPausable(task) {
+		statementLogger := mi.StatementLogger
+		mi.StatementLogger = func(stmtLog *db.StatementLog) {
+			statementLogger(stmtLog)
+			if stmtLog.Error == "" {
+				executedStatementCount = stmtLog.Index
+			}
+		}
+		mi.ShouldPause = func() bool {
+			return server.TaskScheduler.isTaskPauseRequested(task.ID)
+		}
+	}
+	if executedStatementCount > 0 {
+		taskRunLog.info("Resume executing %s migration version %s on database %q after %d executed statements.", mi.Type, mi.Version, databaseName, executedStatementCount)
+	} else {
+		taskRunLog.info("Start executing %s migration version %s on database %q.", mi.Type, mi.Version, databaseName)
+	}
+	// TiDB runs the DDL as asynchronous jobs, the jobs created by the migration are recorded for looking up their details.
+	// The migrations of the database are serialized by the migration lock, so the later jobs on the database are created by this migration.
+	// It's not fatal if failed.
+	tidbDriver, isTiDB := unwrapDriver(driver).(*mysql.Driver)
+	isTiDB = isTiDB && task.Instance.Engine == db.TiDB
+	var latestDDLJobID int64
+	if isTiDB {
+		if latestDDLJobID, err = tidbDriver.GetLatestTiDBDDLJobID(ctx); err != nil {
+			log.Warn("Failed to get the latest TiDB DDL job",
+				zap.String("instance", task.Instance.Name),
+				zap.Error(err),
+			)
+			isTiDB = false
+		}
+	}
+	// MySQL runs the ALTER TABLE with the online algorithm if possible, the algorithms used are recorded so that the
+	// statements locking the table with COPY are noticed.
+	if mysqlDriver, ok := unwrapDriver(driver).(*mysql.Driver); ok && task.Instance.Engine == db.MySQL {
+		if instantDDL, _ := db.HasCapability(task.Instance.Engine, task.Instance.EngineVersion, db.CapabilityInstantDDL); instantDDL {
+			mysqlDriver.PreferOnlineDDLAlgorithm(func(algorithmLog *mysql.DDLAlgorithmLog) {
+				execution.ddlAlgorithmList = append(execution.ddlAlgorithmList, &api.TaskRunDDLAlgorithm{
+					Table:     algorithmLog.Table,
+					Algorithm: string(algorithmLog.Algorithm),
+				})
+				if algorithmLog.Algorithm == mysql.DDLAlgorithmCopy {
+					taskRunLog.info("Altered table %s with ALGORITHM=COPY, which blocks the concurrent writes until the table is copied, consider gh-ost for the large tables.", algorithmLog.Table)
+				} else {
+					taskRunLog.info("Altered table %s with ALGORITHM=%s.", algorithmLog.Table, algorithmLog.Algorithm)
+				}
+			})
+		}
+	}
+	startedNs := time.Now().UnixNano()
 	migrationID, schema, err = driver.ExecuteMigration(ctx, mi, statement)
+	execution.durationNs = time.Now().UnixNano() - startedNs
 	if err != nil {
-		return 0, "", err
+		if common.ErrorCode(err) == common.MigrationPaused {
+			taskRunLog.info("Paused %s migration version %s on database %q after %d executed statements.", mi.Type, mi.Version, databaseName, executedStatementCount)
+			if patchErr := server.patchTaskExecutedStatementCount(ctx, task, executedStatementCount); patchErr != nil {
+				return 0, "", nil, errors.Wrap(patchErr, "failed to record the executed statements of the paused task")
+			}
+		} else if transactionalDDL, _ := db.HasCapability(task.Instance.Engine, task.Instance.EngineVersion, db.CapabilityTransactionalDDL); !transactionalDDL {
+			// The DDL statements implicitly commit on the engines without transactional DDL, so they're not rolled back on failure.
+			taskRunLog.info("The schema changes executed before the failure are not rolled back by %s, check the database before retrying.", task.Instance.Engine)
+		}
+		return 0, "", nil, err
+	}
+	taskRunLog.info("Executed %s migration version %s on database %q in %v.", mi.Type, mi.Version, databaseName, time.Duration(execution.durationNs))
+	if migrationID > 0 && statement != "" {
+		server.recordMigrationHistoryObjectList(ctx, task, int(migrationID), statement)
+		server.recordMigrationHistoryStatementList(ctx, task, int(migrationID), execution.statementLogList)
+	}
+	if isTiDB {
+		ddlJobIDList, err := tidbDriver.FindTiDBDDLJobIDList(ctx, databaseName, latestDDLJobID)
+		if err != nil {
+			log.Warn("Failed to find the TiDB DDL jobs of the migration",
+				zap.String("instance", task.Instance.Name),
+				zap.String("database", databaseName),
+				zap.Error(err),
+			)
+		} else if len(ddlJobIDList) > 0 {
+			execution.ddlJobIDList = ddlJobIDList
+			taskRunLog.info("Created TiDB DDL jobs %s.", formatDDLJobIDList(ddlJobIDList))
+		}
 	}
-	return migrationID, schema, nil
+	return migrationID, schema, execution, nil
+}
+
+// formatDDLJobIDList formats the TiDB DDL job IDs as a comma separated list.
+func formatDDLJobIDList(ddlJobIDList []int64) string {
+	var idList []string
+	for _, id := range ddlJobIDList {
+		idList = append(idList, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(idList, ", ")
 }
 
 func postMigration(ctx context.Context, server *Server, task *api.Task, vcsPushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, migrationID int64, schema string) (bool, *api.TaskRunResultPayload, error) {
@@ -203,6 +362,21 @@ func postMigration(ctx context.Context, server *Server, task *api.Task, vcsPushE
 		}
 	}
 
+	// Keep the schema after the migration in the schema snapshot history, it's not fatal if failed.
+	if _, err := server.store.CreateSchemaSnapshot(ctx, &api.SchemaSnapshotCreate{
+		CreatorID:        api.SystemBotID,
+		DatabaseID:       task.Database.ID,
+		Type:             api.SchemaSnapshotMigration,
+		MigrationVersion: mi.Version,
+		Schema:           schema,
+	}); err != nil {
+		log.Error("Failed to create schema snapshot after migration",
+			zap.Int("task_id", task.ID),
+			zap.String("database", databaseName),
+			zap.Error(err),
+		)
+	}
+
 	log.Debug("Post migration...",
 		zap.String("instance", task.Instance.Name),
 		zap.String("database", databaseName),
@@ -298,16 +472,41 @@ func postMigration(ctx context.Context, server *Server, task *api.Task, vcsPushE
 	}, nil
 }
 
-func runMigration(ctx context.Context, server *Server, task *api.Task, migrationType db.MigrationType, statement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent) (terminated bool, result *api.TaskRunResultPayload, err error) {
+func runMigration(ctx context.Context, server *Server, task *api.Task, migrationType db.MigrationType, statement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent, executedStatementCount int, schemaName string) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	// The statement variables are resolved at the execution time, so that they reflect the latest database labels.
+	if task.Database != nil {
+		statement, err = api.ResolveStatementVariables(statement, task.Database)
+		if err != nil {
+			return true, nil, err
+		}
+	}
 	mi, err := preMigration(ctx, server, task, migrationType, statement, schemaVersion, vcsPushEvent)
 	if err != nil {
 		return true, nil, err
 	}
-	migrationID, schema, err := executeMigration(ctx, server, task, statement, mi)
+	mi.ExecutedStatementCount = executedStatementCount
+	// The secrets are injected by the driver right before executing, so that the migration history keeps the {{secret.NAME}} references only.
+	secretMap, err := server.store.GetDatabaseSecretMap(ctx, task.Database.ID)
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "failed to find secrets of database %q", task.Database.Name)
+	}
+	if _, err := api.ResolveStatementSecrets(statement, secretMap); err != nil {
+		return true, nil, errors.Wrapf(err, "failed to resolve secrets for database %q", task.Database.Name)
+	}
+	mi.ResolveStatement = func(statement string) (string, error) {
+		return api.ResolveStatementSecrets(statement, secretMap)
+	}
+	migrationID, schema, execution, err := executeMigration(ctx, server, task, statement, schemaName, mi)
 	if err != nil {
 		return true, nil, err
 	}
-	return postMigration(ctx, server, task, vcsPushEvent, mi, migrationID, schema)
+	terminated, result, err = postMigration(ctx, server, task, vcsPushEvent, mi, migrationID, schema)
+	if result != nil {
+		result.ExecutionDurationNs = execution.durationNs
+		result.DDLJobIDList = execution.ddlJobIDList
+		result.DDLAlgorithmList = execution.ddlAlgorithmList
+	}
+	return terminated, result, err
 }
 
 func findIssueByTask(ctx context.Context, server *Server, task *api.Task) (*api.Issue, error) {
@@ -470,3 +669,22 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 	}
 	return schemaFileMeta.LastCommitID, nil
 }
+
+// getRecurringTaskMaxAffectedRows returns the affected rows limit of the recurring task which creates the issue, 0 if unlimited.
+func getRecurringTaskMaxAffectedRows(ctx context.Context, server *Server, issue *api.Issue) (int64, error) {
+	payload := &api.IssuePayload{}
+	if err := json.Unmarshal([]byte(issue.Payload), payload); err != nil {
+		return 0, errors.Wrapf(err, "invalid issue payload %q", issue.Payload)
+	}
+	if payload.RecurringTaskID == 0 {
+		return 0, nil
+	}
+	recurringTask, err := server.store.GetRecurringTaskByID(ctx, payload.RecurringTaskID)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get recurring task %d", payload.RecurringTaskID)
+	}
+	if recurringTask == nil {
+		return 0, nil
+	}
+	return recurringTask.MaxAffectedRows, nil
+}
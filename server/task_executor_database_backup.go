@@ -56,7 +56,7 @@ func (exec *DatabaseBackupTaskExecutor) RunOnce(ctx context.Context, server *Ser
 		zap.String("backup", backup.Name),
 	)
 
-	backupPayload, backupErr := exec.backupDatabase(ctx, server, task.Instance, task.Database.Name, backup)
+	backupPayload, backupSize, backupErr := exec.backupDatabase(ctx, server, task.Instance, task.Database.Name, backup)
 	backupStatus := string(api.BackupStatusDone)
 	comment := ""
 	if backupErr != nil {
@@ -76,9 +76,26 @@ func (exec *DatabaseBackupTaskExecutor) RunOnce(ctx context.Context, server *Ser
 	}
 
 	if backupErr != nil {
+		if backup.Type == api.BackupTypeAutomatic {
+			if err := server.createBackupFailureIssueIfNeeded(ctx, backup.DatabaseID); err != nil {
+				log.Error("Failed to create issue for the failed automatic backups",
+					zap.String("database", task.Database.Name),
+					zap.String("backup", backup.Name),
+					zap.Error(err),
+				)
+			}
+		}
 		return true, nil, backupErr
 	}
 
+	if _, err := server.createTaskRunArtifactReference(ctx, task, api.TaskRunArtifactDumpFile, backup.StorageBackend, backup.Path, backupSize); err != nil {
+		log.Warn("Failed to attach the dump file to the task run",
+			zap.Int("task_id", task.ID),
+			zap.String("backup", backup.Name),
+			zap.Error(err),
+		)
+	}
+
 	return true, &api.TaskRunResultPayload{
 		Detail: fmt.Sprintf("Backup database %q", task.Database.Name),
 	}, nil
@@ -97,45 +114,58 @@ func dumpBackupFile(ctx context.Context, driver db.Driver, databaseName, backupF
 	return payload, nil
 }
 
-// backupDatabase will take a backup of a database.
-func (*DatabaseBackupTaskExecutor) backupDatabase(ctx context.Context, server *Server, instance *api.Instance, databaseName string, backup *api.Backup) (string, error) {
+// backupDatabase will take a backup of a database, and returns the backup payload and the size of the dump file.
+func (*DatabaseBackupTaskExecutor) backupDatabase(ctx context.Context, server *Server, instance *api.Instance, databaseName string, backup *api.Backup) (string, int64, error) {
 	driver, err := server.getAdminDatabaseDriver(ctx, instance, databaseName)
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 	defer driver.Close(ctx)
 
 	backupFilePathLocal := filepath.Join(server.profile.DataDir, backup.Path)
 	payload, err := dumpBackupFile(ctx, driver, databaseName, backupFilePathLocal)
 	if err != nil {
-		return "", errors.Wrapf(err, "failed to dump backup file %q", backupFilePathLocal)
+		return "", 0, errors.Wrapf(err, "failed to dump backup file %q", backupFilePathLocal)
+	}
+	fileInfo, err := os.Stat(backupFilePathLocal)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "failed to stat backup file %q", backupFilePathLocal)
 	}
+	size := fileInfo.Size()
 
-	switch backup.StorageBackend {
-	case api.BackupStorageBackendLocal:
-		return payload, nil
-	case api.BackupStorageBackendS3:
-		log.Debug("Uploading backup to s3 bucket.", zap.String("bucket", server.s3Client.GetBucket()), zap.String("path", backupFilePathLocal))
-		bucketFileToUpload, err := os.Open(backupFilePathLocal)
-		if err != nil {
-			return "", errors.Wrapf(err, "failed to open backup file %q for uploading to s3 bucket", backupFilePathLocal)
-		}
-		defer bucketFileToUpload.Close()
+	if backup.StorageBackend == api.BackupStorageBackendLocal {
+		return payload, size, nil
+	}
+	if err := uploadBackupFileToCloud(ctx, server, backup, backupFilePathLocal); err != nil {
+		return "", 0, err
+	}
+	return payload, size, nil
+}
 
-		if _, err := server.s3Client.UploadObject(ctx, backup.Path, bucketFileToUpload); err != nil {
-			return "", errors.Wrapf(err, "failed to upload backup to AWS S3")
-		}
-		log.Debug("Successfully uploaded backup to s3 bucket.")
+// uploadBackupFileToCloud uploads the local backup file to the cloud storage backend of the backup, and removes the local one.
+func uploadBackupFileToCloud(ctx context.Context, server *Server, backup *api.Backup, backupFilePathLocal string) error {
+	backend, err := server.getStorageBackend(backup.StorageBackend)
+	if err != nil {
+		return err
+	}
+	log.Debug("Uploading backup to cloud storage.", zap.String("storage_backend", string(backup.StorageBackend)), zap.String("path", backupFilePathLocal))
+	fileToUpload, err := os.Open(backupFilePathLocal)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open backup file %q for uploading to %s", backupFilePathLocal, backup.StorageBackend)
+	}
+	defer fileToUpload.Close()
 
-		if err := os.Remove(backupFilePathLocal); err != nil {
-			log.Warn("Failed to remove the local backup file after uploading to s3 bucket.", zap.String("path", backupFilePathLocal), zap.Error(err))
-		} else {
-			log.Debug("Successfully removed the local backup file after uploading to s3 bucket.", zap.String("path", backupFilePathLocal))
-		}
-		return payload, nil
-	default:
-		return "", errors.Errorf("backup to %s not implemented yet", backup.StorageBackend)
+	if err := backend.Upload(ctx, backup.Path, fileToUpload); err != nil {
+		return errors.Wrapf(err, "failed to upload backup to %s", backup.StorageBackend)
 	}
+	log.Debug("Successfully uploaded backup to cloud storage.", zap.String("storage_backend", string(backup.StorageBackend)))
+
+	if err := os.Remove(backupFilePathLocal); err != nil {
+		log.Warn("Failed to remove the local backup file after uploading to cloud storage.", zap.String("path", backupFilePathLocal), zap.Error(err))
+	} else {
+		log.Debug("Successfully removed the local backup file after uploading to cloud storage.", zap.String("path", backupFilePathLocal))
+	}
+	return nil
 }
 
 // Get backup dir relative to the data dir.
@@ -148,11 +178,6 @@ func getBackupRelativeFilePath(databaseID int, name string) string {
 	return filepath.Join(dir, fmt.Sprintf("%s.sql", name))
 }
 
-func getBackupAbsFilePath(dataDir string, databaseID int, name string) string {
-	path := getBackupRelativeFilePath(databaseID, name)
-	return filepath.Join(dataDir, path)
-}
-
 // Create backup directory for database.
 func createBackupDirectory(dataDir string, databaseID int) error {
 	dir := getBackupRelativeDir(databaseID)
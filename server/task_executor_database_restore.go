@@ -130,20 +130,11 @@ func (*DatabaseRestoreTaskExecutor) restoreDatabase(ctx context.Context, server
 	}
 	defer driver.Close(ctx)
 
-	backupAbsPathLocal := filepath.Join(server.profile.DataDir, backup.Path)
-
-	if backup.StorageBackend == api.BackupStorageBackendS3 {
-		if err := downloadBackupFileFromCloud(ctx, server, backup.Path, backupAbsPathLocal); err != nil {
-			return errors.Wrapf(err, "failed to download backup %q from S3", backup.Path)
-		}
-		defer os.Remove(backupAbsPathLocal)
-	}
-
-	backupFileLocal, err := os.Open(backupAbsPathLocal)
+	backupFileLocal, cleanup, err := openBackupFile(ctx, server, backup)
 	if err != nil {
-		return errors.Wrapf(err, "failed to open backup file at %s", backupAbsPathLocal)
+		return err
 	}
-	defer backupFileLocal.Close()
+	defer cleanup()
 
 	if err := driver.Restore(ctx, backupFileLocal); err != nil {
 		return errors.Wrap(err, "failed to restore backup")
@@ -152,17 +143,52 @@ func (*DatabaseRestoreTaskExecutor) restoreDatabase(ctx context.Context, server
 	return nil
 }
 
-func downloadBackupFileFromCloud(ctx context.Context, server *Server, backupPath, backupAbsPathLocal string) error {
-	log.Debug("Downloading backup file from s3 bucket.", zap.String("path", backupPath))
+// openBackupFile opens the local backup file, which is downloaded from the cloud storage backend of the backup first if needed.
+// The cleanup closes the file and removes the downloaded one.
+func openBackupFile(ctx context.Context, server *Server, backup *api.Backup) (*os.File, func(), error) {
+	backupAbsPathLocal := filepath.Join(server.profile.DataDir, backup.Path)
+	downloaded := false
+	if backup.StorageBackend != api.BackupStorageBackendLocal {
+		if err := os.MkdirAll(filepath.Dir(backupAbsPathLocal), os.ModePerm); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to create directory for backup file %q", backupAbsPathLocal)
+		}
+		if err := downloadBackupFileFromCloud(ctx, server, backup, backupAbsPathLocal); err != nil {
+			os.Remove(backupAbsPathLocal)
+			return nil, nil, errors.Wrapf(err, "failed to download backup %q from %s", backup.Path, backup.StorageBackend)
+		}
+		downloaded = true
+	}
+
+	backupFileLocal, err := os.Open(backupAbsPathLocal)
+	if err != nil {
+		if downloaded {
+			os.Remove(backupAbsPathLocal)
+		}
+		return nil, nil, errors.Wrapf(err, "failed to open backup file at %s", backupAbsPathLocal)
+	}
+	return backupFileLocal, func() {
+		backupFileLocal.Close()
+		if downloaded {
+			os.Remove(backupAbsPathLocal)
+		}
+	}, nil
+}
+
+func downloadBackupFileFromCloud(ctx context.Context, server *Server, backup *api.Backup, backupAbsPathLocal string) error {
+	backend, err := server.getStorageBackend(backup.StorageBackend)
+	if err != nil {
+		return err
+	}
+	log.Debug("Downloading backup file from cloud storage.", zap.String("storage_backend", string(backup.StorageBackend)), zap.String("path", backup.Path))
 	backupFileDownload, err := os.Create(backupAbsPathLocal)
 	if err != nil {
-		return errors.Wrapf(err, "failed to create local backup file %q for downloading from s3 bucket", backupAbsPathLocal)
+		return errors.Wrapf(err, "failed to create local backup file %q for downloading from %s", backupAbsPathLocal, backup.StorageBackend)
 	}
 	defer backupFileDownload.Close()
-	if _, err := server.s3Client.DownloadObject(ctx, backupPath, backupFileDownload); err != nil {
-		return errors.Wrapf(err, "failed to download backup file %q from s3 bucket", backupPath)
+	if err := backend.Download(ctx, backup.Path, backupFileDownload); err != nil {
+		return errors.Wrapf(err, "failed to download backup file %q from %s", backup.Path, backup.StorageBackend)
 	}
-	log.Debug("Successfully downloaded backup file from s3 bucket.")
+	log.Debug("Successfully downloaded backup file from cloud storage.")
 	return nil
 }
 
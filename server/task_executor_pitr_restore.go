@@ -206,8 +206,8 @@ func (exec *PITRRestoreTaskExecutor) doPITRRestore(ctx context.Context, server *
 	}
 	log.Debug("Found backup list", zap.Array("backups", api.ZapBackupArray(backupList)))
 
-	mysqlSourceDriver, sourceOk := sourceDriver.(*mysql.Driver)
-	mysqlTargetDriver, targetOk := targetDriver.(*mysql.Driver)
+	mysqlSourceDriver, sourceOk := unwrapDriver(sourceDriver).(*mysql.Driver)
+	mysqlTargetDriver, targetOk := unwrapDriver(targetDriver).(*mysql.Driver)
 	if (!sourceOk) || (!targetOk) {
 		log.Error("Failed to cast driver to mysql.Driver")
 		return nil, errors.Errorf("[internal] cast driver to mysql.Driver failed")
@@ -230,13 +230,12 @@ func (exec *PITRRestoreTaskExecutor) doPITRRestore(ctx context.Context, server *
 		return nil, errors.Wrapf(err, "failed to get latest backup before or equal to %s", targetTsHuman)
 	}
 	log.Debug("Got latest backup before or equal to targetTs", zap.String("backup", backup.Name))
-	backupFileName := getBackupAbsFilePath(server.profile.DataDir, backup.DatabaseID, backup.Name)
-	backupFile, err := os.Open(backupFileName)
+	backupFile, cleanup, err := openBackupFile(ctx, server, backup)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to open backup file %q", backupFileName)
+		return nil, err
 	}
-	defer backupFile.Close()
-	log.Debug("Successfully opened backup file", zap.String("filename", backupFileName))
+	defer cleanup()
+	log.Debug("Successfully opened backup file", zap.String("filename", backupFile.Name()))
 
 	log.Debug("Start creating and restoring PITR database",
 		zap.String("instance", task.Instance.Name),
@@ -306,12 +305,11 @@ func (*PITRRestoreTaskExecutor) doRestoreInPlacePostgres(ctx context.Context, se
 	if backup == nil {
 		return nil, errors.Errorf("backup with ID %d not found", *payload.BackupID)
 	}
-	backupFileName := getBackupAbsFilePath(server.profile.DataDir, backup.DatabaseID, backup.Name)
-	backupFile, err := os.Open(backupFileName)
+	backupFile, cleanup, err := openBackupFile(ctx, server, backup)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to open backup file %q", backupFileName)
+		return nil, err
 	}
-	defer backupFile.Close()
+	defer cleanup()
 
 	driver, err := server.getAdminDatabaseDriver(ctx, task.Instance, task.Database.Name)
 	if err != nil {
@@ -319,7 +317,7 @@ func (*PITRRestoreTaskExecutor) doRestoreInPlacePostgres(ctx context.Context, se
 	}
 	defer driver.Close(ctx)
 
-	pgDriver, ok := driver.(*pg.Driver)
+	pgDriver, ok := unwrapDriver(driver).(*pg.Driver)
 	if !ok {
 		log.Error("Failed to cast driver to pg.Driver")
 		return nil, errors.Errorf("[internal] cast driver to pg.Driver failed")
@@ -423,16 +421,25 @@ func (*PITRRestoreTaskExecutor) restoreDatabase(ctx context.Context, server *Ser
 	}
 	defer driver.Close(ctx)
 
-	backupPath := backup.Path
-	if !filepath.IsAbs(backupPath) {
-		backupPath = filepath.Join(dataDir, backupPath)
-	}
-
-	f, err := os.Open(backupPath)
-	if err != nil {
-		return errors.Wrapf(err, "failed to open backup file at %s", backupPath)
+	var f *os.File
+	if backup.StorageBackend == api.BackupStorageBackendLocal {
+		backupPath := backup.Path
+		if !filepath.IsAbs(backupPath) {
+			backupPath = filepath.Join(dataDir, backupPath)
+		}
+		f, err = os.Open(backupPath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open backup file at %s", backupPath)
+		}
+		defer f.Close()
+	} else {
+		var cleanup func()
+		f, cleanup, err = openBackupFile(ctx, server, backup)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
 	}
-	defer f.Close()
 
 	if err := driver.Restore(ctx, f); err != nil {
 		return errors.Wrap(err, "failed to restore backup")
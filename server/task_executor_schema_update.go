@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"sync/atomic"
+	"time"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/pkg/errors"
 )
 
@@ -27,7 +29,20 @@ func (exec *SchemaUpdateTaskExecutor) RunOnce(ctx context.Context, server *Serve
 		return true, nil, errors.Wrap(err, "invalid database schema update payload")
 	}
 
-	return runMigration(ctx, server, task, payload.MigrationType, payload.Statement, payload.SchemaVersion, payload.VCSPushEvent)
+	runCtx := ctx
+	if payload.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, time.Duration(payload.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+	terminated, result, err = runMigration(runCtx, server, task, payload.MigrationType, payload.Statement, payload.SchemaVersion, payload.VCSPushEvent, payload.ExecutedStatementCount, payload.SchemaName)
+	if err != nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return true, nil, errors.Wrapf(err, "timed out after %d seconds", payload.TimeoutSeconds)
+	}
+	if err == nil && payload.MigrationType == db.Migrate {
+		server.recordTaskRollbackStatement(ctx, task, payload)
+	}
+	return terminated, result, err
 }
 
 // IsCompleted tells the scheduler if the task execution has completed.
@@ -89,7 +89,7 @@ func cutover(ctx context.Context, server *Server, task *api.Task, statement, sch
 			return -1, "", common.Errorf(common.MigrationSchemaMissing, "missing migration schema for instance %q", task.Instance.Name)
 		}
 
-		executor := driver.(util.MigrationExecutor)
+		executor := unwrapDriver(driver).(util.MigrationExecutor)
 
 		var prevSchemaBuf bytes.Buffer
 		if _, err := driver.Dump(ctx, mi.Database, &prevSchemaBuf, true); err != nil {
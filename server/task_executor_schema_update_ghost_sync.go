@@ -221,6 +221,7 @@ func (exec *SchemaUpdateGhostSyncTaskExecutor) runGhostMigration(_ context.Conte
 		ticker := time.NewTicker(1 * time.Second)
 		defer ticker.Stop()
 		createdTs := time.Now().Unix()
+		progressReporter := newTaskRunProgressReporter(server, task.ID)
 		for {
 			select {
 			case <-ticker.C:
@@ -235,8 +236,18 @@ func (exec *SchemaUpdateGhostSyncTaskExecutor) runGhostMigration(_ context.Conte
 					CreatedTs:     createdTs,
 					UpdatedTs:     updatedTs,
 				})
+				var eta int64
+				if etaSeconds := migrationContext.GetETASeconds(); etaSeconds > 0 {
+					eta = etaSeconds
+				}
 				// Since we are using postpone flag file to postpone cutover, it's gh-ost mechanism to set migrationContext.IsPostponingCutOver to 1 after synced and before postpone flag file is removed. We utilize this mechanism here to check if synced.
-				if atomic.LoadInt64(&migrationContext.IsPostponingCutOver) > 0 {
+				synced := atomic.LoadInt64(&migrationContext.IsPostponingCutOver) > 0
+				progressReporter.report(ctx, &api.TaskRunProgressPatch{
+					RowsCopied: &completedUnit,
+					RowsTotal:  &totalUnit,
+					EtaSeconds: &eta,
+				}, synced)
+				if synced {
 					close(syncDone)
 					return
 				}
@@ -260,9 +271,37 @@ func (exec *SchemaUpdateGhostSyncTaskExecutor) runGhostMigration(_ context.Conte
 
 	select {
 	case <-syncDone:
+		attachGhostLog(ctx, server, task, migrationContext, nil)
 		server.TaskScheduler.sharedTaskState.Store(task.ID, sharedGhostState{migrationContext: migrationContext, errCh: migrationError})
-		return true, &api.TaskRunResultPayload{Detail: "sync done"}, nil
+		progress := exec.GetProgress()
+		return true, &api.TaskRunResultPayload{Detail: "sync done", Progress: &progress}, nil
 	case err := <-migrationError:
+		attachGhostLog(ctx, server, task, migrationContext, err)
 		return true, nil, err
 	}
 }
+
+// attachGhostLog attaches the summary of the gh-ost sync to the task run as the gh-ost log artifact.
+// It's best-effort, failing to attach the log doesn't fail the task.
+func attachGhostLog(ctx context.Context, server *Server, task *api.Task, migrationContext *base.MigrationContext, migrationErr error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Database: %s\n", migrationContext.DatabaseName)
+	fmt.Fprintf(&b, "Table: %s\n", migrationContext.OriginalTableName)
+	fmt.Fprintf(&b, "Ghost table: %s\n", migrationContext.GetGhostTableName())
+	fmt.Fprintf(&b, "Alter statement: %s\n", migrationContext.AlterStatement)
+	fmt.Fprintf(&b, "Rows estimate: %d\n", atomic.LoadInt64(&migrationContext.RowsEstimate)+atomic.LoadInt64(&migrationContext.RowsDeltaEstimate))
+	fmt.Fprintf(&b, "Rows copied: %d\n", migrationContext.GetTotalRowsCopied())
+	fmt.Fprintf(&b, "Elapsed: %s\n", migrationContext.ElapsedTime().Round(time.Second))
+	if migrationErr != nil {
+		fmt.Fprintf(&b, "Status: failed: %s\n", migrationErr.Error())
+	} else {
+		b.WriteString("Status: synced, waiting for cutover\n")
+	}
+
+	if _, err := server.createTaskRunArtifact(ctx, task, api.TaskRunArtifactGhostLog, "gh-ost-sync.log", []byte(b.String())); err != nil {
+		log.Warn("Failed to attach the gh-ost log to the task run",
+			zap.Int("task_id", task.ID),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestRegisterTaskExecutor(t *testing.T) {
+	const taskType = api.TaskType("bb.task.test.register-task-executor")
+	RegisterTaskExecutor(taskType, NewDefaultTaskExecutor)
+
+	factories := registeredTaskExecutors()
+	factory, ok := factories[taskType]
+	assert.True(t, ok)
+	assert.IsType(t, &DefaultTaskExecutor{}, factory())
+
+	// The returned map is a copy.
+	delete(factories, taskType)
+	_, ok = registeredTaskExecutors()[taskType]
+	assert.True(t, ok)
+
+	assert.Panics(t, func() { RegisterTaskExecutor(taskType, NewDefaultTaskExecutor) })
+	assert.Panics(t, func() { RegisterTaskExecutor("bb.task.test.nil", nil) })
+	assert.Panics(t, func() { RegisterTaskExecutor("test.register-task-executor", NewDefaultTaskExecutor) })
+}
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+)
+
+const (
+	// taskRunHeartbeatInterval is the interval between the heartbeats of the running task run.
+	taskRunHeartbeatInterval = time.Duration(10) * time.Second
+	// defaultTaskHeartbeatTimeoutSeconds is the default number of seconds without heartbeat after which the running task is orphaned.
+	defaultTaskHeartbeatTimeoutSeconds = 120
+	// minTaskHeartbeatTimeoutSeconds is the minimum heartbeat timeout, which tolerates a few missed heartbeats.
+	minTaskHeartbeatTimeoutSeconds = 3 * 10
+)
+
+// orphanedTaskAction is the action taken on the orphaned task, whose executor stopped heartbeating.
+type orphanedTaskAction int
+
+const (
+	// orphanedTaskRequeue re-runs the task, because the migration hasn't started.
+	orphanedTaskRequeue orphanedTaskAction = iota
+	// orphanedTaskDone marks the task as DONE, because the migration has completed.
+	orphanedTaskDone
+	// orphanedTaskFail marks the task as FAILED, because it's unknown whether the task has been partially applied.
+	orphanedTaskFail
+)
+
+// startTaskRunHeartbeat reports the running task run of the task alive every taskRunHeartbeatInterval until the returned
+// stop function is called. The stop function clears the heartbeat, so that the task run isn't taken as orphaned
+// when the scheduler retries the task on the transient error.
+func (s *TaskScheduler) startTaskRunHeartbeat(ctx context.Context, taskID int) func() {
+	beat := func(ctx context.Context, heartbeatTs int64) {
+		if err := s.server.store.PatchTaskRunHeartbeat(ctx, &api.TaskRunHeartbeatPatch{
+			TaskID:      taskID,
+			HeartbeatTs: heartbeatTs,
+		}); err != nil && common.ErrorCode(err) != common.NotFound {
+			log.Warn("Failed to patch task run heartbeat",
+				zap.Int("task_id", taskID),
+				zap.Error(err),
+			)
+		}
+	}
+	beat(ctx, time.Now().Unix())
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(taskRunHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				beat(ctx, time.Now().Unix())
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+		beat(ctx, 0)
+	}
+}
+
+// getTaskHeartbeatTimeout returns the heartbeat timeout of the running tasks, 0 means the orphaned tasks are re-run right away.
+func (s *TaskScheduler) getTaskHeartbeatTimeout(ctx context.Context) (time.Duration, error) {
+	settingName := api.SettingTaskHeartbeatTimeoutSeconds
+	settingList, err := s.server.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return 0, err
+	}
+	if len(settingList) == 0 {
+		return time.Duration(defaultTaskHeartbeatTimeoutSeconds) * time.Second, nil
+	}
+	seconds, err := strconv.Atoi(settingList[0].Value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid setting %s value %q", settingName, settingList[0].Value)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// validateTaskHeartbeatTimeout validates the value of the task heartbeat timeout setting.
+func validateTaskHeartbeatTimeout(value string) error {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return errors.Errorf("must be a non-negative integer, got %q", value)
+	}
+	if seconds > 0 && seconds < minTaskHeartbeatTimeoutSeconds {
+		return errors.Errorf("must be 0 or at least %d seconds, got %d", minTaskHeartbeatTimeoutSeconds, seconds)
+	}
+	return nil
+}
+
+// recoverOrphanedTask checks the RUNNING task without an executor in this server, and returns true if the task
+// shouldn't be run now. The task whose running task run has a fresh heartbeat may still be run by another executor,
+// e.g. the one of the previous server not stopped yet. The task whose heartbeat is older than timeout is orphaned,
+// and it's re-run, marked as DONE or FAILED by its migration history.
+func (s *TaskScheduler) recoverOrphanedTask(ctx context.Context, task *api.Task, timeout time.Duration, now time.Time) (bool, error) {
+	if timeout == 0 {
+		return false, nil
+	}
+	taskRun, err := s.server.store.GetTaskRun(ctx, &api.TaskRunFind{
+		TaskID:     &task.ID,
+		StatusList: &[]api.TaskRunStatus{api.TaskRunRunning},
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get running task run of task %d", task.ID)
+	}
+	// The task run not started by any executor yet, e.g. queued by the concurrency limits.
+	if taskRun == nil || taskRun.HeartbeatTs == 0 {
+		return false, nil
+	}
+	if now.Sub(time.Unix(taskRun.HeartbeatTs, 0)) < timeout {
+		return true, nil
+	}
+
+	historyList, err := s.findTaskMigrationHistory(ctx, task)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to find migration history of orphaned task %d", task.ID)
+	}
+	action := getOrphanedTaskAction(task.Type, historyList)
+	log.Warn("Found orphaned task whose executor stopped heartbeating",
+		zap.Int("task_id", task.ID),
+		zap.String("name", task.Name),
+		zap.Int64("heartbeat_ts", taskRun.HeartbeatTs),
+		zap.Int("action", int(action)),
+	)
+
+	switch action {
+	case orphanedTaskRequeue:
+		if err := s.server.store.PatchTaskRunHeartbeat(ctx, &api.TaskRunHeartbeatPatch{TaskID: task.ID}); err != nil {
+			return false, errors.Wrapf(err, "failed to clear heartbeat of orphaned task %d", task.ID)
+		}
+		return false, nil
+	case orphanedTaskDone:
+		history := historyList[0]
+		bytes, err := json.Marshal(api.TaskRunResultPayload{
+			Detail:      fmt.Sprintf("The executor stopped heartbeating after the migration %q completed.", history.Version),
+			MigrationID: int64(history.ID),
+			Version:     history.Version,
+		})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to marshal task run result of orphaned task %d", task.ID)
+		}
+		code := common.Ok
+		result := string(bytes)
+		if _, err := s.server.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
+			ID:        task.ID,
+			UpdaterID: api.SystemBotID,
+			Status:    api.TaskDone,
+			Code:      &code,
+			Result:    &result,
+		}); err != nil {
+			return false, errors.Wrapf(err, "failed to mark orphaned task %d as DONE", task.ID)
+		}
+		return true, nil
+	default:
+		detail := "The executor stopped heartbeating, and it's unknown whether the task has been applied. Check the database before retrying."
+		if len(historyList) > 0 {
+			detail = fmt.Sprintf("The executor stopped heartbeating with the migration %q left %s. Check the database before retrying.", historyList[0].Version, historyList[0].Status)
+		}
+		bytes, err := json.Marshal(api.TaskRunResultPayload{
+			Detail: detail,
+		})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to marshal task run result of orphaned task %d", task.ID)
+		}
+		code := common.TaskOrphaned
+		result := string(bytes)
+		if _, err := s.server.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
+			ID:        task.ID,
+			UpdaterID: api.SystemBotID,
+			Status:    api.TaskFailed,
+			Code:      &code,
+			Result:    &result,
+		}); err != nil {
+			return false, errors.Wrapf(err, "failed to mark orphaned task %d as FAILED", task.ID)
+		}
+		return true, nil
+	}
+}
+
+// findTaskMigrationHistory finds the migration history recorded by the migration task, nil for the other tasks.
+func (s *TaskScheduler) findTaskMigrationHistory(ctx context.Context, task *api.Task) ([]*db.MigrationHistory, error) {
+	if !isMigrationTask(task.Type) || task.Type == api.TaskDatabaseSchemaUpdateGhostCutover || task.Database == nil {
+		return nil, nil
+	}
+	version, err := s.getTaskMigrationVersion(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := s.server.getAdminDatabaseDriver(ctx, task.Instance, "" /* databaseName */)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	return driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{
+		Database: &task.Database.Name,
+		Version:  &version,
+	})
+}
+
+// getTaskMigrationVersion returns the version of the migration applied by the schema or data update task.
+func (s *TaskScheduler) getTaskMigrationVersion(ctx context.Context, task *api.Task) (string, error) {
+	var schemaVersion string
+	var pushEvent *vcsPlugin.PushEvent
+	switch task.Type {
+	case api.TaskDatabaseSchemaUpdate:
+		payload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return "", errors.Wrap(err, "invalid database schema update payload")
+		}
+		schemaVersion, pushEvent = payload.SchemaVersion, payload.VCSPushEvent
+	case api.TaskDatabaseDataUpdate:
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return "", errors.Wrap(err, "invalid database data update payload")
+		}
+		schemaVersion, pushEvent = payload.SchemaVersion, payload.VCSPushEvent
+	default:
+		return "", errors.Errorf("task type %s doesn't apply migration", task.Type)
+	}
+	if pushEvent == nil {
+		return schemaVersion, nil
+	}
+	// The version of the migration from VCS is parsed from the file path, the same as preMigration.
+	repo, err := findRepositoryByTask(ctx, s.server, task)
+	if err != nil {
+		return "", err
+	}
+	mi, err := db.ParseMigrationInfo(pushEvent.FileCommit.Added, filepath.Join(pushEvent.BaseDirectory, repo.FilePathTemplate))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse migration info")
+	}
+	return mi.Version, nil
+}
+
+// getOrphanedTaskAction decides the action on the orphaned task by the migration history it has recorded.
+// The migration task without any history hasn't started the migration, so it's safe to re-run.
+func getOrphanedTaskAction(taskType api.TaskType, historyList []*db.MigrationHistory) orphanedTaskAction {
+	if !isMigrationTask(taskType) || taskType == api.TaskDatabaseSchemaUpdateGhostCutover {
+		return orphanedTaskFail
+	}
+	if len(historyList) == 0 {
+		return orphanedTaskRequeue
+	}
+	if historyList[0].Status == db.Done {
+		return orphanedTaskDone
+	}
+	return orphanedTaskFail
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetOrphanedTaskAction(t *testing.T) {
+	tests := []struct {
+		taskType    api.TaskType
+		historyList []*db.MigrationHistory
+		want        orphanedTaskAction
+	}{
+		{
+			taskType: api.TaskDatabaseSchemaUpdate,
+			want:     orphanedTaskRequeue,
+		},
+		{
+			taskType:    api.TaskDatabaseDataUpdate,
+			historyList: []*db.MigrationHistory{{Status: db.Done}},
+			want:        orphanedTaskDone,
+		},
+		{
+			taskType:    api.TaskDatabaseSchemaUpdate,
+			historyList: []*db.MigrationHistory{{Status: db.Pending}},
+			want:        orphanedTaskFail,
+		},
+		{
+			taskType:    api.TaskDatabaseSchemaUpdate,
+			historyList: []*db.MigrationHistory{{Status: db.Failed}},
+			want:        orphanedTaskFail,
+		},
+		{
+			// gh-ost doesn't record the migration history until the cutover completes.
+			taskType: api.TaskDatabaseSchemaUpdateGhostCutover,
+			want:     orphanedTaskFail,
+		},
+		{
+			taskType: api.TaskDatabaseRestore,
+			want:     orphanedTaskFail,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, getOrphanedTaskAction(test.taskType, test.historyList), test.taskType)
+	}
+}
+
+func TestValidateTaskHeartbeatTimeout(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "0"},
+		{value: "30"},
+		{value: "120"},
+		{value: "29", wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "1m", wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := validateTaskHeartbeatTimeout(test.value)
+		if test.wantErr {
+			assert.Error(t, err, test.value)
+		} else {
+			assert.NoError(t, err, test.value)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// isTaskPausable returns true if the task executes its statements one by one, so that it can be paused at a statement boundary.
+// The data update statements are executed one by one in a transaction, which is committed on pause.
+// The schema update statements are only executed one by one on MySQL and TiDB, because they commit each DDL implicitly anyway.
+func isTaskPausable(task *api.Task) bool {
+	switch task.Type {
+	case api.TaskDatabaseDataUpdate:
+		return true
+	case api.TaskDatabaseSchemaUpdate:
+		return task.Instance != nil && (task.Instance.Engine == db.MySQL || task.Instance.Engine == db.TiDB)
+	}
+	return false
+}
+
+// patchTaskExecutedStatementCount records the statements executed by the paused task, so that they are skipped when the task is resumed.
+// The task gets a new schema version, otherwise the resumed migration conflicts with the migration history of the paused one.
+func (s *Server) patchTaskExecutedStatementCount(ctx context.Context, task *api.Task, executedStatementCount int) error {
+	var payload interface{}
+	switch task.Type {
+	case api.TaskDatabaseDataUpdate:
+		p := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), p); err != nil {
+			return errors.Wrap(err, "invalid database data update payload")
+		}
+		p.ExecutedStatementCount = executedStatementCount
+		p.SchemaVersion = common.DefaultMigrationVersion()
+		payload = p
+	case api.TaskDatabaseSchemaUpdate:
+		p := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), p); err != nil {
+			return errors.Wrap(err, "invalid database schema update payload")
+		}
+		p.ExecutedStatementCount = executedStatementCount
+		p.SchemaVersion = common.DefaultMigrationVersion()
+		payload = p
+	default:
+		return errors.Errorf("task type %q can not be paused", task.Type)
+	}
+
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal task payload")
+	}
+	payloadStr := string(bytes)
+	if _, err := s.store.PatchTask(ctx, &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Payload:   &payloadStr,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to patch task %d payload", task.ID)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+// recordTaskRollbackStatement generates the statement reverting the executed schema update task, and stores it on the task payload
+// so that a rollback issue can be created from it.
+// The task has been executed, so the failure is logged instead of failing the task.
+func (s *Server) recordTaskRollbackStatement(ctx context.Context, task *api.Task, payload *api.TaskDatabaseSchemaUpdatePayload) {
+	statement := payload.Statement
+	if task.Database != nil {
+		resolved, err := api.ResolveStatementVariables(statement, task.Database)
+		if err != nil {
+			log.Warn("Failed to resolve the statement variables for generating the rollback statement", zap.Int("task_id", task.ID), zap.Error(err))
+			return
+		}
+		statement = resolved
+	}
+	rollbackStatement, err := generateRollbackStatement(task.Instance.Engine, statement)
+	if err != nil {
+		log.Debug("Cannot generate the rollback statement for the task", zap.Int("task_id", task.ID), zap.Error(err))
+		return
+	}
+	if rollbackStatement == "" {
+		return
+	}
+
+	payload.RollbackStatement = rollbackStatement
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		log.Error("Failed to marshal the task payload with the rollback statement", zap.Int("task_id", task.ID), zap.Error(err))
+		return
+	}
+	payloadStr := string(bytes)
+	if _, err := s.store.PatchTask(ctx, &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Payload:   &payloadStr,
+	}); err != nil {
+		log.Error("Failed to store the rollback statement of the task", zap.Int("task_id", task.ID), zap.Error(err))
+	}
+}
+
+// generateRollbackStatement generates the statement reverting the DDL statement.
+// Only the statements that can be reverted without the lost data are supported, i.e. creating and renaming the tables,
+// columns and indexes. Returns error if any statement is not supported, and empty for the engines without parser support.
+// The reverting statements are in the reverse order of the original statements.
+func generateRollbackStatement(engine db.Type, statement string) (string, error) {
+	var rollbackList []string
+	switch engine {
+	case db.MySQL, db.TiDB:
+		p := tidbparser.New()
+		// To support MySQL8 window function syntax.
+		// See https://github.com/bytebase/bytebase/issues/175.
+		p.EnableWindowFunc(true)
+		nodeList, _, err := p.Parse(statement, "", "")
+		if err != nil {
+			return "", err
+		}
+		for _, node := range nodeList {
+			rollback, err := generateMySQLRollbackStatement(node)
+			if err != nil {
+				return "", err
+			}
+			rollbackList = append(rollbackList, rollback)
+		}
+	case db.Postgres:
+		nodeList, err := parser.Parse(parser.Postgres, parser.Context{}, statement)
+		if err != nil {
+			return "", err
+		}
+		for _, node := range nodeList {
+			rollback, err := generatePostgresRollbackStatement(node)
+			if err != nil {
+				return "", err
+			}
+			rollbackList = append(rollbackList, rollback)
+		}
+	default:
+		return "", nil
+	}
+
+	var buf strings.Builder
+	for i := len(rollbackList) - 1; i >= 0; i-- {
+		buf.WriteString(rollbackList[i])
+		buf.WriteString("\n")
+	}
+	return buf.String(), nil
+}
+
+func generateMySQLRollbackStatement(node tidbast.StmtNode) (string, error) {
+	switch node := node.(type) {
+	case *tidbast.CreateTableStmt:
+		// The table may exist before, so it's not safe to drop it.
+		if node.IfNotExists {
+			break
+		}
+		return fmt.Sprintf("DROP TABLE %s;", quoteMySQLTableName(node.Table)), nil
+	case *tidbast.CreateIndexStmt:
+		if node.IfNotExists {
+			break
+		}
+		return fmt.Sprintf("DROP INDEX %s ON %s;", quoteMySQLIdentifier(node.IndexName), quoteMySQLTableName(node.Table)), nil
+	case *tidbast.RenameTableStmt:
+		var tableToTableList []string
+		for i := len(node.TableToTables) - 1; i >= 0; i-- {
+			tableToTable := node.TableToTables[i]
+			tableToTableList = append(tableToTableList, fmt.Sprintf("%s TO %s", quoteMySQLTableName(tableToTable.NewTable), quoteMySQLTableName(tableToTable.OldTable)))
+		}
+		return fmt.Sprintf("RENAME TABLE %s;", strings.Join(tableToTableList, ", ")), nil
+	case *tidbast.AlterTableStmt:
+		// The specs are reverted on the table renamed by the statement.
+		table := node.Table
+		var specList []string
+		for i := len(node.Specs) - 1; i >= 0; i-- {
+			spec := node.Specs[i]
+			switch spec.Tp {
+			case tidbast.AlterTableAddColumns:
+				for j := len(spec.NewColumns) - 1; j >= 0; j-- {
+					specList = append(specList, fmt.Sprintf("DROP COLUMN %s", quoteMySQLIdentifier(spec.NewColumns[j].Name.Name.O)))
+				}
+			case tidbast.AlterTableAddConstraint:
+				switch spec.Constraint.Tp {
+				case tidbast.ConstraintIndex, tidbast.ConstraintKey, tidbast.ConstraintUniq, tidbast.ConstraintUniqIndex, tidbast.ConstraintUniqKey:
+					if spec.Constraint.Name == "" {
+						return "", errors.Errorf("cannot generate rollback statement for %q: the index name is not specified", node.Text())
+					}
+					specList = append(specList, fmt.Sprintf("DROP INDEX %s", quoteMySQLIdentifier(spec.Constraint.Name)))
+				default:
+					return "", errors.Errorf("cannot generate rollback statement for %q", node.Text())
+				}
+			case tidbast.AlterTableRenameColumn:
+				specList = append(specList, fmt.Sprintf("RENAME COLUMN %s TO %s", quoteMySQLIdentifier(spec.NewColumnName.Name.O), quoteMySQLIdentifier(spec.OldColumnName.Name.O)))
+			case tidbast.AlterTableRenameIndex:
+				specList = append(specList, fmt.Sprintf("RENAME INDEX %s TO %s", quoteMySQLIdentifier(spec.ToKey.O), quoteMySQLIdentifier(spec.FromKey.O)))
+			case tidbast.AlterTableRenameTable:
+				specList = append(specList, fmt.Sprintf("RENAME TO %s", quoteMySQLTableName(node.Table)))
+				table = spec.NewTable
+			default:
+				return "", errors.Errorf("cannot generate rollback statement for %q", node.Text())
+			}
+		}
+		return fmt.Sprintf("ALTER TABLE %s %s;", quoteMySQLTableName(table), strings.Join(specList, ", ")), nil
+	}
+	return "", errors.Errorf("cannot generate rollback statement for %q", node.Text())
+}
+
+func quoteMySQLIdentifier(name string) string {
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+}
+
+func quoteMySQLTableName(table *tidbast.TableName) string {
+	if table.Schema.O != "" {
+		return fmt.Sprintf("%s.%s", quoteMySQLIdentifier(table.Schema.O), quoteMySQLIdentifier(table.Name.O))
+	}
+	return quoteMySQLIdentifier(table.Name.O)
+}
+
+func generatePostgresRollbackStatement(node ast.Node) (string, error) {
+	switch node := node.(type) {
+	case *ast.CreateTableStmt:
+		// The table may exist before, so it's not safe to drop it.
+		if node.IfNotExists {
+			break
+		}
+		return fmt.Sprintf("DROP TABLE %s;", quotePostgresName(node.Name.Schema, node.Name.Name)), nil
+	case *ast.CreateIndexStmt:
+		if node.Index.Name == "" {
+			return "", errors.Errorf("cannot generate rollback statement for %q: the index name is not specified", node.Text())
+		}
+		return fmt.Sprintf("DROP INDEX %s;", quotePostgresName(node.Index.Table.Schema, node.Index.Name)), nil
+	case *ast.RenameIndexStmt:
+		return fmt.Sprintf("ALTER INDEX %s RENAME TO %s;", quotePostgresName(node.Table.Schema, node.NewName), quotePostgresIdentifier(node.IndexName)), nil
+	case *ast.AlterTableStmt:
+		var rollbackList []string
+		for i := len(node.AlterItemList) - 1; i >= 0; i-- {
+			switch item := node.AlterItemList[i].(type) {
+			case *ast.AddColumnListStmt:
+				for j := len(item.ColumnList) - 1; j >= 0; j-- {
+					rollbackList = append(rollbackList, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", quotePostgresName(node.Table.Schema, node.Table.Name), quotePostgresIdentifier(item.ColumnList[j].ColumnName)))
+				}
+			case *ast.RenameColumnStmt:
+				rollbackList = append(rollbackList, fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", quotePostgresName(node.Table.Schema, node.Table.Name), quotePostgresIdentifier(item.NewName), quotePostgresIdentifier(item.ColumnName)))
+			case *ast.RenameTableStmt:
+				objectType := "TABLE"
+				if item.Table.Type == ast.TableTypeView {
+					objectType = "VIEW"
+				}
+				rollbackList = append(rollbackList, fmt.Sprintf("ALTER %s %s RENAME TO %s;", objectType, quotePostgresName(item.Table.Schema, item.NewName), quotePostgresIdentifier(item.Table.Name)))
+			default:
+				return "", errors.Errorf("cannot generate rollback statement for %q", node.Text())
+			}
+		}
+		return strings.Join(rollbackList, "\n"), nil
+	}
+	return "", errors.Errorf("cannot generate rollback statement for %q", node.Text())
+}
+
+func quotePostgresIdentifier(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
+
+func quotePostgresName(schema string, name string) string {
+	if schema != "" {
+		return fmt.Sprintf("%s.%s", quotePostgresIdentifier(schema), quotePostgresIdentifier(name))
+	}
+	return quotePostgresIdentifier(name)
+}
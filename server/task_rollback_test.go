@@ -0,0 +1,90 @@
+package server
+
+import (
+	"testing"
+
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+
+	// Register postgresql parser engine.
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
+)
+
+func TestGenerateRollbackStatement(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		want      string
+		wantErr   bool
+	}{
+		{
+			engine: db.MySQL,
+			statement: `CREATE TABLE t (id INT);
+				CREATE INDEX idx_id ON t (id);
+				ALTER TABLE t ADD COLUMN a INT, ADD COLUMN b INT, RENAME COLUMN c TO d, ADD UNIQUE KEY uk_a (a), RENAME INDEX i1 TO i2;
+				RENAME TABLE u TO v, db.w TO db.x;`,
+			want: "RENAME TABLE `db`.`x` TO `db`.`w`, `v` TO `u`;\n" +
+				"ALTER TABLE `t` RENAME INDEX `i2` TO `i1`, DROP INDEX `uk_a`, RENAME COLUMN `d` TO `c`, DROP COLUMN `b`, DROP COLUMN `a`;\n" +
+				"DROP INDEX `idx_id` ON `t`;\n" +
+				"DROP TABLE `t`;\n",
+		},
+		{
+			engine:    db.MySQL,
+			statement: "ALTER TABLE t ADD COLUMN a INT, RENAME TO u;",
+			want:      "ALTER TABLE `u` RENAME TO `t`, DROP COLUMN `a`;\n",
+		},
+		{
+			engine:    db.MySQL,
+			statement: "CREATE TABLE t (id INT); DROP TABLE u;",
+			wantErr:   true,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "CREATE TABLE IF NOT EXISTS t (id INT);",
+			wantErr:   true,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "ALTER TABLE t MODIFY COLUMN a BIGINT;",
+			wantErr:   true,
+		},
+		{
+			engine: db.Postgres,
+			statement: `CREATE TABLE public.t (id INT);
+				CREATE INDEX idx_id ON public.t (id);
+				ALTER TABLE t ADD COLUMN a INT, ADD COLUMN b INT;
+				ALTER TABLE t RENAME COLUMN c TO d;
+				ALTER TABLE s.u RENAME TO v;
+				ALTER INDEX i1 RENAME TO i2;`,
+			want: "ALTER INDEX \"i2\" RENAME TO \"i1\";\n" +
+				"ALTER TABLE \"s\".\"v\" RENAME TO \"u\";\n" +
+				"ALTER TABLE \"t\" RENAME COLUMN \"d\" TO \"c\";\n" +
+				"ALTER TABLE \"t\" DROP COLUMN \"b\";\nALTER TABLE \"t\" DROP COLUMN \"a\";\n" +
+				"DROP INDEX \"public\".\"idx_id\";\n" +
+				"DROP TABLE \"public\".\"t\";\n",
+		},
+		{
+			engine:    db.Postgres,
+			statement: "ALTER TABLE t DROP COLUMN a;",
+			wantErr:   true,
+		},
+		{
+			engine:    db.Snowflake,
+			statement: "CREATE TABLE t (id INT);",
+			want:      "",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := generateRollbackStatement(test.engine, test.statement)
+		if test.wantErr {
+			require.Error(t, err, test.statement)
+			continue
+		}
+		require.NoError(t, err, test.statement)
+		assert.Equal(t, test.want, got, test.statement)
+	}
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// taskRunArtifactRetentionPeriod is how long the task run artifacts are kept before being purged.
+	taskRunArtifactRetentionPeriod = time.Duration(30*24) * time.Hour
+)
+
+func (s *Server) registerTaskRunArtifactRoutes(g *echo.Group) {
+	g.GET("/pipeline/:pipelineID/task/:taskID/artifact", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+
+		artifactList, err := s.store.FindTaskRunArtifact(ctx, &api.TaskRunArtifactFind{TaskID: &taskID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch artifact list for task ID: %d", taskID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, artifactList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal artifact list response for task ID: %d", taskID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/pipeline/:pipelineID/task/:taskID/artifact/:artifactID/download", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+		artifactID, err := strconv.Atoi(c.Param("artifactID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Artifact ID is not a number: %s", c.Param("artifactID"))).SetInternal(err)
+		}
+
+		artifact, err := s.store.GetTaskRunArtifactByID(ctx, artifactID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch artifact ID: %d", artifactID)).SetInternal(err)
+		}
+		if artifact == nil || artifact.TaskID != taskID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Artifact not found with ID %d", artifactID))
+		}
+
+		localPath := filepath.Join(s.profile.DataDir, artifact.Path)
+		if artifact.StorageBackend != api.BackupStorageBackendLocal {
+			backend, err := s.getStorageBackend(artifact.StorageBackend)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Artifact ID %d is stored in %s, but it isn't configured", artifactID, artifact.StorageBackend)).SetInternal(err)
+			}
+			file, err := os.CreateTemp("", "artifact-*")
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create temporary file for downloading artifact").SetInternal(err)
+			}
+			defer os.Remove(file.Name())
+			err = backend.Download(ctx, artifact.Path, file)
+			file.Close()
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to download artifact ID %d from %s", artifactID, artifact.StorageBackend)).SetInternal(err)
+			}
+			localPath = file.Name()
+		}
+
+		if _, err := os.Stat(localPath); err != nil {
+			if os.IsNotExist(err) {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("The content of artifact ID %d no longer exists", artifactID))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to read artifact ID: %d", artifactID)).SetInternal(err)
+		}
+		return c.Attachment(localPath, artifact.Name)
+	})
+}
+
+// getTaskRunArtifactRelativePath returns the path of the artifact relative to the data dir, which is also the object key in the cloud bucket.
+func getTaskRunArtifactRelativePath(taskRunID int, name string) string {
+	return filepath.Join("artifact", "task-run", fmt.Sprintf("%d", taskRunID), filepath.Base(name))
+}
+
+// getRunningTaskRun returns the running task run of the task, and nil if there isn't one.
+func getRunningTaskRun(task *api.Task) *api.TaskRun {
+	for _, taskRun := range task.TaskRunList {
+		if taskRun.Status == api.TaskRunRunning {
+			return taskRun
+		}
+	}
+	return nil
+}
+
+// createTaskRunArtifact stores the content in the blob storage and attaches it to the running task run of the task.
+// The content is stored in the storage backend configured for the server, e.g. the data dir or the cloud bucket.
+func (s *Server) createTaskRunArtifact(ctx context.Context, task *api.Task, artifactType api.TaskRunArtifactType, name string, content []byte) (*api.TaskRunArtifact, error) {
+	taskRun := getRunningTaskRun(task)
+	if taskRun == nil {
+		return nil, errors.Errorf("no running task run for task %d", task.ID)
+	}
+
+	path := getTaskRunArtifactRelativePath(taskRun.ID, name)
+	storageBackend := s.getDefaultStorageBackend()
+	backend, err := s.getStorageBackend(storageBackend)
+	if err != nil {
+		return nil, err
+	}
+	if err := backend.Upload(ctx, path, bytes.NewReader(content)); err != nil {
+		return nil, errors.Wrapf(err, "failed to upload artifact %q to %s", path, storageBackend)
+	}
+
+	return s.store.CreateTaskRunArtifact(ctx, &api.TaskRunArtifactCreate{
+		CreatorID:      api.SystemBotID,
+		TaskRunID:      taskRun.ID,
+		TaskID:         task.ID,
+		Type:           artifactType,
+		Name:           filepath.Base(name),
+		Size:           int64(len(content)),
+		StorageBackend: storageBackend,
+		Path:           path,
+		ExpireTs:       time.Now().Add(taskRunArtifactRetentionPeriod).Unix(),
+	})
+}
+
+// createTaskRunArtifactReference attaches the blob owned by another resource, e.g. the backup dump file, to the running task run of the task.
+// The blob isn't copied, so it's left as is when the artifact expires.
+func (s *Server) createTaskRunArtifactReference(ctx context.Context, task *api.Task, artifactType api.TaskRunArtifactType, storageBackend api.BackupStorageBackend, path string, size int64) (*api.TaskRunArtifact, error) {
+	taskRun := getRunningTaskRun(task)
+	if taskRun == nil {
+		return nil, errors.Errorf("no running task run for task %d", task.ID)
+	}
+
+	return s.store.CreateTaskRunArtifact(ctx, &api.TaskRunArtifactCreate{
+		CreatorID:      api.SystemBotID,
+		TaskRunID:      taskRun.ID,
+		TaskID:         task.ID,
+		Type:           artifactType,
+		Name:           filepath.Base(path),
+		Size:           size,
+		StorageBackend: storageBackend,
+		Path:           path,
+		Reference:      true,
+		ExpireTs:       time.Now().Add(taskRunArtifactRetentionPeriod).Unix(),
+	})
+}
+
+// purgeExpiredTaskRunArtifact deletes the expired artifacts along with their blobs.
+func (s *Server) purgeExpiredTaskRunArtifact(ctx context.Context) {
+	nowTs := time.Now().Unix()
+	artifactList, err := s.store.FindTaskRunArtifact(ctx, &api.TaskRunArtifactFind{ExpireTsBefore: &nowTs})
+	if err != nil {
+		log.Error("Failed to find expired task run artifacts.", zap.Error(err))
+		return
+	}
+
+	for _, artifact := range artifactList {
+		if !artifact.Reference {
+			if err := s.deleteTaskRunArtifactBlob(ctx, artifact); err != nil {
+				log.Error("Failed to delete the blob of an expired task run artifact.", zap.Int("artifact_id", artifact.ID), zap.String("path", artifact.Path), zap.Error(err))
+				continue
+			}
+		}
+		if err := s.store.DeleteTaskRunArtifact(ctx, &api.TaskRunArtifactDelete{ID: artifact.ID}); err != nil {
+			log.Error("Failed to delete an expired task run artifact.", zap.Int("artifact_id", artifact.ID), zap.Error(err))
+			continue
+		}
+		log.Debug("Deleted expired task run artifact.", zap.Int("artifact_id", artifact.ID), zap.String("path", artifact.Path))
+	}
+}
+
+func (s *Server) deleteTaskRunArtifactBlob(ctx context.Context, artifact *api.TaskRunArtifact) error {
+	backend, err := s.getStorageBackend(artifact.StorageBackend)
+	if err != nil {
+		return err
+	}
+	if err := backend.Delete(ctx, artifact.Path); err != nil {
+		return errors.Wrapf(err, "failed to delete artifact %q from %s", artifact.Path, artifact.StorageBackend)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetTaskRunArtifactRelativePath(t *testing.T) {
+	assert.Equal(t, "artifact/task-run/101/gh-ost-sync.log", getTaskRunArtifactRelativePath(101, "gh-ost-sync.log"))
+	// The name must not escape the artifact directory of the task run.
+	assert.Equal(t, "artifact/task-run/101/passwd", getTaskRunArtifactRelativePath(101, "../../../etc/passwd"))
+}
+
+func TestGetRunningTaskRun(t *testing.T) {
+	task := &api.Task{
+		TaskRunList: []*api.TaskRun{
+			{ID: 101, Status: api.TaskRunFailed},
+			{ID: 102, Status: api.TaskRunRunning},
+		},
+	}
+	assert.Equal(t, 102, getRunningTaskRun(task).ID)
+
+	task.TaskRunList[1].Status = api.TaskRunDone
+	assert.Nil(t, getRunningTaskRun(task))
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	taskRunLogStreamInterval = time.Duration(500) * time.Millisecond
+)
+
+// taskRunLogBuffer buffers the execution log of a running task in memory.
+// The buffered log is streamed to the client while the task is running and persisted in the task run result afterward.
+type taskRunLogBuffer struct {
+	mu   sync.RWMutex
+	logs []*api.TaskRunLog
+	done bool
+}
+
+func (b *taskRunLogBuffer) append(log *api.TaskRunLog) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if log.CreatedTs == 0 {
+		log.CreatedTs = time.Now().Unix()
+	}
+	b.logs = append(b.logs, log)
+}
+
+func (b *taskRunLogBuffer) info(format string, a ...interface{}) {
+	b.append(&api.TaskRunLog{
+		Level:   api.TaskRunLogInfo,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (b *taskRunLogBuffer) error(format string, a ...interface{}) {
+	b.append(&api.TaskRunLog{
+		Level:   api.TaskRunLogError,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// statementLogger returns the statement logger used by the migration executor.
+func (b *taskRunLogBuffer) statementLogger() func(*db.StatementLog) {
+	return func(stmtLog *db.StatementLog) {
+		log := &api.TaskRunLog{
+			Level:          api.TaskRunLogInfo,
+			Message:        fmt.Sprintf("Executed statement %d of %d.", stmtLog.Index, stmtLog.Count),
+			StatementIndex: stmtLog.Index,
+			StatementCount: stmtLog.Count,
+			DurationNs:     stmtLog.DurationNs,
+			RowsAffected:   stmtLog.RowsAffected,
+		}
+		if stmtLog.Error != "" {
+			log.Level = api.TaskRunLogError
+			log.Message = fmt.Sprintf("Failed to execute statement %d of %d: %s", stmtLog.Index, stmtLog.Count, stmtLog.Error)
+		}
+		b.append(log)
+	}
+}
+
+// statementProgress returns the progress of the executed statements, and false if no statement has been executed.
+func (b *taskRunLogBuffer) statementProgress() (api.Progress, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var progress api.Progress
+	found := false
+	for _, log := range b.logs {
+		if log.StatementIndex == 0 {
+			continue
+		}
+		if !found {
+			progress.CreatedTs = log.CreatedTs
+			found = true
+		}
+		progress.TotalUnit = int64(log.StatementCount)
+		progress.CompletedUnit = int64(log.StatementIndex)
+		if log.Level == api.TaskRunLogError {
+			progress.CompletedUnit--
+		}
+		progress.UpdatedTs = log.CreatedTs
+	}
+	return progress, found
+}
+
+// getFailedStatementIndex returns the 1-based position of the failed statement in the execution log, 0 if no statement failed
+// or the statements are executed as a whole, which doesn't log the statements.
+func getFailedStatementIndex(executionLog []*api.TaskRunLog) int {
+	for i := len(executionLog) - 1; i >= 0; i-- {
+		if log := executionLog[i]; log.StatementIndex > 0 && log.Level == api.TaskRunLogError {
+			return log.StatementIndex
+		}
+	}
+	return 0
+}
+
+// list returns the logs starting from offset, and whether the buffer is complete.
+func (b *taskRunLogBuffer) list(offset int) ([]*api.TaskRunLog, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if offset >= len(b.logs) {
+		return nil, b.done
+	}
+	return append([]*api.TaskRunLog{}, b.logs[offset:]...), b.done
+}
+
+func (b *taskRunLogBuffer) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done = true
+}
+
+// getTaskRunLogBuffer returns the log buffer of the running task, or a discarded buffer if the task isn't run by the scheduler.
+func (s *Server) getTaskRunLogBuffer(taskID int) *taskRunLogBuffer {
+	if s.TaskScheduler != nil {
+		if buffer, ok := s.TaskScheduler.taskRunLog.Load(taskID); ok {
+			return buffer.(*taskRunLogBuffer)
+		}
+	}
+	return &taskRunLogBuffer{}
+}
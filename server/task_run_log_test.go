@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestTaskRunLogBufferStatementProgress(t *testing.T) {
+	buffer := &taskRunLogBuffer{}
+	_, ok := buffer.statementProgress()
+	assert.False(t, ok)
+
+	buffer.append(&api.TaskRunLog{CreatedTs: 100, Level: api.TaskRunLogInfo, Message: "Start running task."})
+	buffer.append(&api.TaskRunLog{CreatedTs: 101, Level: api.TaskRunLogInfo, StatementIndex: 1, StatementCount: 3})
+	buffer.append(&api.TaskRunLog{CreatedTs: 105, Level: api.TaskRunLogInfo, StatementIndex: 2, StatementCount: 3})
+	progress, ok := buffer.statementProgress()
+	assert.True(t, ok)
+	assert.Equal(t, api.Progress{TotalUnit: 3, CompletedUnit: 2, CreatedTs: 101, UpdatedTs: 105}, progress)
+
+	buffer.append(&api.TaskRunLog{CreatedTs: 108, Level: api.TaskRunLogError, StatementIndex: 3, StatementCount: 3})
+	progress, ok = buffer.statementProgress()
+	assert.True(t, ok)
+	assert.Equal(t, api.Progress{TotalUnit: 3, CompletedUnit: 2, CreatedTs: 101, UpdatedTs: 108}, progress)
+}
+
+func TestGetFailedStatementIndex(t *testing.T) {
+	tests := []struct {
+		executionLog []*api.TaskRunLog
+		want         int
+	}{
+		{
+			executionLog: nil,
+			want:         0,
+		},
+		{
+			executionLog: []*api.TaskRunLog{
+				{Level: api.TaskRunLogInfo, StatementIndex: 1, StatementCount: 2},
+				{Level: api.TaskRunLogInfo, StatementIndex: 2, StatementCount: 2},
+			},
+			want: 0,
+		},
+		{
+			executionLog: []*api.TaskRunLog{
+				{Level: api.TaskRunLogInfo, StatementIndex: 1, StatementCount: 3},
+				{Level: api.TaskRunLogError, StatementIndex: 2, StatementCount: 3},
+				{Level: api.TaskRunLogError, Message: "Failed to run task: failed to execute query"},
+			},
+			want: 2,
+		},
+		// The statements executed as a whole aren't logged, so the failed statement is unknown.
+		{
+			executionLog: []*api.TaskRunLog{
+				{Level: api.TaskRunLogInfo, Message: "Start executing MIGRATE migration version 0001 on database \"db\"."},
+				{Level: api.TaskRunLogError, Message: "Failed to run task: failed to execute query"},
+			},
+			want: 0,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, getFailedStatementIndex(test.executionLog))
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// taskRunProgressReportInterval is the minimum interval between the progress updates persisted for a task run.
+	taskRunProgressReportInterval = time.Duration(5) * time.Second
+	// taskRunProgressMaxStatementLength is the maximum length in characters of the statement kept in the progress.
+	taskRunProgressMaxStatementLength = 1024
+)
+
+func (s *Server) registerTaskRunProgressRoutes(g *echo.Group) {
+	g.GET("/pipeline/:pipelineID/task/:taskID/run/:taskRunID/progress", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+		taskRunID, err := strconv.Atoi(c.Param("taskRunID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task run ID is not a number: %s", c.Param("taskRunID"))).SetInternal(err)
+		}
+
+		taskRun, err := s.store.GetTaskRun(ctx, &api.TaskRunFind{ID: &taskRunID, TaskID: &taskID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task run ID: %d", taskRunID)).SetInternal(err)
+		}
+		if taskRun == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task run ID %d not found in task ID: %d", taskRunID, taskID))
+		}
+
+		progress := &api.TaskRunProgress{}
+		if err := json.Unmarshal([]byte(taskRun.Progress), progress); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal progress of task run ID: %d", taskRunID)).SetInternal(err)
+		}
+		progress.ID = taskRun.ID
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, progress); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal progress response of task run ID: %d", taskRunID)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// taskRunProgressReporter persists the progress reported by the executor of the running task run.
+// The executors report on every step, so the reports are throttled by taskRunProgressReportInterval unless forced.
+// It's not safe for concurrent use, each executor reports from a single goroutine.
+type taskRunProgressReporter struct {
+	server     *Server
+	taskID     int
+	reportedAt time.Time
+}
+
+func newTaskRunProgressReporter(server *Server, taskID int) *taskRunProgressReporter {
+	return &taskRunProgressReporter{
+		server: server,
+		taskID: taskID,
+	}
+}
+
+// report patches the progress of the running task run. The failure is only logged, because the progress is informational.
+func (r *taskRunProgressReporter) report(ctx context.Context, patch *api.TaskRunProgressPatch, force bool) {
+	now := time.Now()
+	if !force && now.Sub(r.reportedAt) < taskRunProgressReportInterval {
+		return
+	}
+	r.reportedAt = now
+
+	patch.TaskID = r.taskID
+	patch.UpdatedTs = now.Unix()
+	if err := r.server.store.PatchTaskRunProgress(ctx, patch); err != nil && common.ErrorCode(err) != common.NotFound {
+		log.Warn("Failed to patch task run progress",
+			zap.Int("task_id", r.taskID),
+			zap.Error(err),
+		)
+	}
+}
+
+// statementLogger returns the statement logger reporting the executed statements, the affected rows and the remaining time
+// estimated from the average duration of the statements executed in this run.
+func (r *taskRunProgressReporter) statementLogger(ctx context.Context) func(*db.StatementLog) {
+	var executedCount int
+	var totalDurationNs, rowsAffected int64
+	return func(stmtLog *db.StatementLog) {
+		executedCount++
+		totalDurationNs += stmtLog.DurationNs
+		rowsAffected += stmtLog.RowsAffected
+		eta := estimateRemainingSeconds(totalDurationNs, executedCount, stmtLog.Count-stmtLog.Index)
+		statement := stmtLog.Statement
+		if runes := []rune(statement); len(runes) > taskRunProgressMaxStatementLength {
+			statement = string(runes[:taskRunProgressMaxStatementLength]) + "..."
+		}
+		r.report(ctx, &api.TaskRunProgressPatch{
+			RowsCopied:       &rowsAffected,
+			CurrentStatement: &statement,
+			StatementIndex:   &stmtLog.Index,
+			StatementCount:   &stmtLog.Count,
+			EtaSeconds:       &eta,
+		}, stmtLog.Index == stmtLog.Count || stmtLog.Error != "")
+	}
+}
+
+// estimateRemainingSeconds estimates the remaining time of the remaining steps by the average duration of the completed steps.
+// It returns 0 if nothing has completed yet.
+func estimateRemainingSeconds(completedDurationNs int64, completedCount int, remainingCount int) int64 {
+	if completedCount <= 0 || remainingCount <= 0 {
+		return 0
+	}
+	return completedDurationNs / int64(completedCount) * int64(remainingCount) / int64(time.Second)
+}
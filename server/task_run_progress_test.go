@@ -0,0 +1,47 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateRemainingSeconds(t *testing.T) {
+	tests := []struct {
+		completedDurationNs int64
+		completedCount      int
+		remainingCount      int
+		want                int64
+	}{
+		{
+			completedDurationNs: 0,
+			completedCount:      0,
+			remainingCount:      10,
+			want:                0,
+		},
+		{
+			completedDurationNs: int64(10 * time.Second),
+			completedCount:      5,
+			remainingCount:      0,
+			want:                0,
+		},
+		{
+			completedDurationNs: int64(10 * time.Second),
+			completedCount:      5,
+			remainingCount:      3,
+			want:                6,
+		},
+		{
+			completedDurationNs: int64(500 * time.Millisecond),
+			completedCount:      1,
+			remainingCount:      5,
+			want:                2,
+		},
+	}
+
+	for _, test := range tests {
+		got := estimateRemainingSeconds(test.completedDurationNs, test.completedCount, test.remainingCount)
+		assert.Equal(t, test.want, got)
+	}
+}
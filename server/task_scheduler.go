@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -24,7 +25,7 @@ const (
 // NewTaskScheduler creates a new task scheduler.
 func NewTaskScheduler(server *Server) *TaskScheduler {
 	return &TaskScheduler{
-		executorGetters:  make(map[api.TaskType]func() TaskExecutor),
+		executorGetters:  make(map[api.TaskType]TaskExecutorFactory),
 		runningExecutors: make(map[int]TaskExecutor),
 		server:           server,
 	}
@@ -32,11 +33,18 @@ func NewTaskScheduler(server *Server) *TaskScheduler {
 
 // TaskScheduler is the task scheduler.
 type TaskScheduler struct {
-	executorGetters  map[api.TaskType]func() TaskExecutor
+	executorGetters  map[api.TaskType]TaskExecutorFactory
 	runningExecutors map[int]TaskExecutor
 	taskProgress     sync.Map // map[taskID]api.Progress
 	sharedTaskState  sync.Map // map[taskID]interface{}
-	server           *Server
+	taskRunLog       sync.Map // map[taskID]*taskRunLogBuffer
+	// taskQueuePosition is the queue position of the RUNNING tasks waiting for a free slot due to the concurrency limits.
+	taskQueuePosition sync.Map // map[taskID]int
+	// pauseRequest is the set of the RUNNING tasks requested to pause at the next statement boundary.
+	pauseRequest sync.Map // map[taskID]bool
+	// runningCancels is the cancel functions of the running task executors.
+	runningCancels sync.Map // map[taskID]context.CancelFunc
+	server         *Server
 }
 
 // Run will run the task scheduler.
@@ -48,6 +56,10 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 	for {
 		select {
 		case <-ticker.C:
+			if s.server.isInMaintenance() {
+				log.Debug("Task scheduler paused in maintenance mode")
+				continue
+			}
 			func() {
 				defer func() {
 					if r := recover(); r != nil {
@@ -66,12 +78,23 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					if executor.IsCompleted() {
 						delete(s.runningExecutors, i)
 						s.taskProgress.Delete(i)
+						s.taskRunLog.Delete(i)
+						s.pauseRequest.Delete(i)
 					}
 				}
 
 				// Update task progress
 				for i, executor := range s.runningExecutors {
-					s.taskProgress.Store(i, executor.GetProgress())
+					progress := executor.GetProgress()
+					// Fall back to the progress of the executed statements for the executors not reporting progress.
+					if progress == (api.Progress{}) {
+						if buffer, ok := s.taskRunLog.Load(i); ok {
+							if statementProgress, ok := buffer.(*taskRunLogBuffer).statementProgress(); ok {
+								progress = statementProgress
+							}
+						}
+					}
+					s.taskProgress.Store(i, progress)
 				}
 
 				// Inspect all open pipelines and schedule the next PENDING task if applicable
@@ -109,6 +132,31 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					log.Error("Failed to retrieve running tasks", zap.Error(err))
 					return
 				}
+				// Tasks are started in the order of creation, so that queued tasks get a stable queue position.
+				sort.Slice(taskList, func(i, j int) bool {
+					return taskList[i].ID < taskList[j].ID
+				})
+
+				globalLimit, instanceLimit, err := s.getTaskConcurrencyLimit(ctx)
+				if err != nil {
+					log.Error("Failed to retrieve task concurrency limits", zap.Error(err))
+					return
+				}
+				heartbeatTimeout, err := s.getTaskHeartbeatTimeout(ctx)
+				if err != nil {
+					log.Error("Failed to retrieve task heartbeat timeout", zap.Error(err))
+					return
+				}
+				runningCount := 0
+				runningCountByInstance := make(map[int]int)
+				for _, task := range taskList {
+					if _, ok := s.runningExecutors[task.ID]; ok {
+						runningCount++
+						runningCountByInstance[task.InstanceID]++
+					}
+				}
+				queuePosition := 0
+				queuedTaskIDs := make(map[int]bool)
 
 				for _, task := range taskList {
 					if task.ID == api.OnboardingTaskID1 || task.ID == api.OnboardingTaskID2 {
@@ -133,10 +181,63 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					if _, ok := s.runningExecutors[task.ID]; ok {
 						continue
 					}
-					s.runningExecutors[task.ID] = executorGetter()
 
-					go func(task *api.Task, executor TaskExecutor) {
-						done, result, err := RunTaskExecutorOnce(ctx, executor, s.server, task)
+					// The task may still be run by another executor, or it's orphaned and recovered by its migration history.
+					skip, err := s.recoverOrphanedTask(ctx, task, heartbeatTimeout, time.Now())
+					if err != nil {
+						log.Error("Failed to recover orphaned task",
+							zap.Int("id", task.ID),
+							zap.String("name", task.Name),
+							zap.Error(err),
+						)
+						continue
+					}
+					if skip {
+						continue
+					}
+
+					if (globalLimit > 0 && runningCount >= globalLimit) || (instanceLimit > 0 && runningCountByInstance[task.InstanceID] >= instanceLimit) {
+						queuePosition++
+						queuedTaskIDs[task.ID] = true
+						s.taskQueuePosition.Store(task.ID, queuePosition)
+						continue
+					}
+					runningCount++
+					runningCountByInstance[task.InstanceID]++
+					s.taskQueuePosition.Delete(task.ID)
+
+					s.runningExecutors[task.ID] = executorGetter()
+					taskRunLog := &taskRunLogBuffer{}
+					taskRunLog.info("Start running task %q.", task.Name)
+					s.taskRunLog.Store(task.ID, taskRunLog)
+
+					runCtx, cancel := context.WithCancel(ctx)
+					s.runningCancels.Store(task.ID, cancel)
+					go func(task *api.Task, executor TaskExecutor, taskRunLog *taskRunLogBuffer) {
+						stopHeartbeat := s.startTaskRunHeartbeat(ctx, task.ID)
+						defer func() {
+							stopHeartbeat()
+							cancel()
+							s.runningCancels.Delete(task.ID)
+						}()
+						done, result, err := RunTaskExecutorOnce(runCtx, executor, s.server, task)
+						// The task has been marked as CANCELED by CancelTaskRun, so its status is left as is.
+						if errors.Is(runCtx.Err(), context.Canceled) {
+							taskRunLog.info("Task canceled.")
+							taskRunLog.finish()
+							return
+						}
+						if done {
+							if common.ErrorCode(err) == common.MigrationPaused {
+								taskRunLog.info("Task paused.")
+							} else if err != nil {
+								taskRunLog.error("Failed to run task: %s", err.Error())
+							} else {
+								taskRunLog.info("Task completed.")
+							}
+							taskRunLog.finish()
+						}
+						executionLog, _ := taskRunLog.list(0)
 						if !done && err != nil {
 							log.Debug("Encountered transient error running task, will retry",
 								zap.Int("id", task.ID),
@@ -146,6 +247,37 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							)
 							return
 						}
+						if done && common.ErrorCode(err) == common.MigrationPaused {
+							bytes, marshalErr := json.Marshal(api.TaskRunResultPayload{
+								Detail:       err.Error(),
+								ExecutionLog: executionLog,
+							})
+							if marshalErr != nil {
+								log.Error("Failed to marshal task run result",
+									zap.Int("task_id", task.ID),
+									zap.String("type", string(task.Type)),
+									zap.Error(marshalErr),
+								)
+								return
+							}
+							code := common.MigrationPaused
+							result := string(bytes)
+							taskStatusPatch := &api.TaskStatusPatch{
+								ID:        task.ID,
+								UpdaterID: api.SystemBotID,
+								Status:    api.TaskPaused,
+								Code:      &code,
+								Result:    &result,
+							}
+							if _, err := s.server.patchTaskStatus(ctx, task, taskStatusPatch); err != nil {
+								log.Error("Failed to mark task as PAUSED",
+									zap.Int("id", task.ID),
+									zap.String("name", task.Name),
+									zap.Error(err),
+								)
+							}
+							return
+						}
 						if done && err != nil {
 							log.Warn("Failed to run task",
 								zap.Int("id", task.ID),
@@ -154,7 +286,9 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 								zap.Error(err),
 							)
 							bytes, marshalErr := json.Marshal(api.TaskRunResultPayload{
-								Detail: err.Error(),
+								Detail:               err.Error(),
+								ExecutionLog:         executionLog,
+								FailedStatementIndex: getFailedStatementIndex(executionLog),
 							})
 							if marshalErr != nil {
 								log.Error("Failed to marshal task run result",
@@ -184,6 +318,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							return
 						}
 						if done && err == nil {
+							result.ExecutionLog = executionLog
 							bytes, err := json.Marshal(*result)
 							if err != nil {
 								log.Error("Failed to marshal task run result",
@@ -248,8 +383,16 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							}
 							return
 						}
-					}(task, s.runningExecutors[task.ID])
+					}(task, s.runningExecutors[task.ID], taskRunLog)
 				}
+
+				// Clear the queue position of the tasks no longer queued, e.g. canceled tasks.
+				s.taskQueuePosition.Range(func(key, _ interface{}) bool {
+					if !queuedTaskIDs[key.(int)] {
+						s.taskQueuePosition.Delete(key)
+					}
+					return true
+				})
 			}()
 		case <-ctx.Done(): // if cancel() execute
 			return
@@ -257,8 +400,38 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// CancelTaskRun interrupts the running executor of the task, which has been marked as CANCELED.
+// The statement running on the database server is canceled as well.
+func (s *TaskScheduler) CancelTaskRun(taskID int) {
+	if cancel, ok := s.runningCancels.Load(taskID); ok {
+		cancel.(context.CancelFunc)()
+	}
+}
+
+// getTaskConcurrencyLimit returns the global and per-instance task concurrency limits, 0 means unlimited.
+func (s *TaskScheduler) getTaskConcurrencyLimit(ctx context.Context) (int, int, error) {
+	settingList, err := s.server.store.FindSetting(ctx, &api.SettingFind{})
+	if err != nil {
+		return 0, 0, err
+	}
+	globalLimit, instanceLimit := 0, 0
+	for _, setting := range settingList {
+		switch setting.Name {
+		case api.SettingTaskConcurrencyGlobal:
+			if globalLimit, err = strconv.Atoi(setting.Value); err != nil {
+				return 0, 0, errors.Wrapf(err, "invalid setting %s value %q", setting.Name, setting.Value)
+			}
+		case api.SettingTaskConcurrencyInstance:
+			if instanceLimit, err = strconv.Atoi(setting.Value); err != nil {
+				return 0, 0, errors.Wrapf(err, "invalid setting %s value %q", setting.Name, setting.Value)
+			}
+		}
+	}
+	return globalLimit, instanceLimit, nil
+}
+
 // Register will register a task executor factory.
-func (s *TaskScheduler) Register(taskType api.TaskType, executorGetter func() TaskExecutor) {
+func (s *TaskScheduler) Register(taskType api.TaskType, executorGetter TaskExecutorFactory) {
 	if executorGetter == nil {
 		panic("scheduler: Register executor is nil for task type: " + taskType)
 	}
@@ -339,8 +512,15 @@ func (s *TaskScheduler) passAllCheck(ctx context.Context, task *api.Task, allowe
 	return true, nil
 }
 
-// auto transit PendingApproval to Pending if all required task checks pass.
+// auto transit PendingApproval to Pending if all required task checks pass and the statement comments are resolved.
 func (s *TaskScheduler) canAutoApprove(ctx context.Context, task *api.Task) (bool, error) {
+	count, err := s.server.store.CountUnresolvedStatementComment(ctx, task.ID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to count unresolved statement comments")
+	}
+	if count > 0 {
+		return false, nil
+	}
 	return s.passAllCheck(ctx, task, api.TaskCheckStatusSuccess)
 }
 
@@ -418,3 +598,14 @@ func getActiveStage(stageList []*api.Stage) *api.Stage {
 	}
 	return nil
 }
+
+// requestTaskPause requests the running task to pause at the next statement boundary.
+func (s *TaskScheduler) requestTaskPause(taskID int) {
+	s.pauseRequest.Store(taskID, true)
+}
+
+// isTaskPauseRequested returns true if the running task is requested to pause.
+func (s *TaskScheduler) isTaskPauseRequested(taskID int) bool {
+	_, ok := s.pauseRequest.Load(taskID)
+	return ok
+}
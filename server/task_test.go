@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAreAllTasksDone(t *testing.T) {
@@ -84,3 +86,73 @@ func TestAreAllTasksDone(t *testing.T) {
 		assert.Equal(t, test.want, res)
 	}
 }
+
+func TestSuppressTaskCheckResult(t *testing.T) {
+	newCheckResult := func() *api.TaskCheckRunResultPayload {
+		return &api.TaskCheckRunResultPayload{
+			ResultList: []api.TaskCheckResult{
+				{
+					Namespace: api.AdvisorNamespace,
+					Code:      advisor.StatementNoWhere.Int(),
+					Status:    api.TaskCheckStatusError,
+					Title:     "statement.where.require",
+				},
+				{
+					Namespace: api.AdvisorNamespace,
+					Code:      advisor.StatementSyntaxError.Int(),
+					Status:    api.TaskCheckStatusError,
+					Title:     "Syntax error",
+				},
+				{
+					Namespace: api.BBNamespace,
+					Status:    api.TaskCheckStatusError,
+					Title:     "Failed to connect",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		suppress *api.TaskCheckResultSuppress
+		wantErr  bool
+	}{
+		{
+			suppress: &api.TaskCheckResultSuppress{ApproverID: 101, Index: 0, Justification: "The table only has a few rows"},
+			wantErr:  false,
+		},
+		{
+			suppress: &api.TaskCheckResultSuppress{ApproverID: 101, Index: 0},
+			wantErr:  true,
+		},
+		{
+			suppress: &api.TaskCheckResultSuppress{ApproverID: 101, Index: 1, Justification: "Ignore"},
+			wantErr:  true,
+		},
+		{
+			suppress: &api.TaskCheckResultSuppress{ApproverID: 101, Index: 2, Justification: "Ignore"},
+			wantErr:  true,
+		},
+		{
+			suppress: &api.TaskCheckResultSuppress{ApproverID: 101, Index: 3, Justification: "Ignore"},
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		checkResult := newCheckResult()
+		err := suppressTaskCheckResult(checkResult, test.suppress, 1660000000)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, &api.TaskCheckResultSuppression{
+			Justification: test.suppress.Justification,
+			ApproverID:    test.suppress.ApproverID,
+			SuppressedTs:  1660000000,
+		}, checkResult.ResultList[test.suppress.Index].Suppression)
+
+		// The same result can not be suppressed twice.
+		assert.Error(t, suppressTaskCheckResult(checkResult, test.suppress, 1660000000))
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	metricAPI "github.com/bytebase/bytebase/metric"
+)
+
+func (s *Server) registerUsageRoutes(g *echo.Group) {
+	g.GET("/usage", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		collectedTs, err := s.store.GetLatestUsageMetricCreatedTs(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch the latest usage metric collection time").SetInternal(err)
+		}
+
+		var metricList []*api.UsageMetric
+		if collectedTs > 0 {
+			metricList, err = s.store.FindUsageMetric(ctx, &api.UsageMetricFind{CreatedTs: &collectedTs})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch usage metric list").SetInternal(err)
+			}
+		}
+
+		usage := buildWorkspaceUsage(metricList)
+		usage.CollectedTs = collectedTs
+
+		optOutName := api.SettingWorkspaceMetricOptOut
+		settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &optOutName})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch the metric opt-out setting").SetInternal(err)
+		}
+		for _, setting := range settingList {
+			usage.OptOut = setting.Value == "true"
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, usage); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal workspace usage response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// buildWorkspaceUsage builds the usage dashboard from the usage metrics collected in a round.
+// Only the instances in the normal status are counted.
+func buildWorkspaceUsage(metricList []*api.UsageMetric) *api.WorkspaceUsage {
+	usage := &api.WorkspaceUsage{
+		EngineMix:            []*api.UsageCount{},
+		WeeklyIssueCountList: []*api.UsageCount{},
+		MetricList:           []*api.UsageMetric{},
+	}
+
+	engineMap := make(map[string]int)
+	for _, usageMetric := range metricList {
+		usage.MetricList = append(usage.MetricList, usageMetric)
+		switch usageMetric.Name {
+		case string(metricAPI.InstanceCountMetricName):
+			if usageMetric.Labels["status"] != string(api.Normal) {
+				continue
+			}
+			usage.InstanceCount += usageMetric.Value
+			engineMap[usageMetric.Labels["engine"]] += usageMetric.Value
+		case string(metricAPI.WeeklyIssueCountMetricName):
+			usage.WeeklyIssueCountList = append(usage.WeeklyIssueCountList, &api.UsageCount{
+				Key:   usageMetric.Labels["week"],
+				Count: usageMetric.Value,
+			})
+		}
+	}
+
+	for engine, count := range engineMap {
+		usage.EngineMix = append(usage.EngineMix, &api.UsageCount{
+			Key:   engine,
+			Count: count,
+		})
+	}
+	// Sort by count descending so that the dominant engines come first.
+	sort.Slice(usage.EngineMix, func(i, j int) bool {
+		if usage.EngineMix[i].Count != usage.EngineMix[j].Count {
+			return usage.EngineMix[i].Count > usage.EngineMix[j].Count
+		}
+		return usage.EngineMix[i].Key < usage.EngineMix[j].Key
+	})
+	// The week key is formatted as "2006-01-02", so the string order is the time order.
+	sort.Slice(usage.WeeklyIssueCountList, func(i, j int) bool {
+		return usage.WeeklyIssueCountList[i].Key < usage.WeeklyIssueCountList[j].Key
+	})
+	return usage
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestBuildWorkspaceUsage(t *testing.T) {
+	metricList := []*api.UsageMetric{
+		{Name: "bb.instance.count", Value: 2, Labels: map[string]string{"engine": "MYSQL", "environment": "Test", "status": "NORMAL"}},
+		{Name: "bb.instance.count", Value: 1, Labels: map[string]string{"engine": "POSTGRES", "environment": "Prod", "status": "NORMAL"}},
+		{Name: "bb.instance.count", Value: 3, Labels: map[string]string{"engine": "MYSQL", "environment": "Prod", "status": "NORMAL"}},
+		{Name: "bb.instance.count", Value: 4, Labels: map[string]string{"engine": "TIDB", "environment": "Prod", "status": "ARCHIVED"}},
+		{Name: "bb.issue.weekly.count", Value: 7, Labels: map[string]string{"week": "2022-08-29"}},
+		{Name: "bb.issue.weekly.count", Value: 5, Labels: map[string]string{"week": "2022-08-22"}},
+		{Name: "bb.project.count", Value: 9, Labels: map[string]string{}},
+	}
+
+	usage := buildWorkspaceUsage(metricList)
+	assert.Equal(t, 6, usage.InstanceCount)
+	assert.Equal(t, []*api.UsageCount{
+		{Key: "MYSQL", Count: 5},
+		{Key: "POSTGRES", Count: 1},
+	}, usage.EngineMix)
+	assert.Equal(t, []*api.UsageCount{
+		{Key: "2022-08-22", Count: 5},
+		{Key: "2022-08-29", Count: 7},
+	}, usage.WeeklyIssueCountList)
+	assert.Len(t, usage.MetricList, len(metricList))
+
+	empty := buildWorkspaceUsage(nil)
+	assert.Equal(t, 0, empty.InstanceCount)
+	assert.Empty(t, empty.EngineMix)
+	assert.Empty(t, empty.WeeklyIssueCountList)
+}
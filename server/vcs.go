@@ -18,7 +18,8 @@ func (s *Server) registerVCSRoutes(g *echo.Group) {
 	g.POST("/vcs", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		vcsCreate := &api.VCSCreate{
-			CreatorID: c.Get(getPrincipalIDContextKey()).(int),
+			CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
+			WorkspaceID: c.Get(getWorkspaceIDContextKey()).(int),
 		}
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, vcsCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create VCS request").SetInternal(err)
@@ -41,7 +42,10 @@ func (s *Server) registerVCSRoutes(g *echo.Group) {
 
 	g.GET("/vcs", func(c echo.Context) error {
 		ctx := c.Request().Context()
-		vcsFind := &api.VCSFind{}
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		vcsFind := &api.VCSFind{
+			WorkspaceID: &workspaceID,
+		}
 		vcsList, err := s.store.FindVCS(ctx, vcsFind)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch vcs list").SetInternal(err)
@@ -561,8 +561,8 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 
 	var createContext string
 	if repo.Project.TenantMode == api.TenantModeTenant {
-		if !s.feature(api.FeatureMultiTenancy) {
-			return "", false, echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
+		if err := s.checkFeature(api.FeatureMultiTenancy); err != nil {
+			return "", false, featureHTTPError(err)
 		}
 		createContext, err = createTenantSchemaUpdateIssue(mi, pushEvent, content)
 	} else {
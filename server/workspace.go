@@ -0,0 +1,529 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+const (
+	workspaceIDContextKey = "workspace_id"
+)
+
+func getWorkspaceIDContextKey() string {
+	return workspaceIDContextKey
+}
+
+func (s *Server) registerWorkspaceRoutes(g *echo.Group) {
+	g.POST("/workspace", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if err := s.checkFeature(api.FeatureMultiWorkspace); err != nil {
+			return featureHTTPError(err)
+		}
+		// Only the owners of the default workspace can create workspaces.
+		if c.Get(getWorkspaceIDContextKey()).(int) != api.DefaultWorkspaceID || c.Get(getRoleContextKey()).(api.Role) != api.Owner {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the owner of the default workspace can create workspaces")
+		}
+
+		workspaceCreate := &api.WorkspaceCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, workspaceCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create workspace request").SetInternal(err)
+		}
+		workspaceCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		if workspaceCreate.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Workspace name is required")
+		}
+		if workspaceCreate.OwnerEmail == "" || workspaceCreate.OwnerPassword == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Workspace owner email and password are required")
+		}
+		// Checks the owner email beforehand, so that we don't leave a workspace without any owner behind.
+		principal, err := s.store.GetPrincipalByEmail(ctx, workspaceCreate.OwnerEmail)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find user by email: %s", workspaceCreate.OwnerEmail)).SetInternal(err)
+		}
+		if principal != nil {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Email already exists: %s", workspaceCreate.OwnerEmail))
+		}
+
+		workspace, err := s.store.CreateWorkspace(ctx, workspaceCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Workspace name already exists: %s", workspaceCreate.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create workspace").SetInternal(err)
+		}
+
+		// The first member of the workspace is granted the Owner role.
+		signUp := &api.SignUp{
+			Name:     workspaceCreate.OwnerName,
+			Email:    workspaceCreate.OwnerEmail,
+			Password: workspaceCreate.OwnerPassword,
+		}
+		if _, httpErr := trySignUp(ctx, s, signUp, workspace.ID, workspaceCreate.CreatorID); httpErr != nil {
+			return httpErr
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, workspace); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create workspace response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/workspace", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		workspaceFind := &api.WorkspaceFind{}
+		// The owners of the default workspace can see all workspaces, the others can only see their own workspace.
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		if workspaceID != api.DefaultWorkspaceID || c.Get(getRoleContextKey()).(api.Role) != api.Owner {
+			workspaceFind.ID = &workspaceID
+		}
+		if rowStatusStr := c.QueryParam("rowstatus"); rowStatusStr != "" {
+			rowStatus := api.RowStatus(rowStatusStr)
+			workspaceFind.RowStatus = &rowStatus
+		}
+		workspaceList, err := s.store.FindWorkspace(ctx, workspaceFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch workspace list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, workspaceList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal workspace list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/workspace/:workspaceID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("workspaceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Workspace ID is not a number: %s", c.Param("workspaceID"))).SetInternal(err)
+		}
+		// The owners can patch their own workspace, and the owners of the default workspace can patch any workspace.
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		if workspaceID != id && workspaceID != api.DefaultWorkspaceID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Workspace ID not found: %d", id))
+		}
+		if c.Get(getRoleContextKey()).(api.Role) != api.Owner {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the workspace owner can update the workspace")
+		}
+
+		workspacePatch := &api.WorkspacePatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, workspacePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch workspace request").SetInternal(err)
+		}
+		if id == api.DefaultWorkspaceID && workspacePatch.RowStatus != nil && *workspacePatch.RowStatus == string(api.Archived) {
+			return echo.NewHTTPError(http.StatusBadRequest, "The default workspace cannot be archived")
+		}
+
+		workspace, err := s.store.PatchWorkspace(ctx, workspacePatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Workspace ID not found: %d", id))
+			}
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Workspace name already exists: %s", *workspacePatch.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch workspace ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, workspace); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal workspace ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// workspaceResourceType is the type of the resource which belongs to a workspace.
+type workspaceResourceType string
+
+const (
+	workspaceResourceProject          workspaceResourceType = "project"
+	workspaceResourceEnvironment      workspaceResourceType = "environment"
+	workspaceResourceInstance         workspaceResourceType = "instance"
+	workspaceResourceDatabase         workspaceResourceType = "database"
+	workspaceResourceIssue            workspaceResourceType = "issue"
+	workspaceResourceMember           workspaceResourceType = "member"
+	workspaceResourceDatabaseTemplate workspaceResourceType = "database-template"
+	workspaceResourceIssueView        workspaceResourceType = "issue-view"
+	workspaceResourcePipeline         workspaceResourceType = "pipeline"
+	workspaceResourceStage            workspaceResourceType = "stage"
+	workspaceResourceTask             workspaceResourceType = "task"
+	workspaceResourceSheet            workspaceResourceType = "sheet"
+	workspaceResourceDataDiff         workspaceResourceType = "data-diff"
+	workspaceResourceRecurringTask    workspaceResourceType = "recurring-task"
+	workspaceResourceActivity         workspaceResourceType = "activity"
+	workspaceResourcePrincipal        workspaceResourceType = "principal"
+	workspaceResourceVCS              workspaceResourceType = "vcs"
+	workspaceResourceLabel            workspaceResourceType = "label"
+	workspaceResourceInstanceUser     workspaceResourceType = "instance-user"
+	workspaceResourceManagedGrant     workspaceResourceType = "managed-grant"
+	workspaceResourceQueryGrant       workspaceResourceType = "query-grant"
+	workspaceResourceTaskCheckRun     workspaceResourceType = "task-check-run"
+	workspaceResourceTaskRun          workspaceResourceType = "task-run"
+	workspaceResourceTaskRunArtifact  workspaceResourceType = "task-run-artifact"
+	workspaceResourceStatementComment workspaceResourceType = "statement-comment"
+)
+
+// workspaceResource is a resource addressed by the route, which belongs to a workspace.
+type workspaceResource struct {
+	resourceType workspaceResourceType
+	id           string
+}
+
+// workspaceResourceParamMap is the map from the route param names to the resource types.
+var workspaceResourceParamMap = map[string]workspaceResourceType{
+	"projectID":          workspaceResourceProject,
+	"environmentID":      workspaceResourceEnvironment,
+	"instanceID":         workspaceResourceInstance,
+	"issueID":            workspaceResourceIssue,
+	"databaseTemplateID": workspaceResourceDatabaseTemplate,
+	"issueViewID":        workspaceResourceIssueView,
+	"pipelineID":         workspaceResourcePipeline,
+	"stageID":            workspaceResourceStage,
+	"taskID":             workspaceResourceTask,
+	"sheetID":            workspaceResourceSheet,
+	"dataDiffID":         workspaceResourceDataDiff,
+	"recurringTaskID":    workspaceResourceRecurringTask,
+	"activityID":         workspaceResourceActivity,
+	"principalID":        workspaceResourcePrincipal,
+	"vcsID":              workspaceResourceVCS,
+	"taskCheckRunID":     workspaceResourceTaskCheckRun,
+	"taskRunID":          workspaceResourceTaskRun,
+	"artifactID":         workspaceResourceTaskRunArtifact,
+	"commentID":          workspaceResourceStatementComment,
+}
+
+// workspaceResourceRouteParamList is the list of the route param names whose resource types depend on the route,
+// the first route prefix matched wins.
+// The "historyID" isn't listed, because the migration history ID is local to the instance,
+// and the handlers always look it up in the database or instance of the route, which is checked by itself.
+var workspaceResourceRouteParamList = []struct {
+	name         string
+	routePrefix  string
+	resourceType workspaceResourceType
+}{
+	{"userID", "/api/instance/", workspaceResourceInstanceUser},
+	{"userID", "/api/", workspaceResourcePrincipal},
+	{"grantID", "/api/database/:id/managed-grant/", workspaceResourceManagedGrant},
+	{"grantID", "/api/database/:id/query-grant/", workspaceResourceQueryGrant},
+}
+
+// getWorkspaceResourceList returns the workspace resources addressed by the route.
+// Every resource is returned, because the handlers don't always check that the nested resource belongs to its parent,
+// e.g. the task of /pipeline/:pipelineID/task/:taskID may be in another pipeline.
+// The routes using the generic "id" param are told apart by the route prefix.
+func getWorkspaceResourceList(routePath string, paramNames []string, paramValues []string) []workspaceResource {
+	var resourceList []workspaceResource
+	for i, name := range paramNames {
+		if i >= len(paramValues) {
+			break
+		}
+		if resourceType, ok := workspaceResourceParamMap[name]; ok {
+			resourceList = append(resourceList, workspaceResource{resourceType: resourceType, id: paramValues[i]})
+			continue
+		}
+		if resourceType, ok := getRouteParamResourceType(routePath, name); ok {
+			resourceList = append(resourceList, workspaceResource{resourceType: resourceType, id: paramValues[i]})
+			continue
+		}
+		if name != "id" {
+			continue
+		}
+		for _, resourceType := range []workspaceResourceType{
+			workspaceResourceProject,
+			workspaceResourceEnvironment,
+			workspaceResourceDatabase,
+			workspaceResourceMember,
+			workspaceResourceSheet,
+			workspaceResourceLabel,
+		} {
+			if strings.HasPrefix(routePath, fmt.Sprintf("/api/%s/:id", resourceType)) {
+				resourceList = append(resourceList, workspaceResource{resourceType: resourceType, id: paramValues[i]})
+				break
+			}
+		}
+	}
+	return resourceList
+}
+
+// getRouteParamResourceType returns the resource type of the route param depending on the route.
+func getRouteParamResourceType(routePath string, name string) (workspaceResourceType, bool) {
+	for _, param := range workspaceResourceRouteParamList {
+		if param.name == name && strings.HasPrefix(routePath, param.routePrefix) {
+			return param.resourceType, true
+		}
+	}
+	return "", false
+}
+
+// checkWorkspaceResource makes sure the resources addressed by the request belong to the workspace.
+// The resource in another workspace is reported as not found, so that its existence isn't leaked.
+func checkWorkspaceResource(c echo.Context, workspaceID int, getWorkspaceID func(resourceType workspaceResourceType, id int) (*int, error)) error {
+	resourceList := getWorkspaceResourceList(c.Path(), c.ParamNames(), c.ParamValues())
+	return checkWorkspaceResourceList(resourceList, workspaceID, getWorkspaceID)
+}
+
+// checkRequestWorkspaceResource makes sure the resources addressed by the IDs in the request body belong to the workspace,
+// which the ACL middleware doesn't see.
+func (s *Server) checkRequestWorkspaceResource(ctx context.Context, workspaceID int, resourceList []workspaceResource) error {
+	return checkWorkspaceResourceList(resourceList, workspaceID, func(resourceType workspaceResourceType, id int) (*int, error) {
+		return s.getResourceWorkspaceID(ctx, resourceType, id)
+	})
+}
+
+func checkWorkspaceResourceList(resourceList []workspaceResource, workspaceID int, getWorkspaceID func(resourceType workspaceResourceType, id int) (*int, error)) error {
+	resource, err := findOtherWorkspaceResource(resourceList, workspaceID, getWorkspaceID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+	}
+	if resource != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Resource not found: %s/%s", resource.resourceType, resource.id))
+	}
+	return nil
+}
+
+// getPipelineWorkspaceResourceList returns the workspace resources of the issue to create, i.e. the project,
+// and the environments, instances and databases of the pipeline.
+func getPipelineWorkspaceResourceList(projectID int, pipelineCreate *api.PipelineCreate) []workspaceResource {
+	resourceList := []workspaceResource{{resourceType: workspaceResourceProject, id: strconv.Itoa(projectID)}}
+	for _, stage := range pipelineCreate.StageList {
+		resourceList = append(resourceList, workspaceResource{resourceType: workspaceResourceEnvironment, id: strconv.Itoa(stage.EnvironmentID)})
+		for _, task := range stage.TaskList {
+			resourceList = append(resourceList, workspaceResource{resourceType: workspaceResourceInstance, id: strconv.Itoa(task.InstanceID)})
+			if task.DatabaseID != nil {
+				resourceList = append(resourceList, workspaceResource{resourceType: workspaceResourceDatabase, id: strconv.Itoa(*task.DatabaseID)})
+			}
+		}
+	}
+	return resourceList
+}
+
+// findOtherWorkspaceResource returns the first resource belonging to another workspace, or nil if there is none.
+// getWorkspaceID returns nil for the resource not found or shared by all workspaces, which is left to the handler,
+// and so is the malformed ID.
+func findOtherWorkspaceResource(resourceList []workspaceResource, workspaceID int, getWorkspaceID func(resourceType workspaceResourceType, id int) (*int, error)) (*workspaceResource, error) {
+	for i, resource := range resourceList {
+		id, err := strconv.Atoi(resource.id)
+		if err != nil {
+			continue
+		}
+		resourceWorkspaceID, err := getWorkspaceID(resource.resourceType, id)
+		if err != nil {
+			return nil, err
+		}
+		if resourceWorkspaceID != nil && *resourceWorkspaceID != workspaceID {
+			return &resourceList[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// getActivityContainerType returns the resource type of the activity container, e.g. the issue of "bb.issue.xxx".
+// It returns false for the activities shared by all workspaces, e.g. the settings.
+func getActivityContainerType(activityType api.ActivityType) (workspaceResourceType, bool) {
+	for _, prefix := range []struct {
+		prefix       string
+		resourceType workspaceResourceType
+	}{
+		{"bb.issue.", workspaceResourceIssue},
+		{"bb.pipeline.", workspaceResourceIssue},
+		{"bb.project.", workspaceResourceProject},
+		// The database activities are kept in the project of the database.
+		{"bb.database.", workspaceResourceProject},
+		{"bb.member.", workspaceResourceMember},
+		{"bb.sql-editor.", workspaceResourceInstance},
+		{"bb.instance.", workspaceResourceInstance},
+	} {
+		if strings.HasPrefix(string(activityType), prefix.prefix) {
+			return prefix.resourceType, true
+		}
+	}
+	return "", false
+}
+
+// getResourceWorkspaceID returns the workspace ID of the resource, or nil if the resource isn't found
+// or is shared by all workspaces.
+func (s *Server) getResourceWorkspaceID(ctx context.Context, resourceType workspaceResourceType, id int) (*int, error) {
+	switch resourceType {
+	case workspaceResourceProject:
+		// The default project holds the unassigned databases of all workspaces.
+		if id == api.DefaultProjectID {
+			return nil, nil
+		}
+		project, err := s.store.GetProjectByID(ctx, id)
+		if err != nil || project == nil {
+			return nil, err
+		}
+		return &project.WorkspaceID, nil
+	case workspaceResourceEnvironment:
+		env, err := s.store.GetEnvironmentByID(ctx, id)
+		if err != nil || env == nil {
+			return nil, err
+		}
+		return &env.WorkspaceID, nil
+	case workspaceResourceInstance:
+		instance, err := s.store.GetInstanceByID(ctx, id)
+		if err != nil || instance == nil {
+			return nil, err
+		}
+		return &instance.WorkspaceID, nil
+	case workspaceResourceDatabase:
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id, IncludeAllDatabase: true})
+		if err != nil || database == nil || database.Instance == nil {
+			return nil, err
+		}
+		return &database.Instance.WorkspaceID, nil
+	case workspaceResourceIssue:
+		issue, err := s.store.GetIssueByID(ctx, id)
+		if err != nil || issue == nil || issue.Project == nil {
+			return nil, err
+		}
+		if issue.ProjectID == api.DefaultProjectID {
+			return nil, nil
+		}
+		return &issue.Project.WorkspaceID, nil
+	case workspaceResourceMember:
+		member, err := s.store.GetMemberByID(ctx, id)
+		if err != nil || member == nil {
+			return nil, err
+		}
+		return &member.WorkspaceID, nil
+	case workspaceResourceDatabaseTemplate:
+		template, err := s.store.GetDatabaseTemplateByID(ctx, id)
+		if err != nil || template == nil {
+			return nil, err
+		}
+		return &template.WorkspaceID, nil
+	case workspaceResourceIssueView:
+		view, err := s.store.GetIssueViewByID(ctx, id)
+		if err != nil || view == nil {
+			return nil, err
+		}
+		return &view.WorkspaceID, nil
+	case workspaceResourcePipeline:
+		issue, err := s.store.GetIssueByPipelineID(ctx, id)
+		if err != nil || issue == nil {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceIssue, issue.ID)
+	case workspaceResourceStage:
+		stageList, err := s.store.FindStage(ctx, &api.StageFind{ID: &id})
+		if err != nil || len(stageList) == 0 {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceEnvironment, stageList[0].EnvironmentID)
+	case workspaceResourceTask:
+		task, err := s.store.GetTaskByID(ctx, id)
+		if err != nil || task == nil {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceInstance, task.InstanceID)
+	case workspaceResourceSheet:
+		// The sheet belongs to the workspace of its creator, the sheets in the default project included.
+		sheet, err := s.store.GetSheet(ctx, &api.SheetFind{ID: &id}, api.SystemBotID)
+		if err != nil || sheet == nil {
+			return nil, err
+		}
+		member, err := s.store.GetMemberByPrincipalID(ctx, sheet.CreatorID)
+		if err != nil || member == nil {
+			return nil, err
+		}
+		return &member.WorkspaceID, nil
+	case workspaceResourceDataDiff:
+		dataDiff := s.DataDiffManager.Get(id)
+		if dataDiff == nil {
+			return nil, nil
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceDatabase, dataDiff.SourceDatabaseID)
+	case workspaceResourceRecurringTask:
+		recurringTask, err := s.store.GetRecurringTaskByID(ctx, id)
+		if err != nil || recurringTask == nil {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceDatabase, recurringTask.DatabaseID)
+	case workspaceResourceActivity:
+		activity, err := s.store.GetActivityByID(ctx, id)
+		if err != nil || activity == nil {
+			return nil, err
+		}
+		containerType, ok := getActivityContainerType(activity.Type)
+		if !ok {
+			return nil, nil
+		}
+		return s.getResourceWorkspaceID(ctx, containerType, activity.ContainerID)
+	case workspaceResourcePrincipal:
+		// The principal belongs to the workspace of its member, the system bot has no member and is shared.
+		member, err := s.store.GetMemberByPrincipalID(ctx, id)
+		if err != nil || member == nil {
+			return nil, err
+		}
+		return &member.WorkspaceID, nil
+	case workspaceResourceVCS:
+		vcs, err := s.store.GetVCSByID(ctx, id)
+		if err != nil || vcs == nil {
+			return nil, err
+		}
+		return &vcs.WorkspaceID, nil
+	case workspaceResourceLabel:
+		// The label keys are shared by all workspaces, so only the default workspace can change them.
+		workspaceID := api.DefaultWorkspaceID
+		return &workspaceID, nil
+	case workspaceResourceInstanceUser:
+		instanceUser, err := s.store.GetInstanceUser(ctx, &api.InstanceUserFind{ID: &id})
+		if err != nil || instanceUser == nil {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceInstance, instanceUser.InstanceID)
+	case workspaceResourceManagedGrant:
+		grantList, err := s.store.FindManagedGrant(ctx, &api.ManagedGrantFind{ID: &id})
+		if err != nil || len(grantList) == 0 {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceDatabase, grantList[0].DatabaseID)
+	case workspaceResourceQueryGrant:
+		grantList, err := s.store.FindDBQueryGrant(ctx, &api.DBQueryGrantFind{ID: &id})
+		if err != nil || len(grantList) == 0 {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceDatabase, grantList[0].DatabaseID)
+	case workspaceResourceTaskCheckRun:
+		taskCheckRunList, err := s.store.FindTaskCheckRun(ctx, &api.TaskCheckRunFind{ID: &id})
+		if err != nil || len(taskCheckRunList) == 0 {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceTask, taskCheckRunList[0].TaskID)
+	case workspaceResourceTaskRun:
+		taskRun, err := s.store.GetTaskRun(ctx, &api.TaskRunFind{ID: &id})
+		if err != nil || taskRun == nil {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceTask, taskRun.TaskID)
+	case workspaceResourceTaskRunArtifact:
+		artifact, err := s.store.GetTaskRunArtifactByID(ctx, id)
+		if err != nil || artifact == nil {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceTask, artifact.TaskID)
+	case workspaceResourceStatementComment:
+		comment, err := s.store.GetStatementComment(ctx, &api.StatementCommentFind{ID: &id})
+		if err != nil || comment == nil {
+			return nil, err
+		}
+		return s.getResourceWorkspaceID(ctx, workspaceResourceIssue, comment.IssueID)
+	}
+	return nil, nil
+}
@@ -0,0 +1,508 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// maxWorkspaceConfigSize is the max size of the workspace configuration bundle.
+const maxWorkspaceConfigSize = 4 << 20
+
+func (s *Server) registerWorkspaceConfigRoutes(g *echo.Group) {
+	// Exports the environments with their policies and the projects of the workspace as a YAML bundle.
+	g.GET("/workspace/config", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		config, err := s.exportWorkspaceConfig(ctx, workspaceID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export workspace config").SetInternal(err)
+		}
+		b, err := yaml.Marshal(config)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal workspace config").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="bytebase-workspace.yaml"`)
+		return c.Blob(http.StatusOK, "application/x-yaml", b)
+	})
+
+	// Applies the YAML bundle to the workspace, which changes nothing if the workspace already matches it.
+	// The whole bundle is validated before any change, and nothing is changed with ?validateOnly=true.
+	g.POST("/workspace/config", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		workspaceID := c.Get(getWorkspaceIDContextKey()).(int)
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWorkspaceConfigSize+1))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read workspace config").SetInternal(err)
+		}
+		if len(body) > maxWorkspaceConfigSize {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Workspace config exceeds %d bytes", maxWorkspaceConfigSize))
+		}
+		config, err := parseWorkspaceConfig(body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid workspace config: %v", err))
+		}
+
+		validateOnly := c.QueryParam("validateOnly") == "true"
+		changeList, err := s.applyWorkspaceConfig(ctx, workspaceID, principalID, config, true /* validateOnly */)
+		if err == nil && !validateOnly {
+			changeList, err = s.applyWorkspaceConfig(ctx, workspaceID, principalID, config, false /* validateOnly */)
+		}
+		if err != nil {
+			var featureErr *api.FeatureError
+			if errors.As(err, &featureErr) {
+				return featureHTTPError(err)
+			}
+			if common.ErrorCode(err) == common.Invalid {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid workspace config: %v", err))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to apply workspace config").SetInternal(err)
+		}
+
+		if changeList == nil {
+			changeList = []string{}
+		}
+		return c.JSON(http.StatusOK, &api.WorkspaceConfigApplyResult{
+			ChangeList:   changeList,
+			ValidateOnly: validateOnly,
+		})
+	})
+}
+
+// exportWorkspaceConfig returns the configuration of the active environments and projects in the workspace.
+// The archived policies and the default project are excluded.
+func (s *Server) exportWorkspaceConfig(ctx context.Context, workspaceID int) (*api.WorkspaceConfig, error) {
+	normalStatus := api.Normal
+	envList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{
+		WorkspaceID: &workspaceID,
+		RowStatus:   &normalStatus,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(envList, func(i, j int) bool {
+		return envList[i].Order < envList[j].Order
+	})
+
+	config := &api.WorkspaceConfig{
+		EnvironmentList: []*api.EnvironmentConfig{},
+		ProjectList:     []*api.ProjectConfig{},
+	}
+	for _, env := range envList {
+		envConfig := &api.EnvironmentConfig{Name: env.Name}
+		policyList, err := s.store.ListPolicy(ctx, &api.PolicyFind{EnvironmentID: &env.ID})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(policyList, func(i, j int) bool {
+			return policyList[i].Type < policyList[j].Type
+		})
+		for _, policy := range policyList {
+			if policy.RowStatus != api.Normal {
+				continue
+			}
+			payload, err := convertJSONPayloadToConfig(policy.Payload)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s policy payload of environment %q", policy.Type, env.Name)
+			}
+			envConfig.PolicyList = append(envConfig.PolicyList, &api.PolicyConfig{
+				Type:    policy.Type,
+				Payload: payload,
+			})
+		}
+		config.EnvironmentList = append(config.EnvironmentList, envConfig)
+	}
+
+	projectList, err := s.store.FindProject(ctx, &api.ProjectFind{
+		WorkspaceID: &workspaceID,
+		RowStatus:   &normalStatus,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(projectList, func(i, j int) bool {
+		return projectList[i].Key < projectList[j].Key
+	})
+	for _, project := range projectList {
+		if project.ID == api.DefaultProjectID {
+			continue
+		}
+		projectConfig := &api.ProjectConfig{
+			Key:                 project.Key,
+			Name:                project.Name,
+			TenantMode:          project.TenantMode,
+			DBNameTemplate:      project.DBNameTemplate,
+			SchemaMigrationType: project.SchemaMigrationType,
+		}
+		override, err := s.store.GetProjectSQLReviewOverrideByProjectID(ctx, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		if override != nil {
+			payload, err := convertJSONPayloadToConfig(override.Payload)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid SQL review override payload of project %q", project.Key)
+			}
+			projectConfig.SQLReviewOverride = payload
+		}
+		config.ProjectList = append(config.ProjectList, projectConfig)
+	}
+	return config, nil
+}
+
+// applyWorkspaceConfig applies the configuration to the workspace and returns the changes.
+// With validateOnly, the changes are returned without being applied, and the configuration conflicting with the workspace
+// is reported as common.Invalid, e.g. changing the tenant mode of an existing project.
+func (s *Server) applyWorkspaceConfig(ctx context.Context, workspaceID int, principalID int, config *api.WorkspaceConfig, validateOnly bool) ([]string, error) {
+	var changeList []string
+
+	envList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{WorkspaceID: &workspaceID})
+	if err != nil {
+		return nil, err
+	}
+	envMap := make(map[string]*api.Environment)
+	for _, env := range envList {
+		envMap[env.Name] = env
+	}
+	ordered := isEnvironmentConfigOrdered(config.EnvironmentList, envMap)
+	if !ordered {
+		changeList = append(changeList, "Reorder environments as listed")
+	}
+	for i, envConfig := range config.EnvironmentList {
+		env, ok := envMap[envConfig.Name]
+		if !ok {
+			changeList = append(changeList, fmt.Sprintf("Create environment %q", envConfig.Name))
+			if !validateOnly {
+				if env, err = s.store.CreateEnvironment(ctx, &api.EnvironmentCreate{
+					CreatorID:   principalID,
+					WorkspaceID: workspaceID,
+					Name:        envConfig.Name,
+				}); err != nil {
+					return nil, errors.Wrapf(err, "failed to create environment %q", envConfig.Name)
+				}
+			}
+		}
+
+		if env != nil {
+			envPatch := &api.EnvironmentPatch{ID: env.ID, UpdaterID: principalID}
+			if env.RowStatus != api.Normal {
+				changeList = append(changeList, fmt.Sprintf("Restore environment %q", envConfig.Name))
+				rowStatus := string(api.Normal)
+				envPatch.RowStatus = &rowStatus
+			}
+			if order := i; !ordered && env.Order != order {
+				envPatch.Order = &order
+			}
+			if !validateOnly && (envPatch.RowStatus != nil || envPatch.Order != nil) {
+				if _, err := s.store.PatchEnvironment(ctx, envPatch); err != nil {
+					return nil, errors.Wrapf(err, "failed to patch environment %q", envConfig.Name)
+				}
+			}
+		}
+
+		for _, policyConfig := range envConfig.PolicyList {
+			payload, err := convertConfigToJSONPayload(policyConfig.Payload)
+			if err != nil {
+				return nil, &common.Error{Code: common.Invalid, Err: errors.Wrapf(err, "invalid %s policy of environment %q", policyConfig.Type, envConfig.Name)}
+			}
+			rowStatus := string(api.Normal)
+			upsert := &api.PolicyUpsert{
+				UpdaterID: principalID,
+				Type:      policyConfig.Type,
+				Payload:   &payload,
+			}
+			if env != nil {
+				policy, err := s.store.GetPolicy(ctx, &api.PolicyFind{EnvironmentID: &env.ID, Type: &policyConfig.Type})
+				if err != nil {
+					return nil, err
+				}
+				if policy.RowStatus == api.Normal && equalJSONPayload(policy.Payload, payload) {
+					continue
+				}
+				if policy.RowStatus != api.Normal {
+					upsert.RowStatus = &rowStatus
+				}
+			}
+			changeList = append(changeList, fmt.Sprintf("Set %s policy of environment %q", policyConfig.Type, envConfig.Name))
+			if err := s.hasAccessToUpsertPolicy(upsert); err != nil {
+				return nil, err
+			}
+			if !validateOnly {
+				upsert.EnvironmentID = env.ID
+				if _, err := s.store.UpsertPolicy(ctx, upsert); err != nil {
+					return nil, errors.Wrapf(err, "failed to set %s policy of environment %q", policyConfig.Type, envConfig.Name)
+				}
+			}
+		}
+	}
+
+	projectList, err := s.store.FindProject(ctx, &api.ProjectFind{WorkspaceID: &workspaceID})
+	if err != nil {
+		return nil, err
+	}
+	projectMap := make(map[string]*api.Project)
+	for _, project := range projectList {
+		projectMap[project.Key] = project
+	}
+	for _, projectConfig := range config.ProjectList {
+		project, ok := projectMap[projectConfig.Key]
+		if !ok {
+			changeList = append(changeList, fmt.Sprintf("Create project %q", projectConfig.Key))
+			if projectConfig.TenantMode == api.TenantModeTenant {
+				if err := s.checkFeature(api.FeatureMultiTenancy); err != nil {
+					return nil, err
+				}
+			}
+			if !validateOnly {
+				if project, err = s.createWorkspaceConfigProject(ctx, workspaceID, principalID, projectConfig); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			if project.ID == api.DefaultProjectID {
+				return nil, &common.Error{Code: common.Invalid, Err: errors.Errorf("project %q is the default project, which can't be configured", projectConfig.Key)}
+			}
+			if project.TenantMode != projectConfig.TenantMode {
+				return nil, &common.Error{Code: common.Invalid, Err: errors.Errorf("project %q has tenant mode %s, which can't be changed to %s", projectConfig.Key, project.TenantMode, projectConfig.TenantMode)}
+			}
+			if project.DBNameTemplate != projectConfig.DBNameTemplate {
+				return nil, &common.Error{Code: common.Invalid, Err: errors.Errorf("project %q has database name template %q, which can't be changed to %q", projectConfig.Key, project.DBNameTemplate, projectConfig.DBNameTemplate)}
+			}
+
+			projectPatch := &api.ProjectPatch{ID: project.ID, UpdaterID: principalID}
+			if project.RowStatus != api.Normal {
+				changeList = append(changeList, fmt.Sprintf("Restore project %q", projectConfig.Key))
+				rowStatus := string(api.Normal)
+				projectPatch.RowStatus = &rowStatus
+			}
+			if project.Name != projectConfig.Name {
+				changeList = append(changeList, fmt.Sprintf("Rename project %q to %q", projectConfig.Key, projectConfig.Name))
+				projectPatch.Name = &projectConfig.Name
+			}
+			if project.SchemaMigrationType != projectConfig.SchemaMigrationType {
+				changeList = append(changeList, fmt.Sprintf("Set schema migration type of project %q to %s", projectConfig.Key, projectConfig.SchemaMigrationType))
+				schemaMigrationType := string(projectConfig.SchemaMigrationType)
+				projectPatch.SchemaMigrationType = &schemaMigrationType
+			}
+			if !validateOnly && (projectPatch.RowStatus != nil || projectPatch.Name != nil || projectPatch.SchemaMigrationType != nil) {
+				if _, err := s.store.PatchProject(ctx, projectPatch); err != nil {
+					return nil, errors.Wrapf(err, "failed to patch project %q", projectConfig.Key)
+				}
+			}
+		}
+
+		if projectConfig.SQLReviewOverride == nil {
+			continue
+		}
+		payload, err := convertConfigToJSONPayload(projectConfig.SQLReviewOverride)
+		if err != nil {
+			return nil, &common.Error{Code: common.Invalid, Err: errors.Wrapf(err, "invalid SQL review override of project %q", projectConfig.Key)}
+		}
+		if project != nil {
+			override, err := s.store.GetProjectSQLReviewOverrideByProjectID(ctx, project.ID)
+			if err != nil {
+				return nil, err
+			}
+			if override != nil && equalJSONPayload(override.Payload, payload) {
+				continue
+			}
+		}
+		changeList = append(changeList, fmt.Sprintf("Set SQL review override of project %q", projectConfig.Key))
+		if err := s.checkFeature(api.FeatureSQLReviewPolicy); err != nil {
+			return nil, err
+		}
+		if !validateOnly {
+			if _, err := s.store.UpsertProjectSQLReviewOverride(ctx, &api.ProjectSQLReviewOverrideUpsert{
+				UpdaterID: principalID,
+				ProjectID: project.ID,
+				Payload:   payload,
+			}); err != nil {
+				return nil, errors.Wrapf(err, "failed to set SQL review override of project %q", projectConfig.Key)
+			}
+		}
+	}
+	return changeList, nil
+}
+
+// isEnvironmentConfigOrdered returns true if the existing environments are ordered as listed, and the new ones are listed last.
+// The new environments are created at the end, so that the environments don't need reordering.
+func isEnvironmentConfigOrdered(envConfigList []*api.EnvironmentConfig, envMap map[string]*api.Environment) bool {
+	prevOrder := -1
+	hasNew := false
+	for _, envConfig := range envConfigList {
+		env, ok := envMap[envConfig.Name]
+		if !ok {
+			hasNew = true
+			continue
+		}
+		if hasNew || env.Order <= prevOrder {
+			return false
+		}
+		prevOrder = env.Order
+	}
+	return true
+}
+
+// createWorkspaceConfigProject creates the project of the configuration with the creator as the project owner.
+func (s *Server) createWorkspaceConfigProject(ctx context.Context, workspaceID int, principalID int, projectConfig *api.ProjectConfig) (*api.Project, error) {
+	project, err := s.store.CreateProject(ctx, &api.ProjectCreate{
+		CreatorID:           principalID,
+		WorkspaceID:         workspaceID,
+		Name:                projectConfig.Name,
+		Key:                 projectConfig.Key,
+		TenantMode:          projectConfig.TenantMode,
+		DBNameTemplate:      projectConfig.DBNameTemplate,
+		RoleProvider:        api.ProjectRoleProviderBytebase,
+		SchemaMigrationType: projectConfig.SchemaMigrationType,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create project %q", projectConfig.Key)
+	}
+	if _, err := s.store.CreateProjectMember(ctx, &api.ProjectMemberCreate{
+		CreatorID:   principalID,
+		ProjectID:   project.ID,
+		Role:        common.ProjectOwner,
+		PrincipalID: principalID,
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to add owner after creating project %q", projectConfig.Key)
+	}
+	return project, nil
+}
+
+// parseWorkspaceConfig parses and validates the YAML bundle, the unknown fields are rejected to catch the typos.
+// The project keys are upper-cased and the defaults are filled as creating the projects.
+func parseWorkspaceConfig(b []byte) (*api.WorkspaceConfig, error) {
+	config := &api.WorkspaceConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil {
+		if err == io.EOF {
+			return nil, errors.Errorf("empty workspace config")
+		}
+		return nil, errors.Wrapf(err, "failed to unmarshal workspace config")
+	}
+
+	envNameSet := make(map[string]bool)
+	for _, envConfig := range config.EnvironmentList {
+		if envConfig == nil || envConfig.Name == "" {
+			return nil, errors.Errorf("environment name must not be empty")
+		}
+		if envNameSet[envConfig.Name] {
+			return nil, errors.Errorf("duplicate environment %q", envConfig.Name)
+		}
+		envNameSet[envConfig.Name] = true
+
+		policyTypeSet := make(map[api.PolicyType]bool)
+		for _, policyConfig := range envConfig.PolicyList {
+			if policyConfig == nil || policyConfig.Payload == nil {
+				return nil, errors.Errorf("policy of environment %q must have type and payload", envConfig.Name)
+			}
+			if policyTypeSet[policyConfig.Type] {
+				return nil, errors.Errorf("duplicate %s policy of environment %q", policyConfig.Type, envConfig.Name)
+			}
+			policyTypeSet[policyConfig.Type] = true
+			payload, err := convertConfigToJSONPayload(policyConfig.Payload)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s policy of environment %q", policyConfig.Type, envConfig.Name)
+			}
+			if err := api.ValidatePolicy(policyConfig.Type, payload); err != nil {
+				return nil, errors.Wrapf(err, "invalid %s policy of environment %q", policyConfig.Type, envConfig.Name)
+			}
+		}
+	}
+
+	projectKeySet := make(map[string]bool)
+	for _, projectConfig := range config.ProjectList {
+		if projectConfig == nil || projectConfig.Key == "" {
+			return nil, errors.Errorf("project key must not be empty")
+		}
+		projectConfig.Key = strings.ToUpper(projectConfig.Key)
+		if projectKeySet[projectConfig.Key] {
+			return nil, errors.Errorf("duplicate project %q", projectConfig.Key)
+		}
+		projectKeySet[projectConfig.Key] = true
+		if projectConfig.Name == "" {
+			return nil, errors.Errorf("project %q name must not be empty", projectConfig.Key)
+		}
+
+		if projectConfig.TenantMode == "" {
+			projectConfig.TenantMode = api.TenantModeDisabled
+		}
+		if projectConfig.TenantMode != api.TenantModeDisabled && projectConfig.TenantMode != api.TenantModeTenant {
+			return nil, errors.Errorf("project %q has invalid tenant mode %q", projectConfig.Key, projectConfig.TenantMode)
+		}
+		if err := api.ValidateProjectDBNameTemplate(projectConfig.DBNameTemplate); err != nil {
+			return nil, errors.Wrapf(err, "project %q has invalid database name template", projectConfig.Key)
+		}
+		if projectConfig.TenantMode != api.TenantModeTenant && projectConfig.DBNameTemplate != "" {
+			return nil, errors.Errorf("project %q can only set database name template in tenant mode", projectConfig.Key)
+		}
+
+		if projectConfig.SchemaMigrationType == "" {
+			projectConfig.SchemaMigrationType = api.ProjectSchemaMigrationTypeDDL
+		}
+		if projectConfig.SchemaMigrationType != api.ProjectSchemaMigrationTypeDDL && projectConfig.SchemaMigrationType != api.ProjectSchemaMigrationTypeSDL {
+			return nil, errors.Errorf("project %q has invalid schema migration type %q", projectConfig.Key, projectConfig.SchemaMigrationType)
+		}
+
+		if projectConfig.SQLReviewOverride != nil {
+			payload, err := convertConfigToJSONPayload(projectConfig.SQLReviewOverride)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid SQL review override of project %q", projectConfig.Key)
+			}
+			if _, err := api.UnmarshalProjectSQLReviewOverridePayload(payload); err != nil {
+				return nil, errors.Wrapf(err, "invalid SQL review override of project %q", projectConfig.Key)
+			}
+		}
+	}
+	return config, nil
+}
+
+// convertJSONPayloadToConfig converts the JSON object payload to the mapping in the workspace config.
+func convertJSONPayloadToConfig(payload string) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if payload == "" {
+		return m, nil
+	}
+	if err := json.Unmarshal([]byte(payload), &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// convertConfigToJSONPayload converts the mapping in the workspace config to the JSON payload.
+func convertConfigToJSONPayload(m map[string]interface{}) (string, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// equalJSONPayload returns true if the JSON payloads are semantically equal, regardless of the key order and the spaces.
+func equalJSONPayload(a, b string) bool {
+	var va, vb interface{}
+	if err := json.Unmarshal([]byte(a), &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(b), &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestParseWorkspaceConfig(t *testing.T) {
+	tests := []struct {
+		config  string
+		wantErr bool
+	}{
+		{
+			config: `
+environments:
+  - name: Staging
+    policies:
+      - type: bb.policy.backup-plan
+        payload:
+          schedule: DAILY
+          retentionPeriodTs: 604800
+  - name: Prod
+projects:
+  - key: shop
+    name: Shop
+    sqlReviewOverride:
+      ruleList: []
+`,
+		},
+		{
+			config:  ``,
+			wantErr: true,
+		},
+		// Unknown fields are rejected.
+		{
+			config:  "environments:\n  - name: Prod\n    policy: []\n",
+			wantErr: true,
+		},
+		{
+			config:  "environments:\n  - name: Prod\n  - name: Prod\n",
+			wantErr: true,
+		},
+		{
+			config:  "environments:\n  - name: Prod\n    policies:\n      - type: bb.policy.unknown\n        payload: {}\n",
+			wantErr: true,
+		},
+		{
+			config:  "environments:\n  - name: Prod\n    policies:\n      - type: bb.policy.backup-plan\n        payload:\n          schedule: HOURLY\n",
+			wantErr: true,
+		},
+		{
+			config:  "environments:\n  - name: Prod\n    policies:\n      - type: bb.policy.backup-plan\n",
+			wantErr: true,
+		},
+		// Project keys are case-insensitive.
+		{
+			config:  "projects:\n  - key: shop\n    name: Shop\n  - key: SHOP\n    name: Shop\n",
+			wantErr: true,
+		},
+		{
+			config:  "projects:\n  - key: shop\n    name: Shop\n    dbNameTemplate: \"{{DB_NAME}}_{{TENANT}}\"\n",
+			wantErr: true,
+		},
+		{
+			config:  "projects:\n  - key: shop\n    name: Shop\n    schemaMigrationType: DML\n",
+			wantErr: true,
+		},
+		{
+			config:  "projects:\n  - key: shop\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := parseWorkspaceConfig([]byte(test.config))
+		if test.wantErr {
+			require.Error(t, err, test.config)
+		} else {
+			require.NoError(t, err, test.config)
+		}
+	}
+}
+
+func TestParseWorkspaceConfigDefault(t *testing.T) {
+	a := require.New(t)
+	config, err := parseWorkspaceConfig([]byte("projects:\n  - key: shop\n    name: Shop\n"))
+	a.NoError(err)
+	a.Equal([]*api.ProjectConfig{
+		{
+			Key:                 "SHOP",
+			Name:                "Shop",
+			TenantMode:          api.TenantModeDisabled,
+			SchemaMigrationType: api.ProjectSchemaMigrationTypeDDL,
+		},
+	}, config.ProjectList)
+}
+
+func TestWorkspaceConfigPayloadRoundTrip(t *testing.T) {
+	a := require.New(t)
+	payload := `{"retentionPeriodTs":604800,"schedule":"DAILY"}`
+	m, err := convertJSONPayloadToConfig(payload)
+	a.NoError(err)
+
+	// The payload survives the bundle export and import.
+	b, err := yaml.Marshal(&api.PolicyConfig{Type: api.PolicyTypeBackupPlan, Payload: m})
+	a.NoError(err)
+	policyConfig := &api.PolicyConfig{}
+	a.NoError(yaml.Unmarshal(b, policyConfig))
+	got, err := convertConfigToJSONPayload(policyConfig.Payload)
+	a.NoError(err)
+	a.True(equalJSONPayload(payload, got), got)
+
+	a.True(equalJSONPayload(`{"a": 1, "b": [1, 2]}`, `{"b":[1,2],"a":1}`))
+	a.False(equalJSONPayload(`{"a": 1}`, `{"a": 2}`))
+	a.False(equalJSONPayload(``, `{}`))
+}
+
+func TestIsEnvironmentConfigOrdered(t *testing.T) {
+	envMap := map[string]*api.Environment{
+		"Test":    {Name: "Test", Order: 0},
+		"Staging": {Name: "Staging", Order: 2},
+		"Prod":    {Name: "Prod", Order: 3},
+	}
+	tests := []struct {
+		nameList []string
+		want     bool
+	}{
+		{nameList: []string{"Test", "Staging", "Prod"}, want: true},
+		// The environments missing from the bundle don't matter.
+		{nameList: []string{"Test", "Prod"}, want: true},
+		// The new environments are created at the end.
+		{nameList: []string{"Test", "Staging", "Prod", "DR"}, want: true},
+		{nameList: []string{"Test", "DR", "Prod"}, want: false},
+		{nameList: []string{"Prod", "Staging"}, want: false},
+	}
+
+	for _, test := range tests {
+		var envConfigList []*api.EnvironmentConfig
+		for _, name := range test.nameList {
+			envConfigList = append(envConfigList, &api.EnvironmentConfig{Name: name})
+		}
+		require.Equal(t, test.want, isEnvironmentConfigOrdered(envConfigList, envMap), test.nameList)
+	}
+}
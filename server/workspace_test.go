@@ -0,0 +1,220 @@
+package server
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetWorkspaceResourceList(t *testing.T) {
+	tests := []struct {
+		routePath    string
+		paramNames   []string
+		paramValues  []string
+		resourceList []workspaceResource
+	}{
+		{"/api/project/:projectID/webhook/:webhookID", []string{"projectID", "webhookID"}, []string{"3", "5"}, []workspaceResource{{workspaceResourceProject, "3"}}},
+		{"/api/project/:id/deployment", []string{"id"}, []string{"4"}, []workspaceResource{{workspaceResourceProject, "4"}}},
+		{"/api/environment/:id", []string{"id"}, []string{"2"}, []workspaceResource{{workspaceResourceEnvironment, "2"}}},
+		{"/api/policy/environment/:environmentID", []string{"environmentID"}, []string{"2"}, []workspaceResource{{workspaceResourceEnvironment, "2"}}},
+		{
+			"/api/instance/:instanceID/user/:userID",
+			[]string{"instanceID", "userID"},
+			[]string{"7", "8"},
+			[]workspaceResource{{workspaceResourceInstance, "7"}, {workspaceResourceInstanceUser, "8"}},
+		},
+		{"/api/database/:id/table/:tableName", []string{"id", "tableName"}, []string{"9", "t1"}, []workspaceResource{{workspaceResourceDatabase, "9"}}},
+		{"/api/issue/:issueID/status", []string{"issueID"}, []string{"11"}, []workspaceResource{{workspaceResourceIssue, "11"}}},
+		{"/api/member/:id", []string{"id"}, []string{"6"}, []workspaceResource{{workspaceResourceMember, "6"}}},
+		{"/api/database-template/:databaseTemplateID", []string{"databaseTemplateID"}, []string{"12"}, []workspaceResource{{workspaceResourceDatabaseTemplate, "12"}}},
+		{"/api/issue-view/:issueViewID", []string{"issueViewID"}, []string{"13"}, []workspaceResource{{workspaceResourceIssueView, "13"}}},
+		{
+			"/api/pipeline/:pipelineID/task/:taskID/status",
+			[]string{"pipelineID", "taskID"},
+			[]string{"14", "15"},
+			[]workspaceResource{{workspaceResourcePipeline, "14"}, {workspaceResourceTask, "15"}},
+		},
+		{
+			"/api/pipeline/:pipelineID/stage/:stageID",
+			[]string{"pipelineID", "stageID"},
+			[]string{"14", "16"},
+			[]workspaceResource{{workspaceResourcePipeline, "14"}, {workspaceResourceStage, "16"}},
+		},
+		{"/api/sheet/:id", []string{"id"}, []string{"17"}, []workspaceResource{{workspaceResourceSheet, "17"}}},
+		{"/api/sheet/:sheetID/organizer", []string{"sheetID"}, []string{"17"}, []workspaceResource{{workspaceResourceSheet, "17"}}},
+		{"/api/data-diff/:dataDiffID", []string{"dataDiffID"}, []string{"18"}, []workspaceResource{{workspaceResourceDataDiff, "18"}}},
+		{"/api/recurring-task/:recurringTaskID", []string{"recurringTaskID"}, []string{"19"}, []workspaceResource{{workspaceResourceRecurringTask, "19"}}},
+		{"/api/activity/:activityID", []string{"activityID"}, []string{"20"}, []workspaceResource{{workspaceResourceActivity, "20"}}},
+		{"/api/label/:id", []string{"id"}, []string{"1"}, []workspaceResource{{workspaceResourceLabel, "1"}}},
+		{"/api/principal/:principalID", []string{"principalID"}, []string{"21"}, []workspaceResource{{workspaceResourcePrincipal, "21"}}},
+		{"/api/inbox/user/:userID", []string{"userID"}, []string{"21"}, []workspaceResource{{workspaceResourcePrincipal, "21"}}},
+		{"/api/vcs/:vcsID/repository", []string{"vcsID"}, []string{"22"}, []workspaceResource{{workspaceResourceVCS, "22"}}},
+		{
+			"/api/database/:id/managed-grant/:grantID",
+			[]string{"id", "grantID"},
+			[]string{"9", "23"},
+			[]workspaceResource{{workspaceResourceDatabase, "9"}, {workspaceResourceManagedGrant, "23"}},
+		},
+		{
+			"/api/database/:id/query-grant/:grantID",
+			[]string{"id", "grantID"},
+			[]string{"9", "24"},
+			[]workspaceResource{{workspaceResourceDatabase, "9"}, {workspaceResourceQueryGrant, "24"}},
+		},
+		// The migration history ID is local to the instance, which is checked instead.
+		{
+			"/api/database/:id/change-history/:historyID",
+			[]string{"id", "historyID"},
+			[]string{"9", "25"},
+			[]workspaceResource{{workspaceResourceDatabase, "9"}},
+		},
+		{
+			"/api/pipeline/:pipelineID/task/:taskID/check/:taskCheckRunID/suppress",
+			[]string{"pipelineID", "taskID", "taskCheckRunID"},
+			[]string{"14", "15", "26"},
+			[]workspaceResource{{workspaceResourcePipeline, "14"}, {workspaceResourceTask, "15"}, {workspaceResourceTaskCheckRun, "26"}},
+		},
+		{
+			"/api/pipeline/:pipelineID/task/:taskID/run/:taskRunID/progress",
+			[]string{"pipelineID", "taskID", "taskRunID"},
+			[]string{"14", "15", "27"},
+			[]workspaceResource{{workspaceResourcePipeline, "14"}, {workspaceResourceTask, "15"}, {workspaceResourceTaskRun, "27"}},
+		},
+		{
+			"/api/pipeline/:pipelineID/task/:taskID/artifact/:artifactID/download",
+			[]string{"pipelineID", "taskID", "artifactID"},
+			[]string{"14", "15", "28"},
+			[]workspaceResource{{workspaceResourcePipeline, "14"}, {workspaceResourceTask, "15"}, {workspaceResourceTaskRunArtifact, "28"}},
+		},
+		{
+			"/api/issue/:issueID/statement-comment/:commentID",
+			[]string{"issueID", "commentID"},
+			[]string{"11", "29"},
+			[]workspaceResource{{workspaceResourceIssue, "11"}, {workspaceResourceStatementComment, "29"}},
+		},
+		{"/api/project", nil, nil, nil},
+	}
+
+	for _, test := range tests {
+		resourceList := getWorkspaceResourceList(test.routePath, test.paramNames, test.paramValues)
+		assert.Equal(t, test.resourceList, resourceList, test.routePath)
+	}
+}
+
+func TestFindOtherWorkspaceResource(t *testing.T) {
+	const workspaceA, workspaceB = 101, 102
+	// The workspaces of the resources, the missing ones are not found or shared by all workspaces.
+	workspaceMap := map[workspaceResource]int{
+		{workspaceResourcePipeline, "1"}: workspaceA,
+		{workspaceResourceTask, "2"}:     workspaceA,
+		{workspaceResourcePipeline, "3"}: workspaceB,
+		{workspaceResourceTask, "4"}:     workspaceB,
+		{workspaceResourceSheet, "5"}:    workspaceB,
+		{workspaceResourceActivity, "6"}: workspaceB,
+	}
+	getWorkspaceID := func(resourceType workspaceResourceType, id int) (*int, error) {
+		for resource, workspaceID := range workspaceMap {
+			if resource.resourceType == resourceType && resource.id == strconv.Itoa(id) {
+				workspaceID := workspaceID
+				return &workspaceID, nil
+			}
+		}
+		return nil, nil
+	}
+
+	tests := []struct {
+		resourceList []workspaceResource
+		want         *workspaceResource
+	}{
+		{
+			resourceList: []workspaceResource{{workspaceResourcePipeline, "1"}, {workspaceResourceTask, "2"}},
+			want:         nil,
+		},
+		{
+			resourceList: []workspaceResource{{workspaceResourcePipeline, "3"}, {workspaceResourceTask, "4"}},
+			want:         &workspaceResource{workspaceResourcePipeline, "3"},
+		},
+		// The task of workspace B is addressed through the pipeline of workspace A.
+		{
+			resourceList: []workspaceResource{{workspaceResourcePipeline, "1"}, {workspaceResourceTask, "4"}},
+			want:         &workspaceResource{workspaceResourceTask, "4"},
+		},
+		{
+			resourceList: []workspaceResource{{workspaceResourceSheet, "5"}},
+			want:         &workspaceResource{workspaceResourceSheet, "5"},
+		},
+		{
+			resourceList: []workspaceResource{{workspaceResourceActivity, "6"}},
+			want:         &workspaceResource{workspaceResourceActivity, "6"},
+		},
+		// The resources not found and the malformed IDs are left to the handlers.
+		{
+			resourceList: []workspaceResource{{workspaceResourceSheet, "99"}, {workspaceResourceTask, "abc"}},
+			want:         nil,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		resource, err := findOtherWorkspaceResource(test.resourceList, workspaceA, getWorkspaceID)
+		a.NoError(err)
+		a.Equal(test.want, resource, "%v", test.resourceList)
+	}
+
+	_, err := findOtherWorkspaceResource([]workspaceResource{{workspaceResourceTask, "2"}}, workspaceA, func(workspaceResourceType, int) (*int, error) {
+		return nil, errors.New("connection refused")
+	})
+	a.Error(err)
+}
+
+func TestGetActivityContainerType(t *testing.T) {
+	tests := []struct {
+		activityType api.ActivityType
+		want         workspaceResourceType
+		wantOK       bool
+	}{
+		{api.ActivityIssueCommentCreate, workspaceResourceIssue, true},
+		{api.ActivityPipelineTaskStatusUpdate, workspaceResourceIssue, true},
+		{api.ActivityProjectMemberCreate, workspaceResourceProject, true},
+		{api.ActivityDatabaseAnomalyCreate, workspaceResourceProject, true},
+		{api.ActivityMemberRoleUpdate, workspaceResourceMember, true},
+		{api.ActivitySQLEditorQuery, workspaceResourceInstance, true},
+		{api.ActivityInstanceEnvironmentUpdate, workspaceResourceInstance, true},
+		{api.ActivitySettingMaintenanceUpdate, "", false},
+	}
+
+	for _, test := range tests {
+		resourceType, ok := getActivityContainerType(test.activityType)
+		assert.Equal(t, test.want, resourceType, test.activityType)
+		assert.Equal(t, test.wantOK, ok, test.activityType)
+	}
+}
+
+func TestGetPipelineWorkspaceResourceList(t *testing.T) {
+	databaseID := 4
+	pipelineCreate := &api.PipelineCreate{
+		StageList: []api.StageCreate{
+			{
+				EnvironmentID: 2,
+				TaskList: []api.TaskCreate{
+					// The create database task has no database yet.
+					{InstanceID: 3},
+					{InstanceID: 3, DatabaseID: &databaseID},
+				},
+			},
+		},
+	}
+	want := []workspaceResource{
+		{workspaceResourceProject, "1"},
+		{workspaceResourceEnvironment, "2"},
+		{workspaceResourceInstance, "3"},
+		{workspaceResourceInstance, "3"},
+		{workspaceResourceDatabase, "4"},
+	}
+	assert.Equal(t, want, getPipelineWorkspaceResourceList(1, pipelineCreate))
+}
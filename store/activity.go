@@ -275,12 +275,33 @@ func findActivityImpl(ctx context.Context, tx *sql.Tx, find *api.ActivityFind) (
 	if v := find.CreatorID; v != nil {
 		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.WorkspaceID; v != nil {
+		// The container of the activity depends on the type, the issues and the projects of the default project are shared,
+		// and so are the other activities such as the settings.
+		where, args = append(where, fmt.Sprintf(`(
+			((type LIKE 'bb.issue.%%' OR type LIKE 'bb.pipeline.%%') AND container_id IN (
+				SELECT issue.id FROM issue, project WHERE issue.project_id = project.id AND (project.workspace_id = $%[1]d OR project.id = %[2]d)))
+			OR ((type LIKE 'bb.project.%%' OR type LIKE 'bb.database.%%') AND container_id IN (
+				SELECT id FROM project WHERE workspace_id = $%[1]d OR id = %[2]d))
+			OR (type LIKE 'bb.member.%%' AND container_id IN (SELECT id FROM member WHERE workspace_id = $%[1]d))
+			OR ((type LIKE 'bb.sql-editor.%%' OR type LIKE 'bb.instance.%%') AND container_id IN (SELECT id FROM instance WHERE workspace_id = $%[1]d))
+			OR (type NOT LIKE 'bb.issue.%%' AND type NOT LIKE 'bb.pipeline.%%' AND type NOT LIKE 'bb.project.%%' AND type NOT LIKE 'bb.database.%%'
+				AND type NOT LIKE 'bb.member.%%' AND type NOT LIKE 'bb.sql-editor.%%' AND type NOT LIKE 'bb.instance.%%')
+		)`, len(args)+1, api.DefaultProjectID)), append(args, *v)
+	}
 	if v := find.TypePrefix; v != nil {
 		where, args = append(where, fmt.Sprintf("type LIKE $%d", len(args)+1)), append(args, fmt.Sprintf("%s%%", *v))
 	}
 	if v := find.Level; v != nil {
 		where, args = append(where, fmt.Sprintf("level = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.After; v != nil {
+		op := "<"
+		if find.Order != nil && *find.Order == api.ASC {
+			op = ">"
+		}
+		where, args = append(where, fmt.Sprintf("(created_ts, id) %s ($%d, $%d)", op, len(args)+1, len(args)+2)), append(args, v.Ts, v.ID)
+	}
 
 	var query = `
 		SELECT
@@ -297,7 +318,9 @@ func findActivityImpl(ctx context.Context, tx *sql.Tx, find *api.ActivityFind) (
 		FROM activity
 		WHERE ` + strings.Join(where, " AND ")
 	if v := find.Order; v != nil {
-		query += fmt.Sprintf(" ORDER BY created_ts %s", *v)
+		query += fmt.Sprintf(" ORDER BY created_ts %s, id %s", *v, *v)
+	} else if find.After != nil {
+		query += " ORDER BY created_ts DESC, id DESC"
 	}
 	if v := find.Limit; v != nil {
 		query += fmt.Sprintf(" LIMIT %d", *v)
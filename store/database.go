@@ -20,6 +20,7 @@ type databaseRaw struct {
 	ID int
 
 	// Standard fields
+	RowStatus api.RowStatus
 	CreatorID int
 	CreatedTs int64
 	UpdaterID int
@@ -29,6 +30,7 @@ type databaseRaw struct {
 	ProjectID      int
 	InstanceID     int
 	SourceBackupID int
+	OwnerID        int
 
 	// Domain specific fields
 	Name                 string
@@ -37,6 +39,8 @@ type databaseRaw struct {
 	SchemaVersion        string
 	SyncStatus           api.SyncStatus
 	LastSuccessfulSyncTs int64
+	OnCall               string
+	ConnectionOption     string
 }
 
 // toDatabase creates an instance of Database based on the databaseRaw.
@@ -46,6 +50,7 @@ func (raw *databaseRaw) toDatabase() *api.Database {
 		ID: raw.ID,
 
 		// Standard fields
+		RowStatus: raw.RowStatus,
 		CreatorID: raw.CreatorID,
 		CreatedTs: raw.CreatedTs,
 		UpdaterID: raw.UpdaterID,
@@ -55,6 +60,7 @@ func (raw *databaseRaw) toDatabase() *api.Database {
 		ProjectID:      raw.ProjectID,
 		InstanceID:     raw.InstanceID,
 		SourceBackupID: raw.SourceBackupID,
+		OwnerID:        raw.OwnerID,
 
 		// Domain specific fields
 		Name:                 raw.Name,
@@ -63,6 +69,8 @@ func (raw *databaseRaw) toDatabase() *api.Database {
 		SchemaVersion:        raw.SchemaVersion,
 		SyncStatus:           raw.SyncStatus,
 		LastSuccessfulSyncTs: raw.LastSuccessfulSyncTs,
+		OnCall:               raw.OnCall,
+		ConnectionOption:     raw.ConnectionOption,
 	}
 }
 
@@ -125,6 +133,19 @@ func (s *Store) GetDatabase(ctx context.Context, find *api.DatabaseFind) (*api.D
 	return database, nil
 }
 
+// GetDatabaseConnectionOption gets the connection option of the database on the instance by name.
+// Returns nil if the database is not found, e.g. it's being created.
+func (s *Store) GetDatabaseConnectionOption(ctx context.Context, instanceID int, databaseName string) (*api.DatabaseConnectionOption, error) {
+	databaseRaw, err := s.getDatabaseRaw(ctx, &api.DatabaseFind{InstanceID: &instanceID, Name: &databaseName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get database %q of instance ID %d", databaseName, instanceID)
+	}
+	if databaseRaw == nil {
+		return nil, nil
+	}
+	return api.UnmarshalDatabaseConnectionOption(databaseRaw.ConnectionOption)
+}
+
 // PatchDatabase patches an instance of Database.
 func (s *Store) PatchDatabase(ctx context.Context, patch *api.DatabasePatch) (*api.Database, error) {
 	databaseRaw, err := s.patchDatabaseRaw(ctx, patch)
@@ -138,6 +159,46 @@ func (s *Store) PatchDatabase(ctx context.Context, patch *api.DatabasePatch) (*a
 	return database, nil
 }
 
+// ArchiveDatabaseList archives the databases in one transaction, so that either all or none of them are archived.
+func (s *Store) ArchiveDatabaseList(ctx context.Context, idList []int, updaterID int) ([]*api.Database, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rowStatus := string(api.Archived)
+	var databaseRawList []*databaseRaw
+	for _, id := range idList {
+		databaseRaw, err := s.patchDatabaseImpl(ctx, tx.PTx, &api.DatabasePatch{
+			ID:        id,
+			UpdaterID: updaterID,
+			RowStatus: &rowStatus,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to archive database ID %d", id)
+		}
+		databaseRawList = append(databaseRawList, databaseRaw)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	var databaseList []*api.Database
+	for _, databaseRaw := range databaseRawList {
+		if err := s.cache.UpsertCache(api.DatabaseCache, databaseRaw.ID, databaseRaw); err != nil {
+			return nil, err
+		}
+		database, err := s.composeDatabase(ctx, databaseRaw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose Database with databaseRaw[%+v]", databaseRaw)
+		}
+		databaseList = append(databaseList, database)
+	}
+	return databaseList, nil
+}
+
 // CountDatabaseGroupByBackupScheduleAndEnabled counts database, group by backup schedule and enabled.
 func (s *Store) CountDatabaseGroupByBackupScheduleAndEnabled(ctx context.Context) ([]*metric.DatabaseCountMetric, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -241,6 +302,14 @@ func (s *Store) composeDatabase(ctx context.Context, raw *databaseRaw) (*api.Dat
 		db.SourceBackup = sourceBackup
 	}
 
+	if db.OwnerID != api.UnknownID {
+		owner, err := s.GetPrincipalByID(ctx, db.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+		db.Owner = owner
+	}
+
 	// For now, only wildcard(*) database has data sources and we disallow it to be returned to the client.
 	// So we set this value to an empty array until we need to develop a data source for a non-wildcard database.
 	db.DataSourceList = []*api.DataSource{}
@@ -451,6 +520,7 @@ func (*Store) createDatabaseImpl(ctx context.Context, tx *sql.Tx, create *api.Da
 		VALUES ($1, $2, $3, $4, $5, $6, $7, 'OK', EXTRACT(epoch from NOW()), $8)
 		RETURNING
 			id,
+			row_status,
 			creator_id,
 			created_ts,
 			updater_id,
@@ -462,7 +532,9 @@ func (*Store) createDatabaseImpl(ctx context.Context, tx *sql.Tx, create *api.Da
 			"collation",
 			sync_status,
 			last_successful_sync_ts,
-			schema_version
+			schema_version,
+			on_call,
+			connection_option
 	`
 	var databaseRaw databaseRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -476,6 +548,7 @@ func (*Store) createDatabaseImpl(ctx context.Context, tx *sql.Tx, create *api.Da
 		create.SchemaVersion,
 	).Scan(
 		&databaseRaw.ID,
+		&databaseRaw.RowStatus,
 		&databaseRaw.CreatorID,
 		&databaseRaw.CreatedTs,
 		&databaseRaw.UpdaterID,
@@ -488,6 +561,8 @@ func (*Store) createDatabaseImpl(ctx context.Context, tx *sql.Tx, create *api.Da
 		&databaseRaw.SyncStatus,
 		&databaseRaw.LastSuccessfulSyncTs,
 		&databaseRaw.SchemaVersion,
+		&databaseRaw.OnCall,
+		&databaseRaw.ConnectionOption,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -509,12 +584,18 @@ func (*Store) findDatabaseImpl(ctx context.Context, tx *sql.Tx, find *api.Databa
 	if v := find.ProjectID; v != nil {
 		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id IN (SELECT id FROM instance WHERE workspace_id = $%d)", len(args)+1)), append(args, *v)
+	}
 	if v := find.Name; v != nil {
 		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
 	if v := find.SyncStatus; v != nil {
 		where, args = append(where, fmt.Sprintf("sync_status = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, *v)
+	}
 	if !find.IncludeAllDatabase {
 		where = append(where, "name != '"+api.AllDatabaseName+"'")
 	}
@@ -522,6 +603,7 @@ func (*Store) findDatabaseImpl(ctx context.Context, tx *sql.Tx, find *api.Databa
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
 			id,
+			row_status,
 			creator_id,
 			created_ts,
 			updater_id,
@@ -529,12 +611,15 @@ func (*Store) findDatabaseImpl(ctx context.Context, tx *sql.Tx, find *api.Databa
 			instance_id,
 			project_id,
 			source_backup_id,
+			owner_id,
 			name,
 			character_set,
 			"collation",
 			sync_status,
 			last_successful_sync_ts,
-			schema_version
+			schema_version,
+			on_call,
+			connection_option
 		FROM db
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -548,9 +633,10 @@ func (*Store) findDatabaseImpl(ctx context.Context, tx *sql.Tx, find *api.Databa
 	var databaseRawList []*databaseRaw
 	for rows.Next() {
 		var databaseRaw databaseRaw
-		var nullSourceBackupID sql.NullInt64
+		var nullSourceBackupID, nullOwnerID sql.NullInt64
 		if err := rows.Scan(
 			&databaseRaw.ID,
+			&databaseRaw.RowStatus,
 			&databaseRaw.CreatorID,
 			&databaseRaw.CreatedTs,
 			&databaseRaw.UpdaterID,
@@ -558,18 +644,24 @@ func (*Store) findDatabaseImpl(ctx context.Context, tx *sql.Tx, find *api.Databa
 			&databaseRaw.InstanceID,
 			&databaseRaw.ProjectID,
 			&nullSourceBackupID,
+			&nullOwnerID,
 			&databaseRaw.Name,
 			&databaseRaw.CharacterSet,
 			&databaseRaw.Collation,
 			&databaseRaw.SyncStatus,
 			&databaseRaw.LastSuccessfulSyncTs,
 			&databaseRaw.SchemaVersion,
+			&databaseRaw.OnCall,
+			&databaseRaw.ConnectionOption,
 		); err != nil {
 			return nil, FormatError(err)
 		}
 		if nullSourceBackupID.Valid {
 			databaseRaw.SourceBackupID = int(nullSourceBackupID.Int64)
 		}
+		if nullOwnerID.Valid {
+			databaseRaw.OwnerID = int(nullOwnerID.Int64)
+		}
 
 		databaseRawList = append(databaseRawList, &databaseRaw)
 	}
@@ -584,6 +676,9 @@ func (*Store) findDatabaseImpl(ctx context.Context, tx *sql.Tx, find *api.Databa
 func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.DatabasePatch) (*databaseRaw, error) {
 	// Build UPDATE clause.
 	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.RowStatus; v != nil {
+		set, args = append(set, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.RowStatus(*v))
+	}
 	if v := patch.ProjectID; v != nil {
 		set, args = append(set, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
 	}
@@ -599,11 +694,25 @@ func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.Data
 	if v := patch.LastSuccessfulSyncTs; v != nil {
 		set, args = append(set, fmt.Sprintf("last_successful_sync_ts = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.OwnerID; v != nil {
+		// Unset the owner if the owner ID is UnknownID.
+		var ownerID interface{}
+		if *v != api.UnknownID {
+			ownerID = *v
+		}
+		set, args = append(set, fmt.Sprintf("owner_id = $%d", len(args)+1)), append(args, ownerID)
+	}
+	if v := patch.OnCall; v != nil {
+		set, args = append(set, fmt.Sprintf("on_call = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.ConnectionOption; v != nil {
+		set, args = append(set, fmt.Sprintf("connection_option = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
 	var databaseRaw databaseRaw
-	var nullSourceBackupID sql.NullInt64
+	var nullSourceBackupID, nullOwnerID sql.NullInt64
 	// Execute update query with RETURNING.
 	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
 		UPDATE db
@@ -611,6 +720,7 @@ func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.Data
 		WHERE id = $%d
 		RETURNING
 			id,
+			row_status,
 			creator_id,
 			created_ts,
 			updater_id,
@@ -618,16 +728,20 @@ func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.Data
 			instance_id,
 			project_id,
 			source_backup_id,
+			owner_id,
 			name,
 			character_set,
 			"collation",
 			sync_status,
 			last_successful_sync_ts,
-			schema_version
+			schema_version,
+			on_call,
+			connection_option
 	`, len(args)),
 		args...,
 	).Scan(
 		&databaseRaw.ID,
+		&databaseRaw.RowStatus,
 		&databaseRaw.CreatorID,
 		&databaseRaw.CreatedTs,
 		&databaseRaw.UpdaterID,
@@ -635,12 +749,15 @@ func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.Data
 		&databaseRaw.InstanceID,
 		&databaseRaw.ProjectID,
 		&nullSourceBackupID,
+		&nullOwnerID,
 		&databaseRaw.Name,
 		&databaseRaw.CharacterSet,
 		&databaseRaw.Collation,
 		&databaseRaw.SyncStatus,
 		&databaseRaw.LastSuccessfulSyncTs,
 		&databaseRaw.SchemaVersion,
+		&databaseRaw.OnCall,
+		&databaseRaw.ConnectionOption,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("database ID not found: %d", patch.ID)}
@@ -650,5 +767,8 @@ func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.Data
 	if nullSourceBackupID.Valid {
 		databaseRaw.SourceBackupID = int(nullSourceBackupID.Int64)
 	}
+	if nullOwnerID.Valid {
+		databaseRaw.OwnerID = int(nullOwnerID.Int64)
+	}
 	return &databaseRaw, nil
 }
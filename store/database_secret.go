@@ -0,0 +1,217 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// UpsertDatabaseSecret creates the database secret, or updates its value and description if it already exists.
+func (s *Store) UpsertDatabaseSecret(ctx context.Context, upsert *api.DatabaseSecretUpsert) (*api.DatabaseSecret, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	databaseSecret, err := upsertDatabaseSecretImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert DatabaseSecret %q of database ID %d", upsert.Name, upsert.DatabaseID)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := s.composeDatabaseSecret(ctx, databaseSecret); err != nil {
+		return nil, err
+	}
+	return databaseSecret, nil
+}
+
+// FindDatabaseSecret finds a list of DatabaseSecret instances ordered by name.
+func (s *Store) FindDatabaseSecret(ctx context.Context, find *api.DatabaseSecretFind) ([]*api.DatabaseSecret, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findDatabaseSecretImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find DatabaseSecret list with DatabaseSecretFind[%+v]", find)
+	}
+
+	for _, databaseSecret := range list {
+		if err := s.composeDatabaseSecret(ctx, databaseSecret); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// GetDatabaseSecretMap gets the secret values of the database by name, used to resolve the secrets in the statements.
+func (s *Store) GetDatabaseSecretMap(ctx context.Context, databaseID int) (map[string]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findDatabaseSecretImpl(ctx, tx.PTx, &api.DatabaseSecretFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find secrets of database ID %d", databaseID)
+	}
+	secretMap := make(map[string]string)
+	for _, databaseSecret := range list {
+		secretMap[databaseSecret.Name] = databaseSecret.Value
+	}
+	return secretMap, nil
+}
+
+// DeleteDatabaseSecret deletes an existing database secret.
+func (s *Store) DeleteDatabaseSecret(ctx context.Context, delete *api.DatabaseSecretDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `DELETE FROM db_secret WHERE database_id = $1 AND name = $2`, delete.DatabaseID, delete.Name)
+	if err != nil {
+		return FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("secret %q not found in database ID %d", delete.Name, delete.DatabaseID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+func (s *Store) composeDatabaseSecret(ctx context.Context, databaseSecret *api.DatabaseSecret) error {
+	creator, err := s.GetPrincipalByID(ctx, databaseSecret.CreatorID)
+	if err != nil {
+		return err
+	}
+	databaseSecret.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, databaseSecret.UpdaterID)
+	if err != nil {
+		return err
+	}
+	databaseSecret.Updater = updater
+	return nil
+}
+
+func upsertDatabaseSecretImpl(ctx context.Context, tx *sql.Tx, upsert *api.DatabaseSecretUpsert) (*api.DatabaseSecret, error) {
+	query := `
+		INSERT INTO db_secret (
+			creator_id,
+			updater_id,
+			database_id,
+			name,
+			value,
+			description
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT(database_id, name) DO UPDATE SET
+			updater_id = excluded.updater_id,
+			value = excluded.value,
+			description = excluded.description
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, value, description
+	`
+	var databaseSecret api.DatabaseSecret
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.DatabaseID,
+		upsert.Name,
+		upsert.Value,
+		upsert.Description,
+	).Scan(
+		&databaseSecret.ID,
+		&databaseSecret.CreatorID,
+		&databaseSecret.CreatedTs,
+		&databaseSecret.UpdaterID,
+		&databaseSecret.UpdatedTs,
+		&databaseSecret.DatabaseID,
+		&databaseSecret.Name,
+		&databaseSecret.Value,
+		&databaseSecret.Description,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	return &databaseSecret, nil
+}
+
+func findDatabaseSecretImpl(ctx context.Context, tx *sql.Tx, find *api.DatabaseSecretFind) ([]*api.DatabaseSecret, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			name,
+			value,
+			description
+		FROM db_secret
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY name`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into databaseSecretList.
+	var databaseSecretList []*api.DatabaseSecret
+	for rows.Next() {
+		var databaseSecret api.DatabaseSecret
+		if err := rows.Scan(
+			&databaseSecret.ID,
+			&databaseSecret.CreatorID,
+			&databaseSecret.CreatedTs,
+			&databaseSecret.UpdaterID,
+			&databaseSecret.UpdatedTs,
+			&databaseSecret.DatabaseID,
+			&databaseSecret.Name,
+			&databaseSecret.Value,
+			&databaseSecret.Description,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		databaseSecretList = append(databaseSecretList, &databaseSecret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return databaseSecretList, nil
+}
@@ -0,0 +1,387 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// databaseTemplateRaw is the store model for a DatabaseTemplate.
+// Fields have exactly the same meanings as DatabaseTemplate.
+type databaseTemplateRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID   int
+	CreatedTs   int64
+	UpdaterID   int
+	UpdatedTs   int64
+	WorkspaceID int
+
+	// Related fields
+	SheetID *int
+
+	// Domain specific fields
+	Name          string
+	Engine        db.Type
+	CharacterSet  string
+	Collation     string
+	ExtensionList []string
+	GranteeList   []string
+}
+
+// toDatabaseTemplate creates an instance of DatabaseTemplate based on the databaseTemplateRaw.
+// This is intended to be called when we need to compose a DatabaseTemplate relationship.
+func (raw *databaseTemplateRaw) toDatabaseTemplate() *api.DatabaseTemplate {
+	return &api.DatabaseTemplate{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID:   raw.CreatorID,
+		CreatedTs:   raw.CreatedTs,
+		UpdaterID:   raw.UpdaterID,
+		UpdatedTs:   raw.UpdatedTs,
+		WorkspaceID: raw.WorkspaceID,
+
+		// Related fields
+		SheetID: raw.SheetID,
+
+		// Domain specific fields
+		Name:          raw.Name,
+		Engine:        raw.Engine,
+		CharacterSet:  raw.CharacterSet,
+		Collation:     raw.Collation,
+		ExtensionList: raw.ExtensionList,
+		GranteeList:   raw.GranteeList,
+	}
+}
+
+// CreateDatabaseTemplate creates an instance of DatabaseTemplate.
+func (s *Store) CreateDatabaseTemplate(ctx context.Context, create *api.DatabaseTemplateCreate) (*api.DatabaseTemplate, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	databaseTemplateRaw, err := createDatabaseTemplateImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create DatabaseTemplate with DatabaseTemplateCreate[%+v]", create)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	databaseTemplate, err := s.composeDatabaseTemplate(ctx, databaseTemplateRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose DatabaseTemplate with databaseTemplateRaw[%+v]", databaseTemplateRaw)
+	}
+	return databaseTemplate, nil
+}
+
+// GetDatabaseTemplateByID gets an instance of DatabaseTemplate.
+func (s *Store) GetDatabaseTemplateByID(ctx context.Context, id int) (*api.DatabaseTemplate, error) {
+	list, err := s.FindDatabaseTemplate(ctx, &api.DatabaseTemplateFind{ID: &id})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get DatabaseTemplate with ID %d", id)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// FindDatabaseTemplate finds a list of DatabaseTemplate instances.
+func (s *Store) FindDatabaseTemplate(ctx context.Context, find *api.DatabaseTemplateFind) ([]*api.DatabaseTemplate, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	databaseTemplateRawList, err := findDatabaseTemplateImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find DatabaseTemplate list with DatabaseTemplateFind[%+v]", find)
+	}
+	var databaseTemplateList []*api.DatabaseTemplate
+	for _, raw := range databaseTemplateRawList {
+		databaseTemplate, err := s.composeDatabaseTemplate(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose DatabaseTemplate with databaseTemplateRaw[%+v]", raw)
+		}
+		databaseTemplateList = append(databaseTemplateList, databaseTemplate)
+	}
+	return databaseTemplateList, nil
+}
+
+// PatchDatabaseTemplate patches an instance of DatabaseTemplate.
+func (s *Store) PatchDatabaseTemplate(ctx context.Context, patch *api.DatabaseTemplatePatch) (*api.DatabaseTemplate, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	databaseTemplateRaw, err := patchDatabaseTemplateImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch DatabaseTemplate with DatabaseTemplatePatch[%+v]", patch)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	databaseTemplate, err := s.composeDatabaseTemplate(ctx, databaseTemplateRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose DatabaseTemplate with databaseTemplateRaw[%+v]", databaseTemplateRaw)
+	}
+	return databaseTemplate, nil
+}
+
+// DeleteDatabaseTemplate deletes an existing database template by ID.
+// Returns ENOTFOUND if the database template does not exist.
+func (s *Store) DeleteDatabaseTemplate(ctx context.Context, delete *api.DatabaseTemplateDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `DELETE FROM database_template WHERE id = $1`, delete.ID)
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("database template ID not found: %d", delete.ID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+//
+// private function
+//
+
+func (s *Store) composeDatabaseTemplate(ctx context.Context, raw *databaseTemplateRaw) (*api.DatabaseTemplate, error) {
+	databaseTemplate := raw.toDatabaseTemplate()
+
+	creator, err := s.GetPrincipalByID(ctx, databaseTemplate.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	databaseTemplate.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, databaseTemplate.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	databaseTemplate.Updater = updater
+
+	return databaseTemplate, nil
+}
+
+// createDatabaseTemplateImpl creates a new database template.
+func createDatabaseTemplateImpl(ctx context.Context, tx *sql.Tx, create *api.DatabaseTemplateCreate) (*databaseTemplateRaw, error) {
+	extensionList, granteeList := create.ExtensionList, create.GranteeList
+	if extensionList == nil {
+		extensionList = []string{}
+	}
+	if granteeList == nil {
+		granteeList = []string{}
+	}
+	// Insert row into database.
+	query := `
+		INSERT INTO database_template (
+			creator_id,
+			updater_id,
+			workspace_id,
+			name,
+			engine,
+			character_set,
+			"collation",
+			extension_list,
+			grantee_list,
+			sheet_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, engine, character_set, "collation", extension_list, grantee_list, sheet_id
+	`
+	row := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.WorkspaceID,
+		create.Name,
+		create.Engine,
+		create.CharacterSet,
+		create.Collation,
+		extensionList,
+		granteeList,
+		create.SheetID,
+	)
+	databaseTemplateRaw, err := scanDatabaseTemplateRaw(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return databaseTemplateRaw, nil
+}
+
+func findDatabaseTemplateImpl(ctx context.Context, tx *sql.Tx, find *api.DatabaseTemplateFind) ([]*databaseTemplateRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("workspace_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			workspace_id,
+			name,
+			engine,
+			character_set,
+			"collation",
+			extension_list,
+			grantee_list,
+			sheet_id
+		FROM database_template
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into databaseTemplateRawList.
+	var databaseTemplateRawList []*databaseTemplateRaw
+	for rows.Next() {
+		databaseTemplateRaw, err := scanDatabaseTemplateRaw(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		databaseTemplateRawList = append(databaseTemplateRawList, databaseTemplateRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return databaseTemplateRawList, nil
+}
+
+// patchDatabaseTemplateImpl updates a database template by ID. Returns the new state of the database template after update.
+func patchDatabaseTemplateImpl(ctx context.Context, tx *sql.Tx, patch *api.DatabaseTemplatePatch) (*databaseTemplateRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.CharacterSet; v != nil {
+		set, args = append(set, fmt.Sprintf("character_set = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Collation; v != nil {
+		set, args = append(set, fmt.Sprintf(`"collation" = $%d`, len(args)+1)), append(args, *v)
+	}
+	if v := patch.ExtensionList; v != nil {
+		set, args = append(set, fmt.Sprintf("extension_list = $%d", len(args)+1)), append(args, splitList(*v))
+	}
+	if v := patch.GranteeList; v != nil {
+		set, args = append(set, fmt.Sprintf("grantee_list = $%d", len(args)+1)), append(args, splitList(*v))
+	}
+	if v := patch.SheetID; v != nil {
+		var sheetID *int
+		if *v != 0 {
+			sheetID = v
+		}
+		set, args = append(set, fmt.Sprintf("sheet_id = $%d", len(args)+1)), append(args, sheetID)
+	}
+
+	args = append(args, patch.ID)
+
+	// Execute update query with RETURNING.
+	row := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE database_template
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, engine, character_set, "collation", extension_list, grantee_list, sheet_id
+	`, len(args)),
+		args...,
+	)
+	databaseTemplateRaw, err := scanDatabaseTemplateRaw(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("database template ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return databaseTemplateRaw, nil
+}
+
+func scanDatabaseTemplateRaw(row interface {
+	Scan(dest ...interface{}) error
+}) (*databaseTemplateRaw, error) {
+	var databaseTemplateRaw databaseTemplateRaw
+	var extensionList, granteeList pgtype.TextArray
+	var sheetID sql.NullInt32
+	if err := row.Scan(
+		&databaseTemplateRaw.ID,
+		&databaseTemplateRaw.CreatorID,
+		&databaseTemplateRaw.CreatedTs,
+		&databaseTemplateRaw.UpdaterID,
+		&databaseTemplateRaw.UpdatedTs,
+		&databaseTemplateRaw.WorkspaceID,
+		&databaseTemplateRaw.Name,
+		&databaseTemplateRaw.Engine,
+		&databaseTemplateRaw.CharacterSet,
+		&databaseTemplateRaw.Collation,
+		&extensionList,
+		&granteeList,
+		&sheetID,
+	); err != nil {
+		return nil, err
+	}
+	if err := extensionList.AssignTo(&databaseTemplateRaw.ExtensionList); err != nil {
+		return nil, err
+	}
+	if err := granteeList.AssignTo(&databaseTemplateRaw.GranteeList); err != nil {
+		return nil, err
+	}
+	if sheetID.Valid {
+		id := int(sheetID.Int32)
+		databaseTemplateRaw.SheetID = &id
+	}
+	return &databaseTemplateRaw, nil
+}
+
+// splitList splits the comma separated list, and the empty string is an empty list.
+func splitList(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,292 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// CreateDBQueryGrant creates the database query grant as pending.
+func (s *Store) CreateDBQueryGrant(ctx context.Context, create *api.DBQueryGrantCreate) (*api.DBQueryGrant, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	dbQueryGrant, err := createDBQueryGrantImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create DBQueryGrant with DBQueryGrantCreate[%+v]", create)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := s.composeDBQueryGrant(ctx, dbQueryGrant); err != nil {
+		return nil, err
+	}
+	return dbQueryGrant, nil
+}
+
+// FindDBQueryGrant finds a list of DBQueryGrant instances, the latest first.
+func (s *Store) FindDBQueryGrant(ctx context.Context, find *api.DBQueryGrantFind) ([]*api.DBQueryGrant, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findDBQueryGrantImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find DBQueryGrant list with DBQueryGrantFind[%+v]", find)
+	}
+
+	for _, dbQueryGrant := range list {
+		if err := s.composeDBQueryGrant(ctx, dbQueryGrant); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// PatchDBQueryGrant updates the status of an existing database query grant.
+// Returns NotFound if the grant doesn't exist in the database.
+func (s *Store) PatchDBQueryGrant(ctx context.Context, patch *api.DBQueryGrantPatch) (*api.DBQueryGrant, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	dbQueryGrant, err := patchDBQueryGrantImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := s.composeDBQueryGrant(ctx, dbQueryGrant); err != nil {
+		return nil, err
+	}
+	return dbQueryGrant, nil
+}
+
+// ExpireDBQueryGrant marks the approved database query grants expired before the time as EXPIRED, and returns them.
+func (s *Store) ExpireDBQueryGrant(ctx context.Context, ts int64) ([]*api.DBQueryGrant, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := expireDBQueryGrantImpl(ctx, tx.PTx, ts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to expire DBQueryGrant before %d", ts)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	for _, dbQueryGrant := range list {
+		if err := s.composeDBQueryGrant(ctx, dbQueryGrant); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func (s *Store) composeDBQueryGrant(ctx context.Context, dbQueryGrant *api.DBQueryGrant) error {
+	creator, err := s.GetPrincipalByID(ctx, dbQueryGrant.CreatorID)
+	if err != nil {
+		return err
+	}
+	dbQueryGrant.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, dbQueryGrant.UpdaterID)
+	if err != nil {
+		return err
+	}
+	dbQueryGrant.Updater = updater
+	return nil
+}
+
+func scanDBQueryGrant(row interface {
+	Scan(dest ...interface{}) error
+}) (*api.DBQueryGrant, error) {
+	var dbQueryGrant api.DBQueryGrant
+	var principalID sql.NullInt32
+	if err := row.Scan(
+		&dbQueryGrant.ID,
+		&dbQueryGrant.CreatorID,
+		&dbQueryGrant.CreatedTs,
+		&dbQueryGrant.UpdaterID,
+		&dbQueryGrant.UpdatedTs,
+		&dbQueryGrant.DatabaseID,
+		&principalID,
+		&dbQueryGrant.Role,
+		&dbQueryGrant.Status,
+		&dbQueryGrant.ExpireTs,
+		&dbQueryGrant.Reason,
+		&dbQueryGrant.Access,
+	); err != nil {
+		return nil, err
+	}
+	dbQueryGrant.PrincipalID = int(principalID.Int32)
+	return &dbQueryGrant, nil
+}
+
+func createDBQueryGrantImpl(ctx context.Context, tx *sql.Tx, create *api.DBQueryGrantCreate) (*api.DBQueryGrant, error) {
+	var principalID *int
+	if create.PrincipalID != 0 {
+		principalID = &create.PrincipalID
+	}
+	query := `
+		INSERT INTO db_query_grant (
+			creator_id,
+			updater_id,
+			database_id,
+			principal_id,
+			role,
+			status,
+			expire_ts,
+			reason,
+			access
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, principal_id, role, status, expire_ts, reason, access
+	`
+	dbQueryGrant, err := scanDBQueryGrant(tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		principalID,
+		create.Role,
+		api.DBQueryGrantPending,
+		create.ExpireTs,
+		create.Reason,
+		create.Access,
+	))
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	return dbQueryGrant, nil
+}
+
+func findDBQueryGrantImpl(ctx context.Context, tx *sql.Tx, find *api.DBQueryGrantFind) ([]*api.DBQueryGrant, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Access; v != nil {
+		where, args = append(where, fmt.Sprintf("access = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			principal_id,
+			role,
+			status,
+			expire_ts,
+			reason,
+			access
+		FROM db_query_grant
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into dbQueryGrantList.
+	var dbQueryGrantList []*api.DBQueryGrant
+	for rows.Next() {
+		dbQueryGrant, err := scanDBQueryGrant(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		dbQueryGrantList = append(dbQueryGrantList, dbQueryGrant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return dbQueryGrantList, nil
+}
+
+func patchDBQueryGrantImpl(ctx context.Context, tx *sql.Tx, patch *api.DBQueryGrantPatch) (*api.DBQueryGrant, error) {
+	dbQueryGrant, err := scanDBQueryGrant(tx.QueryRowContext(ctx, `
+		UPDATE db_query_grant
+		SET updater_id = $1, status = $2
+		WHERE id = $3 AND database_id = $4
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, principal_id, role, status, expire_ts, reason, access
+	`,
+		patch.UpdaterID,
+		patch.Status,
+		patch.ID,
+		patch.DatabaseID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("database query grant ID %d not found in database ID %d", patch.ID, patch.DatabaseID)}
+		}
+		return nil, FormatError(err)
+	}
+	return dbQueryGrant, nil
+}
+
+func expireDBQueryGrantImpl(ctx context.Context, tx *sql.Tx, ts int64) ([]*api.DBQueryGrant, error) {
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE db_query_grant
+		SET updater_id = $1, status = $2
+		WHERE status = $3 AND expire_ts <= $4
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, principal_id, role, status, expire_ts, reason, access
+	`,
+		api.SystemBotID,
+		api.DBQueryGrantExpired,
+		api.DBQueryGrantApproved,
+		ts,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var dbQueryGrantList []*api.DBQueryGrant
+	for rows.Next() {
+		dbQueryGrant, err := scanDBQueryGrant(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		dbQueryGrantList = append(dbQueryGrantList, dbQueryGrant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return dbQueryGrantList, nil
+}
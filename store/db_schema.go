@@ -0,0 +1,294 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/pkg/errors"
+)
+
+// dbSchemaRaw is the store model for an DBSchema.
+// Fields have exactly the same meanings as DBSchema.
+type dbSchemaRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Name  string
+	Owner string
+}
+
+// toDBSchema creates an instance of DBSchema based on the dbSchemaRaw.
+// This is intended to be called when we need to compose an DBSchema relationship.
+func (raw *dbSchemaRaw) toDBSchema() *api.DBSchema {
+	return &api.DBSchema{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		Name:  raw.Name,
+		Owner: raw.Owner,
+	}
+}
+
+// FindDBSchema finds a list of dbSchema instances.
+func (s *Store) FindDBSchema(ctx context.Context, find *api.DBSchemaFind) ([]*api.DBSchema, error) {
+	dbSchemaRawList, err := s.findDBSchemaRaw(ctx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find dbSchema list with dbSchemaFind[%+v]", find)
+	}
+	var dbSchemaList []*api.DBSchema
+	for _, raw := range dbSchemaRawList {
+		dbSchema, err := s.composeDBSchema(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose dbSchema with dbSchemaRaw[%+v]", raw)
+		}
+		dbSchemaList = append(dbSchemaList, dbSchema)
+	}
+	return dbSchemaList, nil
+}
+
+// SetDBSchemaList sets the schemas for a database.
+func (s *Store) SetDBSchemaList(ctx context.Context, schema *db.Schema, databaseID int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	oldDBSchemaRawList, err := s.findDBSchemaImpl(ctx, tx.PTx, &api.DBSchemaFind{
+		DatabaseID: &databaseID,
+	})
+	if err != nil {
+		return FormatError(err)
+	}
+
+	deletes, creates := generateDBSchemaActions(oldDBSchemaRawList, schema.NamespaceList, databaseID)
+	for _, d := range deletes {
+		if err := s.deleteDBSchemaImpl(ctx, tx.PTx, d); err != nil {
+			return err
+		}
+	}
+	for _, c := range creates {
+		if _, err := s.createDBSchemaImpl(ctx, tx.PTx, c); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// private functions.
+func generateDBSchemaActions(oldDBSchemaRawList []*dbSchemaRaw, namespaceList []db.Namespace, databaseID int) ([]*api.DBSchemaDelete, []*api.DBSchemaCreate) {
+	var newDBSchemaList []*api.DBSchemaCreate
+	for _, namespace := range namespaceList {
+		newDBSchemaList = append(newDBSchemaList, &api.DBSchemaCreate{
+			CreatorID:  api.SystemBotID,
+			DatabaseID: databaseID,
+			Name:       namespace.Name,
+			Owner:      namespace.Owner,
+		})
+	}
+	oldDBSchemaMap := make(map[string]*dbSchemaRaw)
+	for _, s := range oldDBSchemaRawList {
+		oldDBSchemaMap[s.Name] = s
+	}
+	newDBSchemaMap := make(map[string]*api.DBSchemaCreate)
+	for _, s := range newDBSchemaList {
+		newDBSchemaMap[s.Name] = s
+	}
+
+	var deletes []*api.DBSchemaDelete
+	var creates []*api.DBSchemaCreate
+	for _, oldValue := range oldDBSchemaRawList {
+		newValue, ok := newDBSchemaMap[oldValue.Name]
+		if !ok {
+			deletes = append(deletes, &api.DBSchemaDelete{ID: oldValue.ID})
+		} else if ok && oldValue.Owner != newValue.Owner {
+			deletes = append(deletes, &api.DBSchemaDelete{ID: oldValue.ID})
+			creates = append(creates, newValue)
+		}
+	}
+	for _, newValue := range newDBSchemaList {
+		if _, ok := oldDBSchemaMap[newValue.Name]; !ok {
+			creates = append(creates, newValue)
+		}
+	}
+	return deletes, creates
+}
+
+func (s *Store) composeDBSchema(ctx context.Context, raw *dbSchemaRaw) (*api.DBSchema, error) {
+	dbSchema := raw.toDBSchema()
+
+	creator, err := s.GetPrincipalByID(ctx, dbSchema.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	dbSchema.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, dbSchema.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	dbSchema.Updater = updater
+
+	database, err := s.GetDatabase(ctx, &api.DatabaseFind{ID: &dbSchema.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+	dbSchema.Database = database
+
+	return dbSchema, nil
+}
+
+// findDBSchemaRaw retrieves a list of DBSchemas based on find.
+func (s *Store) findDBSchemaRaw(ctx context.Context, find *api.DBSchemaFind) ([]*dbSchemaRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := s.findDBSchemaImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// createDBSchemaImpl creates a new DBSchema.
+func (*Store) createDBSchemaImpl(ctx context.Context, tx *sql.Tx, create *api.DBSchemaCreate) (*dbSchemaRaw, error) {
+	// Insert row into db_schema.
+	query := `
+		INSERT INTO db_schema (
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			name,
+			owner
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, owner
+	`
+	var dbSchemaRaw dbSchemaRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatedTs,
+		create.CreatorID,
+		create.UpdatedTs,
+		create.DatabaseID,
+		create.Name,
+		create.Owner,
+	).Scan(
+		&dbSchemaRaw.ID,
+		&dbSchemaRaw.CreatorID,
+		&dbSchemaRaw.CreatedTs,
+		&dbSchemaRaw.UpdaterID,
+		&dbSchemaRaw.UpdatedTs,
+		&dbSchemaRaw.DatabaseID,
+		&dbSchemaRaw.Name,
+		&dbSchemaRaw.Owner,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &dbSchemaRaw, nil
+}
+
+func (*Store) findDBSchemaImpl(ctx context.Context, tx *sql.Tx, find *api.DBSchemaFind) ([]*dbSchemaRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			name,
+			owner
+		FROM db_schema
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id, name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into dbSchemaRawList.
+	var dbSchemaRawList []*dbSchemaRaw
+	for rows.Next() {
+		var dbSchemaRaw dbSchemaRaw
+		if err := rows.Scan(
+			&dbSchemaRaw.ID,
+			&dbSchemaRaw.CreatorID,
+			&dbSchemaRaw.CreatedTs,
+			&dbSchemaRaw.UpdaterID,
+			&dbSchemaRaw.UpdatedTs,
+			&dbSchemaRaw.DatabaseID,
+			&dbSchemaRaw.Name,
+			&dbSchemaRaw.Owner,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		dbSchemaRawList = append(dbSchemaRawList, &dbSchemaRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return dbSchemaRawList, nil
+}
+
+// deleteDBSchemaImpl permanently deletes DBSchemas from a database.
+func (*Store) deleteDBSchemaImpl(ctx context.Context, tx *sql.Tx, delete *api.DBSchemaDelete) error {
+	// Remove row from database.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM db_schema WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
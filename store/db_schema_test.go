@@ -0,0 +1,64 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDBSchemaActions(t *testing.T) {
+	databaseID := 198
+	tests := []struct {
+		oldDBSchemaRawList []*dbSchemaRaw
+		namespaceList      []db.Namespace
+		wantDeletes        []*api.DBSchemaDelete
+		wantCreates        []*api.DBSchemaCreate
+	}{
+		{
+			oldDBSchemaRawList: []*dbSchemaRaw{
+				{ID: 123, Name: "public", Owner: "postgres"},
+				{ID: 124, Name: "sales", Owner: "postgres"},
+			},
+			namespaceList: []db.Namespace{
+				{Name: "public", Owner: "postgres"},
+				{Name: "sales", Owner: "bytebase"},
+				{Name: "hr", Owner: "bytebase"},
+			},
+			wantDeletes: []*api.DBSchemaDelete{
+				{ID: 124},
+			},
+			wantCreates: []*api.DBSchemaCreate{
+				{Name: "sales", Owner: "bytebase", CreatorID: api.SystemBotID, DatabaseID: databaseID},
+				{Name: "hr", Owner: "bytebase", CreatorID: api.SystemBotID, DatabaseID: databaseID},
+			},
+		},
+		{
+			oldDBSchemaRawList: []*dbSchemaRaw{
+				{ID: 123, Name: "public", Owner: "postgres"},
+			},
+			namespaceList: nil,
+			wantDeletes: []*api.DBSchemaDelete{
+				{ID: 123},
+			},
+			wantCreates: nil,
+		},
+		{
+			oldDBSchemaRawList: []*dbSchemaRaw{
+				{ID: 123, Name: "public", Owner: "postgres"},
+			},
+			namespaceList: []db.Namespace{
+				{Name: "public", Owner: "postgres"},
+			},
+			wantDeletes: nil,
+			wantCreates: nil,
+		},
+	}
+
+	for _, test := range tests {
+		deletes, creates := generateDBSchemaActions(test.oldDBSchemaRawList, test.namespaceList, databaseID)
+		require.Equal(t, test.wantDeletes, deletes)
+		require.Equal(t, test.wantCreates, creates)
+	}
+}
@@ -24,8 +24,9 @@ type environmentRaw struct {
 	UpdatedTs int64
 
 	// Domain specific fields
-	Name  string
-	Order int
+	WorkspaceID int
+	Name        string
+	Order       int
 }
 
 // toEnvironment creates an instance of Environment based on the environmentRaw.
@@ -40,8 +41,9 @@ func (raw *environmentRaw) toEnvironment() *api.Environment {
 		UpdaterID: raw.UpdaterID,
 		UpdatedTs: raw.UpdatedTs,
 
-		Name:  raw.Name,
-		Order: raw.Order,
+		WorkspaceID: raw.WorkspaceID,
+		Name:        raw.Name,
+		Order:       raw.Order,
 	}
 }
 
@@ -245,16 +247,12 @@ func (s *Store) patchEnvironmentRaw(ctx context.Context, patch *api.EnvironmentP
 // createEnvironmentImpl creates a new environment.
 func (Store) createEnvironmentImpl(ctx context.Context, tx *sql.Tx, create *api.EnvironmentCreate) (*environmentRaw, error) {
 	var order int
-	// The order is the MAX(order) + 1
+	// The order is the MAX(order) + 1 in the workspace, and 0 for the first environment of the workspace.
 	if err := tx.QueryRowContext(ctx, `
-		SELECT "order"
+		SELECT COALESCE(MAX("order"), -1)
 		FROM environment
-		ORDER BY "order" DESC
-		LIMIT 1
-	`).Scan(&order); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("no environment record found")}
-		}
+		WHERE workspace_id = $1
+	`, create.WorkspaceID).Scan(&order); err != nil {
 		return nil, FormatError(err)
 	}
 
@@ -263,16 +261,18 @@ func (Store) createEnvironmentImpl(ctx context.Context, tx *sql.Tx, create *api.
 		INSERT INTO environment (
 			creator_id,
 			updater_id,
+			workspace_id,
 			name,
 			"order"
 		)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, "order"
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, "order"
 	`
 	var envRaw environmentRaw
 	if err := tx.QueryRowContext(ctx, query,
 		create.CreatorID,
 		create.CreatorID,
+		create.WorkspaceID,
 		create.Name,
 		order+1,
 	).Scan(
@@ -282,6 +282,7 @@ func (Store) createEnvironmentImpl(ctx context.Context, tx *sql.Tx, create *api.
 		&envRaw.CreatedTs,
 		&envRaw.UpdaterID,
 		&envRaw.UpdatedTs,
+		&envRaw.WorkspaceID,
 		&envRaw.Name,
 		&envRaw.Order,
 	); err != nil {
@@ -299,6 +300,9 @@ func (*Store) findEnvironmentImpl(ctx context.Context, tx *sql.Tx, find *api.Env
 	if v := find.ID; v != nil {
 		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("workspace_id = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.Name; v != nil {
 		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
@@ -314,6 +318,7 @@ func (*Store) findEnvironmentImpl(ctx context.Context, tx *sql.Tx, find *api.Env
 			created_ts,
 			updater_id,
 			updated_ts,
+			workspace_id,
 			name,
 			"order"
 		FROM environment
@@ -336,6 +341,7 @@ func (*Store) findEnvironmentImpl(ctx context.Context, tx *sql.Tx, find *api.Env
 			&environment.CreatedTs,
 			&environment.UpdaterID,
 			&environment.UpdatedTs,
+			&environment.WorkspaceID,
 			&environment.Name,
 			&environment.Order,
 		); err != nil {
@@ -373,7 +379,7 @@ func (*Store) patchEnvironmentImpl(ctx context.Context, tx *sql.Tx, patch *api.E
 		UPDATE environment
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, "order"
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, "order"
 	`, len(args)),
 		args...,
 	).Scan(
@@ -383,6 +389,7 @@ func (*Store) patchEnvironmentImpl(ctx context.Context, tx *sql.Tx, patch *api.E
 		&environment.CreatedTs,
 		&environment.UpdaterID,
 		&environment.UpdatedTs,
+		&environment.WorkspaceID,
 		&environment.Name,
 		&environment.Order,
 	); err != nil {
@@ -0,0 +1,231 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// CreateIdempotencyKey creates an idempotency key, replacing the expired one with the same key.
+// Returns nil if the creator already has the unexpired key.
+func (s *Store) CreateIdempotencyKey(ctx context.Context, create *api.IdempotencyKeyCreate) (*api.IdempotencyKey, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	query := `
+		INSERT INTO idempotency_key (
+			creator_id,
+			key,
+			request,
+			request_hash,
+			expire_ts
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(creator_id, key) DO UPDATE SET
+			created_ts = extract(epoch from now()),
+			request = excluded.request,
+			request_hash = excluded.request_hash,
+			status_code = 0,
+			content_type = '',
+			response = '',
+			expire_ts = excluded.expire_ts
+		WHERE idempotency_key.expire_ts < $6
+		RETURNING id, creator_id, created_ts, key, request, request_hash, status_code, content_type, response, expire_ts
+	`
+	var idempotencyKey api.IdempotencyKey
+	if err := tx.PTx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.Key,
+		create.Request,
+		create.RequestHash,
+		create.ExpireTs,
+		time.Now().Unix(),
+	).Scan(
+		&idempotencyKey.ID,
+		&idempotencyKey.CreatorID,
+		&idempotencyKey.CreatedTs,
+		&idempotencyKey.Key,
+		&idempotencyKey.Request,
+		&idempotencyKey.RequestHash,
+		&idempotencyKey.StatusCode,
+		&idempotencyKey.ContentType,
+		&idempotencyKey.Response,
+		&idempotencyKey.ExpireTs,
+	); err != nil {
+		// The conflicting key is unexpired, so it's not updated.
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &idempotencyKey, nil
+}
+
+// GetIdempotencyKey gets an idempotency key.
+// Returns nil if not found.
+func (s *Store) GetIdempotencyKey(ctx context.Context, find *api.IdempotencyKeyFind) (*api.IdempotencyKey, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findIdempotencyKeyImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d idempotency keys with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// PatchIdempotencyKey saves the response of an idempotency key.
+func (s *Store) PatchIdempotencyKey(ctx context.Context, patch *api.IdempotencyKeyPatch) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `
+		UPDATE idempotency_key
+		SET status_code = $1, content_type = $2, response = $3
+		WHERE id = $4`,
+		patch.StatusCode,
+		patch.ContentType,
+		patch.Response,
+		patch.ID,
+	)
+	if err != nil {
+		return FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("idempotency key ID not found: %d", patch.ID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKey deletes an idempotency key.
+func (s *Store) DeleteIdempotencyKey(ctx context.Context, delete *api.IdempotencyKeyDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM idempotency_key WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKeyExpiredBefore deletes the idempotency keys expired before the time.
+func (s *Store) DeleteIdempotencyKeyExpiredBefore(ctx context.Context, expireTs int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM idempotency_key WHERE expire_ts < $1`, expireTs); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+func findIdempotencyKeyImpl(ctx context.Context, tx *sql.Tx, find *api.IdempotencyKeyFind) ([]*api.IdempotencyKey, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Key; v != nil {
+		where, args = append(where, fmt.Sprintf("key = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			key,
+			request,
+			request_hash,
+			status_code,
+			content_type,
+			response,
+			expire_ts
+		FROM idempotency_key
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into idempotencyKeyList.
+	var idempotencyKeyList []*api.IdempotencyKey
+	for rows.Next() {
+		var idempotencyKey api.IdempotencyKey
+		if err := rows.Scan(
+			&idempotencyKey.ID,
+			&idempotencyKey.CreatorID,
+			&idempotencyKey.CreatedTs,
+			&idempotencyKey.Key,
+			&idempotencyKey.Request,
+			&idempotencyKey.RequestHash,
+			&idempotencyKey.StatusCode,
+			&idempotencyKey.ContentType,
+			&idempotencyKey.Response,
+			&idempotencyKey.ExpireTs,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		idempotencyKeyList = append(idempotencyKeyList, &idempotencyKey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return idempotencyKeyList, nil
+}
@@ -29,12 +29,21 @@ type instanceRaw struct {
 	EnvironmentID int
 
 	// Domain specific fields
+	WorkspaceID   int
 	Name          string
 	Engine        db.Type
 	EngineVersion string
 	ExternalLink  string
 	Host          string
 	Port          string
+	// BackupStorageBackend is empty for the storage backend configured for the server.
+	BackupStorageBackend api.BackupStorageBackend
+	// SSHHost is empty for connecting the instance directly.
+	SSHHost       string
+	SSHPort       string
+	SSHUser       string
+	SSHPrivateKey string
+	SSHHostKey    string
 }
 
 // toInstance creates an instance of Instance based on the instanceRaw.
@@ -54,12 +63,20 @@ func (raw *instanceRaw) toInstance() *api.Instance {
 		EnvironmentID: raw.EnvironmentID,
 
 		// Domain specific fields
+		WorkspaceID:   raw.WorkspaceID,
 		Name:          raw.Name,
 		Engine:        raw.Engine,
 		EngineVersion: raw.EngineVersion,
 		ExternalLink:  raw.ExternalLink,
 		Host:          raw.Host,
 		Port:          raw.Port,
+
+		BackupStorageBackend: raw.BackupStorageBackend,
+		SSHHost:              raw.SSHHost,
+		SSHPort:              raw.SSHPort,
+		SSHUser:              raw.SSHUser,
+		SSHPrivateKey:        raw.SSHPrivateKey,
+		SSHHostKey:           raw.SSHHostKey,
 	}
 }
 
@@ -123,6 +140,46 @@ func (s *Store) PatchInstance(ctx context.Context, patch *api.InstancePatch) (*a
 	return instance, nil
 }
 
+// ArchiveInstanceList archives the instances in one transaction, so that either all or none of them are archived.
+func (s *Store) ArchiveInstanceList(ctx context.Context, idList []int, updaterID int) ([]*api.Instance, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rowStatus := string(api.Archived)
+	var instanceRawList []*instanceRaw
+	for _, id := range idList {
+		instanceRaw, err := patchInstanceImpl(ctx, tx.PTx, &api.InstancePatch{
+			ID:        id,
+			UpdaterID: updaterID,
+			RowStatus: &rowStatus,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to archive instance ID %d", id)
+		}
+		instanceRawList = append(instanceRawList, instanceRaw)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	var instanceList []*api.Instance
+	for _, instanceRaw := range instanceRawList {
+		if err := s.cache.UpsertCache(api.InstanceCache, instanceRaw.ID, instanceRaw); err != nil {
+			return nil, err
+		}
+		instance, err := s.composeInstance(ctx, instanceRaw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose Instance with instanceRaw[%+v]", instanceRaw)
+		}
+		instanceList = append(instanceList, instance)
+	}
+	return instanceList, nil
+}
+
 // CountInstance counts the number of instances.
 func (s *Store) CountInstance(ctx context.Context, find *api.InstanceFind) (int, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -190,13 +247,20 @@ func (s *Store) FindInstanceWithDatabaseBackupEnabled(ctx context.Context, engin
 			instance.created_ts,
 			instance.updater_id,
 			instance.updated_ts,
+			instance.workspace_id,
 			instance.environment_id,
 			instance.name,
 			instance.engine,
 			instance.engine_version,
 			instance.external_link,
 			instance.host,
-			instance.port
+			instance.port,
+			instance.backup_storage_backend,
+			instance.ssh_host,
+			instance.ssh_port,
+			instance.ssh_user,
+			instance.ssh_private_key,
+			instance.ssh_host_key
 		FROM instance
 		JOIN db ON db.instance_id = instance.id
 		JOIN backup_setting AS bs ON db.id = bs.database_id
@@ -219,6 +283,7 @@ func (s *Store) FindInstanceWithDatabaseBackupEnabled(ctx context.Context, engin
 			&instanceRaw.CreatedTs,
 			&instanceRaw.UpdaterID,
 			&instanceRaw.UpdatedTs,
+			&instanceRaw.WorkspaceID,
 			&instanceRaw.EnvironmentID,
 			&instanceRaw.Name,
 			&instanceRaw.Engine,
@@ -226,6 +291,12 @@ func (s *Store) FindInstanceWithDatabaseBackupEnabled(ctx context.Context, engin
 			&instanceRaw.ExternalLink,
 			&instanceRaw.Host,
 			&instanceRaw.Port,
+			&instanceRaw.BackupStorageBackend,
+			&instanceRaw.SSHHost,
+			&instanceRaw.SSHPort,
+			&instanceRaw.SSHUser,
+			&instanceRaw.SSHPrivateKey,
+			&instanceRaw.SSHHostKey,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -479,26 +550,40 @@ func createInstanceImpl(ctx context.Context, tx *sql.Tx, create *api.InstanceCre
 		INSERT INTO instance (
 			creator_id,
 			updater_id,
+			workspace_id,
 			environment_id,
 			name,
 			engine,
 			external_link,
 			host,
-			port
+			port,
+			backup_storage_backend,
+			ssh_host,
+			ssh_port,
+			ssh_user,
+			ssh_private_key,
+			ssh_host_key
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, environment_id, name, engine, engine_version, external_link, host, port
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, environment_id, name, engine, engine_version, external_link, host, port, backup_storage_backend, ssh_host, ssh_port, ssh_user, ssh_private_key, ssh_host_key
 	`
 	var instanceRaw instanceRaw
 	if err := tx.QueryRowContext(ctx, query,
 		create.CreatorID,
 		create.CreatorID,
+		create.WorkspaceID,
 		create.EnvironmentID,
 		create.Name,
 		create.Engine,
 		create.ExternalLink,
 		create.Host,
 		create.Port,
+		create.BackupStorageBackend,
+		create.SSHHost,
+		create.SSHPort,
+		create.SSHUser,
+		create.SSHPrivateKey,
+		create.SSHHostKey,
 	).Scan(
 		&instanceRaw.ID,
 		&instanceRaw.RowStatus,
@@ -506,6 +591,7 @@ func createInstanceImpl(ctx context.Context, tx *sql.Tx, create *api.InstanceCre
 		&instanceRaw.CreatedTs,
 		&instanceRaw.UpdaterID,
 		&instanceRaw.UpdatedTs,
+		&instanceRaw.WorkspaceID,
 		&instanceRaw.EnvironmentID,
 		&instanceRaw.Name,
 		&instanceRaw.Engine,
@@ -513,6 +599,12 @@ func createInstanceImpl(ctx context.Context, tx *sql.Tx, create *api.InstanceCre
 		&instanceRaw.ExternalLink,
 		&instanceRaw.Host,
 		&instanceRaw.Port,
+		&instanceRaw.BackupStorageBackend,
+		&instanceRaw.SSHHost,
+		&instanceRaw.SSHPort,
+		&instanceRaw.SSHUser,
+		&instanceRaw.SSHPrivateKey,
+		&instanceRaw.SSHHostKey,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -533,13 +625,20 @@ func findInstanceImpl(ctx context.Context, tx *sql.Tx, find *api.InstanceFind) (
 			created_ts,
 			updater_id,
 			updated_ts,
+			workspace_id,
 			environment_id,
 			name,
 			engine,
 			engine_version,
 			external_link,
 			host,
-			port
+			port,
+			backup_storage_backend,
+			ssh_host,
+			ssh_port,
+			ssh_user,
+			ssh_private_key,
+			ssh_host_key
 		FROM instance
 		WHERE `+where,
 		args...,
@@ -560,6 +659,7 @@ func findInstanceImpl(ctx context.Context, tx *sql.Tx, find *api.InstanceFind) (
 			&instanceRaw.CreatedTs,
 			&instanceRaw.UpdaterID,
 			&instanceRaw.UpdatedTs,
+			&instanceRaw.WorkspaceID,
 			&instanceRaw.EnvironmentID,
 			&instanceRaw.Name,
 			&instanceRaw.Engine,
@@ -567,6 +667,12 @@ func findInstanceImpl(ctx context.Context, tx *sql.Tx, find *api.InstanceFind) (
 			&instanceRaw.ExternalLink,
 			&instanceRaw.Host,
 			&instanceRaw.Port,
+			&instanceRaw.BackupStorageBackend,
+			&instanceRaw.SSHHost,
+			&instanceRaw.SSHPort,
+			&instanceRaw.SSHUser,
+			&instanceRaw.SSHPrivateKey,
+			&instanceRaw.SSHHostKey,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -586,6 +692,9 @@ func patchInstanceImpl(ctx context.Context, tx *sql.Tx, patch *api.InstancePatch
 	if v := patch.RowStatus; v != nil {
 		set, args = append(set, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.RowStatus(*v))
 	}
+	if v := patch.EnvironmentID; v != nil {
+		set, args = append(set, fmt.Sprintf("environment_id = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := patch.Name; v != nil {
 		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
@@ -601,6 +710,24 @@ func patchInstanceImpl(ctx context.Context, tx *sql.Tx, patch *api.InstancePatch
 	if v := patch.Port; v != nil {
 		set, args = append(set, fmt.Sprintf("port = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.BackupStorageBackend; v != nil {
+		set, args = append(set, fmt.Sprintf("backup_storage_backend = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHHost; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_host = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHPort; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_port = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHUser; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_user = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHPrivateKey; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_private_key = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHHostKey; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_host_key = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -610,7 +737,7 @@ func patchInstanceImpl(ctx context.Context, tx *sql.Tx, patch *api.InstancePatch
 		UPDATE instance
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, environment_id, name, engine, engine_version, external_link, host, port
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, environment_id, name, engine, engine_version, external_link, host, port, backup_storage_backend, ssh_host, ssh_port, ssh_user, ssh_private_key, ssh_host_key
 	`, len(args)),
 		args...,
 	).Scan(
@@ -620,6 +747,7 @@ func patchInstanceImpl(ctx context.Context, tx *sql.Tx, patch *api.InstancePatch
 		&instanceRaw.CreatedTs,
 		&instanceRaw.UpdaterID,
 		&instanceRaw.UpdatedTs,
+		&instanceRaw.WorkspaceID,
 		&instanceRaw.EnvironmentID,
 		&instanceRaw.Name,
 		&instanceRaw.Engine,
@@ -627,6 +755,12 @@ func patchInstanceImpl(ctx context.Context, tx *sql.Tx, patch *api.InstancePatch
 		&instanceRaw.ExternalLink,
 		&instanceRaw.Host,
 		&instanceRaw.Port,
+		&instanceRaw.BackupStorageBackend,
+		&instanceRaw.SSHHost,
+		&instanceRaw.SSHPort,
+		&instanceRaw.SSHUser,
+		&instanceRaw.SSHPrivateKey,
+		&instanceRaw.SSHHostKey,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("instance ID not found: %d", patch.ID)}
@@ -648,6 +782,9 @@ func findInstanceQuery(find *api.InstanceFind) (string, []interface{}) {
 	if v := find.EnvironmentID; v != nil {
 		where, args = append(where, fmt.Sprintf("environment_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("workspace_id = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.Host; v != nil {
 		where, args = append(where, fmt.Sprintf("host = $%d", len(args)+1)), append(args, *v)
 	}
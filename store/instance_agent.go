@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// UpsertInstanceAgent creates the agent of the instance, or replaces its token hash if it already exists.
+func (s *Store) UpsertInstanceAgent(ctx context.Context, upsert *api.InstanceAgentUpsert) (*api.InstanceAgent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	instanceAgent, err := upsertInstanceAgentImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert InstanceAgent of instance ID %d", upsert.InstanceID)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := s.composeInstanceAgent(ctx, instanceAgent); err != nil {
+		return nil, err
+	}
+	return instanceAgent, nil
+}
+
+// FindInstanceAgent finds a list of InstanceAgent instances.
+func (s *Store) FindInstanceAgent(ctx context.Context, find *api.InstanceAgentFind) ([]*api.InstanceAgent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findInstanceAgentImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find InstanceAgent list with InstanceAgentFind[%+v]", find)
+	}
+
+	for _, instanceAgent := range list {
+		if err := s.composeInstanceAgent(ctx, instanceAgent); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// GetInstanceAgent gets an instance of InstanceAgent.
+// Returns nil if not found.
+func (s *Store) GetInstanceAgent(ctx context.Context, find *api.InstanceAgentFind) (*api.InstanceAgent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findInstanceAgentImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get InstanceAgent with InstanceAgentFind[%+v]", find)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d instance agents with filter %+v, expect 1", len(list), find)}
+	}
+
+	if err := s.composeInstanceAgent(ctx, list[0]); err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// DeleteInstanceAgent deletes the agent of the instance.
+func (s *Store) DeleteInstanceAgent(ctx context.Context, delete *api.InstanceAgentDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `DELETE FROM instance_agent WHERE instance_id = $1`, delete.InstanceID)
+	if err != nil {
+		return FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("agent not found for instance ID %d", delete.InstanceID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+func (s *Store) composeInstanceAgent(ctx context.Context, instanceAgent *api.InstanceAgent) error {
+	creator, err := s.GetPrincipalByID(ctx, instanceAgent.CreatorID)
+	if err != nil {
+		return err
+	}
+	instanceAgent.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, instanceAgent.UpdaterID)
+	if err != nil {
+		return err
+	}
+	instanceAgent.Updater = updater
+	return nil
+}
+
+func upsertInstanceAgentImpl(ctx context.Context, tx *sql.Tx, upsert *api.InstanceAgentUpsert) (*api.InstanceAgent, error) {
+	query := `
+		INSERT INTO instance_agent (
+			creator_id,
+			updater_id,
+			instance_id,
+			token_hash
+		)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(instance_id) DO UPDATE SET
+			updater_id = excluded.updater_id,
+			token_hash = excluded.token_hash
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, token_hash
+	`
+	var instanceAgent api.InstanceAgent
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.InstanceID,
+		upsert.TokenHash,
+	).Scan(
+		&instanceAgent.ID,
+		&instanceAgent.CreatorID,
+		&instanceAgent.CreatedTs,
+		&instanceAgent.UpdaterID,
+		&instanceAgent.UpdatedTs,
+		&instanceAgent.InstanceID,
+		&instanceAgent.TokenHash,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	return &instanceAgent, nil
+}
+
+func findInstanceAgentImpl(ctx context.Context, tx *sql.Tx, find *api.InstanceAgentFind) ([]*api.InstanceAgent, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TokenHash; v != nil {
+		where, args = append(where, fmt.Sprintf("token_hash = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			instance_id,
+			token_hash
+		FROM instance_agent
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into instanceAgentList.
+	var instanceAgentList []*api.InstanceAgent
+	for rows.Next() {
+		var instanceAgent api.InstanceAgent
+		if err := rows.Scan(
+			&instanceAgent.ID,
+			&instanceAgent.CreatorID,
+			&instanceAgent.CreatedTs,
+			&instanceAgent.UpdaterID,
+			&instanceAgent.UpdatedTs,
+			&instanceAgent.InstanceID,
+			&instanceAgent.TokenHash,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		instanceAgentList = append(instanceAgentList, &instanceAgent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return instanceAgentList, nil
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/pkg/errors"
+)
+
+// CreateInstanceDiskUsage creates an instance of InstanceDiskUsage.
+func (s *Store) CreateInstanceDiskUsage(ctx context.Context, create *api.InstanceDiskUsageCreate) (*api.InstanceDiskUsage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	instanceDiskUsage, err := createInstanceDiskUsageImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create InstanceDiskUsage with InstanceDiskUsageCreate[%+v]", create)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return instanceDiskUsage, nil
+}
+
+// FindInstanceDiskUsage finds a list of InstanceDiskUsage instances, the oldest first.
+func (s *Store) FindInstanceDiskUsage(ctx context.Context, find *api.InstanceDiskUsageFind) ([]*api.InstanceDiskUsage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findInstanceDiskUsageImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find InstanceDiskUsage list with InstanceDiskUsageFind[%+v]", find)
+	}
+
+	return list, nil
+}
+
+// DeleteInstanceDiskUsageBefore deletes the disk usage samples created before the time.
+func (s *Store) DeleteInstanceDiskUsageBefore(ctx context.Context, createdTs int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM instance_disk_usage WHERE created_ts < $1`, createdTs); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// GetInstanceDataSize returns the data and index size of the tables in the databases of the instance,
+// which are synced from the engine views.
+func (s *Store) GetInstanceDataSize(ctx context.Context, instanceID int) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	var dataSize int64
+	if err := tx.PTx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(tbl.data_size + tbl.index_size), 0)
+		FROM tbl
+		INNER JOIN db ON tbl.database_id = db.id
+		WHERE db.instance_id = $1`,
+		instanceID,
+	).Scan(&dataSize); err != nil {
+		return 0, FormatError(err)
+	}
+	return dataSize, nil
+}
+
+// createInstanceDiskUsageImpl creates a new disk usage sample.
+func createInstanceDiskUsageImpl(ctx context.Context, tx *sql.Tx, create *api.InstanceDiskUsageCreate) (*api.InstanceDiskUsage, error) {
+	// Insert row into database.
+	query := `
+		INSERT INTO instance_disk_usage (
+			instance_id,
+			total_bytes,
+			free_bytes,
+			data_bytes
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_ts, instance_id, total_bytes, free_bytes, data_bytes
+	`
+	var instanceDiskUsage api.InstanceDiskUsage
+	if err := tx.QueryRowContext(ctx, query,
+		create.InstanceID,
+		create.TotalBytes,
+		create.FreeBytes,
+		create.DataBytes,
+	).Scan(
+		&instanceDiskUsage.ID,
+		&instanceDiskUsage.CreatedTs,
+		&instanceDiskUsage.InstanceID,
+		&instanceDiskUsage.TotalBytes,
+		&instanceDiskUsage.FreeBytes,
+		&instanceDiskUsage.DataBytes,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	return &instanceDiskUsage, nil
+}
+
+func findInstanceDiskUsageImpl(ctx context.Context, tx *sql.Tx, find *api.InstanceDiskUsageFind) ([]*api.InstanceDiskUsage, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			instance_id,
+			total_bytes,
+			free_bytes,
+			data_bytes
+		FROM instance_disk_usage
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY created_ts ASC, id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into instanceDiskUsageList.
+	var instanceDiskUsageList []*api.InstanceDiskUsage
+	for rows.Next() {
+		var instanceDiskUsage api.InstanceDiskUsage
+		if err := rows.Scan(
+			&instanceDiskUsage.ID,
+			&instanceDiskUsage.CreatedTs,
+			&instanceDiskUsage.InstanceID,
+			&instanceDiskUsage.TotalBytes,
+			&instanceDiskUsage.FreeBytes,
+			&instanceDiskUsage.DataBytes,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		instanceDiskUsageList = append(instanceDiskUsageList, &instanceDiskUsage)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return instanceDiskUsageList, nil
+}
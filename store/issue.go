@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jackc/pgtype"
+
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/metric"
@@ -37,6 +39,7 @@ type issueRaw struct {
 	Description string
 	AssigneeID  int
 	Payload     string
+	LabelList   []string
 }
 
 // toIssue creates an instance of Issue based on the issueRaw.
@@ -62,6 +65,7 @@ func (raw *issueRaw) toIssue() *api.Issue {
 		Description: raw.Description,
 		AssigneeID:  raw.AssigneeID,
 		Payload:     raw.Payload,
+		LabelList:   raw.LabelList,
 	}
 }
 
@@ -184,6 +188,44 @@ func (s *Store) CountIssueGroupByTypeAndStatus(ctx context.Context) ([]*metric.I
 	return res, nil
 }
 
+// CountIssueGroupByWeek counts the number of issues created since the time and group by the week.
+// Used by the metric collector.
+func (s *Store) CountIssueGroupByWeek(ctx context.Context, sinceTs int64) ([]*metric.WeeklyIssueCountMetric, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rows, err := tx.PTx.QueryContext(ctx, `
+		SELECT CAST(extract(epoch from date_trunc('week', to_timestamp(created_ts) AT TIME ZONE 'UTC')) AS BIGINT) AS week_start_ts, COUNT(*)
+		FROM issue
+		WHERE ((id <= 101 AND updater_id != 1) OR id > 101) AND created_ts >= $1
+		GROUP BY week_start_ts
+		ORDER BY week_start_ts`,
+		sinceTs,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var res []*metric.WeeklyIssueCountMetric
+
+	for rows.Next() {
+		var metric metric.WeeklyIssueCountMetric
+		if err := rows.Scan(&metric.WeekStartTs, &metric.Count); err != nil {
+			return nil, FormatError(err)
+		}
+		res = append(res, &metric)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return res, nil
+}
+
 // CreateIssueValidateOnly creates an issue for validation purpose
 // Do NOT write to the database.
 func (s *Store) CreateIssueValidateOnly(ctx context.Context, pipeline *api.Pipeline, create *api.IssueCreate, creatorID int) (*api.Issue, error) {
@@ -198,6 +240,7 @@ func (s *Store) CreateIssueValidateOnly(ctx context.Context, pipeline *api.Pipel
 		Type:        create.Type,
 		Description: create.Description,
 		AssigneeID:  create.AssigneeID,
+		LabelList:   create.LabelList,
 		PipelineID:  pipeline.ID,
 		Pipeline:    pipeline,
 	}
@@ -236,6 +279,8 @@ func (s *Store) CreatePipelineValidateOnly(ctx context.Context, create *api.Pipe
 			UpdatedTs:     ts,
 			PipelineID:    sc.PipelineID,
 			EnvironmentID: sc.EnvironmentID,
+			Paused:        sc.Paused,
+			SoakSeconds:   sc.SoakSeconds,
 		}
 		// We don't know IDs before inserting, so we use array index instead.
 		// indexBlockedByIndex[indexA] holds indices of the tasks that block taskList[indexA]
@@ -370,6 +415,12 @@ func (s *Store) composeIssue(ctx context.Context, raw *issueRaw) (*api.Issue, er
 	}
 	issue.Pipeline = pipeline
 
+	referenceList, err := s.FindIssueReferenceByIssueID(ctx, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	issue.ReferenceList = referenceList
+
 	return issue, nil
 }
 
@@ -560,6 +611,10 @@ func (*Store) createIssueImpl(ctx context.Context, tx *sql.Tx, create *api.Issue
 	if create.Payload == "" {
 		create.Payload = "{}"
 	}
+	labelList := create.LabelList
+	if labelList == nil {
+		labelList = []string{}
+	}
 	query := `
 		INSERT INTO issue (
 			creator_id,
@@ -571,12 +626,14 @@ func (*Store) createIssueImpl(ctx context.Context, tx *sql.Tx, create *api.Issue
 			type,
 			description,
 			assignee_id,
-			payload
+			payload,
+			label_list
 		)
-		VALUES ($1, $2, $3, $4, $5, 'OPEN', $6, $7, $8, $9)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, pipeline_id, name, status, type, description, assignee_id, payload
+		VALUES ($1, $2, $3, $4, $5, 'OPEN', $6, $7, $8, $9, $10)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, pipeline_id, name, status, type, description, assignee_id, payload, label_list
 	`
 	var issueRaw issueRaw
+	var txtArray pgtype.TextArray
 	if err := tx.QueryRowContext(ctx, query,
 		create.CreatorID,
 		create.CreatorID,
@@ -587,6 +644,7 @@ func (*Store) createIssueImpl(ctx context.Context, tx *sql.Tx, create *api.Issue
 		create.Description,
 		create.AssigneeID,
 		create.Payload,
+		labelList,
 	).Scan(
 		&issueRaw.ID,
 		&issueRaw.CreatorID,
@@ -601,12 +659,16 @@ func (*Store) createIssueImpl(ctx context.Context, tx *sql.Tx, create *api.Issue
 		&issueRaw.Description,
 		&issueRaw.AssigneeID,
 		&issueRaw.Payload,
+		&txtArray,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
 		}
 		return nil, FormatError(err)
 	}
+	if err := txtArray.AssignTo(&issueRaw.LabelList); err != nil {
+		return nil, FormatError(err)
+	}
 	return &issueRaw, nil
 }
 
@@ -622,12 +684,36 @@ func (*Store) findIssueImpl(ctx context.Context, tx *sql.Tx, find *api.IssueFind
 	if v := find.ProjectID; v != nil {
 		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id IN (SELECT id FROM project WHERE workspace_id = $%d AND row_status = 'NORMAL')", len(args)+1)), append(args, *v)
+	}
+	if v := find.After; v != nil {
+		where, args = append(where, fmt.Sprintf("(updated_ts, id) < ($%d, $%d)", len(args)+1, len(args)+2)), append(args, v.Ts, v.ID)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts < $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.PrincipalID; v != nil {
 		where = append(where, fmt.Sprintf("(creator_id = $%d OR assignee_id = $%d OR EXISTS (SELECT 1 FROM issue_subscriber WHERE issue_id = issue.id AND subscriber_id = $%d))", len(args)+1, len(args)+2, len(args)+3))
 		args = append(args, *v)
 		args = append(args, *v)
 		args = append(args, *v)
 	}
+	if v := find.AssigneeID; v != nil {
+		where, args = append(where, fmt.Sprintf("assignee_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.EnvironmentID; v != nil {
+		where, args = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM stage WHERE stage.pipeline_id = issue.pipeline_id AND stage.environment_id = $%d)", len(args)+1)), append(args, *v)
+	}
+	if v := find.Label; v != nil {
+		where, args = append(where, fmt.Sprintf("label_list @> ARRAY[$%d::TEXT]", len(args)+1)), append(args, *v)
+	}
+	if v := find.Reference; v != nil {
+		where, args = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM issue_reference WHERE issue_reference.issue_id = issue.id AND issue_reference.type = $%d AND issue_reference.target_id = $%d)", len(args)+1, len(args)+2)), append(args, v.Type, v.TargetID)
+	}
 	if len(find.StatusList) != 0 {
 		list := []string{}
 		for _, status := range find.StatusList {
@@ -651,10 +737,11 @@ func (*Store) findIssueImpl(ctx context.Context, tx *sql.Tx, find *api.IssueFind
 			type,
 			description,
 			assignee_id,
-			payload
+			payload,
+			label_list
 		FROM issue
 		WHERE ` + strings.Join(where, " AND ")
-	query += " ORDER BY updated_ts DESC"
+	query += " ORDER BY updated_ts DESC, id DESC"
 	if v := find.Limit; v != nil {
 		query += fmt.Sprintf(" LIMIT %d", *v)
 	}
@@ -669,6 +756,7 @@ func (*Store) findIssueImpl(ctx context.Context, tx *sql.Tx, find *api.IssueFind
 	var issuerRawList []*issueRaw
 	for rows.Next() {
 		var issueRaw issueRaw
+		var txtArray pgtype.TextArray
 		if err := rows.Scan(
 			&issueRaw.ID,
 			&issueRaw.CreatorID,
@@ -683,9 +771,13 @@ func (*Store) findIssueImpl(ctx context.Context, tx *sql.Tx, find *api.IssueFind
 			&issueRaw.Description,
 			&issueRaw.AssigneeID,
 			&issueRaw.Payload,
+			&txtArray,
 		); err != nil {
 			return nil, FormatError(err)
 		}
+		if err := txtArray.AssignTo(&issueRaw.LabelList); err != nil {
+			return nil, FormatError(err)
+		}
 
 		issuerRawList = append(issuerRawList, &issueRaw)
 	}
@@ -719,16 +811,20 @@ func (*Store) patchIssueImpl(ctx context.Context, tx *sql.Tx, patch *api.IssuePa
 		}
 		set, args = append(set, fmt.Sprintf("payload = $%d", len(args)+1)), append(args, payload)
 	}
+	if v := patch.LabelList; v != nil {
+		set, args = append(set, fmt.Sprintf("label_list = $%d", len(args)+1)), append(args, splitList(*v))
+	}
 
 	args = append(args, patch.ID)
 
 	var issueRaw issueRaw
+	var txtArray pgtype.TextArray
 	// Execute update query with RETURNING.
 	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
 		UPDATE issue
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, pipeline_id, name, status, type, description, assignee_id, payload
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, pipeline_id, name, status, type, description, assignee_id, payload, label_list
 	`, len(args)),
 		args...,
 	).Scan(
@@ -745,11 +841,15 @@ func (*Store) patchIssueImpl(ctx context.Context, tx *sql.Tx, patch *api.IssuePa
 		&issueRaw.Description,
 		&issueRaw.AssigneeID,
 		&issueRaw.Payload,
+		&txtArray,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("unable to find issue ID to update: %d", patch.ID)}
 		}
 		return nil, FormatError(err)
 	}
+	if err := txtArray.AssignTo(&issueRaw.LabelList); err != nil {
+		return nil, FormatError(err)
+	}
 	return &issueRaw, nil
 }
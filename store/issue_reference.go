@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// SetIssueReferenceList replaces the references of the issue with the list.
+func (s *Store) SetIssueReferenceList(ctx context.Context, issueID int, referenceList []*api.IssueReference) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM issue_reference WHERE issue_id = $1`, issueID); err != nil {
+		return FormatError(err)
+	}
+	for _, reference := range referenceList {
+		if _, err := tx.PTx.ExecContext(ctx, `
+			INSERT INTO issue_reference (
+				issue_id,
+				type,
+				target_id,
+				text
+			)
+			VALUES ($1, $2, $3, $4)
+		`,
+			issueID,
+			reference.Type,
+			reference.TargetID,
+			reference.Text,
+		); err != nil {
+			return FormatError(err)
+		}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// FindIssueReferenceByIssueID finds the references of the issue with the current names of the targets.
+// The references to the deleted targets are skipped.
+func (s *Store) FindIssueReferenceByIssueID(ctx context.Context, issueID int) ([]*api.IssueReference, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	return findIssueReferenceImpl(ctx, tx.PTx, issueID)
+}
+
+func findIssueReferenceImpl(ctx context.Context, tx *sql.Tx, issueID int) ([]*api.IssueReference, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			issue_reference.issue_id,
+			issue_reference.type,
+			issue_reference.text,
+			issue_reference.target_id,
+			COALESCE(issue.name, db.name, sheet.name)
+		FROM issue_reference
+		LEFT JOIN issue ON issue_reference.type = 'ISSUE' AND issue.id = issue_reference.target_id
+		LEFT JOIN db ON issue_reference.type = 'DATABASE' AND db.id = issue_reference.target_id
+		LEFT JOIN sheet ON issue_reference.type = 'SHEET' AND sheet.id = issue_reference.target_id AND sheet.row_status = 'NORMAL'
+		WHERE issue_reference.issue_id = $1 AND COALESCE(issue.name, db.name, sheet.name) IS NOT NULL
+		ORDER BY issue_reference.type, issue_reference.target_id`,
+		issueID,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var referenceList []*api.IssueReference
+	for rows.Next() {
+		var reference api.IssueReference
+		if err := rows.Scan(
+			&reference.IssueID,
+			&reference.Type,
+			&reference.Text,
+			&reference.TargetID,
+			&reference.Name,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		referenceList = append(referenceList, &reference)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return referenceList, nil
+}
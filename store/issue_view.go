@@ -0,0 +1,329 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// issueViewRaw is the store model for an IssueView.
+// Fields have exactly the same meanings as IssueView.
+type issueViewRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID   int
+	CreatedTs   int64
+	UpdaterID   int
+	UpdatedTs   int64
+	WorkspaceID int
+
+	// Related fields
+	ProjectID *int
+
+	// Domain specific fields
+	Name   string
+	Filter string
+}
+
+// toIssueView creates an instance of IssueView based on the issueViewRaw.
+// This is intended to be called when we need to compose an IssueView relationship.
+func (raw *issueViewRaw) toIssueView() *api.IssueView {
+	return &api.IssueView{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID:   raw.CreatorID,
+		CreatedTs:   raw.CreatedTs,
+		UpdaterID:   raw.UpdaterID,
+		UpdatedTs:   raw.UpdatedTs,
+		WorkspaceID: raw.WorkspaceID,
+
+		// Related fields
+		ProjectID: raw.ProjectID,
+
+		// Domain specific fields
+		Name:   raw.Name,
+		Filter: raw.Filter,
+	}
+}
+
+// CreateIssueView creates an instance of IssueView.
+func (s *Store) CreateIssueView(ctx context.Context, create *api.IssueViewCreate) (*api.IssueView, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	issueViewRaw, err := createIssueViewImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create IssueView with IssueViewCreate[%+v]", create)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	issueView, err := s.composeIssueView(ctx, issueViewRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose IssueView with issueViewRaw[%+v]", issueViewRaw)
+	}
+	return issueView, nil
+}
+
+// GetIssueViewByID gets an instance of IssueView.
+func (s *Store) GetIssueViewByID(ctx context.Context, id int) (*api.IssueView, error) {
+	list, err := s.FindIssueView(ctx, &api.IssueViewFind{ID: &id})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get IssueView with ID %d", id)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// FindIssueView finds a list of IssueView instances.
+func (s *Store) FindIssueView(ctx context.Context, find *api.IssueViewFind) ([]*api.IssueView, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	issueViewRawList, err := findIssueViewImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find IssueView list with IssueViewFind[%+v]", find)
+	}
+	var issueViewList []*api.IssueView
+	for _, raw := range issueViewRawList {
+		issueView, err := s.composeIssueView(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose IssueView with issueViewRaw[%+v]", raw)
+		}
+		issueViewList = append(issueViewList, issueView)
+	}
+	return issueViewList, nil
+}
+
+// PatchIssueView patches an instance of IssueView.
+func (s *Store) PatchIssueView(ctx context.Context, patch *api.IssueViewPatch) (*api.IssueView, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	issueViewRaw, err := patchIssueViewImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch IssueView with IssueViewPatch[%+v]", patch)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	issueView, err := s.composeIssueView(ctx, issueViewRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose IssueView with issueViewRaw[%+v]", issueViewRaw)
+	}
+	return issueView, nil
+}
+
+// DeleteIssueView deletes an existing issue view by ID.
+// Returns ENOTFOUND if the issue view does not exist.
+func (s *Store) DeleteIssueView(ctx context.Context, delete *api.IssueViewDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `DELETE FROM issue_view WHERE id = $1`, delete.ID)
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("issue view ID not found: %d", delete.ID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+//
+// private function
+//
+
+func (s *Store) composeIssueView(ctx context.Context, raw *issueViewRaw) (*api.IssueView, error) {
+	issueView := raw.toIssueView()
+
+	creator, err := s.GetPrincipalByID(ctx, issueView.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	issueView.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, issueView.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	issueView.Updater = updater
+
+	return issueView, nil
+}
+
+// createIssueViewImpl creates a new issue view.
+func createIssueViewImpl(ctx context.Context, tx *sql.Tx, create *api.IssueViewCreate) (*issueViewRaw, error) {
+	if create.Filter == "" {
+		create.Filter = "{}"
+	}
+	// Insert row into database.
+	query := `
+		INSERT INTO issue_view (
+			creator_id,
+			updater_id,
+			workspace_id,
+			project_id,
+			name,
+			filter
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, workspace_id, project_id, name, filter
+	`
+	row := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.WorkspaceID,
+		create.ProjectID,
+		create.Name,
+		create.Filter,
+	)
+	issueViewRaw, err := scanIssueViewRaw(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return issueViewRaw, nil
+}
+
+func findIssueViewImpl(ctx context.Context, tx *sql.Tx, find *api.IssueViewFind) ([]*issueViewRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("workspace_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			workspace_id,
+			project_id,
+			name,
+			filter
+		FROM issue_view
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY name ASC, id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into issueViewRawList.
+	var issueViewRawList []*issueViewRaw
+	for rows.Next() {
+		issueViewRaw, err := scanIssueViewRaw(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		issueViewRawList = append(issueViewRawList, issueViewRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return issueViewRawList, nil
+}
+
+// patchIssueViewImpl updates an issue view by ID. Returns the new state of the issue view after update.
+func patchIssueViewImpl(ctx context.Context, tx *sql.Tx, patch *api.IssueViewPatch) (*issueViewRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Filter; v != nil {
+		set, args = append(set, fmt.Sprintf("filter = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	// Execute update query with RETURNING.
+	row := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE issue_view
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, workspace_id, project_id, name, filter
+	`, len(args)),
+		args...,
+	)
+	issueViewRaw, err := scanIssueViewRaw(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("issue view ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return issueViewRaw, nil
+}
+
+func scanIssueViewRaw(row interface {
+	Scan(dest ...interface{}) error
+}) (*issueViewRaw, error) {
+	var issueViewRaw issueViewRaw
+	var projectID sql.NullInt32
+	if err := row.Scan(
+		&issueViewRaw.ID,
+		&issueViewRaw.CreatorID,
+		&issueViewRaw.CreatedTs,
+		&issueViewRaw.UpdaterID,
+		&issueViewRaw.UpdatedTs,
+		&issueViewRaw.WorkspaceID,
+		&projectID,
+		&issueViewRaw.Name,
+		&issueViewRaw.Filter,
+	); err != nil {
+		return nil, err
+	}
+	if projectID.Valid {
+		id := int(projectID.Int32)
+		issueViewRaw.ProjectID = &id
+	}
+	return &issueViewRaw, nil
+}
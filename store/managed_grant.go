@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// UpsertManagedGrant creates the managed grant, or updates its privileges if the grantee is already managed on the database.
+func (s *Store) UpsertManagedGrant(ctx context.Context, upsert *api.ManagedGrantUpsert) (*api.ManagedGrant, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	managedGrant, err := upsertManagedGrantImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert ManagedGrant %q of database ID %d", upsert.Grantee, upsert.DatabaseID)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := s.composeManagedGrant(ctx, managedGrant); err != nil {
+		return nil, err
+	}
+	return managedGrant, nil
+}
+
+// FindManagedGrant finds a list of ManagedGrant instances ordered by grantee.
+func (s *Store) FindManagedGrant(ctx context.Context, find *api.ManagedGrantFind) ([]*api.ManagedGrant, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findManagedGrantImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find ManagedGrant list with ManagedGrantFind[%+v]", find)
+	}
+
+	for _, managedGrant := range list {
+		if err := s.composeManagedGrant(ctx, managedGrant); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// DeleteManagedGrant deletes an existing managed grant, the actual grants on the database are left as is.
+func (s *Store) DeleteManagedGrant(ctx context.Context, delete *api.ManagedGrantDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `DELETE FROM managed_grant WHERE id = $1 AND database_id = $2`, delete.ID, delete.DatabaseID)
+	if err != nil {
+		return FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("managed grant ID %d not found in database ID %d", delete.ID, delete.DatabaseID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+func (s *Store) composeManagedGrant(ctx context.Context, managedGrant *api.ManagedGrant) error {
+	creator, err := s.GetPrincipalByID(ctx, managedGrant.CreatorID)
+	if err != nil {
+		return err
+	}
+	managedGrant.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, managedGrant.UpdaterID)
+	if err != nil {
+		return err
+	}
+	managedGrant.Updater = updater
+	return nil
+}
+
+func upsertManagedGrantImpl(ctx context.Context, tx *sql.Tx, upsert *api.ManagedGrantUpsert) (*api.ManagedGrant, error) {
+	privilegeList := upsert.PrivilegeList
+	if privilegeList == nil {
+		privilegeList = []string{}
+	}
+	query := `
+		INSERT INTO managed_grant (
+			creator_id,
+			updater_id,
+			database_id,
+			grantee,
+			privilege_list
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(database_id, grantee) DO UPDATE SET
+			updater_id = excluded.updater_id,
+			privilege_list = excluded.privilege_list
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, grantee, privilege_list
+	`
+	var managedGrant api.ManagedGrant
+	var txtArray pgtype.TextArray
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.DatabaseID,
+		upsert.Grantee,
+		privilegeList,
+	).Scan(
+		&managedGrant.ID,
+		&managedGrant.CreatorID,
+		&managedGrant.CreatedTs,
+		&managedGrant.UpdaterID,
+		&managedGrant.UpdatedTs,
+		&managedGrant.DatabaseID,
+		&managedGrant.Grantee,
+		&txtArray,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	if err := txtArray.AssignTo(&managedGrant.PrivilegeList); err != nil {
+		return nil, FormatError(err)
+	}
+	return &managedGrant, nil
+}
+
+func findManagedGrantImpl(ctx context.Context, tx *sql.Tx, find *api.ManagedGrantFind) ([]*api.ManagedGrant, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			grantee,
+			privilege_list
+		FROM managed_grant
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY grantee`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into managedGrantList.
+	var managedGrantList []*api.ManagedGrant
+	for rows.Next() {
+		var managedGrant api.ManagedGrant
+		var txtArray pgtype.TextArray
+		if err := rows.Scan(
+			&managedGrant.ID,
+			&managedGrant.CreatorID,
+			&managedGrant.CreatedTs,
+			&managedGrant.UpdaterID,
+			&managedGrant.UpdatedTs,
+			&managedGrant.DatabaseID,
+			&managedGrant.Grantee,
+			&txtArray,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if err := txtArray.AssignTo(&managedGrant.PrivilegeList); err != nil {
+			return nil, FormatError(err)
+		}
+
+		managedGrantList = append(managedGrantList, &managedGrant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return managedGrantList, nil
+}
@@ -25,6 +25,7 @@ type memberRaw struct {
 	UpdatedTs int64
 
 	// Domain specific fields
+	WorkspaceID int
 	Status      api.MemberStatus
 	Role        api.Role
 	PrincipalID int
@@ -44,6 +45,7 @@ func (raw *memberRaw) toMember() *api.Member {
 		UpdatedTs: raw.UpdatedTs,
 
 		// Domain specific fields
+		WorkspaceID: raw.WorkspaceID,
 		Status:      raw.Status,
 		Role:        raw.Role,
 		PrincipalID: raw.PrincipalID,
@@ -303,17 +305,19 @@ func createMemberImpl(ctx context.Context, tx *sql.Tx, create *api.MemberCreate)
 		INSERT INTO member (
 			creator_id,
 			updater_id,
+			workspace_id,
 			status,
 			role,
 			principal_id
 		)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, status, role, principal_id
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, status, role, principal_id
 	`
 	var memberRaw memberRaw
 	if err := tx.QueryRowContext(ctx, query,
 		create.CreatorID,
 		create.CreatorID,
+		create.WorkspaceID,
 		create.Status,
 		create.Role,
 		create.PrincipalID,
@@ -324,6 +328,7 @@ func createMemberImpl(ctx context.Context, tx *sql.Tx, create *api.MemberCreate)
 		&memberRaw.CreatedTs,
 		&memberRaw.UpdaterID,
 		&memberRaw.UpdatedTs,
+		&memberRaw.WorkspaceID,
 		&memberRaw.Status,
 		&memberRaw.Role,
 		&memberRaw.PrincipalID,
@@ -345,6 +350,9 @@ func findMemberImpl(ctx context.Context, tx *sql.Tx, find *api.MemberFind) ([]*m
 	if v := find.PrincipalID; v != nil {
 		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("workspace_id = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.Role; v != nil {
 		where, args = append(where, fmt.Sprintf("role = $%d", len(args)+1)), append(args, *v)
 	}
@@ -357,6 +365,7 @@ func findMemberImpl(ctx context.Context, tx *sql.Tx, find *api.MemberFind) ([]*m
 			created_ts,
 			updater_id,
 			updated_ts,
+			workspace_id,
 			status,
 			role,
 			principal_id
@@ -380,6 +389,7 @@ func findMemberImpl(ctx context.Context, tx *sql.Tx, find *api.MemberFind) ([]*m
 			&memberRaw.CreatedTs,
 			&memberRaw.UpdaterID,
 			&memberRaw.UpdatedTs,
+			&memberRaw.WorkspaceID,
 			&memberRaw.Status,
 			&memberRaw.Role,
 			&memberRaw.PrincipalID,
@@ -415,7 +425,7 @@ func patchMemberImpl(ctx context.Context, tx *sql.Tx, patch *api.MemberPatch) (*
 		UPDATE member
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, status, role, principal_id
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, status, role, principal_id
 	`, len(args)),
 		args...,
 	).Scan(
@@ -425,6 +435,7 @@ func patchMemberImpl(ctx context.Context, tx *sql.Tx, patch *api.MemberPatch) (*
 		&memberRaw.CreatedTs,
 		&memberRaw.UpdaterID,
 		&memberRaw.UpdatedTs,
+		&memberRaw.WorkspaceID,
 		&memberRaw.Status,
 		&memberRaw.Role,
 		&memberRaw.PrincipalID,
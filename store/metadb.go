@@ -136,16 +136,36 @@ func (m *MetadataDB) connectExternal(readonly bool, version string) (*DB, error)
 	}
 	connCfg.Database = u.Path[1:]
 
-	connCfg.TLSConfig = dbdriver.TLSConfig{
-		SslCA:   q.Get("sslrootcert"),
-		SslKey:  q.Get("sslkey"),
-		SslCert: q.Get("sslcert"),
+	// The sslrootcert, sslcert and sslkey are the file paths as in libpq, while the TLS config carries their contents.
+	if connCfg.TLSConfig.SslCA, err = readTLSFile(q.Get("sslrootcert")); err != nil {
+		return nil, err
+	}
+	if connCfg.TLSConfig.SslCert, err = readTLSFile(q.Get("sslcert")); err != nil {
+		return nil, err
+	}
+	if connCfg.TLSConfig.SslKey, err = readTLSFile(q.Get("sslkey")); err != nil {
+		return nil, err
+	}
+	if err := connCfg.TLSConfig.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid SSL config in the --pg connection string")
 	}
 
 	db := NewDB(connCfg, m.pgInstance.BaseDir, m.demoDataDir, readonly, version, m.mode)
 	return db, nil
 }
 
+// readTLSFile reads the PEM encoded file, empty path returns empty content.
+func readTLSFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read SSL file %q", path)
+	}
+	return string(content), nil
+}
+
 // Close will stop postgres server if using embed postgres.
 func (m *MetadataDB) Close() error {
 	if !m.pgStarted {
@@ -0,0 +1,2 @@
+ALTER TABLE db ADD COLUMN owner_id INTEGER REFERENCES principal (id);
+ALTER TABLE db ADD COLUMN on_call TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,29 @@
+-- recurring_task table stores the housekeeping DML statements run on the database following the cron schedule.
+CREATE TABLE recurring_task (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    name TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    schedule TEXT NOT NULL,
+    -- 0 means unlimited.
+    max_affected_rows BIGINT NOT NULL DEFAULT 0 CHECK (max_affected_rows >= 0),
+    next_run_ts BIGINT NOT NULL,
+    last_issue_id INTEGER REFERENCES issue (id)
+);
+
+CREATE INDEX idx_recurring_task_database_id ON recurring_task(database_id);
+
+CREATE INDEX idx_recurring_task_next_run_ts ON recurring_task(next_run_ts);
+
+ALTER SEQUENCE recurring_task_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_recurring_task_updated_ts
+BEFORE
+UPDATE
+    ON recurring_task FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,20 @@
+-- project_sql_review_override table stores the project level overrides on the environment SQL review policy.
+CREATE TABLE project_sql_review_override (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_project_sql_review_override_unique_project_id ON project_sql_review_override(project_id);
+
+ALTER SEQUENCE project_sql_review_override_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_project_sql_review_override_updated_ts
+BEFORE
+UPDATE
+    ON project_sql_review_override FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
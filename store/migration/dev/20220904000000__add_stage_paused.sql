@@ -0,0 +1 @@
+ALTER TABLE stage ADD COLUMN paused BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,22 @@
+-- task_run_artifact table stores the artifacts attached to the task run by the executor, e.g. the gh-ost log.
+-- The artifact content is stored in the blob storage, and the row is deleted when the artifact expires.
+CREATE TABLE task_run_artifact (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    task_run_id INTEGER NOT NULL REFERENCES task_run (id),
+    task_id INTEGER NOT NULL REFERENCES task (id),
+    type TEXT NOT NULL CHECK (type LIKE 'bb.task-run-artifact.%'),
+    name TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    storage_backend TEXT NOT NULL CHECK (storage_backend IN ('LOCAL', 'S3', 'GCS', 'OSS')),
+    path TEXT NOT NULL,
+    reference BOOLEAN NOT NULL DEFAULT FALSE,
+    expire_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_task_run_artifact_task_id ON task_run_artifact(task_id);
+
+CREATE INDEX idx_task_run_artifact_expire_ts ON task_run_artifact(expire_ts);
+
+ALTER SEQUENCE task_run_artifact_id_seq RESTART WITH 101;
@@ -0,0 +1,13 @@
+-- usage_metric table stores the workspace usage metrics collected by the metric reporter, e.g. the instance count.
+-- The metrics are kept locally for the usage dashboard regardless of whether they are sent outside the workspace.
+CREATE TABLE usage_metric (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    name TEXT NOT NULL,
+    value INTEGER NOT NULL,
+    labels JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_usage_metric_created_ts ON usage_metric(created_ts);
+
+ALTER SEQUENCE usage_metric_id_seq RESTART WITH 101;
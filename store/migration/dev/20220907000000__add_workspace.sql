@@ -0,0 +1,49 @@
+-- Workspace is the isolated tenant in a deployment, the existing resources are migrated to the default workspace.
+CREATE TABLE workspace (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_workspace_unique_name ON workspace(name);
+
+INSERT INTO
+    workspace (
+        id,
+        creator_id,
+        updater_id,
+        name
+    )
+VALUES
+    (
+        1,
+        1,
+        1,
+        'Default'
+    );
+
+ALTER SEQUENCE workspace_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_workspace_updated_ts
+BEFORE
+UPDATE
+    ON workspace FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+ALTER TABLE member ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id);
+CREATE INDEX idx_member_workspace_id ON member(workspace_id);
+
+ALTER TABLE environment ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id);
+DROP INDEX idx_environment_unique_name;
+CREATE UNIQUE INDEX idx_environment_unique_workspace_id_name ON environment(workspace_id, name);
+
+ALTER TABLE project ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id);
+DROP INDEX idx_project_unique_key;
+CREATE UNIQUE INDEX idx_project_unique_workspace_id_key ON project(workspace_id, key);
+
+ALTER TABLE instance ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id);
+CREATE INDEX idx_instance_workspace_id ON instance(workspace_id);
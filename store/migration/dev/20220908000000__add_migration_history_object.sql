@@ -0,0 +1,16 @@
+-- migration_history_object table stores the tables and columns referenced by the statement of a migration history,
+-- which are extracted by the parser when the migration is executed.
+-- The migration history lives in the instance, so migration_history_id refers to the migration history ID there.
+-- column_name is empty if the whole table is referenced, e.g. dropping or renaming the table.
+CREATE TABLE migration_history_object (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    migration_history_id INTEGER NOT NULL,
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_migration_history_object_database_id_table_name_column_name ON migration_history_object(database_id, table_name, column_name);
+
+ALTER SEQUENCE migration_history_object_id_seq RESTART WITH 101;
@@ -0,0 +1,4 @@
+-- schema_name is the schema of the referenced table, it's empty if the statement doesn't qualify the table with a schema.
+ALTER TABLE migration_history_object ADD COLUMN schema_name TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_migration_history_object_database_id_migration_history_id ON migration_history_object(database_id, migration_history_id);
@@ -0,0 +1,24 @@
+-- query_audit_log table stores the SQL editor executions and the data exports of the query results for compliance.
+-- It's kept separately from the activity table, and purged after the retention period.
+-- fingerprint is the statement with the literals replaced by placeholders, so that the same query with different values can be grouped.
+CREATE TABLE query_audit_log (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    type TEXT NOT NULL CHECK (type IN ('QUERY', 'EXPORT')),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    database_name TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    duration_ns BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_query_audit_log_created_ts ON query_audit_log(created_ts);
+
+CREATE INDEX idx_query_audit_log_creator_id ON query_audit_log(creator_id);
+
+CREATE INDEX idx_query_audit_log_instance_id_database_name ON query_audit_log(instance_id, database_name);
+
+ALTER SEQUENCE query_audit_log_id_seq RESTART WITH 101;
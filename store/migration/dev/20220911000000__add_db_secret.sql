@@ -0,0 +1,25 @@
+-- db_secret stores the secrets of the databases, e.g. a tenant encryption key.
+-- The secrets are referenced in the statements as {{secret.NAME}} and injected at the execution time,
+-- so that the values are never recorded in the migration history.
+CREATE TABLE db_secret (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+-- database_id/name's are unique within the db_secret table.
+CREATE UNIQUE INDEX idx_db_secret_unique_database_id_name ON db_secret(database_id, name);
+
+ALTER SEQUENCE db_secret_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_secret_updated_ts
+BEFORE
+UPDATE
+    ON db_secret FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
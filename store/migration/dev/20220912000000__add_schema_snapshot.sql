@@ -0,0 +1,25 @@
+-- schema_snapshot stores the gzip compressed schema snapshots of the databases.
+-- A snapshot is taken for each database every day and after each migration, so that the schema can be browsed at any point in time.
+CREATE TABLE schema_snapshot (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- Enum: DAILY, MIGRATION
+    type TEXT NOT NULL CHECK (type IN ('DAILY', 'MIGRATION')),
+    -- The migration version for the MIGRATION snapshot.
+    migration_version TEXT NOT NULL DEFAULT '',
+    schema BYTEA NOT NULL
+);
+
+CREATE INDEX idx_schema_snapshot_database_id_created_ts ON schema_snapshot(database_id, created_ts);
+
+ALTER SEQUENCE schema_snapshot_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_snapshot_updated_ts
+BEFORE
+UPDATE
+    ON schema_snapshot FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
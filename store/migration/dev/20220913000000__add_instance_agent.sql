@@ -0,0 +1,24 @@
+-- instance_agent stores the agents of the instances in the private networks unreachable from Bytebase.
+-- The agent connects out to Bytebase with the token, and relays the database connections of the instance.
+CREATE TABLE instance_agent (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    -- The SHA-256 hex digest of the agent token, the token itself is only returned once on creation.
+    token_hash TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_instance_agent_unique_instance_id ON instance_agent(instance_id);
+
+CREATE UNIQUE INDEX idx_instance_agent_unique_token_hash ON instance_agent(token_hash);
+
+ALTER SEQUENCE instance_agent_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_instance_agent_updated_ts
+BEFORE
+UPDATE
+    ON instance_agent FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
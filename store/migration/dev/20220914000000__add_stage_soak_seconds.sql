@@ -0,0 +1 @@
+ALTER TABLE stage ADD COLUMN soak_seconds BIGINT NOT NULL DEFAULT 0;
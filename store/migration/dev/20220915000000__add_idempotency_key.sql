@@ -0,0 +1,24 @@
+-- idempotency_key stores the responses of the create requests sent with the Idempotency-Key header,
+-- so that the retried requests with the same key are answered with the stored response instead of creating duplicates.
+-- The key is scoped to the principal sending the request, and purged after it expires.
+CREATE TABLE idempotency_key (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    key TEXT NOT NULL,
+    -- The method and the route of the request, e.g. "POST /api/issue".
+    request TEXT NOT NULL,
+    -- The SHA-256 hex digest of the request body, to reject the key reused for a different request.
+    request_hash TEXT NOT NULL,
+    -- The status code is 0 while the request is still in progress.
+    status_code INTEGER NOT NULL DEFAULT 0,
+    content_type TEXT NOT NULL DEFAULT '',
+    response TEXT NOT NULL DEFAULT '',
+    expire_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_idempotency_key_unique_creator_id_key ON idempotency_key(creator_id, key);
+
+CREATE INDEX idx_idempotency_key_expire_ts ON idempotency_key(expire_ts);
+
+ALTER SEQUENCE idempotency_key_id_seq RESTART WITH 101;
@@ -0,0 +1,4 @@
+ALTER TABLE task DROP CONSTRAINT task_status_check;
+ALTER TABLE task ADD CONSTRAINT task_status_check CHECK (status IN ('PENDING', 'PENDING_APPROVAL', 'RUNNING', 'DONE', 'FAILED', 'CANCELED', 'PAUSED'));
+ALTER TABLE task_run DROP CONSTRAINT task_run_status_check;
+ALTER TABLE task_run ADD CONSTRAINT task_run_status_check CHECK (status IN ('RUNNING', 'DONE', 'FAILED', 'CANCELED', 'PAUSED'));
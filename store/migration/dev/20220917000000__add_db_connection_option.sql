@@ -0,0 +1 @@
+ALTER TABLE db ADD COLUMN connection_option JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,28 @@
+-- database_template table stores the templates for provisioning new databases consistently, e.g. the tenant databases.
+-- The extensions are only applicable to Postgres, and the grantees are only applicable to MySQL, TiDB and Postgres.
+-- sheet_id refers to the sheet holding the baseline schema applied to the new database, NULL means an empty database.
+CREATE TABLE database_template (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id),
+    name TEXT NOT NULL,
+    engine TEXT NOT NULL CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE')),
+    character_set TEXT NOT NULL DEFAULT '',
+    "collation" TEXT NOT NULL DEFAULT '',
+    extension_list TEXT ARRAY NOT NULL DEFAULT '{}',
+    grantee_list TEXT ARRAY NOT NULL DEFAULT '{}',
+    sheet_id INTEGER
+);
+
+CREATE UNIQUE INDEX idx_database_template_unique_workspace_id_name ON database_template(workspace_id, name);
+
+ALTER SEQUENCE database_template_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_database_template_updated_ts
+BEFORE
+UPDATE
+    ON database_template FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,23 @@
+-- managed_grant table stores the privileges declared for the application roles on the databases.
+-- The actual grants are compared with them on the database sync, and the difference is reported as a grant drift anomaly.
+-- grantee is the role name for Postgres, and "user@host" for MySQL and TiDB.
+CREATE TABLE managed_grant (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    grantee TEXT NOT NULL,
+    privilege_list TEXT ARRAY NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_managed_grant_unique_database_id_grantee ON managed_grant(database_id, grantee);
+
+ALTER SEQUENCE managed_grant_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_managed_grant_updated_ts
+BEFORE
+UPDATE
+    ON managed_grant FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,2 @@
+-- progress saves the progress reported by the executor of the running task run periodically in json format.
+ALTER TABLE task_run ADD COLUMN progress JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+ALTER TABLE member DROP CONSTRAINT member_role_check;
+ALTER TABLE member ADD CONSTRAINT member_role_check CHECK (role IN ('OWNER', 'DBA', 'DEVELOPER', 'AUDITOR'));
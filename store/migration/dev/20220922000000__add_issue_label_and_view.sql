@@ -0,0 +1,30 @@
+ALTER TABLE issue ADD COLUMN label_list TEXT ARRAY NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_issue_label_list ON issue USING GIN(label_list);
+
+-- issue_view table stores the saved issue filters, e.g. the columns of the kanban board.
+-- The view with project_id filters the issues of the project and is shared with the workspace, otherwise it's private to the creator.
+-- filter is the json-encoded api.IssueViewFilter.
+CREATE TABLE issue_view (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id),
+    project_id INTEGER REFERENCES project (id),
+    name TEXT NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_issue_view_creator_id ON issue_view(creator_id);
+
+CREATE INDEX idx_issue_view_project_id ON issue_view(project_id);
+
+ALTER SEQUENCE issue_view_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_view_updated_ts
+BEFORE
+UPDATE
+    ON issue_view FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,2 @@
+-- backup_storage_backend is empty for the storage backend configured for the server.
+ALTER TABLE instance ADD COLUMN backup_storage_backend TEXT NOT NULL DEFAULT '' CHECK (backup_storage_backend IN ('', 'LOCAL', 'S3', 'GCS'));
@@ -0,0 +1,23 @@
+-- report_subscription table stores the subscriptions of the principals to the weekly reports of the projects.
+-- last_sent_ts is when the last report was sent, 0 means never.
+CREATE TABLE report_subscription (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_report_subscription_unique_project_id_principal_id ON report_subscription(project_id, principal_id);
+
+ALTER SEQUENCE report_subscription_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_report_subscription_updated_ts
+BEFORE
+UPDATE
+    ON report_subscription FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,2 @@
+-- heartbeat_ts is when the executor last reported the running task run alive, 0 means no executor is running it.
+ALTER TABLE task_run ADD COLUMN heartbeat_ts BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- ssh_host is empty for connecting the instance directly.
+ALTER TABLE instance ADD COLUMN ssh_host TEXT NOT NULL DEFAULT '';
+ALTER TABLE instance ADD COLUMN ssh_port TEXT NOT NULL DEFAULT '';
+ALTER TABLE instance ADD COLUMN ssh_user TEXT NOT NULL DEFAULT '';
+ALTER TABLE instance ADD COLUMN ssh_private_key TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,18 @@
+-- migration_history_statement table stores the execution duration of each statement of a migration history,
+-- so that the slow migrations can be analyzed later and compared across the environments.
+-- The migration history lives in the instance, so migration_history_id refers to the migration history ID there.
+-- statement_index is the 1-based position of the statement in the migration.
+CREATE TABLE migration_history_statement (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    migration_history_id INTEGER NOT NULL,
+    statement_index INTEGER NOT NULL,
+    statement TEXT NOT NULL,
+    duration_ns BIGINT NOT NULL,
+    rows_affected BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_migration_history_statement_database_id_history_id ON migration_history_statement(database_id, migration_history_id);
+
+ALTER SEQUENCE migration_history_statement_id_seq RESTART WITH 101;
@@ -0,0 +1,38 @@
+-- db_schema stores the schemas for a particular Postgres database.
+-- data is synced periodically from the instance.
+CREATE TABLE db_schema (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    owner TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_db_schema_unique_database_id_name ON db_schema(database_id, name);
+
+ALTER SEQUENCE db_schema_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_schema_updated_ts
+BEFORE
+UPDATE
+    ON db_schema FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- schema is the Postgres schema of the table or view, it's empty for the other engines.
+ALTER TABLE tbl ADD COLUMN schema TEXT NOT NULL DEFAULT '';
+ALTER TABLE vw ADD COLUMN schema TEXT NOT NULL DEFAULT '';
+
+-- The Postgres table and view names are qualified by the schema, e.g. public.t1.
+UPDATE tbl
+SET schema = split_part(tbl.name, '.', 1)
+FROM db, instance
+WHERE tbl.database_id = db.id AND db.instance_id = instance.id AND instance.engine = 'POSTGRES' AND strpos(tbl.name, '.') > 0;
+
+UPDATE vw
+SET schema = split_part(vw.name, '.', 1)
+FROM db, instance
+WHERE vw.database_id = db.id AND db.instance_id = instance.id AND instance.engine = 'POSTGRES' AND strpos(vw.name, '.') > 0;
@@ -0,0 +1,16 @@
+-- instance_disk_usage stores the disk usage samples of the instances, taken by the anomaly scanner to project when the disk fills up.
+-- total_bytes and free_bytes are reported by the instance agent running on the database host, 0 if unknown.
+-- data_bytes is the data and index size of the databases synced from the engine views.
+-- The samples are purged after the projection window.
+CREATE TABLE instance_disk_usage (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    total_bytes BIGINT NOT NULL DEFAULT 0,
+    free_bytes BIGINT NOT NULL DEFAULT 0,
+    data_bytes BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_instance_disk_usage_instance_id_created_ts ON instance_disk_usage(instance_id, created_ts);
+
+ALTER SEQUENCE instance_disk_usage_id_seq RESTART WITH 101;
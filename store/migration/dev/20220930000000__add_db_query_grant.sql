@@ -0,0 +1,27 @@
+-- db_query_grant table stores the grants allowing the developers to query the databases in the SQL editor.
+-- The grantee is either a principal or all the members of a role, exactly one of principal_id and role is set.
+-- The grant is requested as PENDING, and allows querying once APPROVED until expire_ts.
+CREATE TABLE db_query_grant (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    principal_id INTEGER REFERENCES principal (id),
+    role TEXT NOT NULL DEFAULT '' CHECK (role IN ('', 'DEVELOPER')),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'REVOKED')),
+    expire_ts BIGINT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    CHECK ((principal_id IS NULL) <> (role = ''))
+);
+
+CREATE INDEX idx_db_query_grant_database_id ON db_query_grant(database_id);
+
+ALTER SEQUENCE db_query_grant_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_query_grant_updated_ts
+BEFORE
+UPDATE
+    ON db_query_grant FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
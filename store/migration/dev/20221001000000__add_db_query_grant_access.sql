@@ -0,0 +1,8 @@
+-- A WRITE grant is the temporary elevated access to execute the write statements in the SQL editor, which is required by everyone.
+-- The approved grant is marked EXPIRED after expire_ts.
+ALTER TABLE db_query_grant ADD COLUMN access TEXT NOT NULL DEFAULT 'QUERY' CHECK (access IN ('QUERY', 'WRITE'));
+ALTER TABLE db_query_grant DROP CONSTRAINT db_query_grant_status_check;
+ALTER TABLE db_query_grant ADD CONSTRAINT db_query_grant_status_check CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'REVOKED', 'EXPIRED'));
+
+ALTER TABLE query_audit_log DROP CONSTRAINT query_audit_log_type_check;
+ALTER TABLE query_audit_log ADD CONSTRAINT query_audit_log_type_check CHECK (type IN ('QUERY', 'EXPORT', 'EXECUTE'));
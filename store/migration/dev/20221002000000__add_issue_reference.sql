@@ -0,0 +1,11 @@
+-- issue_reference stores the references in the issue description resolved to the targets, e.g. #123, db:prod/orders and sheet:123.
+-- The target_id has no foreign key because it refers to the issue, database or sheet depending on the type.
+CREATE TABLE issue_reference (
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    type TEXT NOT NULL CHECK (type IN ('ISSUE', 'DATABASE', 'SHEET')),
+    target_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (issue_id, type, target_id)
+);
+
+CREATE INDEX idx_issue_reference_type_target_id ON issue_reference(type, target_id);
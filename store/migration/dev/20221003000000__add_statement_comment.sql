@@ -0,0 +1,28 @@
+-- statement_comment stores the review comments on the lines of the task statements in the issues.
+-- statement_hash is the hash of the statement when the comment was created, the line is outdated once the statement changes.
+-- The unresolved comments of a task block approving the task.
+CREATE TABLE statement_comment (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    task_id INTEGER NOT NULL REFERENCES task (id),
+    statement_hash TEXT NOT NULL,
+    line INTEGER NOT NULL CHECK (line > 0),
+    content TEXT NOT NULL,
+    resolved BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_statement_comment_issue_id ON statement_comment(issue_id);
+
+CREATE INDEX idx_statement_comment_task_id ON statement_comment(task_id);
+
+ALTER SEQUENCE statement_comment_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_statement_comment_updated_ts
+BEFORE
+UPDATE
+    ON statement_comment FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,3 @@
+-- The VCS belongs to the workspace of its creator, the existing ones are migrated to the default workspace.
+ALTER TABLE vcs ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id);
+CREATE INDEX idx_vcs_workspace_id ON vcs(workspace_id);
@@ -0,0 +1,2 @@
+-- ssh_host_key is the public key or the SHA256 fingerprint of the SSH server, the existing SSH tunnels fail until it's set.
+ALTER TABLE instance ADD COLUMN ssh_host_key TEXT NOT NULL DEFAULT '';
@@ -79,8 +79,46 @@ UPDATE
     ON setting FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- Workspace
+-- workspace is the isolated tenant in a deployment, the resources created before multi-workspace support belong to the default workspace.
+CREATE TABLE workspace (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_workspace_unique_name ON workspace(name);
+
+INSERT INTO
+    workspace (
+        id,
+        creator_id,
+        updater_id,
+        name
+    )
+VALUES
+    (
+        1,
+        1,
+        1,
+        'Default'
+    );
+
+ALTER SEQUENCE workspace_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_workspace_updated_ts
+BEFORE
+UPDATE
+    ON workspace FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- Member
--- We separate the concept from Principal because if we support multiple workspace in the future, each workspace can have different member for the same principal
+-- We separate the concept from Principal because each workspace has its own members.
+-- A principal is the member of exactly one workspace.
 CREATE TABLE member (
     id SERIAL PRIMARY KEY,
     row_status row_status NOT NULL DEFAULT 'NORMAL',
@@ -89,12 +127,15 @@ CREATE TABLE member (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     status TEXT NOT NULL CHECK (status IN ('INVITED', 'ACTIVE')),
-    role TEXT NOT NULL CHECK (role IN ('OWNER', 'DBA', 'DEVELOPER')),
-    principal_id INTEGER NOT NULL REFERENCES principal (id)
+    role TEXT NOT NULL CHECK (role IN ('OWNER', 'DBA', 'DEVELOPER', 'AUDITOR')),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id)
 );
 
 CREATE UNIQUE INDEX idx_member_unique_principal_id ON member(principal_id);
 
+CREATE INDEX idx_member_workspace_id ON member(workspace_id);
+
 ALTER SEQUENCE member_id_seq RESTART WITH 101;
 
 CREATE TRIGGER update_member_updated_ts
@@ -112,10 +153,11 @@ CREATE TABLE environment (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     name TEXT NOT NULL,
-    "order" INTEGER NOT NULL CHECK ("order" >= 0)
+    "order" INTEGER NOT NULL CHECK ("order" >= 0),
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id)
 );
 
-CREATE UNIQUE INDEX idx_environment_unique_name ON environment(name);
+CREATE UNIQUE INDEX idx_environment_unique_workspace_id_name ON environment(workspace_id, name);
 
 ALTER SEQUENCE environment_id_seq RESTART WITH 101;
 
@@ -170,10 +212,11 @@ CREATE TABLE project (
     db_name_template TEXT NOT NULL,
     role_provider TEXT NOT NULL CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM')) DEFAULT 'BYTEBASE',
     schema_version_type TEXT NOT NULL CHECK (schema_version_type IN ('TIMESTAMP', 'SEMANTIC')) DEFAULT 'TIMESTAMP',
-    schema_migration_type TEXT NOT NULL CHECK (schema_migration_type IN ('DDL', 'SDL')) DEFAULT 'DDL'
+    schema_migration_type TEXT NOT NULL CHECK (schema_migration_type IN ('DDL', 'SDL')) DEFAULT 'DDL',
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id)
 );
 
-CREATE UNIQUE INDEX idx_project_unique_key ON project(key);
+CREATE UNIQUE INDEX idx_project_unique_workspace_id_key ON project(workspace_id, key);
 
 INSERT INTO
     project (
@@ -275,9 +318,21 @@ CREATE TABLE instance (
     engine_version TEXT NOT NULL DEFAULT '',
     host TEXT NOT NULL,
     port TEXT NOT NULL,
-    external_link TEXT NOT NULL DEFAULT ''
+    external_link TEXT NOT NULL DEFAULT '',
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id),
+    -- backup_storage_backend is empty for the storage backend configured for the server.
+    backup_storage_backend TEXT NOT NULL DEFAULT '' CHECK (backup_storage_backend IN ('', 'LOCAL', 'S3', 'GCS')),
+    -- ssh_host is empty for connecting the instance directly.
+    ssh_host TEXT NOT NULL DEFAULT '',
+    ssh_port TEXT NOT NULL DEFAULT '',
+    ssh_user TEXT NOT NULL DEFAULT '',
+    ssh_private_key TEXT NOT NULL DEFAULT '',
+    -- ssh_host_key is the public key or the SHA256 fingerprint of the SSH server.
+    ssh_host_key TEXT NOT NULL DEFAULT ''
 );
 
+CREATE INDEX idx_instance_workspace_id ON instance(workspace_id);
+
 ALTER SEQUENCE instance_id_seq RESTART WITH 101;
 
 CREATE TRIGGER update_instance_updated_ts
@@ -309,6 +364,48 @@ UPDATE
     ON instance_user FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- instance_agent stores the agents of the instances in the private networks unreachable from Bytebase.
+-- The agent connects out to Bytebase with the token, and relays the database connections of the instance.
+CREATE TABLE instance_agent (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    -- The SHA-256 hex digest of the agent token, the token itself is only returned once on creation.
+    token_hash TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_instance_agent_unique_instance_id ON instance_agent(instance_id);
+
+CREATE UNIQUE INDEX idx_instance_agent_unique_token_hash ON instance_agent(token_hash);
+
+ALTER SEQUENCE instance_agent_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_instance_agent_updated_ts
+BEFORE
+UPDATE
+    ON instance_agent FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- instance_disk_usage stores the disk usage samples of the instances, taken by the anomaly scanner to project when the disk fills up.
+-- total_bytes and free_bytes are reported by the instance agent running on the database host, 0 if unknown.
+-- data_bytes is the data and index size of the databases synced from the engine views.
+-- The samples are purged after the projection window.
+CREATE TABLE instance_disk_usage (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    total_bytes BIGINT NOT NULL DEFAULT 0,
+    free_bytes BIGINT NOT NULL DEFAULT 0,
+    data_bytes BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_instance_disk_usage_instance_id_created_ts ON instance_disk_usage(instance_id, created_ts);
+
+ALTER SEQUENCE instance_disk_usage_id_seq RESTART WITH 101;
+
 -- db stores the databases for a particular instance
 -- data is synced periodically from the instance
 CREATE TABLE db (
@@ -327,7 +424,13 @@ CREATE TABLE db (
     schema_version TEXT NOT NULL,
     name TEXT NOT NULL,
     character_set TEXT NOT NULL,
-    "collation" TEXT NOT NULL
+    "collation" TEXT NOT NULL,
+    -- The owner is notified on failed tasks and anomalies affecting the database.
+    owner_id INTEGER REFERENCES principal (id),
+    -- The external handle of the on-call rotation, e.g. an email or a chat group.
+    on_call TEXT NOT NULL DEFAULT '',
+    -- connection_option is the session settings applied on the connections to the database, in json format.
+    connection_option JSONB NOT NULL DEFAULT '{}'
 );
 
 CREATE INDEX idx_db_instance_id ON db(instance_id);
@@ -352,6 +455,8 @@ CREATE TABLE tbl (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    -- schema is the Postgres schema of the table, it's empty for the other engines.
+    schema TEXT NOT NULL DEFAULT '',
     name TEXT NOT NULL,
     type TEXT NOT NULL,
     engine TEXT NOT NULL,
@@ -471,6 +576,30 @@ UPDATE
     ON db_extension FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- db_schema stores the schemas for a particular Postgres database.
+-- data is synced periodically from the instance.
+CREATE TABLE db_schema (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    owner TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_db_schema_unique_database_id_name ON db_schema(database_id, name);
+
+ALTER SEQUENCE db_schema_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_schema_updated_ts
+BEFORE
+UPDATE
+    ON db_schema FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- vw stores the view for a particular database
 -- data is synced periodically from the instance
 CREATE TABLE vw (
@@ -481,6 +610,8 @@ CREATE TABLE vw (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    -- schema is the Postgres schema of the view, it's empty for the other engines.
+    schema TEXT NOT NULL DEFAULT '',
     name TEXT NOT NULL,
     definition TEXT NOT NULL,
     comment TEXT NOT NULL
@@ -625,7 +756,11 @@ CREATE TABLE stage (
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     pipeline_id INTEGER NOT NULL REFERENCES pipeline (id),
     environment_id INTEGER NOT NULL REFERENCES environment (id),
-    name TEXT NOT NULL
+    name TEXT NOT NULL,
+    -- paused holds the pipeline before the stage, no task in the stage is scheduled until it's resumed.
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    -- soak_seconds is the time the paused stage waits after the previous stage finishes before it's resumed automatically, 0 means manual resume only.
+    soak_seconds BIGINT NOT NULL DEFAULT 0
 );
 
 CREATE INDEX idx_stage_pipeline_id ON stage(pipeline_id);
@@ -652,7 +787,7 @@ CREATE TABLE task (
     -- Could be empty for creating database task when the task isn't yet completed successfully.
     database_id INTEGER REFERENCES db (id),
     name TEXT NOT NULL,
-    status TEXT NOT NULL CHECK (status IN ('PENDING', 'PENDING_APPROVAL', 'RUNNING', 'DONE', 'FAILED', 'CANCELED')),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'PENDING_APPROVAL', 'RUNNING', 'DONE', 'FAILED', 'CANCELED', 'PAUSED')),
     type TEXT NOT NULL CHECK (type LIKE 'bb.task.%'),
     payload JSONB NOT NULL DEFAULT '{}',
     earliest_allowed_ts BIGINT NOT NULL DEFAULT 0
@@ -704,13 +839,17 @@ CREATE TABLE task_run (
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     task_id INTEGER NOT NULL REFERENCES task (id),
     name TEXT NOT NULL,
-    status TEXT NOT NULL CHECK (status IN ('RUNNING', 'DONE', 'FAILED', 'CANCELED')),
+    status TEXT NOT NULL CHECK (status IN ('RUNNING', 'DONE', 'FAILED', 'CANCELED', 'PAUSED')),
     type TEXT NOT NULL CHECK (type LIKE 'bb.task.%'),
     code INTEGER NOT NULL DEFAULT 0,
     comment TEXT NOT NULL DEFAULT '',
     -- result saves the task run result in json format
     result  JSONB NOT NULL DEFAULT '{}',
-    payload JSONB NOT NULL DEFAULT '{}'
+    payload JSONB NOT NULL DEFAULT '{}',
+    -- progress saves the progress reported by the executor of the running task run periodically in json format
+    progress JSONB NOT NULL DEFAULT '{}',
+    -- heartbeat_ts is when the executor last reported the running task run alive, 0 means no executor is running it
+    heartbeat_ts BIGINT NOT NULL DEFAULT 0
 );
 
 CREATE INDEX idx_task_run_task_id ON task_run(task_id);
@@ -750,6 +889,29 @@ UPDATE
     ON task_check_run FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- task_run_artifact table stores the artifacts attached to the task run by the executor, e.g. the gh-ost log.
+-- The artifact content is stored in the blob storage, and the row is deleted when the artifact expires.
+CREATE TABLE task_run_artifact (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    task_run_id INTEGER NOT NULL REFERENCES task_run (id),
+    task_id INTEGER NOT NULL REFERENCES task (id),
+    type TEXT NOT NULL CHECK (type LIKE 'bb.task-run-artifact.%'),
+    name TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    storage_backend TEXT NOT NULL CHECK (storage_backend IN ('LOCAL', 'S3', 'GCS', 'OSS')),
+    path TEXT NOT NULL,
+    reference BOOLEAN NOT NULL DEFAULT FALSE,
+    expire_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_task_run_artifact_task_id ON task_run_artifact(task_id);
+
+CREATE INDEX idx_task_run_artifact_expire_ts ON task_run_artifact(expire_ts);
+
+ALTER SEQUENCE task_run_artifact_id_seq RESTART WITH 101;
+
 -- Pipeline related END
 -----------------------
 -- issue
@@ -769,7 +931,9 @@ CREATE TABLE issue (
     description TEXT NOT NULL DEFAULT '',
     -- While changing assignee_id, one should only change it to a non-robot DBA/owner.
     assignee_id INTEGER NOT NULL REFERENCES principal (id),
-    payload JSONB NOT NULL DEFAULT '{}'
+    payload JSONB NOT NULL DEFAULT '{}',
+    -- label_list is the free-form labels for grouping the issues.
+    label_list TEXT ARRAY NOT NULL DEFAULT '{}'
 );
 
 CREATE INDEX idx_issue_project_id ON issue(project_id);
@@ -782,6 +946,8 @@ CREATE INDEX idx_issue_assignee_id ON issue(assignee_id);
 
 CREATE INDEX idx_issue_created_ts ON issue(created_ts);
 
+CREATE INDEX idx_issue_label_list ON issue USING GIN(label_list);
+
 ALTER SEQUENCE issue_id_seq RESTART WITH 101;
 
 CREATE TRIGGER update_issue_updated_ts
@@ -801,6 +967,47 @@ CREATE TABLE issue_subscriber (
 
 CREATE INDEX idx_issue_subscriber_subscriber_id ON issue_subscriber(subscriber_id);
 
+-- issue_reference stores the references in the issue description resolved to the targets, e.g. #123, db:prod/orders and sheet:123.
+-- The target_id has no foreign key because it refers to the issue, database or sheet depending on the type.
+CREATE TABLE issue_reference (
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    type TEXT NOT NULL CHECK (type IN ('ISSUE', 'DATABASE', 'SHEET')),
+    target_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (issue_id, type, target_id)
+);
+
+CREATE INDEX idx_issue_reference_type_target_id ON issue_reference(type, target_id);
+
+-- statement_comment stores the review comments on the lines of the task statements in the issues.
+-- statement_hash is the hash of the statement when the comment was created, the line is outdated once the statement changes.
+-- The unresolved comments of a task block approving the task.
+CREATE TABLE statement_comment (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    task_id INTEGER NOT NULL REFERENCES task (id),
+    statement_hash TEXT NOT NULL,
+    line INTEGER NOT NULL CHECK (line > 0),
+    content TEXT NOT NULL,
+    resolved BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_statement_comment_issue_id ON statement_comment(issue_id);
+
+CREATE INDEX idx_statement_comment_task_id ON statement_comment(task_id);
+
+ALTER SEQUENCE statement_comment_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_statement_comment_updated_ts
+BEFORE
+UPDATE
+    ON statement_comment FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- activity table stores the activity for the container such as issue
 CREATE TABLE activity (
     id SERIAL PRIMARY KEY,
@@ -868,7 +1075,7 @@ UPDATE
     ON bookmark FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
--- vcs table stores the version control provider config
+-- vcs table stores the version control provider config, which belongs to the workspace of its creator.
 CREATE TABLE vcs (
     id SERIAL PRIMARY KEY,
     row_status row_status NOT NULL DEFAULT 'NORMAL',
@@ -881,9 +1088,12 @@ CREATE TABLE vcs (
     instance_url TEXT NOT NULL CHECK ((instance_url LIKE 'http://%' OR instance_url LIKE 'https://%') AND instance_url = rtrim(instance_url, '/')),
     api_url TEXT NOT NULL CHECK ((api_url LIKE 'http://%' OR api_url LIKE 'https://%') AND api_url = rtrim(api_url, '/')),
     application_id TEXT NOT NULL,
-    secret TEXT NOT NULL
+    secret TEXT NOT NULL,
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id)
 );
 
+CREATE INDEX idx_vcs_workspace_id ON vcs(workspace_id);
+
 ALTER SEQUENCE vcs_id_seq RESTART WITH 101;
 
 CREATE TRIGGER update_vcs_updated_ts
@@ -1054,6 +1264,58 @@ UPDATE
     ON db_label FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- db_secret stores the secrets of the databases, e.g. a tenant encryption key.
+-- The secrets are referenced in the statements as {{secret.NAME}} and injected at the execution time,
+-- so that the values are never recorded in the migration history.
+CREATE TABLE db_secret (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+-- database_id/name's are unique within the db_secret table.
+CREATE UNIQUE INDEX idx_db_secret_unique_database_id_name ON db_secret(database_id, name);
+
+ALTER SEQUENCE db_secret_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_secret_updated_ts
+BEFORE
+UPDATE
+    ON db_secret FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- schema_snapshot stores the gzip compressed schema snapshots of the databases.
+-- A snapshot is taken for each database every day and after each migration, so that the schema can be browsed at any point in time.
+CREATE TABLE schema_snapshot (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- Enum: DAILY, MIGRATION
+    type TEXT NOT NULL CHECK (type IN ('DAILY', 'MIGRATION')),
+    -- The migration version for the MIGRATION snapshot.
+    migration_version TEXT NOT NULL DEFAULT '',
+    schema BYTEA NOT NULL
+);
+
+CREATE INDEX idx_schema_snapshot_database_id_created_ts ON schema_snapshot(database_id, created_ts);
+
+ALTER SEQUENCE schema_snapshot_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_snapshot_updated_ts
+BEFORE
+UPDATE
+    ON schema_snapshot FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- Deployment Configuration.
 -- deployment_config stores deployment configurations at project level.
 CREATE TABLE deployment_config (
@@ -1126,3 +1388,292 @@ CREATE TABLE sheet_organizer (
 CREATE UNIQUE INDEX idx_sheet_organizer_unique_sheet_id_principal_id ON sheet_organizer(sheet_id, principal_id);
 
 CREATE INDEX idx_sheet_organizer_principal_id ON sheet_organizer(principal_id);
+
+-- recurring_task table stores the housekeeping DML statements run on the database following the cron schedule.
+CREATE TABLE recurring_task (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    name TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    schedule TEXT NOT NULL,
+    -- 0 means unlimited.
+    max_affected_rows BIGINT NOT NULL DEFAULT 0 CHECK (max_affected_rows >= 0),
+    next_run_ts BIGINT NOT NULL,
+    last_issue_id INTEGER REFERENCES issue (id)
+);
+
+CREATE INDEX idx_recurring_task_database_id ON recurring_task(database_id);
+
+CREATE INDEX idx_recurring_task_next_run_ts ON recurring_task(next_run_ts);
+
+ALTER SEQUENCE recurring_task_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_recurring_task_updated_ts
+BEFORE
+UPDATE
+    ON recurring_task FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- project_sql_review_override table stores the project level overrides on the environment SQL review policy.
+CREATE TABLE project_sql_review_override (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_project_sql_review_override_unique_project_id ON project_sql_review_override(project_id);
+
+ALTER SEQUENCE project_sql_review_override_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_project_sql_review_override_updated_ts
+BEFORE
+UPDATE
+    ON project_sql_review_override FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- usage_metric table stores the workspace usage metrics collected by the metric reporter, e.g. the instance count.
+-- The metrics are kept locally for the usage dashboard regardless of whether they are sent outside the workspace.
+CREATE TABLE usage_metric (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    name TEXT NOT NULL,
+    value INTEGER NOT NULL,
+    labels JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_usage_metric_created_ts ON usage_metric(created_ts);
+
+ALTER SEQUENCE usage_metric_id_seq RESTART WITH 101;
+
+-- migration_history_object table stores the tables and columns referenced by the statement of a migration history,
+-- which are extracted by the parser when the migration is executed.
+-- The migration history lives in the instance, so migration_history_id refers to the migration history ID there.
+-- schema_name is empty if the statement doesn't qualify the table with a schema.
+-- column_name is empty if the whole table is referenced, e.g. dropping or renaming the table.
+CREATE TABLE migration_history_object (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    migration_history_id INTEGER NOT NULL,
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL DEFAULT '',
+    schema_name TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_migration_history_object_database_id_table_name_column_name ON migration_history_object(database_id, table_name, column_name);
+
+CREATE INDEX idx_migration_history_object_database_id_migration_history_id ON migration_history_object(database_id, migration_history_id);
+
+ALTER SEQUENCE migration_history_object_id_seq RESTART WITH 101;
+
+-- migration_history_statement table stores the execution duration of each statement of a migration history,
+-- so that the slow migrations can be analyzed later and compared across the environments.
+-- The migration history lives in the instance, so migration_history_id refers to the migration history ID there.
+-- statement_index is the 1-based position of the statement in the migration.
+CREATE TABLE migration_history_statement (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    migration_history_id INTEGER NOT NULL,
+    statement_index INTEGER NOT NULL,
+    statement TEXT NOT NULL,
+    duration_ns BIGINT NOT NULL,
+    rows_affected BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_migration_history_statement_database_id_history_id ON migration_history_statement(database_id, migration_history_id);
+
+ALTER SEQUENCE migration_history_statement_id_seq RESTART WITH 101;
+
+-- query_audit_log table stores the SQL editor executions and the data exports of the query results for compliance.
+-- It's kept separately from the activity table, and purged after the retention period.
+-- fingerprint is the statement with the literals replaced by placeholders, so that the same query with different values can be grouped.
+CREATE TABLE query_audit_log (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    type TEXT NOT NULL CHECK (type IN ('QUERY', 'EXPORT', 'EXECUTE')),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    database_name TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    duration_ns BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_query_audit_log_created_ts ON query_audit_log(created_ts);
+
+CREATE INDEX idx_query_audit_log_creator_id ON query_audit_log(creator_id);
+
+CREATE INDEX idx_query_audit_log_instance_id_database_name ON query_audit_log(instance_id, database_name);
+
+ALTER SEQUENCE query_audit_log_id_seq RESTART WITH 101;
+
+-- idempotency_key stores the responses of the create requests sent with the Idempotency-Key header,
+-- so that the retried requests with the same key are answered with the stored response instead of creating duplicates.
+-- The key is scoped to the principal sending the request, and purged after it expires.
+CREATE TABLE idempotency_key (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    key TEXT NOT NULL,
+    -- The method and the route of the request, e.g. "POST /api/issue".
+    request TEXT NOT NULL,
+    -- The SHA-256 hex digest of the request body, to reject the key reused for a different request.
+    request_hash TEXT NOT NULL,
+    -- The status code is 0 while the request is still in progress.
+    status_code INTEGER NOT NULL DEFAULT 0,
+    content_type TEXT NOT NULL DEFAULT '',
+    response TEXT NOT NULL DEFAULT '',
+    expire_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_idempotency_key_unique_creator_id_key ON idempotency_key(creator_id, key);
+
+CREATE INDEX idx_idempotency_key_expire_ts ON idempotency_key(expire_ts);
+
+ALTER SEQUENCE idempotency_key_id_seq RESTART WITH 101;
+
+-- database_template table stores the templates for provisioning new databases consistently, e.g. the tenant databases.
+-- The extensions are only applicable to Postgres, and the grantees are only applicable to MySQL, TiDB and Postgres.
+-- sheet_id refers to the sheet holding the baseline schema applied to the new database, NULL means an empty database.
+CREATE TABLE database_template (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id),
+    name TEXT NOT NULL,
+    engine TEXT NOT NULL CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE')),
+    character_set TEXT NOT NULL DEFAULT '',
+    "collation" TEXT NOT NULL DEFAULT '',
+    extension_list TEXT ARRAY NOT NULL DEFAULT '{}',
+    grantee_list TEXT ARRAY NOT NULL DEFAULT '{}',
+    sheet_id INTEGER
+);
+
+CREATE UNIQUE INDEX idx_database_template_unique_workspace_id_name ON database_template(workspace_id, name);
+
+ALTER SEQUENCE database_template_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_database_template_updated_ts
+BEFORE
+UPDATE
+    ON database_template FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- managed_grant table stores the privileges declared for the application roles on the databases.
+-- The actual grants are compared with them on the database sync, and the difference is reported as a grant drift anomaly.
+-- grantee is the role name for Postgres, and "user@host" for MySQL and TiDB.
+CREATE TABLE managed_grant (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    grantee TEXT NOT NULL,
+    privilege_list TEXT ARRAY NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_managed_grant_unique_database_id_grantee ON managed_grant(database_id, grantee);
+
+ALTER SEQUENCE managed_grant_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_managed_grant_updated_ts
+BEFORE
+UPDATE
+    ON managed_grant FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- db_query_grant table stores the grants allowing the developers to query the databases in the SQL editor.
+-- The grantee is either a principal or all the members of a role, exactly one of principal_id and role is set.
+-- The grant is requested as PENDING, and allows querying once APPROVED until expire_ts, after which it's marked EXPIRED.
+-- A WRITE grant is the temporary elevated access to execute the write statements in the SQL editor, which is required by everyone.
+CREATE TABLE db_query_grant (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    principal_id INTEGER REFERENCES principal (id),
+    role TEXT NOT NULL DEFAULT '' CHECK (role IN ('', 'DEVELOPER')),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'REVOKED', 'EXPIRED')),
+    expire_ts BIGINT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    access TEXT NOT NULL DEFAULT 'QUERY' CHECK (access IN ('QUERY', 'WRITE')),
+    CHECK ((principal_id IS NULL) <> (role = ''))
+);
+
+CREATE INDEX idx_db_query_grant_database_id ON db_query_grant(database_id);
+
+ALTER SEQUENCE db_query_grant_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_query_grant_updated_ts
+BEFORE
+UPDATE
+    ON db_query_grant FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- issue_view table stores the saved issue filters, e.g. the columns of the kanban board.
+-- The view with project_id filters the issues of the project and is shared with the workspace, otherwise it's private to the creator.
+-- filter is the json-encoded api.IssueViewFilter.
+CREATE TABLE issue_view (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspace (id),
+    project_id INTEGER REFERENCES project (id),
+    name TEXT NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_issue_view_creator_id ON issue_view(creator_id);
+
+CREATE INDEX idx_issue_view_project_id ON issue_view(project_id);
+
+ALTER SEQUENCE issue_view_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_view_updated_ts
+BEFORE
+UPDATE
+    ON issue_view FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- report_subscription table stores the subscriptions of the principals to the weekly reports of the projects.
+-- last_sent_ts is when the last report was sent, 0 means never.
+CREATE TABLE report_subscription (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_report_subscription_unique_project_id_principal_id ON report_subscription(project_id, principal_id);
+
+ALTER SEQUENCE report_subscription_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_report_subscription_updated_ts
+BEFORE
+UPDATE
+    ON report_subscription FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
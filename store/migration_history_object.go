@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/pkg/errors"
+)
+
+// CreateMigrationHistoryObjectList creates the objects referenced by the statement of a migration history.
+func (s *Store) CreateMigrationHistoryObjectList(ctx context.Context, createList []*api.MigrationHistoryObjectCreate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	for _, create := range createList {
+		if err := createMigrationHistoryObjectImpl(ctx, tx.PTx, create); err != nil {
+			return errors.Wrapf(err, "failed to create MigrationHistoryObject with MigrationHistoryObjectCreate[%+v]", create)
+		}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// FindMigrationHistoryObject finds a list of MigrationHistoryObject instances, the latest first.
+func (s *Store) FindMigrationHistoryObject(ctx context.Context, find *api.MigrationHistoryObjectFind) ([]*api.MigrationHistoryObject, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findMigrationHistoryObjectImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find MigrationHistoryObject list with MigrationHistoryObjectFind[%+v]", find)
+	}
+
+	return list, nil
+}
+
+// createMigrationHistoryObjectImpl creates a new migration history object.
+func createMigrationHistoryObjectImpl(ctx context.Context, tx *sql.Tx, create *api.MigrationHistoryObjectCreate) error {
+	// Insert row into database.
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO migration_history_object (
+			database_id,
+			migration_history_id,
+			schema_name,
+			table_name,
+			column_name
+		)
+		VALUES ($1, $2, $3, $4, $5)`,
+		create.DatabaseID,
+		create.MigrationHistoryID,
+		create.SchemaName,
+		create.TableName,
+		create.ColumnName,
+	); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+func findMigrationHistoryObjectImpl(ctx context.Context, tx *sql.Tx, find *api.MigrationHistoryObjectFind) ([]*api.MigrationHistoryObject, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.MigrationHistoryID; v != nil {
+		where, args = append(where, fmt.Sprintf("migration_history_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.SchemaName; v != nil {
+		where, args = append(where, fmt.Sprintf("schema_name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TableName; v != nil {
+		where, args = append(where, fmt.Sprintf("table_name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ColumnName; v != nil {
+		where, args = append(where, fmt.Sprintf("(column_name = $%d OR column_name = '')", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			database_id,
+			migration_history_id,
+			schema_name,
+			table_name,
+			column_name
+		FROM migration_history_object
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY migration_history_id DESC, id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into migrationHistoryObjectList.
+	var migrationHistoryObjectList []*api.MigrationHistoryObject
+	for rows.Next() {
+		var migrationHistoryObject api.MigrationHistoryObject
+		if err := rows.Scan(
+			&migrationHistoryObject.ID,
+			&migrationHistoryObject.CreatedTs,
+			&migrationHistoryObject.DatabaseID,
+			&migrationHistoryObject.MigrationHistoryID,
+			&migrationHistoryObject.SchemaName,
+			&migrationHistoryObject.TableName,
+			&migrationHistoryObject.ColumnName,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		migrationHistoryObjectList = append(migrationHistoryObjectList, &migrationHistoryObject)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return migrationHistoryObjectList, nil
+}
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// CreateMigrationHistoryStatementList creates the executions of the statements of a migration history.
+func (s *Store) CreateMigrationHistoryStatementList(ctx context.Context, createList []*api.MigrationHistoryStatementCreate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	for _, create := range createList {
+		if err := createMigrationHistoryStatementImpl(ctx, tx.PTx, create); err != nil {
+			return errors.Wrapf(err, "failed to create MigrationHistoryStatement with MigrationHistoryStatementCreate[%+v]", create)
+		}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// FindMigrationHistoryStatement finds a list of MigrationHistoryStatement instances, in the statement order.
+func (s *Store) FindMigrationHistoryStatement(ctx context.Context, find *api.MigrationHistoryStatementFind) ([]*api.MigrationHistoryStatement, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findMigrationHistoryStatementImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find MigrationHistoryStatement list with MigrationHistoryStatementFind[%+v]", find)
+	}
+
+	return list, nil
+}
+
+// createMigrationHistoryStatementImpl creates a new migration history statement.
+func createMigrationHistoryStatementImpl(ctx context.Context, tx *sql.Tx, create *api.MigrationHistoryStatementCreate) error {
+	// Insert row into database.
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO migration_history_statement (
+			database_id,
+			migration_history_id,
+			statement_index,
+			statement,
+			duration_ns,
+			rows_affected
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		create.DatabaseID,
+		create.MigrationHistoryID,
+		create.StatementIndex,
+		create.Statement,
+		create.DurationNs,
+		create.RowsAffected,
+	); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+func findMigrationHistoryStatementImpl(ctx context.Context, tx *sql.Tx, find *api.MigrationHistoryStatementFind) ([]*api.MigrationHistoryStatement, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.MigrationHistoryID; v != nil {
+		where, args = append(where, fmt.Sprintf("migration_history_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			database_id,
+			migration_history_id,
+			statement_index,
+			statement,
+			duration_ns,
+			rows_affected
+		FROM migration_history_statement
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY migration_history_id DESC, statement_index ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into migrationHistoryStatementList.
+	var migrationHistoryStatementList []*api.MigrationHistoryStatement
+	for rows.Next() {
+		var migrationHistoryStatement api.MigrationHistoryStatement
+		if err := rows.Scan(
+			&migrationHistoryStatement.ID,
+			&migrationHistoryStatement.CreatedTs,
+			&migrationHistoryStatement.DatabaseID,
+			&migrationHistoryStatement.MigrationHistoryID,
+			&migrationHistoryStatement.StatementIndex,
+			&migrationHistoryStatement.Statement,
+			&migrationHistoryStatement.DurationNs,
+			&migrationHistoryStatement.RowsAffected,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		migrationHistoryStatementList = append(migrationHistoryStatementList, &migrationHistoryStatement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return migrationHistoryStatementList, nil
+}
@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// FindMigrationRunCount counts the finished migration task runs in the time window, grouped by the environment and the project.
+func (s *Store) FindMigrationRunCount(ctx context.Context, find *api.MigrationStatFind) ([]*api.MigrationRunCount, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	where, args := findMigrationStatWhere("task_run", find)
+	rows, err := tx.PTx.QueryContext(ctx, `
+		SELECT
+			stage.environment_id,
+			issue.project_id,
+			COUNT(*) FILTER (WHERE task_run.status = 'DONE'),
+			COUNT(*) FILTER (WHERE task_run.status = 'FAILED'),
+			COALESCE(SUM(task_run.updated_ts - task_run.created_ts) FILTER (WHERE task_run.status = 'DONE'), 0)
+		FROM task_run
+		JOIN task ON task.id = task_run.task_id
+		JOIN stage ON stage.id = task.stage_id
+		JOIN issue ON issue.pipeline_id = task.pipeline_id
+		WHERE task_run.status IN ('DONE', 'FAILED') AND `+strings.Join(where, " AND ")+`
+		GROUP BY stage.environment_id, issue.project_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var countList []*api.MigrationRunCount
+	for rows.Next() {
+		var count api.MigrationRunCount
+		if err := rows.Scan(
+			&count.EnvironmentID,
+			&count.ProjectID,
+			&count.DoneCount,
+			&count.FailedCount,
+			&count.DoneDurationSeconds,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		countList = append(countList, &count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return countList, nil
+}
+
+// FindMigrationCheckFailureCount counts the failed task checks of the migrations in the time window,
+// grouped by the environment, the project and the check type.
+func (s *Store) FindMigrationCheckFailureCount(ctx context.Context, find *api.MigrationStatFind) ([]*api.MigrationCheckFailureCount, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	where, args := findMigrationStatWhere("task_check_run", find)
+	// The check run fails if it fails to run or reports an error that is not suppressed.
+	rows, err := tx.PTx.QueryContext(ctx, `
+		SELECT
+			stage.environment_id,
+			issue.project_id,
+			task_check_run.type,
+			COUNT(*)
+		FROM task_check_run
+		JOIN task ON task.id = task_check_run.task_id
+		JOIN stage ON stage.id = task.stage_id
+		JOIN issue ON issue.pipeline_id = task.pipeline_id
+		WHERE (
+			task_check_run.status = 'FAILED'
+			OR (
+				task_check_run.status = 'DONE'
+				AND EXISTS (
+					SELECT 1 FROM jsonb_array_elements(COALESCE(task_check_run.result->'resultList', '[]'::jsonb)) AS r
+					WHERE r->>'status' = 'ERROR' AND r->'suppression' IS NULL
+				)
+			)
+		) AND `+strings.Join(where, " AND ")+`
+		GROUP BY stage.environment_id, issue.project_id, task_check_run.type`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var countList []*api.MigrationCheckFailureCount
+	for rows.Next() {
+		var count api.MigrationCheckFailureCount
+		if err := rows.Scan(
+			&count.EnvironmentID,
+			&count.ProjectID,
+			&count.Type,
+			&count.Count,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		countList = append(countList, &count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return countList, nil
+}
+
+// findMigrationStatWhere builds the WHERE clause of the migration statistics on the run table joined with task, stage and issue.
+func findMigrationStatWhere(runTable string, find *api.MigrationStatFind) ([]string, []interface{}) {
+	where, args := []string{}, []interface{}{}
+	where, args = append(where, fmt.Sprintf("%s.created_ts >= $%d", runTable, len(args)+1)), append(args, find.StartTs)
+	where, args = append(where, fmt.Sprintf("%s.created_ts < $%d", runTable, len(args)+1)), append(args, find.EndTs)
+	typeList := []string{}
+	for _, taskType := range api.MigrationStatTaskTypeList {
+		typeList = append(typeList, fmt.Sprintf("$%d", len(args)+1))
+		args = append(args, taskType)
+	}
+	where = append(where, fmt.Sprintf("task.type IN (%s)", strings.Join(typeList, ",")))
+	if v := find.EnvironmentID; v != nil {
+		where, args = append(where, fmt.Sprintf("stage.environment_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue.project_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue.project_id IN (SELECT id FROM project WHERE workspace_id = $%d)", len(args)+1)), append(args, *v)
+	}
+	return where, args
+}
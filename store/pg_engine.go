@@ -674,11 +674,13 @@ func FormatError(err error) error {
 			return common.Errorf(common.Conflict, "setting name already exists")
 		case strings.Contains(err.Error(), "idx_member_unique_principal_id"):
 			return common.Errorf(common.Conflict, "member already exists")
-		case strings.Contains(err.Error(), "idx_environment_unique_name"):
+		case strings.Contains(err.Error(), "idx_workspace_unique_name"):
+			return common.Errorf(common.Conflict, "workspace name already exists")
+		case strings.Contains(err.Error(), "idx_environment_unique_name"), strings.Contains(err.Error(), "idx_environment_unique_workspace_id_name"):
 			return common.Errorf(common.Conflict, "environment name already exists")
 		case strings.Contains(err.Error(), "idx_policy_unique_environment_id_type"):
 			return common.Errorf(common.Conflict, "policy environment and type already exists")
-		case strings.Contains(err.Error(), "idx_project_unique_key"):
+		case strings.Contains(err.Error(), "idx_project_unique_key"), strings.Contains(err.Error(), "idx_project_unique_workspace_id_key"):
 			return common.Errorf(common.Conflict, "project key already exists")
 		case strings.Contains(err.Error(), "idx_project_member_unique_project_id_role_provider_principal_id"):
 			return common.Errorf(common.Conflict, "project member already exists")
@@ -716,6 +718,8 @@ func FormatError(err error) error {
 			return common.Errorf(common.Conflict, "database id and key already exists")
 		case strings.Contains(err.Error(), "idx_deployment_config_unique_project_id"):
 			return common.Errorf(common.Conflict, "project deployment configuration already exists")
+		case strings.Contains(err.Error(), "idx_database_template_unique_workspace_id_name"):
+			return common.Errorf(common.Conflict, "database template name already exists")
 		case strings.Contains(err.Error(), "issue_subscriber_pkey"):
 			return common.Errorf(common.Conflict, "issue subscriber already exists")
 		}
@@ -225,6 +225,45 @@ func (s *Store) GetEnvironmentTierPolicyByEnvID(ctx context.Context, environment
 	return api.UnmarshalEnvironmentTierPolicy(policy.Payload)
 }
 
+// GetQueryLimitPolicyByEnvID will get the query limit policy for an environment.
+func (s *Store) GetQueryLimitPolicyByEnvID(ctx context.Context, environmentID int) (*api.QueryLimitPolicy, error) {
+	pType := api.PolicyTypeQueryLimit
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalQueryLimitPolicy(policy.Payload)
+}
+
+// GetTaskFailurePolicyByEnvID will get the task failure policy for an environment.
+func (s *Store) GetTaskFailurePolicyByEnvID(ctx context.Context, environmentID int) (*api.TaskFailurePolicy, error) {
+	pType := api.PolicyTypeTaskFailure
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalTaskFailurePolicy(policy.Payload)
+}
+
+// GetIssueSLAPolicyByEnvID will get the issue SLA policy for an environment.
+func (s *Store) GetIssueSLAPolicyByEnvID(ctx context.Context, environmentID int) (*api.IssueSLAPolicy, error) {
+	pType := api.PolicyTypeIssueSLA
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalIssueSLAPolicy(policy.Payload)
+}
+
 //
 // private functions
 //
@@ -310,6 +349,9 @@ func findPolicyImpl(ctx context.Context, tx *sql.Tx, find *api.PolicyFind) ([]*p
 	if v := find.EnvironmentID; v != nil {
 		where, args = append(where, fmt.Sprintf("environment_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("environment_id IN (SELECT id FROM environment WHERE workspace_id = $%d)", len(args)+1)), append(args, *v)
+	}
 	if v := find.Type; v != nil {
 		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
 	}
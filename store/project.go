@@ -26,6 +26,7 @@ type projectRaw struct {
 	UpdatedTs int64
 
 	// Domain specific fields
+	WorkspaceID         int
 	Name                string
 	Key                 string
 	WorkflowType        api.ProjectWorkflowType
@@ -48,6 +49,7 @@ func (raw *projectRaw) toProject() *api.Project {
 		UpdaterID: raw.UpdaterID,
 		UpdatedTs: raw.UpdatedTs,
 
+		WorkspaceID:         raw.WorkspaceID,
 		Name:                raw.Name,
 		Key:                 raw.Key,
 		WorkflowType:        raw.WorkflowType,
@@ -320,6 +322,7 @@ func createProjectImpl(ctx context.Context, tx *sql.Tx, create *api.ProjectCreat
 		INSERT INTO project (
 			creator_id,
 			updater_id,
+			workspace_id,
 			name,
 			key,
 			workflow_type,
@@ -328,13 +331,14 @@ func createProjectImpl(ctx context.Context, tx *sql.Tx, create *api.ProjectCreat
 			db_name_template,
 			role_provider
 		)
-		VALUES ($1, $2, $3, $4, 'UI', 'PUBLIC', $5, $6, $7)
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider
+		VALUES ($1, $2, $3, $4, $5, 'UI', 'PUBLIC', $6, $7, $8)
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider
 	`
 		var project projectRaw
 		if err := tx.QueryRowContext(ctx, query,
 			create.CreatorID,
 			create.CreatorID,
+			create.WorkspaceID,
 			create.Name,
 			strings.ToUpper(create.Key),
 			create.TenantMode,
@@ -347,6 +351,7 @@ func createProjectImpl(ctx context.Context, tx *sql.Tx, create *api.ProjectCreat
 			&project.CreatedTs,
 			&project.UpdaterID,
 			&project.UpdatedTs,
+			&project.WorkspaceID,
 			&project.Name,
 			&project.Key,
 			&project.WorkflowType,
@@ -367,6 +372,7 @@ func createProjectImpl(ctx context.Context, tx *sql.Tx, create *api.ProjectCreat
 		INSERT INTO project (
 			creator_id,
 			updater_id,
+			workspace_id,
 			name,
 			key,
 			workflow_type,
@@ -376,13 +382,14 @@ func createProjectImpl(ctx context.Context, tx *sql.Tx, create *api.ProjectCreat
 			role_provider,
 			schema_migration_type
 		)
-		VALUES ($1, $2, $3, $4, 'UI', 'PUBLIC', $5, $6, $7, $8)
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider, schema_migration_type
+		VALUES ($1, $2, $3, $4, $5, 'UI', 'PUBLIC', $6, $7, $8, $9)
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider, schema_migration_type
 	`
 	var project projectRaw
 	if err := tx.QueryRowContext(ctx, query,
 		create.CreatorID,
 		create.CreatorID,
+		create.WorkspaceID,
 		create.Name,
 		strings.ToUpper(create.Key),
 		create.TenantMode,
@@ -396,6 +403,7 @@ func createProjectImpl(ctx context.Context, tx *sql.Tx, create *api.ProjectCreat
 		&project.CreatedTs,
 		&project.UpdaterID,
 		&project.UpdatedTs,
+		&project.WorkspaceID,
 		&project.Name,
 		&project.Key,
 		&project.WorkflowType,
@@ -425,6 +433,10 @@ func findProjectImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectFind, mod
 	if v := find.PrincipalID; v != nil {
 		where, args = append(where, fmt.Sprintf("id IN (SELECT project_id FROM project_member WHERE principal_id = $%d)", len(args)+1)), append(args, *v)
 	}
+	if v := find.WorkspaceID; v != nil {
+		// The default project is shared by all workspaces to hold the unassigned databases, which are scoped by their instances.
+		where, args = append(where, fmt.Sprintf("(workspace_id = $%d OR id = %d)", len(args)+1, api.DefaultProjectID)), append(args, *v)
+	}
 
 	if mode == common.ReleaseModeProd {
 		rows, err := tx.QueryContext(ctx, `
@@ -435,6 +447,7 @@ func findProjectImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectFind, mod
 			created_ts,
 			updater_id,
 			updated_ts,
+			workspace_id,
 			name,
 			key,
 			workflow_type,
@@ -462,6 +475,7 @@ func findProjectImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectFind, mod
 				&project.CreatedTs,
 				&project.UpdaterID,
 				&project.UpdatedTs,
+				&project.WorkspaceID,
 				&project.Name,
 				&project.Key,
 				&project.WorkflowType,
@@ -490,6 +504,7 @@ func findProjectImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectFind, mod
 			created_ts,
 			updater_id,
 			updated_ts,
+			workspace_id,
 			name,
 			key,
 			workflow_type,
@@ -518,6 +533,7 @@ func findProjectImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectFind, mod
 			&project.CreatedTs,
 			&project.UpdaterID,
 			&project.UpdatedTs,
+			&project.WorkspaceID,
 			&project.Name,
 			&project.Key,
 			&project.WorkflowType,
@@ -570,7 +586,7 @@ func patchProjectImpl(ctx context.Context, tx *sql.Tx, patch *api.ProjectPatch,
 		UPDATE project
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider
 	`, len(args)),
 			args...,
 		).Scan(
@@ -580,6 +596,7 @@ func patchProjectImpl(ctx context.Context, tx *sql.Tx, patch *api.ProjectPatch,
 			&project.CreatedTs,
 			&project.UpdaterID,
 			&project.UpdatedTs,
+			&project.WorkspaceID,
 			&project.Name,
 			&project.Key,
 			&project.WorkflowType,
@@ -602,7 +619,7 @@ func patchProjectImpl(ctx context.Context, tx *sql.Tx, patch *api.ProjectPatch,
 		UPDATE project
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider, schema_migration_type
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider, schema_migration_type
 	`, len(args)),
 		args...,
 	).Scan(
@@ -612,6 +629,7 @@ func patchProjectImpl(ctx context.Context, tx *sql.Tx, patch *api.ProjectPatch,
 		&project.CreatedTs,
 		&project.UpdaterID,
 		&project.UpdatedTs,
+		&project.WorkspaceID,
 		&project.Name,
 		&project.Key,
 		&project.WorkflowType,
@@ -0,0 +1,228 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/pkg/errors"
+)
+
+// projectSQLReviewOverrideRaw is the store model for a ProjectSQLReviewOverride.
+// Fields have exactly the same meanings as ProjectSQLReviewOverride.
+type projectSQLReviewOverrideRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Payload string
+}
+
+// toProjectSQLReviewOverride creates an instance of ProjectSQLReviewOverride based on the projectSQLReviewOverrideRaw.
+// This is intended to be called when we need to compose a ProjectSQLReviewOverride relationship.
+func (raw *projectSQLReviewOverrideRaw) toProjectSQLReviewOverride() *api.ProjectSQLReviewOverride {
+	return &api.ProjectSQLReviewOverride{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID: raw.ProjectID,
+
+		// Domain specific fields
+		Payload: raw.Payload,
+	}
+}
+
+// UpsertProjectSQLReviewOverride upserts the SQL review override of a project.
+func (s *Store) UpsertProjectSQLReviewOverride(ctx context.Context, upsert *api.ProjectSQLReviewOverrideUpsert) (*api.ProjectSQLReviewOverride, error) {
+	raw, err := s.upsertProjectSQLReviewOverrideRaw(ctx, upsert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert project SQL review override with ProjectSQLReviewOverrideUpsert[%+v]", upsert)
+	}
+	override, err := s.composeProjectSQLReviewOverride(ctx, raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose project SQL review override with projectSQLReviewOverrideRaw[%+v]", raw)
+	}
+	return override, nil
+}
+
+// GetProjectSQLReviewOverrideByProjectID gets the SQL review override of a project.
+// Returns nil if the project has no override.
+func (s *Store) GetProjectSQLReviewOverrideByProjectID(ctx context.Context, projectID int) (*api.ProjectSQLReviewOverride, error) {
+	raw, err := s.getProjectSQLReviewOverrideRaw(ctx, projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get project SQL review override with project ID %d", projectID)
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	override, err := s.composeProjectSQLReviewOverride(ctx, raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose project SQL review override with projectSQLReviewOverrideRaw[%+v]", raw)
+	}
+	return override, nil
+}
+
+// MergeProjectSQLReviewOverride applies the SQL review override of the project on the environment SQL review policy.
+func (s *Store) MergeProjectSQLReviewOverride(ctx context.Context, projectID int, policy *advisor.SQLReviewPolicy) error {
+	raw, err := s.getProjectSQLReviewOverrideRaw(ctx, projectID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get project SQL review override with project ID %d", projectID)
+	}
+	if raw == nil {
+		return nil
+	}
+	payload, err := api.UnmarshalProjectSQLReviewOverridePayload(raw.Payload)
+	if err != nil {
+		return err
+	}
+	policy.RuleList = advisor.MergeSQLReviewRuleOverride(policy.RuleList, payload.RuleList)
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeProjectSQLReviewOverride(ctx context.Context, raw *projectSQLReviewOverrideRaw) (*api.ProjectSQLReviewOverride, error) {
+	override := raw.toProjectSQLReviewOverride()
+
+	creator, err := s.GetPrincipalByID(ctx, override.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	override.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, override.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	override.Updater = updater
+
+	return override, nil
+}
+
+// upsertProjectSQLReviewOverrideRaw sets the SQL review override for a project.
+func (s *Store) upsertProjectSQLReviewOverrideRaw(ctx context.Context, upsert *api.ProjectSQLReviewOverrideUpsert) (*projectSQLReviewOverrideRaw, error) {
+	if _, err := api.UnmarshalProjectSQLReviewOverridePayload(upsert.Payload); err != nil {
+		return nil, &common.Error{Code: common.Invalid, Err: err}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := upsertProjectSQLReviewOverrideImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return raw, nil
+}
+
+// getProjectSQLReviewOverrideRaw retrieves the SQL review override for a project.
+func (s *Store) getProjectSQLReviewOverrideRaw(ctx context.Context, projectID int) (*projectSQLReviewOverrideRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := getProjectSQLReviewOverrideImpl(ctx, tx.PTx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+const projectSQLReviewOverrideColumns = `
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			payload`
+
+func scanProjectSQLReviewOverrideRaw(row rowScanner) (*projectSQLReviewOverrideRaw, error) {
+	var raw projectSQLReviewOverrideRaw
+	if err := row.Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.Payload,
+	); err != nil {
+		return nil, err
+	}
+	return &raw, nil
+}
+
+// upsertProjectSQLReviewOverrideImpl creates or updates the SQL review override by project id.
+func upsertProjectSQLReviewOverrideImpl(ctx context.Context, tx *sql.Tx, upsert *api.ProjectSQLReviewOverrideUpsert) (*projectSQLReviewOverrideRaw, error) {
+	query := `
+		INSERT INTO project_sql_review_override (
+			creator_id,
+			updater_id,
+			project_id,
+			payload
+		)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(project_id) DO UPDATE SET
+			updater_id = EXCLUDED.updater_id,
+			payload = EXCLUDED.payload
+		RETURNING ` + projectSQLReviewOverrideColumns
+	raw, err := scanProjectSQLReviewOverrideRaw(tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.ProjectID,
+		upsert.Payload,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return raw, nil
+}
+
+func getProjectSQLReviewOverrideImpl(ctx context.Context, tx *sql.Tx, projectID int) (*projectSQLReviewOverrideRaw, error) {
+	raw, err := scanProjectSQLReviewOverrideRaw(tx.QueryRowContext(ctx, `
+		SELECT `+projectSQLReviewOverrideColumns+`
+		FROM project_sql_review_override
+		WHERE project_id = $1`,
+		projectID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, FormatError(err)
+	}
+	return raw, nil
+}
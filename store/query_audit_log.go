@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/pkg/errors"
+)
+
+// CreateQueryAuditLog creates an instance of QueryAuditLog.
+func (s *Store) CreateQueryAuditLog(ctx context.Context, create *api.QueryAuditLogCreate) (*api.QueryAuditLog, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	queryAuditLog, err := createQueryAuditLogImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create QueryAuditLog with QueryAuditLogCreate[%+v]", create)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := s.composeQueryAuditLog(ctx, queryAuditLog); err != nil {
+		return nil, err
+	}
+	return queryAuditLog, nil
+}
+
+// FindQueryAuditLog finds a list of QueryAuditLog instances, the latest first.
+func (s *Store) FindQueryAuditLog(ctx context.Context, find *api.QueryAuditLogFind) ([]*api.QueryAuditLog, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findQueryAuditLogImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find QueryAuditLog list with QueryAuditLogFind[%+v]", find)
+	}
+
+	for _, queryAuditLog := range list {
+		if err := s.composeQueryAuditLog(ctx, queryAuditLog); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// DeleteQueryAuditLogBefore deletes the query audit logs created before the time.
+func (s *Store) DeleteQueryAuditLogBefore(ctx context.Context, createdTs int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM query_audit_log WHERE created_ts < $1`, createdTs); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+func (s *Store) composeQueryAuditLog(ctx context.Context, queryAuditLog *api.QueryAuditLog) error {
+	creator, err := s.GetPrincipalByID(ctx, queryAuditLog.CreatorID)
+	if err != nil {
+		return err
+	}
+	queryAuditLog.Creator = creator
+	return nil
+}
+
+// createQueryAuditLogImpl creates a new query audit log.
+func createQueryAuditLogImpl(ctx context.Context, tx *sql.Tx, create *api.QueryAuditLogCreate) (*api.QueryAuditLog, error) {
+	// Insert row into database.
+	query := `
+		INSERT INTO query_audit_log (
+			creator_id,
+			type,
+			instance_id,
+			database_name,
+			statement,
+			fingerprint,
+			row_count,
+			duration_ns,
+			error
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, creator_id, created_ts, type, instance_id, database_name, statement, fingerprint, row_count, duration_ns, error
+	`
+	var queryAuditLog api.QueryAuditLog
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.Type,
+		create.InstanceID,
+		create.DatabaseName,
+		create.Statement,
+		create.Fingerprint,
+		create.RowCount,
+		create.DurationNs,
+		create.Error,
+	).Scan(
+		&queryAuditLog.ID,
+		&queryAuditLog.CreatorID,
+		&queryAuditLog.CreatedTs,
+		&queryAuditLog.Type,
+		&queryAuditLog.InstanceID,
+		&queryAuditLog.DatabaseName,
+		&queryAuditLog.Statement,
+		&queryAuditLog.Fingerprint,
+		&queryAuditLog.RowCount,
+		&queryAuditLog.DurationNs,
+		&queryAuditLog.Error,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	return &queryAuditLog, nil
+}
+
+func findQueryAuditLogImpl(ctx context.Context, tx *sql.Tx, find *api.QueryAuditLogFind) ([]*api.QueryAuditLog, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id IN (SELECT id FROM instance WHERE workspace_id = $%d)", len(args)+1)), append(args, *v)
+	}
+	if v := find.Type; v != nil {
+		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseName; v != nil {
+		where, args = append(where, fmt.Sprintf("database_name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Fingerprint; v != nil {
+		where, args = append(where, fmt.Sprintf("fingerprint = $%d", len(args)+1)), append(args, *v)
+	}
+
+	query := `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			type,
+			instance_id,
+			database_name,
+			statement,
+			fingerprint,
+			row_count,
+			duration_ns,
+			error
+		FROM query_audit_log
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY created_ts DESC, id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into queryAuditLogList.
+	var queryAuditLogList []*api.QueryAuditLog
+	for rows.Next() {
+		var queryAuditLog api.QueryAuditLog
+		if err := rows.Scan(
+			&queryAuditLog.ID,
+			&queryAuditLog.CreatorID,
+			&queryAuditLog.CreatedTs,
+			&queryAuditLog.Type,
+			&queryAuditLog.InstanceID,
+			&queryAuditLog.DatabaseName,
+			&queryAuditLog.Statement,
+			&queryAuditLog.Fingerprint,
+			&queryAuditLog.RowCount,
+			&queryAuditLog.DurationNs,
+			&queryAuditLog.Error,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		queryAuditLogList = append(queryAuditLogList, &queryAuditLog)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return queryAuditLogList, nil
+}
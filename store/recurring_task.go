@@ -0,0 +1,381 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/pkg/errors"
+)
+
+// recurringTaskRaw is the store model for a RecurringTask.
+// Fields have exactly the same meanings as RecurringTask.
+type recurringTaskRaw struct {
+	ID int
+
+	// Standard fields
+	RowStatus api.RowStatus
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Name            string
+	Statement       string
+	Schedule        string
+	MaxAffectedRows int64
+	NextRunTs       int64
+	LastIssueID     int
+}
+
+// toRecurringTask creates an instance of RecurringTask based on the recurringTaskRaw.
+// This is intended to be called when we need to compose a RecurringTask relationship.
+func (raw *recurringTaskRaw) toRecurringTask() *api.RecurringTask {
+	return &api.RecurringTask{
+		ID: raw.ID,
+
+		// Standard fields
+		RowStatus: raw.RowStatus,
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		Name:            raw.Name,
+		Statement:       raw.Statement,
+		Schedule:        raw.Schedule,
+		MaxAffectedRows: raw.MaxAffectedRows,
+		NextRunTs:       raw.NextRunTs,
+		LastIssueID:     raw.LastIssueID,
+	}
+}
+
+// CreateRecurringTask creates an instance of RecurringTask.
+func (s *Store) CreateRecurringTask(ctx context.Context, create *api.RecurringTaskCreate) (*api.RecurringTask, error) {
+	recurringTaskRaw, err := s.createRecurringTaskRaw(ctx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create RecurringTask with RecurringTaskCreate[%+v]", create)
+	}
+	recurringTask, err := s.composeRecurringTask(ctx, recurringTaskRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose RecurringTask with recurringTaskRaw[%+v]", recurringTaskRaw)
+	}
+	return recurringTask, nil
+}
+
+// GetRecurringTaskByID gets an instance of RecurringTask.
+func (s *Store) GetRecurringTaskByID(ctx context.Context, id int) (*api.RecurringTask, error) {
+	find := &api.RecurringTaskFind{ID: &id}
+	recurringTaskRawList, err := s.findRecurringTaskRaw(ctx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get RecurringTask with ID %d", id)
+	}
+	if len(recurringTaskRawList) == 0 {
+		return nil, nil
+	} else if len(recurringTaskRawList) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d recurring tasks with filter %+v, expect 1", len(recurringTaskRawList), find)}
+	}
+	recurringTask, err := s.composeRecurringTask(ctx, recurringTaskRawList[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose RecurringTask with recurringTaskRaw[%+v]", recurringTaskRawList[0])
+	}
+	return recurringTask, nil
+}
+
+// FindRecurringTask finds a list of RecurringTask instances.
+func (s *Store) FindRecurringTask(ctx context.Context, find *api.RecurringTaskFind) ([]*api.RecurringTask, error) {
+	recurringTaskRawList, err := s.findRecurringTaskRaw(ctx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find RecurringTask list with RecurringTaskFind[%+v]", find)
+	}
+	var recurringTaskList []*api.RecurringTask
+	for _, raw := range recurringTaskRawList {
+		recurringTask, err := s.composeRecurringTask(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose RecurringTask with recurringTaskRaw[%+v]", raw)
+		}
+		recurringTaskList = append(recurringTaskList, recurringTask)
+	}
+	return recurringTaskList, nil
+}
+
+// PatchRecurringTask patches an instance of RecurringTask.
+func (s *Store) PatchRecurringTask(ctx context.Context, patch *api.RecurringTaskPatch) (*api.RecurringTask, error) {
+	recurringTaskRaw, err := s.patchRecurringTaskRaw(ctx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch RecurringTask with RecurringTaskPatch[%+v]", patch)
+	}
+	recurringTask, err := s.composeRecurringTask(ctx, recurringTaskRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose RecurringTask with recurringTaskRaw[%+v]", recurringTaskRaw)
+	}
+	return recurringTask, nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeRecurringTask(ctx context.Context, raw *recurringTaskRaw) (*api.RecurringTask, error) {
+	recurringTask := raw.toRecurringTask()
+
+	creator, err := s.GetPrincipalByID(ctx, recurringTask.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	recurringTask.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, recurringTask.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	recurringTask.Updater = updater
+
+	database, err := s.GetDatabase(ctx, &api.DatabaseFind{ID: &recurringTask.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+	recurringTask.Database = database
+
+	return recurringTask, nil
+}
+
+// createRecurringTaskRaw creates a new recurring task.
+func (s *Store) createRecurringTaskRaw(ctx context.Context, create *api.RecurringTaskCreate) (*recurringTaskRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	recurringTask, err := createRecurringTaskImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return recurringTask, nil
+}
+
+// findRecurringTaskRaw retrieves a list of recurring tasks based on find.
+func (s *Store) findRecurringTaskRaw(ctx context.Context, find *api.RecurringTaskFind) ([]*recurringTaskRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findRecurringTaskImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// patchRecurringTaskRaw updates an existing recurring task by ID.
+// Returns ENOTFOUND if recurring task does not exist.
+func (s *Store) patchRecurringTaskRaw(ctx context.Context, patch *api.RecurringTaskPatch) (*recurringTaskRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	recurringTask, err := patchRecurringTaskImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return recurringTask, nil
+}
+
+const recurringTaskColumns = `
+			id,
+			row_status,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			name,
+			statement,
+			schedule,
+			max_affected_rows,
+			next_run_ts,
+			last_issue_id`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRecurringTaskRaw(row rowScanner) (*recurringTaskRaw, error) {
+	var recurringTaskRaw recurringTaskRaw
+	var nullLastIssueID sql.NullInt64
+	if err := row.Scan(
+		&recurringTaskRaw.ID,
+		&recurringTaskRaw.RowStatus,
+		&recurringTaskRaw.CreatorID,
+		&recurringTaskRaw.CreatedTs,
+		&recurringTaskRaw.UpdaterID,
+		&recurringTaskRaw.UpdatedTs,
+		&recurringTaskRaw.DatabaseID,
+		&recurringTaskRaw.Name,
+		&recurringTaskRaw.Statement,
+		&recurringTaskRaw.Schedule,
+		&recurringTaskRaw.MaxAffectedRows,
+		&recurringTaskRaw.NextRunTs,
+		&nullLastIssueID,
+	); err != nil {
+		return nil, err
+	}
+	if nullLastIssueID.Valid {
+		recurringTaskRaw.LastIssueID = int(nullLastIssueID.Int64)
+	}
+	return &recurringTaskRaw, nil
+}
+
+// createRecurringTaskImpl creates a new recurring task.
+func createRecurringTaskImpl(ctx context.Context, tx *sql.Tx, create *api.RecurringTaskCreate) (*recurringTaskRaw, error) {
+	// Insert row into database.
+	query := `
+		INSERT INTO recurring_task (
+			creator_id,
+			updater_id,
+			database_id,
+			name,
+			statement,
+			schedule,
+			max_affected_rows,
+			next_run_ts
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + recurringTaskColumns
+	recurringTaskRaw, err := scanRecurringTaskRaw(tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.Name,
+		create.Statement,
+		create.Schedule,
+		create.MaxAffectedRows,
+		create.NextRunTs,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return recurringTaskRaw, nil
+}
+
+func findRecurringTaskImpl(ctx context.Context, tx *sql.Tx, find *api.RecurringTaskFind) ([]*recurringTaskRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id IN (SELECT db.id FROM db, instance WHERE db.instance_id = instance.id AND instance.workspace_id = $%d)", len(args)+1)), append(args, *v)
+	}
+	if v := find.NextRunTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("next_run_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+recurringTaskColumns+`
+		FROM recurring_task
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into recurringTaskRawList.
+	var recurringTaskRawList []*recurringTaskRaw
+	for rows.Next() {
+		recurringTaskRaw, err := scanRecurringTaskRaw(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		recurringTaskRawList = append(recurringTaskRawList, recurringTaskRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return recurringTaskRawList, nil
+}
+
+// patchRecurringTaskImpl updates a recurring task by ID. Returns the new state of the recurring task after update.
+func patchRecurringTaskImpl(ctx context.Context, tx *sql.Tx, patch *api.RecurringTaskPatch) (*recurringTaskRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.RowStatus; v != nil {
+		set, args = append(set, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.RowStatus(*v))
+	}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Statement; v != nil {
+		set, args = append(set, fmt.Sprintf("statement = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Schedule; v != nil {
+		set, args = append(set, fmt.Sprintf("schedule = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.MaxAffectedRows; v != nil {
+		set, args = append(set, fmt.Sprintf("max_affected_rows = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.NextRunTs; v != nil {
+		set, args = append(set, fmt.Sprintf("next_run_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.LastIssueID; v != nil {
+		set, args = append(set, fmt.Sprintf("last_issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	// Execute update query with RETURNING.
+	recurringTaskRaw, err := scanRecurringTaskRaw(tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE recurring_task
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING `+recurringTaskColumns, len(args)),
+		args...,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("recurring task ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return recurringTaskRaw, nil
+}
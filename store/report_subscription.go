@@ -0,0 +1,297 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// reportSubscriptionRaw is the store model for a ReportSubscription.
+// Fields have exactly the same meanings as ReportSubscription.
+type reportSubscriptionRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID   int
+	PrincipalID int
+
+	// Domain specific fields
+	Enabled    bool
+	LastSentTs int64
+}
+
+// toReportSubscription creates an instance of ReportSubscription based on the reportSubscriptionRaw.
+// This is intended to be called when we need to compose a ReportSubscription relationship.
+func (raw *reportSubscriptionRaw) toReportSubscription() *api.ReportSubscription {
+	return &api.ReportSubscription{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID:   raw.ProjectID,
+		PrincipalID: raw.PrincipalID,
+
+		// Domain specific fields
+		Enabled:    raw.Enabled,
+		LastSentTs: raw.LastSentTs,
+	}
+}
+
+// UpsertReportSubscription creates or updates the subscription of the principal to the report of the project.
+func (s *Store) UpsertReportSubscription(ctx context.Context, upsert *api.ReportSubscriptionUpsert) (*api.ReportSubscription, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	reportSubscriptionRaw, err := upsertReportSubscriptionImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert ReportSubscription with ReportSubscriptionUpsert[%+v]", upsert)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	reportSubscription, err := s.composeReportSubscription(ctx, reportSubscriptionRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose ReportSubscription with reportSubscriptionRaw[%+v]", reportSubscriptionRaw)
+	}
+	return reportSubscription, nil
+}
+
+// GetReportSubscription gets an instance of ReportSubscription.
+func (s *Store) GetReportSubscription(ctx context.Context, find *api.ReportSubscriptionFind) (*api.ReportSubscription, error) {
+	list, err := s.FindReportSubscription(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d report subscriptions with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// FindReportSubscription finds a list of ReportSubscription instances.
+func (s *Store) FindReportSubscription(ctx context.Context, find *api.ReportSubscriptionFind) ([]*api.ReportSubscription, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	reportSubscriptionRawList, err := findReportSubscriptionImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find ReportSubscription list with ReportSubscriptionFind[%+v]", find)
+	}
+	var reportSubscriptionList []*api.ReportSubscription
+	for _, raw := range reportSubscriptionRawList {
+		reportSubscription, err := s.composeReportSubscription(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose ReportSubscription with reportSubscriptionRaw[%+v]", raw)
+		}
+		reportSubscriptionList = append(reportSubscriptionList, reportSubscription)
+	}
+	return reportSubscriptionList, nil
+}
+
+// PatchReportSubscription patches an instance of ReportSubscription.
+func (s *Store) PatchReportSubscription(ctx context.Context, patch *api.ReportSubscriptionPatch) (*api.ReportSubscription, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	reportSubscriptionRaw, err := patchReportSubscriptionImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch ReportSubscription with ReportSubscriptionPatch[%+v]", patch)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	reportSubscription, err := s.composeReportSubscription(ctx, reportSubscriptionRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose ReportSubscription with reportSubscriptionRaw[%+v]", reportSubscriptionRaw)
+	}
+	return reportSubscription, nil
+}
+
+//
+// private function
+//
+
+func (s *Store) composeReportSubscription(ctx context.Context, raw *reportSubscriptionRaw) (*api.ReportSubscription, error) {
+	reportSubscription := raw.toReportSubscription()
+
+	creator, err := s.GetPrincipalByID(ctx, reportSubscription.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	reportSubscription.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, reportSubscription.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	reportSubscription.Updater = updater
+
+	return reportSubscription, nil
+}
+
+// upsertReportSubscriptionImpl creates the subscription, or updates the enabled flag of the existing one.
+func upsertReportSubscriptionImpl(ctx context.Context, tx *sql.Tx, upsert *api.ReportSubscriptionUpsert) (*reportSubscriptionRaw, error) {
+	query := `
+		INSERT INTO report_subscription (
+			creator_id,
+			updater_id,
+			project_id,
+			principal_id,
+			enabled
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(project_id, principal_id) DO UPDATE SET
+			updater_id = excluded.updater_id,
+			enabled = excluded.enabled
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, principal_id, enabled, last_sent_ts
+	`
+	row := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.ProjectID,
+		upsert.PrincipalID,
+		upsert.Enabled,
+	)
+	reportSubscriptionRaw, err := scanReportSubscriptionRaw(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return reportSubscriptionRaw, nil
+}
+
+func findReportSubscriptionImpl(ctx context.Context, tx *sql.Tx, find *api.ReportSubscriptionFind) ([]*reportSubscriptionRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PrincipalID; v != nil {
+		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Enabled; v != nil {
+		where, args = append(where, fmt.Sprintf("enabled = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			principal_id,
+			enabled,
+			last_sent_ts
+		FROM report_subscription
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY project_id ASC, id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into reportSubscriptionRawList.
+	var reportSubscriptionRawList []*reportSubscriptionRaw
+	for rows.Next() {
+		reportSubscriptionRaw, err := scanReportSubscriptionRaw(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		reportSubscriptionRawList = append(reportSubscriptionRawList, reportSubscriptionRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return reportSubscriptionRawList, nil
+}
+
+// patchReportSubscriptionImpl updates a report subscription by ID. Returns the new state of the report subscription after update.
+func patchReportSubscriptionImpl(ctx context.Context, tx *sql.Tx, patch *api.ReportSubscriptionPatch) (*reportSubscriptionRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.LastSentTs; v != nil {
+		set, args = append(set, fmt.Sprintf("last_sent_ts = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	// Execute update query with RETURNING.
+	row := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE report_subscription
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, principal_id, enabled, last_sent_ts
+	`, len(args)),
+		args...,
+	)
+	reportSubscriptionRaw, err := scanReportSubscriptionRaw(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("report subscription ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return reportSubscriptionRaw, nil
+}
+
+func scanReportSubscriptionRaw(row interface {
+	Scan(dest ...interface{}) error
+}) (*reportSubscriptionRaw, error) {
+	var reportSubscriptionRaw reportSubscriptionRaw
+	if err := row.Scan(
+		&reportSubscriptionRaw.ID,
+		&reportSubscriptionRaw.CreatorID,
+		&reportSubscriptionRaw.CreatedTs,
+		&reportSubscriptionRaw.UpdaterID,
+		&reportSubscriptionRaw.UpdatedTs,
+		&reportSubscriptionRaw.ProjectID,
+		&reportSubscriptionRaw.PrincipalID,
+		&reportSubscriptionRaw.Enabled,
+		&reportSubscriptionRaw.LastSentTs,
+	); err != nil {
+		return nil, err
+	}
+	return &reportSubscriptionRaw, nil
+}
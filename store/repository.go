@@ -461,6 +461,9 @@ func findRepositoryImpl(ctx context.Context, tx *sql.Tx, find *api.RepositoryFin
 	if v := find.WebhookEndpointID; v != nil {
 		where, args = append(where, fmt.Sprintf("webhook_endpoint_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.ExternalID; v != nil {
+		where, args = append(where, fmt.Sprintf("external_id = $%d", len(args)+1)), append(args, *v)
+	}
 
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
@@ -0,0 +1,248 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// CreateSchemaSnapshot creates an instance of SchemaSnapshot, the schema is stored compressed.
+func (s *Store) CreateSchemaSnapshot(ctx context.Context, create *api.SchemaSnapshotCreate) (*api.SchemaSnapshot, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	schemaSnapshot, err := createSchemaSnapshotImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create SchemaSnapshot of database ID %d", create.DatabaseID)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := s.composeSchemaSnapshot(ctx, schemaSnapshot); err != nil {
+		return nil, err
+	}
+	return schemaSnapshot, nil
+}
+
+// FindSchemaSnapshot finds a list of SchemaSnapshot instances without the schema, ordered from the newest.
+func (s *Store) FindSchemaSnapshot(ctx context.Context, find *api.SchemaSnapshotFind) ([]*api.SchemaSnapshot, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findSchemaSnapshotImpl(ctx, tx.PTx, find, false /* withSchema */)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find SchemaSnapshot list with SchemaSnapshotFind[%+v]", find)
+	}
+
+	for _, schemaSnapshot := range list {
+		if err := s.composeSchemaSnapshot(ctx, schemaSnapshot); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// GetSchemaSnapshot gets the newest SchemaSnapshot matching the find with the schema.
+// Returns nil if not found.
+func (s *Store) GetSchemaSnapshot(ctx context.Context, find *api.SchemaSnapshotFind) (*api.SchemaSnapshot, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	limit := 1
+	findWithLimit := *find
+	findWithLimit.Limit = &limit
+	list, err := findSchemaSnapshotImpl(ctx, tx.PTx, &findWithLimit, true /* withSchema */)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get SchemaSnapshot with SchemaSnapshotFind[%+v]", find)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	if err := s.composeSchemaSnapshot(ctx, list[0]); err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+func (s *Store) composeSchemaSnapshot(ctx context.Context, schemaSnapshot *api.SchemaSnapshot) error {
+	creator, err := s.GetPrincipalByID(ctx, schemaSnapshot.CreatorID)
+	if err != nil {
+		return err
+	}
+	schemaSnapshot.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, schemaSnapshot.UpdaterID)
+	if err != nil {
+		return err
+	}
+	schemaSnapshot.Updater = updater
+	return nil
+}
+
+func createSchemaSnapshotImpl(ctx context.Context, tx *sql.Tx, create *api.SchemaSnapshotCreate) (*api.SchemaSnapshot, error) {
+	schema, err := compressSchema(create.Schema)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		INSERT INTO schema_snapshot (
+			creator_id,
+			updater_id,
+			database_id,
+			type,
+			migration_version,
+			schema
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, type, migration_version
+	`
+	schemaSnapshot := api.SchemaSnapshot{
+		Schema: create.Schema,
+	}
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.Type,
+		create.MigrationVersion,
+		schema,
+	).Scan(
+		&schemaSnapshot.ID,
+		&schemaSnapshot.CreatorID,
+		&schemaSnapshot.CreatedTs,
+		&schemaSnapshot.UpdaterID,
+		&schemaSnapshot.UpdatedTs,
+		&schemaSnapshot.DatabaseID,
+		&schemaSnapshot.Type,
+		&schemaSnapshot.MigrationVersion,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	return &schemaSnapshot, nil
+}
+
+func findSchemaSnapshotImpl(ctx context.Context, tx *sql.Tx, find *api.SchemaSnapshotFind, withSchema bool) ([]*api.SchemaSnapshot, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Type; v != nil {
+		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+
+	// The schema is fetched only when needed since it's much larger than the other fields.
+	schemaColumn := "''::BYTEA"
+	if withSchema {
+		schemaColumn = "schema"
+	}
+	query := `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			type,
+			migration_version,
+			` + schemaColumn + `
+		FROM schema_snapshot
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY created_ts DESC, id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into schemaSnapshotList.
+	var schemaSnapshotList []*api.SchemaSnapshot
+	for rows.Next() {
+		var schemaSnapshot api.SchemaSnapshot
+		var schema []byte
+		if err := rows.Scan(
+			&schemaSnapshot.ID,
+			&schemaSnapshot.CreatorID,
+			&schemaSnapshot.CreatedTs,
+			&schemaSnapshot.UpdaterID,
+			&schemaSnapshot.UpdatedTs,
+			&schemaSnapshot.DatabaseID,
+			&schemaSnapshot.Type,
+			&schemaSnapshot.MigrationVersion,
+			&schema,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if withSchema {
+			if schemaSnapshot.Schema, err = decompressSchema(schema); err != nil {
+				return nil, errors.Wrapf(err, "failed to decompress schema snapshot %d", schemaSnapshot.ID)
+			}
+		}
+
+		schemaSnapshotList = append(schemaSnapshotList, &schemaSnapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return schemaSnapshotList, nil
+}
+
+func compressSchema(schema string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(schema)); err != nil {
+		return nil, errors.Wrap(err, "failed to compress schema")
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to compress schema")
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressSchema(data []byte) (string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	schema, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(schema), nil
+}
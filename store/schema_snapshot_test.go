@@ -0,0 +1,25 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressSchema(t *testing.T) {
+	schema := strings.Repeat("CREATE TABLE `t` (\n  `id` int NOT NULL\n);\n", 100)
+	data, err := compressSchema(schema)
+	require.NoError(t, err)
+	require.Less(t, len(data), len(schema))
+
+	got, err := decompressSchema(data)
+	require.NoError(t, err)
+	require.Equal(t, schema, got)
+
+	data, err = compressSchema("")
+	require.NoError(t, err)
+	got, err = decompressSchema(data)
+	require.NoError(t, err)
+	require.Equal(t, "", got)
+}
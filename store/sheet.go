@@ -460,6 +460,9 @@ func findSheetImpl(ctx context.Context, tx *sql.Tx, find *api.SheetFind) ([]*she
 	if v := find.DatabaseID; v != nil {
 		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id IN (SELECT principal_id FROM member WHERE workspace_id = $%d)", len(args)+1)), append(args, *v)
+	}
 
 	// Domain fields
 	if v := find.Visibility; v != nil {
@@ -27,7 +27,9 @@ type stageRaw struct {
 	EnvironmentID int
 
 	// Domain specific fields
-	Name string
+	Name        string
+	Paused      bool
+	SoakSeconds int64
 }
 
 // toStage creates an instance of Stage based on the stageRaw.
@@ -47,7 +49,9 @@ func (raw *stageRaw) toStage() *api.Stage {
 		EnvironmentID: raw.EnvironmentID,
 
 		// Domain specific fields
-		Name: raw.Name,
+		Name:        raw.Name,
+		Paused:      raw.Paused,
+		SoakSeconds: raw.SoakSeconds,
 	}
 }
 
@@ -81,6 +85,30 @@ func (s *Store) FindStage(ctx context.Context, find *api.StageFind) ([]*api.Stag
 	return stageList, nil
 }
 
+// PatchStage patches an instance of Stage.
+func (s *Store) PatchStage(ctx context.Context, patch *api.StagePatch) (*api.Stage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	stageRaw, err := patchStageImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch Stage with StagePatch[%+v]", patch)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	stage, err := s.composeStage(ctx, stageRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose Stage with stageRaw[%+v]", stageRaw)
+	}
+	return stage, nil
+}
+
 //
 // private functions
 //
@@ -188,10 +216,12 @@ func (*Store) createStageImpl(ctx context.Context, tx *sql.Tx, create *api.Stage
 			updater_id,
 			pipeline_id,
 			environment_id,
-			name
+			name,
+			paused,
+			soak_seconds
 		)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, environment_id, name` + `
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, environment_id, name, paused, soak_seconds
 	`
 	var stageRaw stageRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -200,6 +230,8 @@ func (*Store) createStageImpl(ctx context.Context, tx *sql.Tx, create *api.Stage
 		create.PipelineID,
 		create.EnvironmentID,
 		create.Name,
+		create.Paused,
+		create.SoakSeconds,
 	).Scan(
 		&stageRaw.ID,
 		&stageRaw.CreatorID,
@@ -209,6 +241,8 @@ func (*Store) createStageImpl(ctx context.Context, tx *sql.Tx, create *api.Stage
 		&stageRaw.PipelineID,
 		&stageRaw.EnvironmentID,
 		&stageRaw.Name,
+		&stageRaw.Paused,
+		&stageRaw.SoakSeconds,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -237,7 +271,9 @@ func (*Store) findStageImpl(ctx context.Context, tx *sql.Tx, find *api.StageFind
 			updated_ts,
 			pipeline_id,
 			environment_id,
-			name
+			name,
+			paused,
+			soak_seconds
 		FROM stage
 		WHERE `+strings.Join(where, " AND ")+` ORDER BY id ASC`,
 		args...,
@@ -260,6 +296,8 @@ func (*Store) findStageImpl(ctx context.Context, tx *sql.Tx, find *api.StageFind
 			&stageRaw.PipelineID,
 			&stageRaw.EnvironmentID,
 			&stageRaw.Name,
+			&stageRaw.Paused,
+			&stageRaw.SoakSeconds,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -272,3 +310,41 @@ func (*Store) findStageImpl(ctx context.Context, tx *sql.Tx, find *api.StageFind
 
 	return stageRawList, nil
 }
+
+// patchStageImpl updates a stage by ID. Returns the new state of the stage after update.
+func patchStageImpl(ctx context.Context, tx *sql.Tx, patch *api.StagePatch) (*stageRaw, error) {
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Paused; v != nil {
+		set, args = append(set, fmt.Sprintf("paused = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SoakSeconds; v != nil {
+		set, args = append(set, fmt.Sprintf("soak_seconds = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	query := `
+		UPDATE stage
+		SET ` + strings.Join(set, ", ") + `
+		WHERE id = ` + fmt.Sprintf("$%d", len(args)) + `
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, environment_id, name, paused, soak_seconds
+	`
+	var stageRaw stageRaw
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(
+		&stageRaw.ID,
+		&stageRaw.CreatorID,
+		&stageRaw.CreatedTs,
+		&stageRaw.UpdaterID,
+		&stageRaw.UpdatedTs,
+		&stageRaw.PipelineID,
+		&stageRaw.EnvironmentID,
+		&stageRaw.Name,
+		&stageRaw.Paused,
+		&stageRaw.SoakSeconds,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("stage ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &stageRaw, nil
+}
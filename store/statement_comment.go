@@ -0,0 +1,352 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// statementCommentRaw is the store model for a StatementComment.
+// Fields have exactly the same meanings as StatementComment.
+type statementCommentRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	IssueID int
+	TaskID  int
+
+	// Domain specific fields
+	StatementHash string
+	Line          int
+	Content       string
+	Resolved      bool
+}
+
+// toStatementComment creates an instance of StatementComment based on the statementCommentRaw.
+// This is intended to be called when we need to compose a StatementComment relationship.
+func (raw *statementCommentRaw) toStatementComment() *api.StatementComment {
+	return &api.StatementComment{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		IssueID: raw.IssueID,
+		TaskID:  raw.TaskID,
+
+		// Domain specific fields
+		StatementHash: raw.StatementHash,
+		Line:          raw.Line,
+		Content:       raw.Content,
+		Resolved:      raw.Resolved,
+	}
+}
+
+// CreateStatementComment creates an instance of StatementComment.
+func (s *Store) CreateStatementComment(ctx context.Context, create *api.StatementCommentCreate) (*api.StatementComment, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	statementCommentRaw, err := createStatementCommentImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create StatementComment with StatementCommentCreate[%+v]", create)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	statementComment, err := s.composeStatementComment(ctx, statementCommentRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose StatementComment with statementCommentRaw[%+v]", statementCommentRaw)
+	}
+	return statementComment, nil
+}
+
+// GetStatementComment gets an instance of StatementComment.
+func (s *Store) GetStatementComment(ctx context.Context, find *api.StatementCommentFind) (*api.StatementComment, error) {
+	list, err := s.FindStatementComment(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d statement comments with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// FindStatementComment finds a list of StatementComment instances.
+func (s *Store) FindStatementComment(ctx context.Context, find *api.StatementCommentFind) ([]*api.StatementComment, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	statementCommentRawList, err := findStatementCommentImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find StatementComment list with StatementCommentFind[%+v]", find)
+	}
+	var statementCommentList []*api.StatementComment
+	for _, raw := range statementCommentRawList {
+		statementComment, err := s.composeStatementComment(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose StatementComment with statementCommentRaw[%+v]", raw)
+		}
+		statementCommentList = append(statementCommentList, statementComment)
+	}
+	return statementCommentList, nil
+}
+
+// CountUnresolvedStatementComment counts the unresolved statement comments of the task.
+func (s *Store) CountUnresolvedStatementComment(ctx context.Context, taskID int) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	var count int
+	if err := tx.PTx.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM statement_comment
+		WHERE task_id = $1 AND resolved = FALSE`,
+		taskID,
+	).Scan(&count); err != nil {
+		return 0, FormatError(err)
+	}
+	return count, nil
+}
+
+// PatchStatementComment patches an instance of StatementComment.
+func (s *Store) PatchStatementComment(ctx context.Context, patch *api.StatementCommentPatch) (*api.StatementComment, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	statementCommentRaw, err := patchStatementCommentImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch StatementComment with StatementCommentPatch[%+v]", patch)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	statementComment, err := s.composeStatementComment(ctx, statementCommentRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose StatementComment with statementCommentRaw[%+v]", statementCommentRaw)
+	}
+	return statementComment, nil
+}
+
+// DeleteStatementComment deletes an existing statement comment by ID.
+// Returns ENOTFOUND if the statement comment does not exist.
+func (s *Store) DeleteStatementComment(ctx context.Context, delete *api.StatementCommentDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `DELETE FROM statement_comment WHERE id = $1`, delete.ID)
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("statement comment ID not found: %d", delete.ID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+//
+// private function
+//
+
+func (s *Store) composeStatementComment(ctx context.Context, raw *statementCommentRaw) (*api.StatementComment, error) {
+	statementComment := raw.toStatementComment()
+
+	creator, err := s.GetPrincipalByID(ctx, statementComment.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	statementComment.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, statementComment.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	statementComment.Updater = updater
+
+	return statementComment, nil
+}
+
+// createStatementCommentImpl creates a new statement comment.
+func createStatementCommentImpl(ctx context.Context, tx *sql.Tx, create *api.StatementCommentCreate) (*statementCommentRaw, error) {
+	query := `
+		INSERT INTO statement_comment (
+			creator_id,
+			updater_id,
+			issue_id,
+			task_id,
+			statement_hash,
+			line,
+			content
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, issue_id, task_id, statement_hash, line, content, resolved
+	`
+	row := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.IssueID,
+		create.TaskID,
+		create.StatementHash,
+		create.Line,
+		create.Content,
+	)
+	statementCommentRaw, err := scanStatementCommentRaw(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return statementCommentRaw, nil
+}
+
+func findStatementCommentImpl(ctx context.Context, tx *sql.Tx, find *api.StatementCommentFind) ([]*statementCommentRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.IssueID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TaskID; v != nil {
+		where, args = append(where, fmt.Sprintf("task_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Resolved; v != nil {
+		where, args = append(where, fmt.Sprintf("resolved = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			issue_id,
+			task_id,
+			statement_hash,
+			line,
+			content,
+			resolved
+		FROM statement_comment
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY task_id ASC, line ASC, id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into statementCommentRawList.
+	var statementCommentRawList []*statementCommentRaw
+	for rows.Next() {
+		statementCommentRaw, err := scanStatementCommentRaw(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		statementCommentRawList = append(statementCommentRawList, statementCommentRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return statementCommentRawList, nil
+}
+
+// patchStatementCommentImpl updates a statement comment by ID. Returns the new state of the statement comment after update.
+func patchStatementCommentImpl(ctx context.Context, tx *sql.Tx, patch *api.StatementCommentPatch) (*statementCommentRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Content; v != nil {
+		set, args = append(set, fmt.Sprintf("content = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Resolved; v != nil {
+		set, args = append(set, fmt.Sprintf("resolved = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	// Execute update query with RETURNING.
+	row := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE statement_comment
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, issue_id, task_id, statement_hash, line, content, resolved
+	`, len(args)),
+		args...,
+	)
+	statementCommentRaw, err := scanStatementCommentRaw(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("statement comment ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return statementCommentRaw, nil
+}
+
+func scanStatementCommentRaw(row interface {
+	Scan(dest ...interface{}) error
+}) (*statementCommentRaw, error) {
+	var statementCommentRaw statementCommentRaw
+	if err := row.Scan(
+		&statementCommentRaw.ID,
+		&statementCommentRaw.CreatorID,
+		&statementCommentRaw.CreatedTs,
+		&statementCommentRaw.UpdaterID,
+		&statementCommentRaw.UpdatedTs,
+		&statementCommentRaw.IssueID,
+		&statementCommentRaw.TaskID,
+		&statementCommentRaw.StatementHash,
+		&statementCommentRaw.Line,
+		&statementCommentRaw.Content,
+		&statementCommentRaw.Resolved,
+	); err != nil {
+		return nil, err
+	}
+	return &statementCommentRaw, nil
+}
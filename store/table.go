@@ -28,6 +28,7 @@ type tableRaw struct {
 	DatabaseID int
 
 	// Domain specific fields
+	Schema        string
 	Name          string
 	Type          string
 	Engine        string
@@ -56,6 +57,7 @@ func (raw *tableRaw) toTable() *api.Table {
 		DatabaseID: raw.DatabaseID,
 
 		// Domain specific fields
+		Schema:        raw.Schema,
 		Name:          raw.Name,
 		Type:          raw.Type,
 		Engine:        raw.Engine,
@@ -168,13 +170,20 @@ func (s *Store) SetTableList(ctx context.Context, schema *db.Schema, databaseID
 			}
 		}
 
+		// The table has no index left on the database, so the index metadata is dropped all at once.
+		if len(table.IndexList) == 0 {
+			if err := s.deleteIndexListByTableImpl(ctx, tx.PTx, databaseID, tableID); err != nil {
+				return err
+			}
+			continue
+		}
 		indexList, err := s.findIndexImpl(ctx, tx.PTx, &api.IndexFind{
 			TableID: &tableID,
 		})
 		if err != nil {
 			return err
 		}
-		idxDeletes, idxCreates := generateIndexActions(indexList, table.IndexList, databaseID, tableID)
+		idxDeletes, idxCreates, idxPatches := generateIndexActions(indexList, table.IndexList, databaseID, tableID)
 		for _, d := range idxDeletes {
 			if err := s.deleteIndexImpl(ctx, tx.PTx, d); err != nil {
 				return err
@@ -185,6 +194,11 @@ func (s *Store) SetTableList(ctx context.Context, schema *db.Schema, databaseID
 				return err
 			}
 		}
+		for _, p := range idxPatches {
+			if _, err := s.patchIndexImpl(ctx, tx.PTx, p); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := tx.PTx.Commit(); err != nil {
@@ -247,6 +261,7 @@ func generateTableActions(oldTableRawList []*tableRaw, tableList []db.Table, dat
 				CreatedTs:     newValue.CreatedTs,
 				UpdatedTs:     newValue.UpdatedTs,
 				DatabaseID:    databaseID,
+				Schema:        newValue.Schema,
 				Name:          newValue.Name,
 				Type:          newValue.Type,
 				Engine:        newValue.Engine,
@@ -339,6 +354,7 @@ func (*Store) createTableImpl(ctx context.Context, tx *sql.Tx, create *api.Table
 			updater_id,
 			updated_ts,
 			database_id,
+			schema,
 			name,
 			type,
 			engine,
@@ -350,8 +366,8 @@ func (*Store) createTableImpl(ctx context.Context, tx *sql.Tx, create *api.Table
 			create_options,
 			comment
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, type, engine, "collation", row_count, data_size, index_size, data_free, create_options, comment
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, schema, name, type, engine, "collation", row_count, data_size, index_size, data_free, create_options, comment
 	`
 	var tableRaw tableRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -360,6 +376,7 @@ func (*Store) createTableImpl(ctx context.Context, tx *sql.Tx, create *api.Table
 		create.CreatorID,
 		create.UpdatedTs,
 		create.DatabaseID,
+		create.Schema,
 		create.Name,
 		create.Type,
 		create.Engine,
@@ -377,6 +394,7 @@ func (*Store) createTableImpl(ctx context.Context, tx *sql.Tx, create *api.Table
 		&tableRaw.UpdaterID,
 		&tableRaw.UpdatedTs,
 		&tableRaw.DatabaseID,
+		&tableRaw.Schema,
 		&tableRaw.Name,
 		&tableRaw.Type,
 		&tableRaw.Engine,
@@ -404,7 +422,7 @@ func (*Store) patchTableImpl(ctx context.Context, tx *sql.Tx, patch *api.TablePa
 		UPDATE tbl
 		SET	type=$1, engine=$2, "collation"=$3, row_count=$4, data_size=$5, index_size=$6, data_free=$7, create_options=$8, comment=$9
 		WHERE id = $10
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, type, engine, "collation", row_count, data_size, index_size, data_free, create_options, comment`,
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, schema, name, type, engine, "collation", row_count, data_size, index_size, data_free, create_options, comment`,
 		patch.Type,
 		patch.Engine,
 		patch.Collation,
@@ -422,6 +440,7 @@ func (*Store) patchTableImpl(ctx context.Context, tx *sql.Tx, patch *api.TablePa
 		&tableRaw.UpdaterID,
 		&tableRaw.UpdatedTs,
 		&tableRaw.DatabaseID,
+		&tableRaw.Schema,
 		&tableRaw.Name,
 		&tableRaw.Type,
 		&tableRaw.Engine,
@@ -450,6 +469,9 @@ func (*Store) findTableImpl(ctx context.Context, tx *sql.Tx, find *api.TableFind
 	if v := find.DatabaseID; v != nil {
 		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.Schema; v != nil {
+		where, args = append(where, fmt.Sprintf("schema = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.Name; v != nil {
 		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
@@ -462,6 +484,7 @@ func (*Store) findTableImpl(ctx context.Context, tx *sql.Tx, find *api.TableFind
 			updater_id,
 			updated_ts,
 			database_id,
+			schema,
 			name,
 			type,
 			engine,
@@ -492,6 +515,7 @@ func (*Store) findTableImpl(ctx context.Context, tx *sql.Tx, find *api.TableFind
 			&tableRaw.UpdaterID,
 			&tableRaw.UpdatedTs,
 			&tableRaw.DatabaseID,
+			&tableRaw.Schema,
 			&tableRaw.Name,
 			&tableRaw.Type,
 			&tableRaw.Engine,
@@ -7,6 +7,8 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/pkg/errors"
+
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
@@ -28,12 +30,58 @@ func (s *Store) FindIndex(ctx context.Context, find *api.IndexFind) ([]*api.Inde
 	return list, nil
 }
 
+// PatchIndexList patches a list of indices in a transaction, e.g. all the key parts of an index.
+func (s *Store) PatchIndexList(ctx context.Context, patchList []*api.IndexPatch) ([]*api.Index, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	var indexList []*api.Index
+	for _, patch := range patchList {
+		index, err := s.patchIndexImpl(ctx, tx.PTx, patch)
+		if err != nil {
+			return nil, err
+		}
+		indexList = append(indexList, index)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return indexList, nil
+}
+
+// DeleteIndexListByTable deletes all the indices of a table, e.g. the stale ones after the indices are dropped on the database.
+// The schema sync deletes them in its own transaction with deleteIndexListByTableImpl.
+func (s *Store) DeleteIndexListByTable(ctx context.Context, databaseID, tableID int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if err := s.deleteIndexListByTableImpl(ctx, tx.PTx, databaseID, tableID); err != nil {
+		return err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
 type indexKey struct {
 	name     string
 	position int
 }
 
-func generateIndexActions(oldIndexList []*api.Index, indexList []db.Index, databaseID, tableID int) ([]*api.IndexDelete, []*api.IndexCreate) {
+// generateIndexActions generates the actions syncing the index key parts of a table.
+// The key part only changing the visibility or comment is patched in place, others are recreated.
+func generateIndexActions(oldIndexList []*api.Index, indexList []db.Index, databaseID, tableID int) ([]*api.IndexDelete, []*api.IndexCreate, []*api.IndexPatch) {
 	var indexCreateList []*api.IndexCreate
 	for _, index := range indexList {
 		indexCreateList = append(indexCreateList, &api.IndexCreate{
@@ -61,14 +109,23 @@ func generateIndexActions(oldIndexList []*api.Index, indexList []db.Index, datab
 
 	var deletes []*api.IndexDelete
 	var creates []*api.IndexCreate
+	var patches []*api.IndexPatch
 	for _, oldValue := range oldIndexList {
 		k := indexKey{oldValue.Name, oldValue.Position}
 		newValue, ok := newIndexMap[k]
 		if !ok {
 			deletes = append(deletes, &api.IndexDelete{ID: oldValue.ID})
-		} else if ok && (oldValue.Expression != newValue.Expression || oldValue.Position != newValue.Position || oldValue.Type != newValue.Type || oldValue.Unique != newValue.Unique || oldValue.Primary != newValue.Primary || oldValue.Visible != newValue.Visible || oldValue.Comment != newValue.Comment) {
+		} else if ok && (oldValue.Expression != newValue.Expression || oldValue.Position != newValue.Position || oldValue.Type != newValue.Type || oldValue.Unique != newValue.Unique || oldValue.Primary != newValue.Primary) {
 			deletes = append(deletes, &api.IndexDelete{ID: oldValue.ID})
 			creates = append(creates, newValue)
+		} else if ok && (oldValue.Visible != newValue.Visible || oldValue.Comment != newValue.Comment) {
+			visible, comment := newValue.Visible, newValue.Comment
+			patches = append(patches, &api.IndexPatch{
+				ID:        oldValue.ID,
+				UpdaterID: api.SystemBotID,
+				Visible:   &visible,
+				Comment:   &comment,
+			})
 		}
 	}
 	for _, newValue := range indexCreateList {
@@ -85,8 +142,11 @@ func generateIndexActions(oldIndexList []*api.Index, indexList []db.Index, datab
 	sort.Slice(creates, func(i, j int) bool {
 		return creates[i].Name < creates[j].Name || (creates[i].Name == creates[j].Name && creates[i].Position < creates[j].Position)
 	})
+	sort.Slice(patches, func(i, j int) bool {
+		return patches[i].ID < patches[j].ID
+	})
 
-	return deletes, creates
+	return deletes, creates, patches
 }
 
 // createIndexImpl creates a new index.
@@ -229,6 +289,56 @@ func (*Store) findIndexImpl(ctx context.Context, tx *sql.Tx, find *api.IndexFind
 	return indexList, nil
 }
 
+// patchIndexImpl patches an index.
+func (*Store) patchIndexImpl(ctx context.Context, tx *sql.Tx, patch *api.IndexPatch) (*api.Index, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Visible; v != nil {
+		set, args = append(set, fmt.Sprintf("visible = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Comment; v != nil {
+		set, args = append(set, fmt.Sprintf("comment = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	var index api.Index
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE idx
+		SET %s
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, expression, position, type, "unique", "primary", visible, comment
+	`, strings.Join(set, ", "), len(args)),
+		args...,
+	).Scan(
+		&index.ID,
+		&index.CreatorID,
+		&index.CreatedTs,
+		&index.UpdaterID,
+		&index.UpdatedTs,
+		&index.DatabaseID,
+		&index.TableID,
+		&index.Name,
+		&index.Expression,
+		&index.Position,
+		&index.Type,
+		&index.Unique,
+		&index.Primary,
+		&index.Visible,
+		&index.Comment,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("index ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+
+	return &index, nil
+}
+
 // deleteIndexImpl deletes an index.
 func (*Store) deleteIndexImpl(ctx context.Context, tx *sql.Tx, delete *api.IndexDelete) error {
 	if _, err := tx.ExecContext(ctx, `DELETE FROM idx WHERE id = $1`, delete.ID); err != nil {
@@ -236,3 +346,11 @@ func (*Store) deleteIndexImpl(ctx context.Context, tx *sql.Tx, delete *api.Index
 	}
 	return nil
 }
+
+// deleteIndexListByTableImpl deletes all the indices of a table.
+func (*Store) deleteIndexListByTableImpl(ctx context.Context, tx *sql.Tx, databaseID, tableID int) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM idx WHERE database_id = $1 AND table_id = $2`, databaseID, tableID); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
@@ -1,10 +1,16 @@
 package store
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path"
 	"testing"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/resources/postgres"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,6 +22,7 @@ func TestGenerateIndexActions(t *testing.T) {
 		indexList    []db.Index
 		wantDeletes  []*api.IndexDelete
 		wantCreates  []*api.IndexCreate
+		wantPatches  []*api.IndexPatch
 	}{
 		{
 			oldIndexList: []*api.Index{
@@ -32,14 +39,31 @@ func TestGenerateIndexActions(t *testing.T) {
 			},
 			wantDeletes: []*api.IndexDelete{
 				{ID: 123},
-				{ID: 125},
 			},
 			wantCreates: []*api.IndexCreate{
 				{Name: "index1", Expression: "def1-change", Comment: "comment1", CreatorID: api.SystemBotID, DatabaseID: databaseID, TableID: tableID},
-				{Name: "index2", Expression: "def2", Position: 2, Comment: "comment2-change", CreatorID: api.SystemBotID, DatabaseID: databaseID, TableID: tableID},
 				{Name: "index2", Expression: "def2", Position: 3, Comment: "comment2-new", CreatorID: api.SystemBotID, DatabaseID: databaseID, TableID: tableID},
 				{Name: "index3", Expression: "def3", Comment: "comment3", CreatorID: api.SystemBotID, DatabaseID: databaseID, TableID: tableID},
 			},
+			wantPatches: []*api.IndexPatch{
+				{ID: 125, UpdaterID: api.SystemBotID, Visible: newBool(false), Comment: newString("comment2-change")},
+			},
+		},
+		{
+			oldIndexList: []*api.Index{
+				{ID: 123, Name: "index1", Expression: "def1", Visible: true},
+				{ID: 124, Name: "index1", Expression: "def2", Position: 1, Visible: true},
+			},
+			indexList: []db.Index{
+				{Name: "index1", Expression: "def1", Visible: false},
+				{Name: "index1", Expression: "def2", Position: 1, Visible: false},
+			},
+			wantDeletes: nil,
+			wantCreates: nil,
+			wantPatches: []*api.IndexPatch{
+				{ID: 123, UpdaterID: api.SystemBotID, Visible: newBool(false), Comment: newString("")},
+				{ID: 124, UpdaterID: api.SystemBotID, Visible: newBool(false), Comment: newString("")},
+			},
 		},
 		{
 			oldIndexList: []*api.Index{
@@ -76,8 +100,74 @@ func TestGenerateIndexActions(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		deletes, creates := generateIndexActions(test.oldIndexList, test.indexList, databaseID, tableID)
+		deletes, creates, patches := generateIndexActions(test.oldIndexList, test.indexList, databaseID, tableID)
 		require.Equal(t, test.wantDeletes, deletes)
 		require.Equal(t, test.wantCreates, creates)
+		require.Equal(t, test.wantPatches, patches)
+	}
+}
+
+func TestSetTableListDeleteStaleIndex(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pgDir := t.TempDir()
+	pgInstance, err := postgres.Install(path.Join(pgDir, "resource"), path.Join(pgDir, "data"), pgUser)
+	a.NoError(err)
+	// Use another port than TestMigrationCompatibility in case the instance of the other test isn't stopped.
+	port := pgPort + 1
+	err = postgres.Start(port, pgInstance.BaseDir, pgInstance.DataDir, os.Stderr, os.Stderr)
+	a.NoError(err)
+	defer func() {
+		_ = postgres.Stop(pgInstance.BaseDir, pgInstance.DataDir, os.Stdout, os.Stderr)
+	}()
+
+	storeDB := NewDB(db.ConnectionConfig{
+		Username: pgUser,
+		Host:     common.GetPostgresSocketDir(),
+		Port:     fmt.Sprintf("%d", port),
+	}, pgInstance.BaseDir, "" /* demoDataDir */, false /* readonly */, serverVersion, common.ReleaseModeDev)
+	a.NoError(storeDB.Open(ctx))
+	s := New(storeDB, nil)
+	defer s.Close()
+
+	// The database 101 is created by the initial data.
+	databaseID := 101
+	indexList := []db.Index{
+		{Name: "PRIMARY", Expression: "id", Type: "BTREE", Unique: true, Primary: true, Visible: true},
+		{Name: "idx_name", Expression: "name", Type: "BTREE", Visible: true},
+	}
+	t1 := db.Table{Name: "t1", Type: "BASE TABLE", IndexList: indexList}
+	t2 := db.Table{Name: "t2", Type: "BASE TABLE", IndexList: indexList}
+	a.NoError(s.SetTableList(ctx, &db.Schema{TableList: []db.Table{t1, t2}}, databaseID))
+	gotIndexList, err := s.FindIndex(ctx, &api.IndexFind{DatabaseID: &databaseID})
+	a.NoError(err)
+	a.Len(gotIndexList, 4)
+
+	// All the indexes of t1 are dropped on the database, the ones of t2 are kept.
+	t1.IndexList = nil
+	a.NoError(s.SetTableList(ctx, &db.Schema{TableList: []db.Table{t1, t2}}, databaseID))
+	tableName := "t2"
+	// The raw table is read, because composing the table needs the principal cache, which is nil in the test.
+	table, err := s.getTableRaw(ctx, &api.TableFind{DatabaseID: &databaseID, Name: &tableName})
+	a.NoError(err)
+	a.NotNil(table)
+	gotIndexList, err = s.FindIndex(ctx, &api.IndexFind{DatabaseID: &databaseID})
+	a.NoError(err)
+	a.Len(gotIndexList, 2)
+	for _, index := range gotIndexList {
+		a.Equal(table.ID, index.TableID)
 	}
+
+	a.NoError(s.DeleteIndexListByTable(ctx, databaseID, table.ID))
+	gotIndexList, err = s.FindIndex(ctx, &api.IndexFind{DatabaseID: &databaseID})
+	a.NoError(err)
+	a.Empty(gotIndexList)
+}
+
+func newBool(b bool) *bool {
+	return &b
+}
+
+func newString(s string) *string {
+	return &s
 }
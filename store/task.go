@@ -489,6 +489,9 @@ func (s *Store) findTaskImpl(ctx context.Context, tx *sql.Tx, find *api.TaskFind
 	if v := find.StageID; v != nil {
 		where, args = append(where, fmt.Sprintf("stage_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.StatusList; v != nil {
 		list := []string{}
 		for _, status := range *v {
@@ -686,6 +689,8 @@ func (s *Store) patchTaskStatusImpl(ctx context.Context, tx *sql.Tx, patch *api.
 		case api.TaskPendingApproval:
 		case api.TaskCanceled:
 			taskRunStatusPatch.Status = api.TaskRunCanceled
+		case api.TaskPaused:
+			taskRunStatusPatch.Status = api.TaskRunPaused
 		}
 		if _, err := s.patchTaskRunStatusImpl(ctx, tx, taskRunStatusPatch); err != nil {
 			return nil, err
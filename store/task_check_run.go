@@ -104,6 +104,89 @@ func (s *Store) PatchTaskCheckRunStatus(ctx context.Context, patch *api.TaskChec
 	return taskCheckRun, nil
 }
 
+// BatchPatchTaskCheckRunStatus patches all the RUNNING task check runs of the task in one statement.
+func (s *Store) BatchPatchTaskCheckRunStatus(ctx context.Context, patch *api.TaskCheckRunStatusPatch) ([]*api.TaskCheckRun, error) {
+	if patch.TaskID == nil {
+		return nil, &common.Error{Code: common.Invalid, Err: errors.Errorf("missing task ID to batch patch task check run status")}
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	taskCheckRunRawList, err := s.patchTaskCheckRunStatusImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to batch patch TaskCheckRunStatus with TaskCheckRunStatusPatch[%+v]", patch)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	var taskCheckRunList []*api.TaskCheckRun
+	for _, raw := range taskCheckRunRawList {
+		taskCheckRun, err := s.composeTaskCheckRun(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose TaskCheckRun with taskCheckRunRaw[%+v]", raw)
+		}
+		taskCheckRunList = append(taskCheckRunList, taskCheckRun)
+	}
+	return taskCheckRunList, nil
+}
+
+// CancelTaskCheckRun cancels a RUNNING task check run.
+// It returns a NotFound error if the task check run doesn't exist or is no longer running.
+func (s *Store) CancelTaskCheckRun(ctx context.Context, id int, updaterID int) (*api.TaskCheckRun, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	var taskCheckRunRaw taskCheckRunRaw
+	if err := tx.PTx.QueryRowContext(ctx, `
+		UPDATE task_check_run
+		SET updater_id = $1, status = $2, result = $3
+		WHERE id = $4 AND status = $5
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, status, type, code, comment, result, payload
+	`,
+		updaterID,
+		api.TaskCheckRunCanceled,
+		`{"detail":"Canceled"}`,
+		id,
+		api.TaskCheckRunRunning,
+	).Scan(
+		&taskCheckRunRaw.ID,
+		&taskCheckRunRaw.CreatorID,
+		&taskCheckRunRaw.CreatedTs,
+		&taskCheckRunRaw.UpdaterID,
+		&taskCheckRunRaw.UpdatedTs,
+		&taskCheckRunRaw.TaskID,
+		&taskCheckRunRaw.Status,
+		&taskCheckRunRaw.Type,
+		&taskCheckRunRaw.Code,
+		&taskCheckRunRaw.Comment,
+		&taskCheckRunRaw.Result,
+		&taskCheckRunRaw.Payload,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("running task check run ID not found: %d", id)}
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	taskCheckRun, err := s.composeTaskCheckRun(ctx, &taskCheckRunRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose TaskCheckRun with taskCheckRunRaw[%+v]", taskCheckRunRaw)
+	}
+	return taskCheckRun, nil
+}
+
 //
 // private functions
 //
@@ -271,20 +354,24 @@ func (s *Store) patchTaskCheckRunRawStatus(ctx context.Context, patch *api.TaskC
 	}
 	defer tx.PTx.Rollback()
 
-	taskCheckRun, err := s.patchTaskCheckRunStatusImpl(ctx, tx.PTx, patch)
+	taskCheckRunList, err := s.patchTaskCheckRunStatusImpl(ctx, tx.PTx, patch)
 	if err != nil {
 		return nil, FormatError(err)
 	}
+	if len(taskCheckRunList) == 0 {
+		return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("task check run ID not found: %d", *patch.ID)}
+	}
 
 	if err := tx.PTx.Commit(); err != nil {
 		return nil, FormatError(err)
 	}
 
-	return taskCheckRun, nil
+	return taskCheckRunList[0], nil
 }
 
-// patchTaskCheckRunStatusImpl updates a taskCheckRun status. Returns the new state of the taskCheckRun after update.
-func (*Store) patchTaskCheckRunStatusImpl(ctx context.Context, tx *sql.Tx, patch *api.TaskCheckRunStatusPatch) (*taskCheckRunRaw, error) {
+// patchTaskCheckRunStatusImpl updates the taskCheckRun status by ID, or all the RUNNING ones of the task by TaskID.
+// Returns the new state of the taskCheckRuns after update.
+func (*Store) patchTaskCheckRunStatusImpl(ctx context.Context, tx *sql.Tx, patch *api.TaskCheckRunStatusPatch) ([]*taskCheckRunRaw, error) {
 	// Build UPDATE clause.
 	if patch.Result == "" {
 		patch.Result = "{}"
@@ -299,35 +386,49 @@ func (*Store) patchTaskCheckRunStatusImpl(ctx context.Context, tx *sql.Tx, patch
 	if v := patch.ID; v != nil {
 		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.TaskID; v != nil {
+		where, args = append(where, fmt.Sprintf("task_id = $%d", len(args)+1)), append(args, *v)
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, api.TaskCheckRunRunning)
+	}
 
-	var taskCheckRunRaw taskCheckRunRaw
-	if err := tx.QueryRowContext(ctx, `
+	rows, err := tx.QueryContext(ctx, `
 		UPDATE task_check_run
 		SET `+strings.Join(set, ", ")+`
 		WHERE `+strings.Join(where, " AND ")+`
 		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, status, type, code, comment, result, payload
 	`,
 		args...,
-	).Scan(
-		&taskCheckRunRaw.ID,
-		&taskCheckRunRaw.CreatorID,
-		&taskCheckRunRaw.CreatedTs,
-		&taskCheckRunRaw.UpdaterID,
-		&taskCheckRunRaw.UpdatedTs,
-		&taskCheckRunRaw.TaskID,
-		&taskCheckRunRaw.Status,
-		&taskCheckRunRaw.Type,
-		&taskCheckRunRaw.Code,
-		&taskCheckRunRaw.Comment,
-		&taskCheckRunRaw.Result,
-		&taskCheckRunRaw.Payload,
-	); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("task check run ID not found: %d", *patch.ID)}
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var taskCheckRunRawList []*taskCheckRunRaw
+	for rows.Next() {
+		var taskCheckRunRaw taskCheckRunRaw
+		if err := rows.Scan(
+			&taskCheckRunRaw.ID,
+			&taskCheckRunRaw.CreatorID,
+			&taskCheckRunRaw.CreatedTs,
+			&taskCheckRunRaw.UpdaterID,
+			&taskCheckRunRaw.UpdatedTs,
+			&taskCheckRunRaw.TaskID,
+			&taskCheckRunRaw.Status,
+			&taskCheckRunRaw.Type,
+			&taskCheckRunRaw.Code,
+			&taskCheckRunRaw.Comment,
+			&taskCheckRunRaw.Result,
+			&taskCheckRunRaw.Payload,
+		); err != nil {
+			return nil, FormatError(err)
 		}
+		taskCheckRunRawList = append(taskCheckRunRawList, &taskCheckRunRaw)
+	}
+	if err := rows.Err(); err != nil {
 		return nil, FormatError(err)
 	}
-	return &taskCheckRunRaw, nil
+	return taskCheckRunRawList, nil
 }
 
 func (*Store) findTaskCheckRunImpl(ctx context.Context, tx *sql.Tx, find *api.TaskCheckRunFind) ([]*taskCheckRunRaw, error) {
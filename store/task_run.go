@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -26,13 +27,15 @@ type taskRunRaw struct {
 	TaskID int
 
 	// Domain specific fields
-	Name    string
-	Status  api.TaskRunStatus
-	Type    api.TaskType
-	Code    common.Code
-	Comment string
-	Result  string
-	Payload string
+	Name        string
+	Status      api.TaskRunStatus
+	Type        api.TaskType
+	Code        common.Code
+	Comment     string
+	Result      string
+	Payload     string
+	Progress    string
+	HeartbeatTs int64
 }
 
 // toTaskRun creates an instance of TaskRun based on the taskRunRaw.
@@ -51,16 +54,110 @@ func (raw *taskRunRaw) toTaskRun() *api.TaskRun {
 		TaskID: raw.TaskID,
 
 		// Domain specific fields
-		Name:    raw.Name,
-		Status:  raw.Status,
-		Type:    raw.Type,
-		Code:    raw.Code,
-		Comment: raw.Comment,
-		Result:  raw.Result,
-		Payload: raw.Payload,
+		Name:        raw.Name,
+		Status:      raw.Status,
+		Type:        raw.Type,
+		Code:        raw.Code,
+		Comment:     raw.Comment,
+		Result:      raw.Result,
+		Payload:     raw.Payload,
+		Progress:    raw.Progress,
+		HeartbeatTs: raw.HeartbeatTs,
 	}
 }
 
+// GetTaskRun gets an instance of TaskRun.
+// Returns ECONFLICT if finding more than 1 matching records.
+func (s *Store) GetTaskRun(ctx context.Context, find *api.TaskRunFind) (*api.TaskRun, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	taskRunRaw, err := s.getTaskRunRawTx(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get TaskRun with TaskRunFind[%+v]", find)
+	}
+	if taskRunRaw == nil {
+		return nil, nil
+	}
+	return taskRunRaw.toTaskRun(), nil
+}
+
+// PatchTaskRunProgress merges the reported progress into the progress of the running task run of the task.
+// Returns ENOTFOUND if the task has no running task run, e.g. it has been canceled.
+func (s *Store) PatchTaskRunProgress(ctx context.Context, patch *api.TaskRunProgressPatch) error {
+	progress, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal TaskRunProgressPatch[%+v]", patch)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `
+		UPDATE task_run
+		SET progress = progress || $1::jsonb
+		WHERE task_id = $2 AND status = 'RUNNING'
+	`,
+		string(progress),
+		patch.TaskID,
+	)
+	if err != nil {
+		return FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("running task run not found for task ID %d", patch.TaskID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+// PatchTaskRunHeartbeat updates the heartbeat of the running task run of the task.
+// Returns ENOTFOUND if the task has no running task run, e.g. it has completed.
+func (s *Store) PatchTaskRunHeartbeat(ctx context.Context, patch *api.TaskRunHeartbeatPatch) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `
+		UPDATE task_run
+		SET heartbeat_ts = $1
+		WHERE task_id = $2 AND status = 'RUNNING'
+	`,
+		patch.HeartbeatTs,
+		patch.TaskID,
+	)
+	if err != nil {
+		return FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("running task run not found for task ID %d", patch.TaskID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
 // createTaskRunImpl creates a new taskRun.
 func (*Store) createTaskRunImpl(ctx context.Context, tx *sql.Tx, create *api.TaskRunCreate) (*taskRunRaw, error) {
 	if create.Payload == "" {
@@ -77,7 +174,7 @@ func (*Store) createTaskRunImpl(ctx context.Context, tx *sql.Tx, create *api.Tas
 			payload
 		)
 		VALUES ($1, $2, $3, $4, 'RUNNING', $5, $6)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, name, status, type, code, comment, result, payload
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, name, status, type, code, comment, result, payload, progress, heartbeat_ts
 	`
 	var taskRunRaw taskRunRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -101,6 +198,8 @@ func (*Store) createTaskRunImpl(ctx context.Context, tx *sql.Tx, create *api.Tas
 		&taskRunRaw.Comment,
 		&taskRunRaw.Result,
 		&taskRunRaw.Payload,
+		&taskRunRaw.Progress,
+		&taskRunRaw.HeartbeatTs,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -158,7 +257,7 @@ func (*Store) patchTaskRunStatusImpl(ctx context.Context, tx *sql.Tx, patch *api
 		UPDATE task_run
 		SET `+strings.Join(set, ", ")+`
 		WHERE `+strings.Join(where, " AND ")+`
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, name, status, type, code, comment, result, payload
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, name, status, type, code, comment, result, payload, progress, heartbeat_ts
 	`,
 		args...,
 	).Scan(
@@ -175,6 +274,8 @@ func (*Store) patchTaskRunStatusImpl(ctx context.Context, tx *sql.Tx, patch *api
 		&taskRunRaw.Comment,
 		&taskRunRaw.Result,
 		&taskRunRaw.Payload,
+		&taskRunRaw.Progress,
+		&taskRunRaw.HeartbeatTs,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("project ID not found: %d", patch.ID)}
@@ -217,7 +318,9 @@ func (*Store) findTaskRunImpl(ctx context.Context, tx *sql.Tx, find *api.TaskRun
 			code,
 			comment,
 			result,
-			payload
+			payload,
+			progress,
+			heartbeat_ts
 		FROM task_run
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -245,6 +348,8 @@ func (*Store) findTaskRunImpl(ctx context.Context, tx *sql.Tx, find *api.TaskRun
 			&taskRunRaw.Comment,
 			&taskRunRaw.Result,
 			&taskRunRaw.Payload,
+			&taskRunRaw.Progress,
+			&taskRunRaw.HeartbeatTs,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -0,0 +1,315 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/pkg/errors"
+)
+
+// taskRunArtifactRaw is the store model for a TaskRunArtifact.
+// Fields have exactly the same meanings as TaskRunArtifact.
+type taskRunArtifactRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	TaskRunID int
+	TaskID    int
+
+	// Domain specific fields
+	Type           api.TaskRunArtifactType
+	Name           string
+	Size           int64
+	StorageBackend api.BackupStorageBackend
+	Path           string
+	Reference      bool
+	ExpireTs       int64
+}
+
+// toTaskRunArtifact creates an instance of TaskRunArtifact based on the taskRunArtifactRaw.
+// This is intended to be called when we need to compose a TaskRunArtifact relationship.
+func (raw *taskRunArtifactRaw) toTaskRunArtifact() *api.TaskRunArtifact {
+	return &api.TaskRunArtifact{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Related fields
+		TaskRunID: raw.TaskRunID,
+		TaskID:    raw.TaskID,
+
+		// Domain specific fields
+		Type:           raw.Type,
+		Name:           raw.Name,
+		Size:           raw.Size,
+		StorageBackend: raw.StorageBackend,
+		Path:           raw.Path,
+		Reference:      raw.Reference,
+		ExpireTs:       raw.ExpireTs,
+	}
+}
+
+// CreateTaskRunArtifact creates an instance of TaskRunArtifact.
+func (s *Store) CreateTaskRunArtifact(ctx context.Context, create *api.TaskRunArtifactCreate) (*api.TaskRunArtifact, error) {
+	taskRunArtifactRaw, err := s.createTaskRunArtifactRaw(ctx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create TaskRunArtifact with TaskRunArtifactCreate[%+v]", create)
+	}
+	taskRunArtifact, err := s.composeTaskRunArtifact(ctx, taskRunArtifactRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose TaskRunArtifact with taskRunArtifactRaw[%+v]", taskRunArtifactRaw)
+	}
+	return taskRunArtifact, nil
+}
+
+// GetTaskRunArtifactByID gets an instance of TaskRunArtifact.
+func (s *Store) GetTaskRunArtifactByID(ctx context.Context, id int) (*api.TaskRunArtifact, error) {
+	find := &api.TaskRunArtifactFind{ID: &id}
+	taskRunArtifactRawList, err := s.findTaskRunArtifactRaw(ctx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get TaskRunArtifact with ID %d", id)
+	}
+	if len(taskRunArtifactRawList) == 0 {
+		return nil, nil
+	} else if len(taskRunArtifactRawList) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d task run artifacts with filter %+v, expect 1", len(taskRunArtifactRawList), find)}
+	}
+	taskRunArtifact, err := s.composeTaskRunArtifact(ctx, taskRunArtifactRawList[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose TaskRunArtifact with taskRunArtifactRaw[%+v]", taskRunArtifactRawList[0])
+	}
+	return taskRunArtifact, nil
+}
+
+// FindTaskRunArtifact finds a list of TaskRunArtifact instances.
+func (s *Store) FindTaskRunArtifact(ctx context.Context, find *api.TaskRunArtifactFind) ([]*api.TaskRunArtifact, error) {
+	taskRunArtifactRawList, err := s.findTaskRunArtifactRaw(ctx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find TaskRunArtifact list with TaskRunArtifactFind[%+v]", find)
+	}
+	var taskRunArtifactList []*api.TaskRunArtifact
+	for _, raw := range taskRunArtifactRawList {
+		taskRunArtifact, err := s.composeTaskRunArtifact(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose TaskRunArtifact with taskRunArtifactRaw[%+v]", raw)
+		}
+		taskRunArtifactList = append(taskRunArtifactList, taskRunArtifact)
+	}
+	return taskRunArtifactList, nil
+}
+
+// DeleteTaskRunArtifact deletes an existing task run artifact by ID.
+// Returns ENOTFOUND if task run artifact does not exist.
+func (s *Store) DeleteTaskRunArtifact(ctx context.Context, delete *api.TaskRunArtifactDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if err := deleteTaskRunArtifactImpl(ctx, tx.PTx, delete); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeTaskRunArtifact(ctx context.Context, raw *taskRunArtifactRaw) (*api.TaskRunArtifact, error) {
+	taskRunArtifact := raw.toTaskRunArtifact()
+
+	creator, err := s.GetPrincipalByID(ctx, taskRunArtifact.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	taskRunArtifact.Creator = creator
+
+	return taskRunArtifact, nil
+}
+
+// createTaskRunArtifactRaw creates a new task run artifact.
+func (s *Store) createTaskRunArtifactRaw(ctx context.Context, create *api.TaskRunArtifactCreate) (*taskRunArtifactRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	taskRunArtifact, err := createTaskRunArtifactImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return taskRunArtifact, nil
+}
+
+// findTaskRunArtifactRaw retrieves a list of task run artifacts based on find.
+func (s *Store) findTaskRunArtifactRaw(ctx context.Context, find *api.TaskRunArtifactFind) ([]*taskRunArtifactRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findTaskRunArtifactImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+const taskRunArtifactColumns = `
+			id,
+			creator_id,
+			created_ts,
+			task_run_id,
+			task_id,
+			type,
+			name,
+			size,
+			storage_backend,
+			path,
+			reference,
+			expire_ts`
+
+func scanTaskRunArtifactRaw(row rowScanner) (*taskRunArtifactRaw, error) {
+	var taskRunArtifactRaw taskRunArtifactRaw
+	if err := row.Scan(
+		&taskRunArtifactRaw.ID,
+		&taskRunArtifactRaw.CreatorID,
+		&taskRunArtifactRaw.CreatedTs,
+		&taskRunArtifactRaw.TaskRunID,
+		&taskRunArtifactRaw.TaskID,
+		&taskRunArtifactRaw.Type,
+		&taskRunArtifactRaw.Name,
+		&taskRunArtifactRaw.Size,
+		&taskRunArtifactRaw.StorageBackend,
+		&taskRunArtifactRaw.Path,
+		&taskRunArtifactRaw.Reference,
+		&taskRunArtifactRaw.ExpireTs,
+	); err != nil {
+		return nil, err
+	}
+	return &taskRunArtifactRaw, nil
+}
+
+// createTaskRunArtifactImpl creates a new task run artifact.
+func createTaskRunArtifactImpl(ctx context.Context, tx *sql.Tx, create *api.TaskRunArtifactCreate) (*taskRunArtifactRaw, error) {
+	// Insert row into database.
+	query := `
+		INSERT INTO task_run_artifact (
+			creator_id,
+			task_run_id,
+			task_id,
+			type,
+			name,
+			size,
+			storage_backend,
+			path,
+			reference,
+			expire_ts
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + taskRunArtifactColumns
+	taskRunArtifactRaw, err := scanTaskRunArtifactRaw(tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.TaskRunID,
+		create.TaskID,
+		create.Type,
+		create.Name,
+		create.Size,
+		create.StorageBackend,
+		create.Path,
+		create.Reference,
+		create.ExpireTs,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return taskRunArtifactRaw, nil
+}
+
+func findTaskRunArtifactImpl(ctx context.Context, tx *sql.Tx, find *api.TaskRunArtifactFind) ([]*taskRunArtifactRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TaskRunID; v != nil {
+		where, args = append(where, fmt.Sprintf("task_run_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TaskID; v != nil {
+		where, args = append(where, fmt.Sprintf("task_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ExpireTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("expire_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+taskRunArtifactColumns+`
+		FROM task_run_artifact
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into taskRunArtifactRawList.
+	var taskRunArtifactRawList []*taskRunArtifactRaw
+	for rows.Next() {
+		taskRunArtifactRaw, err := scanTaskRunArtifactRaw(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		taskRunArtifactRawList = append(taskRunArtifactRawList, taskRunArtifactRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return taskRunArtifactRawList, nil
+}
+
+// deleteTaskRunArtifactImpl permanently deletes a task run artifact by ID.
+func deleteTaskRunArtifactImpl(ctx context.Context, tx *sql.Tx, delete *api.TaskRunArtifactDelete) error {
+	// Remove row from database.
+	result, err := tx.ExecContext(ctx, `DELETE FROM task_run_artifact WHERE id = $1`, delete.ID)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("task run artifact ID not found: %d", delete.ID)}
+	}
+
+	return nil
+}
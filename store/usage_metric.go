@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/pkg/errors"
+)
+
+// CreateUsageMetricList creates the usage metrics collected in a round.
+func (s *Store) CreateUsageMetricList(ctx context.Context, createList []*api.UsageMetricCreate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	for _, create := range createList {
+		if err := createUsageMetricImpl(ctx, tx.PTx, create); err != nil {
+			return errors.Wrapf(err, "failed to create UsageMetric with UsageMetricCreate[%+v]", create)
+		}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// FindUsageMetric finds a list of UsageMetric instances.
+func (s *Store) FindUsageMetric(ctx context.Context, find *api.UsageMetricFind) ([]*api.UsageMetric, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findUsageMetricImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find UsageMetric list with UsageMetricFind[%+v]", find)
+	}
+
+	return list, nil
+}
+
+// GetLatestUsageMetricCreatedTs gets the time of the latest collection round.
+// Returns 0 if the usage metrics have never been collected.
+func (s *Store) GetLatestUsageMetricCreatedTs(ctx context.Context) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	var createdTs int64
+	if err := tx.PTx.QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(created_ts), 0)
+		FROM usage_metric`,
+	).Scan(&createdTs); err != nil {
+		return 0, FormatError(err)
+	}
+
+	return createdTs, nil
+}
+
+// DeleteUsageMetricBefore deletes the usage metrics collected before the time.
+func (s *Store) DeleteUsageMetricBefore(ctx context.Context, createdTs int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM usage_metric WHERE created_ts < $1`, createdTs); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// createUsageMetricImpl creates a new usage metric.
+func createUsageMetricImpl(ctx context.Context, tx *sql.Tx, create *api.UsageMetricCreate) error {
+	labels := "{}"
+	if len(create.Labels) > 0 {
+		bytes, err := json.Marshal(create.Labels)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal labels")
+		}
+		labels = string(bytes)
+	}
+
+	// Insert row into database.
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO usage_metric (
+			created_ts,
+			name,
+			value,
+			labels
+		)
+		VALUES ($1, $2, $3, $4)`,
+		create.CreatedTs,
+		create.Name,
+		create.Value,
+		labels,
+	); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+func findUsageMetricImpl(ctx context.Context, tx *sql.Tx, find *api.UsageMetricFind) ([]*api.UsageMetric, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.CreatedTs; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			name,
+			value,
+			labels
+		FROM usage_metric
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into usageMetricList.
+	var usageMetricList []*api.UsageMetric
+	for rows.Next() {
+		var usageMetric api.UsageMetric
+		var labels string
+		if err := rows.Scan(
+			&usageMetric.ID,
+			&usageMetric.CreatedTs,
+			&usageMetric.Name,
+			&usageMetric.Value,
+			&labels,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if err := json.Unmarshal([]byte(labels), &usageMetric.Labels); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal labels of usage metric %d", usageMetric.ID)
+		}
+		usageMetricList = append(usageMetricList, &usageMetric)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return usageMetricList, nil
+}
@@ -23,6 +23,9 @@ type vcsRaw struct {
 	UpdaterID int
 	UpdatedTs int64
 
+	// Related fields
+	WorkspaceID int
+
 	// Domain specific fields
 	Name          string
 	Type          vcs.Type
@@ -43,6 +46,8 @@ func (raw *vcsRaw) toVCS() *api.VCS {
 		UpdaterID: raw.UpdaterID,
 		UpdatedTs: raw.UpdatedTs,
 
+		WorkspaceID: raw.WorkspaceID,
+
 		Name:          raw.Name,
 		Type:          raw.Type,
 		InstanceURL:   raw.InstanceURL,
@@ -259,10 +264,11 @@ func createVCSImpl(ctx context.Context, tx *sql.Tx, create *api.VCSCreate) (*vcs
 			instance_url,
 			api_url,
 			application_id,
-			secret
+			secret,
+			workspace_id
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, type, instance_url, api_url, application_id, secret
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, type, instance_url, api_url, application_id, secret
 	`
 	var vcs vcsRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -274,12 +280,14 @@ func createVCSImpl(ctx context.Context, tx *sql.Tx, create *api.VCSCreate) (*vcs
 		create.APIURL,
 		create.ApplicationID,
 		create.Secret,
+		create.WorkspaceID,
 	).Scan(
 		&vcs.ID,
 		&vcs.CreatorID,
 		&vcs.CreatedTs,
 		&vcs.UpdaterID,
 		&vcs.UpdatedTs,
+		&vcs.WorkspaceID,
 		&vcs.Name,
 		&vcs.Type,
 		&vcs.InstanceURL,
@@ -299,7 +307,10 @@ func findVCSImpl(ctx context.Context, tx *sql.Tx, find *api.VCSFind) ([]*vcsRaw,
 	// Build WHERE clause.
 	where, args := []string{"1 = 1"}, []interface{}{}
 	if v := find.ID; v != nil {
-		where, args = append(where, "id = $1"), append(args, *v)
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.WorkspaceID; v != nil {
+		where, args = append(where, fmt.Sprintf("workspace_id = $%d", len(args)+1)), append(args, *v)
 	}
 
 	rows, err := tx.QueryContext(ctx, `
@@ -309,6 +320,7 @@ func findVCSImpl(ctx context.Context, tx *sql.Tx, find *api.VCSFind) ([]*vcsRaw,
 			created_ts,
 			updater_id,
 			updated_ts,
+			workspace_id,
 			name,
 			type,
 			instance_url,
@@ -334,6 +346,7 @@ func findVCSImpl(ctx context.Context, tx *sql.Tx, find *api.VCSFind) ([]*vcsRaw,
 			&vcs.CreatedTs,
 			&vcs.UpdaterID,
 			&vcs.UpdatedTs,
+			&vcs.WorkspaceID,
 			&vcs.Name,
 			&vcs.Type,
 			&vcs.InstanceURL,
@@ -374,7 +387,7 @@ func patchVCSImpl(ctx context.Context, tx *sql.Tx, patch *api.VCSPatch) (*vcsRaw
 		UPDATE vcs
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, type, instance_url, api_url, application_id, secret
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, workspace_id, name, type, instance_url, api_url, application_id, secret
 	`, len(args)),
 		args...,
 	).Scan(
@@ -383,6 +396,7 @@ func patchVCSImpl(ctx context.Context, tx *sql.Tx, patch *api.VCSPatch) (*vcsRaw
 		&vcs.CreatedTs,
 		&vcs.UpdaterID,
 		&vcs.UpdatedTs,
+		&vcs.WorkspaceID,
 		&vcs.Name,
 		&vcs.Type,
 		&vcs.InstanceURL,
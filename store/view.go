@@ -27,6 +27,7 @@ type viewRaw struct {
 	DatabaseID int
 
 	// Domain specific fields
+	Schema     string
 	Name       string
 	Definition string
 	Comment    string
@@ -48,6 +49,7 @@ func (raw *viewRaw) toView() *api.View {
 		DatabaseID: raw.DatabaseID,
 
 		// Domain specific fields
+		Schema:     raw.Schema,
 		Name:       raw.Name,
 		Definition: raw.Definition,
 		Comment:    raw.Comment,
@@ -114,6 +116,7 @@ func generateViewActions(oldViewRawList []*viewRaw, viewList []db.View, database
 			CreatedTs:  view.CreatedTs,
 			UpdatedTs:  view.UpdatedTs,
 			DatabaseID: databaseID,
+			Schema:     view.Schema,
 			Name:       view.Name,
 			Definition: view.Definition,
 			Comment:    view.Comment,
@@ -199,12 +202,13 @@ func (*Store) createViewImpl(ctx context.Context, tx *sql.Tx, create *api.ViewCr
 			updater_id,
 			updated_ts,
 			database_id,
+			schema,
 			name,
 			definition,
 			comment
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)` +
-		"RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, definition, comment" + `
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)` +
+		"RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, schema, name, definition, comment" + `
 	`
 	var viewRaw viewRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -213,6 +217,7 @@ func (*Store) createViewImpl(ctx context.Context, tx *sql.Tx, create *api.ViewCr
 		create.CreatorID,
 		create.UpdatedTs,
 		create.DatabaseID,
+		create.Schema,
 		create.Name,
 		create.Definition,
 		create.Comment,
@@ -223,6 +228,7 @@ func (*Store) createViewImpl(ctx context.Context, tx *sql.Tx, create *api.ViewCr
 		&viewRaw.UpdaterID,
 		&viewRaw.UpdatedTs,
 		&viewRaw.DatabaseID,
+		&viewRaw.Schema,
 		&viewRaw.Name,
 		&viewRaw.Definition,
 		&viewRaw.Comment,
@@ -244,6 +250,9 @@ func (*Store) findViewImpl(ctx context.Context, tx *sql.Tx, find *api.ViewFind)
 	if v := find.DatabaseID; v != nil {
 		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.Schema; v != nil {
+		where, args = append(where, fmt.Sprintf("schema = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.Name; v != nil {
 		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
@@ -256,6 +265,7 @@ func (*Store) findViewImpl(ctx context.Context, tx *sql.Tx, find *api.ViewFind)
 			updater_id,
 			updated_ts,
 			database_id,
+			schema,
 			name,
 			definition,
 			comment
@@ -280,6 +290,7 @@ func (*Store) findViewImpl(ctx context.Context, tx *sql.Tx, find *api.ViewFind)
 			&viewRaw.UpdaterID,
 			&viewRaw.UpdatedTs,
 			&viewRaw.DatabaseID,
+			&viewRaw.Schema,
 			&viewRaw.Name,
 			&viewRaw.Definition,
 			&viewRaw.Comment,
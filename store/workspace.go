@@ -0,0 +1,312 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/pkg/errors"
+)
+
+// workspaceRaw is the store model for a Workspace.
+// Fields have exactly the same meanings as Workspace.
+type workspaceRaw struct {
+	ID int
+
+	// Standard fields
+	RowStatus api.RowStatus
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Domain specific fields
+	Name string
+}
+
+// toWorkspace creates an instance of Workspace based on the workspaceRaw.
+// This is intended to be called when we need to compose a Workspace relationship.
+func (raw *workspaceRaw) toWorkspace() *api.Workspace {
+	return &api.Workspace{
+		ID: raw.ID,
+
+		// Standard fields
+		RowStatus: raw.RowStatus,
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Domain specific fields
+		Name: raw.Name,
+	}
+}
+
+// CreateWorkspace creates an instance of Workspace.
+func (s *Store) CreateWorkspace(ctx context.Context, create *api.WorkspaceCreate) (*api.Workspace, error) {
+	workspaceRaw, err := s.createWorkspaceRaw(ctx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create Workspace with WorkspaceCreate[%+v]", create)
+	}
+	workspace, err := s.composeWorkspace(ctx, workspaceRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose Workspace with workspaceRaw[%+v]", workspaceRaw)
+	}
+	return workspace, nil
+}
+
+// FindWorkspace finds a list of Workspace instances.
+func (s *Store) FindWorkspace(ctx context.Context, find *api.WorkspaceFind) ([]*api.Workspace, error) {
+	workspaceRawList, err := s.findWorkspaceRaw(ctx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find Workspace list with WorkspaceFind[%+v]", find)
+	}
+	var workspaceList []*api.Workspace
+	for _, raw := range workspaceRawList {
+		workspace, err := s.composeWorkspace(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose Workspace with workspaceRaw[%+v]", raw)
+		}
+		workspaceList = append(workspaceList, workspace)
+	}
+	return workspaceList, nil
+}
+
+// GetWorkspaceByID gets an instance of Workspace.
+func (s *Store) GetWorkspaceByID(ctx context.Context, id int) (*api.Workspace, error) {
+	workspaceRawList, err := s.findWorkspaceRaw(ctx, &api.WorkspaceFind{ID: &id})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Workspace with ID %d", id)
+	}
+	if len(workspaceRawList) == 0 {
+		return nil, nil
+	} else if len(workspaceRawList) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d workspaces with ID %d, expect 1", len(workspaceRawList), id)}
+	}
+	workspace, err := s.composeWorkspace(ctx, workspaceRawList[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose Workspace with workspaceRaw[%+v]", workspaceRawList[0])
+	}
+	return workspace, nil
+}
+
+// PatchWorkspace patches an instance of Workspace.
+func (s *Store) PatchWorkspace(ctx context.Context, patch *api.WorkspacePatch) (*api.Workspace, error) {
+	workspaceRaw, err := s.patchWorkspaceRaw(ctx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch Workspace with WorkspacePatch[%+v]", patch)
+	}
+	workspace, err := s.composeWorkspace(ctx, workspaceRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose Workspace with workspaceRaw[%+v]", workspaceRaw)
+	}
+	return workspace, nil
+}
+
+//
+// private functions
+//
+
+// createWorkspaceRaw creates a new workspace.
+func (s *Store) createWorkspaceRaw(ctx context.Context, create *api.WorkspaceCreate) (*workspaceRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	workspace, err := createWorkspaceImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return workspace, nil
+}
+
+// findWorkspaceRaw retrieves a list of workspaceRaw instances.
+func (s *Store) findWorkspaceRaw(ctx context.Context, find *api.WorkspaceFind) ([]*workspaceRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	return findWorkspaceImpl(ctx, tx.PTx, find)
+}
+
+// patchWorkspaceRaw updates an existing instance of workspaceRaw by ID.
+// Returns ENOTFOUND if workspace does not exist.
+func (s *Store) patchWorkspaceRaw(ctx context.Context, patch *api.WorkspacePatch) (*workspaceRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	workspace, err := patchWorkspaceImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return workspace, nil
+}
+
+// composeWorkspace composes an instance of Workspace by workspaceRaw.
+func (s *Store) composeWorkspace(ctx context.Context, raw *workspaceRaw) (*api.Workspace, error) {
+	workspace := raw.toWorkspace()
+
+	creator, err := s.GetPrincipalByID(ctx, workspace.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	workspace.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, workspace.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	workspace.Updater = updater
+
+	return workspace, nil
+}
+
+// createWorkspaceImpl creates a new workspace.
+func createWorkspaceImpl(ctx context.Context, tx *sql.Tx, create *api.WorkspaceCreate) (*workspaceRaw, error) {
+	// Insert row into database.
+	query := `
+		INSERT INTO workspace (
+			creator_id,
+			updater_id,
+			name
+		)
+		VALUES ($1, $2, $3)
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name
+	`
+	var workspaceRaw workspaceRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.Name,
+	).Scan(
+		&workspaceRaw.ID,
+		&workspaceRaw.RowStatus,
+		&workspaceRaw.CreatorID,
+		&workspaceRaw.CreatedTs,
+		&workspaceRaw.UpdaterID,
+		&workspaceRaw.UpdatedTs,
+		&workspaceRaw.Name,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &workspaceRaw, nil
+}
+
+func findWorkspaceImpl(ctx context.Context, tx *sql.Tx, find *api.WorkspaceFind) ([]*workspaceRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			row_status,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			name
+		FROM workspace
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into workspaceRawList.
+	var workspaceRawList []*workspaceRaw
+	for rows.Next() {
+		var workspaceRaw workspaceRaw
+		if err := rows.Scan(
+			&workspaceRaw.ID,
+			&workspaceRaw.RowStatus,
+			&workspaceRaw.CreatorID,
+			&workspaceRaw.CreatedTs,
+			&workspaceRaw.UpdaterID,
+			&workspaceRaw.UpdatedTs,
+			&workspaceRaw.Name,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		workspaceRawList = append(workspaceRawList, &workspaceRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return workspaceRawList, nil
+}
+
+// patchWorkspaceImpl updates a workspace by ID. Returns the new state of the workspace after update.
+func patchWorkspaceImpl(ctx context.Context, tx *sql.Tx, patch *api.WorkspacePatch) (*workspaceRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.RowStatus; v != nil {
+		set, args = append(set, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.RowStatus(*v))
+	}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	var workspaceRaw workspaceRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE workspace
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name
+	`, len(args)),
+		args...,
+	).Scan(
+		&workspaceRaw.ID,
+		&workspaceRaw.RowStatus,
+		&workspaceRaw.CreatorID,
+		&workspaceRaw.CreatedTs,
+		&workspaceRaw.UpdaterID,
+		&workspaceRaw.UpdatedTs,
+		&workspaceRaw.Name,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("workspace ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &workspaceRaw, nil
+}
@@ -143,7 +143,7 @@ func TestSQLReviewForPostgreSQL(t *testing.T) {
 						Namespace: api.AdvisorNamespace,
 						Code:      advisor.NoRequiredColumn.Int(),
 						Title:     "column.required",
-						Content:   `Table "userTable" requires columns: created_ts, creator_id, updated_ts, updater_id`,
+						Content:   `Table "userTable" requires columns: created_ts, creator_id, updated_ts, updater_id. Add the missing columns to the CREATE TABLE statement`,
 					},
 					{
 						Status:    api.TaskCheckStatusWarn,
@@ -493,7 +493,7 @@ func TestSQLReviewForMySQL(t *testing.T) {
 						Namespace: api.AdvisorNamespace,
 						Code:      advisor.NoRequiredColumn.Int(),
 						Title:     "column.required",
-						Content:   "Table `userTable` requires columns: created_ts, creator_id, updated_ts, updater_id",
+						Content:   "Table `userTable` requires columns: created_ts, creator_id, updated_ts, updater_id. Add the missing columns to the CREATE TABLE statement",
 					},
 					{
 						Status:    api.TaskCheckStatusWarn,